@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// apiError 2xx가 아닌 응답 (상태 코드별로 집계)
+type apiError struct {
+	Status  int
+	Message string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%d %s", e.Status, e.Message)
+}
+
+// client 오케스트레이터 API 호출 (token이 있으면 Bearer로 보냄)
+type client struct {
+	baseURL string
+	token   string
+	http    *http.Client
+}
+
+func newClient(baseURL, token string, timeout time.Duration) *client {
+	return &client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		http: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				MaxIdleConnsPerHost: 256,
+				IdleConnTimeout:     90 * time.Second,
+			},
+		},
+	}
+}
+
+// withToken 같은 연결 풀을 쓰면서 다른 키로 호출하는 클라이언트
+func (c *client) withToken(token string) *client {
+	copied := *c
+	copied.token = token
+	return &copied
+}
+
+// do body를 JSON으로 보내고 2xx 응답을 out에 디코딩 (out이 nil이면 본문은 버림)
+func (c *client) do(ctx context.Context, method, path string, body, out interface{}) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return resp.StatusCode, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var failure struct {
+			Error string `json:"error"`
+		}
+		json.Unmarshal(data, &failure)
+		if failure.Error == "" {
+			failure.Error = http.StatusText(resp.StatusCode)
+		}
+		return resp.StatusCode, &apiError{Status: resp.StatusCode, Message: failure.Error}
+	}
+	if out != nil {
+		if err := json.Unmarshal(data, out); err != nil {
+			return resp.StatusCode, fmt.Errorf("응답 해석 실패: %v", err)
+		}
+	}
+	return resp.StatusCode, nil
+}
+
+// createSession 세션 생성 (async면 202 응답 뒤 진행 기록이 끝날 때까지 pollInterval마다 확인)
+func (c *client) createSession(ctx context.Context, req map[string]interface{}, async bool, pollInterval time.Duration) (string, error) {
+	path := "/sessions"
+	if async {
+		path += "?async=true"
+	}
+	var created struct {
+		SessionID string `json:"session_id"`
+	}
+	if _, err := c.do(ctx, http.MethodPost, path, req, &created); err != nil {
+		return "", err
+	}
+	if !async {
+		return created.SessionID, nil
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return created.SessionID, fmt.Errorf("비동기 생성 대기 시간 초과: %v", ctx.Err())
+		case <-ticker.C:
+		}
+		var progress struct {
+			Step   string `json:"step"`
+			Done   bool   `json:"done"`
+			Events []struct {
+				Error string `json:"error"`
+			} `json:"events"`
+		}
+		if _, err := c.do(ctx, http.MethodGet, "/sessions/"+created.SessionID+"/progress", nil, &progress); err != nil {
+			return created.SessionID, err
+		}
+		if !progress.Done {
+			continue
+		}
+		if progress.Step != "ready" {
+			message := "생성 실패"
+			if n := len(progress.Events); n > 0 && progress.Events[n-1].Error != "" {
+				message = progress.Events[n-1].Error
+			}
+			return "", &apiError{Status: http.StatusInternalServerError, Message: message}
+		}
+		return created.SessionID, nil
+	}
+}
+
+func (c *client) deleteSession(ctx context.Context, sessionID string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/sessions/"+sessionID, nil, nil)
+	return err
+}
+
+// extendSession 팀 리드 경로로 세션 연장 (c는 리드 사용자의 키를 써야 함)
+func (c *client) extendSession(ctx context.Context, teamID, sessionID string, minutes int) error {
+	_, err := c.do(ctx, http.MethodPost, "/teams/"+teamID+"/sessions/"+sessionID+"/extend", map[string]int{"minutes": minutes}, nil)
+	return err
+}
@@ -0,0 +1,357 @@
+// loadgen 오케스트레이터 API에 세션 생성/삭제/연장 부하를 걸고 처리량, 지연 시간 백분위, 오류율을 보고하는 도구
+//
+// 가짜 백엔드(-tags fakegpu 빌드, SANDMAN_FAKE_MIG)로 띄운 오케스트레이터나 스테이징 호스트를 대상으로 쓴다.
+// 사용자마다 세션은 하나이므로 -users명의 가상 사용자를 돌려 가며 쓴다. create는 세션이 없는 사용자를,
+// delete와 extend는 세션이 있는 사용자를 고르고, 고를 사용자가 없으면 건너뛴 것으로 센다.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// 작업 종류 (-mix의 키)
+const (
+	opCreate = "create"
+	opDelete = "delete"
+	opExtend = "extend"
+)
+
+// skipBackoff 작업을 건너뛴 워커가 다시 시도하기 전 쉬는 시간
+const skipBackoff = 10 * time.Millisecond
+
+var (
+	target        = flag.String("url", "http://localhost:8080", "오케스트레이터 API 주소")
+	token         = flag.String("token", os.Getenv("SANDMAN_TOKEN"), "API 키 (기본값: SANDMAN_TOKEN, extend를 쓰면 관리자 키 필요)")
+	duration      = flag.Duration("duration", time.Minute, "부하를 거는 시간")
+	concurrency   = flag.Int("concurrency", 8, "동시에 요청을 보내는 워커 수")
+	rate          = flag.Float64("rate", 0, "초당 작업 수 상한 (0이면 워커가 쉬지 않고 보냄)")
+	mixFlag       = flag.String("mix", "create=5,delete=4,extend=1", "작업 비율 (create, delete, extend의 가중치)")
+	users         = flag.Int("users", 32, "가상 사용자 수 (동시에 존재할 수 있는 세션 수의 상한)")
+	userPrefix    = flag.String("user-prefix", "", "가상 사용자 ID 접두사 (기본값: loadgen-<실행 시각>)")
+	profile       = flag.String("profile", "", "요청할 MIG 프로파일 (비워 두면 서버 기본값)")
+	ttlMinutes    = flag.Int("ttl", 30, "생성하는 세션의 TTL (분, 정리에 실패해도 이 시간 뒤 만료)")
+	extendMinutes = flag.Int("extend-minutes", 5, "extend 한 번에 늘리는 시간 (분)")
+	async         = flag.Bool("async", false, "POST /sessions?async=true로 생성하고 진행 기록이 끝날 때까지 기다림 (지연 시간은 ready까지)")
+	pollInterval  = flag.Duration("poll-interval", 500*time.Millisecond, "-async 진행 기록 확인 간격")
+	timeout       = flag.Duration("timeout", 5*time.Minute, "요청 하나(비동기 생성은 ready까지)의 제한 시간")
+	cleanup       = flag.Bool("cleanup", true, "끝난 뒤 남은 세션과 연장용 팀/키 삭제")
+	jsonOutput    = flag.Bool("json", false, "결과를 JSON으로 출력")
+)
+
+func main() {
+	flag.Parse()
+	log.SetFlags(log.LstdFlags)
+
+	mix, err := parseMix(*mixFlag)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if *concurrency < 1 || *users < 1 {
+		log.Fatalf("❌ -concurrency와 -users는 1 이상이어야 합니다")
+	}
+	prefix := *userPrefix
+	if prefix == "" {
+		prefix = "loadgen-" + strconv.FormatInt(time.Now().Unix(), 36)
+	}
+
+	api := newClient(*target, *token, *timeout)
+	pool := newUserPool(prefix, *users)
+	rec := newRecorder()
+
+	// extend는 팀 리드 경로만 있으므로 가상 사용자를 팀에 넣고 리드 키를 발급
+	var ext *extendSetup
+	if mix.weight(opExtend) > 0 {
+		if ext, err = setupExtend(api, prefix, pool.ids()); err != nil {
+			log.Fatalf("❌ extend 준비 실패: %v", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, *duration)
+	defer cancel()
+
+	var ticks <-chan time.Time
+	if *rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / *rate))
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	log.Printf("🚦 부하 시작: %s, %s 동안 워커 %d개, 사용자 %d명 (%s)", *target, *duration, *concurrency, *users, *mixFlag)
+	var wg sync.WaitGroup
+	for i := 0; i < *concurrency; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(seed))
+			for {
+				if ticks != nil {
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticks:
+					}
+				} else if ctx.Err() != nil {
+					return
+				}
+				if !runOp(mix.pick(rnd), api, pool, ext, rec) {
+					// 고를 사용자가 없으면 다른 워커의 요청이 끝나길 잠시 기다림
+					time.Sleep(skipBackoff)
+				}
+			}
+		}(time.Now().UnixNano() + int64(i))
+	}
+	wg.Wait()
+
+	report := rec.report(*target, *concurrency, *async)
+	if *cleanup {
+		teardown(api, pool, ext)
+	}
+
+	if *jsonOutput {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	} else {
+		err = report.print(os.Stdout)
+	}
+	if err != nil {
+		log.Fatalf("❌ 결과 출력 실패: %v", err)
+	}
+}
+
+// runOp 작업 하나 실행 (요청마다 -timeout 제한, 부하 시간이 끝나도 보낸 요청은 마무리, 건너뛰었으면 false)
+func runOp(op string, api *client, pool *userPool, ext *extendSetup, rec *recorder) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	switch op {
+	case opCreate:
+		user := pool.take(false)
+		if user == nil {
+			rec.skip(op)
+			return false
+		}
+		req := map[string]interface{}{"user_id": user.id, "ttl_minutes": *ttlMinutes}
+		if *profile != "" {
+			req["mig_profile"] = *profile
+		}
+		start := time.Now()
+		sessionID, err := api.createSession(ctx, req, *async, *pollInterval)
+		rec.record(op, time.Since(start), err)
+		if err != nil {
+			sessionID = ""
+		}
+		pool.release(user, sessionID)
+	case opDelete:
+		user := pool.take(true)
+		if user == nil {
+			rec.skip(op)
+			return false
+		}
+		start := time.Now()
+		err := api.deleteSession(ctx, user.sessionID)
+		rec.record(op, time.Since(start), err)
+		if err == nil {
+			pool.release(user, "")
+		} else {
+			pool.release(user, user.sessionID)
+		}
+	case opExtend:
+		user := pool.take(true)
+		if user == nil {
+			rec.skip(op)
+			return false
+		}
+		start := time.Now()
+		err := ext.lead.extendSession(ctx, ext.teamID, user.sessionID, *extendMinutes)
+		rec.record(op, time.Since(start), err)
+		pool.release(user, user.sessionID)
+	}
+	return true
+}
+
+// mix 작업별 가중치
+type mix struct {
+	ops     []string
+	weights []int
+	total   int
+}
+
+func parseMix(value string) (*mix, error) {
+	m := &mix{}
+	for _, entry := range strings.Split(value, ",") {
+		name, raw, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		weight, err := strconv.Atoi(raw)
+		if !ok || err != nil || weight < 0 {
+			return nil, fmt.Errorf("-mix 형식이 잘못되었습니다: %q (예: create=5,delete=4,extend=1)", entry)
+		}
+		if name != opCreate && name != opDelete && name != opExtend {
+			return nil, fmt.Errorf("알 수 없는 작업입니다: %q (create, delete, extend)", name)
+		}
+		m.ops = append(m.ops, name)
+		m.weights = append(m.weights, weight)
+		m.total += weight
+	}
+	if m.total == 0 {
+		return nil, fmt.Errorf("-mix의 가중치 합이 0입니다")
+	}
+	return m, nil
+}
+
+func (m *mix) weight(op string) int {
+	for i, name := range m.ops {
+		if name == op {
+			return m.weights[i]
+		}
+	}
+	return 0
+}
+
+func (m *mix) pick(rnd *rand.Rand) string {
+	n := rnd.Intn(m.total)
+	for i, weight := range m.weights {
+		if n < weight {
+			return m.ops[i]
+		}
+		n -= weight
+	}
+	return m.ops[len(m.ops)-1]
+}
+
+// virtualUser 가상 사용자와 현재 세션 (busy면 다른 워커가 요청 중)
+type virtualUser struct {
+	id        string
+	sessionID string
+	busy      bool
+}
+
+type userPool struct {
+	mu    sync.Mutex
+	users []*virtualUser
+	next  int
+}
+
+func newUserPool(prefix string, n int) *userPool {
+	p := &userPool{}
+	for i := 0; i < n; i++ {
+		p.users = append(p.users, &virtualUser{id: fmt.Sprintf("%s-%d", prefix, i)})
+	}
+	return p
+}
+
+func (p *userPool) ids() []string {
+	ids := make([]string, len(p.users))
+	for i, user := range p.users {
+		ids[i] = user.id
+	}
+	return ids
+}
+
+// take 세션 유무가 active와 같고 쉬고 있는 사용자를 돌아가며 골라 busy로 표시 (없으면 nil)
+func (p *userPool) take(active bool) *virtualUser {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for i := 0; i < len(p.users); i++ {
+		user := p.users[(p.next+i)%len(p.users)]
+		if !user.busy && (user.sessionID != "") == active {
+			p.next = (p.next + i + 1) % len(p.users)
+			user.busy = true
+			return user
+		}
+	}
+	return nil
+}
+
+func (p *userPool) release(user *virtualUser, sessionID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	user.sessionID = sessionID
+	user.busy = false
+}
+
+// extendSetup 연장에 쓰는 팀과 리드 키
+type extendSetup struct {
+	teamID string
+	keyID  string
+	lead   *client
+}
+
+func setupExtend(api *client, prefix string, members []string) (*extendSetup, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	var team struct {
+		ID string `json:"id"`
+	}
+	if _, err := api.do(ctx, http.MethodPost, "/teams", map[string]string{"name": prefix}, &team); err != nil {
+		return nil, fmt.Errorf("팀 생성 실패: %v", err)
+	}
+	leadID := prefix + "-lead"
+	var key struct {
+		Token    string `json:"token"`
+		Metadata struct {
+			ID string `json:"id"`
+		} `json:"metadata"`
+	}
+	if _, err := api.do(ctx, http.MethodPost, "/admin/api-keys", map[string]string{"name": prefix + "-lead", "role": "user", "user_id": leadID}, &key); err != nil {
+		return nil, fmt.Errorf("리드 키 발급 실패: %v", err)
+	}
+	setup := &extendSetup{teamID: team.ID, keyID: key.Metadata.ID, lead: api.withToken(key.Token)}
+
+	roles := map[string]string{leadID: "lead"}
+	for _, member := range members {
+		roles[member] = "member"
+	}
+	for userID, role := range roles {
+		if _, err := api.do(ctx, http.MethodPut, "/teams/"+team.ID+"/members/"+userID, map[string]string{"role": role}, nil); err != nil {
+			return setup, fmt.Errorf("팀 구성원 %s 추가 실패: %v", userID, err)
+		}
+	}
+	log.Printf("👥 연장용 팀 %s 준비 (리드: %s, 구성원 %d명)", team.ID, leadID, len(members))
+	return setup, nil
+}
+
+// teardown 남은 세션을 지우고 연장용 팀과 키를 정리 (실패해도 TTL이 지나면 세션은 만료됨)
+func teardown(api *client, pool *userPool, ext *extendSetup) {
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	deleted, failed := 0, 0
+	for _, user := range pool.users {
+		if user.sessionID == "" {
+			continue
+		}
+		if err := api.deleteSession(ctx, user.sessionID); err != nil {
+			failed++
+			log.Printf("⚠️ 세션 %s(사용자 %s) 정리 실패: %v", user.sessionID, user.id, err)
+			continue
+		}
+		deleted++
+	}
+	if ext != nil {
+		if _, err := api.do(ctx, http.MethodDelete, "/teams/"+ext.teamID, nil, nil); err != nil {
+			log.Printf("⚠️ 팀 %s 삭제 실패: %v", ext.teamID, err)
+		}
+		if ext.keyID != "" {
+			if _, err := api.do(ctx, http.MethodDelete, "/admin/api-keys/"+ext.keyID, nil, nil); err != nil {
+				log.Printf("⚠️ 리드 키 %s 폐기 실패: %v", ext.keyID, err)
+			}
+		}
+	}
+	log.Printf("🧹 정리 완료: 세션 %d개 삭제, %d개 실패", deleted, failed)
+}
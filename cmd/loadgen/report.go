@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// opStats 작업 하나의 결과 모음
+type opStats struct {
+	latencies []time.Duration // 성공한 호출만
+	errors    map[string]int  // 상태 코드 또는 transport, timeout
+	skipped   int             // 대상 사용자가 없어 건너뜀
+}
+
+// recorder 작업별 결과를 모으는 곳 (워커들이 함께 씀)
+type recorder struct {
+	mu    sync.Mutex
+	start time.Time
+	ops   map[string]*opStats
+}
+
+func newRecorder() *recorder {
+	return &recorder{start: time.Now(), ops: make(map[string]*opStats)}
+}
+
+func (r *recorder) stats(op string) *opStats {
+	stats, ok := r.ops[op]
+	if !ok {
+		stats = &opStats{errors: make(map[string]int)}
+		r.ops[op] = stats
+	}
+	return stats
+}
+
+func (r *recorder) record(op string, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stats := r.stats(op)
+	if err == nil {
+		stats.latencies = append(stats.latencies, latency)
+		return
+	}
+	stats.errors[errorClass(err)]++
+}
+
+func (r *recorder) skip(op string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stats(op).skipped++
+}
+
+// errorClass 오류를 집계 단위로 (API 오류는 상태 코드)
+func errorClass(err error) string {
+	var apiErr *apiError
+	switch {
+	case errors.As(err, &apiErr):
+		return fmt.Sprintf("%d", apiErr.Status)
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	default:
+		return "transport"
+	}
+}
+
+// OpReport 작업 하나의 처리량, 지연 시간 백분위, 오류율
+type OpReport struct {
+	Op         string         `json:"op"`
+	Requests   int            `json:"requests"`
+	Succeeded  int            `json:"succeeded"`
+	Failed     int            `json:"failed"`
+	Skipped    int            `json:"skipped"`
+	ErrorRate  float64        `json:"error_rate"`
+	Throughput float64        `json:"throughput_per_sec"` // 성공한 호출 기준
+	P50Ms      float64        `json:"p50_ms"`
+	P90Ms      float64        `json:"p90_ms"`
+	P99Ms      float64        `json:"p99_ms"`
+	MaxMs      float64        `json:"max_ms"`
+	Errors     map[string]int `json:"errors,omitempty"`
+}
+
+// Report 실행 전체 결과
+type Report struct {
+	Target          string     `json:"target"`
+	DurationSeconds float64    `json:"duration_seconds"`
+	Concurrency     int        `json:"concurrency"`
+	Async           bool       `json:"async"`
+	Ops             []OpReport `json:"ops"`
+}
+
+func (r *recorder) report(target string, concurrency int, async bool) *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	elapsed := time.Since(r.start)
+	report := &Report{
+		Target:          target,
+		DurationSeconds: elapsed.Seconds(),
+		Concurrency:     concurrency,
+		Async:           async,
+		Ops:             []OpReport{},
+	}
+	for op, stats := range r.ops {
+		sort.Slice(stats.latencies, func(i, j int) bool { return stats.latencies[i] < stats.latencies[j] })
+		entry := OpReport{
+			Op:        op,
+			Succeeded: len(stats.latencies),
+			Skipped:   stats.skipped,
+			Errors:    stats.errors,
+		}
+		for _, n := range stats.errors {
+			entry.Failed += n
+		}
+		entry.Requests = entry.Succeeded + entry.Failed
+		if entry.Requests > 0 {
+			entry.ErrorRate = float64(entry.Failed) / float64(entry.Requests)
+		}
+		if elapsed > 0 {
+			entry.Throughput = float64(entry.Succeeded) / elapsed.Seconds()
+		}
+		entry.P50Ms = percentile(stats.latencies, 0.50)
+		entry.P90Ms = percentile(stats.latencies, 0.90)
+		entry.P99Ms = percentile(stats.latencies, 0.99)
+		entry.MaxMs = percentile(stats.latencies, 1)
+		report.Ops = append(report.Ops, entry)
+	}
+	sort.Slice(report.Ops, func(i, j int) bool { return report.Ops[i].Op < report.Ops[j].Op })
+	return report
+}
+
+// percentile 정렬된 지연 시간의 q 백분위 (ms, 최근접 순위)
+func percentile(sorted []time.Duration, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(q*float64(len(sorted))+0.5) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return float64(sorted[index]) / float64(time.Millisecond)
+}
+
+func (report *Report) print(w io.Writer) error {
+	fmt.Fprintf(w, "대상: %s  시간: %.1fs  동시성: %d  async: %t\n\n", report.Target, report.DurationSeconds, report.Concurrency, report.Async)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "OP\tREQ\tOK\tFAIL\tSKIP\tERR%\tOK/S\tP50(ms)\tP90(ms)\tP99(ms)\tMAX(ms)\tERRORS")
+	for _, op := range report.Ops {
+		codes := make([]string, 0, len(op.Errors))
+		for code := range op.Errors {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		detail := ""
+		for _, code := range codes {
+			detail += fmt.Sprintf("%s×%d ", code, op.Errors[code])
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%.1f\t%.2f\t%.0f\t%.0f\t%.0f\t%.0f\t%s\n",
+			op.Op, op.Requests, op.Succeeded, op.Failed, op.Skipped, op.ErrorRate*100, op.Throughput,
+			op.P50Ms, op.P90Ms, op.P99Ms, op.MaxMs, detail)
+	}
+	return tw.Flush()
+}
@@ -7,39 +7,182 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata" // 컨테이너 이미지에 tzdata가 없어도 시간대 이름을 해석하기 위함
 
 	"github.com/sandman/gpu-ssh-gateway/internal/api"
+	"github.com/sandman/gpu-ssh-gateway/internal/auth"
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
 	"github.com/sandman/gpu-ssh-gateway/internal/docker"
 	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+	"github.com/sandman/gpu-ssh-gateway/internal/lock"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/manifest"
+	"github.com/sandman/gpu-ssh-gateway/internal/nodeca"
+	"github.com/sandman/gpu-ssh-gateway/internal/replica"
+	"github.com/sandman/gpu-ssh-gateway/internal/report"
 	"github.com/sandman/gpu-ssh-gateway/internal/session"
+	"github.com/sandman/gpu-ssh-gateway/internal/sshca"
+	"github.com/sandman/gpu-ssh-gateway/internal/sshpiper"
 	"github.com/sandman/gpu-ssh-gateway/internal/store"
+	"github.com/sandman/gpu-ssh-gateway/internal/tracing"
 	"github.com/sandman/gpu-ssh-gateway/internal/watcher"
 )
 
+// version 빌드 시 -ldflags "-X main.version=..."로 지정 (노드 등록 정보에 표시)
+var version = "dev"
+
 var (
-	port          = flag.String("port", "8080", "API 서버 포트")
-	dbPath        = flag.String("db", "/var/lib/orchestrator/sessions.db", "SQLite 데이터베이스 파일 경로")
-	workspaceRoot = flag.String("workspace-root", "/srv/workspaces", "사용자 워크스페이스 루트 디렉토리")
-	sshPortStart  = flag.Int("ssh-port-start", 10000, "SSH 포트 범위 시작")
-	sshPortEnd    = flag.Int("ssh-port-end", 20000, "SSH 포트 범위 끝")
+	port            = flag.String("port", "8080", "API 서버 포트")
+	dbPath          = flag.String("db", "/var/lib/orchestrator/sessions.db", "SQLite 데이터베이스 파일 경로")
+	workspaceRoot   = flag.String("workspace-root", "/srv/workspaces", "사용자 워크스페이스 루트 디렉토리")
+	sshPortStart    = flag.Int("ssh-port-start", 10000, "SSH 포트 범위 시작")
+	sshPortEnd      = flag.Int("ssh-port-end", 20000, "SSH 포트 범위 끝")
+	timezone        = flag.String("timezone", "UTC", "만료 시각 표시 및 벽시계 만료 계산에 사용할 기본 시간대 (예: Asia/Seoul)")
+	configPath      = flag.String("config", "", "리로드 가능한 설정 파일 경로 (JSON, SIGHUP 또는 POST /admin/config/reload로 재적용)")
+	sshCAKey        = flag.String("ssh-ca-key", "", "SSH CA 개인키 경로 (없으면 생성, 비워 두면 인증서 모드 비활성)")
+	manifestKey     = flag.String("manifest-key", "", "세션 매니페스트 서명 키 경로 (없으면 생성, 비워 두면 서명 없이 기록, 복제본은 같은 키를 공유)")
+	accessLogPath   = flag.String("access-log", "stdout", "API 접근 로그(JSON 한 줄) 출력 대상: stdout, off, 또는 파일 경로")
+	requireAuth     = flag.Bool("require-auth", false, "공개 경로(/healthz, /status 등)를 뺀 모든 API 요청에 API 키 요구 (첫 관리자 키는 sandman-admin create-api-key로 발급)")
+	shutdownTimeout = flag.Duration("shutdown-timeout", 2*time.Minute, "종료 시 진행 중인 세션 생성/삭제를 기다리는 최대 시간")
+
+	// 외부 폴링용 세션 상태 캐시
+	livenessInterval = flag.Duration("liveness-interval", 10*time.Second, "GET /sessions/:id/alive 캐시 갱신 간격 (컨테이너/SSH 확인)")
+
+	// 세션 기록, 컨테이너, GPU 할당, 라우트, 포트/IP 기록 일관성 검사
+	consistencyInterval = flag.Duration("consistency-interval", 10*time.Minute, "일관성 검사 간격 (0이면 GET /admin/consistency?refresh=true로만 검사)")
+
+	// 재시작 전 컨테이너로 포트/GPU 할당 기록 복구
+	startupReconcile = flag.Bool("startup-reconcile", true, "시작 시 세션 네트워크의 컨테이너와 세션 기록으로 포트/GPU/IP 할당 상태를 다시 만들고 세션 없는 컨테이너 제거")
+	orphanGrace      = flag.Duration("orphan-grace", 10*time.Minute, "시작 시 이 시간보다 오래된 세션 없는 컨테이너만 제거 (다른 복제본이 만들고 있는 컨테이너 보호, 단일 복제본이면 0)")
+
+	// 노드 에이전트 ↔ 컨트롤 플레인 mTLS (비워 두면 비활성, 에이전트는 관리자 키로 /admin/nodes 사용)
+	nodeCADir       = flag.String("node-ca-dir", "", "노드 에이전트 인증서를 발급할 CA 디렉토리 (ca.key/ca.crt, 없으면 생성, 복제본은 같은 디렉토리를 공유)")
+	nodeTrustDomain = flag.String("node-trust-domain", nodeca.DefaultTrustDomain, "노드 인증서 SPIFFE ID의 trust domain (spiffe://<domain>/node/<id>)")
+	nodeCertTTL     = flag.Duration("node-cert-ttl", nodeca.DefaultCertTTL, "노드 인증서 유효 시간 (에이전트는 2/3가 지나면 갱신)")
+	agentListen     = flag.String("agent-listen", "", "노드 에이전트 전용 mTLS 리스너 주소 (예: :8443, -node-ca-dir 필요)")
+	agentHosts      = flag.String("agent-hosts", "", "에이전트 리스너 인증서에 넣을 호스트 이름/IP (쉼표로 구분, 기본값: 이 호스트 이름)")
+
+	// OpenTelemetry 트레이스 (비워 두면 비활성, 들어온 traceparent는 그대로 전달)
+	otlpEndpoint     = flag.String("otlp-endpoint", "", "트레이스를 보낼 OTLP/HTTP 수집기 (host:port 또는 http(s)://host:port/v1/traces)")
+	otlpInsecure     = flag.Bool("otlp-insecure", false, "host:port 형식의 -otlp-endpoint에 TLS 없이 전송")
+	otlpHeaders      = flag.String("otlp-headers", "", "OTLP 요청 헤더 key=value (쉼표로 구분, 예: authorization=Bearer xyz)")
+	traceSampleRatio = flag.Float64("trace-sample-ratio", 1.0, "부모 span이 없는 요청의 트레이스 샘플링 비율 (0~1)")
+
+	// SSHPiper yaml 플러그인 라우트 게시 (비워 두면 비활성, 세션은 호스트 SSH 포트로만 접속)
+	sshpiperConfig      = flag.String("sshpiper-config", "", "라우트를 게시할 SSHPiper yaml 플러그인 설정 파일 경로 (예: /etc/sshpiper/sshpiperd.yaml)")
+	sshpiperAddr        = flag.String("sshpiper-addr", "", "라우트 반영 후 응답을 확인할 sshpiperd 주소 (예: 127.0.0.1:2222)")
+	sshpiperMinInterval = flag.Duration("sshpiper-min-interval", time.Second, "설정 파일 쓰기 최소 간격 (그 사이 변경은 한 번에 반영)")
+	wakeHost            = flag.String("wake-host", "", "잠긴 세션의 깨우기 리스너를 열 주소 (SSHPiper에서 접근 가능해야 함, 예: 127.0.0.1, 비워 두면 SSH 접속으로 깨우지 않음)")
+
+	// 노드 등록/하트비트 (멀티 노드 구성에서 스케줄러가 노드 상태를 판단하는 근거)
+	nodeID            = flag.String("node-id", "", "이 호스트의 노드 ID (기본값: 호스트명)")
+	nodeAddress       = flag.String("node-address", "", "다른 노드/사용자가 이 호스트에 접속할 주소")
+	heartbeatInterval = flag.Duration("heartbeat-interval", 15*time.Second, "노드 하트비트 간격")
+	missedHeartbeats  = flag.Int("missed-heartbeats", 3, "이 횟수만큼 하트비트가 없으면 노드를 unreachable로 표시")
+
+	// 여러 복제본이 함께 쓰는 PostgreSQL 저장소 (비워 두면 -db의 SQLite 파일 사용)
+	postgresDSN          = flag.String("postgres-dsn", "", "PostgreSQL 연결 문자열 (예: postgres://sandman@db:5432/sandman?sslmode=require, 비밀번호는 PGPASSWORD로도 지정)")
+	postgresMaxConns     = flag.Int("postgres-max-conns", store.DefaultPoolOptions.MaxOpenConns, "복제본 하나가 여는 최대 연결 수")
+	postgresMaxIdleConns = flag.Int("postgres-max-idle-conns", store.DefaultPoolOptions.MaxIdleConns, "유지할 유휴 연결 수")
+	postgresConnLifetime = flag.Duration("postgres-conn-lifetime", store.DefaultPoolOptions.ConnMaxLifetime, "연결을 닫고 새로 여는 주기 (0이면 무제한)")
+	schemaPolicy         = flag.String("schema-policy", string(store.SchemaMigrate), "저장소 스키마가 이 바이너리보다 오래되었을 때: migrate(시작하면서 올림) 또는 strict(시작 거부)")
+	migrateOnly          = flag.Bool("migrate-only", false, "저장소 스키마만 올리고 종료 (롤링 업그레이드 전에 한 번 실행)")
+
+	// SQLite WAL 스트리밍 복제 (단일 노드 배포의 디스크 장애 대비, 비워 두면 비활성)
+	replicaURL       = flag.String("replica-url", "", "litestream 복제본 URL (예: s3://bucket/sandman/sessions.db), DB 파일이 없으면 시작 시 여기서 복원")
+	litestreamBinary = flag.String("litestream-bin", "litestream", "복제에 사용할 litestream 실행 파일")
+
+	// 세션별 DNS 질의 감사 (세션 네트워크의 CoreDNS 사이드카가 질의를 기록, 보존 기간은 설정 파일에서 조정)
+	dnsAudit         = flag.Bool("dns-audit", false, "세션 DNS 질의 기록 (켠 뒤 만든 세션부터 적용)")
+	dnsAuditImage    = flag.String("dns-audit-image", "coredns/coredns:1.11.1", "DNS 감사 사이드카 이미지")
+	dnsAuditUpstream = flag.String("dns-audit-upstream", "/etc/resolv.conf", "사이드카가 질의를 넘길 업스트림 (주소 목록 또는 resolv.conf 경로)")
+
+	// 종료된 세션 기록의 정기 내보내기 (BI 도구용)
+	reportDestination = flag.String("report-destination", "", "세션 보고서 대상: 로컬 디렉토리 또는 s3://bucket/prefix (비워 두면 비활성)")
+	reportFormat      = flag.String("report-format", report.FormatCSV, "세션 보고서 형식: csv 또는 parquet")
+	reportInterval    = flag.Duration("report-interval", 24*time.Hour, "세션 보고서 구간 (UTC 기준으로 정렬)")
+
+	// 워크스페이스 저장 방식 (bind: <workspace-root>/<사용자> 디렉토리, volume: 사용자별 Docker 볼륨)
+	workspaceBackend      = flag.String("workspace-backend", docker.WorkspaceBackendBind, "워크스페이스 백엔드: bind 또는 volume")
+	workspaceVolumeDriver = flag.String("workspace-volume-driver", "local", "volume 백엔드의 볼륨 드라이버")
+	workspaceVolumeOpts   = docker.VolumeOptions{}
+
+	// 같은 호스트에서 여러 배포(staging/prod)를 띄울 때 리소스 이름이 겹치지 않도록 설정
+	deployment      = flag.String("deployment", "default", "배포 이름 (컨테이너, 이미지, 네트워크의 sandman.deployment 레이블 값)")
+	networkName     = flag.String("network-name", docker.DefaultNetworkName, "세션 컨테이너용 Docker 네트워크 이름")
+	networkSubnet   = flag.String("network-subnet", docker.NetworkSubnet, "세션 컨테이너 네트워크 서브넷 (배포마다 겹치지 않게 지정)")
+	imagePrefix     = flag.String("image-prefix", docker.DefaultImage, "사용자별 이미지 이름 접두사 (<접두사>-<사용자>)")
+	containerPrefix = flag.String("container-prefix", "", "컨테이너 이름 접두사")
+	containerSuffix = flag.String("container-suffix", "-container", "컨테이너 이름 접미사")
 )
 
 func main() {
+	flag.Var(workspaceVolumeOpts, "workspace-volume-opt", "volume 백엔드의 드라이버 옵션 key=value (여러 번 지정, 값의 {user}는 사용자 ID로 바뀜)")
 	flag.Parse()
 
 	// 로그 설정
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 	log.Println("🚀 GPU SSH Gateway Orchestrator 시작 중...")
 
-	// 데이터베이스 초기화
-	log.Println("📦 데이터베이스 초기화 중...")
-	db, err := store.NewSQLiteStore(*dbPath)
+	// 설정 로드 (파일 값이 플래그 기본값을 덮어씀)
+	baseConfig := config.Defaults()
+	baseConfig.Timezone = *timezone
+	cfgManager, err := config.NewManager(*configPath, baseConfig)
 	if err != nil {
+		log.Fatalf("설정 로드 실패: %v", err)
+	}
+
+	// 복제본 복원 (DB 파일이 없을 때만, 저장소를 열기 전에 수행)
+	var replicator *replica.Litestream
+	if *replicaURL != "" && *postgresDSN != "" {
+		log.Fatalf("-replica-url은 SQLite 저장소에서만 사용할 수 있습니다 (-postgres-dsn과 함께 지정됨)")
+	}
+	if *replicaURL != "" {
+		replicator, err = replica.NewLitestream(*litestreamBinary, *dbPath, *replicaURL)
+		if err != nil {
+			log.Fatalf("DB 복제 설정 실패: %v", err)
+		}
+		if _, err := replicator.Restore(); err != nil {
+			log.Fatalf("%v", err)
+		}
+	}
+
+	// 데이터베이스 초기화 (스키마 버전이 맞지 않으면 정책에 따라 올리거나 시작 거부)
+	log.Println("📦 데이터베이스 초기화 중...")
+	policy := store.SchemaPolicy(*schemaPolicy)
+	if !store.ValidSchemaPolicy(policy) {
+		log.Fatalf("-schema-policy가 잘못되었습니다: %q (%s, %s)", *schemaPolicy, store.SchemaMigrate, store.SchemaStrict)
+	}
+	if *migrateOnly {
+		policy = store.SchemaMigrate
+	}
+	var db store.Store
+	if *postgresDSN != "" {
+		pg, err := store.OpenPostgresStore(*postgresDSN, policy)
+		if err != nil {
+			log.Fatalf("데이터베이스 초기화 실패: %v", err)
+		}
+		pg.SetPool(store.PoolOptions{
+			MaxOpenConns:    *postgresMaxConns,
+			MaxIdleConns:    *postgresMaxIdleConns,
+			ConnMaxLifetime: *postgresConnLifetime,
+		})
+		db = pg
+		log.Printf("🐘 PostgreSQL 저장소 사용 (최대 연결 %d개)", *postgresMaxConns)
+	} else if db, err = store.OpenSQLiteStore(*dbPath, policy); err != nil {
 		log.Fatalf("데이터베이스 초기화 실패: %v", err)
 	}
-	defer db.Close()
+	if *migrateOnly {
+		log.Printf("✅ 저장소 스키마 v%d 준비 완료 (-migrate-only)", store.SchemaVersion)
+		db.Close()
+		return
+	}
+	if replicator != nil {
+		replicator.Start()
+	}
 
 	// GPU 관리자 초기화
 	log.Println("🎮 GPU 관리자 초기화 중...")
@@ -51,24 +194,237 @@ func main() {
 
 	// Docker 클라이언트 초기화
 	log.Println("🐳 Docker 클라이언트 초기화 중...")
-	dockerClient, err := docker.NewClient(*sshPortStart, *sshPortEnd)
+	naming := docker.Naming{
+		Deployment:      *deployment,
+		NetworkName:     *networkName,
+		NetworkSubnet:   *networkSubnet,
+		ImagePrefix:     *imagePrefix,
+		ContainerPrefix: *containerPrefix,
+		ContainerSuffix: *containerSuffix,
+	}
+	dockerClient, err := docker.NewClient(*sshPortStart, *sshPortEnd, naming)
 	if err != nil {
 		log.Fatalf("Docker 클라이언트 초기화 실패: %v", err)
 	}
 	defer dockerClient.Close()
 
+	// 워크스페이스 백엔드 (세션 컨테이너를 만들기 전에 정해야 함)
+	switch *workspaceBackend {
+	case docker.WorkspaceBackendBind:
+	case docker.WorkspaceBackendVolume:
+		volumes := docker.WorkspaceVolumes{Driver: *workspaceVolumeDriver, Options: workspaceVolumeOpts}
+		dockerClient.SetWorkspaceVolumes(volumes)
+		log.Printf("💾 워크스페이스 볼륨 백엔드: 드라이버 %s (옵션: %s)", volumes.Driver, volumes.Options)
+		if !volumes.HostReadable() {
+			log.Printf("⚠️ 볼륨 내용을 호스트에서 읽을 수 없어 환영 메시지, 워크스페이스 .ssh, 복원 지점, 보관 정책을 사용할 수 없습니다")
+		}
+	default:
+		log.Fatalf("-workspace-backend가 잘못되었습니다: %q (bind, volume)", *workspaceBackend)
+	}
+
+	// NVIDIA 런타임 확인 (GPU가 있는데 없으면 GPU 세션 생성이 거부됨, GPU가 없는 호스트는 필요 없음)
+	if runtime := dockerClient.CheckNVIDIARuntime(); runtime.Available {
+		log.Println("✅ Docker 데몬에 NVIDIA 런타임이 구성되어 있습니다")
+	} else if gpuManager.HasGPU() {
+		log.Printf("⚠️ NVIDIA 런타임이 구성되지 않아 GPU 세션 생성이 거부됩니다: %s", runtime.Error)
+	} else {
+		log.Println("ℹ️ NVIDIA 런타임이 없지만 GPU가 없는 호스트라 필요하지 않습니다 (CPU 전용 세션만 제공)")
+	}
+
+	// 복제본 간 임계 구역(GPU 할당, IPAM, 정리 작업)용 분산 락
+	locker := lock.NewLocker(db, lock.DefaultOwner())
+	dockerClient.SetLocker(locker)
+
+	// DNS 감사 사이드카 (세션 컨테이너를 만들기 전에 준비해야 새 세션이 사이드카를 DNS로 사용)
+	if *dnsAudit {
+		if _, err := dockerClient.EnsureDNSSidecar(*dnsAuditImage, *dnsAuditUpstream); err != nil {
+			log.Fatalf("DNS 감사 사이드카 준비 실패: %v", err)
+		}
+	}
+	log.Printf("🔐 분산 락 소유자 ID: %s", locker.Owner())
+
 	// 세션 서비스 초기화
-	sessionService := session.NewService(db, dockerClient, gpuManager, *workspaceRoot)
+	sessionService := session.NewService(db, dockerClient, gpuManager, *workspaceRoot, cfgManager, locker)
+
+	// SSH CA (세션 principal로 제한된 단기 사용자 인증서 발급)
+	if *sshCAKey != "" {
+		ca, err := sshca.LoadOrCreate(*sshCAKey)
+		if err != nil {
+			log.Fatalf("SSH CA 초기화 실패: %v", err)
+		}
+		sessionService.SetSSHCA(ca)
+	}
+
+	// 노드 에이전트 인증서 CA (가입 토큰으로 첫 인증서, 이후 mTLS로 갱신)
+	var nodeCA *nodeca.CA
+	if *nodeCADir != "" {
+		if nodeCA, err = nodeca.LoadOrCreate(*nodeCADir, *nodeTrustDomain); err != nil {
+			log.Fatalf("노드 CA 초기화 실패: %v", err)
+		}
+		sessionService.SetNodeCA(nodeCA, *nodeCertTTL)
+	} else if *agentListen != "" {
+		log.Fatalf("-agent-listen에는 -node-ca-dir이 필요합니다")
+	}
+
+	// 세션 매니페스트 서명 (인용/재현용 환경 기록)
+	if *manifestKey != "" {
+		signer, err := manifest.LoadOrCreate(*manifestKey)
+		if err != nil {
+			log.Fatalf("매니페스트 서명 키 초기화 실패: %v", err)
+		}
+		sessionService.SetManifestSigner(signer)
+	}
+
+	// 노드 등록 및 하트비트 (이 오케스트레이터가 자기 호스트의 에이전트 역할)
+	if *missedHeartbeats < 1 {
+		log.Fatalf("-missed-heartbeats는 1 이상이어야 합니다: %d", *missedHeartbeats)
+	}
+	hostname, _ := os.Hostname()
+	if *nodeID == "" {
+		*nodeID = hostname
+	}
+	sessionService.SetNodeID(*nodeID)
+
+	// 트레이싱 설정
+	headers, err := tracing.ParseHeaders(*otlpHeaders)
+	if err != nil {
+		log.Fatalf("-otlp-headers 해석 실패: %v", err)
+	}
+	traceShutdown, err := tracing.Setup(tracing.Config{
+		Endpoint:    *otlpEndpoint,
+		Insecure:    *otlpInsecure,
+		Headers:     headers,
+		SampleRatio: *traceSampleRatio,
+		ServiceName: "sandman-orchestrator",
+		NodeID:      *nodeID,
+	})
+	if err != nil {
+		log.Fatalf("트레이싱 설정 실패: %v", err)
+	}
+	nodeAgent := watcher.NewNodeAgent(sessionService, sessionService.LocalNodeRegistration(hostname, *nodeAddress, version), *heartbeatInterval, *missedHeartbeats)
+	if err := nodeAgent.Start(); err != nil {
+		log.Fatalf("노드 등록 실패: %v", err)
+	}
+
+	// SSHPiper 라우트 게시 시작 (노드 ID가 정해진 뒤 이 노드 세션으로 테이블 구성)
+	var router *sshpiper.Router
+	if *sshpiperConfig != "" {
+		if *sshpiperMinInterval <= 0 {
+			log.Fatalf("-sshpiper-min-interval은 0보다 커야 합니다: %v", *sshpiperMinInterval)
+		}
+		router = sshpiper.NewRouter(*sshpiperConfig, *sshpiperAddr, *sshpiperMinInterval)
+		router.Start()
+		if err := sessionService.SetRouter(router); err != nil {
+			log.Fatalf("SSHPiper 라우트 초기화 실패: %v", err)
+		}
+		sessionService.SetWakeHost(*wakeHost)
+	}
+
+	// 재시작으로 비어 있는 할당 기록 복구 (감시자와 API가 새 세션에 포트/GPU를 주기 전에)
+	if *startupReconcile {
+		if _, err := sessionService.ReconcileStartup(*orphanGrace); err != nil {
+			log.Fatalf("시작 복구 실패: %v", err)
+		}
+	}
 
 	// TTL 감시자 시작
 	log.Println("⏰ TTL 감시자 시작 중...")
 	ttlWatcher := watcher.NewTTLWatcher(sessionService, 1*time.Minute)
 	ttlWatcher.Start()
-	defer ttlWatcher.Stop()
+
+	// 네트워크 사용량 수집기 시작
+	usageWatcher := watcher.NewUsageWatcher(sessionService, 1*time.Minute)
+	usageWatcher.Start()
+
+	// 워크스페이스 복원 지점 생성기 시작 (간격/보존 개수는 설정 파일에서 조정)
+	restorePointWatcher := watcher.NewRestorePointWatcher(sessionService, 1*time.Minute)
+	restorePointWatcher.Start()
+
+	// 크래시 루프 감지 시작 (기준은 설정 파일의 crash_loop_restarts, crash_loop_window_minutes)
+	crashLoopWatcher := watcher.NewCrashLoopWatcher(sessionService, 30*time.Second)
+	crashLoopWatcher.Start()
+
+	// 호스트 NTP 동기화와 컨테이너 시계 차이 확인 시작 (허용 범위는 설정 파일의 clock_tolerance_ms)
+	clockWatcher := watcher.NewClockWatcher(sessionService, 5*time.Minute)
+	clockWatcher.Start()
+
+	// NVIDIA 드라이버/CUDA/NVML 버전과 MIG 모드 재확인 (드라이버 업그레이드, MIG 모드 변경을 노드 정보에 반영)
+	driverWatcher := watcher.NewDriverWatcher(sessionService, 15*time.Minute)
+	driverWatcher.Start()
+
+	// Docker 이벤트 감시 시작 (외부에서 지운 네트워크 복구, 볼륨/이미지 삭제 기록)
+	dockerEventWatcher := watcher.NewDockerEventWatcher(sessionService, dockerClient)
+	dockerEventWatcher.Start()
+
+	// 유휴 세션 잠금/GPU 회수 시작 (기준은 설정 파일의 inactivity_lock)
+	inactivityWatcher := watcher.NewInactivityWatcher(sessionService, 1*time.Minute)
+	inactivityWatcher.Start()
+
+	// 세션 상태 파일 갱신 시작 (컨테이너 안 sandman-status와 로그인 알림, 경고 기준은 설정 파일의 session_agent)
+	agentStatusWatcher := watcher.NewAgentStatusWatcher(sessionService, 1*time.Minute)
+	agentStatusWatcher.Start()
+
+	// 일관성 검사 시작 (안전한 불일치는 고치고 나머지는 감사 기록으로 알림)
+	var consistencyWatcher *watcher.ConsistencyWatcher
+	if *consistencyInterval > 0 {
+		consistencyWatcher = watcher.NewConsistencyWatcher(sessionService, *consistencyInterval)
+		consistencyWatcher.Start()
+	}
+
+	// 워크스페이스 수명 주기 작업 시작 (정책은 사용자 설정과 설정 파일의 workspace_retention)
+	workspaceWatcher := watcher.NewWorkspaceLifecycleWatcher(sessionService, 10*time.Minute)
+	workspaceWatcher.Start()
+
+	// DNS 감사 기록 시작 (보존 기간/최대 행 수는 설정 파일의 dns_audit_retention_days, dns_audit_max_rows)
+	var dnsAuditWatcher *watcher.DNSAuditWatcher
+	if *dnsAudit {
+		dnsAuditWatcher = watcher.NewDNSAuditWatcher(sessionService, dockerClient, 5*time.Second)
+		dnsAuditWatcher.Start()
+	}
+
+	// 세션 상태 캐시 갱신 시작 (외부 로드 밸런서/SSO 런처 폴링용)
+	livenessWatcher := watcher.NewLivenessWatcher(sessionService, *livenessInterval)
+	livenessWatcher.Start()
+
+	// 세션 보고서 작업 시작 (대상이 지정된 경우)
+	var reportWatcher *watcher.ReportWatcher
+	if *reportDestination != "" {
+		dest, err := report.ParseDestination(*reportDestination)
+		if err != nil {
+			log.Fatalf("세션 보고서 대상 설정 실패: %v", err)
+		}
+		if !report.ValidFormat(*reportFormat) {
+			log.Fatalf("-report-format이 잘못되었습니다: %q (csv, parquet)", *reportFormat)
+		}
+		if *reportInterval < 5*time.Minute {
+			log.Fatalf("-report-interval은 5분 이상이어야 합니다: %v", *reportInterval)
+		}
+		reportWatcher = watcher.NewReportWatcher(sessionService, dest, *reportFormat, *reportInterval)
+		reportWatcher.Start()
+	}
 
 	// API 서버 초기화
 	log.Println("🌐 API 서버 초기화 중...")
-	apiServer := api.NewServer(sessionService, gpuManager)
+	authService := auth.NewService(db)
+	apiServer := api.NewServer(sessionService, gpuManager, authService, dockerClient, cfgManager)
+	apiServer.SetRequireAuth(*requireAuth)
+	if *requireAuth {
+		log.Println("🔐 API 키 인증 사용 (관리 API는 admin 범위 키 필요)")
+	} else {
+		log.Println("⚠️ API 키 인증이 꺼져 있습니다. 네트워크의 누구나 세션을 만들고 지울 수 있습니다 (-require-auth)")
+	}
+	switch *accessLogPath {
+	case "stdout":
+	case "off", "":
+		apiServer.SetAccessLog(nil)
+	default:
+		accessLogFile, err := os.OpenFile(*accessLogPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640)
+		if err != nil {
+			log.Fatalf("접근 로그 파일 열기 실패: %v", err)
+		}
+		defer accessLogFile.Close()
+		apiServer.SetAccessLog(accessLogFile)
+	}
 
 	// HTTP 서버 설정
 	srv := &http.Server{
@@ -84,18 +440,100 @@ func main() {
 		}
 	}()
 
-	// 우아한 종료 처리
+	// 노드 에이전트 mTLS 리스너 (CA가 발급한 클라이언트 인증서만 받음)
+	var agentSrv *http.Server
+	if *agentListen != "" {
+		hosts := []string{hostname}
+		if *agentHosts != "" {
+			hosts = strings.Split(*agentHosts, ",")
+		}
+		agentSrv = &http.Server{
+			Addr:      *agentListen,
+			Handler:   apiServer.SetupAgentRoutes(),
+			TLSConfig: nodeCA.ServerTLSConfig(hosts),
+		}
+		go func() {
+			log.Printf("🔐 노드 에이전트 mTLS 리스너가 %s에서 시작되었습니다 (%s)", *agentListen, nodeca.SPIFFEID(nodeCA.TrustDomain(), "*"))
+			if err := agentSrv.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("에이전트 리스너 시작 실패: %v", err)
+			}
+		}()
+	}
+
+	// SIGHUP: 설정 리로드, SIGINT/SIGTERM: 우아한 종료
 	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
-	<-quit
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	for sig := range quit {
+		if sig != syscall.SIGHUP {
+			break
+		}
+		log.Println("🔄 SIGHUP 수신 - 설정 리로드")
+		if _, err := cfgManager.Reload(); err != nil {
+			log.Printf("⚠️ 설정 리로드 실패 (기존 설정 유지): %v", err)
+		}
+	}
 	log.Println("🛑 Orchestrator 종료 중...")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	// 1. 새 요청 거부 (로드 밸런서가 /readyz로 트래픽을 뺄 수 있도록)
+	apiServer.BeginDrain()
+
+	ctx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
 	defer cancel()
 
+	// 2. 진행 중인 세션 생성/삭제 완료 대기 후 남은 만료 세션 정리
+	if err := sessionService.Shutdown(ctx); err != nil {
+		log.Printf("⚠️ 세션 작업 정리 중 오류: %v", err)
+	}
+
+	// 3. HTTP 서버 종료 (남은 요청 응답 마무리)
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Printf("서버 종료 중 오류: %v", err)
 	}
+	if agentSrv != nil {
+		if err := agentSrv.Shutdown(ctx); err != nil {
+			log.Printf("에이전트 리스너 종료 중 오류: %v", err)
+		}
+	}
+
+	// 4. 백그라운드 작업 중지 후 저장소 닫기 (나머지 리소스는 defer로 정리)
+	ttlWatcher.Stop()
+	usageWatcher.Stop()
+	restorePointWatcher.Stop()
+	livenessWatcher.Stop()
+	crashLoopWatcher.Stop()
+	clockWatcher.Stop()
+	driverWatcher.Stop()
+	dockerEventWatcher.Stop()
+	inactivityWatcher.Stop()
+	agentStatusWatcher.Stop()
+	if consistencyWatcher != nil {
+		consistencyWatcher.Stop()
+	}
+	workspaceWatcher.Stop()
+	if dnsAuditWatcher != nil {
+		dnsAuditWatcher.Stop()
+	}
+	if reportWatcher != nil {
+		reportWatcher.Stop()
+	}
+	nodeAgent.Stop()
+	if router != nil {
+		router.Stop()
+	}
+	// 종료 시한이 이미 지났어도 남은 span을 보낼 수 있도록 별도 시한 사용
+	traceCtx, traceCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := traceShutdown(traceCtx); err != nil {
+		log.Printf("트레이스 내보내기 종료 중 오류: %v", err)
+	}
+	traceCancel()
+	if err := db.Close(); err != nil {
+		log.Printf("데이터베이스 종료 중 오류: %v", err)
+	}
+	if replicator != nil {
+		replicator.Stop()
+	}
 
+	// 요약 대기 중인 반복 로그의 생략 건수 출력
+	logging.FlushSampled()
 	log.Println("✅ Orchestrator가 성공적으로 종료되었습니다")
 }
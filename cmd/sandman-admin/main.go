@@ -0,0 +1,474 @@
+// sandman-admin API 서버가 내려가 있을 때 DB와 Docker 호스트를 직접 다루는 운영자용 도구
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/auth"
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+var (
+	dbPath      = flag.String("db", "/var/lib/orchestrator/sessions.db", "SQLite 데이터베이스 파일 경로")
+	networkName = flag.String("network-name", docker.DefaultNetworkName, "오케스트레이터가 사용하는 Docker 네트워크 이름")
+)
+
+const usage = `사용법: sandman-admin [-db 경로] [-network-name 이름] <명령> [옵션]
+
+명령:
+  sessions                 저장된 세션 목록 출력
+  orphans [-fix]           세션 없는 컨테이너 / 컨테이너 없는 세션 검사 (-fix: 정리)
+  release-gpu <GPU UUID>   해당 GPU를 점유한 세션의 컨테이너와 레코드를 강제로 정리
+  leases [-fix]            DB의 포트/IP 임대 정보를 실제 컨테이너와 비교 (-fix: DB 갱신)
+  dump <파일>              데이터베이스 스냅샷 저장
+  restore <파일>           스냅샷으로 데이터베이스 복원 (오케스트레이터 중지 후 실행)
+  rotate-tokens            모든 활성 API 토큰을 폐기하고 같은 설정으로 재발급
+  create-api-key [-user ID] <이름>
+                           관리자 API 키 발급 (-require-auth 배포의 첫 키, -user: 키가 대표할 사용자)
+`
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	log.SetFlags(0)
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	cmd, args := flag.Arg(0), flag.Args()[1:]
+
+	var err error
+	switch cmd {
+	case "sessions":
+		err = withStore(listSessions)
+	case "orphans":
+		err = runOrphans(args)
+	case "release-gpu":
+		err = runReleaseGPU(args)
+	case "leases":
+		err = runLeases(args)
+	case "dump":
+		err = runDump(args)
+	case "restore":
+		err = runRestore(args)
+	case "rotate-tokens":
+		err = withStore(rotateTokens)
+	case "create-api-key":
+		err = runCreateAPIKey(args)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+}
+
+func withStore(fn func(db *store.SQLiteStore) error) error {
+	db, err := store.NewSQLiteStore(*dbPath)
+	if err != nil {
+		return fmt.Errorf("데이터베이스 열기 실패: %v", err)
+	}
+	defer db.Close()
+	return fn(db)
+}
+
+func withStoreAndDocker(fn func(db *store.SQLiteStore, dockerClient *docker.Client) error) error {
+	return withStore(func(db *store.SQLiteStore) error {
+		naming := docker.DefaultNaming()
+		naming.NetworkName = *networkName
+		dockerClient, err := docker.NewInspectClient(naming)
+		if err != nil {
+			return err
+		}
+		defer dockerClient.Close()
+		return fn(db, dockerClient)
+	})
+}
+
+func fixFlag(name string, args []string) (bool, []string, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fix := fs.Bool("fix", false, "발견된 불일치를 정리")
+	if err := fs.Parse(args); err != nil {
+		return false, nil, err
+	}
+	return *fix, fs.Args(), nil
+}
+
+func newTable(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+}
+
+func listSessions(db *store.SQLiteStore) error {
+	sessions, err := db.ListAllSessions()
+	if err != nil {
+		return err
+	}
+
+	tw := newTable(os.Stdout)
+	fmt.Fprintln(tw, "ID\tUSER\tCONTAINER\tIP\tPORT\tGPU\tEXPIRES")
+	for _, s := range sessions {
+		fmt.Fprintf(tw, "%s\t%s\t%.12s\t%s\t%d\t%s\t%s\n",
+			s.ID, s.UserID, s.ContainerID, s.ContainerIP, s.SSHPort, s.GPUUUID, s.ExpiresAt.Format(time.RFC3339))
+	}
+	return tw.Flush()
+}
+
+func runOrphans(args []string) error {
+	fix, _, err := fixFlag("orphans", args)
+	if err != nil {
+		return err
+	}
+
+	return withStoreAndDocker(func(db *store.SQLiteStore, dockerClient *docker.Client) error {
+		sessions, err := db.ListAllSessions()
+		if err != nil {
+			return err
+		}
+		containers, err := dockerClient.ListManagedContainers()
+		if err != nil {
+			return err
+		}
+
+		byContainer := make(map[string]*store.Session, len(sessions))
+		for _, s := range sessions {
+			byContainer[s.ContainerID] = s
+		}
+		live := make(map[string]bool, len(containers))
+		for _, c := range containers {
+			live[c.ID] = true
+		}
+
+		problems := 0
+		for _, c := range containers {
+			if _, ok := byContainer[c.ID]; ok {
+				continue
+			}
+			problems++
+			fmt.Printf("🧟 세션 없는 컨테이너: %s (%s, %s)\n", c.Name, c.ID[:12], c.State)
+			if fix {
+				if err := dockerClient.RemoveContainer(c.ID); err != nil {
+					fmt.Printf("   ⚠️ 제거 실패: %v\n", err)
+				}
+			}
+		}
+
+		for _, s := range sessions {
+			if live[s.ContainerID] {
+				continue
+			}
+			problems++
+			fmt.Printf("👻 컨테이너 없는 세션: %s (사용자: %s, GPU: %s)\n", s.ID, s.UserID, s.GPUUUID)
+			if fix {
+				db.DeleteCollaborators(s.ID)
+				if err := releaseGPUClaim(db, s); err != nil {
+					fmt.Printf("   ⚠️ GPU 할당 기록 삭제 실패: %v\n", err)
+				}
+				if err := db.DeleteSession(s.ID); err != nil {
+					fmt.Printf("   ⚠️ 세션 삭제 실패: %v\n", err)
+				}
+			}
+		}
+
+		if problems == 0 {
+			fmt.Println("✅ 고아 리소스가 없습니다")
+		} else if !fix {
+			fmt.Printf("\n%d건 발견. -fix로 정리할 수 있습니다\n", problems)
+		}
+		return nil
+	})
+}
+
+func runReleaseGPU(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("GPU UUID를 지정해야 합니다")
+	}
+	gpuUUID := args[0]
+
+	return withStoreAndDocker(func(db *store.SQLiteStore, dockerClient *docker.Client) error {
+		sessions, err := db.ListAllSessions()
+		if err != nil {
+			return err
+		}
+
+		released := false
+		for _, s := range sessions {
+			if s.GPUUUID != gpuUUID {
+				continue
+			}
+			fmt.Printf("🔓 세션 %s (사용자: %s)가 %s를 점유 중 - 정리합니다\n", s.ID, s.UserID, gpuUUID)
+			if err := dockerClient.StopContainer(s.ContainerID); err != nil {
+				fmt.Printf("   ⚠️ 컨테이너 중지 실패: %v\n", err)
+			}
+			if err := dockerClient.RemoveContainer(s.ContainerID); err != nil {
+				fmt.Printf("   ⚠️ 컨테이너 제거 실패: %v\n", err)
+			}
+			db.DeleteCollaborators(s.ID)
+			if err := releaseGPUClaim(db, s); err != nil {
+				return fmt.Errorf("GPU 할당 기록 삭제 실패: %v", err)
+			}
+			if err := db.DeleteSession(s.ID); err != nil {
+				return fmt.Errorf("세션 삭제 실패: %v", err)
+			}
+			released = true
+		}
+
+		// DB에 기록이 없어도 해당 GPU를 붙잡고 있는 컨테이너가 있으면 제거
+		containers, err := dockerClient.ListManagedContainers()
+		if err != nil {
+			return err
+		}
+		for _, c := range containers {
+			if c.GPUUUID != gpuUUID {
+				continue
+			}
+			fmt.Printf("🗑️ GPU %s를 사용하는 컨테이너 %s 제거\n", gpuUUID, c.Name)
+			if err := dockerClient.RemoveContainer(c.ID); err != nil {
+				fmt.Printf("   ⚠️ 제거 실패: %v\n", err)
+			}
+			released = true
+		}
+
+		if !released {
+			fmt.Printf("ℹ️ %s를 점유한 세션이나 컨테이너가 없습니다\n", gpuUUID)
+			return nil
+		}
+		fmt.Println("✅ GPU 해제 완료 (오케스트레이터 재시작 시 사용 가능 상태로 인식됩니다)")
+		return nil
+	})
+}
+
+// releaseGPUClaim 세션 기록을 지우기 전에 GPU 할당 기록도 지움 (남으면 오케스트레이터가 다시 사용 중으로 표시)
+func releaseGPUClaim(db *store.SQLiteStore, s *store.Session) error {
+	if s.GPUUUID == "" {
+		return nil
+	}
+	return db.ReleaseGPUClaim(s.GPUUUID, s.ID)
+}
+
+func runLeases(args []string) error {
+	fix, _, err := fixFlag("leases", args)
+	if err != nil {
+		return err
+	}
+
+	return withStoreAndDocker(func(db *store.SQLiteStore, dockerClient *docker.Client) error {
+		sessions, err := db.ListAllSessions()
+		if err != nil {
+			return err
+		}
+		containers, err := dockerClient.ListManagedContainers()
+		if err != nil {
+			return err
+		}
+
+		byID := make(map[string]*docker.ManagedContainer, len(containers))
+		portOwners := make(map[int][]string)
+		ipOwners := make(map[string][]string)
+		for _, c := range containers {
+			byID[c.ID] = c
+			if c.SSHPort > 0 {
+				portOwners[c.SSHPort] = append(portOwners[c.SSHPort], c.Name)
+			}
+			if c.IP != "" {
+				ipOwners[c.IP] = append(ipOwners[c.IP], c.Name)
+			}
+		}
+
+		tw := newTable(os.Stdout)
+		fmt.Fprintln(tw, "SESSION\tUSER\tDB PORT\tLIVE PORT\tDB IP\tLIVE IP\tSTATUS")
+		mismatches := 0
+		for _, s := range sessions {
+			c, ok := byID[s.ContainerID]
+			if !ok {
+				fmt.Fprintf(tw, "%s\t%s\t%d\t-\t%s\t-\t컨테이너 없음\n", s.ID, s.UserID, s.SSHPort, s.ContainerIP)
+				continue
+			}
+
+			status := "일치"
+			if c.SSHPort != s.SSHPort || c.IP != s.ContainerIP {
+				status = "불일치"
+				mismatches++
+				if fix {
+					s.SSHPort = c.SSHPort
+					s.ContainerIP = c.IP
+					if err := db.UpdateSession(s); err != nil {
+						status = "갱신 실패: " + err.Error()
+					} else {
+						status = "갱신됨"
+					}
+				}
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\t%s\t%s\n", s.ID, s.UserID, s.SSHPort, c.SSHPort, s.ContainerIP, c.IP, status)
+		}
+		tw.Flush()
+
+		for port, owners := range portOwners {
+			if len(owners) > 1 {
+				fmt.Printf("⚠️ 포트 %d가 여러 컨테이너에 바인딩됨: %v\n", port, owners)
+			}
+		}
+		for ip, owners := range ipOwners {
+			if len(owners) > 1 {
+				fmt.Printf("⚠️ IP %s가 여러 컨테이너에 할당됨: %v\n", ip, owners)
+			}
+		}
+
+		if mismatches > 0 && !fix {
+			fmt.Printf("\n%d건 불일치. -fix로 DB를 실제 상태에 맞출 수 있습니다\n", mismatches)
+		}
+		return nil
+	})
+}
+
+func runDump(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("저장할 파일 경로를 지정해야 합니다")
+	}
+	if _, err := os.Stat(args[0]); err == nil {
+		return fmt.Errorf("파일이 이미 존재합니다: %s", args[0])
+	}
+
+	return withStore(func(db *store.SQLiteStore) error {
+		if err := db.Backup(args[0]); err != nil {
+			return fmt.Errorf("스냅샷 저장 실패: %v", err)
+		}
+		fmt.Printf("✅ 스냅샷 저장 완료: %s\n", args[0])
+		return nil
+	})
+}
+
+func runRestore(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("복원할 스냅샷 파일을 지정해야 합니다")
+	}
+	snapshot := args[0]
+
+	// 스냅샷이 열리고 스키마가 최신인지 먼저 확인
+	check, err := store.NewSQLiteStore(snapshot)
+	if err != nil {
+		return fmt.Errorf("스냅샷 검증 실패: %v", err)
+	}
+	sessions, err := check.ListAllSessions()
+	check.Close()
+	if err != nil {
+		return fmt.Errorf("스냅샷 검증 실패: %v", err)
+	}
+
+	// 현재 DB는 덮어쓰기 전에 보관 (WAL/SHM도 함께 옮겨 보관본이 온전하고, 남은 WAL이 복원한 DB 위에 적용되지 않게 함)
+	backup := fmt.Sprintf("%s.bak-%s", *dbPath, time.Now().Format("20060102-150405"))
+	if _, err := os.Stat(*dbPath); err == nil {
+		if err := os.Rename(*dbPath, backup); err != nil {
+			return fmt.Errorf("기존 데이터베이스 보관 실패: %v", err)
+		}
+		fmt.Printf("📦 기존 데이터베이스 보관: %s\n", backup)
+	}
+	for _, suffix := range sqliteSidecars {
+		if _, err := os.Stat(*dbPath + suffix); err != nil {
+			continue
+		}
+		if err := os.Rename(*dbPath+suffix, backup+suffix); err != nil {
+			return fmt.Errorf("기존 데이터베이스 %s 보관 실패: %v", suffix, err)
+		}
+	}
+
+	// 검증하며 연 스냅샷은 닫을 때 WAL을 본 파일에 합치지만, 남아 있으면 함께 복사 (SHM은 열 때 다시 만들어짐)
+	if err := copyFile(snapshot, *dbPath); err != nil {
+		return fmt.Errorf("복원 실패: %v", err)
+	}
+	if _, err := os.Stat(snapshot + "-wal"); err == nil {
+		if err := copyFile(snapshot+"-wal", *dbPath+"-wal"); err != nil {
+			return fmt.Errorf("복원 실패 (-wal): %v", err)
+		}
+	}
+
+	fmt.Printf("✅ 복원 완료: 세션 %d개\n", len(sessions))
+	return nil
+}
+
+// sqliteSidecars SQLite WAL 모드에서 DB 파일 옆에 생기는 파일
+var sqliteSidecars = []string{"-wal", "-shm"}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func rotateTokens(db *store.SQLiteStore) error {
+	tokens, err := db.ListAPITokens("")
+	if err != nil {
+		return err
+	}
+
+	authService := auth.NewService(db)
+	tw := newTable(os.Stdout)
+	fmt.Fprintln(tw, "NAME\tPROJECT\tOLD ID\tNEW TOKEN")
+	rotated := 0
+	for _, token := range tokens {
+		if token.RevokedAt != nil {
+			continue
+		}
+
+		_, raw, err := authService.IssueToken(token.Name, token.ProjectID, token.Scopes)
+		if err != nil {
+			return err
+		}
+		if err := authService.RevokeToken(token.ID); err != nil {
+			return err
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", token.Name, token.ProjectID, token.ID, raw)
+		rotated++
+	}
+	tw.Flush()
+
+	fmt.Printf("\n🔁 토큰 %d개 교체 완료. 새 토큰은 다시 확인할 수 없으니 지금 배포하세요\n", rotated)
+	return nil
+}
+
+func runCreateAPIKey(args []string) error {
+	fs := flag.NewFlagSet("create-api-key", flag.ContinueOnError)
+	userID := fs.String("user", "", "키가 대표할 사용자 ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("사용법: sandman-admin create-api-key [-user ID] <이름>")
+	}
+
+	scopes, err := auth.RoleScopes("admin", *userID, nil)
+	if err != nil {
+		return err
+	}
+	return withStore(func(db *store.SQLiteStore) error {
+		token, raw, err := auth.NewService(db).IssueToken(fs.Arg(0), "", scopes)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("🔑 관리자 API 키 발급: %s (ID: %s)\n%s\n", token.Name, token.ID, raw)
+		fmt.Println("키는 다시 확인할 수 없으니 지금 보관하세요")
+		return nil
+	})
+}
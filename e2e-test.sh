@@ -0,0 +1,377 @@
+#!/bin/bash
+
+# Sandman 종단 간(E2E) 테스트
+# 일회용 Docker-in-Docker 데몬 위에서 가짜 GPU 빌드(-tags fakegpu)의 오케스트레이터를 띄우고
+# 생성 → SSH 접속 → 만료 → 정리 흐름과 프로비저닝 경계 조건을 검증합니다.
+# 필요: docker, curl, jq, ssh (GPU와 nvidia 런타임은 필요 없음)
+
+# 색상 정의
+RED='\033[0;31m'
+GREEN='\033[0;32m'
+YELLOW='\033[1;33m'
+BLUE='\033[0;34m'
+PURPLE='\033[0;35m'
+CYAN='\033[0;36m'
+NC='\033[0m' # No Color
+
+# 설정 (환경 변수로 덮어쓰기 가능)
+E2E_ID="sandman-e2e-$$"
+API_PORT="${E2E_API_PORT:-18080}"
+SSH_PORT_START="${E2E_SSH_PORT_START:-12000}"
+SSH_PORT_END="${E2E_SSH_PORT_END:-12009}"
+BASE_IMAGE="${E2E_BASE_IMAGE:-ubuntu:24.04}"   # 워크스페이스 이미지 베이스 (CUDA 이미지 대신 가벼운 이미지)
+DIND_IMAGE="${E2E_DIND_IMAGE:-docker:24-dind}"
+GO_IMAGE="${E2E_GO_IMAGE:-golang:1.21}"
+FAKE_MIG="3g.20gb:2"                             # 용량 초과 테스트가 이 개수에 맞춰져 있음
+KEEP_ON_FAILURE="${E2E_KEEP:-0}"                 # 1이면 실패 시 환경을 남겨 조사 가능
+
+API_URL="http://127.0.0.1:$API_PORT"
+SCRIPT_DIR="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
+WORK_DIR="$(mktemp -d)"
+TEST_PASSED=0
+TEST_FAILED=0
+
+# 유틸리티 함수
+log() {
+    echo -e "${CYAN}[$(date '+%H:%M:%S')] $1${NC}"
+}
+
+success() {
+    echo -e "${GREEN}✅ $1${NC}"
+    ((TEST_PASSED++))
+}
+
+error() {
+    echo -e "${RED}❌ $1${NC}"
+    ((TEST_FAILED++))
+}
+
+warning() {
+    echo -e "${YELLOW}⚠️  $1${NC}"
+}
+
+info() {
+    echo -e "${BLUE}ℹ️  $1${NC}"
+}
+
+section() {
+    echo -e "\n${PURPLE}=== $1 ===${NC}"
+}
+
+# dind 안의 Docker 명령
+dind() {
+    docker exec "$E2E_ID-dind" docker "$@"
+}
+
+# HTTP 요청 (본문은 stdout, 상태 코드는 HTTP_CODE)
+request() {
+    local method="$1"
+    local url="$2"
+    local data="$3"
+
+    local response
+    if [ -n "$data" ]; then
+        response=$(curl -s -w "\n%{http_code}" -X "$method" -H "Content-Type: application/json" -d "$data" "$API_URL$url")
+    else
+        response=$(curl -s -w "\n%{http_code}" -X "$method" "$API_URL$url")
+    fi
+    HTTP_CODE=$(echo "$response" | tail -n1)
+    BODY=$(echo "$response" | head -n -1)
+}
+
+expect_status() {
+    local expected="$1"
+    local message="$2"
+
+    if [ "$HTTP_CODE" = "$expected" ]; then
+        success "$message"
+        return 0
+    fi
+    error "$message (기대 $expected, 실제 $HTTP_CODE: $BODY)"
+    return 1
+}
+
+# 조건이 참이 될 때까지 대기 (초 단위 제한)
+wait_until() {
+    local timeout="$1"
+    shift
+    local deadline=$((SECONDS + timeout))
+    while [ $SECONDS -lt $deadline ]; do
+        if "$@" > /dev/null 2>&1; then
+            return 0
+        fi
+        sleep 2
+    done
+    return 1
+}
+
+available_instances() {
+    curl -s "$API_URL/gpus/available" | jq '.available_instances | length'
+}
+
+# 환경 구성
+start_environment() {
+    section "E2E 환경 구성"
+
+    for cmd in docker curl jq ssh; do
+        if ! command -v $cmd > /dev/null; then
+            echo -e "${RED}❌ $cmd 명령이 필요합니다${NC}"
+            exit 1
+        fi
+    done
+
+    docker network create "$E2E_ID" > /dev/null
+    docker volume create "$E2E_ID-workspaces" > /dev/null
+
+    # 세션 컨테이너가 뜨는 일회용 데몬 (SSH 포트를 호스트에 노출)
+    log "Docker-in-Docker 시작 ($DIND_IMAGE)"
+    docker run -d --privileged --name "$E2E_ID-dind" \
+        --network "$E2E_ID" --network-alias dind \
+        -e DOCKER_TLS_CERTDIR= \
+        -v "$E2E_ID-workspaces:/srv/workspaces" \
+        -v /usr/share/zoneinfo:/usr/share/zoneinfo:ro \
+        -p "127.0.0.1:$SSH_PORT_START-$SSH_PORT_END:$SSH_PORT_START-$SSH_PORT_END" \
+        "$DIND_IMAGE" > /dev/null
+
+    if ! wait_until 60 dind info; then
+        echo -e "${RED}❌ Docker-in-Docker 데몬이 시작되지 않았습니다${NC}"
+        exit 1
+    fi
+    success "Docker-in-Docker 준비 완료"
+
+    cat > "$WORK_DIR/config.json" <<EOF
+{
+  "verify_gpu_attachment": false,
+  "default_mig_profile": "3g.20gb",
+  "provisioning_workers": 2,
+  "crash_loop_restarts": 0
+}
+EOF
+
+    # docker-compose 배포와 같이 소스를 /app/source에 마운트 (워크스페이스 이미지 빌드 컨텍스트)
+    log "가짜 GPU 오케스트레이터 빌드 및 시작 ($GO_IMAGE, -tags fakegpu)"
+    docker run -d --name "$E2E_ID-orchestrator" \
+        --network "$E2E_ID" \
+        -e DOCKER_HOST=tcp://dind:2375 \
+        -e SANDMAN_FAKE_MIG="$FAKE_MIG" \
+        -v "$SCRIPT_DIR:/app/source:ro" \
+        -v "$WORK_DIR/config.json:/e2e/config.json:ro" \
+        -v "$E2E_ID-workspaces:/srv/workspaces" \
+        -p "127.0.0.1:$API_PORT:8080" \
+        -w /app/source \
+        "$GO_IMAGE" \
+        sh -c "go build -buildvcs=false -tags fakegpu -o /tmp/orchestrator ./cmd/orchestrator && \
+               exec /tmp/orchestrator -db /tmp/sessions.db -config /e2e/config.json -deployment e2e \
+                    -ssh-port-start $SSH_PORT_START -ssh-port-end $SSH_PORT_END -access-log off" > /dev/null
+
+    if ! wait_until 300 curl -sf "$API_URL/readyz"; then
+        echo -e "${RED}❌ 오케스트레이터가 준비되지 않았습니다${NC}"
+        docker logs --tail 50 "$E2E_ID-orchestrator"
+        exit 1
+    fi
+    success "오케스트레이터 준비 완료"
+
+    # CUDA 이미지를 받지 않도록 stable 채널 베이스 이미지를 교체
+    request PUT "/admin/image-channels/stable" "{\"base_image\":\"$BASE_IMAGE\"}"
+    expect_status 200 "워크스페이스 베이스 이미지 설정: $BASE_IMAGE" || exit 1
+}
+
+stop_environment() {
+    section "E2E 환경 정리"
+
+    if [ $TEST_FAILED -gt 0 ]; then
+        info "오케스트레이터 로그 (마지막 100줄)"
+        docker logs --tail 100 "$E2E_ID-orchestrator" 2>&1
+        if [ "$KEEP_ON_FAILURE" = "1" ]; then
+            warning "E2E_KEEP=1: 환경을 남겨 둡니다 (docker ps --filter name=$E2E_ID)"
+            return
+        fi
+    fi
+
+    docker rm -f "$E2E_ID-orchestrator" "$E2E_ID-dind" > /dev/null 2>&1
+    docker volume rm "$E2E_ID-workspaces" > /dev/null 2>&1
+    docker network rm "$E2E_ID" > /dev/null 2>&1
+    rm -rf "$WORK_DIR"
+    info "정리 완료"
+}
+
+# 생성 → 접속 → 삭제
+test_create_connect_delete() {
+    section "생성 → SSH 접속 → 삭제"
+
+    local user_id="e2e-connect"
+    local initial=$(available_instances)
+
+    request POST "/sessions" "{\"user_id\":\"$user_id\",\"ttl_minutes\":30}"
+    expect_status 201 "세션 생성" || return
+
+    local session_id=$(echo "$BODY" | jq -r '.session_id')
+    local ssh_port=$(echo "$BODY" | jq -r '.ssh_port')
+    local key_file="$WORK_DIR/$user_id.key"
+    echo "$BODY" | jq -r '.ssh_private_key' > "$key_file"
+    chmod 600 "$key_file"
+
+    local ssh_opts="-o StrictHostKeyChecking=no -o UserKnownHostsFile=/dev/null -o ConnectTimeout=5 -o BatchMode=yes"
+    if wait_until 120 ssh $ssh_opts -i "$key_file" -p "$ssh_port" "$user_id@127.0.0.1" true; then
+        success "SSH 키 인증 접속"
+    else
+        error "SSH 접속 실패 (포트 $ssh_port)"
+    fi
+
+    if ssh $ssh_opts -i "$key_file" -p "$ssh_port" "$user_id@127.0.0.1" "echo e2e > /workspace/marker" 2>/dev/null && \
+       docker exec "$E2E_ID-dind" cat "/srv/workspaces/$user_id/marker" 2>/dev/null | grep -q e2e; then
+        success "워크스페이스 바인드 마운트 확인"
+    else
+        error "워크스페이스에 쓴 파일이 호스트 경로에 없습니다"
+    fi
+
+    request GET "/sessions/$session_id/alive"
+    if [ "$(echo "$BODY" | jq -r '.running')" = "true" ]; then
+        success "세션 상태: running"
+    else
+        error "세션 상태가 running이 아닙니다: $BODY"
+    fi
+
+    request DELETE "/sessions/$session_id"
+    expect_status 200 "세션 삭제"
+
+    request GET "/sessions/$session_id"
+    expect_status 404 "삭제된 세션 조회 시 404"
+
+    if [ -z "$(dind ps -aq --filter "label=sandman.user=$user_id")" ]; then
+        success "컨테이너 제거 확인"
+    else
+        error "삭제 후에도 컨테이너가 남아 있습니다"
+    fi
+
+    if [ "$(available_instances)" = "$initial" ]; then
+        success "MIG 인스턴스 반환 확인 ($initial개 사용 가능)"
+    else
+        error "MIG 인스턴스가 반환되지 않았습니다 ($(available_instances)/$initial)"
+    fi
+}
+
+# 만료 → 자동 정리
+test_expiry_cleanup() {
+    section "만료 → 자동 정리"
+
+    local user_id="e2e-expire"
+    local initial=$(available_instances)
+
+    request POST "/sessions" "{\"user_id\":\"$user_id\",\"ttl_minutes\":1}"
+    expect_status 201 "TTL 1분 세션 생성" || return
+    local session_id=$(echo "$BODY" | jq -r '.session_id')
+
+    # TTL 감시자는 1분 간격으로 실행
+    info "만료 정리 대기 중... (최대 3분)"
+    if wait_until 180 sh -c "[ \"\$(curl -s -o /dev/null -w '%{http_code}' $API_URL/sessions/$session_id)\" = 404 ]"; then
+        success "만료된 세션 자동 삭제"
+    else
+        error "만료된 세션이 정리되지 않았습니다"
+        return
+    fi
+
+    if [ -z "$(dind ps -aq --filter "label=sandman.user=$user_id")" ]; then
+        success "만료된 세션 컨테이너 제거 확인"
+    else
+        error "만료 후에도 컨테이너가 남아 있습니다"
+    fi
+
+    if [ "$(available_instances)" = "$initial" ]; then
+        success "만료 후 MIG 인스턴스 반환 확인"
+    else
+        error "만료 후 MIG 인스턴스가 반환되지 않았습니다"
+    fi
+
+}
+
+# 프로비저닝 경계 조건
+test_provisioning_edges() {
+    section "프로비저닝 경계 조건"
+
+    request POST "/sessions" '{"user_id":"e2e-edge-a"}'
+    expect_status 201 "첫 번째 세션 생성" || return
+    local first=$(echo "$BODY" | jq -r '.session_id')
+
+    request POST "/sessions" '{"user_id":"e2e-edge-a"}'
+    expect_status 409 "같은 사용자 중복 생성 거부"
+
+    request POST "/sessions" '{"user_id":"e2e-edge-b"}'
+    expect_status 201 "두 번째 세션 생성 (마지막 인스턴스)"
+
+    request POST "/sessions" '{"user_id":"e2e-edge-c"}'
+    expect_status 503 "인스턴스 소진 시 503"
+
+    if [ -z "$(dind ps -aq --filter "label=sandman.user=e2e-edge-c")" ]; then
+        success "실패한 생성의 컨테이너가 남지 않음"
+    else
+        error "실패한 생성 후 컨테이너가 남아 있습니다"
+    fi
+
+    request POST "/sessions" '{"user_id":"e2e-edge-c","mig_profile":"7g.80gb"}'
+    expect_status 503 "없는 프로파일 인스턴스 요청 거부"
+
+    request POST "/sessions" '{"user_id":"../etc"}'
+    expect_status 400 "잘못된 사용자 ID 거부"
+
+    request DELETE "/sessions/$first"
+    expect_status 200 "첫 번째 세션 삭제"
+
+    request POST "/sessions" '{"user_id":"e2e-edge-c"}'
+    expect_status 201 "반환된 인스턴스로 새 세션 생성"
+
+    request DELETE "/sessions"
+    expect_status 200 "전체 세션 삭제"
+
+    if [ "$(available_instances)" = "2" ]; then
+        success "모든 MIG 인스턴스 반환 확인"
+    else
+        error "전체 삭제 후 사용 가능한 인스턴스: $(available_instances)/2"
+    fi
+
+    local leftover=$(dind ps -aq --filter "label=sandman.deployment=e2e")
+    if [ -z "$leftover" ]; then
+        success "남은 세션 컨테이너 없음"
+    else
+        error "남은 세션 컨테이너: $leftover"
+    fi
+}
+
+# 테스트 결과 출력
+print_results() {
+    section "테스트 결과"
+
+    local total_tests=$((TEST_PASSED + TEST_FAILED))
+    echo -e "${BLUE}총 테스트 수: $total_tests${NC}"
+    echo -e "${GREEN}성공: $TEST_PASSED${NC}"
+    echo -e "${RED}실패: $TEST_FAILED${NC}"
+
+    if [ $TEST_FAILED -eq 0 ]; then
+        echo -e "\n${GREEN}🎉 모든 E2E 테스트가 성공했습니다!${NC}"
+        exit 0
+    else
+        echo -e "\n${RED}💥 $TEST_FAILED개의 E2E 테스트가 실패했습니다.${NC}"
+        exit 1
+    fi
+}
+
+# 메인 함수
+main() {
+    echo -e "${PURPLE}"
+    echo "=================================="
+    echo "   Sandman E2E 테스트 (fakegpu)"
+    echo "=================================="
+    echo -e "${NC}"
+
+    trap stop_environment EXIT
+
+    start_environment
+    test_create_connect_delete
+    test_provisioning_edges
+    test_expiry_cleanup
+
+    print_results
+}
+
+# 스크립트 실행
+main "$@"
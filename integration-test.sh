@@ -14,6 +14,7 @@ API_URL="http://localhost:8080"
 TEST_PASSED=0
 TEST_FAILED=0
 CREATED_SESSIONS=()
+SSH_TEST_PASSWORD=""
 
 # 유틸리티 함수
 log() {
@@ -461,24 +462,11 @@ test_ssh_connection() {
     
     info "SSH 개인키 임시 파일 생성: $temp_key_file"
     
-    # 세션 상세 정보 조회하여 SSH 비밀번호 획득 (fallback용)
-    local session_info
-    session_info=$(http_get "/sessions/$session_id")
-    if [ $? -ne 0 ]; then
-        error "세션 정보 조회 실패: $session_info"
-        rm -f "$temp_key_file"
-        return
-    fi
-    
-    local ssh_password=$(echo "$session_info" | jq -r '.metadata.ssh_password' 2>/dev/null)
+    # SSH 비밀번호는 생성 응답에만 포함됨 (세션 조회 응답에서는 제외)
+    local ssh_password=$(echo "$result" | jq -r '.ssh_password' 2>/dev/null)
+    SSH_TEST_PASSWORD="$ssh_password"
     if [ "$ssh_password" = "null" ] || [ "$ssh_password" = "" ]; then
-        warning "SSH 비밀번호를 찾을 수 없습니다. 컨테이너가 아직 완전히 시작되지 않았을 수 있습니다."
-        info "컨테이너 시작 대기 중... (10초)"
-        sleep 10
-        
-        # 다시 시도
-        session_info=$(http_get "/sessions/$session_id")
-        ssh_password=$(echo "$session_info" | jq -r '.metadata.ssh_password' 2>/dev/null)
+        warning "생성 응답에 SSH 비밀번호가 없습니다"
     fi
     
     # SSH 접속 테스트 (포트 접근 가능성만 확인)
@@ -547,7 +535,7 @@ test_ssh_functionality() {
     fi
     
     local user_id=$(echo "$session_info" | jq -r '.user_id' 2>/dev/null)
-    local ssh_password=$(echo "$session_info" | jq -r '.metadata.ssh_password' 2>/dev/null)
+    local ssh_password="$SSH_TEST_PASSWORD"  # test_ssh_connection에서 생성 응답으로 받은 값
     local ssh_port=$(echo "$session_info" | jq -r '.metadata.ssh_port' 2>/dev/null)
     local gpu_uuid=$(echo "$session_info" | jq -r '.gpu_uuid' 2>/dev/null)
     
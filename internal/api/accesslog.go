@@ -0,0 +1,145 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+)
+
+const (
+	requestIDHeader   = "X-Request-ID"
+	requestIDKey      = "request_id"
+	requestUserKey    = "request_user"
+	unmatchedRoute    = "unmatched"
+	maxRequestIDBytes = 128
+)
+
+// probeRoutes 헬스 체크/메트릭 수집/상태 폴링처럼 짧은 간격으로 반복 호출되는 라우트
+var probeRoutes = map[string]bool{
+	"/healthz":            true,
+	"/readyz":             true,
+	"/metrics":            true,
+	"/sessions/:id/alive": true,
+}
+
+var (
+	httpRequestsTotal   = metrics.NewCounter("sandman_http_requests_total", "API 요청 수", "method", "route", "status")
+	httpRequestDuration = metrics.NewHistogram("sandman_http_request_duration_seconds", "API 요청 처리 시간", nil, "method", "route")
+)
+
+// AccessEntry 요청 하나에 대한 구조화된 접근 로그 항목
+type AccessEntry struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"request_id"`
+	Method    string    `json:"method"`
+	Route     string    `json:"route"`
+	Path      string    `json:"path"`
+	Status    int       `json:"status"`
+	LatencyMS float64   `json:"latency_ms"`
+	Bytes     int       `json:"bytes"`
+	ClientIP  string    `json:"client_ip"`
+	User      string    `json:"user,omitempty"`
+	TokenID   string    `json:"token_id,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// accessLogger 접근 로그를 한 줄에 하나의 JSON으로 기록 (writer가 nil이면 메트릭만 집계)
+type accessLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *accessLogger) write(entry *AccessEntry) {
+	if l.w == nil {
+		return
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		log.Printf("⚠️ 접근 로그 직렬화 실패: %v", err)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.w.Write(append(line, '\n'))
+}
+
+// SetAccessLog 접근 로그 출력 대상 지정 (nil이면 로그는 끄고 지연 시간 메트릭만 유지)
+func (s *Server) SetAccessLog(w io.Writer) {
+	s.accessLog.w = w
+}
+
+// accessLogMiddleware 요청 ID를 부여하고, 완료된 요청을 라우트 템플릿 기준으로 기록/집계
+func (s *Server) accessLogMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		requestID := c.GetHeader(requestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDBytes {
+			requestID = uuid.New().String()
+		}
+		c.Set(requestIDKey, requestID)
+		c.Header(requestIDHeader, requestID)
+
+		c.Next()
+
+		// 세션 ID 같은 경로 값이 레이블로 퍼지지 않도록 라우트 템플릿 사용
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := c.Writer.Status()
+		latency := time.Since(start)
+
+		httpRequestsTotal.Inc(c.Request.Method, route, strconv.Itoa(status))
+		httpRequestDuration.Observe(latency.Seconds(), c.Request.Method, route)
+
+		entry := &AccessEntry{
+			Time:      start.UTC(),
+			RequestID: requestID,
+			Method:    c.Request.Method,
+			Route:     route,
+			Path:      c.Request.URL.Path,
+			Status:    status,
+			LatencyMS: float64(latency.Microseconds()) / 1000,
+			Bytes:     c.Writer.Size(),
+			ClientIP:  c.ClientIP(),
+			User:      c.GetString(requestUserKey),
+		}
+		if token := currentToken(c); token != nil {
+			entry.TokenID = token.ID
+		}
+		// 일회용 자격 증명 링크 토큰은 로그에 남기지 않음
+		if route == "/credentials/:token" {
+			entry.Path = route
+		}
+		if len(c.Errors) > 0 {
+			entry.Error = c.Errors.String()
+		}
+		if entry.Bytes < 0 {
+			entry.Bytes = 0
+		}
+
+		// 성공한 프로브 요청은 log_sampling.probe_access_logs가 꺼져 있으면 메트릭만 남김 (debug 레벨에서는 기록)
+		if probeRoutes[route] && status < 400 && !logging.DebugEnabled() && !s.config.Current().LogSampling.ProbeAccessLogs {
+			return
+		}
+		s.accessLog.write(entry)
+	}
+}
+
+// setRequestUser 접근 로그에 남길 요청 사용자 지정
+func setRequestUser(c *gin.Context, userID string) {
+	if userID != "" {
+		c.Set(requestUserKey, userID)
+	}
+}
@@ -0,0 +1,175 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/capacity"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+)
+
+func (s *Server) getConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, s.config.Current())
+}
+
+func (s *Server) reloadConfig(c *gin.Context) {
+	changes, err := s.config.Reload()
+	if err != nil {
+		// 실행 중인 세션과 충돌하는 변경은 409, 그 외 잘못된 설정은 400
+		status := http.StatusBadRequest
+		if errors.Is(err, errs.ErrConflict) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "설정이 리로드되었습니다",
+		"changes": changes,
+		"config":  s.config.Current(),
+	})
+}
+
+func (s *Server) listLocks(c *gin.Context) {
+	leases, err := s.sessionService.ListLocks()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "락 목록 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"locks": leases,
+		"count": len(leases),
+	})
+}
+
+// listMIGReservations 세션이 끝난 사용자에게 소프트 예약된 이 노드의 MIG 인스턴스
+func (s *Server) listMIGReservations(c *gin.Context) {
+	reservations, err := s.sessionService.ListMIGReservations()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "MIG 인스턴스 예약 목록 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"reservations": reservations,
+		"count":        len(reservations),
+	})
+}
+
+// getRoutes 저장소 기준 SSHPiper 라우트 테이블과 설정 파일의 차이 (라우팅 불일치 확인용)
+func (s *Server) getRoutes(c *gin.Context) {
+	snapshot, err := s.sessionService.RouteSnapshot()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "라우트 테이블 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// getConsistency 마지막 일관성 검사 보고서 (refresh=true면 지금 검사)
+func (s *Server) getConsistency(c *gin.Context) {
+	report := s.sessionService.LastConsistencyReport()
+	if report == nil || c.Query("refresh") == "true" {
+		var err error
+		if report, err = s.sessionService.CheckConsistency(); err != nil {
+			c.JSON(errorStatus(err), gin.H{
+				"error": "일관성 검사 실패: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// getDockerResources 세션 네트워크 상태와 최근에 삭제된 이 배포의 Docker 리소스
+func (s *Server) getDockerResources(c *gin.Context) {
+	c.JSON(http.StatusOK, s.sessionService.DockerResourceStatus())
+}
+
+// reconcileDockerResources 이벤트를 기다리지 않고 Docker 리소스를 바로 확인하고 복구
+func (s *Server) reconcileDockerResources(c *gin.Context) {
+	if err := s.sessionService.ReconcileDockerResources(); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error": "Docker 리소스 복구 실패: " + err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, s.sessionService.DockerResourceStatus())
+}
+
+// getProvisioning 세션 생성 워커 풀과 대기열 현황 (설정은 /admin/config/reload로 조정)
+func (s *Server) getProvisioning(c *gin.Context) {
+	c.JSON(http.StatusOK, s.sessionService.ProvisioningStatus())
+}
+
+// whatIf 가상의 노드 추가/MIG 재분할 시 프로파일별 예상 용량과 부족분 (실제 상태는 바꾸지 않음)
+func (s *Server) whatIf(c *gin.Context) {
+	var scenario capacity.Scenario
+	if err := c.ShouldBindJSON(&scenario); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	result, err := s.sessionService.WhatIf(scenario)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "용량 시뮬레이션 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// createMIGInstance GPU의 남는 슬라이스에 MIG 인스턴스를 만듦 (nvidia-smi mig -cgi)
+func (s *Server) createMIGInstance(c *gin.Context) {
+	var req struct {
+		GPUIndex int    `json:"gpu_index"`
+		Profile  string `json:"profile" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	instance, err := s.sessionService.CreateMIGInstance(req.GPUIndex, req.Profile)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "MIG 인스턴스 생성 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, instance)
+}
+
+// deleteMIGInstance 사용 중이 아닌 MIG 인스턴스를 GPU에서 지움 (UUID 또는 이름)
+func (s *Server) deleteMIGInstance(c *gin.Context) {
+	if err := s.sessionService.DeleteMIGInstance(c.Param("id"), "admin"); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "MIG 인스턴스 삭제 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "MIG 인스턴스가 삭제되었습니다",
+	})
+}
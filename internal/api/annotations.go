@@ -0,0 +1,38 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// patchAnnotations 예약 포털, 티켓, 과금 시스템 등이 세션에 네임스페이스별 JSON을 붙임
+// 본문: {"<네임스페이스>": <JSON 값 또는 삭제할 경우 null>, ...}
+func (s *Server) patchAnnotations(c *gin.Context) {
+	sessionID := c.Param("id")
+	if !s.sessionAccessible(c, sessionID) {
+		return
+	}
+
+	var patch map[string]json.RawMessage
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	annotations, err := s.sessionService.PatchAnnotations(sessionID, patch)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "주석 변경 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session_id":  sessionID,
+		"annotations": annotations,
+	})
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/auth"
+)
+
+type createAPIKeyRequest struct {
+	Name      string   `json:"name" binding:"required"`
+	Role      string   `json:"role" binding:"required"` // admin, user
+	UserID    string   `json:"user_id"`
+	ProjectID string   `json:"project_id"`
+	Scopes    []string `json:"scopes"` // 역할 범위에 더할 범위 (credentials:read 등)
+}
+
+// createAPIKey 관리자/사용자 키 발급 (평문 키는 이 응답에서만 확인 가능)
+func (s *Server) createAPIKey(c *gin.Context) {
+	var req createAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	scopes, err := auth.RoleScopes(req.Role, req.UserID, req.Scopes)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if scopes, err = auth.CanGrant(currentToken(c), scopes); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	if req.ProjectID != "" {
+		if _, err := s.sessionService.GetProject(req.ProjectID); err != nil {
+			c.JSON(errorStatus(err), gin.H{
+				"error": "프로젝트를 찾을 수 없습니다: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	token, raw, err := s.authService.IssueToken(req.Name, req.ProjectID, scopes)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"token":    raw,
+		"metadata": token,
+	})
+}
+
+func (s *Server) listAPIKeys(c *gin.Context) {
+	tokens, err := s.authService.ListTokens("")
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "API 키 목록 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"keys":  tokens,
+		"count": len(tokens),
+	})
+}
+
+func (s *Server) revokeAPIKey(c *gin.Context) {
+	if err := s.authService.RevokeToken(c.Param("id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "API 키가 폐기되었습니다",
+	})
+}
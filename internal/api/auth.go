@@ -0,0 +1,221 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/auth"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+const tokenContextKey = "api_token"
+
+// publicRoutes 인증을 요구하는 배포에서도 키 없이 호출할 수 있는 경로 (일회용 자격 증명 링크와 노드 가입 토큰은 그 자체가 비밀)
+var publicRoutes = map[string]bool{
+	"/":                     true,
+	"/healthz":              true,
+	"/readyz":               true,
+	"/metrics":              true,
+	"/status":               true,
+	"/ui/*filepath":         true,
+	"/credentials/:token":   true,
+	"/manifests/public-key": true,
+	"/nodes/ca":             true,
+	"/nodes/enroll":         true,
+}
+
+// adminRoutes /admin/* 외에 관리자 키가 필요한 경로 ("메서드 경로")
+var adminRoutes = map[string]bool{
+	"DELETE /sessions":                  true,
+	"POST /orgs":                        true,
+	"GET /orgs":                         true,
+	"GET /orgs/:id/usage":               true,
+	"GET /orgs/:id/projects":            true,
+	"DELETE /orgs/:id":                  true,
+	"POST /orgs/:id/projects":           true,
+	"DELETE /projects/:id":              true,
+	"POST /teams":                       true,
+	"DELETE /teams/:id":                 true,
+	"PUT /teams/:id/members/:userId":    true,
+	"DELETE /teams/:id/members/:userId": true,
+	"GET /audit":                        true,
+}
+
+// handlerCheckedRoutes 세션 소유자가 아니어도 범위(credentials:read, credentials:rotate)로 허용할 수 있어 핸들러가 직접 확인하는 경로
+var handlerCheckedRoutes = map[string]bool{
+	"GET /sessions/:id/credentials":         true,
+	"POST /sessions/:id/credentials/rotate": true,
+	"GET /sessions/:id/events":              true,
+}
+
+// SetRequireAuth 켜면 공개 경로를 뺀 모든 요청에 API 키를 요구하고 키 범위(admin, user:<id>)로 접근을 제한
+func (s *Server) SetRequireAuth(required bool) {
+	s.requireAuth = required
+}
+
+// authMiddleware Authorization 헤더의 Bearer 토큰을 검증해 컨텍스트에 저장
+// 인증을 요구하지 않는 배포에서는 토큰이 없는 요청을 그대로 통과시키고, 잘못된 토큰은 항상 401로 거부
+func (s *Server) authMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		// 브라우저 WebSocket은 헤더를 지정할 수 없어 access_token 쿼리로 받음 (접근 로그에는 쿼리를 남기지 않음)
+		if header == "" && strings.EqualFold(c.GetHeader("Upgrade"), "websocket") && c.Query("access_token") != "" {
+			header = "Bearer " + c.Query("access_token")
+		}
+		if header == "" {
+			if s.requireAuth && !publicRoutes[c.FullPath()] && c.FullPath() != "" {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"error": "API 키가 필요합니다 (Authorization: Bearer <키>)",
+				})
+				return
+			}
+			c.Next()
+			return
+		}
+
+		raw := strings.TrimSpace(strings.TrimPrefix(header, "Bearer "))
+		token, err := s.authService.Authenticate(raw)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+
+		c.Set(tokenContextKey, token)
+		if s.requireAuth {
+			if err := s.authorizeRoute(c, token); err != nil {
+				c.AbortWithStatusJSON(errorStatus(err), gin.H{
+					"error": err.Error(),
+				})
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// authorizeRoute 관리 경로는 관리자 키만, /sessions/:id 아래 경로는 세션 소유자나 같은 프로젝트의 키만 허용
+// (/users/:id 아래 경로는 그 사용자의 키만)
+func (s *Server) authorizeRoute(c *gin.Context, token *store.APIToken) error {
+	if auth.IsAdmin(token) {
+		return nil
+	}
+	route := c.Request.Method + " " + c.FullPath()
+	if adminRoutes[route] || strings.HasPrefix(c.FullPath(), "/admin/") {
+		return errs.New(errs.ErrForbidden, "관리자 키가 필요합니다")
+	}
+	if strings.HasPrefix(c.FullPath(), "/users/:id") {
+		return auth.AuthorizeUser(token, c.Param("id"))
+	}
+	if handlerCheckedRoutes[route] || !strings.HasPrefix(c.FullPath(), "/sessions/:id") {
+		return nil
+	}
+
+	session, err := s.sessionService.GetSession(c.Param("id"))
+	if err != nil {
+		// 없는 세션은 핸들러가 404로 응답
+		return nil
+	}
+	return auth.AuthorizeSession(token, session)
+}
+
+// currentToken 요청에 사용된 토큰 (없으면 nil)
+func currentToken(c *gin.Context) *store.APIToken {
+	if v, ok := c.Get(tokenContextKey); ok {
+		if token, ok := v.(*store.APIToken); ok {
+			return token
+		}
+	}
+	return nil
+}
+
+// allowProject 프로젝트에는 관리자 키나 그 프로젝트 범위의 키만 접근할 수 있는지 확인
+// 프로젝트 소속을 기록하지 않으므로 프로젝트 범위가 없는 사용자 키는 어떤 프로젝트에도 접근할 수 없음
+func allowProject(c *gin.Context, projectID string) bool {
+	token := currentToken(c)
+	if token == nil || auth.IsAdmin(token) || token.ProjectID == projectID {
+		return true
+	}
+
+	message := "토큰이 프로젝트 " + token.ProjectID + " 범위로 제한되어 있습니다"
+	if token.ProjectID == "" {
+		message = "프로젝트 " + projectID + "에는 관리자 키나 그 프로젝트 범위의 키가 필요합니다"
+	}
+	c.JSON(http.StatusForbidden, gin.H{
+		"error": message,
+	})
+	return false
+}
+
+// allowSession 프로젝트 범위가 없는 키는 세션 프로젝트와 관계없이 허용하되 사용자 키는 자기 세션만 (종료된 세션 기록 포함)
+// 프로젝트 범위 키는 allowProject와 같음
+func allowSession(c *gin.Context, userID, projectID string) bool {
+	token := currentToken(c)
+	if token == nil || token.ProjectID != "" || auth.IsAdmin(token) {
+		return allowProject(c, projectID)
+	}
+	if err := auth.AuthorizeUser(token, userID); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+// allowOrg 조직 조회는 관리자 키만 (조직의 모든 프로젝트와 사용량이 보임)
+func allowOrg(c *gin.Context) bool {
+	token := currentToken(c)
+	if token == nil || auth.IsAdmin(token) {
+		return true
+	}
+
+	c.JSON(http.StatusForbidden, gin.H{
+		"error": "조직 조회에는 관리자 키가 필요합니다",
+	})
+	return false
+}
+
+// requestActor 감사 로그와 세션 이벤트에 남길 요청자 (인증을 요구하지 않는 배포에서 토큰 없이 오면 "api")
+func requestActor(c *gin.Context) string {
+	if token := currentToken(c); token != nil {
+		return "token:" + token.Name
+	}
+	return "api"
+}
+
+// scopedUser ?user_id= 필터 (관리자가 아닌 사용자 키는 자기 사용자 ID로 고정, 다른 사용자를 지정하면 403)
+func scopedUser(c *gin.Context) (string, bool) {
+	userID := c.Query("user_id")
+	token := currentToken(c)
+	if auth.IsAdmin(token) {
+		return userID, true
+	}
+	tokenUser := auth.TokenUser(token)
+	if tokenUser == "" {
+		return userID, true
+	}
+	if userID != "" && userID != tokenUser {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "사용자 " + tokenUser + "의 키로 사용자 " + userID + "의 기록을 조회할 수 없습니다",
+		})
+		return "", false
+	}
+	return tokenUser, true
+}
+
+// requireTeamLead /teams/:id 아래 팀 리드 전용 경로 공통 검사 (역할 판단은 auth의 RBAC에서)
+func (s *Server) requireTeamLead() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := s.authService.AuthorizeTeamLead(currentToken(c), c.Param("id")); err != nil {
+			c.AbortWithStatusJSON(errorStatus(err), gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.Next()
+	}
+}
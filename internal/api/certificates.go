@@ -0,0 +1,97 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type issueCertificateRequest struct {
+	PublicKey string `json:"public_key" binding:"required"`
+}
+
+func (s *Server) getSSHCA(c *gin.Context) {
+	publicKey := s.sessionService.SSHCAPublicKey()
+	if publicKey == "" {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "SSH CA가 설정되지 않았습니다",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"public_key": publicKey,
+	})
+}
+
+func (s *Server) issueCertificate(c *gin.Context) {
+	sessionID := c.Param("id")
+	if !s.sessionAccessible(c, sessionID) {
+		return
+	}
+
+	var req issueCertificateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	cert, err := s.sessionService.IssueCertificate(sessionID, req.PublicKey)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "인증서 발급 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, cert)
+}
+
+func (s *Server) listCertificates(c *gin.Context) {
+	sessionID := c.Param("id")
+	if !s.sessionAccessible(c, sessionID) {
+		return
+	}
+
+	certs, err := s.sessionService.ListCertificates(sessionID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "인증서 목록 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"certificates": certs,
+		"count":        len(certs),
+	})
+}
+
+func (s *Server) revokeCertificate(c *gin.Context) {
+	sessionID := c.Param("id")
+	if !s.sessionAccessible(c, sessionID) {
+		return
+	}
+
+	serial, err := strconv.ParseUint(c.Param("serial"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 인증서 일련번호: " + c.Param("serial"),
+		})
+		return
+	}
+
+	if err := s.sessionService.RevokeCertificate(sessionID, serial); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "인증서 폐기 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "인증서가 폐기되었습니다",
+	})
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type addCollaboratorRequest struct {
+	UserID    string `json:"user_id" binding:"required"`
+	PublicKey string `json:"public_key" binding:"required"`
+}
+
+// sessionAccessible 세션이 존재하고 현재 토큰의 프로젝트 범위 안에 있는지 확인
+func (s *Server) sessionAccessible(c *gin.Context, sessionID string) bool {
+	session, err := s.sessionService.GetSession(sessionID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "세션을 찾을 수 없습니다: " + err.Error(),
+		})
+		return false
+	}
+	setRequestUser(c, session.UserID)
+	return allowSession(c, session.UserID, session.ProjectID)
+}
+
+func (s *Server) addCollaborator(c *gin.Context) {
+	sessionID := c.Param("id")
+	if !s.sessionAccessible(c, sessionID) {
+		return
+	}
+
+	var req addCollaboratorRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	collab, err := s.sessionService.AddCollaborator(sessionID, req.UserID, req.PublicKey)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "협업자 추가 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, collab)
+}
+
+func (s *Server) listCollaborators(c *gin.Context) {
+	sessionID := c.Param("id")
+	if !s.sessionAccessible(c, sessionID) {
+		return
+	}
+
+	collabs, err := s.sessionService.ListCollaborators(sessionID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "협업자 목록 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"collaborators": collabs,
+		"count":         len(collabs),
+	})
+}
+
+func (s *Server) removeCollaborator(c *gin.Context) {
+	sessionID := c.Param("id")
+	if !s.sessionAccessible(c, sessionID) {
+		return
+	}
+
+	if err := s.sessionService.RemoveCollaborator(sessionID, c.Param("userId")); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "협업자 제거 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "협업자 권한이 회수되었습니다",
+	})
+}
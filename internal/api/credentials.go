@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/auth"
+)
+
+// getSessionCredentials 세션 소유자(user:<id> 범위) 또는 credentials:read 범위 토큰만 비밀번호 조회 가능
+func (s *Server) getSessionCredentials(c *gin.Context) {
+	session, err := s.sessionService.GetSession(c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "세션을 찾을 수 없습니다: " + err.Error(),
+		})
+		return
+	}
+	setRequestUser(c, session.UserID)
+	if !allowSession(c, session.UserID, session.ProjectID) {
+		return
+	}
+
+	token := currentToken(c)
+	if !auth.HasScope(token, auth.UserScope(session.UserID)) && !auth.HasScope(token, auth.ScopeCredentialsRead) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "세션 소유자 또는 " + auth.ScopeCredentialsRead + " 범위 토큰만 자격 증명을 조회할 수 있습니다",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.sessionService.GetSessionCredentials(session, "token:"+token.Name))
+}
+
+// rotateSessionCredentials 세션 SSH 키 교체 (세션 소유자 또는 credentials:rotate 범위 토큰)
+func (s *Server) rotateSessionCredentials(c *gin.Context) {
+	session, err := s.sessionService.GetSession(c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "세션을 찾을 수 없습니다: " + err.Error(),
+		})
+		return
+	}
+	setRequestUser(c, session.UserID)
+	if !allowSession(c, session.UserID, session.ProjectID) {
+		return
+	}
+
+	token := currentToken(c)
+	if !auth.HasScope(token, auth.UserScope(session.UserID)) && !auth.HasScope(token, auth.ScopeCredentialsRotate) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "세션 소유자 또는 " + auth.ScopeCredentialsRotate + " 범위 토큰만 키를 교체할 수 있습니다",
+		})
+		return
+	}
+
+	rotated, err := s.sessionService.RotateCredentials(session.ID, "token:"+token.Name)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "SSH 키 교체 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, rotated)
+}
+
+// redeemCredentialLink 세션 생성 시 발급된 일회용 링크로 자격 증명(개인키 포함) 조회
+func (s *Server) redeemCredentialLink(c *gin.Context) {
+	credentials, err := s.sessionService.RedeemCredentialLink(c.Param("token"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	setRequestUser(c, credentials.SSHUser)
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, credentials)
+}
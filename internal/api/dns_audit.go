@@ -0,0 +1,50 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// listDNSQueries 정책 위반 조사용 DNS 질의 조회 (session_id, user_id, name(하위 도메인 포함), since/until(RFC3339), limit)
+func (s *Server) listDNSQueries(c *gin.Context) {
+	limit, err := queryInt(c, "limit", 100)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	filter := store.DNSQueryFilter{
+		SessionID: c.Query("session_id"),
+		UserID:    c.Query("user_id"),
+		Name:      c.Query("name"),
+		Limit:     limit,
+	}
+	for name, target := range map[string]*time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": name + "는 RFC3339 시각이어야 합니다: " + value,
+			})
+			return
+		}
+		*target = t
+	}
+
+	queries, err := s.sessionService.ListDNSQueries(filter)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "DNS 질의 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, queries)
+}
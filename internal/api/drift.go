@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getSessionDrift 세션 컨테이너가 템플릿 이미지에서 바뀐 패키지와 파일
+func (s *Server) getSessionDrift(c *gin.Context) {
+	sessionID := c.Param("id")
+	if !s.sessionAccessible(c, sessionID) {
+		return
+	}
+
+	report, err := s.sessionService.SessionDrift(sessionID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "환경 변경 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// resetSessionDrift 컨테이너를 템플릿 이미지로 다시 만들어 환경 변경을 되돌림 (워크스페이스 유지)
+func (s *Server) resetSessionDrift(c *gin.Context) {
+	sessionID := c.Param("id")
+	if !s.sessionAccessible(c, sessionID) {
+		return
+	}
+
+	session, err := s.sessionService.ResetToBaseline(sessionID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "환경 초기화 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.sessionService.View(session))
+}
@@ -0,0 +1,27 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+// errorStatus 에러 종류에 맞는 HTTP 상태 코드 (분류되지 않은 에러는 500)
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, errs.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, errs.ErrAlreadyExists), errors.Is(err, errs.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, errs.ErrNoCapacity), errors.Is(err, session.ErrShuttingDown):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, errs.ErrInvalid):
+		return http.StatusBadRequest
+	case errors.Is(err, errs.ErrForbidden):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
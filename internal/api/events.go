@@ -0,0 +1,83 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/auth"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// listSessionEvents 세션 생애 주기 이벤트 (끝난 세션은 세션 소유자 키나 관리자 키로만 조회)
+func (s *Server) listSessionEvents(c *gin.Context) {
+	sessionID := c.Param("id")
+
+	events, err := s.sessionService.ListSessionEvents(sessionID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "세션 이벤트 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	token := currentToken(c)
+	if session, err := s.sessionService.GetSession(sessionID); err == nil {
+		if err := auth.AuthorizeSession(token, session); err != nil {
+			c.JSON(errorStatus(err), gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		setRequestUser(c, session.UserID)
+	} else if len(events) > 0 && token != nil && !auth.IsAdmin(token) && auth.TokenUser(token) != events[0].UserID {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": "끝난 세션의 이벤트는 세션 소유자나 관리자만 조회할 수 있습니다",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"session_id": sessionID,
+		"events":     events,
+		"count":      len(events),
+	})
+}
+
+// getAudit 감사 기록 (?actor=&action=session.&target=<세션 ID>&since=RFC3339&limit=100)
+func (s *Server) getAudit(c *gin.Context) {
+	limit, err := queryInt(c, "limit", 0)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter := store.AuditFilter{
+		Actor:  c.Query("actor"),
+		Action: c.Query("action"),
+		Target: c.Query("target"),
+		Limit:  limit,
+	}
+	if value := c.Query("since"); value != "" {
+		since, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "since는 RFC3339 시각이어야 합니다: " + value,
+			})
+			return
+		}
+		filter.Since = since
+	}
+
+	entries, err := s.sessionService.QueryAudit(filter)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "감사 기록 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"entries": entries,
+		"count":   len(entries),
+	})
+}
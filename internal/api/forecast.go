@@ -0,0 +1,51 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultForecastLookbackDays = 14
+	defaultForecastHorizonHours = 24
+)
+
+// getForecast 프로파일별 수요 예측과 예상 소진 시각 (?lookback_days=14&horizon_hours=24&profile=)
+func (s *Server) getForecast(c *gin.Context) {
+	lookbackDays, err := queryInt(c, "lookback_days", defaultForecastLookbackDays)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	horizonHours, err := queryInt(c, "horizon_hours", defaultForecastHorizonHours)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	forecast, err := s.sessionService.Forecast(lookbackDays, horizonHours, c.Query("profile"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "수요 예측 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, forecast)
+}
+
+// queryInt 정수 쿼리 파라미터 (없으면 기본값)
+func queryInt(c *gin.Context, name string, fallback int) (int, error) {
+	v := c.Query(name)
+	if v == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s는 정수여야 합니다: %q", name, v)
+	}
+	return n, nil
+}
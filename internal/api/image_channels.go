@@ -0,0 +1,125 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+func (s *Server) listImageChannels(c *gin.Context) {
+	channels, err := s.sessionService.ListImageChannels()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "이미지 채널 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"channels": channels,
+		"count":    len(channels),
+	})
+}
+
+// updateImageChannel stable/next 채널의 베이스 이미지나 next 롤아웃 비율 변경
+func (s *Server) updateImageChannel(c *gin.Context) {
+	var req session.ImageChannelUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	channel, err := s.sessionService.UpdateImageChannel(c.Param("name"), req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "이미지 채널 변경 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, channel)
+}
+
+func (s *Server) listImagePins(c *gin.Context) {
+	pins, err := s.sessionService.ListImagePins()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "이미지 채널 고정 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pins":  pins,
+		"count": len(pins),
+	})
+}
+
+// pinImageChannel 사용자 또는 프로젝트를 채널에 고정 (롤아웃 비율과 무관하게 적용)
+func (s *Server) pinImageChannel(c *gin.Context) {
+	var req struct {
+		Subject string `json:"subject" binding:"required"`
+		Channel string `json:"channel" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	pin, err := s.sessionService.PinImageChannel(req.Subject, req.Channel)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "이미지 채널 고정 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, pin)
+}
+
+func (s *Server) unpinImageChannel(c *gin.Context) {
+	if err := s.sessionService.UnpinImageChannel(c.Param("subject")); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "이미지 채널 고정 해제 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "이미지 채널 고정이 해제되었습니다",
+	})
+}
+
+// summarizeImageBuilds 템플릿별 이미지 빌드 시간과 캐시 적중률 (?since=RFC3339, 기본 최근 7일)
+func (s *Server) summarizeImageBuilds(c *gin.Context) {
+	since := time.Now().AddDate(0, 0, -7)
+	if value := c.Query("since"); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "since는 RFC3339 시각이어야 합니다: " + value,
+			})
+			return
+		}
+		since = t
+	}
+
+	summaries, err := s.sessionService.SummarizeImageBuilds(since)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "이미지 빌드 집계 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"since":     since,
+		"templates": summaries,
+	})
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+// listImages 세션 요청의 image로 쓸 수 있는 이미지 (기본 이미지는 카탈로그와 무관하게 항상 사용 가능)
+func (s *Server) listImages(c *gin.Context) {
+	images, err := s.sessionService.ListCatalogImages()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "이미지 카탈로그 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"default": docker.DefaultImage,
+		"images":  images,
+		"count":   len(images),
+	})
+}
+
+// registerImage 카탈로그에 이미지 등록 또는 교체
+func (s *Server) registerImage(c *gin.Context) {
+	var req session.CatalogImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	image, err := s.sessionService.RegisterCatalogImage(c.Param("name"), req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "이미지 등록 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, image)
+}
+
+func (s *Server) deregisterImage(c *gin.Context) {
+	if err := s.sessionService.DeregisterCatalogImage(c.Param("name")); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "이미지 등록 해제 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "이미지 등록이 해제되었습니다",
+	})
+}
@@ -0,0 +1,25 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// unlockSession 비활성으로 잠긴 세션을 SSH 접속 없이 재개 (호스트 포트로 직접 접속하는 경우)
+func (s *Server) unlockSession(c *gin.Context) {
+	sessionID := c.Param("id")
+	if !s.sessionAccessible(c, sessionID) {
+		return
+	}
+
+	session, err := s.sessionService.UnlockSession(sessionID, requestActor(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "세션 재개 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.sessionService.View(session))
+}
@@ -0,0 +1,33 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+type validateKeyRequest struct {
+	PublicKey string `json:"public_key" binding:"required"`
+}
+
+// validateKey 협업자 등록/인증서 발급 전에 공개키를 미리 검사 (저장하지 않음)
+func (s *Server) validateKey(c *gin.Context) {
+	var req validateKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	key, err := session.ValidatePublicKey(req.PublicKey)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "공개키 검증 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
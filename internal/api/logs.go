@@ -0,0 +1,113 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"golang.org/x/net/websocket"
+)
+
+// 로그 조회 기본값과 한도
+const (
+	defaultLogTail = 200
+	maxLogTail     = 10000
+	maxLogLine     = 64 * 1024 // 이보다 긴 줄은 잘라서 여러 메시지로 보냄
+)
+
+// getSessionLogs 세션 컨테이너 로그 (follow=true면 WebSocket으로 새 로그를 계속 전송)
+// tail(기본 200줄), since(RFC3339), timestamps=true로 범위와 형식을 고름
+func (s *Server) getSessionLogs(c *gin.Context) {
+	opts := docker.LogOptions{Tail: defaultLogTail, Timestamps: c.Query("timestamps") == "true"}
+	if tail := c.Query("tail"); tail != "" {
+		n, err := strconv.Atoi(tail)
+		if err != nil || n < 0 || n > maxLogTail {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "tail은 0~" + strconv.Itoa(maxLogTail) + " 사이의 정수여야 합니다",
+			})
+			return
+		}
+		opts.Tail = n
+	}
+	if since := c.Query("since"); since != "" {
+		at, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "since는 RFC3339 시각이어야 합니다: " + err.Error(),
+			})
+			return
+		}
+		opts.Since = at
+	}
+	opts.Follow = c.Query("follow") == "true"
+	if opts.Follow && !strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "follow=true는 WebSocket 연결로 요청해야 합니다",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+	session, reader, err := s.sessionService.SessionLogs(ctx, c.Param("id"), opts)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "로그 조회 실패: " + err.Error(),
+		})
+		return
+	}
+	defer reader.Close()
+	if !allowSession(c, session.UserID, session.ProjectID) {
+		return
+	}
+	setRequestUser(c, session.UserID)
+
+	if !opts.Follow {
+		c.Header("Content-Type", "text/plain; charset=utf-8")
+		c.Status(http.StatusOK)
+		io.Copy(c.Writer, reader)
+		return
+	}
+
+	// 브라우저가 보내는 Origin은 CORS 정책과 같이 모두 허용 (Handshake를 지정하지 않음)
+	server := websocket.Server{Handler: func(ws *websocket.Conn) {
+		defer ws.Close()
+		// 클라이언트가 연결을 닫으면 로그 스트림도 끝냄 (클라이언트 메시지는 읽고 버림)
+		go func() {
+			io.Copy(io.Discard, ws)
+			cancel()
+		}()
+		streamLogLines(ws, reader)
+	}}
+	log.Printf("📜 세션 로그 스트리밍 시작: %s", session.ID)
+	server.ServeHTTP(c.Writer, c.Request)
+	log.Printf("📜 세션 로그 스트리밍 종료: %s", session.ID)
+}
+
+// streamLogLines 로그를 한 줄씩 텍스트 메시지로 전송 (스트림이 끝나거나 전송에 실패하면 반환)
+func streamLogLines(ws *websocket.Conn, reader io.Reader) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 4096), maxLogLine)
+	scanner.Split(scanLogLines)
+	for scanner.Scan() {
+		if err := websocket.Message.Send(ws, scanner.Text()); err != nil {
+			return
+		}
+	}
+}
+
+// scanLogLines bufio.ScanLines와 같되 버퍼보다 긴 줄은 버퍼 크기만큼 잘라서 반환
+func scanLogLines(data []byte, atEOF bool) (int, []byte, error) {
+	advance, token, err := bufio.ScanLines(data, atEOF)
+	if advance == 0 && token == nil && err == nil && len(data) >= maxLogLine {
+		return maxLogLine, data[:maxLogLine], nil
+	}
+	return advance, token, err
+}
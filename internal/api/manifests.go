@@ -0,0 +1,46 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/auth"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// getSessionManifest 세션 생성 시 기록한 서명된 매니페스트 (세션을 지운 뒤에도 소유자가 내려받을 수 있음)
+func (s *Server) getSessionManifest(c *gin.Context) {
+	signed, err := s.sessionService.SessionManifest(c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "매니페스트 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	// 지운 세션은 인증 미들웨어가 소유자를 확인하지 못하므로 매니페스트에 기록된 소유자로 확인
+	owner := &store.Session{ID: signed.Manifest.SessionID, UserID: signed.Manifest.UserID, ProjectID: signed.Manifest.ProjectID}
+	if err := auth.AuthorizeSession(currentToken(c), owner); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	setRequestUser(c, owner.UserID)
+
+	c.Header("Content-Disposition", `attachment; filename="sandman-manifest-`+owner.ID+`.json"`)
+	c.JSON(http.StatusOK, signed)
+}
+
+// getManifestKey 매니페스트 서명을 검증할 공개키 (인용한 환경을 외부에서 확인할 수 있도록 공개 경로)
+func (s *Server) getManifestKey(c *gin.Context) {
+	key, err := s.sessionService.ManifestKey()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, key)
+}
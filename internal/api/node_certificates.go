@@ -0,0 +1,183 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/nodeca"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+type joinTokenRequest struct {
+	TTLMinutes int `json:"ttl_minutes"`
+}
+
+// SetupAgentRoutes 노드 에이전트 전용 mTLS 리스너의 라우트 (API 키 대신 노드 인증서로 인증)
+func (s *Server) SetupAgentRoutes() *gin.Engine {
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	r.Use(s.accessLogMiddleware(), tracingMiddleware(), gin.Recovery(), s.drainMiddleware())
+
+	r.POST("/agent/nodes", s.agentRegisterNode)
+	r.POST("/agent/nodes/:id/heartbeat", s.agentNodeHeartbeat)
+	r.POST("/agent/nodes/:id/certificate", s.agentRenewCertificate)
+	return r
+}
+
+// authorizeNode 클라이언트 인증서가 nodeID의 유효한 인증서가 아니면 403으로 응답하고 false
+func (s *Server) authorizeNode(c *gin.Context, nodeID string) bool {
+	if c.Request.TLS == nil || len(c.Request.TLS.PeerCertificates) == 0 {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+			"error": "노드 인증서가 필요합니다",
+		})
+		return false
+	}
+	if err := s.sessionService.AuthorizeNode(c.Request.TLS.PeerCertificates[0], nodeID); err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func (s *Server) agentRegisterNode(c *gin.Context) {
+	var req session.NodeRegistration
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+	if !s.authorizeNode(c, req.ID) {
+		return
+	}
+
+	node, err := s.sessionService.RegisterNode(req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, node)
+}
+
+func (s *Server) agentNodeHeartbeat(c *gin.Context) {
+	if !s.authorizeNode(c, c.Param("id")) {
+		return
+	}
+	s.nodeHeartbeat(c)
+}
+
+func (s *Server) agentRenewCertificate(c *gin.Context) {
+	nodeID := c.Param("id")
+	if !s.authorizeNode(c, nodeID) {
+		return
+	}
+
+	var req nodeca.RenewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	cert, err := s.sessionService.RenewNodeCertificate(nodeID, req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "노드 인증서 갱신 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, cert)
+}
+
+// enrollNode 일회용 가입 토큰으로 노드의 첫 인증서 발급 (토큰 자체가 비밀이라 API 키 없이 호출)
+func (s *Server) enrollNode(c *gin.Context) {
+	var req nodeca.EnrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	cert, err := s.sessionService.EnrollNode(req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "노드 가입 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, cert)
+}
+
+func (s *Server) getNodeCA(c *gin.Context) {
+	info := s.sessionService.NodeCAInfo()
+	if info == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "노드 CA가 설정되지 않았습니다",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, info)
+}
+
+func (s *Server) createNodeJoinToken(c *gin.Context) {
+	var req joinTokenRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "잘못된 요청 형식: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	token, err := s.sessionService.CreateNodeJoinToken(c.Param("id"), time.Duration(req.TTLMinutes)*time.Minute)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "가입 토큰 발급 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, token)
+}
+
+func (s *Server) listNodeCertificates(c *gin.Context) {
+	certs, err := s.sessionService.ListNodeCertificates(c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "노드 인증서 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"certificates": certs,
+		"count":        len(certs),
+	})
+}
+
+func (s *Server) revokeNodeCertificates(c *gin.Context) {
+	revoked, err := s.sessionService.RevokeNodeCertificates(c.Param("id"), "admin", "revoked by admin")
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "노드 인증서가 폐기되었습니다",
+		"revoked": revoked,
+	})
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+func (s *Server) listNodes(c *gin.Context) {
+	nodes, err := s.sessionService.ListNodes()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "노드 목록 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"nodes": nodes,
+		"count": len(nodes),
+	})
+}
+
+// registerNode 외부 노드 에이전트의 자가 등록
+func (s *Server) registerNode(c *gin.Context) {
+	var req session.NodeRegistration
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	node, err := s.sessionService.RegisterNode(req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, node)
+}
+
+func (s *Server) nodeHeartbeat(c *gin.Context) {
+	var req session.NodeHeartbeat
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	if err := s.sessionService.HeartbeatNode(c.Param("id"), req); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (s *Server) deleteNode(c *gin.Context) {
+	if err := s.sessionService.DeleteNode(c.Param("id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "노드 등록 해제 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "노드 등록이 해제되었습니다",
+	})
+}
+
+// drainNode 노드에 새 세션 배치를 멈추고 정책(immediate, ttl, maintenance)에 따라 세션 정리
+func (s *Server) drainNode(c *gin.Context) {
+	var req session.DrainRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "잘못된 요청 형식: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	status, err := s.sessionService.DrainNode(c.Param("id"), req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "노드 드레인 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, status)
+}
+
+func (s *Server) getDrainStatus(c *gin.Context) {
+	status, err := s.sessionService.DrainStatus(c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+func (s *Server) cancelDrain(c *gin.Context) {
+	if err := s.sessionService.CancelDrain(c.Param("id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "드레인 취소 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "드레인이 취소되었습니다",
+	})
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// expiringSoonWindow 대시보드에서 곧 만료될 세션으로 경고하는 기준
+const expiringSoonWindow = 10 * time.Minute
+
+// externalChangeAlertWindow 외부에서 지운 Docker 리소스를 대시보드에서 경고하는 기간
+const externalChangeAlertWindow = time.Hour
+
+// ProfileAvailability 프로파일별 MIG 인스턴스 가용 현황
+type ProfileAvailability struct {
+	Total     int `json:"total"`
+	Available int `json:"available"`
+}
+
+// Alert 대시보드에 표시할 운영 경고
+type Alert struct {
+	Severity string `json:"severity"` // critical, warning, info
+	Message  string `json:"message"`
+}
+
+// getOverview 대시보드가 한 번의 요청으로 그릴 수 있도록 세션, GPU 가용 현황, 대기열, 경고를 모아서 반환
+func (s *Server) getOverview(c *gin.Context) {
+	var (
+		sessions []*store.Session
+		err      error
+	)
+	if token := currentToken(c); token != nil && token.ProjectID != "" {
+		sessions, err = s.sessionService.ListProjectSessions(token.ProjectID)
+	} else {
+		sessions, err = s.sessionService.ListAllSessions()
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": "세션 목록 조회 실패: " + err.Error(),
+		})
+		return
+	}
+	if sessions == nil {
+		sessions = []*store.Session{}
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].CreatedAt.After(sessions[j].CreatedAt) })
+
+	availability := map[string]*ProfileAvailability{}
+	for _, instance := range s.gpuManager.ListMIGInstances() {
+		p, ok := availability[instance.Profile.Name]
+		if !ok {
+			p = &ProfileAvailability{}
+			availability[instance.Profile.Name] = p
+		}
+		p.Total++
+		if !instance.InUse {
+			p.Available++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"deployment":           s.config.Current().Deployment,
+		"sessions":             s.sessionService.Views(sessions),
+		"gpus":                 s.gpuManager.GetGPUInfo(),
+		"profile_availability": availability,
+		// 세션은 현재 요청 즉시 생성되므로 대기열은 항상 비어 있음
+		"queue":  []interface{}{},
+		"alerts": s.collectAlerts(sessions, availability),
+	})
+}
+
+func (s *Server) collectAlerts(sessions []*store.Session, availability map[string]*ProfileAvailability) []Alert {
+	alerts := []Alert{}
+
+	if err := s.dockerClient.Ping(); err != nil {
+		alerts = append(alerts, Alert{Severity: "critical", Message: "Docker 데몬에 연결할 수 없습니다: " + err.Error()})
+	}
+	if runtime := s.dockerClient.NVIDIARuntimeStatus(); !runtime.Available {
+		alerts = append(alerts, Alert{Severity: "critical", Message: "NVIDIA 컨테이너 런타임을 사용할 수 없습니다: " + runtime.Error})
+	}
+	resources := s.sessionService.DockerResourceStatus()
+	for _, issue := range resources.Issues {
+		alerts = append(alerts, Alert{Severity: "critical", Message: issue})
+	}
+	for _, event := range resources.Recent {
+		if event.External && time.Since(event.At) < externalChangeAlertWindow {
+			alerts = append(alerts, Alert{
+				Severity: "warning",
+				Message:  "Docker " + event.Type + " " + event.Name + "이(가) 외부에서 삭제되었습니다 (" + event.At.Format(time.RFC3339) + ")",
+			})
+		}
+	}
+
+	names := make([]string, 0, len(availability))
+	for name := range availability {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if availability[name].Available == 0 {
+			alerts = append(alerts, Alert{Severity: "warning", Message: "프로파일 " + name + "의 사용 가능한 MIG 인스턴스가 없습니다"})
+		}
+	}
+
+	now := time.Now()
+	for _, session := range sessions {
+		if remaining := session.ExpiresAt.Sub(now); remaining > 0 && remaining < expiringSoonWindow {
+			alerts = append(alerts, Alert{
+				Severity: "info",
+				Message:  "세션 " + session.UserID + "이(가) " + remaining.Round(time.Minute).String() + " 후 만료됩니다",
+			})
+		}
+	}
+
+	return alerts
+}
@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PauseRequest 일시 중지 옵션
+type PauseRequest struct {
+	// KeepGPU 인스턴스를 계속 점유 (기본은 내놓고 mig_reservation_minutes 동안 소프트 예약)
+	KeepGPU bool `json:"keep_gpu"`
+}
+
+// pauseSession 컨테이너를 멈추고 세션 기록과 워크스페이스를 유지 (밤새 작업을 멈춰 둘 때)
+func (s *Server) pauseSession(c *gin.Context) {
+	sessionID := c.Param("id")
+	if !s.sessionAccessible(c, sessionID) {
+		return
+	}
+
+	var req PauseRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "잘못된 요청 형식: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	session, err := s.sessionService.PauseSession(sessionID, requestActor(c), req.KeepGPU)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "세션 일시 중지 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.sessionService.View(session))
+}
+
+// resumeSession 일시 중지한 세션의 컨테이너를 다시 시작 (GPU 인스턴스를 다시 할당)
+func (s *Server) resumeSession(c *gin.Context) {
+	sessionID := c.Param("id")
+	if !s.sessionAccessible(c, sessionID) {
+		return
+	}
+
+	session, err := s.sessionService.ResumeSession(sessionID, requestActor(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "세션 재개 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.sessionService.View(session))
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/auth"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/policy"
+)
+
+// policyBodyLimit 규칙 입력으로 읽는 요청 본문 최대 크기 (더 크면 body 없이 평가)
+const policyBodyLimit = 1 << 20
+
+var policyDenials = metrics.NewCounter("sandman_policy_denials_total", "허가 규칙(authz_policies)으로 거부한 요청 수", "rule")
+
+// policyMiddleware 변경 요청을 authz_policies로 평가 (인증/RBAC 검사 뒤, 규칙이 없으면 그냥 통과)
+func (s *Server) policyMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			c.Next()
+			return
+		}
+		cfg := s.config.Current()
+		engine := cfg.Policies()
+		if engine.Empty() || c.FullPath() == "" {
+			c.Next()
+			return
+		}
+
+		if denial := engine.Evaluate(s.policyInput(c), cfg.Location()); denial != nil {
+			policyDenials.Inc(denial.Rule)
+			log.Printf("🚫 정책 %s로 요청 거부: %s %s", denial.Rule, c.Request.Method, c.Request.URL.Path)
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":  denial.Message,
+				"policy": denial.Rule,
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// policyInput 요청 키, 경로, 본문, 대상 사용자와 그 사용량으로 규칙 입력 구성
+func (s *Server) policyInput(c *gin.Context) policy.Input {
+	token := currentToken(c)
+	actor := map[string]interface{}{
+		"id":         auth.TokenUser(token),
+		"admin":      auth.IsAdmin(token),
+		"scopes":     []string{},
+		"project_id": "",
+	}
+	if token != nil {
+		actor["scopes"] = token.Scopes
+		actor["project_id"] = token.ProjectID
+	}
+
+	params := map[string]interface{}{}
+	for _, p := range c.Params {
+		params[p.Key] = p.Value
+	}
+	query := map[string]interface{}{}
+	for key, values := range c.Request.URL.Query() {
+		query[key] = strings.Join(values, ",")
+	}
+	body := readPolicyBody(c)
+	request := map[string]interface{}{
+		"method": c.Request.Method,
+		"path":   c.Request.URL.Path,
+		"params": params,
+		"query":  query,
+		"body":   body,
+	}
+
+	// 대상 사용자: 사용자 키의 주인, 없으면 본문의 user_id나 /users/:id
+	userID := auth.TokenUser(token)
+	if userID == "" {
+		if id, ok := body["user_id"].(string); ok {
+			userID = id
+		} else if strings.HasPrefix(c.FullPath(), "/users/:id") {
+			userID = c.Param("id")
+		}
+	}
+	user := map[string]interface{}{"id": userID, "teams": []string{}, "team_ids": []string{}}
+	usage := map[string]interface{}{}
+	if userID != "" {
+		if ids, names, err := s.authService.UserTeams(userID); err == nil {
+			user["teams"], user["team_ids"] = names, ids
+		}
+		projectID, _ := body["project_id"].(string)
+		if projectID == "" && token != nil {
+			projectID = token.ProjectID
+		}
+		if quota, err := s.sessionService.QuotaStatus(userID, projectID); err == nil {
+			usage = toPolicyMap(quota)
+		}
+	}
+
+	return policy.Input{
+		Actor:   actor,
+		Action:  c.Request.Method + " " + c.FullPath(),
+		Request: request,
+		User:    user,
+		Usage:   usage,
+		Now:     time.Now(),
+	}
+}
+
+// readPolicyBody JSON 본문을 읽고 핸들러가 다시 읽을 수 있게 되돌려 놓음 (JSON 객체가 아니면 빈 맵)
+func readPolicyBody(c *gin.Context) map[string]interface{} {
+	body := map[string]interface{}{}
+	if c.Request.Body == nil || !strings.Contains(c.ContentType(), "json") {
+		return body
+	}
+	raw, err := io.ReadAll(io.LimitReader(c.Request.Body, policyBodyLimit+1))
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(raw), c.Request.Body))
+	if err != nil || len(raw) > policyBodyLimit {
+		return body
+	}
+	json.Unmarshal(raw, &body)
+	return body
+}
+
+// toPolicyMap 구조체를 JSON 필드 이름 그대로 규칙에서 쓸 수 있는 맵으로 변환
+func toPolicyMap(v interface{}) map[string]interface{} {
+	m := map[string]interface{}{}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return m
+	}
+	json.Unmarshal(raw, &m)
+	return m
+}
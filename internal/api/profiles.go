@@ -0,0 +1,90 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+func (s *Server) listProfiles(c *gin.Context) {
+	profiles, err := s.sessionService.ListProfiles()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "프로파일 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"profiles": profiles,
+		"count":    len(profiles),
+	})
+}
+
+func (s *Server) getProfile(c *gin.Context) {
+	profile, err := s.sessionService.GetProfile(c.Param("name"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "프로파일 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// createProfile 사용자에게 제공할 MIG 프로파일 정의 추가 (활성 프로파일은 이 노드에 인스턴스가 있어야 함)
+func (s *Server) createProfile(c *gin.Context) {
+	var req session.ProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	profile, err := s.sessionService.CreateProfile(req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "프로파일 생성 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, profile)
+}
+
+// updateProfile 프로파일 정의 전체 교체 (이름은 경로 값 사용)
+func (s *Server) updateProfile(c *gin.Context) {
+	var req session.ProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	profile, err := s.sessionService.UpdateProfile(c.Param("name"), req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "프로파일 변경 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+func (s *Server) deleteProfile(c *gin.Context) {
+	if err := s.sessionService.DeleteProfile(c.Param("name")); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "프로파일 삭제 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "프로파일 정의가 삭제되었습니다",
+	})
+}
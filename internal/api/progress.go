@@ -0,0 +1,86 @@
+package api
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/auth"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+	"golang.org/x/net/websocket"
+)
+
+// progressAccessible 생성 중인 세션은 아직 저장되지 않아 인증 미들웨어가 확인하지 못하므로 요청자를 직접 확인
+func progressAccessible(c *gin.Context, p *session.ProvisioningProgress) bool {
+	owner := &store.Session{ID: p.SessionID, UserID: p.UserID, ProjectID: p.ProjectID}
+	if err := auth.AuthorizeSession(currentToken(c), owner); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return false
+	}
+	setRequestUser(c, p.UserID)
+	return true
+}
+
+// getSessionProgress 비동기 생성(POST /sessions?async=true)의 현재 단계와 이벤트 기록
+func (s *Server) getSessionProgress(c *gin.Context) {
+	progress, err := s.sessionService.SessionProgress(c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "생성 진행 조회 실패: " + err.Error(),
+		})
+		return
+	}
+	if !progressAccessible(c, progress) {
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// streamSessionProgress 지금까지의 이벤트를 보낸 뒤 새 이벤트를 WebSocket JSON 메시지로 전송 (완료/실패 이벤트 후 닫음)
+func (s *Server) streamSessionProgress(c *gin.Context) {
+	if !strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "WebSocket 연결로 요청해야 합니다 (폴링은 GET /sessions/:id/progress)",
+		})
+		return
+	}
+
+	progress, events, cancel, err := s.sessionService.SubscribeProgress(c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "생성 진행 조회 실패: " + err.Error(),
+		})
+		return
+	}
+	defer cancel()
+	if !progressAccessible(c, progress) {
+		return
+	}
+
+	server := websocket.Server{Handler: func(ws *websocket.Conn) {
+		defer ws.Close()
+		// 클라이언트가 연결을 닫으면 구독도 끝냄 (클라이언트 메시지는 읽고 버림)
+		go func() {
+			io.Copy(io.Discard, ws)
+			cancel()
+		}()
+		for _, event := range progress.Events {
+			if err := websocket.JSON.Send(ws, event); err != nil {
+				return
+			}
+		}
+		for event := range events {
+			if err := websocket.JSON.Send(ws, event); err != nil {
+				return
+			}
+		}
+	}}
+	log.Printf("📡 세션 생성 진행 스트리밍 시작: %s", progress.SessionID)
+	server.ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,312 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/auth"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+type createOrganizationRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type createProjectRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type createTokenRequest struct {
+	Name   string   `json:"name" binding:"required"`
+	Scopes []string `json:"scopes"`
+}
+
+func (s *Server) createOrganization(c *gin.Context) {
+	var req createOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	org, err := s.sessionService.CreateOrganization(req.Name)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, org)
+}
+
+func (s *Server) listOrganizations(c *gin.Context) {
+	if !allowOrg(c) {
+		return
+	}
+
+	orgs, err := s.sessionService.ListOrganizations()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "조직 목록 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, orgs)
+}
+
+func (s *Server) deleteOrganization(c *gin.Context) {
+	if err := s.sessionService.DeleteOrganization(c.Param("id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "조직 삭제 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "조직이 삭제되었습니다",
+	})
+}
+
+func (s *Server) getOrganizationUsage(c *gin.Context) {
+	orgID := c.Param("id")
+	if !allowOrg(c) {
+		return
+	}
+	if _, err := s.sessionService.GetOrganization(orgID); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "조직을 찾을 수 없습니다: " + err.Error(),
+		})
+		return
+	}
+
+	usage, err := s.sessionService.GetOrganizationUsage(orgID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "조직 사용량 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+func (s *Server) createProject(c *gin.Context) {
+	var req createProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	project, err := s.sessionService.CreateProject(c.Param("id"), req.Name)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, project)
+}
+
+func (s *Server) listProjects(c *gin.Context) {
+	if !allowOrg(c) {
+		return
+	}
+
+	projects, err := s.sessionService.ListProjects(c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "프로젝트 목록 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, projects)
+}
+
+func (s *Server) getProject(c *gin.Context) {
+	projectID := c.Param("id")
+	if !allowProject(c, projectID) {
+		return
+	}
+
+	project, err := s.sessionService.GetProject(projectID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "프로젝트를 찾을 수 없습니다: " + err.Error(),
+		})
+		return
+	}
+
+	usage, err := s.sessionService.GetProjectUsage(project)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "프로젝트 사용량 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"project": project,
+		"usage":   usage,
+	})
+}
+
+func (s *Server) deleteProject(c *gin.Context) {
+	projectID := c.Param("id")
+	if !allowProject(c, projectID) {
+		return
+	}
+
+	if err := s.sessionService.DeleteProject(projectID); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "프로젝트 삭제 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "프로젝트가 삭제되었습니다",
+	})
+}
+
+func (s *Server) listProjectSessions(c *gin.Context) {
+	projectID := c.Param("id")
+	if !allowProject(c, projectID) {
+		return
+	}
+
+	if _, err := s.sessionService.GetProject(projectID); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "프로젝트를 찾을 수 없습니다: " + err.Error(),
+		})
+		return
+	}
+
+	sessions, err := s.sessionService.ListProjectSessions(projectID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "세션 목록 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"project_id": projectID,
+		"sessions":   s.sessionService.Views(sessions),
+		"count":      len(sessions),
+	})
+}
+
+func (s *Server) createProjectToken(c *gin.Context) {
+	projectID := c.Param("id")
+	if !allowProject(c, projectID) {
+		return
+	}
+
+	var req createTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	if _, err := s.sessionService.GetProject(projectID); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "프로젝트를 찾을 수 없습니다: " + err.Error(),
+		})
+		return
+	}
+
+	scopes, err := auth.CanGrant(currentToken(c), req.Scopes)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	token, raw, err := s.authService.IssueToken(req.Name, projectID, scopes)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	// 평문 토큰은 발급 응답에서만 확인 가능
+	c.JSON(http.StatusCreated, gin.H{
+		"token":    raw,
+		"metadata": token,
+	})
+}
+
+func (s *Server) listProjectTokens(c *gin.Context) {
+	projectID := c.Param("id")
+	if !allowProject(c, projectID) {
+		return
+	}
+
+	tokens, err := s.authService.ListTokens(projectID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "토큰 목록 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+func (s *Server) revokeProjectToken(c *gin.Context) {
+	projectID := c.Param("id")
+	if !allowProject(c, projectID) {
+		return
+	}
+
+	tokenID := c.Param("tokenId")
+	tokens, err := s.authService.ListTokens(projectID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "토큰 목록 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	var target *store.APIToken
+	for _, token := range tokens {
+		if token.ID == tokenID {
+			target = token
+			break
+		}
+	}
+	if target == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "프로젝트에 토큰 " + tokenID + "이 없습니다",
+		})
+		return
+	}
+	if err := auth.CanRevoke(currentToken(c), target); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	if err := s.authService.RevokeToken(tokenID); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "토큰이 폐기되었습니다",
+	})
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuarantineRequest 세션 격리 요청
+type QuarantineRequest struct {
+	Reason string `json:"reason" binding:"required"` // 감사 기록과 세션 이벤트에 남는 사유 (사고 번호 등)
+}
+
+// adminActor 관리자 작업을 한 주체 (인증을 끈 배포에서는 "admin")
+func adminActor(c *gin.Context) string {
+	if token := currentToken(c); token != nil {
+		return "token:" + token.Name
+	}
+	return "admin"
+}
+
+// quarantineSession 침해가 의심되는 세션의 네트워크를 끊고 컨테이너와 워크스페이스를 조사용으로 보존
+func (s *Server) quarantineSession(c *gin.Context) {
+	var req QuarantineRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	session, err := s.sessionService.QuarantineSession(c.Param("id"), adminActor(c), req.Reason)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "세션 격리 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.sessionService.View(session))
+}
+
+// liftQuarantine 격리를 풀고 세션을 네트워크에 다시 연결 (이후 삭제와 만료가 다시 가능)
+func (s *Server) liftQuarantine(c *gin.Context) {
+	session, err := s.sessionService.LiftQuarantine(c.Param("id"), adminActor(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "세션 격리 해제 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.sessionService.View(session))
+}
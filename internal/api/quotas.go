@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+func (s *Server) listQuotas(c *gin.Context) {
+	quotas, err := s.sessionService.ListQuotas()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "한도 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"quotas": quotas,
+		"count":  len(quotas),
+	})
+}
+
+func (s *Server) getQuota(c *gin.Context) {
+	quota, err := s.sessionService.GetQuota(c.Param("subject"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "한도 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, quota)
+}
+
+// setQuota 사용자(user:<id>) 또는 팀(team:<id>)의 한도 전체 교체 (0은 제한 없음)
+func (s *Server) setQuota(c *gin.Context) {
+	var req struct {
+		MaxSessions   int `json:"max_sessions"`
+		MaxGPUSlices  int `json:"max_gpu_slices"`
+		MaxTTLMinutes int `json:"max_ttl_minutes"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	quota, err := s.sessionService.SetQuota(store.Quota{
+		Subject:       c.Param("subject"),
+		MaxSessions:   req.MaxSessions,
+		MaxGPUSlices:  req.MaxGPUSlices,
+		MaxTTLMinutes: req.MaxTTLMinutes,
+	})
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "한도 설정 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, quota)
+}
+
+func (s *Server) deleteQuota(c *gin.Context) {
+	if err := s.sessionService.DeleteQuota(c.Param("subject")); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "한도 삭제 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "한도가 삭제되었습니다",
+	})
+}
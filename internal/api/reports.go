@@ -0,0 +1,74 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/report"
+)
+
+// defaultReportRange from을 생략했을 때 내보낼 기간
+const defaultReportRange = 30 * 24 * time.Hour
+
+// exportSessions 종료된 세션 기록 내보내기 (?from=&to=&format=csv|parquet&project_id=)
+func (s *Server) exportSessions(c *gin.Context) {
+	to := time.Now()
+	if v := c.Query("to"); v != "" {
+		t, err := parseReportTime(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		to = t
+	}
+	from := to.Add(-defaultReportRange)
+	if v := c.Query("from"); v != "" {
+		t, err := parseReportTime(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		from = t
+	}
+	format := c.DefaultQuery("format", report.FormatCSV)
+
+	projectID := c.Query("project_id")
+	if token := currentToken(c); token != nil && token.ProjectID != "" {
+		if projectID == "" {
+			projectID = token.ProjectID
+		}
+		if !allowProject(c, projectID) {
+			return
+		}
+	}
+
+	userID, ok := scopedUser(c)
+	if !ok {
+		return
+	}
+
+	var buf bytes.Buffer
+	if _, err := s.sessionService.ExportSessions(&buf, from, to, projectID, userID, format); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "세션 기록 내보내기 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, report.FileName(from, to, format)))
+	c.Data(http.StatusOK, report.ContentType(format), buf.Bytes())
+}
+
+// parseReportTime RFC3339 시각 또는 UTC 날짜(2024-01-31)
+func parseReportTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse("2006-01-02", v); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("시각 형식이 잘못되었습니다 (RFC3339 또는 YYYY-MM-DD): %q", v)
+}
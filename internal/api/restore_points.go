@@ -0,0 +1,79 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// restorePointsAccessible 세션(종료된 세션 포함)의 복원 지점에 현재 토큰이 접근할 수 있는지 확인
+func (s *Server) restorePointsAccessible(c *gin.Context, sessionID string) bool {
+	if session, err := s.sessionService.GetSession(sessionID); err == nil {
+		setRequestUser(c, session.UserID)
+		return allowSession(c, session.UserID, session.ProjectID)
+	}
+
+	points, err := s.sessionService.ListRestorePoints(sessionID)
+	if err != nil || len(points) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error": "세션 " + sessionID + "의 복원 지점을 찾을 수 없습니다",
+		})
+		return false
+	}
+	setRequestUser(c, points[0].UserID)
+	return allowSession(c, points[0].UserID, points[0].ProjectID)
+}
+
+func (s *Server) listRestorePoints(c *gin.Context) {
+	sessionID := c.Param("id")
+	if !s.restorePointsAccessible(c, sessionID) {
+		return
+	}
+
+	points, err := s.sessionService.ListRestorePoints(sessionID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "복원 지점 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"restore_points": points,
+		"count":          len(points),
+	})
+}
+
+func (s *Server) createRestorePoint(c *gin.Context) {
+	sessionID := c.Param("id")
+	if !s.sessionAccessible(c, sessionID) {
+		return
+	}
+
+	rp, err := s.sessionService.CreateRestorePoint(sessionID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "복원 지점 생성 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, rp)
+}
+
+func (s *Server) diffRestorePoint(c *gin.Context) {
+	sessionID := c.Param("id")
+	if !s.restorePointsAccessible(c, sessionID) {
+		return
+	}
+
+	diff, err := s.sessionService.DiffRestorePoint(sessionID, c.Param("restorePointId"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "복원 지점 비교 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
@@ -1,23 +1,50 @@
 package api
 
 import (
+	"errors"
 	"net/http"
+	"os"
+	"sync/atomic"
 
 	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/auth"
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
 	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
 	"github.com/sandman/gpu-ssh-gateway/internal/session"
 	"github.com/sandman/gpu-ssh-gateway/internal/store"
 )
 
+// livenessCacheControl 폴링 클라이언트/프록시가 짧게 재사용할 수 있도록 허용
+const livenessCacheControl = "private, max-age=5"
+
 type Server struct {
 	sessionService *session.Service
 	gpuManager     *gpu.Manager
+	authService    *auth.Service
+	dockerClient   *docker.Client
+	config         *config.Manager
+
+	draining    atomic.Bool // 종료 절차 중에는 새 요청 거부
+	accessLog   accessLogger
+	requireAuth bool // 공개 경로를 뺀 모든 요청에 API 키 요구
 }
 
-func NewServer(sessionService *session.Service, gpuManager *gpu.Manager) *Server {
+func NewServer(
+	sessionService *session.Service,
+	gpuManager *gpu.Manager,
+	authService *auth.Service,
+	dockerClient *docker.Client,
+	cfg *config.Manager,
+) *Server {
 	return &Server{
 		sessionService: sessionService,
 		gpuManager:     gpuManager,
+		authService:    authService,
+		dockerClient:   dockerClient,
+		config:         cfg,
+		accessLog:      accessLogger{w: os.Stdout},
 	}
 }
 
@@ -56,31 +83,254 @@ func (s *Server) SetupRoutes() *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
 
-	// 미들웨어 추가: 로거, 복구, CORS
-	r.Use(gin.Logger(), gin.Recovery(), corsMiddleware())
+	// 미들웨어 추가: 접근 로그, 복구, CORS, 압축, 인증, 허가 규칙
+	r.Use(s.accessLogMiddleware(), tracingMiddleware(), gin.Recovery(), corsMiddleware(), s.drainMiddleware(), gzipMiddleware(), s.authMiddleware(), s.policyMiddleware())
 
 	// Health check
 	r.GET("/healthz", s.healthCheck)
+	r.GET("/readyz", s.readinessCheck)
+	r.GET("/metrics", gin.WrapH(metrics.Handler()))
+
+	// Public status feed (no credentials needed)
+	r.GET("/status", s.getStatus)
+
+	// Dashboard
+	r.StaticFS("/ui", uiFileSystem())
+	r.GET("/", func(c *gin.Context) { c.Redirect(http.StatusFound, "/ui/") })
+	r.GET("/overview", etagMiddleware(), s.getOverview)
 
 	// Session management
 	r.POST("/sessions", s.createSession)
 	r.GET("/sessions/:id", s.getSession)
+	r.GET("/sessions/:id/alive", s.getSessionLiveness)
 	r.DELETE("/sessions/:id", s.deleteSession)
-	r.GET("/sessions", s.listSessions)
+	r.GET("/sessions", etagMiddleware(), s.listSessions)
 	r.DELETE("/sessions", s.deleteAllSessions)
+	r.GET("/sessions/:id/collaborators", s.listCollaborators)
+	r.POST("/sessions/:id/collaborators", s.addCollaborator)
+	r.DELETE("/sessions/:id/collaborators/:userId", s.removeCollaborator)
+	r.GET("/sessions/:id/certificates", s.listCertificates)
+	r.POST("/sessions/:id/certificates", s.issueCertificate)
+	r.DELETE("/sessions/:id/certificates/:serial", s.revokeCertificate)
+	r.GET("/sessions/:id/usage", s.getSessionUsage)
+	r.GET("/sessions/:id/build", s.getSessionBuild)
+	r.GET("/sessions/:id/manifest", s.getSessionManifest)
+	r.GET("/sessions/:id/logs", s.getSessionLogs)
+	r.GET("/sessions/:id/progress", s.getSessionProgress)
+	r.GET("/sessions/:id/events", s.listSessionEvents)
+	r.GET("/sessions/:id/progress/stream", s.streamSessionProgress)
+	r.GET("/sessions/:id/credentials", s.getSessionCredentials)
+	r.POST("/sessions/:id/credentials/rotate", s.rotateSessionCredentials)
+	r.POST("/sessions/:id/unlock", s.unlockSession)
+	r.POST("/sessions/:id/pause", s.pauseSession)
+	r.POST("/sessions/:id/resume", s.resumeSession)
+	r.POST("/sessions/:id/stop", s.stopSession)
+	r.POST("/sessions/:id/start", s.startSession)
+	r.PATCH("/sessions/:id/image", s.changeSessionImage)
+	r.GET("/credentials/:token", s.redeemCredentialLink)
+	r.PATCH("/sessions/:id/annotations", s.patchAnnotations)
+	r.GET("/sessions/:id/drift", s.getSessionDrift)
+	r.POST("/sessions/:id/drift/reset", s.resetSessionDrift)
+	r.GET("/sessions/:id/restore-points", s.listRestorePoints)
+	r.POST("/sessions/:id/restore-points", s.createRestorePoint)
+	r.GET("/sessions/:id/restore-points/:restorePointId/diff", s.diffRestorePoint)
+	r.GET("/usage", s.listUsage)
+	r.GET("/forecast", s.getForecast)
+	r.GET("/stats/wait-times", s.getWaitTimeStats)
+	r.GET("/audit", s.getAudit)
+	r.GET("/reports/sessions", s.exportSessions)
+	r.GET("/ssh-ca", s.getSSHCA)
+	r.POST("/keys/validate", s.validateKey)
+
+	// Organization / project management
+	r.POST("/orgs", s.createOrganization)
+	r.GET("/orgs", s.listOrganizations)
+	r.DELETE("/orgs/:id", s.deleteOrganization)
+	r.GET("/orgs/:id/usage", s.getOrganizationUsage)
+	r.POST("/orgs/:id/projects", s.createProject)
+	r.GET("/orgs/:id/projects", s.listProjects)
+	r.GET("/projects/:id", s.getProject)
+	r.DELETE("/projects/:id", s.deleteProject)
+	r.GET("/projects/:id/sessions", s.listProjectSessions)
+	r.POST("/projects/:id/tokens", s.createProjectToken)
+	r.GET("/projects/:id/tokens", s.listProjectTokens)
+	r.DELETE("/projects/:id/tokens/:tokenId", s.revokeProjectToken)
+
+	// Teams (user directory) and team-lead session management
+	r.POST("/teams", s.createTeam)
+	r.GET("/teams", s.listTeams)
+	r.GET("/teams/:id", s.getTeam)
+	r.DELETE("/teams/:id", s.deleteTeam)
+	r.PUT("/teams/:id/members/:userId", s.setTeamMember)
+	r.DELETE("/teams/:id/members/:userId", s.removeTeamMember)
+	teamLead := r.Group("/teams/:id/sessions", s.requireTeamLead())
+	teamLead.GET("", s.listTeamSessions)
+	teamLead.POST("/:sessionId/extend", s.extendTeamSession)
+	teamLead.DELETE("/:sessionId", s.deleteTeamSession)
+
+	// User settings
+	r.GET("/me/quota", s.getMyQuota)
+	r.GET("/users/:id/settings", s.getUserSettings)
+	r.PUT("/users/:id/settings", s.updateUserSettings)
+
+	// Admin
+	r.GET("/admin/config", s.getConfig)
+	r.GET("/admin/api-keys", s.listAPIKeys)
+	r.POST("/admin/api-keys", s.createAPIKey)
+	r.DELETE("/admin/api-keys/:id", s.revokeAPIKey)
+	r.POST("/admin/config/reload", s.reloadConfig)
+	r.GET("/admin/locks", s.listLocks)
+	r.GET("/admin/provisioning", s.getProvisioning)
+	r.GET("/admin/mig-reservations", s.listMIGReservations)
+	r.POST("/admin/mig-instances", s.createMIGInstance)
+	r.DELETE("/admin/mig-instances/:id", s.deleteMIGInstance)
+	r.GET("/admin/routes", s.getRoutes)
+	r.GET("/admin/docker-resources", s.getDockerResources)
+	r.POST("/admin/docker-resources/reconcile", s.reconcileDockerResources)
+	r.GET("/admin/consistency", s.getConsistency)
+	r.POST("/admin/whatif", s.whatIf)
+	r.GET("/admin/profiles", s.listProfiles)
+	r.POST("/admin/profiles", s.createProfile)
+	r.GET("/admin/profiles/:name", s.getProfile)
+	r.PUT("/admin/profiles/:name", s.updateProfile)
+	r.DELETE("/admin/profiles/:name", s.deleteProfile)
+	r.GET("/admin/image-channels", s.listImageChannels)
+	r.PUT("/admin/image-channels/:name", s.updateImageChannel)
+	r.GET("/admin/image-builds", s.summarizeImageBuilds)
+	r.GET("/admin/image-pins", s.listImagePins)
+	r.PUT("/admin/image-pins", s.pinImageChannel)
+	r.DELETE("/admin/image-pins/:subject", s.unpinImageChannel)
+	r.PUT("/admin/images/:name", s.registerImage)
+	r.DELETE("/admin/images/:name", s.deregisterImage)
+	r.GET("/admin/quotas", s.listQuotas)
+	r.GET("/admin/quotas/:subject", s.getQuota)
+	r.PUT("/admin/quotas/:subject", s.setQuota)
+	r.DELETE("/admin/quotas/:subject", s.deleteQuota)
+	r.GET("/admin/nodes", s.listNodes)
+	r.POST("/admin/nodes", s.registerNode)
+	r.POST("/admin/nodes/:id/heartbeat", s.nodeHeartbeat)
+	r.DELETE("/admin/nodes/:id", s.deleteNode)
+	r.POST("/admin/nodes/:id/drain", s.drainNode)
+	r.GET("/admin/nodes/:id/drain", s.getDrainStatus)
+	r.DELETE("/admin/nodes/:id/drain", s.cancelDrain)
+	r.POST("/admin/nodes/:id/join-token", s.createNodeJoinToken)
+	r.GET("/admin/nodes/:id/certificates", s.listNodeCertificates)
+	r.DELETE("/admin/nodes/:id/certificates", s.revokeNodeCertificates)
+	r.GET("/nodes/ca", s.getNodeCA)
+	r.POST("/nodes/enroll", s.enrollNode)
+	r.GET("/admin/dns-queries", s.listDNSQueries)
+	r.GET("/admin/workspaces", s.listWorkspaceLifecycle)
+	r.GET("/admin/workspace-volumes", s.listWorkspaceVolumes)
+	r.DELETE("/admin/workspace-volumes/:id", s.deleteWorkspaceVolume)
+	r.DELETE("/admin/users/:id/data", s.purgeUserData)
+	r.POST("/admin/incidents", s.createIncident)
+	r.GET("/admin/incidents", s.listIncidents)
+	r.PATCH("/admin/incidents/:id", s.updateIncident)
+	r.POST("/admin/incidents/:id/resolve", s.resolveIncident)
+	r.POST("/admin/sessions/:id/quarantine", s.quarantineSession)
+	r.DELETE("/admin/sessions/:id/quarantine", s.liftQuarantine)
+
+	// Session manifests
+	r.GET("/manifests/public-key", s.getManifestKey)
 
 	// GPU information
-	r.GET("/gpus", s.getGPUInfo)
+	r.GET("/gpus", etagMiddleware(), s.getGPUInfo)
 	r.GET("/gpus/profiles", s.getMIGProfiles)
-	r.GET("/gpus/available", s.getAvailableMIGInstances)
+	r.GET("/gpus/available", etagMiddleware(), s.getAvailableMIGInstances)
+
+	// Image catalog
+	r.GET("/images", s.listImages)
 
 	return r
 }
 
+// BeginDrain 종료 절차 시작 - 이후 새 API 요청은 503으로 거부 (헬스 체크, 메트릭 제외)
+func (s *Server) BeginDrain() {
+	s.draining.Store(true)
+}
+
+func (s *Server) drainMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.draining.Load() {
+			c.Next()
+			return
+		}
+
+		switch c.Request.URL.Path {
+		case "/healthz", "/readyz", "/metrics":
+			c.Next()
+			return
+		}
+
+		c.Header("Retry-After", "30")
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{
+			"error": "오케스트레이터가 종료 중입니다. 잠시 후 다시 시도하세요",
+		})
+	}
+}
+
 func (s *Server) healthCheck(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{
+	resp := gin.H{
 		"status":  "healthy",
 		"service": "gpu-ssh-gateway-orchestrator",
+	}
+	if deployment := s.config.Current().Deployment; !deployment.IsZero() {
+		resp["deployment"] = deployment
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// readinessCheck Docker 데몬과 NVIDIA 런타임이 GPU 세션을 받을 준비가 되었는지 확인
+// GPU가 없는 호스트는 CPU 전용 세션만 받으므로 NVIDIA 런타임이 없어도 준비된 것으로 봄
+func (s *Server) readinessCheck(c *gin.Context) {
+	checks := gin.H{}
+	ready := true
+
+	if s.draining.Load() {
+		checks["draining"] = gin.H{"ok": false}
+		ready = false
+	}
+
+	if err := s.dockerClient.Ping(); err != nil {
+		checks["docker"] = gin.H{"ok": false, "error": err.Error()}
+		ready = false
+	} else {
+		checks["docker"] = gin.H{"ok": true}
+	}
+
+	runtime := s.dockerClient.NVIDIARuntimeStatus()
+	switch {
+	case !s.gpuManager.HasGPU():
+		checks["nvidia_runtime"] = gin.H{"ok": true, "status": "not_required", "available": runtime.Available}
+	case !runtime.Available:
+		checks["nvidia_runtime"] = runtime
+		ready = false
+	default:
+		checks["nvidia_runtime"] = runtime
+	}
+
+	// 세션 네트워크가 없어 복구하지 못했으면 세션을 만들 수 없음
+	resources := s.sessionService.DockerResourceStatus()
+	checks["docker_resources"] = gin.H{"ok": resources.Healthy, "issues": resources.Issues}
+	if !resources.Healthy {
+		ready = false
+	}
+
+	// 시계 차이는 준비 상태에 영향을 주지 않고 경고로만 표시
+	if clock := s.sessionService.ClockStatus(); !clock.CheckedAt.IsZero() {
+		checks["clock"] = gin.H{"ok": clock.Warning == "", "status": clock}
+	}
+
+	status := http.StatusOK
+	state := "ready"
+	if !ready {
+		status = http.StatusServiceUnavailable
+		state = "not_ready"
+	}
+
+	c.JSON(status, gin.H{
+		"status": state,
+		"checks": checks,
 	})
 }
 
@@ -93,14 +343,55 @@ func (s *Server) createSession(c *gin.Context) {
 		return
 	}
 
-	response, err := s.sessionService.CreateSession(req)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+	setRequestUser(c, req.UserID)
+
+	// 사용자 키로는 자기 세션만 만들 수 있음
+	if err := auth.AuthorizeUser(currentToken(c), req.UserID); err != nil {
+		c.JSON(errorStatus(err), gin.H{
 			"error": err.Error(),
 		})
 		return
 	}
 
+	// 프로젝트 범위 토큰으로 생성한 세션은 해당 프로젝트에 귀속 (다른 키는 관리자만 프로젝트를 지정할 수 있음)
+	if token := currentToken(c); token != nil && token.ProjectID != "" && req.ProjectID == "" {
+		req.ProjectID = token.ProjectID
+	}
+	if req.ProjectID != "" && !allowProject(c, req.ProjectID) {
+		return
+	}
+
+	// async=true면 세션 ID만 바로 돌려주고 진행 상황은 /sessions/:id/progress(/stream)로 확인
+	if c.Query("async") == "true" {
+		sessionID, err := s.sessionService.StartSession(req.WithContext(c.Request.Context()))
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{
+			"session_id": sessionID,
+			"progress":   "/sessions/" + sessionID + "/progress",
+			"stream":     "/sessions/" + sessionID + "/progress/stream",
+		})
+		return
+	}
+
+	response, err := s.sessionService.CreateSession(req.WithContext(c.Request.Context()))
+	if err != nil {
+		body := gin.H{
+			"error": err.Error(),
+		}
+		// 한도 때문에 거부됐으면 현재 사용량과 한도를 함께 보여 줌
+		var quotaErr *session.QuotaError
+		if errors.As(err, &quotaErr) {
+			body["quota"] = quotaErr.Quota
+		}
+		c.JSON(errorStatus(err), body)
+		return
+	}
+
 	c.JSON(http.StatusCreated, response)
 }
 
@@ -109,20 +400,54 @@ func (s *Server) getSession(c *gin.Context) {
 
 	session, err := s.sessionService.GetSession(sessionID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
+		c.JSON(errorStatus(err), gin.H{
 			"error": "세션을 찾을 수 없습니다: " + err.Error(),
 		})
 		return
 	}
+	setRequestUser(c, session.UserID)
+
+	if !allowSession(c, session.UserID, session.ProjectID) {
+		return
+	}
 
-	c.JSON(http.StatusOK, session)
+	c.JSON(http.StatusOK, s.sessionService.View(session))
+}
+
+// getSessionLiveness SSO 런처/로드 밸런서가 자주 폴링하는 세션 상태 (메모리 캐시에서 응답)
+func (s *Server) getSessionLiveness(c *gin.Context) {
+	liveness, err := s.sessionService.SessionLiveness(c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "세션을 찾을 수 없습니다: " + err.Error(),
+		})
+		return
+	}
+
+	if !allowSession(c, liveness.UserID, liveness.ProjectID) {
+		return
+	}
+
+	c.Header("Cache-Control", livenessCacheControl)
+	c.JSON(http.StatusOK, liveness)
 }
 
 func (s *Server) deleteSession(c *gin.Context) {
 	sessionID := c.Param("id")
 
-	if err := s.sessionService.DeleteSession(sessionID); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+	if session, err := s.sessionService.GetSession(sessionID); err == nil {
+		setRequestUser(c, session.UserID)
+		if !allowSession(c, session.UserID, session.ProjectID) {
+			return
+		}
+	}
+
+	actor := "api"
+	if token := currentToken(c); token != nil {
+		actor = "token:" + token.Name
+	}
+	if err := s.sessionService.DeleteSessionAs(sessionID, actor); err != nil {
+		c.JSON(errorStatus(err), gin.H{
 			"error": "세션 삭제 실패: " + err.Error(),
 		})
 		return
@@ -134,9 +459,17 @@ func (s *Server) deleteSession(c *gin.Context) {
 }
 
 func (s *Server) listSessions(c *gin.Context) {
-	sessions, err := s.sessionService.ListAllSessions()
+	var (
+		sessions []*store.Session
+		err      error
+	)
+	if token := currentToken(c); token != nil && token.ProjectID != "" {
+		sessions, err = s.sessionService.ListProjectSessions(token.ProjectID)
+	} else {
+		sessions, err = s.sessionService.ListAllSessions()
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err), gin.H{
 			"error": "세션 목록 조회 실패: " + err.Error(),
 		})
 		return
@@ -147,12 +480,23 @@ func (s *Server) listSessions(c *gin.Context) {
 		sessions = []*store.Session{}
 	}
 
-	c.JSON(http.StatusOK, sessions)
+	// 관리자가 아닌 사용자 키에는 자기 세션만 보여 줌
+	if token := currentToken(c); !auth.IsAdmin(token) && auth.TokenUser(token) != "" {
+		own := []*store.Session{}
+		for _, session := range sessions {
+			if session.UserID == auth.TokenUser(token) {
+				own = append(own, session)
+			}
+		}
+		sessions = own
+	}
+
+	c.JSON(http.StatusOK, s.sessionService.Views(sessions))
 }
 
 func (s *Server) deleteAllSessions(c *gin.Context) {
 	if err := s.sessionService.DeleteAllSessions(); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(errorStatus(err), gin.H{
 			"error": "모든 세션 삭제 실패: " + err.Error(),
 		})
 		return
@@ -167,16 +511,26 @@ func (s *Server) getGPUInfo(c *gin.Context) {
 	gpuInfo := s.gpuManager.GetGPUInfo()
 
 	c.JSON(http.StatusOK, gin.H{
-		"gpus":  gpuInfo,
-		"count": len(gpuInfo),
+		"gpus":   gpuInfo,
+		"count":  len(gpuInfo),
+		"driver": s.gpuManager.DriverInfo(),
 	})
 }
 
 func (s *Server) getMIGProfiles(c *gin.Context) {
 	profiles := s.gpuManager.GetAvailableProfiles()
+	offered, err := s.sessionService.OfferedProfiles()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "프로파일 정의 조회 실패: " + err.Error(),
+		})
+		return
+	}
 
+	// offered: 관리자가 정의한 활성 프로파일 (비어 있으면 allowed_profiles 기준으로 모두 제공)
 	c.JSON(http.StatusOK, gin.H{
 		"profiles": profiles,
+		"offered":  offered,
 	})
 }
 
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SessionImageRequest 세션 이미지 교체 요청
+type SessionImageRequest struct {
+	Image string `json:"image" binding:"required"` // 카탈로그 이미지 이름 (GET /images)
+}
+
+// changeSessionImage 세션 컨테이너를 다른 카탈로그 이미지로 다시 만듦 (GPU와 대기열 자리는 유지)
+func (s *Server) changeSessionImage(c *gin.Context) {
+	sessionID := c.Param("id")
+	if !s.sessionAccessible(c, sessionID) {
+		return
+	}
+
+	var req SessionImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	session, err := s.sessionService.ChangeSessionImage(sessionID, requestActor(c), req.Image)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "세션 이미지 교체 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.sessionService.View(session))
+}
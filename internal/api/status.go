@@ -0,0 +1,87 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+// getStatus 사용자용 상태 페이지가 인증 없이 가져가는 공개 상태 (CDN/브라우저도 캐시 가능)
+func (s *Server) getStatus(c *gin.Context) {
+	status, err := s.sessionService.PublicStatus()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "상태 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(session.StatusCacheTTL.Seconds())))
+	c.JSON(http.StatusOK, status)
+}
+
+func (s *Server) createIncident(c *gin.Context) {
+	var req session.IncidentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	incident, err := s.sessionService.CreateIncident(req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, incident)
+}
+
+func (s *Server) listIncidents(c *gin.Context) {
+	incidents, err := s.sessionService.ListIncidents(c.Query("active") == "true")
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "공지 목록 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, incidents)
+}
+
+func (s *Server) updateIncident(c *gin.Context) {
+	var req session.IncidentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	incident, err := s.sessionService.UpdateIncident(c.Param("id"), req)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "공지 수정 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
+
+func (s *Server) resolveIncident(c *gin.Context) {
+	incident, err := s.sessionService.ResolveIncident(c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "공지 해결 처리 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
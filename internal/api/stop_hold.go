@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StopRequest 세션 중지 옵션
+type StopRequest struct {
+	// HoldMinutes GPU와 세션 기록을 잡아 둘 시간 (0이면 stop_hold_minutes, 최대 max_stop_hold_minutes)
+	HoldMinutes int `json:"hold_minutes"`
+}
+
+// stopSession 컨테이너를 멈추고 GPU를 잠시 잡아 둠 (다른 이미지로 다시 시작할 때)
+func (s *Server) stopSession(c *gin.Context) {
+	sessionID := c.Param("id")
+	if !s.sessionAccessible(c, sessionID) {
+		return
+	}
+
+	var req StopRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "잘못된 요청 형식: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	session, err := s.sessionService.StopSession(sessionID, requestActor(c), req.HoldMinutes)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "세션 중지 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.sessionService.View(session))
+}
+
+// startSession 멈춘 세션의 컨테이너를 새로 만들어 시작
+func (s *Server) startSession(c *gin.Context) {
+	sessionID := c.Param("id")
+	if !s.sessionAccessible(c, sessionID) {
+		return
+	}
+
+	session, err := s.sessionService.StartStoppedSession(sessionID, requestActor(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "세션 시작 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.sessionService.View(session))
+}
@@ -0,0 +1,190 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/auth"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+type createTeamRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type setTeamMemberRequest struct {
+	Role string `json:"role" binding:"required"`
+}
+
+type extendSessionRequest struct {
+	Minutes int `json:"minutes" binding:"required"`
+}
+
+func (s *Server) createTeam(c *gin.Context) {
+	var req createTeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	team, err := s.sessionService.CreateTeam(req.Name)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, team)
+}
+
+func (s *Server) listTeams(c *gin.Context) {
+	teams, err := s.sessionService.ListTeams()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "팀 목록 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, teams)
+}
+
+func (s *Server) getTeam(c *gin.Context) {
+	team, err := s.sessionService.GetTeam(c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "팀을 찾을 수 없습니다: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, team)
+}
+
+func (s *Server) deleteTeam(c *gin.Context) {
+	if err := s.sessionService.DeleteTeam(c.Param("id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "팀 삭제 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "팀이 삭제되었습니다",
+	})
+}
+
+func (s *Server) setTeamMember(c *gin.Context) {
+	var req setTeamMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	member, err := s.sessionService.SetTeamMember(c.Param("id"), c.Param("userId"), req.Role)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "팀 구성원 설정 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+func (s *Server) removeTeamMember(c *gin.Context) {
+	if err := s.sessionService.RemoveTeamMember(c.Param("id"), c.Param("userId")); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "팀 구성원 제거 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "팀 구성원이 제거되었습니다",
+	})
+}
+
+// listTeamSessions 팀 리드용 팀 구성원 세션 목록 (requireTeamLead 뒤에서 호출)
+func (s *Server) listTeamSessions(c *gin.Context) {
+	sessions, err := s.sessionService.ListTeamSessions(c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "팀 세션 목록 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.sessionService.Views(sessions))
+}
+
+func (s *Server) extendTeamSession(c *gin.Context) {
+	var req extendSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	session, ok := s.teamSession(c)
+	if !ok {
+		return
+	}
+
+	extended, err := s.sessionService.ExtendSession(session.ID, req.Minutes, "user:"+auth.TokenUser(currentToken(c)))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "세션 연장 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, s.sessionService.View(extended))
+}
+
+func (s *Server) deleteTeamSession(c *gin.Context) {
+	session, ok := s.teamSession(c)
+	if !ok {
+		return
+	}
+
+	if err := s.sessionService.DeleteSessionAs(session.ID, "user:"+auth.TokenUser(currentToken(c))); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "세션 삭제 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "세션이 성공적으로 삭제되었습니다",
+	})
+}
+
+// teamSession :sessionId 세션을 찾고 팀 리드가 관리할 수 있는 세션인지 RBAC로 확인
+func (s *Server) teamSession(c *gin.Context) (*store.Session, bool) {
+	session, err := s.sessionService.GetSession(c.Param("sessionId"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "세션을 찾을 수 없습니다: " + err.Error(),
+		})
+		return nil, false
+	}
+	setRequestUser(c, session.UserID)
+	if !allowSession(c, session.UserID, session.ProjectID) {
+		return nil, false
+	}
+
+	if err := s.authService.AuthorizeTeamSession(currentToken(c), c.Param("id"), session); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return nil, false
+	}
+	return session, true
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// traceIDHeader 샘플링된 요청의 트레이스 ID (트레이스 백엔드에서 찾을 때 사용)
+const traceIDHeader = "X-Trace-Id"
+
+// tracingMiddleware 들어온 traceparent를 이어 요청마다 서버 span을 만듦 (트레이싱이 꺼져 있으면 no-op)
+func tracingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		ctx, span := tracing.Start(ctx, c.Request.Method+" "+route,
+			attribute.String("http.method", c.Request.Method),
+			attribute.String("http.route", route),
+			attribute.String("sandman.request_id", c.GetString(requestIDKey)))
+		defer span.End()
+		if sc := span.SpanContext(); sc.IsSampled() {
+			c.Header(traceIDHeader, sc.TraceID().String())
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.status_code", status))
+		if status >= 500 {
+			span.SetStatus(codes.Error, strconv.Itoa(status))
+		}
+	}
+}
@@ -0,0 +1,19 @@
+package api
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+//go:embed ui
+var uiFiles embed.FS
+
+// uiFileSystem /ui 경로로 서빙할 내장 대시보드 정적 파일
+func uiFileSystem() http.FileSystem {
+	sub, err := fs.Sub(uiFiles, "ui")
+	if err != nil {
+		panic(err)
+	}
+	return http.FS(sub)
+}
@@ -0,0 +1,111 @@
+// Sandman 대시보드 - 기존 API(/overview, /sessions)만 사용
+(function () {
+  const POLL_MS = 2000;
+  const tokenInput = document.getElementById('token');
+  tokenInput.value = localStorage.getItem('sandman-token') || '';
+  tokenInput.addEventListener('change', () => {
+    localStorage.setItem('sandman-token', tokenInput.value);
+    refresh();
+  });
+
+  function api(method, path, body) {
+    const headers = { 'Content-Type': 'application/json' };
+    if (tokenInput.value) headers['Authorization'] = 'Bearer ' + tokenInput.value;
+    return fetch(path, { method, headers, body: body ? JSON.stringify(body) : undefined })
+      .then(async (res) => {
+        const data = res.status === 304 ? null : await res.json().catch(() => ({}));
+        if (!res.ok && res.status !== 304) throw new Error(data.error || res.statusText);
+        return data;
+      });
+  }
+
+  function text(value) {
+    const span = document.createElement('span');
+    span.textContent = value == null ? '' : String(value);
+    return span.innerHTML;
+  }
+
+  function renderAlerts(alerts) {
+    document.getElementById('alerts').innerHTML = (alerts || [])
+      .map((a) => `<div class="alert ${text(a.severity)}">${text(a.message)}</div>`)
+      .join('');
+  }
+
+  function renderDeployment(d) {
+    d = d || {};
+    const label = [d.name, d.environment].filter(Boolean).join(' · ');
+    document.getElementById('deployment').textContent = label;
+    if (d.name) document.title = 'Sandman - ' + d.name;
+  }
+
+  function renderGPUs(overview) {
+    const rows = Object.entries(overview.profile_availability || {})
+      .sort(([a], [b]) => a.localeCompare(b))
+      .map(([name, p]) => `<tr><td>${text(name)}</td><td>${p.available}</td><td>${p.total}</td></tr>`);
+    document.getElementById('gpu-rows').innerHTML = rows.join('') ||
+      '<tr><td colspan="3" class="muted">MIG 인스턴스가 없습니다</td></tr>';
+
+    const select = document.getElementById('profile-select');
+    const current = select.value;
+    select.innerHTML = Object.keys(overview.profile_availability || {}).sort()
+      .map((name) => `<option value="${text(name)}">${text(name)}</option>`).join('');
+    if (current) select.value = current;
+  }
+
+  function renderQueue(queue) {
+    document.getElementById('queue-list').innerHTML = queue
+      .map((q) => `<li>${text(q.user_id)} <span class="muted">${text(q.mig_profile)}</span></li>`)
+      .join('') || '<li class="muted">대기 중인 요청이 없습니다</li>';
+  }
+
+  function renderSessions(sessions) {
+    document.getElementById('session-count').textContent = `(${sessions.length})`;
+    document.getElementById('session-rows').innerHTML = sessions.map((s) => `
+      <tr>
+        <td>${text(s.user_id)}</td>
+        <td>${text(s.mig_profile)}</td>
+        <td>${text(s.ssh_port)}</td>
+        <td class="muted" title="${text(s.gpu_uuid)}">${text(s.gpu_name || s.gpu_uuid)}</td>
+        <td>${text(s.expires_at_local || s.expires_at)}</td>
+        <td><button class="danger" data-id="${text(s.id)}">삭제</button></td>
+      </tr>`).join('') || '<tr><td colspan="6" class="muted">활성 세션이 없습니다</td></tr>';
+  }
+
+  function refresh() {
+    api('GET', '/overview')
+      .then((overview) => {
+        if (!overview) return;
+        renderDeployment(overview.deployment);
+        renderAlerts((overview.deployment && overview.deployment.banner
+          ? [{ severity: 'info', message: overview.deployment.banner }] : []).concat(overview.alerts || []));
+        renderGPUs(overview);
+        renderQueue(overview.queue || []);
+        renderSessions(overview.sessions || []);
+        document.getElementById('updated').textContent = '갱신: ' + new Date().toLocaleTimeString();
+      })
+      .catch((err) => renderAlerts([{ severity: 'critical', message: '대시보드 갱신 실패: ' + err.message }]));
+  }
+
+  document.getElementById('session-rows').addEventListener('click', (e) => {
+    const id = e.target.dataset && e.target.dataset.id;
+    if (!id || !confirm('세션을 삭제할까요?')) return;
+    api('DELETE', '/sessions/' + encodeURIComponent(id)).then(refresh).catch((err) => alert(err.message));
+  });
+
+  document.getElementById('create-form').addEventListener('submit', (e) => {
+    e.preventDefault();
+    const form = new FormData(e.target);
+    const body = { user_id: form.get('user_id'), mig_profile: form.get('mig_profile') };
+    if (form.get('ttl_minutes')) body.ttl_minutes = Number(form.get('ttl_minutes'));
+
+    const result = document.getElementById('create-result');
+    result.classList.remove('hidden');
+    result.textContent = '생성 중...';
+    api('POST', '/sessions', body)
+      .then((res) => { result.textContent = JSON.stringify(res, null, 2); refresh(); })
+      .catch((err) => { result.textContent = '❌ ' + err.message; });
+  });
+
+  refresh();
+  setInterval(refresh, POLL_MS);
+})();
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getSessionUsage 세션 네트워크 사용량 (종료된 세션의 최종 보고 포함)
+func (s *Server) getSessionUsage(c *gin.Context) {
+	usage, err := s.sessionService.GetUsage(c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "사용량 기록을 찾을 수 없습니다: " + err.Error(),
+		})
+		return
+	}
+
+	if !allowSession(c, usage.UserID, usage.ProjectID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+// getSessionBuild 세션 컨테이너를 만들 때의 이미지 빌드 시간과 캐시 사용 (세션이 끝난 뒤에도 조회 가능)
+func (s *Server) getSessionBuild(c *gin.Context) {
+	build, err := s.sessionService.GetImageBuild(c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "이미지 빌드 기록을 찾을 수 없습니다: " + err.Error(),
+		})
+		return
+	}
+
+	if !allowSession(c, build.UserID, build.ProjectID) {
+		return
+	}
+
+	c.JSON(http.StatusOK, build)
+}
+
+// listUsage 사용자/프로젝트별 세션 사용량 보고 (?user_id=, ?project_id=)
+func (s *Server) listUsage(c *gin.Context) {
+	projectID := c.Query("project_id")
+	if token := currentToken(c); token != nil && token.ProjectID != "" {
+		if projectID == "" {
+			projectID = token.ProjectID
+		}
+		if !allowProject(c, projectID) {
+			return
+		}
+	}
+
+	userID, ok := scopedUser(c)
+	if !ok {
+		return
+	}
+
+	usages, err := s.sessionService.ListUsage(userID, projectID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "사용량 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	var rx, tx uint64
+	for _, usage := range usages {
+		rx += usage.RxBytes
+		tx += usage.TxBytes
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"usage":          usages,
+		"count":          len(usages),
+		"total_rx_bytes": rx,
+		"total_tx_bytes": tx,
+	})
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sandman/gpu-ssh-gateway/internal/auth"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+type updateUserSettingsRequest struct {
+	Timezone               string `json:"timezone"`
+	Locale                 string `json:"locale"`
+	WorkspacePolicy        string `json:"workspace_policy"` // keep, archive, delete (비어 있으면 배포 설정)
+	WorkspaceRetentionDays int    `json:"workspace_retention_days"`
+}
+
+func (s *Server) getUserSettings(c *gin.Context) {
+	setRequestUser(c, c.Param("id"))
+	settings, err := s.sessionService.GetUserSettings(c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "사용자 설정 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+func (s *Server) updateUserSettings(c *gin.Context) {
+	setRequestUser(c, c.Param("id"))
+	var req updateUserSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "잘못된 요청 형식: " + err.Error(),
+		})
+		return
+	}
+
+	settings := &store.UserSettings{
+		UserID:   c.Param("id"),
+		Timezone: req.Timezone,
+		Locale:   req.Locale,
+
+		WorkspacePolicy:        req.WorkspacePolicy,
+		WorkspaceRetentionDays: req.WorkspaceRetentionDays,
+	}
+	if err := s.sessionService.UpdateUserSettings(settings); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// getMyQuota 토큰 사용자(user:<id> 범위, 없으면 user_id 쿼리)의 동시 세션, MIG 인스턴스, TTL 한도와 사용량
+func (s *Server) getMyQuota(c *gin.Context) {
+	token := currentToken(c)
+	userID := auth.TokenUser(token)
+	if userID == "" {
+		userID = c.Query("user_id")
+	}
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "사용자를 알 수 없습니다: user:<id> 범위 토큰을 쓰거나 user_id를 지정하세요",
+		})
+		return
+	}
+	setRequestUser(c, userID)
+
+	projectID := c.Query("project_id")
+	if projectID == "" && token != nil {
+		projectID = token.ProjectID
+	}
+	if !allowProject(c, projectID) {
+		return
+	}
+
+	quota, err := s.sessionService.QuotaStatus(userID, projectID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "쿼터 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, quota)
+}
+
+// purgeUserData 사용자 데이터 삭제 (history, audit 쿼리로 user_purge 설정을 이 요청에만 덮어씀)
+func (s *Server) purgeUserData(c *gin.Context) {
+	setRequestUser(c, c.Param("id"))
+	policy := s.config.Current().UserPurge
+	if history := c.Query("history"); history != "" {
+		policy.History = history
+	}
+	if audit := c.Query("audit"); audit != "" {
+		policy.Audit = audit
+	}
+
+	report, err := s.sessionService.PurgeUserData(c.Param("id"), policy)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "사용자 데이터 삭제 실패: " + err.Error(),
+		})
+		return
+	}
+	if len(report.Errors) > 0 {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":  "일부 사용자 데이터를 삭제하지 못했습니다 (다시 요청하면 남은 항목을 처리합니다)",
+			"report": report,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
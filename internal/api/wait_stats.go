@@ -0,0 +1,28 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultWaitWindows /stats/wait-times의 기본 구간
+const defaultWaitWindows = "1h,24h,7d"
+
+// getWaitTimeStats 프로파일별 요청부터 실행까지 걸린 시간의 p50/p95 (?windows=1h,24h,7d&profile=3g.40gb)
+func (s *Server) getWaitTimeStats(c *gin.Context) {
+	windows := c.DefaultQuery("windows", defaultWaitWindows)
+
+	stats, err := s.sessionService.WaitTimeStats(strings.Split(windows, ","), c.Query("profile"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "대기 시간 통계 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"windows": stats,
+	})
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// listWorkspaceLifecycle 보관 예약 중이거나 보관된 워크스페이스
+func (s *Server) listWorkspaceLifecycle(c *gin.Context) {
+	entries, err := s.sessionService.ListWorkspaceLifecycle()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "워크스페이스 수명 주기 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"workspaces": entries,
+		"count":      len(entries),
+	})
+}
+
+// listWorkspaceVolumes 볼륨 백엔드 배포의 사용자 워크스페이스 볼륨
+func (s *Server) listWorkspaceVolumes(c *gin.Context) {
+	volumes, err := s.sessionService.ListWorkspaceVolumes()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "워크스페이스 볼륨 조회 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"volumes": volumes,
+		"count":   len(volumes),
+	})
+}
+
+// deleteWorkspaceVolume 세션이 없는 사용자의 워크스페이스 볼륨 삭제
+func (s *Server) deleteWorkspaceVolume(c *gin.Context) {
+	if err := s.sessionService.DeleteWorkspaceVolume(c.Param("id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": "워크스페이스 볼륨 삭제 실패: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "워크스페이스 볼륨이 삭제되었습니다",
+	})
+}
@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// TokenPrefix 발급되는 토큰 앞에 붙는 접두사 (로그/설정 파일에서 토큰을 식별하기 위함)
+const TokenPrefix = "sgw_"
+
+type Service struct {
+	store store.Store
+}
+
+func NewService(store store.Store) *Service {
+	return &Service{store: store}
+}
+
+// IssueToken 새 토큰을 생성해 해시를 저장하고, 평문 토큰은 호출자에게 한 번만 반환
+func (s *Service) IssueToken(name, projectID string, scopes []string) (*store.APIToken, string, error) {
+	if name == "" {
+		return nil, "", errs.New(errs.ErrInvalid, "토큰 이름이 필요합니다")
+	}
+
+	raw, err := generateToken()
+	if err != nil {
+		return nil, "", fmt.Errorf("토큰 생성 실패: %v", err)
+	}
+
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	token := &store.APIToken{
+		ID:        uuid.New().String(),
+		Name:      name,
+		ProjectID: projectID,
+		TokenHash: HashToken(raw),
+		Scopes:    scopes,
+		CreatedAt: time.Now(),
+	}
+	if err := s.store.CreateAPIToken(token); err != nil {
+		return nil, "", fmt.Errorf("토큰 저장 실패: %w", err)
+	}
+
+	log.Printf("🔑 API 토큰 발급: %s (이름: %s, 프로젝트: %s)", token.ID, name, projectID)
+	return token, raw, nil
+}
+
+// Authenticate 평문 토큰을 검증하고 해당 토큰 정보를 반환
+func (s *Service) Authenticate(raw string) (*store.APIToken, error) {
+	token, err := s.store.GetAPITokenByHash(HashToken(raw))
+	if err != nil {
+		return nil, fmt.Errorf("유효하지 않은 토큰입니다")
+	}
+	if token.RevokedAt != nil {
+		return nil, fmt.Errorf("폐기된 토큰입니다")
+	}
+	return token, nil
+}
+
+func (s *Service) ListTokens(projectID string) ([]*store.APIToken, error) {
+	return s.store.ListAPITokens(projectID)
+}
+
+func (s *Service) RevokeToken(id string) error {
+	if err := s.store.RevokeAPIToken(id); err != nil {
+		return fmt.Errorf("토큰 %s 폐기 실패: %w", id, err)
+	}
+	log.Printf("🔒 API 토큰 폐기: %s", id)
+	return nil
+}
+
+// HashToken 토큰 평문의 SHA-256 해시 (저장/조회용)
+func HashToken(raw string) string {
+	sum := sha256.Sum256([]byte(raw))
+	return hex.EncodeToString(sum[:])
+}
+
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return TokenPrefix + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,93 @@
+package auth
+
+import (
+	"strings"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// TokenUser 토큰이 대표하는 사용자 (user:<id> 범위가 없으면 "")
+func TokenUser(token *store.APIToken) string {
+	if token == nil {
+		return ""
+	}
+	for _, scope := range token.Scopes {
+		if userID, ok := strings.CutPrefix(scope, "user:"); ok && userID != "" {
+			return userID
+		}
+	}
+	return ""
+}
+
+// TeamRole 토큰 사용자의 팀 내 역할 (구성원이 아니면 "")
+func (s *Service) TeamRole(token *store.APIToken, teamID string) (string, error) {
+	userID := TokenUser(token)
+	if userID == "" {
+		return "", nil
+	}
+	teams, err := s.store.ListUserTeams(userID)
+	if err != nil {
+		return "", err
+	}
+	for _, member := range teams {
+		if member.TeamID == teamID {
+			return member.Role, nil
+		}
+	}
+	return "", nil
+}
+
+// AuthorizeTeamLead 토큰 사용자가 팀의 리드인지 확인 (팀 단위 조회/관리 API의 공통 검사)
+func (s *Service) AuthorizeTeamLead(token *store.APIToken, teamID string) error {
+	if _, err := s.store.GetTeam(teamID); err != nil {
+		return errs.New(errs.ErrNotFound, "팀 %s를 찾을 수 없습니다", teamID)
+	}
+	if TokenUser(token) == "" {
+		return errs.New(errs.ErrForbidden, "팀 리드의 사용자 토큰(user:<id> 범위)이 필요합니다")
+	}
+	role, err := s.TeamRole(token, teamID)
+	if err != nil {
+		return err
+	}
+	if role != store.RoleLead {
+		return errs.New(errs.ErrForbidden, "사용자 %s는 팀 %s의 리드가 아닙니다", TokenUser(token), teamID)
+	}
+	return nil
+}
+
+// AuthorizeTeamSession 팀 리드가 자기 팀 구성원의 세션을 관리할 수 있는지 확인 (다른 팀 세션은 거부)
+func (s *Service) AuthorizeTeamSession(token *store.APIToken, teamID string, session *store.Session) error {
+	if err := s.AuthorizeTeamLead(token, teamID); err != nil {
+		return err
+	}
+	members, err := s.store.ListTeamMembers(teamID)
+	if err != nil {
+		return err
+	}
+	for _, member := range members {
+		if member.UserID == session.UserID {
+			return nil
+		}
+	}
+	return errs.New(errs.ErrForbidden, "세션 %s의 소유자 %s는 팀 %s의 구성원이 아닙니다", session.ID, session.UserID, teamID)
+}
+
+// UserTeams 사용자가 속한 팀의 ID와 이름 (허가 규칙 입력)
+func (s *Service) UserTeams(userID string) (ids, names []string, err error) {
+	ids, names = []string{}, []string{}
+	if userID == "" {
+		return ids, names, nil
+	}
+	members, err := s.store.ListUserTeams(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, member := range members {
+		ids = append(ids, member.TeamID)
+		if team, err := s.store.GetTeam(member.TeamID); err == nil {
+			names = append(names, team.Name)
+		}
+	}
+	return ids, names, nil
+}
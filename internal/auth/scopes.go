@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// ScopeCredentialsRead 다른 사용자 세션의 SSH 비밀번호 등 자격 증명 조회 허용
+const ScopeCredentialsRead = "credentials:read"
+
+// ScopeCredentialsRotate 다른 사용자 세션의 SSH 키 교체 허용
+const ScopeCredentialsRotate = "credentials:rotate"
+
+// UserScope 토큰이 특정 사용자를 대표함을 나타내는 범위 (세션 소유자 판단에 사용)
+func UserScope(userID string) string {
+	return "user:" + userID
+}
+
+// HasScope 토큰에 범위가 있는지 확인 (nil 토큰은 항상 false)
+func HasScope(token *store.APIToken, scope string) bool {
+	if token == nil {
+		return false
+	}
+	for _, s := range token.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ScopeAdmin 관리 API(/admin/*, 전체 세션 삭제, 조직/팀 관리)와 모든 사용자의 세션 접근 허용
+const ScopeAdmin = "admin"
+
+// IsAdmin 관리자 키인지 확인
+func IsAdmin(token *store.APIToken) bool {
+	return HasScope(token, ScopeAdmin)
+}
+
+// CanGrant 토큰이 새 키에 범위를 부여할 수 있는지 확인하고 새 키에 저장할 범위를 반환 (자기가 가진 범위만, 관리자는 모두)
+// 사용자 키가 만든 키에는 그 사용자 범위(user:<id>)를 항상 붙여 발급한 키보다 넓은 세션/사용자에 접근하지 못하게 함
+// 토큰 없이 발급하는 경우(인증을 요구하지 않는 배포)에도 관리자 범위는 부여할 수 없음
+func CanGrant(token *store.APIToken, scopes []string) ([]string, error) {
+	if IsAdmin(token) {
+		return scopes, nil
+	}
+	for _, scope := range scopes {
+		if scope == ScopeAdmin {
+			return nil, errs.New(errs.ErrForbidden, "관리자 범위는 관리자 키로만 부여할 수 있습니다")
+		}
+		if token != nil && !HasScope(token, scope) {
+			return nil, errs.New(errs.ErrForbidden, "가지고 있지 않은 범위는 부여할 수 없습니다: %s", scope)
+		}
+	}
+	if userID := TokenUser(token); userID != "" && !contains(scopes, UserScope(userID)) {
+		scopes = append(append([]string{}, scopes...), UserScope(userID))
+	}
+	return scopes, nil
+}
+
+// CanRevoke 토큰이 다른 키를 폐기할 수 있는지 확인 (사용자 키는 같은 사용자 범위의 키만, 관리자는 모두)
+func CanRevoke(token, target *store.APIToken) error {
+	if token == nil || IsAdmin(token) {
+		return nil
+	}
+	if userID := TokenUser(token); userID != "" && !HasScope(target, UserScope(userID)) {
+		return errs.New(errs.ErrForbidden, "사용자 %s의 키로 다른 사용자의 키를 폐기할 수 없습니다", userID)
+	}
+	return nil
+}
+
+// RoleScopes 키 역할(admin, user)과 추가 범위로 저장할 범위 목록 구성
+// user 역할은 사용자 ID가 필요하며 그 사용자의 세션만 다룰 수 있음
+func RoleScopes(role, userID string, extra []string) ([]string, error) {
+	scopes := []string{}
+	switch role {
+	case "admin":
+		scopes = append(scopes, ScopeAdmin)
+		if userID != "" {
+			scopes = append(scopes, UserScope(userID))
+		}
+	case "user":
+		if userID == "" {
+			return nil, errs.New(errs.ErrInvalid, "user 역할 키에는 user_id가 필요합니다")
+		}
+		scopes = append(scopes, UserScope(userID))
+	default:
+		return nil, errs.New(errs.ErrInvalid, "역할은 admin 또는 user여야 합니다: %q", role)
+	}
+	for _, scope := range extra {
+		if scope != "" && !contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+// AuthorizeSession 관리자가 아닌 키는 자기 세션(user:<id>)과 자기 프로젝트의 세션만 다룰 수 있음
+func AuthorizeSession(token *store.APIToken, session *store.Session) error {
+	if token == nil || IsAdmin(token) {
+		return nil
+	}
+	if userID := TokenUser(token); userID != "" && userID != session.UserID {
+		return errs.New(errs.ErrForbidden, "세션 %s는 사용자 %s의 세션이 아닙니다", session.ID, userID)
+	}
+	if token.ProjectID != "" && token.ProjectID != session.ProjectID {
+		return errs.New(errs.ErrForbidden, "토큰이 프로젝트 %s 범위로 제한되어 있습니다", token.ProjectID)
+	}
+	return nil
+}
+
+// AuthorizeUser 관리자가 아닌 사용자 키는 자기 사용자 ID로만 요청할 수 있음
+func AuthorizeUser(token *store.APIToken, userID string) error {
+	if token == nil || IsAdmin(token) {
+		return nil
+	}
+	if tokenUser := TokenUser(token); tokenUser != "" && tokenUser != userID {
+		return errs.New(errs.ErrForbidden, "사용자 %s의 키로 사용자 %s의 요청을 할 수 없습니다", tokenUser, userID)
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,242 @@
+package capacity
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+const (
+	SeasonalityHourOfWeek = "hour_of_week"
+	SeasonalityHourOfDay  = "hour_of_day"
+
+	// weeklyLookbackDays 요일별 패턴을 쓰기 위한 최소 과거 기간 (요일·시각마다 표본 2개 이상)
+	weeklyLookbackDays = 14
+	MaxLookbackDays    = 90
+	MaxHorizonHours    = 72
+)
+
+// Occupancy 세션 하나가 프로파일 인스턴스를 점유한 구간
+type Occupancy struct {
+	Profile string
+	Start   time.Time
+	End     time.Time
+}
+
+// ForecastInput 예측 입력
+// History는 종료된 세션과 실행 중인 세션(End=Now), Committed는 실행 중인 세션의 만료 시각까지 구간
+type ForecastInput struct {
+	History      []Occupancy
+	Committed    []Occupancy
+	Capacity     map[string]int
+	Now          time.Time
+	Location     *time.Location
+	LookbackDays int
+	HorizonHours int
+}
+
+// HourForecast 한 시간 구간의 예상 수요
+type HourForecast struct {
+	Hour             time.Time `json:"hour"`
+	ExpectedArrivals float64   `json:"expected_arrivals"` // 이 시간에 새로 시작할 것으로 예상되는 세션 수
+	ExpectedDemand   float64   `json:"expected_demand"`   // 이 시간의 평균 동시 세션 수
+	Committed        int       `json:"committed"`         // 이 시간에도 만료되지 않는 실행 중 세션 수
+	Capacity         int       `json:"capacity"`
+}
+
+// ProfileForecast 프로파일별 예측
+type ProfileForecast struct {
+	Profile      string         `json:"profile"`
+	Capacity     int            `json:"capacity"`
+	Current      int            `json:"current"`
+	PeakDemand   float64        `json:"peak_demand"`
+	PeakAt       time.Time      `json:"peak_at"`
+	ExhaustionAt *time.Time     `json:"exhaustion_at,omitempty"` // 예상 수요가 용량에 처음 닿는 시간 (없으면 예측 기간 안에 소진되지 않음)
+	ExpectWaits  bool           `json:"expect_waits"`
+	Hours        []HourForecast `json:"hours"`
+}
+
+// Forecast 예측 결과
+type Forecast struct {
+	GeneratedAt  time.Time          `json:"generated_at"`
+	Timezone     string             `json:"timezone"`
+	LookbackDays int                `json:"lookback_days"`
+	HorizonHours int                `json:"horizon_hours"`
+	Seasonality  string             `json:"seasonality"`
+	ExpectWaits  bool               `json:"expect_waits"`
+	FirstWaitAt  *time.Time         `json:"first_wait_at,omitempty"` // 가장 먼저 소진되는 프로파일의 소진 시간
+	Profiles     []*ProfileForecast `json:"profiles"`
+}
+
+// ValidateForecastRange 과거 기간과 예측 기간 범위 확인
+func ValidateForecastRange(lookbackDays, horizonHours int) error {
+	if lookbackDays < 1 || lookbackDays > MaxLookbackDays {
+		return fmt.Errorf("lookback_days는 1~%d여야 합니다: %d", MaxLookbackDays, lookbackDays)
+	}
+	if horizonHours < 1 || horizonHours > MaxHorizonHours {
+		return fmt.Errorf("horizon_hours는 1~%d여야 합니다: %d", MaxHorizonHours, horizonHours)
+	}
+	return nil
+}
+
+// seasonalStats 시즌 키(요일·시각 또는 시각)별 누적값
+type seasonalStats struct {
+	arrivals  map[int]float64
+	occupancy map[int]float64
+}
+
+// BuildForecast 과거 수요를 시간대 패턴으로 평균 내 앞으로 horizon 시간의 수요와 소진 시각을 예측
+// 과거 기간이 14일 이상이면 요일·시각별, 짧으면 시각별 평균을 사용하고,
+// 예상 수요는 실행 중인 세션 중 아직 만료되지 않는 수보다 작아지지 않음
+func BuildForecast(in ForecastInput) (*Forecast, error) {
+	if err := ValidateForecastRange(in.LookbackDays, in.HorizonHours); err != nil {
+		return nil, err
+	}
+	loc := in.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	seasonality := SeasonalityHourOfDay
+	if in.LookbackDays >= weeklyLookbackDays {
+		seasonality = SeasonalityHourOfWeek
+	}
+	key := func(t time.Time) int {
+		t = t.In(loc)
+		if seasonality == SeasonalityHourOfWeek {
+			return int(t.Weekday())*24 + t.Hour()
+		}
+		return t.Hour()
+	}
+
+	local := in.Now.In(loc)
+	currentHour := time.Date(local.Year(), local.Month(), local.Day(), local.Hour(), 0, 0, 0, loc)
+	windowStart := currentHour.AddDate(0, 0, -in.LookbackDays)
+
+	// 과거 기간의 시즌 키별 표본(시간 구간) 수
+	samples := make(map[int]int)
+	for t := windowStart; t.Before(currentHour); t = t.Add(time.Hour) {
+		samples[key(t)]++
+	}
+
+	stats := make(map[string]*seasonalStats)
+	profileStats := func(profile string) *seasonalStats {
+		st, ok := stats[profile]
+		if !ok {
+			st = &seasonalStats{arrivals: make(map[int]float64), occupancy: make(map[int]float64)}
+			stats[profile] = st
+		}
+		return st
+	}
+	for name := range in.Capacity {
+		profileStats(name)
+	}
+
+	for _, occ := range in.History {
+		st := profileStats(occ.Profile)
+		if !occ.Start.Before(windowStart) && occ.Start.Before(currentHour) {
+			st.arrivals[key(occ.Start)]++
+		}
+
+		start, end := occ.Start, occ.End
+		if start.Before(windowStart) {
+			start = windowStart
+		}
+		if end.After(currentHour) {
+			end = currentHour
+		}
+		// 시간 구간별로 겹친 비율만큼 점유량 누적
+		for start.Before(end) {
+			s := start.In(loc)
+			next := time.Date(s.Year(), s.Month(), s.Day(), s.Hour(), 0, 0, 0, loc).Add(time.Hour)
+			if next.After(end) {
+				next = end
+			}
+			st.occupancy[key(start)] += next.Sub(start).Hours()
+			start = next
+		}
+	}
+
+	current := make(map[string]int)
+	for _, occ := range in.Committed {
+		current[occ.Profile]++
+		profileStats(occ.Profile)
+	}
+
+	forecast := &Forecast{
+		GeneratedAt:  in.Now,
+		Timezone:     loc.String(),
+		LookbackDays: in.LookbackDays,
+		HorizonHours: in.HorizonHours,
+		Seasonality:  seasonality,
+		Profiles:     []*ProfileForecast{},
+	}
+
+	for profile, st := range stats {
+		pf := &ProfileForecast{
+			Profile:  profile,
+			Capacity: in.Capacity[profile],
+			Current:  current[profile],
+			Hours:    make([]HourForecast, 0, in.HorizonHours),
+		}
+		for i := 0; i < in.HorizonHours; i++ {
+			hour := currentHour.Add(time.Duration(i) * time.Hour)
+			k := key(hour)
+
+			hf := HourForecast{Hour: hour, Capacity: pf.Capacity}
+			if n := samples[k]; n > 0 {
+				hf.ExpectedArrivals = round2(st.arrivals[k] / float64(n))
+				hf.ExpectedDemand = round2(st.occupancy[k] / float64(n))
+			}
+			for _, occ := range in.Committed {
+				if occ.Profile == profile && occ.End.After(hour) {
+					hf.Committed++
+				}
+			}
+			if float64(hf.Committed) > hf.ExpectedDemand {
+				hf.ExpectedDemand = float64(hf.Committed)
+			}
+
+			if hf.ExpectedDemand > pf.PeakDemand || i == 0 {
+				pf.PeakDemand, pf.PeakAt = hf.ExpectedDemand, hour
+			}
+			if pf.ExhaustionAt == nil && hf.ExpectedDemand > 0 && hf.ExpectedDemand >= float64(pf.Capacity) {
+				at := hour
+				pf.ExhaustionAt = &at
+				pf.ExpectWaits = true
+			}
+			pf.Hours = append(pf.Hours, hf)
+		}
+
+		if pf.ExhaustionAt != nil {
+			forecast.ExpectWaits = true
+			if forecast.FirstWaitAt == nil || pf.ExhaustionAt.Before(*forecast.FirstWaitAt) {
+				at := *pf.ExhaustionAt
+				forecast.FirstWaitAt = &at
+			}
+		}
+		forecast.Profiles = append(forecast.Profiles, pf)
+	}
+
+	sort.Slice(forecast.Profiles, func(i, j int) bool { return forecast.Profiles[i].Profile < forecast.Profiles[j].Profile })
+	return forecast, nil
+}
+
+// ForProfile 한 프로파일만 남긴 예측 (전체 대기 예상 여부도 그 프로파일 기준으로 다시 계산)
+func (f *Forecast) ForProfile(profile string) *Forecast {
+	filtered := *f
+	filtered.Profiles = []*ProfileForecast{}
+	filtered.ExpectWaits, filtered.FirstWaitAt = false, nil
+	for _, pf := range f.Profiles {
+		if pf.Profile != profile {
+			continue
+		}
+		filtered.Profiles = append(filtered.Profiles, pf)
+		filtered.ExpectWaits, filtered.FirstWaitAt = pf.ExpectWaits, pf.ExhaustionAt
+	}
+	return &filtered
+}
+
+func round2(v float64) float64 {
+	return float64(int64(v*100+0.5)) / 100
+}
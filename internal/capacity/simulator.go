@@ -0,0 +1,182 @@
+package capacity
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+)
+
+const (
+	// gpuComputeSlices MIG가 나눌 수 있는 GPU당 연산 슬라이스 수 (A100/H100)
+	gpuComputeSlices = 7
+	// memSliceGB 프로파일의 MemSlice 한 칸에 해당하는 메모리 (7g.80gb = 16칸)
+	memSliceGB = 5
+	// defaultGPUMemoryGB 노드 계획에 메모리를 지정하지 않았을 때 가정하는 GPU (H100 80GB)
+	defaultGPUMemoryGB = 80
+)
+
+// Layout GPU 한 장을 나누는 방법 (프로파일별 인스턴스 수)
+type Layout map[string]int
+
+// NodePlan 가상의 노드 구성 (기존 노드 ID면 그 노드의 용량을 이 구성으로 대체)
+type NodePlan struct {
+	ID          string `json:"id"`
+	GPUs        int    `json:"gpus"`
+	GPUMemoryGB int    `json:"gpu_memory_gb,omitempty"`
+	Layout      Layout `json:"layout"`
+}
+
+// Scenario what-if 입력: 노드 추가/재분할과 추가로 가정할 수요
+type Scenario struct {
+	Nodes  []NodePlan     `json:"nodes"`
+	Demand map[string]int `json:"demand,omitempty"` // 프로파일별 추가 세션 수
+}
+
+// Snapshot 현재 상태: 노드별 프로파일별 전체 인스턴스 수와 프로파일별 수요
+type Snapshot struct {
+	Capacity map[string]map[string]int
+	Demand   map[string]int
+}
+
+// ProfileProjection 프로파일별 현재/예상 용량과 수요
+type ProfileProjection struct {
+	Profile            string `json:"profile"`
+	Demand             int    `json:"demand"`
+	CurrentCapacity    int    `json:"current_capacity"`
+	ProjectedCapacity  int    `json:"projected_capacity"`
+	CurrentShortfall   int    `json:"current_shortfall"`
+	ProjectedShortfall int    `json:"projected_shortfall"`
+}
+
+// Result 시뮬레이션 결과
+type Result struct {
+	Profiles           []*ProfileProjection `json:"profiles"`
+	CurrentCapacity    int                  `json:"current_capacity"`
+	ProjectedCapacity  int                  `json:"projected_capacity"`
+	Demand             int                  `json:"demand"`
+	CurrentShortfall   int                  `json:"current_shortfall"`
+	ProjectedShortfall int                  `json:"projected_shortfall"`
+	ReplacedNodes      []string             `json:"replaced_nodes"`
+	AddedNodes         []string             `json:"added_nodes"`
+}
+
+// ValidateLayout 레이아웃이 GPU 한 장의 연산/메모리 슬라이스 안에 들어가는지 확인
+func ValidateLayout(layout Layout, gpuMemoryGB int, profiles map[string]gpu.MIGProfile) error {
+	if len(layout) == 0 {
+		return fmt.Errorf("레이아웃이 비어 있습니다")
+	}
+	memSlices := gpuMemoryGB / memSliceGB
+
+	var compute, memory int
+	for name, count := range layout {
+		profile, ok := profiles[name]
+		if !ok {
+			return fmt.Errorf("알 수 없는 MIG 프로파일입니다: %s", name)
+		}
+		if count < 0 {
+			return fmt.Errorf("프로파일 %s 인스턴스 수가 음수입니다", name)
+		}
+		compute += profile.GPUSlice * count
+		memory += profile.MemSlice * count
+	}
+	if compute > gpuComputeSlices {
+		return fmt.Errorf("연산 슬라이스 %d개가 GPU당 최대 %d개를 넘습니다", compute, gpuComputeSlices)
+	}
+	if memory > memSlices {
+		return fmt.Errorf("메모리 %dGB가 GPU 메모리 %dGB를 넘습니다", memory*memSliceGB, gpuMemoryGB)
+	}
+	return nil
+}
+
+// Simulate 현재 상태에 시나리오를 적용했을 때 프로파일별 용량과 부족분 계산
+func Simulate(current Snapshot, scenario Scenario, profiles map[string]gpu.MIGProfile) (*Result, error) {
+	projected := make(map[string]map[string]int, len(current.Capacity))
+	for node, capacity := range current.Capacity {
+		projected[node] = capacity
+	}
+
+	result := &Result{Profiles: []*ProfileProjection{}, ReplacedNodes: []string{}, AddedNodes: []string{}}
+	seen := make(map[string]bool)
+	for _, plan := range scenario.Nodes {
+		if plan.ID == "" {
+			return nil, fmt.Errorf("노드 계획에 id가 없습니다")
+		}
+		if seen[plan.ID] {
+			return nil, fmt.Errorf("노드 %s가 시나리오에 두 번 나옵니다", plan.ID)
+		}
+		seen[plan.ID] = true
+		if plan.GPUs <= 0 {
+			return nil, fmt.Errorf("노드 %s의 gpus는 0보다 커야 합니다", plan.ID)
+		}
+		if plan.GPUMemoryGB == 0 {
+			plan.GPUMemoryGB = defaultGPUMemoryGB
+		}
+		if err := ValidateLayout(plan.Layout, plan.GPUMemoryGB, profiles); err != nil {
+			return nil, fmt.Errorf("노드 %s 레이아웃이 잘못되었습니다: %v", plan.ID, err)
+		}
+
+		capacity := make(map[string]int, len(plan.Layout))
+		for name, count := range plan.Layout {
+			capacity[name] = count * plan.GPUs
+		}
+		if _, exists := current.Capacity[plan.ID]; exists {
+			result.ReplacedNodes = append(result.ReplacedNodes, plan.ID)
+		} else {
+			result.AddedNodes = append(result.AddedNodes, plan.ID)
+		}
+		projected[plan.ID] = capacity
+	}
+
+	demand := make(map[string]int, len(current.Demand))
+	for name, count := range current.Demand {
+		demand[name] += count
+	}
+	for name, count := range scenario.Demand {
+		if _, ok := profiles[name]; !ok {
+			return nil, fmt.Errorf("알 수 없는 MIG 프로파일입니다: %s", name)
+		}
+		if count < 0 {
+			return nil, fmt.Errorf("프로파일 %s 수요가 음수입니다", name)
+		}
+		demand[name] += count
+	}
+
+	currentTotals, projectedTotals := sumByProfile(current.Capacity), sumByProfile(projected)
+	names := make(map[string]bool)
+	for _, m := range []map[string]int{currentTotals, projectedTotals, demand} {
+		for name := range m {
+			names[name] = true
+		}
+	}
+
+	for name := range names {
+		p := &ProfileProjection{
+			Profile:           name,
+			Demand:            demand[name],
+			CurrentCapacity:   currentTotals[name],
+			ProjectedCapacity: projectedTotals[name],
+		}
+		p.CurrentShortfall = max(p.Demand-p.CurrentCapacity, 0)
+		p.ProjectedShortfall = max(p.Demand-p.ProjectedCapacity, 0)
+
+		result.Profiles = append(result.Profiles, p)
+		result.Demand += p.Demand
+		result.CurrentCapacity += p.CurrentCapacity
+		result.ProjectedCapacity += p.ProjectedCapacity
+		result.CurrentShortfall += p.CurrentShortfall
+		result.ProjectedShortfall += p.ProjectedShortfall
+	}
+	sort.Slice(result.Profiles, func(i, j int) bool { return result.Profiles[i].Profile < result.Profiles[j].Profile })
+	return result, nil
+}
+
+func sumByProfile(capacity map[string]map[string]int) map[string]int {
+	totals := make(map[string]int)
+	for _, node := range capacity {
+		for name, count := range node {
+			totals[name] += count
+		}
+	}
+	return totals
+}
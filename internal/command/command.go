@@ -0,0 +1,239 @@
+// Package command 외부 명령(nvidia-smi, git, 정리 훅, litestream restore) 실행
+//
+// 모든 실행에 제한 시간과 출력 크기 상한을 두고, 명령/걸린 시간/종료 코드를 디버그 로그와 메트릭으로 남긴다.
+// 자식 프로세스는 오케스트레이터 환경 대신 최소한의 환경 변수로, 별도 프로세스 그룹에서 실행하고
+// 제한 시간이 지나면 그룹 전체를 종료한다. litestream replicate처럼 계속 실행되는 프로세스는 대상이 아니다.
+package command
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+)
+
+const (
+	DefaultTimeout   = 30 * time.Second
+	DefaultMaxOutput = 4 << 20 // 스트림별 최대 바이트 (넘는 출력은 버림)
+
+	// waitDelay 프로세스를 죽인 뒤 출력 파이프가 닫히기를 기다리는 시간 (손자 프로세스가 파이프를 잡고 있을 때)
+	waitDelay = 5 * time.Second
+	// errorTail 오류 메시지에 붙이는 stderr 끝부분 길이
+	errorTail = 512
+)
+
+var (
+	commandRuns = metrics.NewCounter("sandman_external_commands_total",
+		"외부 명령 실행 결과 (result: ok, failed, timeout, error)", "command", "result")
+	commandSeconds   = metrics.NewHistogram("sandman_external_command_seconds", "외부 명령 실행 시간", nil, "command")
+	commandTruncated = metrics.NewCounter("sandman_external_command_output_truncated_total",
+		"출력 크기 상한을 넘어 잘린 실행 수", "command")
+)
+
+// passEnv Env를 지정하지 않은 명령도 물려받는 환경 변수 (나머지 오케스트레이터 환경은 넘기지 않음)
+var passEnv = []string{"PATH", "HOME", "LANG", "LC_ALL", "TZ", "HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY",
+	"http_proxy", "https_proxy", "no_proxy"}
+
+// Options 실행 옵션 (0 값이면 기본값)
+type Options struct {
+	Timeout    time.Duration // 0이면 ctx의 남은 시간, ctx에 기한이 없으면 DefaultTimeout
+	MaxOutput  int           // 0이면 DefaultMaxOutput
+	Dir        string
+	Env        []string  // passEnv에 더할 변수 ("KEY=value")
+	InheritEnv bool      // 오케스트레이터 환경 전체를 물려줌 (클라우드 자격 증명을 환경 변수로 읽는 도구)
+	Stdin      io.Reader // nil이면 /dev/null
+	Combined   bool      // stderr를 stdout과 같은 버퍼에 순서대로 기록
+}
+
+// Result 실행 결과 (오류가 나도 가능한 만큼 채움)
+type Result struct {
+	Stdout    []byte
+	Stderr    []byte // Combined면 비어 있음
+	ExitCode  int    // 시작하지 못했거나 시그널로 끝났으면 -1
+	Duration  time.Duration
+	Truncated bool
+}
+
+// Error 명령이 0이 아닌 코드로 끝났거나 제한 시간을 넘김
+type Error struct {
+	Command  string
+	ExitCode int
+	TimedOut bool
+	Timeout  time.Duration
+	Output   string // stderr(Combined면 출력) 끝부분
+}
+
+func (e *Error) Error() string {
+	var msg string
+	if e.TimedOut {
+		msg = fmt.Sprintf("%s: 제한 시간 %v 초과", e.Command, e.Timeout)
+	} else {
+		msg = fmt.Sprintf("%s: exit status %d", e.Command, e.ExitCode)
+	}
+	if e.Output != "" {
+		msg += ": " + e.Output
+	}
+	return msg
+}
+
+// IsTimeout 제한 시간 초과로 끝난 오류인지
+func IsTimeout(err error) bool {
+	var cmdErr *Error
+	return errors.As(err, &cmdErr) && cmdErr.TimedOut
+}
+
+// Run 명령을 실행하고 끝날 때까지 기다림 (ctx가 먼저 취소되면 그때 종료)
+func Run(ctx context.Context, opts Options, name string, args ...string) (*Result, error) {
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout = time.Until(deadline)
+		}
+	}
+	maxOutput := opts.MaxOutput
+	if maxOutput <= 0 {
+		maxOutput = DefaultMaxOutput
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Dir = opts.Dir
+	cmd.Env = environ(opts)
+	cmd.Stdin = opts.Stdin
+	cmd.WaitDelay = waitDelay
+	isolate(cmd)
+
+	stdout := &limitedBuffer{limit: maxOutput}
+	stderr := stdout
+	cmd.Stdout = stdout
+	if !opts.Combined {
+		stderr = &limitedBuffer{limit: maxOutput}
+	}
+	cmd.Stderr = stderr
+
+	label := filepath.Base(name)
+	start := time.Now()
+	err := cmd.Run()
+	result := &Result{
+		Stdout:    stdout.Bytes(),
+		ExitCode:  -1,
+		Duration:  time.Since(start),
+		Truncated: stdout.truncated || stderr.truncated,
+	}
+	if !opts.Combined {
+		result.Stderr = stderr.Bytes()
+	}
+	if cmd.ProcessState != nil {
+		result.ExitCode = cmd.ProcessState.ExitCode()
+	}
+	commandSeconds.Observe(result.Duration.Seconds(), label)
+	if result.Truncated {
+		commandTruncated.Inc(label)
+	}
+
+	outcome := "ok"
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		outcome = "timeout"
+		err = &Error{Command: label, ExitCode: result.ExitCode, TimedOut: true, Timeout: timeout, Output: tail(stderr.Bytes())}
+	case err != nil && cmd.ProcessState != nil:
+		outcome = "failed"
+		err = &Error{Command: label, ExitCode: result.ExitCode, Output: tail(stderr.Bytes())}
+	case err != nil:
+		// 실행 파일이 없거나 시작하지 못함, 호출자가 취소한 경우
+		outcome = "error"
+	}
+	commandRuns.Inc(label, outcome)
+	logging.Debugf("🔧 명령 실행: %s (결과: %s, 종료 코드: %d, %v, 출력: %dB%s)",
+		commandLine(name, args), outcome, result.ExitCode, result.Duration.Round(time.Millisecond),
+		len(result.Stdout)+len(result.Stderr), truncatedNote(result.Truncated))
+	return result, err
+}
+
+// Output exec.Cmd.Output처럼 stdout만 반환 (기본 제한 시간과 출력 상한)
+func Output(name string, args ...string) ([]byte, error) {
+	result, err := Run(context.Background(), Options{}, name, args...)
+	return result.Stdout, err
+}
+
+// CombinedOutput exec.Cmd.CombinedOutput처럼 stdout과 stderr를 합쳐 반환
+func CombinedOutput(name string, args ...string) ([]byte, error) {
+	result, err := Run(context.Background(), Options{Combined: true}, name, args...)
+	return result.Stdout, err
+}
+
+func environ(opts Options) []string {
+	if opts.InheritEnv {
+		return append(os.Environ(), opts.Env...)
+	}
+	env := make([]string, 0, len(passEnv)+len(opts.Env))
+	for _, key := range passEnv {
+		if value, ok := os.LookupEnv(key); ok {
+			env = append(env, key+"="+value)
+		}
+	}
+	return append(env, opts.Env...)
+}
+
+// commandLine 로그용 명령줄 (인자가 길면 줄임)
+func commandLine(name string, args []string) string {
+	line := strings.Join(append([]string{name}, args...), " ")
+	if len(line) > 200 {
+		line = line[:200] + "…"
+	}
+	return line
+}
+
+func truncatedNote(truncated bool) string {
+	if truncated {
+		return ", 잘림"
+	}
+	return ""
+}
+
+func tail(output []byte) string {
+	if len(output) > errorTail {
+		output = output[len(output)-errorTail:]
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// limitedBuffer limit까지만 보관하고 나머지는 버리는 버퍼 (프로세스가 쓰기 실패로 멈추지 않도록 항상 성공을 반환)
+type limitedBuffer struct {
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if room := b.limit - b.buf.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.buf.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	b.buf.Write(p)
+	return len(p), nil
+}
+
+func (b *limitedBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Bytes()
+}
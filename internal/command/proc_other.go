@@ -0,0 +1,8 @@
+//go:build !unix
+
+package command
+
+import "os/exec"
+
+// isolate 프로세스 그룹을 지원하지 않는 플랫폼은 프로세스만 종료 (exec 기본 동작)
+func isolate(cmd *exec.Cmd) {}
@@ -0,0 +1,16 @@
+//go:build unix
+
+package command
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// isolate 새 프로세스 그룹으로 실행하고, 취소되면 그룹 전체에 SIGKILL (자식이 띄운 프로세스까지 정리)
+func isolate(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,1038 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/policy"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+	"github.com/sandman/gpu-ssh-gateway/internal/workspace"
+)
+
+// Config 재시작 없이 변경 가능한 설정 (파일 내용이 플래그 기본값 위에 덮어씌워짐)
+type Config struct {
+	LogLevel          string   `json:"log_level"`
+	Timezone          string   `json:"timezone"`
+	DefaultTTLMinutes int      `json:"default_ttl_minutes"`
+	MaxTTLMinutes     int      `json:"max_ttl_minutes"`
+	DefaultMIGProfile string   `json:"default_mig_profile"`
+	AllowedProfiles   []string `json:"allowed_profiles,omitempty"` // 비어 있으면 모든 프로파일 허용
+
+	// 여러 sandman 설치를 구분하는 배포 이름, 환경, 지원 연락처, 배너 (/healthz, /overview, 세션 접속 정보에 포함)
+	Deployment Deployment `json:"deployment"`
+
+	// 주기 작업의 반복 로그 요약/생략
+	LogSampling LogSampling `json:"log_sampling"`
+
+	// expiry_policy "end_of_day"/"end_of_week"의 기준 (timezone 기준 벽시계 시각, 요일)
+	DayEnd  string `json:"day_end"`  // "18:00"
+	WeekEnd string `json:"week_end"` // "friday"
+
+	CertificateTTLMinutes int `json:"certificate_ttl_minutes"` // SSH CA 인증서 최대 유효 시간 (세션 만료 시각을 넘지 않음)
+	CredentialLinkMinutes int `json:"credential_link_minutes"` // 세션 생성 시 발급하는 일회용 자격 증명 링크 유효 시간
+
+	// SSH 키 교체 후 이전 키를 계속 허용하는 시간(분), 0이면 즉시 폐기
+	CredentialRotationGraceMinutes int `json:"credential_rotation_grace_minutes"`
+
+	RestorePointIntervalMinutes int `json:"restore_point_interval_minutes"` // 워크스페이스 복원 지점 생성 간격 (기본 0: 자동 생성 안 함, 켜면 실행 중인 세션마다 워크스페이스를 주기적으로 복사)
+	RestorePointKeep            int `json:"restore_point_keep"`             // 사용자별로 보존할 복원 지점 수
+
+	ContainerTimezone string                 `json:"container_timezone,omitempty"` // 컨테이너 TZ (비어 있으면 timezone 사용)
+	ContainerLocale   string                 `json:"container_locale"`             // 컨테이너 LANG/LC_ALL
+	ImageLocales      map[string]ImageLocale `json:"image_locales,omitempty"`      // 이미지(템플릿)별 기본값
+
+	// 이미지(템플릿)별 CUDA/드라이버/MIG 호환성 (없는 이미지는 검사하지 않음)
+	ImageCompatibility map[string]ImageCompatibility `json:"image_compatibility,omitempty"`
+
+	ProvisioningWorkers    int                  `json:"provisioning_workers"`     // 동시에 진행할 세션 생성 수
+	ProvisioningQueueLimit int                  `json:"provisioning_queue_limit"` // 워커를 기다릴 수 있는 요청 수 (넘으면 503)
+	ProvisioningTimeouts   ProvisioningTimeouts `json:"provisioning_timeouts"`
+	VerifyGPUAttachment    bool                 `json:"verify_gpu_attachment"` // 세션 시작 전에 컨테이너 안에서 GPU 장치 확인
+
+	// 노드별 동시 이미지 빌드/컨테이너 생성 수 (같은 노드의 모든 복제본 합계, "default"는 목록에 없는 노드에 적용, 0이나 없으면 제한 없음)
+	NodeProvisioningLimits map[string]int `json:"node_provisioning_limits,omitempty"`
+
+	// 이미지 채널 자동 롤백: 마지막 변경 이후 시도가 min_sessions 이상이고 실패율이 failure_rate를 넘으면 롤백 (0이면 비활성)
+	ImageRollbackMinSessions int     `json:"image_rollback_min_sessions"`
+	ImageRollbackFailureRate float64 `json:"image_rollback_failure_rate"`
+
+	// MIG 인스턴스 배치 정책
+	Scheduler SchedulerConfig `json:"scheduler"`
+
+	// 세션이 끝난 사용자가 이 시간(분) 안에 새 세션을 만들면 같은 노드의 이전 MIG 인스턴스를 우선 배정
+	// (캐시한 데이터셋, NVMe 스크래치 재사용, 0이면 비활성)
+	MIGReservationMinutes int `json:"mig_reservation_minutes"`
+
+	// 사용자가 멈춘(POST /sessions/:id/stop) 세션의 GPU와 기록을 잡아 두는 시간(분), 지나면 세션을 정리
+	// (요청의 hold_minutes가 없을 때 기본값, max_stop_hold_minutes가 상한)
+	StopHoldMinutes    int `json:"stop_hold_minutes"`
+	MaxStopHoldMinutes int `json:"max_stop_hold_minutes"`
+
+	// 요청한 프로파일의 빈 인스턴스가 없으면 MIG 모드가 켜진 GPU에 새로 만듦 (nvidia-smi mig)
+	MIGOnDemand MIGOnDemand `json:"mig_on_demand"`
+
+	// spot 등급: 남는 인스턴스를 싼 요율로 쓰고, standard 요청에 인스턴스가 없으면 회수 예고 후 종료
+	Spot SpotTier `json:"spot"`
+
+	// 비활성 세션 잠금: SSH 연결과 CPU 사용이 없으면 컨테이너를 일시 정지하고 GPU는 잠시 더 유지
+	InactivityLock InactivityLock `json:"inactivity_lock"`
+
+	// 세션 에이전트: 컨테이너 안 /workspace/.sandman/status.json과 로그인 알림에 넣을 경고 기준
+	SessionAgent SessionAgent `json:"session_agent"`
+
+	// 이미지(템플릿)별 컨테이너 재시작 정책 ("default"는 목록에 없는 이미지에 적용, 없으면 재시작 안 함)
+	RestartPolicies map[string]RestartPolicy `json:"restart_policies,omitempty"`
+
+	// 이미지(템플릿)별 컨테이너 종료 절차 ("default"는 목록에 없는 이미지에 적용, 없으면 SIGTERM 후 10초 뒤 SIGKILL)
+	StopPolicies map[string]StopPolicy `json:"stop_policies,omitempty"`
+	// 세션 생성 요청의 stop_timeout_seconds 상한
+	MaxStopTimeoutSeconds int `json:"max_stop_timeout_seconds"`
+
+	// 이미지(템플릿)별 세션 종료 후 워크스페이스 처리 ("default"는 목록에 없는 이미지에 적용, 없으면 보존, 사용자 설정이 우선)
+	WorkspaceRetention map[string]WorkspaceRetention `json:"workspace_retention,omitempty"`
+
+	// 크래시 루프: crash_loop_window_minutes 안에 crash_loop_restarts회를 넘게 재시작하면 세션을 failed로 표시 (0이면 비활성)
+	CrashLoopRestarts      int `json:"crash_loop_restarts"`
+	CrashLoopWindowMinutes int `json:"crash_loop_window_minutes"`
+
+	// MIG 프로파일별 CPU/메모리 보장량과 버스트 상한 ("default"는 목록에 없는 프로파일에 적용)
+	ResourceClasses map[string]ResourceClass `json:"resource_classes,omitempty"`
+
+	// 컨테이너-호스트, 노드-오케스트레이터 시계 차이 허용 범위 (넘으면 노드 상태에 경고, 0이면 확인하지 않음)
+	ClockToleranceMs int `json:"clock_tolerance_ms"`
+
+	// DNS 감사 기록 보존 기간과 최대 행 수 (-dns-audit로 켰을 때 적용, max_rows가 0이면 개수 제한 없음)
+	DNSAuditRetentionDays int `json:"dns_audit_retention_days"`
+	DNSAuditMaxRows       int `json:"dns_audit_max_rows"`
+
+	// 세션 대기 시간(요청부터 실행까지) 기록 보존 기간 (/stats/wait-times로 조회할 수 있는 가장 긴 구간)
+	WaitStatsRetentionDays int `json:"wait_stats_retention_days"`
+
+	// 세션 생성 시 워크스페이스에 만들어 두는 .ssh (키, ssh_config), 만료 시 삭제
+	WorkspaceSSH WorkspaceSSH `json:"workspace_ssh"`
+
+	// 세션 로그인 시 보여 주는 환영 메시지 (생성 시와 연장 시 워크스페이스에 렌더링)
+	Welcome Welcome `json:"welcome"`
+
+	// 세션 컨테이너를 만들기 전에 실행하는 워크스페이스 준비 (디렉토리, 템플릿, git clone, 데이터셋 링크, 소유권)
+	WorkspaceProvisioning WorkspaceProvisioning `json:"workspace_provisioning"`
+
+	// 사용자 워크스페이스 디스크 한도 (bind 마운트 워크스페이스에만 적용, mode가 비어 있으면 끔)
+	WorkspaceQuota WorkspaceQuota `json:"workspace_quota"`
+
+	// 세션 정리가 끝난 뒤 실행할 사이트별 훅 (스크래치 디스크 삭제, Kerberos 티켓 폐기 등)
+	CleanupHooks []CleanupHook `json:"cleanup_hooks,omitempty"`
+
+	// 사용자 데이터 삭제 요청(오프보딩, 개인정보 삭제 요청) 시 남기는 기록의 처리 방식
+	UserPurge UserPurge `json:"user_purge"`
+
+	// 변경 요청(POST, PUT, PATCH, DELETE)마다 평가하는 사이트별 허가 규칙 (CEL, RBAC 검사 뒤에 적용)
+	AuthzPolicies []policy.Rule `json:"authz_policies,omitempty"`
+
+	location *time.Location
+	welcome  *template.Template
+	policies *policy.Engine
+}
+
+// DefaultWelcomeTemplate welcome.template을 지정하지 않았을 때 쓰는 환영 메시지
+const DefaultWelcomeTemplate = `🎮 GPU 세션: {{.Profile}} ({{.GPUUUID}})
+⏰ 만료: {{.ExpiresAt}} ({{.Timezone}})
+💾 워크스페이스: /workspace
+{{- if .SupportContact}}
+🙋 문의: {{.SupportContact}}
+{{- end}}
+{{- if .DocsURL}}
+📚 사용 안내: {{.DocsURL}}
+{{- end}}
+`
+
+// Welcome 환영 메시지 템플릿(text/template)과 템플릿에서 쓰는 배포 공통 값
+type Welcome struct {
+	Template       string `json:"template,omitempty"` // 비어 있으면 DefaultWelcomeTemplate
+	SupportContact string `json:"support_contact,omitempty"`
+	DocsURL        string `json:"docs_url,omitempty"`
+}
+
+// SpotTier spot 세션 허용 여부, 회수 예고 시간, 정산 요율 (standard 대비 배율)
+type MIGOnDemand struct {
+	Enabled         bool `json:"enabled"`
+	DeleteOnRelease bool `json:"delete_on_release"` // 요청으로 만든 인스턴스는 세션이 끝나면 지워 슬라이스를 돌려줌 (소프트 예약 대신)
+}
+
+type SpotTier struct {
+	Enabled       bool    `json:"enabled"`
+	NoticeMinutes int     `json:"notice_minutes"` // 0이면 예고 없이 바로 회수
+	RateFactor    float64 `json:"rate_factor"`
+}
+
+// LogSampling 주기 작업이 매번 남기는 같은 로그 줄이기 (debug 레벨에서는 항상 전부 출력, 메트릭은 그대로)
+type LogSampling struct {
+	Mode            string `json:"mode"`              // all, summary(창마다 첫 줄 + 생략 건수), off(정상 경로 로그 생략)
+	WindowMinutes   int    `json:"window_minutes"`    // summary 요약 단위
+	ProbeAccessLogs bool   `json:"probe_access_logs"` // /healthz, /readyz, /metrics, /sessions/:id/alive 성공 요청도 접근 로그에 기록
+}
+
+// InactivityLock idle_minutes 동안 활동이 없으면 컨테이너를 일시 정지(docker pause)하고,
+// 그 뒤 hold_minutes 안에 SSH 연결이 들어오면 재개, 지나면 세션을 정리해 GPU를 회수
+type InactivityLock struct {
+	IdleMinutes      int     `json:"idle_minutes"`       // 0이면 비활성
+	HoldMinutes      int     `json:"hold_minutes"`       // 0이면 만료 시각까지 GPU 유지
+	CPUActivePercent float64 `json:"cpu_active_percent"` // 확인 간격 동안 평균 CPU 사용률(코어 1개 기준 %)이 이 이상이면 활동 중
+}
+
+// Deployment 사용자가 어느 설치에 접속하는지 알 수 있도록 응답에 넣는 배포 정보
+type Deployment struct {
+	Name        string `json:"name,omitempty"`
+	Environment string `json:"environment,omitempty"` // prod, staging 등 자유 형식
+	SupportURL  string `json:"support_url,omitempty"`
+	Banner      string `json:"banner,omitempty"` // 점검 예고 같은 짧은 안내
+}
+
+// IsZero 아무 값도 설정하지 않았는지
+func (d Deployment) IsZero() bool {
+	return d == Deployment{}
+}
+
+func (d Deployment) validate() error {
+	if d.SupportURL != "" {
+		u, err := url.Parse(d.SupportURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "mailto") {
+			return fmt.Errorf("support_url은 http, https 또는 mailto URL이어야 합니다: %q", d.SupportURL)
+		}
+	}
+	if len(d.Banner) > maxBannerLength {
+		return fmt.Errorf("banner는 %d자 이하여야 합니다", maxBannerLength)
+	}
+	return nil
+}
+
+// maxBannerLength 배너 최대 길이 (바이트)
+const maxBannerLength = 512
+
+// SessionAgent 컨테이너 안에서 보여 줄 만료/잠금 경고 시점 (0이면 그 경고를 끔)
+type SessionAgent struct {
+	ExpiryWarningMinutes int `json:"expiry_warning_minutes"` // 만료까지 남은 시간이 이 이하이면 경고
+	IdleWarningMinutes   int `json:"idle_warning_minutes"`   // 비활성 잠금까지 남은 시간이 이 이하이면 경고
+}
+
+// WorkspaceSSH 워크스페이스 .ssh 자동 구성
+type WorkspaceSSH struct {
+	Enabled     bool   `json:"enabled"`
+	ExtraConfig string `json:"extra_config,omitempty"` // ssh_config 끝에 덧붙일 배포 공통 설정 (예: 사내 git 서버 Host 블록)
+}
+
+// WorkspaceProvisioning 워크스페이스 준비 방식과 내용 (한 번의 시도가 timeout_seconds를 넘거나 실패하면 retries만큼 재시도)
+type WorkspaceProvisioning struct {
+	Mode                string              `json:"mode"`                 // host(오케스트레이터가 직접), init-container(일회용 컨테이너에서)
+	InitImage           string              `json:"init_image,omitempty"` // init-container에서 쓸 이미지 (sh와 git 필요)
+	TimeoutSeconds      int                 `json:"timeout_seconds"`
+	Retries             int                 `json:"retries"`
+	RetryBackoffSeconds int                 `json:"retry_backoff_seconds"`  // 재시도마다 두 배
+	TemplateDir         string              `json:"template_dir,omitempty"` // 없는 파일만 복사할 호스트 디렉토리
+	GitRepos            []workspace.GitRepo `json:"git_repos,omitempty"`
+	Datasets            []workspace.Dataset `json:"datasets,omitempty"`
+}
+
+// WorkspaceQuota 워크스페이스 디스크 쿼터 (세션 요청의 workspace_quota_gb가 없으면 default_gb)
+type WorkspaceQuota struct {
+	Mode          string `json:"mode,omitempty"`            // xfs(프로젝트 쿼터), loopback(사용자별 ext4 이미지 loop 마운트)
+	DefaultGB     int    `json:"default_gb"`                // 0이면 요청에 workspace_quota_gb가 있을 때만 적용
+	MaxGB         int    `json:"max_gb"`                    // 요청할 수 있는 최대 한도
+	MountPoint    string `json:"mount_point,omitempty"`     // xfs: workspace_root가 있는 XFS 파일시스템의 마운트 지점
+	ProjectIDBase uint32 `json:"project_id_base,omitempty"` // xfs: 프로젝트 ID 시작 값 (사용자마다 이 값부터 차례로 배정)
+	ImageDir      string `json:"image_dir,omitempty"`       // loopback: 이미지 파일을 둘 디렉토리 (workspace_root 밖)
+}
+
+// Enabled 쿼터 백엔드가 설정되었는지
+func (q WorkspaceQuota) Enabled() bool {
+	return q.Mode != ""
+}
+
+// WelcomeData 환영 메시지 템플릿 변수
+type WelcomeData struct {
+	UserID         string
+	SessionID      string
+	Profile        string
+	GPUUUID        string
+	GPUName        string // gpu0-slot4-3g.40gb
+	SSHPort        int
+	ExpiresAt      string // 사용자 시간대 기준 "2006-01-02 15:04"
+	Timezone       string
+	SupportContact string
+	DocsURL        string
+}
+
+// SchedulerConfig 배치 정책 이름과 정책별 파라미터
+// 정책: first-fit, bin-pack, spread, profile-affinity, fair-share(max_share), priority-preempt(min_gap), 또는 빌드에 포함된 사이트 전용 정책
+type SchedulerConfig struct {
+	Policy string             `json:"policy"`
+	Params map[string]float64 `json:"params,omitempty"`
+}
+
+// 재시작 정책 이름 (Docker restart policy)
+const (
+	RestartNo        = "no"
+	RestartOnFailure = "on-failure"
+)
+
+// RestartPolicy 세션 컨테이너가 비정상 종료했을 때의 재시작 방식
+type RestartPolicy struct {
+	Policy     string `json:"policy"`                // "no" 또는 "on-failure"
+	MaxRetries int    `json:"max_retries,omitempty"` // on-failure 최대 재시도 횟수 (0이면 무제한)
+}
+
+func (p RestartPolicy) validate() error {
+	switch p.Policy {
+	case RestartNo:
+		if p.MaxRetries != 0 {
+			return fmt.Errorf("max_retries는 on-failure에서만 지정할 수 있습니다")
+		}
+	case RestartOnFailure:
+		if p.MaxRetries < 0 {
+			return fmt.Errorf("max_retries는 0 이상이어야 합니다")
+		}
+	default:
+		return fmt.Errorf("알 수 없는 정책입니다: %q (no, on-failure)", p.Policy)
+	}
+	return nil
+}
+
+// CleanupHook 세션이 끝난 뒤 세션 정보를 넘겨 실행하는 명령 또는 웹훅 (command와 url 중 하나만 지정)
+type CleanupHook struct {
+	Name           string   `json:"name"`
+	Command        []string `json:"command,omitempty"`         // 셸을 거치지 않고 실행, 세션 정보는 stdin JSON과 SANDMAN_* 환경 변수
+	URL            string   `json:"url,omitempty"`             // 세션 정보를 JSON으로 POST, 2xx면 성공
+	SecretEnv      string   `json:"secret_env,omitempty"`      // 이 환경 변수 값으로 본문의 HMAC-SHA256을 X-Sandman-Signature 헤더에 넣음 (GET /admin/config에 비밀이 드러나지 않도록 이름만 설정)
+	TimeoutSeconds int      `json:"timeout_seconds,omitempty"` // 0이면 30초
+	Reasons        []string `json:"reasons,omitempty"`         // 이 종료 사유에만 실행 (비어 있으면 모두)
+	PassEnv        []string `json:"pass_env,omitempty"`        // command에 넘길 오케스트레이터 환경 변수 이름 (PATH, HOME, LANG 등 기본 변수 외)
+}
+
+func (h CleanupHook) validate() error {
+	if (len(h.Command) > 0) == (h.URL != "") {
+		return fmt.Errorf("command와 url 중 하나만 지정해야 합니다")
+	}
+	if len(h.Command) > 0 && h.Command[0] == "" {
+		return fmt.Errorf("command의 실행 파일이 비어 있습니다")
+	}
+	if h.URL != "" {
+		u, err := url.Parse(h.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("url이 잘못되었습니다: %q", h.URL)
+		}
+	}
+	if h.SecretEnv != "" && os.Getenv(h.SecretEnv) == "" {
+		return fmt.Errorf("secret_env 환경 변수 %s가 비어 있습니다", h.SecretEnv)
+	}
+	if h.TimeoutSeconds < 0 {
+		return fmt.Errorf("timeout_seconds는 0 이상이어야 합니다")
+	}
+	return nil
+}
+
+// DefaultStopTimeoutSeconds 종료 정책이 없을 때 SIGTERM 후 SIGKILL까지 기다리는 시간
+const DefaultStopTimeoutSeconds = 10
+
+// StopPolicy 세션 컨테이너 종료 절차: pre_stop 명령을 실행하고 컨테이너가 스스로 끝나기를
+// pre_stop_wait_seconds까지 기다린 뒤 SIGTERM, timeout_seconds 뒤에도 남아 있으면 SIGKILL
+type StopPolicy struct {
+	TimeoutSeconds     int      `json:"timeout_seconds"`                 // 0이면 기본값 10초
+	PreStop            []string `json:"pre_stop,omitempty"`              // 컨테이너 안에서 실행할 명령 (예: ["touch", "/tmp/shutdown-requested"])
+	PreStopWaitSeconds int      `json:"pre_stop_wait_seconds,omitempty"` // pre_stop 후 SIGTERM까지 최대 대기 시간
+}
+
+func (p StopPolicy) validate() error {
+	if p.TimeoutSeconds < 0 || p.PreStopWaitSeconds < 0 {
+		return fmt.Errorf("timeout_seconds와 pre_stop_wait_seconds는 0 이상이어야 합니다")
+	}
+	if len(p.PreStop) > 0 && p.PreStop[0] == "" {
+		return fmt.Errorf("pre_stop의 실행 파일이 비어 있습니다")
+	}
+	if len(p.PreStop) == 0 && p.PreStopWaitSeconds > 0 {
+		return fmt.Errorf("pre_stop_wait_seconds는 pre_stop과 함께 지정해야 합니다")
+	}
+	return nil
+}
+
+// StopPolicyFor 이미지에 적용할 종료 절차 (없으면 "default", 그것도 없으면 SIGTERM 후 10초)
+func (c *Config) StopPolicyFor(image string) StopPolicy {
+	policy, ok := c.StopPolicies[image]
+	if !ok {
+		policy = c.StopPolicies["default"]
+	}
+	if policy.TimeoutSeconds == 0 {
+		policy.TimeoutSeconds = DefaultStopTimeoutSeconds
+	}
+	return policy
+}
+
+// RestartPolicyFor 이미지에 적용할 재시작 정책 (없으면 "default", 그것도 없으면 재시작 안 함)
+func (c *Config) RestartPolicyFor(image string) RestartPolicy {
+	if policy, ok := c.RestartPolicies[image]; ok {
+		return policy
+	}
+	if policy, ok := c.RestartPolicies["default"]; ok {
+		return policy
+	}
+	return RestartPolicy{Policy: RestartNo}
+}
+
+// NodeProvisioningLimitFor 노드의 동시 프로비저닝 한도 (없으면 "default", 그것도 없으면 0 = 제한 없음)
+func (c *Config) NodeProvisioningLimitFor(nodeID string) int {
+	if limit, ok := c.NodeProvisioningLimits[nodeID]; ok {
+		return limit
+	}
+	return c.NodeProvisioningLimits["default"]
+}
+
+// 세션 종료 후 워크스페이스 처리 방식
+const (
+	WorkspaceKeep    = "keep"    // 계속 보존
+	WorkspaceArchive = "archive" // days일 뒤 tar.gz로 보관하고 디렉토리 삭제 (다음 세션 시작 시 복원)
+	WorkspaceDelete  = "delete"  // 세션 종료 즉시 삭제
+)
+
+// WorkspaceRetention 세션 종료 후 워크스페이스 처리
+type WorkspaceRetention struct {
+	Policy string `json:"policy"`
+	Days   int    `json:"days,omitempty"` // archive에서 보관 전 유지 기간 (0이면 다음 수명 주기 작업에서 바로 보관)
+}
+
+// Validate 사용자 설정에서도 쓰므로 공개
+func (r WorkspaceRetention) Validate() error {
+	switch r.Policy {
+	case WorkspaceKeep, WorkspaceDelete:
+		if r.Days != 0 {
+			return fmt.Errorf("days는 archive에서만 지정할 수 있습니다")
+		}
+	case WorkspaceArchive:
+		if r.Days < 0 {
+			return fmt.Errorf("days는 0 이상이어야 합니다")
+		}
+	default:
+		return fmt.Errorf("알 수 없는 워크스페이스 정책입니다: %q (keep, archive, delete)", r.Policy)
+	}
+	return nil
+}
+
+// WorkspaceRetentionFor 이미지에 적용할 워크스페이스 처리 (없으면 "default", 그것도 없으면 보존)
+func (c *Config) WorkspaceRetentionFor(image string) WorkspaceRetention {
+	if retention, ok := c.WorkspaceRetention[image]; ok {
+		return retention
+	}
+	if retention, ok := c.WorkspaceRetention["default"]; ok {
+		return retention
+	}
+	return WorkspaceRetention{Policy: WorkspaceKeep}
+}
+
+// UserPurge 사용자 데이터 삭제 시 세션 기록(사용량, 이미지 빌드)과 감사 기록 처리
+type UserPurge struct {
+	History string `json:"history"` // anonymize, delete
+	Audit   string `json:"audit"`   // keep, anonymize, delete
+}
+
+// Validate 요청별로 덮어쓴 값도 검사하므로 공개
+func (p UserPurge) Validate() error {
+	switch p.History {
+	case store.PurgeAnonymize, store.PurgeDelete:
+	default:
+		return fmt.Errorf("history는 anonymize 또는 delete여야 합니다: %q", p.History)
+	}
+	switch p.Audit {
+	case store.PurgeKeep, store.PurgeAnonymize, store.PurgeDelete:
+	default:
+		return fmt.Errorf("audit은 keep, anonymize, delete 중 하나여야 합니다: %q", p.Audit)
+	}
+	return nil
+}
+
+// ImageLocale 이미지별 컨테이너 시간대/로케일 (비어 있는 항목은 배포 기본값 사용)
+type ImageLocale struct {
+	Timezone string `json:"timezone,omitempty"`
+	Locale   string `json:"locale,omitempty"`
+}
+
+// ImageCompatibility 세션 생성 전에 이미지와 MIG 프로파일/호스트 드라이버 조합을 검사하는 정보
+type ImageCompatibility struct {
+	CUDA      string   `json:"cuda,omitempty"`       // 이미지의 CUDA 런타임 버전 (MIG는 11.0 이상 필요)
+	MinDriver string   `json:"min_driver,omitempty"` // 이미지가 요구하는 최소 호스트 드라이버 버전
+	MIGAware  *bool    `json:"mig_aware,omitempty"`  // false면 MIG 인스턴스에서 실행 불가 (비어 있으면 CUDA 버전으로 판단)
+	Profiles  []string `json:"profiles,omitempty"`   // 비어 있지 않으면 이 프로파일에서만 실행
+}
+
+// ProvisioningTimeouts 세션 생성 단계별 제한 시간 (초, 0이면 제한 없음)
+type ProvisioningTimeouts struct {
+	QueueWaitSeconds       int `json:"queue_wait_seconds"`
+	ImageBuildSeconds      int `json:"image_build_seconds"`
+	ContainerCreateSeconds int `json:"container_create_seconds"`
+	ContainerStartSeconds  int `json:"container_start_seconds"`
+	GPUVerifySeconds       int `json:"gpu_verify_seconds"` // 0이면 30초
+}
+
+// ResourceClass 세션 컨테이너의 보장량(경합 시 확보되는 몫)과 버스트 상한 (0이면 제한 없음)
+type ResourceClass struct {
+	CPUReservation      float64 `json:"cpu_reservation"`               // 코어 수, cpu.weight로 반영
+	CPULimit            float64 `json:"cpu_limit"`                     // 코어 수, 버스트 상한
+	MemoryReservationMB int64   `json:"memory_reservation_mb"`         // 메모리 압박 시에도 유지되는 양
+	MemoryLimitMB       int64   `json:"memory_limit_mb"`               // 버스트 상한 (넘으면 OOM)
+	MaxCPULimit         float64 `json:"max_cpu_limit,omitempty"`       // 세션이 cpu_cores로 요청할 수 있는 최대 (0이면 cpu_limit)
+	MaxMemoryLimitMB    int64   `json:"max_memory_limit_mb,omitempty"` // 세션이 memory_gb로 요청할 수 있는 최대 (0이면 memory_limit_mb)
+}
+
+// Validate 값 범위와 예약/상한 관계 확인 (관리자가 정의한 프로파일의 리소스에도 사용)
+func (r ResourceClass) Validate() error {
+	if r.CPUReservation < 0 || r.CPULimit < 0 || r.MemoryReservationMB < 0 || r.MemoryLimitMB < 0 ||
+		r.MaxCPULimit < 0 || r.MaxMemoryLimitMB < 0 {
+		return fmt.Errorf("값은 0 이상이어야 합니다")
+	}
+	if r.MaxCPULimit > 0 && r.CPULimit > r.MaxCPULimit {
+		return fmt.Errorf("cpu_limit(%g)이 max_cpu_limit(%g)보다 큽니다", r.CPULimit, r.MaxCPULimit)
+	}
+	if r.MaxMemoryLimitMB > 0 && r.MemoryLimitMB > r.MaxMemoryLimitMB {
+		return fmt.Errorf("memory_limit_mb(%d)가 max_memory_limit_mb(%d)보다 큽니다", r.MemoryLimitMB, r.MaxMemoryLimitMB)
+	}
+	if r.CPULimit > 0 && r.CPUReservation > r.CPULimit {
+		return fmt.Errorf("cpu_reservation(%g)이 cpu_limit(%g)보다 큽니다", r.CPUReservation, r.CPULimit)
+	}
+	if r.MemoryLimitMB > 0 && r.MemoryReservationMB >= r.MemoryLimitMB {
+		return fmt.Errorf("memory_reservation_mb(%d)는 memory_limit_mb(%d)보다 작아야 합니다", r.MemoryReservationMB, r.MemoryLimitMB)
+	}
+	return nil
+}
+
+// ResourceClassFor 프로파일에 적용할 리소스 클래스 (없으면 "default", 그것도 없으면 제한 없음)
+func (c *Config) ResourceClassFor(profile string) ResourceClass {
+	if class, ok := c.ResourceClasses[profile]; ok {
+		return class
+	}
+	return c.ResourceClasses["default"]
+}
+
+var localePattern = regexp.MustCompile(`^[A-Za-z]{1,8}(_[A-Za-z]{2})?(\.[A-Za-z0-9-]+)?(@[A-Za-z0-9]+)?$`)
+
+// ValidLocale "ko_KR.UTF-8", "C.UTF-8", "POSIX" 같은 로케일 이름인지 확인
+func ValidLocale(locale string) bool {
+	return localePattern.MatchString(locale)
+}
+
+// Defaults 설정 파일이 없을 때 사용하는 기본값
+func Defaults() Config {
+	return Config{
+		LogLevel:          logging.LevelInfo,
+		LogSampling:       LogSampling{Mode: logging.SamplingSummary, WindowMinutes: 60},
+		Timezone:          "UTC",
+		DefaultTTLMinutes: 60,
+		MaxTTLMinutes:     7 * 24 * 60,
+		DefaultMIGProfile: "3g.20gb",
+
+		DayEnd:  "18:00",
+		WeekEnd: "friday",
+
+		CertificateTTLMinutes:          8 * 60,
+		CredentialLinkMinutes:          15,
+		CredentialRotationGraceMinutes: 10,
+
+		RestorePointKeep: 6,
+
+		ContainerLocale: "C.UTF-8",
+
+		ProvisioningWorkers:    4,
+		ProvisioningQueueLimit: 16,
+		ProvisioningTimeouts: ProvisioningTimeouts{
+			QueueWaitSeconds:       120,
+			ImageBuildSeconds:      600,
+			ContainerCreateSeconds: 60,
+			ContainerStartSeconds:  60,
+			GPUVerifySeconds:       30,
+		},
+		VerifyGPUAttachment: true,
+
+		ImageRollbackMinSessions: 10,
+		ImageRollbackFailureRate: 0.3,
+
+		Scheduler:             SchedulerConfig{Policy: gpu.PolicyFirstFit},
+		MIGReservationMinutes: 15,
+		StopHoldMinutes:       15,
+		MaxStopHoldMinutes:    120,
+
+		Spot: SpotTier{Enabled: true, NoticeMinutes: 10, RateFactor: 0.3},
+
+		InactivityLock: InactivityLock{HoldMinutes: 60, CPUActivePercent: 10},
+
+		SessionAgent: SessionAgent{ExpiryWarningMinutes: 30, IdleWarningMinutes: 10},
+
+		CrashLoopRestarts:      5,
+		CrashLoopWindowMinutes: 10,
+
+		MaxStopTimeoutSeconds: 600,
+
+		ClockToleranceMs: 2000,
+
+		DNSAuditRetentionDays: 14,
+		DNSAuditMaxRows:       1000000,
+
+		WaitStatsRetentionDays: 90,
+
+		WorkspaceSSH: WorkspaceSSH{Enabled: true},
+
+		WorkspaceProvisioning: WorkspaceProvisioning{Mode: workspace.ModeHost, TimeoutSeconds: 120, Retries: 2, RetryBackoffSeconds: 5},
+
+		UserPurge: UserPurge{History: store.PurgeAnonymize, Audit: store.PurgeAnonymize},
+	}
+}
+
+// ParseWeekday "friday", "fri" 같은 요일 이름 해석 (대소문자 무시)
+func ParseWeekday(name string) (time.Weekday, bool) {
+	name = strings.ToLower(name)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		full := strings.ToLower(day.String())
+		if name == full || name == full[:3] {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// Location 검증 시 해석해 둔 시간대
+func (c *Config) Location() *time.Location {
+	if c.location == nil {
+		return time.UTC
+	}
+	return c.location
+}
+
+// WelcomeTemplate 검증 시 파싱해 둔 환영 메시지 템플릿
+func (c *Config) WelcomeTemplate() *template.Template {
+	if c.welcome == nil {
+		return template.Must(template.New("welcome").Parse(DefaultWelcomeTemplate))
+	}
+	return c.welcome
+}
+
+// Policies 검증 시 컴파일해 둔 허가 규칙
+func (c *Config) Policies() *policy.Engine {
+	return c.policies
+}
+
+// ProfileAllowed 프로파일이 허용 목록에 있는지 확인
+func (c *Config) ProfileAllowed(profile string) bool {
+	if len(c.AllowedProfiles) == 0 {
+		return true
+	}
+	for _, p := range c.AllowedProfiles {
+		if p == profile {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Config) validate() error {
+	if !logging.ValidLevel(c.LogLevel) {
+		return fmt.Errorf("log_level이 잘못되었습니다: %q", c.LogLevel)
+	}
+	if !logging.ValidSamplingMode(c.LogSampling.Mode) {
+		return fmt.Errorf("log_sampling.mode가 잘못되었습니다: %q (all, summary, off)", c.LogSampling.Mode)
+	}
+	if c.LogSampling.WindowMinutes <= 0 {
+		return fmt.Errorf("log_sampling.window_minutes는 0보다 커야 합니다")
+	}
+
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return fmt.Errorf("timezone이 잘못되었습니다: %q", c.Timezone)
+	}
+	c.location = loc
+
+	if c.DefaultTTLMinutes <= 0 {
+		return fmt.Errorf("default_ttl_minutes는 0보다 커야 합니다")
+	}
+	if c.MaxTTLMinutes < c.DefaultTTLMinutes {
+		return fmt.Errorf("max_ttl_minutes(%d)가 default_ttl_minutes(%d)보다 작습니다", c.MaxTTLMinutes, c.DefaultTTLMinutes)
+	}
+	if c.DefaultMIGProfile == "" {
+		return fmt.Errorf("default_mig_profile이 비어 있습니다")
+	}
+	if !c.ProfileAllowed(c.DefaultMIGProfile) {
+		return fmt.Errorf("default_mig_profile %s가 allowed_profiles에 없습니다", c.DefaultMIGProfile)
+	}
+	if _, err := time.Parse("15:04", c.DayEnd); err != nil {
+		return fmt.Errorf("day_end가 잘못되었습니다 (HH:MM): %q", c.DayEnd)
+	}
+	if _, ok := ParseWeekday(c.WeekEnd); !ok {
+		return fmt.Errorf("week_end가 잘못되었습니다 (예: friday): %q", c.WeekEnd)
+	}
+	if c.CertificateTTLMinutes <= 0 {
+		return fmt.Errorf("certificate_ttl_minutes는 0보다 커야 합니다")
+	}
+	if c.CredentialLinkMinutes <= 0 {
+		return fmt.Errorf("credential_link_minutes는 0보다 커야 합니다")
+	}
+	if c.CredentialRotationGraceMinutes < 0 {
+		return fmt.Errorf("credential_rotation_grace_minutes는 0 이상이어야 합니다")
+	}
+	if c.RestorePointIntervalMinutes < 0 {
+		return fmt.Errorf("restore_point_interval_minutes는 0 이상이어야 합니다")
+	}
+	if c.RestorePointKeep <= 0 {
+		return fmt.Errorf("restore_point_keep은 0보다 커야 합니다")
+	}
+	if c.ContainerTimezone != "" {
+		if _, err := time.LoadLocation(c.ContainerTimezone); err != nil {
+			return fmt.Errorf("container_timezone이 잘못되었습니다: %q", c.ContainerTimezone)
+		}
+	}
+	if !ValidLocale(c.ContainerLocale) {
+		return fmt.Errorf("container_locale이 잘못되었습니다: %q", c.ContainerLocale)
+	}
+	if c.ProvisioningWorkers <= 0 {
+		return fmt.Errorf("provisioning_workers는 0보다 커야 합니다")
+	}
+	if c.ProvisioningQueueLimit < 0 {
+		return fmt.Errorf("provisioning_queue_limit은 0 이상이어야 합니다")
+	}
+	t := c.ProvisioningTimeouts
+	if t.QueueWaitSeconds < 0 || t.ImageBuildSeconds < 0 || t.ContainerCreateSeconds < 0 || t.ContainerStartSeconds < 0 || t.GPUVerifySeconds < 0 {
+		return fmt.Errorf("provisioning_timeouts 값은 0 이상이어야 합니다")
+	}
+	for node, limit := range c.NodeProvisioningLimits {
+		if limit < 0 {
+			return fmt.Errorf("node_provisioning_limits[%s]는 0 이상이어야 합니다", node)
+		}
+	}
+	if c.ImageRollbackMinSessions < 1 {
+		return fmt.Errorf("image_rollback_min_sessions는 1 이상이어야 합니다")
+	}
+	if c.ImageRollbackFailureRate < 0 || c.ImageRollbackFailureRate > 1 {
+		return fmt.Errorf("image_rollback_failure_rate는 0~1이어야 합니다")
+	}
+	if _, err := gpu.NewScheduler(c.Scheduler.Policy, c.Scheduler.Params); err != nil {
+		return fmt.Errorf("scheduler: %v", err)
+	}
+	if c.MIGReservationMinutes < 0 {
+		return fmt.Errorf("mig_reservation_minutes는 0 이상이어야 합니다")
+	}
+	if c.StopHoldMinutes < 1 || c.MaxStopHoldMinutes < c.StopHoldMinutes {
+		return fmt.Errorf("stop_hold_minutes는 1 이상, max_stop_hold_minutes는 stop_hold_minutes 이상이어야 합니다")
+	}
+	if c.InactivityLock.IdleMinutes < 0 || c.InactivityLock.HoldMinutes < 0 || c.InactivityLock.CPUActivePercent < 0 {
+		return fmt.Errorf("inactivity_lock 값은 0 이상이어야 합니다")
+	}
+	if err := c.Deployment.validate(); err != nil {
+		return fmt.Errorf("deployment: %v", err)
+	}
+	if c.SessionAgent.ExpiryWarningMinutes < 0 || c.SessionAgent.IdleWarningMinutes < 0 {
+		return fmt.Errorf("session_agent의 경고 시간은 0 이상이어야 합니다")
+	}
+	if c.Spot.NoticeMinutes < 0 {
+		return fmt.Errorf("spot.notice_minutes는 0 이상이어야 합니다")
+	}
+	if c.Spot.RateFactor < 0 || c.Spot.RateFactor > 1 {
+		return fmt.Errorf("spot.rate_factor는 0~1이어야 합니다")
+	}
+	for image, policy := range c.RestartPolicies {
+		if err := policy.validate(); err != nil {
+			return fmt.Errorf("restart_policies[%s]: %v", image, err)
+		}
+	}
+	for image, policy := range c.StopPolicies {
+		if err := policy.validate(); err != nil {
+			return fmt.Errorf("stop_policies[%s]: %v", image, err)
+		}
+	}
+	if c.MaxStopTimeoutSeconds < 0 {
+		return fmt.Errorf("max_stop_timeout_seconds는 0 이상이어야 합니다")
+	}
+	hookNames := make(map[string]bool)
+	for i, hook := range c.CleanupHooks {
+		if hook.Name == "" {
+			return fmt.Errorf("cleanup_hooks[%d]: name이 비어 있습니다", i)
+		}
+		if hookNames[hook.Name] {
+			return fmt.Errorf("cleanup_hooks: 이름이 중복됩니다: %s", hook.Name)
+		}
+		hookNames[hook.Name] = true
+		if err := hook.validate(); err != nil {
+			return fmt.Errorf("cleanup_hooks[%s]: %v", hook.Name, err)
+		}
+	}
+	if err := c.WorkspaceQuota.validate(); err != nil {
+		return fmt.Errorf("workspace_quota: %v", err)
+	}
+	if err := c.WorkspaceProvisioning.validate(); err != nil {
+		return fmt.Errorf("workspace_provisioning: %v", err)
+	}
+	for image, retention := range c.WorkspaceRetention {
+		if err := retention.Validate(); err != nil {
+			return fmt.Errorf("workspace_retention[%s]: %v", image, err)
+		}
+	}
+	if c.policies, err = policy.Compile(c.AuthzPolicies); err != nil {
+		return fmt.Errorf("authz_policies%v", err)
+	}
+	if err := c.UserPurge.Validate(); err != nil {
+		return fmt.Errorf("user_purge: %v", err)
+	}
+	if c.CrashLoopRestarts < 0 {
+		return fmt.Errorf("crash_loop_restarts는 0 이상이어야 합니다")
+	}
+	if c.CrashLoopRestarts > 0 && c.CrashLoopWindowMinutes <= 0 {
+		return fmt.Errorf("crash_loop_window_minutes는 0보다 커야 합니다")
+	}
+	if c.ClockToleranceMs < 0 {
+		return fmt.Errorf("clock_tolerance_ms는 0 이상이어야 합니다")
+	}
+	if c.DNSAuditRetentionDays < 1 {
+		return fmt.Errorf("dns_audit_retention_days는 1 이상이어야 합니다")
+	}
+	if c.DNSAuditMaxRows < 0 {
+		return fmt.Errorf("dns_audit_max_rows는 0 이상이어야 합니다")
+	}
+	if c.WaitStatsRetentionDays < 1 {
+		return fmt.Errorf("wait_stats_retention_days는 1 이상이어야 합니다")
+	}
+	for profile, class := range c.ResourceClasses {
+		if err := class.Validate(); err != nil {
+			return fmt.Errorf("resource_classes[%s]: %v", profile, err)
+		}
+	}
+	for image, compat := range c.ImageCompatibility {
+		if compat.CUDA != "" && !gpu.ValidVersion(compat.CUDA) {
+			return fmt.Errorf("image_compatibility[%s].cuda가 잘못되었습니다: %q", image, compat.CUDA)
+		}
+		if compat.MinDriver != "" && !gpu.ValidVersion(compat.MinDriver) {
+			return fmt.Errorf("image_compatibility[%s].min_driver가 잘못되었습니다: %q", image, compat.MinDriver)
+		}
+	}
+	welcome := c.Welcome.Template
+	if welcome == "" {
+		welcome = DefaultWelcomeTemplate
+	}
+	if c.welcome, err = template.New("welcome").Parse(welcome); err != nil {
+		return fmt.Errorf("welcome.template이 잘못되었습니다: %v", err)
+	}
+	if err := c.welcome.Execute(io.Discard, WelcomeData{}); err != nil {
+		return fmt.Errorf("welcome.template이 잘못되었습니다: %v", err)
+	}
+	for image, l := range c.ImageLocales {
+		if l.Timezone != "" {
+			if _, err := time.LoadLocation(l.Timezone); err != nil {
+				return fmt.Errorf("image_locales[%s].timezone이 잘못되었습니다: %q", image, l.Timezone)
+			}
+		}
+		if l.Locale != "" && !ValidLocale(l.Locale) {
+			return fmt.Errorf("image_locales[%s].locale이 잘못되었습니다: %q", image, l.Locale)
+		}
+	}
+	return nil
+}
+
+// Guard 새 설정을 적용하기 전에 실행 중인 상태와 충돌하는지 검사 (에러 시 리로드 거부)
+type Guard func(old, new *Config) error
+
+// Manager 현재 설정을 보관하고 파일 변경을 검증 후 원자적으로 교체
+type Manager struct {
+	path string
+	base Config
+
+	mu      sync.Mutex // 리로드 직렬화
+	current atomic.Pointer[Config]
+	guards  []Guard
+}
+
+// NewManager base(기본값+플래그) 위에 path 파일을 적용해 초기 설정을 만든다
+// path가 비어 있으면 base만 사용하며 리로드는 지원되지 않음
+func NewManager(path string, base Config) (*Manager, error) {
+	m := &Manager{path: path, base: base}
+
+	cfg, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+	m.current.Store(cfg)
+	cfg.applyLogging()
+	return m, nil
+}
+
+// applyLogging 로그 레벨과 반복 로그 처리 방식을 logging 패키지에 반영
+func (c *Config) applyLogging() {
+	logging.SetLevel(c.LogLevel)
+	logging.SetSampling(c.LogSampling.Mode, time.Duration(c.LogSampling.WindowMinutes)*time.Minute)
+}
+
+// Current 현재 적용된 설정 (읽기 전용으로 사용)
+func (m *Manager) Current() *Config {
+	return m.current.Load()
+}
+
+// AddGuard 리로드 시 실행할 검사 등록
+func (m *Manager) AddGuard(guard Guard) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.guards = append(m.guards, guard)
+}
+
+func (m *Manager) load() (*Config, error) {
+	cfg := m.base
+	cfg.AllowedProfiles = append([]string(nil), m.base.AllowedProfiles...)
+
+	if m.path != "" {
+		data, err := os.ReadFile(m.path)
+		if err != nil {
+			return nil, fmt.Errorf("설정 파일 읽기 실패: %v", err)
+		}
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("설정 파일 파싱 실패: %v", err)
+		}
+	}
+
+	if err := cfg.validate(); err != nil {
+		return nil, fmt.Errorf("설정 검증 실패: %v", err)
+	}
+	return &cfg, nil
+}
+
+// Reload 설정 파일을 다시 읽어 검증과 가드를 통과하면 적용하고 변경 내역을 반환
+func (m *Manager) Reload() ([]string, error) {
+	if m.path == "" {
+		return nil, fmt.Errorf("설정 파일(-config)이 지정되지 않아 리로드할 수 없습니다")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	next, err := m.load()
+	if err != nil {
+		return nil, err
+	}
+
+	prev := m.Current()
+	for _, guard := range m.guards {
+		if err := guard(prev, next); err != nil {
+			return nil, fmt.Errorf("설정 변경 거부: %w", err)
+		}
+	}
+
+	changes := Diff(prev, next)
+	m.current.Store(next)
+	next.applyLogging()
+
+	if len(changes) == 0 {
+		log.Printf("🔄 설정 리로드: 변경 사항 없음")
+	}
+	for _, change := range changes {
+		log.Printf("🔄 설정 변경: %s", change)
+	}
+	return changes, nil
+}
+
+// Diff 두 설정의 JSON 필드 단위 변경 내역 ("필드: 이전 → 이후")
+func Diff(old, new *Config) []string {
+	oldFields, newFields := toFields(old), toFields(new)
+
+	keys := make([]string, 0, len(newFields))
+	for key := range newFields {
+		keys = append(keys, key)
+	}
+	for key := range oldFields {
+		if _, ok := newFields[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	changes := []string{}
+	for _, key := range keys {
+		if !reflect.DeepEqual(oldFields[key], newFields[key]) {
+			changes = append(changes, fmt.Sprintf("%s: %v → %v", key, oldFields[key], newFields[key]))
+		}
+	}
+	return changes
+}
+
+func toFields(cfg *Config) map[string]interface{} {
+	fields := map[string]interface{}{}
+	data, _ := json.Marshal(cfg)
+	json.Unmarshal(data, &fields)
+	return fields
+}
+
+func (q WorkspaceQuota) validate() error {
+	switch q.Mode {
+	case "":
+		return nil
+	case workspace.QuotaXFS:
+		if !strings.HasPrefix(q.MountPoint, "/") {
+			return fmt.Errorf("xfs 방식에는 절대 경로 mount_point가 필요합니다")
+		}
+		if q.ProjectIDBase == 0 {
+			return fmt.Errorf("xfs 방식에는 project_id_base가 필요합니다 (다른 프로젝트 ID와 겹치지 않는 값)")
+		}
+	case workspace.QuotaLoopback:
+		if !strings.HasPrefix(q.ImageDir, "/") {
+			return fmt.Errorf("loopback 방식에는 절대 경로 image_dir이 필요합니다")
+		}
+	default:
+		return fmt.Errorf("mode가 잘못되었습니다: %q (xfs, loopback)", q.Mode)
+	}
+	if q.MaxGB <= 0 {
+		return fmt.Errorf("max_gb는 0보다 커야 합니다")
+	}
+	if q.DefaultGB < 0 || q.DefaultGB > q.MaxGB {
+		return fmt.Errorf("default_gb는 0~max_gb(%d)여야 합니다", q.MaxGB)
+	}
+	return nil
+}
+
+func (w WorkspaceProvisioning) validate() error {
+	switch w.Mode {
+	case workspace.ModeHost:
+	case workspace.ModeInitContainer:
+		if w.InitImage == "" {
+			return fmt.Errorf("init-container 방식에는 init_image가 필요합니다")
+		}
+	default:
+		return fmt.Errorf("mode가 잘못되었습니다: %q (host, init-container)", w.Mode)
+	}
+	if w.TimeoutSeconds <= 0 {
+		return fmt.Errorf("timeout_seconds는 0보다 커야 합니다")
+	}
+	if w.Retries < 0 || w.RetryBackoffSeconds < 0 {
+		return fmt.Errorf("retries와 retry_backoff_seconds는 0 이상이어야 합니다")
+	}
+	if w.TemplateDir != "" && !strings.HasPrefix(w.TemplateDir, "/") {
+		return fmt.Errorf("template_dir은 절대 경로여야 합니다: %q", w.TemplateDir)
+	}
+	for i, repo := range w.GitRepos {
+		if repo.URL == "" {
+			return fmt.Errorf("git_repos[%d]: url이 비어 있습니다", i)
+		}
+		if !workspace.ValidRelativePath(repo.Path) {
+			return fmt.Errorf("git_repos[%d]: path는 워크스페이스 안의 상대 경로여야 합니다: %q", i, repo.Path)
+		}
+	}
+	for i, dataset := range w.Datasets {
+		if !workspace.ValidRelativePath(dataset.Link) {
+			return fmt.Errorf("datasets[%d]: link는 워크스페이스 안의 상대 경로여야 합니다: %q", i, dataset.Link)
+		}
+		if !strings.HasPrefix(dataset.Target, "/") {
+			return fmt.Errorf("datasets[%d]: target은 절대 경로여야 합니다: %q", i, dataset.Target)
+		}
+	}
+	return nil
+}
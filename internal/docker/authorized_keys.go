@@ -0,0 +1,51 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// authorizedKeysPath 컨테이너 사용자(세션 소유자)의 authorized_keys 경로
+func authorizedKeysPath(user string) string {
+	return fmt.Sprintf("/home/%s/.ssh/authorized_keys", user)
+}
+
+// AppendAuthorizedKey 컨테이너 사용자의 authorized_keys에 공개키 한 줄을 추가
+// tag는 키 주석으로 기록되어 RemoveAuthorizedKey에서 해당 줄을 찾는 데 사용됨
+func (c *Client) AppendAuthorizedKey(containerID, user, keyLine, tag string) error {
+	script := `f="$0"; printf '%s %s\n' "$1" "$2" >> "$f" && chmod 600 "$f"`
+	_, err := c.ExecOK(context.Background(), containerID,
+		[]string{"sh", "-c", script, authorizedKeysPath(user), keyLine, tag})
+	if err != nil {
+		return fmt.Errorf("authorized_keys 추가 실패: %v", err)
+	}
+	return nil
+}
+
+// RemoveAuthorizedKey tag 주석이 달린 줄을 authorized_keys에서 제거
+func (c *Client) RemoveAuthorizedKey(containerID, user, tag string) error {
+	script := `f="$0"; [ -f "$f" ] || exit 0; awk -v t="$1" '$NF != t' "$f" > "$f.tmp"; cat "$f.tmp" > "$f"; rm -f "$f.tmp"`
+	_, err := c.ExecOK(context.Background(), containerID,
+		[]string{"sh", "-c", script, authorizedKeysPath(user), tag})
+	if err != nil {
+		return fmt.Errorf("authorized_keys 제거 실패: %v", err)
+	}
+	return nil
+}
+
+// RemoveAuthorizedKeyMaterial 키 종류와 본문이 같은 줄을 authorized_keys에서 제거 (주석/태그와 관계없음)
+// 이미지 빌드 때 들어간 세션 기본 키처럼 태그가 없는 줄을 지울 때 사용
+func (c *Client) RemoveAuthorizedKeyMaterial(containerID, user, keyLine string) error {
+	fields := strings.Fields(keyLine)
+	if len(fields) < 2 {
+		return fmt.Errorf("공개키 형식이 잘못되었습니다")
+	}
+	script := `f="$0"; [ -f "$f" ] || exit 0; awk -v t="$1" -v k="$2" '!($1 == t && $2 == k)' "$f" > "$f.tmp"; cat "$f.tmp" > "$f"; rm -f "$f.tmp"`
+	_, err := c.ExecOK(context.Background(), containerID,
+		[]string{"sh", "-c", script, authorizedKeysPath(user), fields[0], fields[1]})
+	if err != nil {
+		return fmt.Errorf("authorized_keys 제거 실패: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package docker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+)
+
+var (
+	imageBuilds       = metrics.NewCounter("sandman_image_builds_total", "워크스페이스 이미지 빌드 수 (result: ok, failed)", "template", "result")
+	imageBuildSeconds = metrics.NewHistogram("sandman_image_build_seconds", "워크스페이스 이미지 빌드 시간",
+		[]float64{1, 5, 15, 30, 60, 120, 300, 600}, "template")
+	imageBuildSteps = metrics.NewCounter("sandman_image_build_steps_total", "이미지 빌드 단계 수 (cache: hit, miss)", "template", "cache")
+	imageSizeBytes  = metrics.NewGauge("sandman_image_size_bytes", "마지막으로 빌드한 워크스페이스 이미지 크기", "template")
+)
+
+// "Step 3/12 : RUN apt-get ..." (클래식 빌더 출력)
+var buildStepPattern = regexp.MustCompile(`^Step \d+/\d+ : (\S+)`)
+
+// BuildStats 이미지 빌드 한 번의 소요 시간과 캐시 사용 현황
+// 캐시 판단 대상은 FROM을 제외한 Dockerfile 단계 (클래식 빌더의 "Using cache" 출력 기준)
+type BuildStats struct {
+	Template    string        `json:"template"`
+	UserID      string        `json:"user_id"`
+	BaseImage   string        `json:"base_image,omitempty"`
+	Image       string        `json:"image"`
+	StartedAt   time.Time     `json:"started_at"`
+	Duration    time.Duration `json:"-"`
+	Steps       int           `json:"steps"`
+	CachedSteps int           `json:"cached_steps"`
+	SizeBytes   int64         `json:"size_bytes"`
+	Error       string        `json:"error,omitempty"`
+}
+
+// buildMessage 이미지 빌드 API 응답 스트림의 한 줄
+type buildMessage struct {
+	Stream string `json:"stream"`
+	Error  string `json:"error"`
+}
+
+// readBuildOutput 빌드 응답 스트림을 끝까지 읽으며 단계/캐시 사용을 세고 빌드 오류를 반환 (빈 줄이 아닌 로그는 onLine으로 전달)
+func readBuildOutput(r io.Reader, stats *BuildStats, onLine func(string)) error {
+	dec := json.NewDecoder(r)
+	for {
+		var msg buildMessage
+		if err := dec.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("빌드 로그 처리 실패: %v", err)
+		}
+		if msg.Error != "" {
+			return fmt.Errorf("이미지 빌드 실패: %s", strings.TrimSpace(msg.Error))
+		}
+		for _, line := range strings.Split(msg.Stream, "\n") {
+			line = strings.TrimSpace(line)
+			if line != "" {
+				onLine(line)
+			}
+			if m := buildStepPattern.FindStringSubmatch(line); m != nil && !strings.EqualFold(m[1], "FROM") {
+				stats.Steps++
+			} else if line == "---> Using cache" {
+				stats.CachedSteps++
+			}
+		}
+	}
+}
+
+// CacheHitRate 캐시를 사용한 단계 비율 (단계가 없으면 0)
+func (b *BuildStats) CacheHitRate() float64 {
+	if b.Steps == 0 {
+		return 0
+	}
+	return float64(b.CachedSteps) / float64(b.Steps)
+}
+
+// record 빌드 결과를 템플릿별 메트릭에 반영
+func (b *BuildStats) record() {
+	template := b.Template
+	if template == "" {
+		template = DefaultImage
+	}
+	result := "ok"
+	if b.Error != "" {
+		result = "failed"
+	}
+	imageBuilds.Inc(template, result)
+	imageBuildSeconds.Observe(b.Duration.Seconds(), template)
+	imageBuildSteps.Add(float64(b.CachedSteps), template, "hit")
+	imageBuildSteps.Add(float64(b.Steps-b.CachedSteps), template, "miss")
+	if b.SizeBytes > 0 {
+		imageSizeBytes.Set(float64(b.SizeBytes), template)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
 	"log"
@@ -15,6 +16,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -23,13 +25,31 @@ import (
 	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/go-connections/nat"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+	"github.com/sandman/gpu-ssh-gateway/internal/lock"
+	"github.com/sandman/gpu-ssh-gateway/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/crypto/ssh"
 )
 
 type Client struct {
 	cli         *client.Client
 	portManager *PortManager
+
+	runtimeMu     sync.Mutex
+	runtimeStatus *RuntimeStatus
+
+	locker *lock.Locker // nil이면 단일 프로세스로 보고 락을 생략
+	naming Naming
+
+	dnsServer string // DNS 감사 사이드카 주소 (비어 있으면 Docker 기본 DNS)
+
+	workspaceVolumes *WorkspaceVolumes // nil이면 워크스페이스를 호스트 디렉토리로 bind 마운트
+
+	ownRemovals sync.Map // 직접 지운 리소스 ("<종류>/<이름>" → 시각), 이벤트를 외부 변경과 구분
 }
 
 type PortManager struct {
@@ -42,12 +62,88 @@ type PortManager struct {
 type ContainerConfig struct {
 	UserID        string
 	GPUUUID       string
-	WorkspaceDir  string
+	WorkspaceDir  string // 볼륨 백엔드에서 호스트가 볼륨 내용을 읽을 수 없으면 비어 있음
 	SSHPassword   string
 	SSHPrivateKey string
 	SSHPublicKey  string
 	Image         string
+	BaseImage     string // 워크스페이스 이미지의 FROM (비어 있으면 Dockerfile 기본값)
 	NetworkName   string
+	Timezone      string // TZ 및 /etc/localtime (비어 있으면 이미지 기본값)
+	Locale        string // LANG/LC_ALL
+	Timeouts      StepTimeouts
+	Resources     ResourceLimits
+	RestartPolicy RestartPolicy
+	Progress      ProgressFunc  // 단계 진행과 빌드 로그를 받을 콜백 (nil이면 보내지 않음)
+	StopTimeout   time.Duration // Docker가 직접 멈출 때(데몬 종료 등) SIGTERM 후 SIGKILL까지 (0이면 Docker 기본값)
+
+	// Context 단계별 span(docker.image_build, docker.container_create, docker.container_start)의 부모 (nil이면 새 트레이스)
+	// 취소는 쓰지 않으며 단계 시간 제한은 Timeouts로 정함
+	Context context.Context
+}
+
+// 컨테이너 생성 중 ProgressFunc로 알리는 단계
+const (
+	ProgressImageBuild      = "image_build"
+	ProgressContainerCreate = "container_create"
+	ProgressContainerStart  = "container_start"
+)
+
+// ProgressFunc 단계가 시작될 때(line 빈 값)와 이미지 빌드 로그 한 줄마다 호출되는 콜백
+type ProgressFunc func(step, line string)
+
+func (f ProgressFunc) report(step, line string) {
+	if f != nil {
+		f(step, line)
+	}
+}
+
+// ResourceLimits CPU/메모리 보장량과 버스트 상한 (0이면 설정하지 않음)
+type ResourceLimits struct {
+	CPUReservation    float64 // 코어 수 → CPUShares (cgroup v2 cpu.weight)
+	CPULimit          float64 // 코어 수 → NanoCPUs (cpu.max)
+	MemoryReservation int64   // 바이트 → MemoryReservation (memory.low)
+	MemoryLimit       int64   // 바이트 → Memory (memory.max), 스왑 없음
+}
+
+// apply 보장량은 경합 시의 상대 가중치/회수 보호로, 상한은 하드 제한으로 설정
+func (l ResourceLimits) apply(resources *container.Resources) {
+	if l.CPUReservation > 0 {
+		resources.CPUShares = int64(l.CPUReservation * 1024)
+	}
+	if l.CPULimit > 0 {
+		resources.NanoCPUs = int64(l.CPULimit * 1e9)
+	}
+	if l.MemoryReservation > 0 {
+		resources.MemoryReservation = l.MemoryReservation
+	}
+	if l.MemoryLimit > 0 {
+		resources.Memory = l.MemoryLimit
+		resources.MemorySwap = l.MemoryLimit
+	}
+}
+
+// StepTimeouts 컨테이너 생성 단계별 제한 시간 (0이면 제한 없음)
+type StepTimeouts struct {
+	ImageBuild      time.Duration
+	ContainerCreate time.Duration
+	ContainerStart  time.Duration
+}
+
+// stepContext 단계 제한 시간이 있으면 그만큼의 deadline을 가진 컨텍스트
+func stepContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// stepError 제한 시간 초과는 단계 이름과 함께 보고
+func stepError(step string, timeout time.Duration, err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%s 시간 초과 (%v)", step, timeout)
+	}
+	return fmt.Errorf("%s 실패: %v", step, err)
 }
 
 type ContainerInfo struct {
@@ -57,18 +153,31 @@ type ContainerInfo struct {
 	Status        string `json:"status"`
 	Created       string `json:"created"`
 	SSHPrivateKey string `json:"ssh_private_key"`
+	SSHPublicKey  string `json:"ssh_public_key"`
 	SSHPort       int    `json:"ssh_port"`
+
+	Build *BuildStats `json:"build,omitempty"` // 이 컨테이너의 이미지 빌드 기록
 }
 
+// 워크스페이스 이미지 빌드 컨텍스트 (오케스트레이터 컨테이너에 마운트된 소스 디렉토리)
+const (
+	buildContextDir     = "/app/source"
+	workspaceDockerfile = "Dockerfile.gpu-workspace"
+)
+
 const (
 	DefaultImage       = "gpu-workspace"
 	DefaultNetworkName = "sandman_worknet"
 	NetworkSubnet      = "10.100.0.0/16"
-	IPRangeStart       = 100 // 10.100.0.100부터 시작
-	IPRangeEnd         = 254 // 10.100.0.254까지
+	IPRangeStart       = 100 // 서브넷의 x.x.x.100부터 시작
+	IPRangeEnd         = 254 // x.x.x.254까지
 )
 
-func NewClient(sshPortStart, sshPortEnd int) (*Client, error) {
+func NewClient(sshPortStart, sshPortEnd int, naming Naming) (*Client, error) {
+	if err := naming.Validate(); err != nil {
+		return nil, fmt.Errorf("Docker 리소스 이름 설정 오류: %v", err)
+	}
+
 	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
 	if err != nil {
 		return nil, fmt.Errorf("Docker 클라이언트 생성 실패: %v", err)
@@ -83,6 +192,7 @@ func NewClient(sshPortStart, sshPortEnd int) (*Client, error) {
 	dockerClient := &Client{
 		cli:         cli,
 		portManager: portManager,
+		naming:      naming,
 	}
 
 	// 네트워크 초기화
@@ -104,7 +214,7 @@ func (pm *PortManager) AllocatePort() (int, error) {
 			return port, nil
 		}
 	}
-	return 0, fmt.Errorf("사용 가능한 포트가 없습니다")
+	return 0, errs.New(errs.ErrNoCapacity, "사용 가능한 포트가 없습니다")
 }
 
 func (pm *PortManager) ReleasePort(port int) {
@@ -113,6 +223,27 @@ func (pm *PortManager) ReleasePort(port int) {
 	delete(pm.usedPorts, port)
 }
 
+// SetLocker IPAM 등 복제본 간 임계 구역에 사용할 분산 락 설정
+func (c *Client) SetLocker(locker *lock.Locker) {
+	c.locker = locker
+}
+
+func (c *Client) acquireLock(name string) (func(), error) {
+	if c.locker == nil {
+		return func() {}, nil
+	}
+	lease, err := c.locker.Acquire(name)
+	if err != nil {
+		return nil, err
+	}
+	return lease.Release, nil
+}
+
+// Naming 이 클라이언트가 사용하는 리소스 이름 설정
+func (c *Client) Naming() Naming {
+	return c.naming
+}
+
 func (c *Client) Close() error {
 	return c.cli.Close()
 }
@@ -127,40 +258,48 @@ func (c *Client) ensureNetwork() error {
 	}
 
 	for _, net := range networks {
-		if net.Name == DefaultNetworkName {
-			log.Printf("🌐 기존 네트워크 사용: %s", DefaultNetworkName)
+		if net.Name == c.naming.NetworkName {
+			log.Printf("🌐 기존 네트워크 사용: %s", c.naming.NetworkName)
 			return nil
 		}
 	}
 
+	options := map[string]string{}
+	if bridge := c.naming.bridgeName(); bridge != "" {
+		options["com.docker.network.bridge.name"] = bridge
+	}
+
 	// 네트워크 생성
-	_, err = c.cli.NetworkCreate(ctx, DefaultNetworkName, types.NetworkCreate{
+	_, err = c.cli.NetworkCreate(ctx, c.naming.NetworkName, types.NetworkCreate{
 		Driver: "bridge",
 		IPAM: &network.IPAM{
 			Config: []network.IPAMConfig{
 				{
-					Subnet: NetworkSubnet,
+					Subnet: c.naming.NetworkSubnet,
 				},
 			},
 		},
-		Options: map[string]string{
-			"com.docker.network.bridge.name": DefaultNetworkName,
-		},
+		Options: options,
+		Labels:  c.naming.Labels(""),
 	})
 
 	if err != nil {
 		return fmt.Errorf("네트워크 생성 실패: %v", err)
 	}
 
-	log.Printf("🌐 새 네트워크 생성: %s (%s)", DefaultNetworkName, NetworkSubnet)
+	log.Printf("🌐 새 네트워크 생성: %s (%s)", c.naming.NetworkName, c.naming.NetworkSubnet)
 	return nil
 }
 
 func (c *Client) CreateContainer(config ContainerConfig) (*ContainerInfo, error) {
 	ctx := context.Background()
+	traceCtx := config.Context
+	if traceCtx == nil {
+		traceCtx = ctx
+	}
 
 	// SSH 키 쌍 생성
-	publicKey, privateKey, err := c.generateSSHKeyPair(config.UserID)
+	publicKey, privateKey, err := c.GenerateSSHKeyPair(config.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("SSH 키 생성 실패: %v", err)
 	}
@@ -168,26 +307,67 @@ func (c *Client) CreateContainer(config ContainerConfig) (*ContainerInfo, error)
 	log.Printf("🔑 SSH 키 쌍 생성 완료: %s", config.UserID)
 
 	// 이미지 빌드 (공개키를 ARG로 전달)
-	imageName, err := c.buildImageWithSSHKey(ctx, config.UserID, publicKey)
+	build := &BuildStats{Template: config.Image, UserID: config.UserID, BaseImage: config.BaseImage, StartedAt: time.Now()}
+	if build.Template == "" {
+		build.Template = DefaultImage
+	}
+	config.Progress.report(ProgressImageBuild, "")
+	_, span := tracing.Start(traceCtx, "docker.image_build",
+		attribute.String("sandman.template", build.Template), attribute.String("sandman.base_image", config.BaseImage))
+	buildCtx, cancel := stepContext(ctx, config.Timeouts.ImageBuild)
+	imageName, err := c.buildImageWithSSHKey(buildCtx, config.UserID, publicKey, config.BaseImage, build, config.Progress)
+	cancel()
+	build.Duration = time.Since(build.StartedAt)
 	if err != nil {
-		return nil, fmt.Errorf("이미지 빌드 실패: %v", err)
+		build.Error = err.Error()
+		build.record()
+		err = stepError("이미지 빌드", config.Timeouts.ImageBuild, err)
+		tracing.End(span, err)
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("sandman.build_steps", build.Steps), attribute.Int("sandman.build_cached_steps", build.CachedSteps))
+	span.End()
+	build.Image = imageName
+	if inspect, _, err := c.cli.ImageInspectWithRaw(ctx, imageName); err == nil {
+		build.SizeBytes = inspect.Size
 	}
+	build.record()
 
-	// 워크스페이스 디렉토리 생성
-	if err := c.ensureWorkspaceDir(config.WorkspaceDir); err != nil {
-		return nil, fmt.Errorf("워크스페이스 디렉토리 생성 실패: %v", err)
+	// 워크스페이스 마운트 (내용은 세션 서비스가 workspace 패키지로 미리 준비)
+	workspaceMount := mount.Mount{
+		Type:   mount.TypeBind,
+		Source: config.WorkspaceDir,
+		Target: "/workspace",
+	}
+	if c.workspaceVolumes != nil {
+		vol, err := c.EnsureWorkspaceVolume(config.UserID)
+		if err != nil {
+			return nil, err
+		}
+		workspaceMount = mount.Mount{
+			Type:   mount.TypeVolume,
+			Source: vol.Name,
+			Target: "/workspace",
+		}
+	}
+
+	// IP/포트 임대는 복제본 간 상호 배제 (컨테이너 생성으로 IP가 확정될 때까지 보유)
+	release, err := c.acquireLock("ipam")
+	if err != nil {
+		return nil, err
 	}
+	defer release()
 
 	// 사용 가능한 IP 찾기
 	ip, err := c.findAvailableIP()
 	if err != nil {
-		return nil, fmt.Errorf("사용 가능한 IP 찾기 실패: %v", err)
+		return nil, fmt.Errorf("사용 가능한 IP 찾기 실패: %w", err)
 	}
 
 	// SSH 포트 할당
 	sshPort, err := c.portManager.AllocatePort()
 	if err != nil {
-		return nil, fmt.Errorf("SSH 포트 할당 실패: %v", err)
+		return nil, fmt.Errorf("SSH 포트 할당 실패: %w", err)
 	}
 
 	// SSH 비밀번호 생성
@@ -199,7 +379,6 @@ func (c *Client) CreateContainer(config ContainerConfig) (*ContainerInfo, error)
 	containerConfig := &container.Config{
 		Image: imageName,
 		Env: []string{
-			"NVIDIA_VISIBLE_DEVICES=" + config.GPUUUID,
 			"SSH_PASSWORD=" + config.SSHPassword,
 			"USER_ID=" + config.UserID,
 		},
@@ -208,18 +387,41 @@ func (c *Client) CreateContainer(config ContainerConfig) (*ContainerInfo, error)
 		},
 		// Cmd:        []string{"/start.sh"},
 		WorkingDir: "/workspace",
+		Labels:     c.naming.Labels(config.UserID),
+	}
+	if config.StopTimeout > 0 {
+		stopTimeout := int(config.StopTimeout / time.Second)
+		containerConfig.StopTimeout = &stopTimeout
+	}
+
+	if config.GPUUUID != "" {
+		containerConfig.Env = append(containerConfig.Env, "NVIDIA_VISIBLE_DEVICES="+config.GPUUUID)
+	}
+
+	mounts := []mount.Mount{workspaceMount}
+
+	// 시간대/로케일 (start.sh가 SSH 로그인 세션에도 적용되도록 /etc/environment에 기록)
+	if config.Timezone != "" {
+		containerConfig.Env = append(containerConfig.Env, "TZ="+config.Timezone)
+		// 호스트에 zoneinfo가 있으면 /etc/localtime도 맞춤 (없으면 이미지의 tzdata와 TZ에 맡김)
+		zoneinfo := filepath.Join("/usr/share/zoneinfo", config.Timezone)
+		if _, err := os.Stat(zoneinfo); err == nil {
+			mounts = append(mounts, mount.Mount{
+				Type:     mount.TypeBind,
+				Source:   zoneinfo,
+				Target:   "/etc/localtime",
+				ReadOnly: true,
+			})
+		}
+	}
+	if config.Locale != "" {
+		containerConfig.Env = append(containerConfig.Env, "LANG="+config.Locale, "LC_ALL="+config.Locale)
 	}
 
 	// 호스트 설정 (공유 볼륨 제거)
 	hostConfig := &container.HostConfig{
-		Mounts: []mount.Mount{
-			{
-				Type:   mount.TypeBind,
-				Source: config.WorkspaceDir,
-				Target: "/workspace",
-			},
-		},
-		NetworkMode: container.NetworkMode(DefaultNetworkName),
+		Mounts:      mounts,
+		NetworkMode: container.NetworkMode(c.naming.NetworkName),
 		PortBindings: nat.PortMap{
 			"22/tcp": []nat.PortBinding{
 				{
@@ -238,10 +440,8 @@ func (c *Client) CreateContainer(config ContainerConfig) (*ContainerInfo, error)
 			},
 			PidsLimit: &[]int64{100}[0],
 		},
-		RestartPolicy: container.RestartPolicy{
-			Name: "no",
-		},
-		AutoRemove: false, // 포트 관리를 위해 자동 제거 비활성화
+		RestartPolicy: restartPolicy(config.RestartPolicy),
+		AutoRemove:    false, // 포트 관리를 위해 자동 제거 비활성화
 		SecurityOpt: []string{
 			"no-new-privileges:true",
 			"apparmor:unconfined",
@@ -249,10 +449,22 @@ func (c *Client) CreateContainer(config ContainerConfig) (*ContainerInfo, error)
 		ReadonlyRootfs: false,
 	}
 
+	config.Resources.apply(&hostConfig.Resources)
+
+	// DNS 감사가 켜져 있으면 사이드카를 거쳐 질의 (Docker 내장 DNS가 외부 질의를 사이드카로 넘김)
+	if c.dnsServer != "" {
+		hostConfig.DNS = []string{c.dnsServer}
+	}
+
+	// CPU 전용 세션과 가짜 GPU 빌드(-tags fakegpu)는 NVIDIA 장치 없이 실행
+	if config.GPUUUID == "" || gpu.Fake {
+		hostConfig.Resources.DeviceRequests = nil
+	}
+
 	// 네트워크 설정
 	networkConfig := &network.NetworkingConfig{
 		EndpointsConfig: map[string]*network.EndpointSettings{
-			DefaultNetworkName: {
+			c.naming.NetworkName: {
 				IPAMConfig: &network.EndpointIPAMConfig{
 					IPv4Address: ip,
 				},
@@ -261,19 +473,39 @@ func (c *Client) CreateContainer(config ContainerConfig) (*ContainerInfo, error)
 	}
 
 	// 컨테이너 생성
-	containerName := fmt.Sprintf("%s-container", config.UserID)
-	resp, err := c.cli.ContainerCreate(ctx, containerConfig, hostConfig, networkConfig, nil, containerName)
+	containerName := c.naming.ContainerName(config.UserID)
+	config.Progress.report(ProgressContainerCreate, "")
+	_, span = tracing.Start(traceCtx, "docker.container_create",
+		attribute.String("sandman.container_name", containerName), attribute.String("sandman.container_ip", ip), attribute.Int("sandman.ssh_port", sshPort))
+	createCtx, cancel := stepContext(ctx, config.Timeouts.ContainerCreate)
+	resp, err := c.cli.ContainerCreate(createCtx, containerConfig, hostConfig, networkConfig, nil, containerName)
+	cancel()
 	if err != nil {
 		c.portManager.ReleasePort(sshPort)
-		return nil, fmt.Errorf("컨테이너 생성 실패: %v", err)
+		if errdefs.IsConflict(err) {
+			err = errs.New(errs.ErrAlreadyExists, "컨테이너 생성 실패: %v", err)
+		} else {
+			err = stepError("컨테이너 생성", config.Timeouts.ContainerCreate, err)
+		}
+		tracing.End(span, err)
+		return nil, err
 	}
+	span.End()
 
 	// 컨테이너 시작
-	if err := c.cli.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+	config.Progress.report(ProgressContainerStart, "")
+	_, span = tracing.Start(traceCtx, "docker.container_start", attribute.String("sandman.container_id", resp.ID))
+	startCtx, cancel := stepContext(ctx, config.Timeouts.ContainerStart)
+	err = c.cli.ContainerStart(startCtx, resp.ID, types.ContainerStartOptions{})
+	cancel()
+	if err != nil {
 		c.portManager.ReleasePort(sshPort)
 		c.cli.ContainerRemove(ctx, resp.ID, types.ContainerRemoveOptions{Force: true})
-		return nil, fmt.Errorf("컨테이너 시작 실패: %v", err)
+		err = stepError("컨테이너 시작", config.Timeouts.ContainerStart, err)
+		tracing.End(span, err)
+		return nil, err
 	}
+	span.End()
 
 	log.Printf("✅ 컨테이너 생성 완료: %s (IP: %s, SSH 포트: %d)", resp.ID[:12], ip, sshPort)
 
@@ -284,23 +516,14 @@ func (c *Client) CreateContainer(config ContainerConfig) (*ContainerInfo, error)
 		Status:        "running",
 		Created:       time.Now().Format(time.RFC3339),
 		SSHPrivateKey: privateKey,
+		SSHPublicKey:  strings.TrimSpace(publicKey),
 		SSHPort:       sshPort,
+		Build:         build,
 	}, nil
 }
 
 func (c *Client) StopContainer(containerID string) error {
-	ctx := context.Background()
-
-	timeoutSeconds := 10
-	err := c.cli.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &timeoutSeconds})
-	if err != nil {
-		log.Printf("⚠️ 컨테이너 중지 실패 (강제 종료 시도): %v", err)
-		// 강제 종료 시도
-		return c.cli.ContainerKill(ctx, containerID, "SIGKILL")
-	}
-
-	log.Printf("🛑 컨테이너 중지됨: %s", containerID[:12])
-	return nil
+	return c.StopContainerWith(containerID, StopOptions{Timeout: defaultStopTimeout})
 }
 
 func (c *Client) RemoveContainer(containerID string) error {
@@ -328,6 +551,9 @@ func (c *Client) RemoveContainer(containerID string) error {
 	})
 
 	if err != nil {
+		if client.IsErrNotFound(err) {
+			return errs.New(errs.ErrNotFound, "컨테이너 제거 실패: %v", err)
+		}
 		return fmt.Errorf("컨테이너 제거 실패: %v", err)
 	}
 
@@ -346,13 +572,16 @@ func (c *Client) GetContainerInfo(containerID string) (*ContainerInfo, error) {
 	ctx := context.Background()
 
 	inspect, err := c.cli.ContainerInspect(ctx, containerID)
+	if client.IsErrNotFound(err) {
+		return nil, errs.Wrap(errs.ErrNotFound, err)
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	ip := ""
 	if inspect.NetworkSettings != nil && inspect.NetworkSettings.Networks != nil {
-		if netInfo, exists := inspect.NetworkSettings.Networks[DefaultNetworkName]; exists {
+		if netInfo, exists := inspect.NetworkSettings.Networks[c.naming.NetworkName]; exists {
 			ip = netInfo.IPAddress
 		}
 	}
@@ -386,63 +615,41 @@ func (c *Client) pullImageIfNotExists(ctx context.Context, image string) error {
 	return err
 }
 
-func (c *Client) ensureWorkspaceDir(path string) error {
-	if err := os.MkdirAll(path, 0755); err != nil {
-		return err
+func (c *Client) findAvailableIP() (string, error) {
+	usedIPs, err := c.usedIPs()
+	if err != nil {
+		return "", err
 	}
 
-	// 기본 파일들 생성
-	bashrcPath := filepath.Join(path, ".bashrc")
-	if _, err := os.Stat(bashrcPath); os.IsNotExist(err) {
-		bashrcContent := `# GPU SSH Gateway 워크스페이스
-export PS1='\[\033[01;32m\]\u@\h\[\033[00m\]:\[\033[01;34m\]\w\[\033[00m\]\$ '
-alias ll='ls -alF'
-alias la='ls -A'
-alias l='ls -CF'
-
-# GPU 정보 표시
-echo "🎮 할당된 GPU 정보:"
-nvidia-smi -L 2>/dev/null || echo "GPU 정보를 가져올 수 없습니다."
-echo "💾 워크스페이스: /workspace"
-echo "🔗 네트워크: ` + "`" + `hostname -I` + "`" + `"
-echo ""
-`
-		os.WriteFile(bashrcPath, []byte(bashrcContent), 0644)
+	// 사용 가능한 IP 찾기
+	for i := IPRangeStart; i <= IPRangeEnd; i++ {
+		ip := c.naming.hostIP(i)
+		if !usedIPs[ip] {
+			return ip, nil
+		}
 	}
 
-	return nil
+	return "", errs.New(errs.ErrNoCapacity, "사용 가능한 IP가 없습니다")
 }
 
-func (c *Client) findAvailableIP() (string, error) {
-	ctx := context.Background()
-
-	// 사용 중인 IP 목록 수집
-	usedIPs := make(map[string]bool)
-
-	containers, err := c.cli.ContainerList(ctx, types.ContainerListOptions{
+// usedIPs 세션 네트워크에 연결된 컨테이너들의 IP
+func (c *Client) usedIPs() (map[string]bool, error) {
+	containers, err := c.cli.ContainerList(context.Background(), types.ContainerListOptions{
 		All: true,
 	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
+	used := make(map[string]bool)
 	for _, container := range containers {
 		if container.NetworkSettings != nil && container.NetworkSettings.Networks != nil {
-			if netInfo, exists := container.NetworkSettings.Networks[DefaultNetworkName]; exists && netInfo.IPAddress != "" {
-				usedIPs[netInfo.IPAddress] = true
+			if netInfo, exists := container.NetworkSettings.Networks[c.naming.NetworkName]; exists && netInfo.IPAddress != "" {
+				used[netInfo.IPAddress] = true
 			}
 		}
 	}
-
-	// 사용 가능한 IP 찾기
-	for i := IPRangeStart; i <= IPRangeEnd; i++ {
-		ip := fmt.Sprintf("10.100.0.%d", i)
-		if !usedIPs[ip] {
-			return ip, nil
-		}
-	}
-
-	return "", fmt.Errorf("사용 가능한 IP가 없습니다")
+	return used, nil
 }
 
 func generateRandomPassword() string {
@@ -454,8 +661,8 @@ func generateRandomPassword() string {
 	return string(b)
 }
 
-// generateSSHKeyPair은 SSH 키 쌍을 생성합니다
-func (c *Client) generateSSHKeyPair(userID string) (string, string, error) {
+// GenerateSSHKeyPair은 SSH 키 쌍을 생성합니다 (authorized_keys 형식 공개키, PEM 개인키)
+func (c *Client) GenerateSSHKeyPair(userID string) (string, string, error) {
 	// 1. 개인키 생성
 	bits := 2048
 	privateKey, err := rsa.GenerateKey(rand.Reader, bits)
@@ -482,20 +689,20 @@ func (c *Client) generateSSHKeyPair(userID string) (string, string, error) {
 	return publicKey, privateKeyPEM, nil
 }
 
-// buildImageWithSSHKey는 SSH 공개키를 포함한 이미지를 빌드합니다
-func (c *Client) buildImageWithSSHKey(ctx context.Context, userID, publicKey string) (string, error) {
-	imageName := fmt.Sprintf("gpu-workspace-%s", userID)
+// buildImageWithSSHKey는 SSH 공개키를 포함한 이미지를 빌드합니다 (단계/캐시 사용은 stats에 기록)
+func (c *Client) buildImageWithSSHKey(ctx context.Context, userID, publicKey, baseImage string, stats *BuildStats, progress ProgressFunc) (string, error) {
+	imageName := c.naming.ImageName(userID)
 
 	log.Printf("🏗️ 사용자별 이미지 빌드 시작: %s", imageName)
 
 	// Dockerfile 경로 확인 (컨테이너 내 마운트된 경로)
-	dockerfilePath := "/app/source/Dockerfile.gpu-workspace"
+	dockerfilePath := filepath.Join(buildContextDir, workspaceDockerfile)
 	if _, err := os.Stat(dockerfilePath); os.IsNotExist(err) {
 		return "", fmt.Errorf("Dockerfile을 찾을 수 없습니다: %s", dockerfilePath)
 	}
 
 	// 빌드 컨텍스트 생성 (마운트된 소스 디렉토리)
-	buildContext, err := c.createBuildContext("/app/source")
+	buildContext, err := c.createBuildContext(buildContextDir)
 	if err != nil {
 		return "", fmt.Errorf("빌드 컨텍스트 생성 실패: %v", err)
 	}
@@ -503,7 +710,7 @@ func (c *Client) buildImageWithSSHKey(ctx context.Context, userID, publicKey str
 
 	// 빌드 옵션 설정
 	buildOptions := types.ImageBuildOptions{
-		Dockerfile: "Dockerfile.gpu-workspace", // 상대 경로로 변경
+		Dockerfile: workspaceDockerfile, // 빌드 컨텍스트 기준 상대 경로
 		Tags:       []string{imageName},
 		BuildArgs: map[string]*string{
 			"USERNAME": &userID,
@@ -511,11 +718,17 @@ func (c *Client) buildImageWithSSHKey(ctx context.Context, userID, publicKey str
 			"GID":      stringPtr("1001"),
 			"PUBKEY":   &publicKey,
 		},
+		Labels:      c.naming.Labels(userID),
 		Remove:      true,
 		ForceRemove: true,
 		NoCache:     false, // 캐시 사용으로 빌드 속도 향상
 	}
 
+	if baseImage != "" {
+		buildOptions.BuildArgs["BASE_IMAGE"] = &baseImage
+		log.Printf("🖼️ 베이스 이미지: %s", baseImage)
+	}
+
 	// 이미지 빌드
 	resp, err := c.cli.ImageBuild(ctx, buildContext, buildOptions)
 	if err != nil {
@@ -523,13 +736,13 @@ func (c *Client) buildImageWithSSHKey(ctx context.Context, userID, publicKey str
 	}
 	defer resp.Body.Close()
 
-	// 빌드 로그 처리 (에러 확인)
-	_, err = io.Copy(io.Discard, resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("빌드 로그 처리 실패: %v", err)
+	// 빌드 로그 처리 (에러 확인, 단계별 캐시 사용 집계)
+	if err := readBuildOutput(resp.Body, stats, func(line string) { progress.report(ProgressImageBuild, line) }); err != nil {
+		return "", err
 	}
 
-	log.Printf("✅ 사용자별 이미지 빌드 완료: %s", imageName)
+	log.Printf("✅ 사용자별 이미지 빌드 완료: %s (%s, 캐시 %d/%d 단계)", imageName,
+		time.Since(stats.StartedAt).Round(time.Millisecond), stats.CachedSteps, stats.Steps)
 	return imageName, nil
 }
 
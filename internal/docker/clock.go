@@ -0,0 +1,43 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClockOffset 컨테이너 안 시계와 이 호스트 시계의 차이 (양수면 컨테이너가 앞섬)
+// exec 전후 시각의 중간값과 비교하고, 왕복 시간의 절반을 측정 오차로 함께 반환
+func (c *Client) ClockOffset(ctx context.Context, containerID string) (offset, uncertainty time.Duration, err error) {
+	before := time.Now()
+	out, err := c.ExecOK(ctx, containerID, []string{"date", "+%s.%N"})
+	after := time.Now()
+	if err != nil {
+		return 0, 0, fmt.Errorf("컨테이너 시각 확인 실패: %v", err)
+	}
+
+	containerTime, err := parseEpoch(strings.TrimSpace(out))
+	if err != nil {
+		return 0, 0, err
+	}
+	roundTrip := after.Sub(before)
+	return containerTime.Sub(before.Add(roundTrip / 2)), roundTrip / 2, nil
+}
+
+// parseEpoch date +%s.%N 출력 해석 (%N을 지원하지 않는 date면 초 단위만 사용)
+func parseEpoch(value string) (time.Time, error) {
+	secPart, fracPart, _ := strings.Cut(value, ".")
+	sec, err := strconv.ParseInt(secPart, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("컨테이너 시각을 해석할 수 없습니다: %q", value)
+	}
+
+	var nsec int64
+	if len(fracPart) > 0 && strings.Trim(fracPart, "0123456789") == "" {
+		fracPart = (fracPart + "000000000")[:9]
+		nsec, _ = strconv.ParseInt(fracPart, 10, 64)
+	}
+	return time.Unix(sec, nsec), nil
+}
@@ -0,0 +1,203 @@
+package docker
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+const (
+	// dnsSidecarOctet DNS 사이드카 주소의 마지막 옥텟 (세션 IP 범위 IPRangeStart 아래)
+	dnsSidecarOctet = 53
+	// RoleDNS LabelRole 값: 세션이 아닌 DNS 감사 사이드카
+	RoleDNS = "dns"
+	// labelDNSConfig 사이드카를 만든 이미지/업스트림 (바뀌면 다시 만듦)
+	labelDNSConfig = "sandman.dns.config"
+)
+
+// DNSQuery 사이드카가 기록한 질의 한 건
+type DNSQuery struct {
+	At       time.Time
+	ClientIP string
+	Type     string
+	Name     string
+	RCode    string
+}
+
+// dnsCorefile 모든 질의를 업스트림으로 넘기고 "클라이언트 유형 이름 응답코드"를 한 줄씩 로그로 남기는 CoreDNS 설정
+func dnsCorefile(upstream string) string {
+	return fmt.Sprintf(`. {
+    forward . %s
+    log . "{remote} {type} {name} {rcode}"
+    errors
+}
+`, upstream)
+}
+
+// DNSSidecarName 배포별 DNS 사이드카 컨테이너 이름
+func (n Naming) DNSSidecarName() string {
+	return fmt.Sprintf("%ssandman-dns-%s", n.ContainerPrefix, n.Deployment)
+}
+
+// EnsureDNSSidecar 세션 네트워크에 CoreDNS 사이드카를 띄우고, 이후 만드는 세션 컨테이너가 이 주소를 DNS로 쓰도록 설정
+// 이미 실행 중이면 그대로 사용하고, 이미지나 업스트림이 바뀌었으면 다시 만듦
+func (c *Client) EnsureDNSSidecar(image, upstream string) (string, error) {
+	ctx := context.Background()
+	name := c.naming.DNSSidecarName()
+	ip := c.naming.hostIP(dnsSidecarOctet)
+	configKey := image + " " + upstream
+
+	inspect, err := c.cli.ContainerInspect(ctx, name)
+	switch {
+	case err == nil && inspect.Config.Labels[labelDNSConfig] == configKey:
+		if !inspect.State.Running {
+			if err := c.cli.ContainerStart(ctx, inspect.ID, types.ContainerStartOptions{}); err != nil {
+				return "", fmt.Errorf("DNS 사이드카 시작 실패: %v", err)
+			}
+		}
+		c.dnsServer = ip
+		log.Printf("🔎 기존 DNS 감사 사이드카 사용: %s (%s)", name, ip)
+		return ip, nil
+	case err == nil:
+		log.Printf("🔎 DNS 사이드카 설정이 바뀌어 다시 만듭니다: %s", name)
+		if err := c.cli.ContainerRemove(ctx, inspect.ID, types.ContainerRemoveOptions{Force: true}); err != nil {
+			return "", fmt.Errorf("이전 DNS 사이드카 제거 실패: %v", err)
+		}
+	case !errdefs.IsNotFound(err):
+		return "", fmt.Errorf("DNS 사이드카 조회 실패: %v", err)
+	}
+
+	if err := c.pullImageIfNotExists(ctx, image); err != nil {
+		return "", fmt.Errorf("DNS 사이드카 이미지 준비 실패: %v", err)
+	}
+
+	labels := c.naming.Labels("")
+	labels[LabelRole] = RoleDNS
+	labels[labelDNSConfig] = configKey
+	resp, err := c.cli.ContainerCreate(ctx,
+		&container.Config{
+			Image:  image,
+			Cmd:    []string{"-conf", "/Corefile"},
+			Labels: labels,
+		},
+		&container.HostConfig{
+			NetworkMode:   container.NetworkMode(c.naming.NetworkName),
+			RestartPolicy: container.RestartPolicy{Name: "unless-stopped"},
+		},
+		&network.NetworkingConfig{
+			EndpointsConfig: map[string]*network.EndpointSettings{
+				c.naming.NetworkName: {IPAMConfig: &network.EndpointIPAMConfig{IPv4Address: ip}},
+			},
+		},
+		nil, name)
+	if err != nil {
+		return "", fmt.Errorf("DNS 사이드카 생성 실패: %v", err)
+	}
+
+	// CoreDNS 이미지에는 셸이 없어 설정 파일을 tar로 복사
+	corefile, err := tarFile("Corefile", []byte(dnsCorefile(upstream)))
+	if err == nil {
+		err = c.cli.CopyToContainer(ctx, resp.ID, "/", corefile, types.CopyToContainerOptions{})
+	}
+	if err == nil {
+		err = c.cli.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{})
+	}
+	if err != nil {
+		c.cli.ContainerRemove(ctx, resp.ID, types.ContainerRemoveOptions{Force: true})
+		return "", fmt.Errorf("DNS 사이드카 시작 실패: %v", err)
+	}
+
+	c.dnsServer = ip
+	log.Printf("🔎 DNS 감사 사이드카 시작: %s (%s, 업스트림 %s)", name, ip, upstream)
+	return ip, nil
+}
+
+// FollowDNSLog since 이후 사이드카 로그를 따라가며 질의마다 handle 호출 (ctx가 끝나거나 로그 스트림이 끊기면 반환)
+func (c *Client) FollowDNSLog(ctx context.Context, since time.Time, handle func(DNSQuery)) error {
+	reader, err := c.cli.ContainerLogs(ctx, c.naming.DNSSidecarName(), types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     true,
+		Timestamps: true,
+		Since:      since.Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return fmt.Errorf("DNS 사이드카 로그 조회 실패: %v", err)
+	}
+	defer reader.Close()
+
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(pw, pw, reader)
+		pw.CloseWithError(err)
+	}()
+
+	scanner := bufio.NewScanner(pr)
+	for scanner.Scan() {
+		if query, ok := parseDNSLogLine(scanner.Text()); ok {
+			handle(query)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return io.ErrUnexpectedEOF
+}
+
+// parseDNSLogLine "<docker 타임스탬프> [INFO] 10.100.0.101:51234 A example.com. NOERROR" 형식 해석
+func parseDNSLogLine(line string) (DNSQuery, bool) {
+	timestamp, rest, ok := strings.Cut(line, " ")
+	if !ok {
+		return DNSQuery{}, false
+	}
+	at, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return DNSQuery{}, false
+	}
+	rest, ok = strings.CutPrefix(rest, "[INFO] ")
+	if !ok {
+		return DNSQuery{}, false
+	}
+	fields := strings.Fields(rest)
+	if len(fields) != 4 {
+		return DNSQuery{}, false
+	}
+	clientIP := fields[0]
+	if i := strings.LastIndex(clientIP, ":"); i >= 0 {
+		clientIP = clientIP[:i]
+	}
+	return DNSQuery{
+		At:       at,
+		ClientIP: strings.Trim(clientIP, "[]"),
+		Type:     fields[1],
+		Name:     strings.TrimSuffix(fields[2], "."),
+		RCode:    fields[3],
+	}, true
+}
+
+func tarFile(name string, content []byte) (io.Reader, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content))}); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(content); err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
@@ -0,0 +1,184 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+)
+
+// FileChange 이미지 대비 컨테이너 파일시스템 변경
+type FileChange struct {
+	Path string `json:"path"`
+	Kind string `json:"kind"` // added, modified, deleted
+}
+
+// driftIgnoredPrefixes 실행 중 자연히 바뀌거나 오케스트레이터가 직접 관리하는 경로 (드리프트로 보지 않음)
+var driftIgnoredPrefixes = []string{
+	"/workspace", "/tmp", "/run", "/var/run", "/var/log", "/var/tmp", "/var/cache", "/proc", "/sys", "/dev",
+	"/root/.cache", "/etc/environment", "/etc/ssh/sshd_config", "/etc/ssh/sandman_", "/etc/ssh/ssh_host_",
+	"/etc/hostname", "/etc/hosts", "/etc/resolv.conf", "/etc/mtab",
+}
+
+func driftIgnored(path string) bool {
+	for _, prefix := range driftIgnoredPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	// 사용자 홈의 SSH 키와 캐시 (authorized_keys는 협업자 추가로 바뀜)
+	if strings.HasPrefix(path, "/home/") && (strings.Contains(path, "/.ssh") || strings.Contains(path, "/.cache")) {
+		return true
+	}
+	return false
+}
+
+// FilesystemChanges 컨테이너가 이미지에서 바뀐 파일 목록 (변경된 상위 디렉토리와 무시 경로 제외)
+func (c *Client) FilesystemChanges(ctx context.Context, containerID string) ([]FileChange, error) {
+	changes, err := c.cli.ContainerDiff(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("컨테이너 변경 조회 실패: %v", err)
+	}
+
+	paths := make([]string, 0, len(changes))
+	kinds := make(map[string]container.ChangeType, len(changes))
+	for _, change := range changes {
+		if driftIgnored(change.Path) {
+			continue
+		}
+		paths = append(paths, change.Path)
+		kinds[change.Path] = change.Kind
+	}
+	sort.Strings(paths)
+
+	result := make([]FileChange, 0, len(paths))
+	for i, path := range paths {
+		// 하위 항목이 바뀌어 "modified"로 표시된 디렉토리는 생략
+		if kinds[path] == container.ChangeModify && i+1 < len(paths) && strings.HasPrefix(paths[i+1], path+"/") {
+			continue
+		}
+		kind := "modified"
+		switch kinds[path] {
+		case container.ChangeAdd:
+			kind = "added"
+		case container.ChangeDelete:
+			kind = "deleted"
+		}
+		result = append(result, FileChange{Path: path, Kind: kind})
+	}
+	return result, nil
+}
+
+// InstalledPackages 컨테이너에 설치된 dpkg/pip 패키지 ("dpkg:<이름>", "pip:<이름>" → 버전)
+// pip이 없는 이미지는 dpkg 패키지만 반환
+func (c *Client) InstalledPackages(ctx context.Context, containerID string) (map[string]string, error) {
+	packages := make(map[string]string)
+
+	out, err := c.ExecOK(ctx, containerID, []string{"dpkg-query", "-W", "-f", "${Package}\t${Version}\n"})
+	if err != nil {
+		return nil, fmt.Errorf("dpkg 패키지 조회 실패: %v", err)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if name, version, ok := strings.Cut(strings.TrimSpace(line), "\t"); ok {
+			packages["dpkg:"+name] = version
+		}
+	}
+
+	result, err := c.Exec(ctx, containerID, []string{"sh", "-c", "python3 -m pip list --format=freeze 2>/dev/null"})
+	if err == nil && result.ExitCode == 0 {
+		for _, line := range strings.Split(result.Stdout, "\n") {
+			if name, version, ok := strings.Cut(strings.TrimSpace(line), "=="); ok {
+				packages["pip:"+strings.ToLower(name)] = version
+			}
+		}
+	}
+	return packages, nil
+}
+
+// RecreateContainer 같은 이미지, 설정, 이름, IP, SSH 포트로 컨테이너를 새로 만들어 이미지 상태로 되돌림
+// 바인드 마운트된 워크스페이스는 유지되고 실행 중인 프로세스는 stop 종료 절차에 따라 종료됨
+func (c *Client) RecreateContainer(containerID string, stop StopOptions) (*ContainerInfo, error) {
+	return c.recreateContainer(containerID, stop, nil)
+}
+
+// recreateContainer 같은 이름, IP, 포트로 컨테이너를 다시 만들고 시작 (mutate가 있으면 생성 전에 설정을 바꿈)
+// 실행 중인 기존 컨테이너는 세션의 종료 절차(pre-stop, 제한 시간)대로 멈춘 뒤 제거
+func (c *Client) recreateContainer(containerID string, stop StopOptions, mutate func(*container.Config, *container.HostConfig)) (*ContainerInfo, error) {
+	ctx := context.Background()
+
+	inspect, err := c.cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, errs.Wrap(errs.ErrNotFound, fmt.Errorf("컨테이너 조회 실패: %v", err))
+	}
+
+	endpoints := make(map[string]*network.EndpointSettings)
+	ip := ""
+	if netInfo, ok := inspect.NetworkSettings.Networks[c.naming.NetworkName]; ok {
+		ip = netInfo.IPAddress
+		if netInfo.IPAMConfig != nil && netInfo.IPAMConfig.IPv4Address != "" {
+			ip = netInfo.IPAMConfig.IPv4Address
+		}
+		endpoints[c.naming.NetworkName] = &network.EndpointSettings{
+			IPAMConfig: &network.EndpointIPAMConfig{IPv4Address: ip},
+		}
+	}
+
+	sshPort := 0
+	if bindings, ok := inspect.HostConfig.PortBindings["22/tcp"]; ok && len(bindings) > 0 {
+		sshPort = parsePort(bindings[0].HostPort)
+	}
+
+	// pre-stop과 종료 제한 시간이 길 수 있으므로 IPAM 락을 잡기 전에 멈춤
+	running := inspect.State != nil && inspect.State.Running
+	if running {
+		if err := c.StopContainerWith(containerID, stop); err != nil {
+			log.Printf("⚠️ 재생성 전 컨테이너 %s 중지 실패 (강제 제거로 대체): %v", shortID(containerID), err)
+		}
+	}
+
+	// IP/포트를 다른 세션이 가져가지 않도록 제거부터 재생성까지 IPAM 락 보유
+	release, err := c.acquireLock("ipam")
+	if err != nil {
+		// 재생성하지 못하므로 멈춘 기존 컨테이너를 다시 시작
+		if running {
+			c.cli.ContainerStart(ctx, containerID, types.ContainerStartOptions{})
+		}
+		return nil, err
+	}
+	defer release()
+
+	if err := c.cli.ContainerRemove(ctx, containerID, types.ContainerRemoveOptions{Force: true}); err != nil {
+		return nil, fmt.Errorf("기존 컨테이너 제거 실패: %v", err)
+	}
+
+	if mutate != nil {
+		mutate(inspect.Config, inspect.HostConfig)
+	}
+	name := strings.TrimPrefix(inspect.Name, "/")
+	resp, err := c.cli.ContainerCreate(ctx, inspect.Config, inspect.HostConfig,
+		&network.NetworkingConfig{EndpointsConfig: endpoints}, nil, name)
+	if err != nil {
+		c.portManager.ReleasePort(sshPort)
+		return nil, fmt.Errorf("컨테이너 재생성 실패: %v", err)
+	}
+	if err := c.cli.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+		c.portManager.ReleasePort(sshPort)
+		c.cli.ContainerRemove(ctx, resp.ID, types.ContainerRemoveOptions{Force: true})
+		return nil, fmt.Errorf("재생성한 컨테이너 시작 실패: %v", err)
+	}
+
+	log.Printf("♻️ 컨테이너 재생성 완료: %s → %s (IP: %s, SSH 포트: %d)", containerID[:12], resp.ID[:12], ip, sshPort)
+	return &ContainerInfo{
+		ID:      resp.ID,
+		IP:      ip,
+		Image:   inspect.Config.Image,
+		Status:  "running",
+		SSHPort: sshPort,
+	}, nil
+}
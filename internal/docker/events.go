@@ -0,0 +1,124 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+// ownRemovalWindow 직접 지운 리소스의 삭제 이벤트를 기다리는 시간
+const ownRemovalWindow = time.Minute
+
+// ResourceEvent 이 배포의 네트워크, 워크스페이스 볼륨, 사용자 이미지가 삭제된 Docker 이벤트
+type ResourceEvent struct {
+	Type     string    `json:"type"`   // network, volume, image
+	Action   string    `json:"action"` // destroy, delete, untag
+	Name     string    `json:"name"`
+	UserID   string    `json:"user_id,omitempty"` // 사용자 볼륨/이미지면 소유 사용자
+	External bool      `json:"external"`          // 오케스트레이터가 지운 것이 아님 (docker rm 등)
+	At       time.Time `json:"at"`
+}
+
+// expectRemoval 곧 받을 삭제 이벤트가 오케스트레이터 자신의 작업임을 기록
+func (c *Client) expectRemoval(kind, name string) {
+	c.ownRemovals.Store(kind+"/"+name, time.Now())
+}
+
+// ownRemoval 최근에 직접 지운 리소스인지 (확인하면 기록을 지움)
+func (c *Client) ownRemoval(kind, name string) bool {
+	value, ok := c.ownRemovals.LoadAndDelete(kind + "/" + name)
+	return ok && time.Since(value.(time.Time)) < ownRemovalWindow
+}
+
+// FollowResourceEvents since 이후의 Docker 이벤트를 따라가며 이 배포의 리소스 삭제만 handle에 전달
+// 스트림이 끊기면 오류를 반환 (호출자가 마지막 이벤트 시각부터 다시 연결)
+func (c *Client) FollowResourceEvents(ctx context.Context, since time.Time, handle func(ResourceEvent)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	messages, errCh := c.cli.Events(ctx, types.EventsOptions{
+		Since: fmt.Sprintf("%d.%09d", since.Unix(), since.Nanosecond()),
+		Filters: filters.NewArgs(
+			filters.Arg("type", string(events.NetworkEventType)),
+			filters.Arg("type", string(events.VolumeEventType)),
+			filters.Arg("type", string(events.ImageEventType)),
+			filters.Arg("event", "destroy"),
+			filters.Arg("event", "delete"),
+			filters.Arg("event", "untag"),
+		),
+	})
+	for {
+		select {
+		case msg := <-messages:
+			if event, ok := c.resourceEvent(msg); ok {
+				handle(event)
+			}
+		case err := <-errCh:
+			return fmt.Errorf("Docker 이벤트 스트림 끊김: %v", err)
+		}
+	}
+}
+
+// resourceEvent 이 배포의 리소스에 대한 이벤트만 변환
+func (c *Client) resourceEvent(msg events.Message) (ResourceEvent, bool) {
+	event := ResourceEvent{Type: string(msg.Type), Action: string(msg.Action), At: time.Unix(0, msg.TimeNano)}
+	attrs := msg.Actor.Attributes
+
+	switch msg.Type {
+	case events.NetworkEventType:
+		if attrs["name"] != c.naming.NetworkName {
+			return event, false
+		}
+		event.Name = c.naming.NetworkName
+	case events.VolumeEventType:
+		prefix := c.naming.WorkspaceVolumeName("")
+		if !strings.HasPrefix(msg.Actor.ID, prefix) {
+			return event, false
+		}
+		event.Name = msg.Actor.ID
+		event.UserID = strings.TrimPrefix(msg.Actor.ID, prefix)
+	case events.ImageEventType:
+		// 이미지 이벤트에는 이미지 레이블이 함께 옴
+		if attrs[LabelManaged] != "true" || attrs[LabelDeployment] != c.naming.Deployment {
+			return event, false
+		}
+		event.Name = attrs["name"]
+		event.UserID = attrs[LabelUser]
+	default:
+		return event, false
+	}
+	event.External = !c.ownRemoval(event.Type, event.Name)
+	return event, true
+}
+
+// NetworkExists 세션 네트워크가 있는지
+func (c *Client) NetworkExists() (bool, error) {
+	networks, err := c.cli.NetworkList(context.Background(), types.NetworkListOptions{
+		Filters: filters.NewArgs(filters.Arg("name", c.naming.NetworkName)),
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, n := range networks {
+		// name 필터는 부분 일치이므로 이름을 다시 확인
+		if n.Name == c.naming.NetworkName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// EnsureNetwork 세션 네트워크가 없으면 다시 만듦 (외부에서 지워진 경우 복구)
+func (c *Client) EnsureNetwork() error {
+	release, err := c.acquireLock("network")
+	if err != nil {
+		return err
+	}
+	defer release()
+	return c.ensureNetwork()
+}
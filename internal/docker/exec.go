@@ -0,0 +1,64 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// ExecResult 컨테이너 내부 명령 실행 결과
+type ExecResult struct {
+	ExitCode int
+	Stdout   string
+	Stderr   string
+}
+
+// Exec 실행 중인 컨테이너 안에서 명령을 실행하고 종료될 때까지 대기
+func (c *Client) Exec(ctx context.Context, containerID string, cmd []string) (*ExecResult, error) {
+	created, err := c.cli.ContainerExecCreate(ctx, containerID, types.ExecConfig{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("exec 생성 실패: %v", err)
+	}
+
+	attach, err := c.cli.ContainerExecAttach(ctx, created.ID, types.ExecStartCheck{})
+	if err != nil {
+		return nil, fmt.Errorf("exec 연결 실패: %v", err)
+	}
+	defer attach.Close()
+
+	var stdout, stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &stderr, attach.Reader); err != nil {
+		return nil, fmt.Errorf("exec 출력 읽기 실패: %v", err)
+	}
+
+	inspect, err := c.cli.ContainerExecInspect(ctx, created.ID)
+	if err != nil {
+		return nil, fmt.Errorf("exec 상태 조회 실패: %v", err)
+	}
+
+	return &ExecResult{
+		ExitCode: inspect.ExitCode,
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+	}, nil
+}
+
+// ExecOK Exec와 같지만 0이 아닌 종료 코드를 에러로 변환
+func (c *Client) ExecOK(ctx context.Context, containerID string, cmd []string) (string, error) {
+	result, err := c.Exec(ctx, containerID, cmd)
+	if err != nil {
+		return "", err
+	}
+	if result.ExitCode != 0 {
+		return result.Stdout, fmt.Errorf("명령 실패 (종료 코드 %d): %s", result.ExitCode, strings.TrimSpace(result.Stderr))
+	}
+	return result.Stdout, nil
+}
@@ -0,0 +1,59 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var nvidiaSMIUUID = regexp.MustCompile(`\(UUID: ([^)\s]+)\)`)
+
+// VisibleGPUs 컨테이너 안에서 nvidia-smi -L로 보이는 GPU/MIG 장치 UUID 목록
+func (c *Client) VisibleGPUs(ctx context.Context, containerID string) ([]string, error) {
+	out, err := c.ExecOK(ctx, containerID, []string{"nvidia-smi", "-L"})
+	if err != nil {
+		return nil, fmt.Errorf("nvidia-smi -L 실행 실패: %v", err)
+	}
+
+	uuids := []string{}
+	for _, match := range nvidiaSMIUUID.FindAllStringSubmatch(out, -1) {
+		uuids = append(uuids, match[1])
+	}
+	return uuids, nil
+}
+
+// VerifyGPUAttachment 요청한 GPU/MIG 장치가 컨테이너 안에서 실제로 보이는지 확인
+// 런타임이 장치를 붙이는 중일 수 있어 timeout 안에서 몇 번 재시도
+func (c *Client) VerifyGPUAttachment(containerID, expectedUUID string, timeout time.Duration) error {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var (
+		visible []string
+		err     error
+	)
+	for {
+		visible, err = c.VisibleGPUs(ctx, containerID)
+		if err == nil {
+			for _, uuid := range visible {
+				if uuid == expectedUUID {
+					return nil
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if err != nil {
+				return fmt.Errorf("GPU 장치 확인 실패 (%v 동안 재시도): %v", timeout, err)
+			}
+			return fmt.Errorf("요청한 GPU 장치 %s가 컨테이너 안에 보이지 않습니다 (보이는 장치: %s)", expectedUUID, strings.Join(visible, ", "))
+		case <-time.After(time.Second):
+		}
+	}
+}
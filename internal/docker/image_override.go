@@ -0,0 +1,61 @@
+package docker
+
+import (
+	"context"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/sandman/gpu-ssh-gateway/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ImageOverride 세션 컨테이너를 다른 이미지로 바꿀 때의 빌드 설정
+type ImageOverride struct {
+	UserID    string
+	Template  string // 카탈로그 이미지 이름 (빌드 기록용)
+	BaseImage string // 비어 있으면 Dockerfile 기본값
+	PublicKey string // 세션의 SSH 공개키 (그대로 넣어 빌드하므로 게이트웨이 키가 계속 맞음)
+	Timeout   time.Duration
+	Stop      StopOptions     // 기존 컨테이너 종료 절차 (세션의 종료 제한 시간과 pre-stop)
+	Context   context.Context // docker.image_build span의 부모 (nil이면 새 트레이스)
+}
+
+// RecreateContainerWithImage 사용자 이미지를 새 베이스 이미지로 다시 빌드하고 그 이미지로 컨테이너를 다시 만듦
+// 이름, IP, SSH 포트, 워크스페이스, GPU, 환경 변수(SSH 비밀번호 포함)는 유지
+// 빌드가 끝날 때까지 기존 컨테이너는 그대로 실행되고, 빌드에 실패하면 아무것도 바꾸지 않음
+func (c *Client) RecreateContainerWithImage(containerID string, override ImageOverride) (*ContainerInfo, error) {
+	traceCtx := override.Context
+	if traceCtx == nil {
+		traceCtx = context.Background()
+	}
+
+	build := &BuildStats{Template: override.Template, UserID: override.UserID, BaseImage: override.BaseImage, StartedAt: time.Now()}
+	_, span := tracing.Start(traceCtx, "docker.image_build",
+		attribute.String("sandman.template", build.Template), attribute.String("sandman.base_image", override.BaseImage))
+	buildCtx, cancel := stepContext(context.Background(), override.Timeout)
+	imageName, err := c.buildImageWithSSHKey(buildCtx, override.UserID, override.PublicKey, override.BaseImage, build, nil)
+	cancel()
+	build.Duration = time.Since(build.StartedAt)
+	if err != nil {
+		build.Error = err.Error()
+		build.record()
+		err = stepError("이미지 빌드", override.Timeout, err)
+		tracing.End(span, err)
+		return nil, err
+	}
+	span.End()
+	build.Image = imageName
+	if inspect, _, err := c.cli.ImageInspectWithRaw(context.Background(), imageName); err == nil {
+		build.SizeBytes = inspect.Size
+	}
+	build.record()
+
+	info, err := c.recreateContainer(containerID, override.Stop, func(cfg *container.Config, host *container.HostConfig) {
+		cfg.Image = imageName
+	})
+	if err != nil {
+		return nil, err
+	}
+	info.Build = build
+	return info, nil
+}
@@ -0,0 +1,109 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+// RoleInit LabelRole 값: 워크스페이스를 준비하는 일회용 init 컨테이너
+const RoleInit = "init"
+
+// InitMount init 컨테이너에 붙일 마운트 (Volume이면 Source는 볼륨 이름)
+type InitMount struct {
+	Source   string
+	Target   string
+	Volume   bool
+	ReadOnly bool
+}
+
+// InitContainerName 사용자 워크스페이스 init 컨테이너 이름 (동시에 하나만 실행)
+func (n Naming) InitContainerName(userID string) string {
+	return n.ContainerName(userID) + "-init"
+}
+
+// RunInitContainer 마운트를 붙인 일회용 컨테이너에서 셸 스크립트를 실행하고 출력(stdout+stderr)을 반환
+// 컨테이너는 끝나면 항상 지우며, 이전 실행이 남긴 같은 이름의 컨테이너도 먼저 지움
+func (c *Client) RunInitContainer(ctx context.Context, userID, image, script string, mounts []InitMount) (string, error) {
+	if err := c.pullImageIfNotExists(ctx, image); err != nil {
+		return "", fmt.Errorf("init 이미지 준비 실패: %v", err)
+	}
+
+	name := c.naming.InitContainerName(userID)
+	if err := c.cli.ContainerRemove(ctx, name, types.ContainerRemoveOptions{Force: true}); err != nil && !errdefs.IsNotFound(err) {
+		return "", fmt.Errorf("이전 init 컨테이너 제거 실패: %v", err)
+	}
+
+	var binds []mount.Mount
+	for _, m := range mounts {
+		typ := mount.TypeBind
+		if m.Volume {
+			typ = mount.TypeVolume
+		}
+		binds = append(binds, mount.Mount{Type: typ, Source: m.Source, Target: m.Target, ReadOnly: m.ReadOnly})
+	}
+	labels := c.naming.Labels(userID)
+	labels[LabelRole] = RoleInit
+
+	resp, err := c.cli.ContainerCreate(ctx,
+		&container.Config{
+			Image:      image,
+			User:       "0:0",
+			Entrypoint: []string{"/bin/sh", "-c", script},
+			Labels:     labels,
+		},
+		&container.HostConfig{
+			Mounts: binds,
+			SecurityOpt: []string{
+				"no-new-privileges:true",
+			},
+		},
+		nil, nil, name)
+	if err != nil {
+		return "", fmt.Errorf("init 컨테이너 생성 실패: %v", err)
+	}
+	// 제한 시간으로 ctx가 끝나도 정리되도록 별도 컨텍스트 사용
+	defer c.cli.ContainerRemove(context.Background(), resp.ID, types.ContainerRemoveOptions{Force: true})
+
+	waitCh, errCh := c.cli.ContainerWait(ctx, resp.ID, container.WaitConditionNextExit)
+	if err := c.cli.ContainerStart(ctx, resp.ID, types.ContainerStartOptions{}); err != nil {
+		return "", fmt.Errorf("init 컨테이너 시작 실패: %v", err)
+	}
+
+	var exitCode int64
+	select {
+	case status := <-waitCh:
+		if status.Error != nil {
+			return "", fmt.Errorf("init 컨테이너 대기 실패: %s", status.Error.Message)
+		}
+		exitCode = status.StatusCode
+	case err := <-errCh:
+		return "", fmt.Errorf("init 컨테이너 대기 실패: %v", err)
+	}
+
+	output := c.containerOutput(resp.ID)
+	if exitCode != 0 {
+		return output, fmt.Errorf("init 컨테이너가 종료 코드 %d로 끝났습니다", exitCode)
+	}
+	log.Printf("📦 init 컨테이너 완료: %s (%s)", name, image)
+	return output, nil
+}
+
+// containerOutput 끝난 컨테이너의 stdout/stderr (읽지 못하면 빈 문자열)
+func (c *Client) containerOutput(containerID string) string {
+	reader, err := c.cli.ContainerLogs(context.Background(), containerID, types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true})
+	if err != nil {
+		return ""
+	}
+	defer reader.Close()
+	var out bytes.Buffer
+	stdcopy.StdCopy(&out, &out, reader)
+	return out.String()
+}
@@ -0,0 +1,120 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/client"
+)
+
+// ManagedContainer 오케스트레이터 네트워크에 연결된 컨테이너의 실제 상태
+type ManagedContainer struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	State   string `json:"state"`
+	IP      string `json:"ip"`
+	SSHPort int    `json:"ssh_port"`
+	GPUUUID string `json:"gpu_uuid"`
+	Image   string `json:"image"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewInspectClient 네트워크 생성 같은 초기화 없이 Docker 데몬에 연결 (관리 도구용)
+func NewInspectClient(naming Naming) (*Client, error) {
+	cli, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return nil, fmt.Errorf("Docker 클라이언트 생성 실패: %v", err)
+	}
+
+	return &Client{
+		cli:         cli,
+		portManager: &PortManager{usedPorts: make(map[int]bool)},
+		naming:      naming,
+	}, nil
+}
+
+// ListManagedContainers 오케스트레이터 네트워크의 모든 세션 컨테이너(중지된 것 포함)를 조회
+func (c *Client) ListManagedContainers() ([]*ManagedContainer, error) {
+	ctx := context.Background()
+
+	containers, err := c.cli.ContainerList(ctx, types.ContainerListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("network", c.naming.NetworkName)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("컨테이너 목록 조회 실패: %v", err)
+	}
+
+	result := make([]*ManagedContainer, 0, len(containers))
+	for _, summary := range containers {
+		// DNS 사이드카 같은 보조 컨테이너는 세션과 맞춰 보지 않음
+		if summary.Labels[LabelRole] != "" {
+			continue
+		}
+		inspect, err := c.cli.ContainerInspect(ctx, summary.ID)
+		if err != nil {
+			continue
+		}
+
+		managed := &ManagedContainer{
+			ID:    inspect.ID,
+			Name:  strings.TrimPrefix(inspect.Name, "/"),
+			State: inspect.State.Status,
+			Image: inspect.Config.Image,
+		}
+		managed.CreatedAt, _ = time.Parse(time.RFC3339Nano, inspect.Created)
+
+		if inspect.NetworkSettings != nil {
+			if netInfo, ok := inspect.NetworkSettings.Networks[c.naming.NetworkName]; ok {
+				managed.IP = netInfo.IPAddress
+				// 중지된 컨테이너는 IPAddress가 비어 있으므로 고정 할당 주소 사용
+				if managed.IP == "" && netInfo.IPAMConfig != nil {
+					managed.IP = netInfo.IPAMConfig.IPv4Address
+				}
+			}
+		}
+
+		if inspect.HostConfig != nil {
+			if bindings, ok := inspect.HostConfig.PortBindings["22/tcp"]; ok && len(bindings) > 0 {
+				managed.SSHPort = parsePort(bindings[0].HostPort)
+			}
+			for _, req := range inspect.HostConfig.DeviceRequests {
+				if len(req.DeviceIDs) > 0 {
+					managed.GPUUUID = req.DeviceIDs[0]
+				}
+			}
+		}
+
+		result = append(result, managed)
+	}
+
+	return result, nil
+}
+
+// PortLeases 세션 SSH 포트로 잡아 둔 호스트 포트 (이 프로세스의 기록)
+func (c *Client) PortLeases() []int {
+	c.portManager.mu.Lock()
+	defer c.portManager.mu.Unlock()
+	ports := make([]int, 0, len(c.portManager.usedPorts))
+	for port := range c.portManager.usedPorts {
+		ports = append(ports, port)
+	}
+	return ports
+}
+
+// LeasePort 컨테이너에 이미 바인딩된 포트를 잡아 둠 (재시작으로 기록이 빠졌을 때 다른 세션에 주지 않도록)
+func (c *Client) LeasePort(port int) {
+	c.portManager.mu.Lock()
+	defer c.portManager.mu.Unlock()
+	c.portManager.usedPorts[port] = true
+}
+
+// ReleasePortLease 어떤 컨테이너에도 바인딩되지 않은 포트 기록을 해제
+func (c *Client) ReleasePortLease(port int) {
+	c.portManager.ReleasePort(port)
+}
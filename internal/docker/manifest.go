@@ -0,0 +1,92 @@
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// manifestRedactedEnv 매니페스트에 값을 남기지 않는 환경 변수 (이름만 남김)
+var manifestRedactedEnv = map[string]bool{
+	"SSH_PASSWORD": true,
+}
+
+// MountSpec 컨테이너 마운트 하나
+type MountSpec struct {
+	Type     string `json:"type"`
+	Source   string `json:"source"`
+	Target   string `json:"target"`
+	ReadOnly bool   `json:"read_only,omitempty"`
+}
+
+// ContainerSpec 세션 컨테이너를 다시 만드는 데 필요한 이미지와 실행 설정
+type ContainerSpec struct {
+	ImageID     string      `json:"image_id"`               // sha256:... (빌드한 사용자별 이미지)
+	RepoDigests []string    `json:"repo_digests,omitempty"` // 레지스트리에 올린 이미지면 repo@sha256:...
+	Env         []string    `json:"env"`                    // 정렬됨, 비밀 값은 <redacted>
+	Mounts      []MountSpec `json:"mounts"`
+}
+
+// InspectContainerSpec 실행 중인 컨테이너의 이미지 ID와 환경 변수, 마운트
+func (c *Client) InspectContainerSpec(ctx context.Context, containerID string) (*ContainerSpec, error) {
+	inspect, err := c.cli.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return nil, fmt.Errorf("컨테이너 조회 실패: %v", err)
+	}
+
+	spec := &ContainerSpec{ImageID: inspect.Image, Env: []string{}, Mounts: []MountSpec{}}
+	if image, _, err := c.cli.ImageInspectWithRaw(ctx, inspect.Image); err == nil {
+		spec.RepoDigests = image.RepoDigests
+	}
+	if inspect.Config != nil {
+		for _, kv := range inspect.Config.Env {
+			name, _, _ := strings.Cut(kv, "=")
+			if manifestRedactedEnv[name] {
+				kv = name + "=<redacted>"
+			}
+			spec.Env = append(spec.Env, kv)
+		}
+	}
+	sort.Strings(spec.Env)
+	if inspect.HostConfig != nil {
+		for _, m := range inspect.HostConfig.Mounts {
+			spec.Mounts = append(spec.Mounts, MountSpec{Type: string(m.Type), Source: m.Source, Target: m.Target, ReadOnly: m.ReadOnly})
+		}
+	}
+	return spec, nil
+}
+
+// ImageDigest 로컬에 있는 이미지의 repo@sha256:... 참조 (레지스트리에서 받지 않은 이미지면 "")
+func (c *Client) ImageDigest(ctx context.Context, ref string) string {
+	image, _, err := c.cli.ImageInspectWithRaw(ctx, ref)
+	if err != nil || len(image.RepoDigests) == 0 {
+		return ""
+	}
+	return image.RepoDigests[0]
+}
+
+// WorkspaceTemplate 워크스페이스 Dockerfile의 SHA-256과 기본 베이스 이미지 (ARG BASE_IMAGE의 기본값)
+func WorkspaceTemplate() (digest, defaultBase string, err error) {
+	data, err := os.ReadFile(filepath.Join(buildContextDir, workspaceDockerfile))
+	if err != nil {
+		return "", "", fmt.Errorf("Dockerfile 읽기 실패: %v", err)
+	}
+	sum := sha256.Sum256(data)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if value, ok := strings.CutPrefix(line, "ARG BASE_IMAGE="); ok {
+			defaultBase = strings.Trim(value, `"`)
+			break
+		}
+	}
+	return "sha256:" + hex.EncodeToString(sum[:]), defaultBase, nil
+}
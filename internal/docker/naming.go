@@ -0,0 +1,114 @@
+package docker
+
+import (
+	"fmt"
+	"net"
+)
+
+const (
+	// LabelManaged 오케스트레이터가 만든 리소스 표시
+	LabelManaged = "sandman.managed"
+	// LabelDeployment 리소스를 만든 배포 이름 (같은 호스트의 staging/prod 구분)
+	LabelDeployment = "sandman.deployment"
+	// LabelUser 컨테이너/이미지 소유 사용자
+	LabelUser = "sandman.user"
+	// LabelRole 세션이 아닌 보조 컨테이너의 역할 (예: dns), 고아 컨테이너 정리 대상에서 제외
+	LabelRole = "sandman.role"
+
+	// maxBridgeNameLen 리눅스 인터페이스 이름 길이 제한 (IFNAMSIZ - 1)
+	maxBridgeNameLen = 15
+)
+
+// Naming 배포별로 달라야 하는 Docker 리소스 이름과 레이블
+// 같은 호스트에서 여러 오케스트레이터를 띄울 때 네트워크, 이미지, 컨테이너 이름이 겹치지 않도록 설정
+type Naming struct {
+	Deployment      string
+	NetworkName     string
+	NetworkSubnet   string
+	ImagePrefix     string
+	ContainerPrefix string
+	ContainerSuffix string
+}
+
+// DefaultNaming 기존 하드코딩 값과 동일한 기본 이름
+func DefaultNaming() Naming {
+	return Naming{
+		Deployment:      "default",
+		NetworkName:     DefaultNetworkName,
+		NetworkSubnet:   NetworkSubnet,
+		ImagePrefix:     DefaultImage,
+		ContainerSuffix: "-container",
+	}
+}
+
+// Validate 이름 설정이 Docker 리소스로 사용 가능한지 확인
+func (n Naming) Validate() error {
+	if n.Deployment == "" {
+		return fmt.Errorf("배포 이름이 비어 있습니다")
+	}
+	if n.NetworkName == "" {
+		return fmt.Errorf("네트워크 이름이 비어 있습니다")
+	}
+	if n.ImagePrefix == "" {
+		return fmt.Errorf("이미지 접두사가 비어 있습니다")
+	}
+	if _, _, err := n.subnetBase(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ImageName 사용자별 이미지 이름 (<접두사>-<사용자>)
+func (n Naming) ImageName(userID string) string {
+	return fmt.Sprintf("%s-%s", n.ImagePrefix, userID)
+}
+
+// ContainerName 사용자별 컨테이너 이름 (<접두사><사용자><접미사>)
+func (n Naming) ContainerName(userID string) string {
+	return n.ContainerPrefix + userID + n.ContainerSuffix
+}
+
+// Labels 이 배포가 만든 리소스에 붙일 레이블
+func (n Naming) Labels(userID string) map[string]string {
+	labels := map[string]string{
+		LabelManaged:    "true",
+		LabelDeployment: n.Deployment,
+	}
+	if userID != "" {
+		labels[LabelUser] = userID
+	}
+	return labels
+}
+
+// bridgeName 호스트 브리지 인터페이스 이름 (길이 제한을 넘으면 Docker 기본 이름 사용)
+func (n Naming) bridgeName() string {
+	if len(n.NetworkName) > maxBridgeNameLen {
+		return ""
+	}
+	return n.NetworkName
+}
+
+// subnetBase 컨테이너 IP를 배정할 서브넷의 앞 세 옥텟
+func (n Naming) subnetBase() (net.IP, *net.IPNet, error) {
+	ip, ipNet, err := net.ParseCIDR(n.NetworkSubnet)
+	if err != nil {
+		return nil, nil, fmt.Errorf("잘못된 네트워크 서브넷: %s", n.NetworkSubnet)
+	}
+	base := ip.Mask(ipNet.Mask).To4()
+	if base == nil {
+		return nil, nil, fmt.Errorf("IPv4 서브넷만 지원합니다: %s", n.NetworkSubnet)
+	}
+	if ones, _ := ipNet.Mask.Size(); ones > 24 {
+		return nil, nil, fmt.Errorf("서브넷은 /24 이상이어야 합니다: %s", n.NetworkSubnet)
+	}
+	return base, ipNet, nil
+}
+
+// hostIP 서브넷 안에서 마지막 옥텟이 i인 주소
+func (n Naming) hostIP(i int) string {
+	base, _, err := n.subnetBase()
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d.%d.%d.%d", base[0], base[1], base[2], i)
+}
@@ -0,0 +1,117 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+)
+
+// sshListenPort 컨테이너 sshd 포트 (/proc/net/tcp의 16진수 표기)
+const sshListenPort = "0016"
+
+// PauseContainer 컨테이너의 모든 프로세스를 멈춤 (cgroup freezer, 메모리와 GPU 할당은 유지)
+func (c *Client) PauseContainer(containerID string) error {
+	if err := c.cli.ContainerPause(context.Background(), containerID); err != nil {
+		return fmt.Errorf("컨테이너 일시 정지 실패: %v", err)
+	}
+	log.Printf("⏸️ 컨테이너 일시 정지: %s", shortID(containerID))
+	return nil
+}
+
+// UnpauseContainer 일시 정지한 컨테이너 재개
+func (c *Client) UnpauseContainer(containerID string) error {
+	if err := c.cli.ContainerUnpause(context.Background(), containerID); err != nil {
+		return fmt.Errorf("컨테이너 재개 실패: %v", err)
+	}
+	log.Printf("▶️ 컨테이너 재개: %s", shortID(containerID))
+	return nil
+}
+
+// ContainerActivity 컨테이너 sshd에 연결된 TCP 세션 수와 누적 CPU 사용 시간(ns)
+// 연결 수는 컨테이너 네트워크 네임스페이스의 /proc/net/tcp{,6}에서 22번 포트 ESTABLISHED 항목을 셈
+func (c *Client) ContainerActivity(containerID string) (sshConnections int, cpuNanos uint64, err error) {
+	output, err := c.ExecOK(context.Background(), containerID,
+		[]string{"sh", "-c", "cat /proc/net/tcp /proc/net/tcp6 2>/dev/null"})
+	if err != nil {
+		return 0, 0, fmt.Errorf("SSH 연결 조회 실패: %v", err)
+	}
+	sshConnections = countEstablished(output, sshListenPort)
+
+	resp, err := c.cli.ContainerStatsOneShot(context.Background(), containerID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("컨테이너 통계 조회 실패: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var stats types.StatsJSON
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return 0, 0, fmt.Errorf("컨테이너 통계 파싱 실패: %v", err)
+	}
+	return sshConnections, stats.CPUStats.CPUUsage.TotalUsage, nil
+}
+
+// countEstablished /proc/net/tcp 형식에서 로컬 포트가 port(16진수)인 ESTABLISHED(01) 연결 수
+func countEstablished(output, port string) int {
+	count := 0
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[3] != "01" {
+			continue
+		}
+		if _, localPort, ok := strings.Cut(fields[1], ":"); ok && strings.EqualFold(localPort, port) {
+			count++
+		}
+	}
+	return count
+}
+
+func shortID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+// StartContainer 멈춘 컨테이너를 다시 시작 (이미 실행 중이면 아무것도 하지 않음)
+func (c *Client) StartContainer(containerID string) error {
+	if err := c.cli.ContainerStart(context.Background(), containerID, types.ContainerStartOptions{}); err != nil {
+		if client.IsErrNotFound(err) {
+			return errs.New(errs.ErrNotFound, "컨테이너 %s를 찾을 수 없습니다", shortID(containerID))
+		}
+		return fmt.Errorf("컨테이너 시작 실패: %v", err)
+	}
+	log.Printf("▶️ 컨테이너 시작됨: %s", shortID(containerID))
+	return nil
+}
+
+// RecreateContainerWithGPU 다른 MIG 인스턴스를 붙여 컨테이너를 다시 만듦 (이름, IP, SSH 포트, 워크스페이스는 유지)
+func (c *Client) RecreateContainerWithGPU(containerID, gpuUUID string, stop StopOptions) (*ContainerInfo, error) {
+	return c.recreateContainer(containerID, stop, func(cfg *container.Config, host *container.HostConfig) {
+		env := cfg.Env[:0]
+		for _, e := range cfg.Env {
+			if !strings.HasPrefix(e, "NVIDIA_VISIBLE_DEVICES=") {
+				env = append(env, e)
+			}
+		}
+		host.Resources.DeviceRequests = nil
+		if gpuUUID != "" {
+			env = append(env, "NVIDIA_VISIBLE_DEVICES="+gpuUUID)
+			if !gpu.Fake {
+				host.Resources.DeviceRequests = []container.DeviceRequest{{
+					Driver:       "nvidia",
+					DeviceIDs:    []string{gpuUUID},
+					Capabilities: [][]string{{"gpu"}},
+				}}
+			}
+		}
+		cfg.Env = env
+	})
+}
@@ -0,0 +1,46 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/docker/docker/api/types/network"
+)
+
+// IsolateContainer 컨테이너를 세션 네트워크에서 떼어 SSH 포트와 외부 통신을 모두 끊음 (컨테이너, 프로세스, 워크스페이스는 그대로)
+func (c *Client) IsolateContainer(containerID string) error {
+	if err := c.cli.NetworkDisconnect(context.Background(), c.naming.NetworkName, containerID, true); err != nil {
+		return fmt.Errorf("세션 네트워크 분리 실패: %v", err)
+	}
+	log.Printf("🚧 컨테이너 네트워크 격리: %s", shortID(containerID))
+	return nil
+}
+
+// ReconnectContainer 격리한 컨테이너를 세션 네트워크에 다시 연결하고 실제 IP를 반환
+// 격리된 동안 preferredIP를 다른 컨테이너가 가져갔으면 빈 IP를 새로 받음
+func (c *Client) ReconnectContainer(containerID, preferredIP string) (string, error) {
+	release, err := c.acquireLock("ipam")
+	if err != nil {
+		return "", err
+	}
+	defer release()
+
+	used, err := c.usedIPs()
+	if err != nil {
+		return "", err
+	}
+	ip := preferredIP
+	if ip == "" || used[ip] {
+		if ip, err = c.findAvailableIP(); err != nil {
+			return "", err
+		}
+	}
+
+	endpoint := &network.EndpointSettings{IPAMConfig: &network.EndpointIPAMConfig{IPv4Address: ip}}
+	if err := c.cli.NetworkConnect(context.Background(), c.naming.NetworkName, containerID, endpoint); err != nil {
+		return "", fmt.Errorf("세션 네트워크 재연결 실패: %v", err)
+	}
+	log.Printf("🔗 컨테이너 네트워크 재연결: %s (IP: %s)", shortID(containerID), ip)
+	return ip, nil
+}
@@ -0,0 +1,112 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+)
+
+// RestartPolicy 세션 컨테이너 재시작 정책 (Name이 비어 있으면 "no")
+type RestartPolicy struct {
+	Name       string // "no" 또는 "on-failure"
+	MaxRetries int    // on-failure 최대 재시도 횟수 (0이면 무제한)
+}
+
+func restartPolicy(policy RestartPolicy) container.RestartPolicy {
+	if policy.Name == "" || policy.Name == "no" {
+		return container.RestartPolicy{Name: "no"}
+	}
+	return container.RestartPolicy{Name: policy.Name, MaximumRetryCount: policy.MaxRetries}
+}
+
+// RestartCount Docker가 컨테이너를 재시작한 누적 횟수
+func (c *Client) RestartCount(containerID string) (int, error) {
+	inspect, err := c.cli.ContainerInspect(context.Background(), containerID)
+	if client.IsErrNotFound(err) {
+		return 0, errs.Wrap(errs.ErrNotFound, err)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("컨테이너 조회 실패: %v", err)
+	}
+	return inspect.RestartCount, nil
+}
+
+// DisableRestart 재시작 정책을 "no"로 바꿔 더 이상 재시작하지 않게 함
+func (c *Client) DisableRestart(containerID string) error {
+	_, err := c.cli.ContainerUpdate(context.Background(), containerID, container.UpdateConfig{
+		RestartPolicy: container.RestartPolicy{Name: "no"},
+	})
+	if err != nil {
+		return fmt.Errorf("재시작 정책 변경 실패: %v", err)
+	}
+	return nil
+}
+
+// LogTail 컨테이너 stdout/stderr 마지막 lines줄 (재시작 전 로그 포함)
+func (c *Client) LogTail(containerID string, lines int) ([]byte, error) {
+	reader, err := c.cli.ContainerLogs(context.Background(), containerID, types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Timestamps: true,
+		Tail:       strconv.Itoa(lines),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("컨테이너 로그 조회 실패: %v", err)
+	}
+	defer reader.Close()
+
+	var out bytes.Buffer
+	if _, err := stdcopy.StdCopy(&out, &out, reader); err != nil {
+		return nil, fmt.Errorf("컨테이너 로그 읽기 실패: %v", err)
+	}
+	return out.Bytes(), nil
+}
+
+// LogOptions 세션 로그 조회 범위
+type LogOptions struct {
+	Follow     bool      // 새로 쓰이는 로그를 계속 따라감 (ctx가 끝나거나 컨테이너가 멈추면 끝남)
+	Tail       int       // 마지막 몇 줄부터 (0 이하면 전체)
+	Since      time.Time // 이 시각 이후 로그만 (비어 있으면 처음부터)
+	Timestamps bool      // 줄 앞에 Docker 타임스탬프(RFC3339Nano)를 붙임
+}
+
+// StreamLogs 컨테이너 stdout/stderr를 합친 로그 스트림 (호출자가 닫아야 함)
+func (c *Client) StreamLogs(ctx context.Context, containerID string, opts LogOptions) (io.ReadCloser, error) {
+	options := types.ContainerLogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     opts.Follow,
+		Timestamps: opts.Timestamps,
+		Tail:       "all",
+	}
+	if opts.Tail > 0 {
+		options.Tail = strconv.Itoa(opts.Tail)
+	}
+	if !opts.Since.IsZero() {
+		options.Since = opts.Since.Format(time.RFC3339Nano)
+	}
+	reader, err := c.cli.ContainerLogs(ctx, containerID, options)
+	if client.IsErrNotFound(err) {
+		return nil, errs.Wrap(errs.ErrNotFound, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("컨테이너 로그 조회 실패: %v", err)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := stdcopy.StdCopy(pw, pw, reader)
+		reader.Close()
+		pw.CloseWithError(err)
+	}()
+	return pr, nil
+}
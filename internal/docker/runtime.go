@@ -0,0 +1,82 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+)
+
+const (
+	nvidiaRuntimeName = "nvidia"
+	// runtimeCheckTTL 데몬 정보 재조회 주기 (세션 생성마다 Info API를 호출하지 않도록 캐시)
+	runtimeCheckTTL = 30 * time.Second
+)
+
+// RuntimeStatus Docker 데몬의 NVIDIA 런타임 구성 상태
+type RuntimeStatus struct {
+	Available      bool      `json:"available"`
+	DefaultRuntime string    `json:"default_runtime"`
+	Runtimes       []string  `json:"runtimes"`
+	Error          string    `json:"error,omitempty"`
+	CheckedAt      time.Time `json:"checked_at"`
+}
+
+// CheckNVIDIARuntime 데몬 정보를 조회해 nvidia 런타임 등록 여부를 확인하고 결과를 캐시
+func (c *Client) CheckNVIDIARuntime() *RuntimeStatus {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	status := &RuntimeStatus{CheckedAt: time.Now(), Runtimes: []string{}}
+
+	info, err := c.cli.Info(ctx)
+	if err != nil {
+		status.Error = fmt.Sprintf("Docker 데몬 정보 조회 실패: %v", err)
+	} else {
+		status.DefaultRuntime = info.DefaultRuntime
+		for name := range info.Runtimes {
+			status.Runtimes = append(status.Runtimes, name)
+		}
+		sort.Strings(status.Runtimes)
+
+		// 가짜 GPU 빌드(-tags fakegpu)는 nvidia 런타임 없이 컨테이너를 실행
+		if _, ok := info.Runtimes[nvidiaRuntimeName]; ok || gpu.Fake {
+			status.Available = true
+		} else {
+			status.Error = "Docker 데몬에 nvidia 런타임이 등록되어 있지 않습니다 (nvidia-container-toolkit 설치 및 'nvidia-ctk runtime configure --runtime=docker' 필요)"
+		}
+	}
+
+	c.runtimeMu.Lock()
+	c.runtimeStatus = status
+	c.runtimeMu.Unlock()
+
+	if !status.Available {
+		log.Printf("⚠️ NVIDIA 런타임 확인 실패: %s", status.Error)
+	}
+	return status
+}
+
+// NVIDIARuntimeStatus 캐시된 런타임 상태를 반환하고, 오래되었으면 다시 확인
+func (c *Client) NVIDIARuntimeStatus() *RuntimeStatus {
+	c.runtimeMu.Lock()
+	status := c.runtimeStatus
+	c.runtimeMu.Unlock()
+
+	if status == nil || time.Since(status.CheckedAt) > runtimeCheckTTL {
+		return c.CheckNVIDIARuntime()
+	}
+	return status
+}
+
+// Ping Docker 데몬 응답 여부 확인
+func (c *Client) Ping() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := c.cli.Ping(ctx)
+	return err
+}
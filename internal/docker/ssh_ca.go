@@ -0,0 +1,53 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+const (
+	sshCAKeyPath       = "/etc/ssh/sandman_ca.pub"
+	sshRevokedKeysPath = "/etc/ssh/sandman_revoked_keys"
+	sshPrincipalsDir   = "/etc/ssh/sandman_principals"
+)
+
+// InstallSSHCA 컨테이너 sshd가 CA 서명 인증서를 신뢰하도록 설정하고 sshd 설정을 다시 읽게 함
+// principal 파일은 사용자별로 두어 해당 세션 principal이 담긴 인증서만 허용
+// 기존 authorized_keys 로그인은 그대로 동작
+func (c *Client) InstallSSHCA(containerID, user, caPublicKey, principal string) error {
+	script := `set -e
+ca="$0"; user="$1"; principal="$2"; ca_file="$3"; revoked="$4"; pdir="$5"
+printf '%s\n' "$ca" > "$ca_file"
+touch "$revoked"
+mkdir -p "$pdir"
+printf '%s\n' "$principal" > "$pdir/$user"
+chmod 644 "$ca_file" "$revoked" "$pdir/$user"
+if ! grep -q "^TrustedUserCAKeys $ca_file" /etc/ssh/sshd_config; then
+  printf '\nTrustedUserCAKeys %s\nAuthorizedPrincipalsFile %s/%%u\nRevokedKeys %s\n' "$ca_file" "$pdir" "$revoked" >> /etc/ssh/sshd_config
+fi
+# start.sh가 sshd를 exec하므로 pkill이 없는 이미지에서는 PID 1에 신호 전송
+pkill -HUP -x sshd 2>/dev/null || kill -HUP 1`
+
+	_, err := c.ExecOK(context.Background(), containerID, []string{"sh", "-c", script,
+		caPublicKey, user, principal, sshCAKeyPath, sshRevokedKeysPath, sshPrincipalsDir})
+	if err != nil {
+		return fmt.Errorf("SSH CA 설치 실패: %v", err)
+	}
+	return nil
+}
+
+// RevokeSSHKeys 공개키를 sshd RevokedKeys 목록에 추가 (해당 키로 서명된 인증서 포함 즉시 거부)
+func (c *Client) RevokeSSHKeys(containerID string, keyLines []string) error {
+	if len(keyLines) == 0 {
+		return nil
+	}
+
+	script := `f="$0"; printf '%s\n' "$1" >> "$f"`
+	_, err := c.ExecOK(context.Background(), containerID,
+		[]string{"sh", "-c", script, sshRevokedKeysPath, strings.Join(keyLines, "\n")})
+	if err != nil {
+		return fmt.Errorf("SSH 키 폐기 목록 갱신 실패: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/docker/docker/api/types"
+)
+
+// NetworkCounters 컨테이너의 모든 네트워크 인터페이스 누적 수신/송신 바이트
+// 컨테이너가 재시작되면 0부터 다시 시작하므로 호출자가 누적 처리해야 함
+func (c *Client) NetworkCounters(containerID string) (rxBytes, txBytes uint64, err error) {
+	resp, err := c.cli.ContainerStatsOneShot(context.Background(), containerID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("컨테이너 통계 조회 실패: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var stats types.StatsJSON
+	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+		return 0, 0, fmt.Errorf("컨테이너 통계 파싱 실패: %v", err)
+	}
+
+	for _, network := range stats.Networks {
+		rxBytes += network.RxBytes
+		txBytes += network.TxBytes
+	}
+	return rxBytes, txBytes, nil
+}
@@ -0,0 +1,74 @@
+package docker
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// defaultStopTimeout 종료 절차를 지정하지 않았을 때 SIGTERM 후 SIGKILL까지 기다리는 시간
+const defaultStopTimeout = 10 * time.Second
+
+// StopOptions 컨테이너 종료 절차
+type StopOptions struct {
+	Timeout     time.Duration // SIGTERM(이미지의 STOPSIGNAL) 후 SIGKILL까지
+	PreStop     []string      // SIGTERM 전에 컨테이너 안에서 실행할 명령 (체크포인트 저장 신호 등)
+	PreStopWait time.Duration // pre-stop 명령 후 컨테이너가 스스로 끝나기를 기다리는 최대 시간
+}
+
+// StopContainerWith pre-stop 명령을 실행하고 기다린 뒤 SIGTERM, 제한 시간이 지나면 SIGKILL
+// pre-stop이 실패해도 종료는 계속함 (이미 멈춘 컨테이너면 바로 반환)
+func (c *Client) StopContainerWith(containerID string, opts StopOptions) error {
+	ctx := context.Background()
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultStopTimeout
+	}
+
+	if len(opts.PreStop) > 0 {
+		start := time.Now()
+		if c.preStop(ctx, containerID, opts) {
+			log.Printf("🛑 컨테이너가 pre-stop 후 스스로 종료됨: %s (%s)", shortID(containerID), time.Since(start).Round(time.Second))
+			return nil
+		}
+	}
+
+	timeoutSeconds := int(opts.Timeout / time.Second)
+	err := c.cli.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &timeoutSeconds})
+	if err != nil {
+		log.Printf("⚠️ 컨테이너 중지 실패 (강제 종료 시도): %v", err)
+		// 강제 종료 시도
+		return c.cli.ContainerKill(ctx, containerID, "SIGKILL")
+	}
+
+	log.Printf("🛑 컨테이너 중지됨: %s", shortID(containerID))
+	return nil
+}
+
+// preStop pre-stop 명령을 실행하고 PreStopWait까지 컨테이너 종료를 기다림 (그 안에 끝났으면 true)
+func (c *Client) preStop(ctx context.Context, containerID string, opts StopOptions) bool {
+	// 기다리지 않더라도 명령 자체는 실행할 시간을 줌
+	deadline := opts.PreStopWait
+	if deadline <= 0 {
+		deadline = defaultStopTimeout
+	}
+	waitCtx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+
+	if _, err := c.ExecOK(waitCtx, containerID, opts.PreStop); err != nil {
+		log.Printf("⚠️ 컨테이너 %s pre-stop 명령 실패 (종료는 계속): %v", shortID(containerID), err)
+	}
+	if opts.PreStopWait <= 0 {
+		return false
+	}
+
+	log.Printf("⏳ 컨테이너 %s 종료 대기 (최대 %s)", shortID(containerID), opts.PreStopWait)
+	statusCh, errCh := c.cli.ContainerWait(waitCtx, containerID, container.WaitConditionNotRunning)
+	select {
+	case <-statusCh:
+		return true
+	case <-errCh:
+		return false
+	}
+}
@@ -0,0 +1,181 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/errdefs"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+)
+
+const (
+	// WorkspaceBackendBind 호스트 디렉토리(<workspace-root>/<사용자>)를 bind 마운트
+	WorkspaceBackendBind = "bind"
+	// WorkspaceBackendVolume 사용자별 Docker 명명 볼륨을 마운트 (볼륨 드라이버로 용량 제한, 원격 저장소 사용)
+	WorkspaceBackendVolume = "volume"
+
+	// RoleWorkspace LabelRole 값: 사용자 워크스페이스 볼륨
+	RoleWorkspace = "workspace"
+
+	// volumeUserPlaceholder 볼륨 드라이버 옵션 값에서 사용자 ID로 바꿀 자리 (예: device=:/exports/ws/{user})
+	volumeUserPlaceholder = "{user}"
+)
+
+// VolumeOptions 볼륨 드라이버 옵션 (-workspace-volume-opt key=value를 여러 번 지정)
+type VolumeOptions map[string]string
+
+func (o VolumeOptions) String() string {
+	keys := make([]string, 0, len(o))
+	for k := range o {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+o[k])
+	}
+	return strings.Join(pairs, " ")
+}
+
+// Set flag.Value 구현 (값에 ','나 '='가 들어가는 NFS 마운트 옵션도 그대로 받도록 첫 '='에서만 나눔)
+func (o VolumeOptions) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("볼륨 옵션은 key=value 형식이어야 합니다: %q", value)
+	}
+	o[key] = val
+	return nil
+}
+
+// WorkspaceVolumes 볼륨 백엔드 설정 (배포 단위로 고정, 바꾸면 이후 새로 만드는 볼륨부터 적용)
+type WorkspaceVolumes struct {
+	Driver  string
+	Options VolumeOptions
+}
+
+// HostReadable 볼륨 내용이 호스트의 마운트 지점에 그대로 있는지
+// local 드라이버의 기본 볼륨만 해당하고, type 옵션(nfs, tmpfs 등)을 쓰면 컨테이너에 붙일 때만 마운트되므로
+// 환영 메시지, 복원 지점, 보관 같은 호스트 측 파일 기능을 쓸 수 없음
+func (v WorkspaceVolumes) HostReadable() bool {
+	return (v.Driver == "" || v.Driver == "local") && v.Options["type"] == ""
+}
+
+// optionsFor 사용자 ID를 채운 드라이버 옵션
+func (v WorkspaceVolumes) optionsFor(userID string) map[string]string {
+	opts := make(map[string]string, len(v.Options))
+	for k, val := range v.Options {
+		opts[k] = strings.ReplaceAll(val, volumeUserPlaceholder, userID)
+	}
+	return opts
+}
+
+// WorkspaceVolume 사용자 워크스페이스 볼륨
+type WorkspaceVolume struct {
+	Name       string            `json:"name"`
+	UserID     string            `json:"user_id"`
+	Driver     string            `json:"driver"`
+	Options    map[string]string `json:"options,omitempty"`
+	Mountpoint string            `json:"mountpoint"`
+	CreatedAt  string            `json:"created_at"`
+}
+
+func newWorkspaceVolume(v volume.Volume) *WorkspaceVolume {
+	return &WorkspaceVolume{
+		Name:       v.Name,
+		UserID:     v.Labels[LabelUser],
+		Driver:     v.Driver,
+		Options:    v.Options,
+		Mountpoint: v.Mountpoint,
+		CreatedAt:  v.CreatedAt,
+	}
+}
+
+// WorkspaceVolumeName 배포별 사용자 워크스페이스 볼륨 이름
+func (n Naming) WorkspaceVolumeName(userID string) string {
+	return fmt.Sprintf("%ssandman-workspace-%s-%s", n.ContainerPrefix, n.Deployment, userID)
+}
+
+// SetWorkspaceVolumes 워크스페이스를 bind 마운트 대신 Docker 볼륨으로 제공 (세션을 만들기 전에 호출)
+func (c *Client) SetWorkspaceVolumes(v WorkspaceVolumes) {
+	c.workspaceVolumes = &v
+}
+
+// WorkspaceVolumes 볼륨 백엔드 설정 (bind 마운트 배포면 nil)
+func (c *Client) WorkspaceVolumes() *WorkspaceVolumes {
+	return c.workspaceVolumes
+}
+
+// EnsureWorkspaceVolume 사용자 워크스페이스 볼륨이 없으면 만들고 정보를 반환
+// 같은 이름으로 다시 만들어도 Docker가 기존 볼륨을 돌려주므로 복제본 간 락이 필요 없음
+func (c *Client) EnsureWorkspaceVolume(userID string) (*WorkspaceVolume, error) {
+	if c.workspaceVolumes == nil {
+		return nil, fmt.Errorf("워크스페이스 볼륨 백엔드가 설정되지 않았습니다")
+	}
+	ctx := context.Background()
+	name := c.naming.WorkspaceVolumeName(userID)
+
+	existing, err := c.cli.VolumeInspect(ctx, name)
+	if err == nil {
+		return newWorkspaceVolume(existing), nil
+	}
+	if !errdefs.IsNotFound(err) {
+		return nil, fmt.Errorf("워크스페이스 볼륨 조회 실패: %v", err)
+	}
+
+	labels := c.naming.Labels(userID)
+	labels[LabelRole] = RoleWorkspace
+	created, err := c.cli.VolumeCreate(ctx, volume.CreateOptions{
+		Name:       name,
+		Driver:     c.workspaceVolumes.Driver,
+		DriverOpts: c.workspaceVolumes.optionsFor(userID),
+		Labels:     labels,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("워크스페이스 볼륨 생성 실패: %v", err)
+	}
+	log.Printf("💾 워크스페이스 볼륨 생성: %s (드라이버: %s)", name, created.Driver)
+	return newWorkspaceVolume(created), nil
+}
+
+// RemoveWorkspaceVolume 사용자 워크스페이스 볼륨 삭제 (사용 중인 컨테이너가 있으면 Docker가 거부)
+// 원격 저장소 드라이버는 볼륨 정의만 지우고 서버의 데이터는 남길 수 있음
+func (c *Client) RemoveWorkspaceVolume(userID string) error {
+	name := c.naming.WorkspaceVolumeName(userID)
+	c.expectRemoval("volume", name)
+	err := c.cli.VolumeRemove(context.Background(), name, false)
+	switch {
+	case err == nil:
+		log.Printf("💾 워크스페이스 볼륨 삭제: %s", name)
+		return nil
+	case errdefs.IsNotFound(err):
+		return errs.New(errs.ErrNotFound, "워크스페이스 볼륨이 없습니다: %s", name)
+	case errdefs.IsConflict(err):
+		return errs.New(errs.ErrConflict, "워크스페이스 볼륨이 사용 중입니다: %s", name)
+	}
+	return fmt.Errorf("워크스페이스 볼륨 삭제 실패: %v", err)
+}
+
+// ListWorkspaceVolumes 이 배포가 만든 워크스페이스 볼륨
+func (c *Client) ListWorkspaceVolumes() ([]*WorkspaceVolume, error) {
+	resp, err := c.cli.VolumeList(context.Background(), volume.ListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("label", LabelDeployment+"="+c.naming.Deployment),
+			filters.Arg("label", LabelRole+"="+RoleWorkspace),
+		),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("워크스페이스 볼륨 목록 조회 실패: %v", err)
+	}
+
+	volumes := make([]*WorkspaceVolume, 0, len(resp.Volumes))
+	for _, v := range resp.Volumes {
+		volumes = append(volumes, newWorkspaceVolume(*v))
+	}
+	sort.Slice(volumes, func(i, j int) bool { return volumes[i].Name < volumes[j].Name })
+	return volumes, nil
+}
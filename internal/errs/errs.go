@@ -0,0 +1,55 @@
+// Package errs 패키지 경계를 넘어 호출자가 문자열 비교 없이 분기할 수 있는 오류 종류
+package errs
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound 대상 리소스가 없음
+	ErrNotFound = errors.New("not found")
+	// ErrAlreadyExists 같은 키의 리소스가 이미 있음
+	ErrAlreadyExists = errors.New("already exists")
+	// ErrNoCapacity GPU, 포트, IP 등 할당할 자원이 부족함 (나중에 재시도 가능)
+	ErrNoCapacity = errors.New("no capacity")
+	// ErrConflict 현재 상태와 충돌해 요청을 수행할 수 없음
+	ErrConflict = errors.New("conflict")
+	// ErrInvalid 요청 값이 잘못됨
+	ErrInvalid = errors.New("invalid argument")
+	// ErrForbidden 요청자의 역할/범위로는 허용되지 않음
+	ErrForbidden = errors.New("forbidden")
+)
+
+// kindError 메시지는 그대로 두고 errors.Is로 종류를 판별할 수 있게 하는 오류
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string        { return e.err.Error() }
+func (e *kindError) Is(target error) bool { return target == e.kind }
+func (e *kindError) Unwrap() error        { return e.err }
+
+// New fmt.Errorf와 같은 메시지(%w 포함)에 종류를 붙인 오류
+func New(kind error, format string, args ...interface{}) error {
+	return &kindError{kind: kind, err: fmt.Errorf(format, args...)}
+}
+
+// Wrap 기존 오류에 종류를 붙임 (nil이면 nil)
+func Wrap(kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &kindError{kind: kind, err: err}
+}
+
+// KindOf err가 속한 종류 (해당 없으면 nil)
+func KindOf(err error) error {
+	for _, kind := range []error{ErrNotFound, ErrAlreadyExists, ErrNoCapacity, ErrConflict, ErrInvalid, ErrForbidden} {
+		if errors.Is(err, kind) {
+			return kind
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package gpu
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/command"
+)
+
+// discoverGPUs nvidia-smi로 물리 GPU(인덱스, UUID, 이름, 메모리, PCI 버스)를 나열하고 sysfs에서 NUMA 노드를 찾음
+func discoverGPUs() ([]*GPUInfo, map[int]Topology, error) {
+	output, err := command.Output("nvidia-smi",
+		"--query-gpu=index,uuid,name,memory.total,pci.bus_id,mig.mode.current",
+		"--format=csv,noheader,nounits")
+	if err != nil {
+		return nil, nil, fmt.Errorf("nvidia-smi GPU 조회 실패: %v", err)
+	}
+	return parseGPUList(string(output))
+}
+
+// parseGPUList "0, GPU-..., NVIDIA H100 80GB HBM3, 81559, 00000000:3B:00.0, Enabled" 형식 (메모리는 MiB)
+func parseGPUList(output string) ([]*GPUInfo, map[int]Topology, error) {
+	gpus := []*GPUInfo{}
+	topology := make(map[int]Topology)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) < 6 {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		index, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		info := &GPUInfo{
+			Index:        index,
+			UUID:         fields[1],
+			Name:         fields[2],
+			MIGEnabled:   fields[5] == "Enabled",
+			MIGInstances: []*MIGInstance{},
+			PCIBusID:     fields[4],
+			NUMANode:     readNUMANode(fields[4]),
+		}
+		if mib, err := strconv.ParseUint(fields[3], 10, 64); err == nil {
+			info.MemoryTotal = mib * 1024 * 1024
+		}
+		gpus = append(gpus, info)
+		topology[index] = Topology{GPUIndex: index, PCIBusID: info.PCIBusID, NUMANode: info.NUMANode}
+	}
+	if len(gpus) == 0 {
+		return nil, nil, fmt.Errorf("nvidia-smi 출력에서 GPU를 찾지 못했습니다")
+	}
+	sort.Slice(gpus, func(i, j int) bool { return gpus[i].Index < gpus[j].Index })
+	return gpus, topology, nil
+}
+
+// fakeGPUs 가짜 GPU 빌드에서 인스턴스가 있는 GPU 인덱스마다 가짜 GPU 항목을 만듦
+func fakeGPUs(instances map[string]*MIGInstance) []*GPUInfo {
+	seen := make(map[int]bool)
+	gpus := []*GPUInfo{}
+	for _, instance := range instances {
+		if seen[instance.GPUIndex] {
+			continue
+		}
+		seen[instance.GPUIndex] = true
+		gpus = append(gpus, &GPUInfo{
+			Index:        instance.GPUIndex,
+			UUID:         fmt.Sprintf("GPU-fake-%d", instance.GPUIndex),
+			Name:         "Fake GPU",
+			MIGEnabled:   true,
+			MIGInstances: []*MIGInstance{},
+			NUMANode:     -1,
+		})
+	}
+	sort.Slice(gpus, func(i, j int) bool { return gpus[i].Index < gpus[j].Index })
+	return gpus
+}
@@ -0,0 +1,95 @@
+package gpu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/command"
+)
+
+// DriverInfo 호스트 NVIDIA 소프트웨어 버전과 GPU별 MIG 모드 (탐색 시 수집, 주기적으로 갱신)
+type DriverInfo struct {
+	DriverVersion string `json:"driver_version"`
+	CUDAVersion   string `json:"cuda_version"` // 드라이버가 지원하는 최대 CUDA 버전 (이보다 높은 CUDA 이미지는 실행 불가)
+	NVMLVersion   string `json:"nvml_version"`
+
+	MIGModes  map[int]MIGMode `json:"-"`
+	CheckedAt time.Time       `json:"checked_at"`
+}
+
+// MIGMode GPU의 현재 MIG 모드와 재설정 후 적용될 모드 (지원하지 않는 GPU는 "N/A")
+type MIGMode struct {
+	Current string `json:"current"`
+	Pending string `json:"pending"`
+}
+
+// queryDriverInfo nvidia-smi로 드라이버/CUDA/NVML 버전과 MIG 모드 조회
+// nvidia-smi --version이 없는 오래된 드라이버는 -q 출력에서 드라이버/CUDA 버전만 읽음
+func queryDriverInfo() (*DriverInfo, error) {
+	info := &DriverInfo{CheckedAt: time.Now()}
+
+	if output, err := command.Output("nvidia-smi", "--version"); err == nil {
+		values := parseKeyValues(string(output))
+		info.DriverVersion = values["driver version"]
+		info.CUDAVersion = values["cuda version"]
+		info.NVMLVersion = values["nvml version"]
+	}
+	if info.DriverVersion == "" || info.CUDAVersion == "" {
+		output, err := command.Output("nvidia-smi", "-q")
+		if err != nil {
+			return nil, fmt.Errorf("nvidia-smi 실행 실패: %v", err)
+		}
+		values := parseKeyValues(string(output))
+		if info.DriverVersion == "" {
+			info.DriverVersion = values["driver version"]
+		}
+		if info.CUDAVersion == "" {
+			info.CUDAVersion = values["cuda version"]
+		}
+	}
+
+	output, err := command.Output("nvidia-smi", "--query-gpu=index,mig.mode.current,mig.mode.pending", "--format=csv,noheader")
+	if err != nil {
+		return info, fmt.Errorf("MIG 모드 조회 실패: %v", err)
+	}
+	info.MIGModes = parseMIGModes(string(output))
+	return info, nil
+}
+
+// parseKeyValues "Key : Value" 형식 줄을 소문자 키로 모음 (같은 키는 처음 값 사용)
+func parseKeyValues(output string) map[string]string {
+	values := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		if _, seen := values[key]; !seen {
+			values[key] = strings.TrimSpace(value)
+		}
+	}
+	return values
+}
+
+// parseMIGModes "0, Enabled, Enabled" 형식의 CSV에서 GPU 인덱스별 MIG 모드
+func parseMIGModes(output string) map[int]MIGMode {
+	modes := make(map[int]MIGMode)
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) != 3 {
+			continue
+		}
+		index, err := strconv.Atoi(strings.TrimSpace(fields[0]))
+		if err != nil {
+			continue
+		}
+		modes[index] = MIGMode{
+			Current: strings.Trim(strings.TrimSpace(fields[1]), "[]"),
+			Pending: strings.Trim(strings.TrimSpace(fields[2]), "[]"),
+		}
+	}
+	return modes
+}
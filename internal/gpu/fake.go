@@ -0,0 +1,45 @@
+//go:build fakegpu
+
+package gpu
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Fake GPU 없는 환경(CI, Docker-in-Docker)용 빌드 (go build -tags fakegpu)
+// 가짜 MIG 인스턴스를 만들고, 컨테이너에 NVIDIA 장치를 붙이지 않으며 런타임 확인을 생략
+const Fake = true
+
+// fakeInstances SANDMAN_FAKE_MIG="3g.20gb:2,1g.10gb:4" 형식으로 프로파일별 가짜 인스턴스 생성 (기본 3g.20gb 2개)
+func fakeInstances(profiles map[string]MIGProfile) map[string]*MIGInstance {
+	spec := os.Getenv("SANDMAN_FAKE_MIG")
+	if spec == "" {
+		spec = "3g.20gb:2"
+	}
+
+	instances := make(map[string]*MIGInstance)
+	slot := 0
+	for _, entry := range strings.Split(spec, ",") {
+		name, countStr, _ := strings.Cut(strings.TrimSpace(entry), ":")
+		count, err := strconv.Atoi(countStr)
+		if err != nil || count <= 0 {
+			log.Fatalf("SANDMAN_FAKE_MIG 형식이 잘못되었습니다: %q (예: 3g.20gb:2)", entry)
+		}
+		profile, ok := profiles[name]
+		if !ok {
+			profile = MIGProfile{Name: name}
+			profiles[name] = profile
+		}
+		for i := 0; i < count; i++ {
+			uuid := fmt.Sprintf("MIG-fake-%s-%d", name, i)
+			instances[uuid] = &MIGInstance{UUID: uuid, Name: FriendlyName(0, slot, name, 0), Profile: profile}
+			slot += max(profile.GPUSlice, 1)
+		}
+	}
+	log.Printf("🧪 가짜 GPU 모드: MIG 인스턴스 %d개 (%s)", len(instances), spec)
+	return instances
+}
@@ -0,0 +1,10 @@
+//go:build !fakegpu
+
+package gpu
+
+// Fake 실제 GPU 빌드 (가짜 GPU는 -tags fakegpu)
+const Fake = false
+
+func fakeInstances(map[string]MIGProfile) map[string]*MIGInstance {
+	return nil
+}
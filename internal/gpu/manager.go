@@ -4,11 +4,19 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/command"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
 )
 
+// ProfileCPU GPU 없이 CPU와 메모리만 쓰는 세션 유형 (MIG 인스턴스를 할당하지 않음)
+const ProfileCPU = "cpu"
+
 type MIGProfile struct {
 	Name     string `json:"name"`
 	Memory   string `json:"memory"`
@@ -18,10 +26,14 @@ type MIGProfile struct {
 
 type MIGInstance struct {
 	UUID      string     `json:"uuid"`
+	Name      string     `json:"name"` // 사람이 읽기 쉬운 이름 (gpu0-slot4-3g.40gb), 할당 요청에서 UUID 대신 사용 가능
 	Profile   MIGProfile `json:"profile"`
 	GPUIndex  int        `json:"gpu_index"`
 	InUse     bool       `json:"in_use"`
 	CreatedBy string     `json:"created_by,omitempty"`
+	OnDemand  bool       `json:"on_demand,omitempty"` // 요청에 맞춰 오케스트레이터가 만든 인스턴스
+
+	placement *migPlacement // GPU/컴퓨트 인스턴스 ID (삭제에 필요, 읽지 못했으면 nil)
 }
 
 type GPUInfo struct {
@@ -31,6 +43,15 @@ type GPUInfo struct {
 	MemoryTotal  uint64         `json:"memory_total"`
 	MIGEnabled   bool           `json:"mig_enabled"`
 	MIGInstances []*MIGInstance `json:"mig_instances"`
+	PCIBusID     string         `json:"pci_bus_id,omitempty"`
+	NUMANode     int            `json:"numa_node"` // -1이면 알 수 없음
+
+	// 탐색 시 수집하고 주기적으로 갱신하는 드라이버 정보 (확인하지 못했으면 비어 있음)
+	DriverVersion  string `json:"driver_version,omitempty"`
+	CUDAVersion    string `json:"cuda_version,omitempty"`
+	NVMLVersion    string `json:"nvml_version,omitempty"`
+	MIGMode        string `json:"mig_mode,omitempty"`         // Enabled, Disabled, N/A
+	MIGModePending string `json:"mig_mode_pending,omitempty"` // 현재 모드와 다르면 GPU 재설정 후 적용됨
 }
 
 type Manager struct {
@@ -38,17 +59,41 @@ type Manager struct {
 	gpus         []*GPUInfo
 	migInstances map[string]*MIGInstance // UUID -> MIGInstance
 	profiles     map[string]MIGProfile   // profile name -> MIGProfile
+
+	nvidia   bool             // 실제 NVIDIA GPU가 있어 nvidia-smi로 드라이버 정보를 갱신할 수 있음
+	driver   *DriverInfo      // nil이면 확인 전이거나 실패
+	topology map[int]Topology // GPU 인덱스 -> PCI/NUMA 위치 (탐색 시 한 번 읽음)
+
+	opMu    sync.Mutex // MIG 인스턴스 생성/삭제 직렬화
+	fakeSeq int        // 가짜 GPU 모드에서 만든 인스턴스 번호
 }
 
 func NewManager() (*Manager, error) {
 	log.Printf("🎮 GPU 매니저 초기화 시작...")
 
-	// NVIDIA GPU가 있는지 확인
+	if Fake {
+		profiles := getDefaultMIGProfiles()
+		instances := fakeInstances(profiles)
+		return &Manager{
+			gpus:         fakeGPUs(instances),
+			migInstances: instances,
+			profiles:     profiles,
+			driver: &DriverInfo{
+				DriverVersion: "fake",
+				CUDAVersion:   "fake",
+				NVMLVersion:   "fake",
+				MIGModes:      map[int]MIGMode{0: {Current: "Enabled", Pending: "Enabled"}},
+				CheckedAt:     time.Now(),
+			},
+		}, nil
+	}
+
+	// NVIDIA GPU가 있는지 확인 (없으면 CPU 전용 세션만 제공)
 	if _, err := os.Stat("/dev/nvidia0"); os.IsNotExist(err) {
-		log.Printf("⚠️  NVIDIA GPU가 감지되지 않음, GPU 기능 없이 진행")
+		log.Printf("⚠️  NVIDIA GPU가 감지되지 않음, CPU 전용 세션(%s)만 제공합니다", ProfileCPU)
 		return &Manager{
 			migInstances: make(map[string]*MIGInstance),
-			profiles:     getDefaultMIGProfiles(),
+			profiles:     map[string]MIGProfile{ProfileCPU: cpuProfile()},
 		}, nil
 	}
 
@@ -57,6 +102,18 @@ func NewManager() (*Manager, error) {
 		gpus:         make([]*GPUInfo, 0),
 		migInstances: make(map[string]*MIGInstance),
 		profiles:     getDefaultMIGProfiles(),
+		nvidia:       true,
+	}
+
+	// 물리 GPU와 PCI/NUMA 위치 검색 (실패하면 MIG 인스턴스의 GPU 인덱스로만 표시)
+	if gpus, topology, err := discoverGPUs(); err != nil {
+		log.Printf("⚠️ GPU 검색 실패 (배치 힌트에서 NUMA 노드를 고려하지 않음): %v", err)
+	} else {
+		manager.gpus, manager.topology = gpus, topology
+		for _, info := range gpus {
+			log.Printf("✅ GPU %d 발견: %s (%s, %d MiB, MIG: %v, NUMA: %d)",
+				info.Index, info.Name, info.UUID, info.MemoryTotal>>20, info.MIGEnabled, info.NUMANode)
+		}
 	}
 
 	// 실제 MIG 인스턴스 검색
@@ -64,78 +121,164 @@ func NewManager() (*Manager, error) {
 		log.Printf("⚠️ MIG 인스턴스 검색 실패: %v", err)
 	}
 
+	if err := manager.RefreshDriverInfo(); err != nil {
+		log.Printf("⚠️ NVIDIA 드라이버 정보 확인 실패: %v", err)
+	}
+
 	log.Printf("✅ GPU 매니저 초기화 완료")
 	return manager, nil
 }
 
+// HasGPU 세션에 붙일 GPU가 있는 호스트인지 (가짜 GPU 빌드 포함)
+func (m *Manager) HasGPU() bool {
+	return m.nvidia || Fake
+}
+
+// CPUInstance CPU 전용 세션의 자리 표시 인스턴스 (UUID가 없어 할당/해제 대상이 아님)
+func CPUInstance() *MIGInstance {
+	return &MIGInstance{Profile: cpuProfile()}
+}
+
+func cpuProfile() MIGProfile {
+	return MIGProfile{Name: ProfileCPU}
+}
+
 func (m *Manager) Shutdown() {
 	log.Printf("🔄 GPU 매니저 종료")
 }
 
+// DriverVersion 호스트 NVIDIA 드라이버 버전 (GPU가 없거나 확인 실패 시 빈 문자열)
+func (m *Manager) DriverVersion() string {
+	return m.DriverInfo().DriverVersion
+}
+
+// DriverInfo 마지막으로 확인한 드라이버/CUDA/NVML 버전 (GPU가 없거나 확인 전이면 빈 값)
+func (m *Manager) DriverInfo() DriverInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if m.driver == nil {
+		return DriverInfo{}
+	}
+	return *m.driver
+}
+
+// RefreshDriverInfo nvidia-smi로 드라이버 정보를 다시 읽음 (호스트 드라이버 업그레이드, MIG 모드 변경 반영)
+// 버전은 읽었지만 MIG 모드 조회만 실패했으면 버전은 갱신하고 오류를 반환
+func (m *Manager) RefreshDriverInfo() error {
+	if !m.nvidia {
+		return nil
+	}
+	info, err := queryDriverInfo()
+	if info == nil {
+		return err
+	}
+
+	m.mu.Lock()
+	previous := m.driver
+	m.driver = info
+	m.mu.Unlock()
+
+	if previous == nil || previous.DriverVersion != info.DriverVersion || previous.CUDAVersion != info.CUDAVersion {
+		log.Printf("🎮 NVIDIA 드라이버 %s (CUDA %s, NVML %s)", info.DriverVersion, info.CUDAVersion, info.NVMLVersion)
+	}
+	return err
+}
+
 func (m *Manager) discoverMIGInstances() error {
 	log.Printf("🔍 MIG 인스턴스 검색 중...")
 
-	// nvidia-smi -L 명령어로 MIG 인스턴스 목록 가져오기
-	cmd := exec.Command("nvidia-smi", "-L")
-	output, err := cmd.Output()
+	if err := m.RefreshMIGInstances(); err != nil {
+		return err
+	}
+	for _, instance := range m.ListMIGInstances() {
+		log.Printf("✅ MIG 인스턴스 발견: %s (%s) → %s", instance.UUID, instance.Profile.Name, instance.Name)
+	}
+
+	log.Printf("📊 총 %d개의 MIG 인스턴스 발견", len(m.migInstances))
+	return nil
+}
+
+// scanMIGInstances nvidia-smi -L 출력에서 MIG 인스턴스와 Device 번호(UUID별)를 읽음
+func (m *Manager) scanMIGInstances() (map[string]*MIGInstance, map[string]int, error) {
+	output, err := command.Output("nvidia-smi", "-L")
 	if err != nil {
-		return fmt.Errorf("nvidia-smi -L 실행 실패: %v", err)
+		return nil, nil, fmt.Errorf("nvidia-smi -L 실행 실패: %v", err)
 	}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	m.mu.RLock()
+	defer m.mu.RUnlock()
 
-	for _, line := range lines {
+	found := make(map[string]*MIGInstance)
+	deviceIndex := make(map[string]int)
+	gpuIndex := 0
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
 		line = strings.TrimSpace(line)
-		if strings.Contains(line, "MIG") && strings.Contains(line, "UUID:") {
-			// MIG 인스턴스 라인 파싱
-			// 예: "  MIG 1g.10gb     Device  1: (UUID: MIG-0042c8df-65bb-5d61-beb7-655f4b4318ea)"
-			parts := strings.Split(line, ":")
-			if len(parts) >= 2 {
-				uuidPart := strings.TrimSpace(parts[len(parts)-1])
-				uuid := strings.Trim(uuidPart, " ()")
-
-				// 프로파일 이름 추출
-				profileName := ""
-				if strings.Contains(line, "1g.10gb") {
-					profileName = "1g.10gb"
-				} else if strings.Contains(line, "4g.40gb") {
-					profileName = "4g.40gb"
-				} else if strings.Contains(line, "3g.40gb") {
-					profileName = "3g.40gb"
-				} else if strings.Contains(line, "2g.20gb") {
-					profileName = "2g.20gb"
-				} else if strings.Contains(line, "1g.20gb") {
-					profileName = "1g.20gb"
-				} else if strings.Contains(line, "7g.80gb") {
-					profileName = "7g.80gb"
-				}
-
-				if profileName != "" && uuid != "" {
-					profile, exists := m.profiles[profileName]
-					if !exists {
-						// 기본 프로파일이 없으면 새로 생성
-						profile = MIGProfile{
-							Name:   profileName,
-							Memory: strings.Replace(profileName, "g.", "gb", 1),
-						}
-						m.profiles[profileName] = profile
-					}
-
-					migInstance := &MIGInstance{
-						UUID:     uuid,
-						Profile:  profile,
-						GPUIndex: 0,
-						InUse:    false,
-					}
-
-					m.migInstances[uuid] = migInstance
-					log.Printf("✅ MIG 인스턴스 발견: %s (%s)", uuid, profileName)
-				}
+		if match := gpuLinePattern.FindStringSubmatch(line); match != nil {
+			gpuIndex, _ = strconv.Atoi(match[1])
+			continue
+		}
+		// 예: "MIG 1g.10gb     Device  1: (UUID: MIG-0042c8df-65bb-5d61-beb7-655f4b4318ea)"
+		match := migLinePattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		profileName, uuid := match[1], match[3]
+		profile, exists := m.profiles[profileName]
+		if !exists {
+			// 기본 프로파일 목록에 없으면 이름에서 메모리만 채움
+			profile = MIGProfile{
+				Name:   profileName,
+				Memory: profileName[strings.Index(profileName, ".")+1:],
 			}
 		}
+		found[uuid] = &MIGInstance{
+			UUID:     uuid,
+			Profile:  profile,
+			GPUIndex: gpuIndex,
+		}
+		deviceIndex[uuid], _ = strconv.Atoi(match[2])
 	}
+	return found, deviceIndex, nil
+}
 
-	log.Printf("📊 총 %d개의 MIG 인스턴스 발견", len(m.migInstances))
+// RefreshMIGInstances MIG 구성을 다시 읽어 반영 (할당 상태는 유지)
+// 사라진 인스턴스는 사용 중이 아니면 목록에서 빼고, 사용 중이면 경고만 남김
+func (m *Manager) RefreshMIGInstances() error {
+	if !m.nvidia {
+		return nil
+	}
+	found, deviceIndex, err := m.scanMIGInstances()
+	if err != nil {
+		return err
+	}
+	assignNames(found, deviceIndex)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for uuid, instance := range found {
+		if _, known := m.profiles[instance.Profile.Name]; !known {
+			m.profiles[instance.Profile.Name] = instance.Profile
+		}
+		existing, ok := m.migInstances[uuid]
+		if !ok {
+			m.migInstances[uuid] = instance
+			continue
+		}
+		existing.Name = instance.Name
+		existing.GPUIndex = instance.GPUIndex
+		existing.placement = instance.placement
+	}
+	for uuid, instance := range m.migInstances {
+		if _, ok := found[uuid]; ok {
+			continue
+		}
+		if instance.InUse {
+			log.Printf("⚠️ 사용 중인 MIG 인스턴스 %s (%s)가 GPU에서 사라졌습니다", instance.Name, uuid)
+			continue
+		}
+		delete(m.migInstances, uuid)
+		log.Printf("🗑️ 사라진 MIG 인스턴스를 목록에서 제거: %s (%s)", instance.Name, uuid)
+	}
 	return nil
 }
 
@@ -153,21 +296,11 @@ func (m *Manager) GetGPU(index int) (*GPUInfo, error) {
 	defer m.mu.RUnlock()
 
 	if index < 0 || index >= len(m.gpus) {
-		return nil, fmt.Errorf("GPU 인덱스 %d가 유효하지 않음", index)
+		return nil, errs.New(errs.ErrNotFound, "GPU 인덱스 %d가 유효하지 않음", index)
 	}
 	return m.gpus[index], nil
 }
 
-func (m *Manager) CreateMIGInstance(gpuIndex int, profileName string) (*MIGInstance, error) {
-	log.Printf("⚠️ MIG 인스턴스 생성 기능이 임시로 비활성화됨 (NVML 문제로 인해)")
-	return nil, fmt.Errorf("MIG 인스턴스 생성 기능이 비활성화됨")
-}
-
-func (m *Manager) DeleteMIGInstance(instanceUUID string) error {
-	log.Printf("⚠️ MIG 인스턴스 삭제 기능이 임시로 비활성화됨 (NVML 문제로 인해)")
-	return fmt.Errorf("MIG 인스턴스 삭제 기능이 비활성화됨")
-}
-
 func (m *Manager) ListMIGInstances() []*MIGInstance {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
@@ -195,7 +328,7 @@ func (m *Manager) AllocateMIG(profileName, userID string) (*MIGInstance, error)
 	}
 
 	if availableInstance == nil {
-		return nil, fmt.Errorf("프로파일 %s의 사용 가능한 MIG 인스턴스가 없습니다", profileName)
+		return nil, errs.New(errs.ErrNoCapacity, "프로파일 %s의 사용 가능한 MIG 인스턴스가 없습니다", profileName)
 	}
 
 	// 인스턴스 할당
@@ -212,11 +345,16 @@ func (m *Manager) ReleaseMIG(instanceUUID, userID string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	// CPU 전용 세션은 해제할 인스턴스가 없음
+	if instanceUUID == "" {
+		return nil
+	}
+
 	log.Printf("🔓 MIG 해제 요청: UUID=%s, 사용자=%s", instanceUUID, userID)
 
 	instance, exists := m.migInstances[instanceUUID]
 	if !exists {
-		return fmt.Errorf("MIG 인스턴스 %s를 찾을 수 없습니다", instanceUUID)
+		return errs.New(errs.ErrNotFound, "MIG 인스턴스 %s를 찾을 수 없습니다", instanceUUID)
 	}
 
 	if !instance.InUse {
@@ -236,83 +374,110 @@ func (m *Manager) ReleaseMIG(instanceUUID, userID string) error {
 	return nil
 }
 
+// GetGPUInfo 물리 GPU마다 드라이버/MIG 모드와 그 GPU의 MIG 인스턴스를 묶어 반환 (GPU 인덱스 순)
+// 검색에 실패한 GPU라도 인스턴스가 있으면 인덱스만 채운 항목으로 보여 줌
 func (m *Manager) GetGPUInfo() []*GPUInfo {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	// 실제 MIG 인스턴스 정보를 포함한 가짜 GPU 정보 반환
-	if len(m.migInstances) == 0 {
-		return []*GPUInfo{}
+	byIndex := make(map[int]*GPUInfo, len(m.gpus))
+	result := make([]*GPUInfo, 0, len(m.gpus))
+	for _, g := range m.gpus {
+		info := *g
+		info.MIGInstances = []*MIGInstance{}
+		byIndex[info.Index] = &info
+		result = append(result, &info)
 	}
-
-	// GPU 0 정보 생성
-	migInstances := make([]*MIGInstance, 0, len(m.migInstances))
 	for _, instance := range m.migInstances {
-		migInstances = append(migInstances, &MIGInstance{
-			UUID:      instance.UUID,
-			Profile:   instance.Profile,
-			GPUIndex:  instance.GPUIndex,
-			InUse:     instance.InUse,
-			CreatedBy: instance.CreatedBy,
-		})
+		info, ok := byIndex[instance.GPUIndex]
+		if !ok {
+			info = &GPUInfo{Index: instance.GPUIndex, MIGEnabled: true, MIGInstances: []*MIGInstance{}, NUMANode: -1}
+			byIndex[instance.GPUIndex] = info
+			result = append(result, info)
+		}
+		copied := *instance
+		copied.placement = nil
+		info.MIGInstances = append(info.MIGInstances, &copied)
 	}
 
-	gpuInfo := &GPUInfo{
-		Index:        0,
-		UUID:         "GPU-372cf708-4ec1-0f35-bfef-a24bae2df638",
-		Name:         "NVIDIA H100 80GB HBM3",
-		MemoryTotal:  85899345920, // 80GB
-		MIGEnabled:   true,
-		MIGInstances: migInstances,
+	for _, info := range result {
+		sort.Slice(info.MIGInstances, func(i, j int) bool { return info.MIGInstances[i].Name < info.MIGInstances[j].Name })
+		if m.driver == nil {
+			continue
+		}
+		info.DriverVersion = m.driver.DriverVersion
+		info.CUDAVersion = m.driver.CUDAVersion
+		info.NVMLVersion = m.driver.NVMLVersion
+		if mode, ok := m.driver.MIGModes[info.Index]; ok {
+			info.MIGMode = mode.Current
+			info.MIGModePending = mode.Pending
+			info.MIGEnabled = mode.Current == "Enabled"
+		}
 	}
-
-	return []*GPUInfo{gpuInfo}
+	sort.Slice(result, func(i, j int) bool { return result[i].Index < result[j].Index })
+	return result
 }
 
 func (m *Manager) GetAvailableProfiles() map[string]MIGProfile {
-	return m.profiles
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	profiles := make(map[string]MIGProfile, len(m.profiles))
+	for name, profile := range m.profiles {
+		profiles[name] = profile
+	}
+	return profiles
 }
 
-// GetAvailableMIGInstances 사용 가능한 MIG 인스턴스들의 목록을 인덱스와 함께 반환
+// GetAvailableMIGInstances 사용 가능한 MIG 인스턴스 사본 (gpu_index는 인스턴스가 속한 물리 GPU)
 func (m *Manager) GetAvailableMIGInstances() []*MIGInstance {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
 	availableInstances := make([]*MIGInstance, 0)
-	index := 0
-
 	for _, instance := range m.migInstances {
 		if !instance.InUse {
-			// 복사본 생성하여 인덱스 추가
-			instanceCopy := &MIGInstance{
-				UUID:      instance.UUID,
-				Profile:   instance.Profile,
-				GPUIndex:  index, // 사용 가능한 인스턴스의 인덱스
-				InUse:     instance.InUse,
-				CreatedBy: instance.CreatedBy,
-			}
-			availableInstances = append(availableInstances, instanceCopy)
-			index++
+			instanceCopy := *instance
+			instanceCopy.placement = nil
+			availableInstances = append(availableInstances, &instanceCopy)
 		}
 	}
-
+	sort.Slice(availableInstances, func(i, j int) bool {
+		if availableInstances[i].GPUIndex != availableInstances[j].GPUIndex {
+			return availableInstances[i].GPUIndex < availableInstances[j].GPUIndex
+		}
+		return availableInstances[i].Name < availableInstances[j].Name
+	})
 	return availableInstances
 }
 
-// AllocateMIGByUUID 특정 UUID의 MIG 인스턴스를 직접 할당
+// lookup UUID 또는 이름으로 인스턴스 조회 (m.mu 보유 중 호출)
+func (m *Manager) lookup(id string) (*MIGInstance, bool) {
+	if instance, ok := m.migInstances[id]; ok {
+		return instance, true
+	}
+	for _, instance := range m.migInstances {
+		if instance.Name != "" && instance.Name == id {
+			return instance, true
+		}
+	}
+	return nil, false
+}
+
+// AllocateMIGByUUID 특정 MIG 인스턴스를 직접 할당 (UUID 대신 이름도 받음)
 func (m *Manager) AllocateMIGByUUID(instanceUUID, userID string) (*MIGInstance, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	log.Printf("🎯 MIG 할당 요청 (UUID 지정): UUID=%s, 사용자=%s", instanceUUID, userID)
 
-	instance, exists := m.migInstances[instanceUUID]
+	instance, exists := m.lookup(instanceUUID)
 	if !exists {
-		return nil, fmt.Errorf("MIG 인스턴스 %s를 찾을 수 없습니다", instanceUUID)
+		return nil, errs.New(errs.ErrNotFound, "MIG 인스턴스 %s를 찾을 수 없습니다", instanceUUID)
 	}
 
 	if instance.InUse {
-		return nil, fmt.Errorf("MIG 인스턴스 %s는 이미 사용 중입니다 (사용자: %s)", instanceUUID, instance.CreatedBy)
+		return nil, errs.New(errs.ErrConflict, "MIG 인스턴스 %s는 이미 사용 중입니다 (사용자: %s)", instanceUUID, instance.CreatedBy)
 	}
 
 	// 인스턴스 할당
@@ -325,8 +490,10 @@ func (m *Manager) AllocateMIGByUUID(instanceUUID, userID string) (*MIGInstance,
 	return instance, nil
 }
 
+// getDefaultMIGProfiles GPU 호스트가 제공하는 프로파일 (MIG 프로파일과 CPU 전용)
 func getDefaultMIGProfiles() map[string]MIGProfile {
 	return map[string]MIGProfile{
+		ProfileCPU: cpuProfile(),
 		"1g.5gb": {
 			Name:     "1g.5gb",
 			Memory:   "5gb",
@@ -0,0 +1,235 @@
+package gpu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/command"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+)
+
+// fakeGPUSlices 가짜 GPU 모드에서 GPU 하나의 컴퓨트 슬라이스 수
+const fakeGPUSlices = 7
+
+// "Successfully created GPU instance ID  9 on GPU  0 using profile MIG 1g.10gb (ID 19)" (nvidia-smi mig -cgi)
+var createdGILinePattern = regexp.MustCompile(`created GPU instance ID\s+(\d+) on GPU\s+(\d+)`)
+
+// CreateMIGInstance GPU에 프로파일의 GPU 인스턴스와 컴퓨트 인스턴스를 하나 만들고 목록에 추가
+// GPU에 남은 슬라이스가 없으면 errs.ErrNoCapacity
+func (m *Manager) CreateMIGInstance(gpuIndex int, profileName string) (*MIGInstance, error) {
+	m.opMu.Lock()
+	defer m.opMu.Unlock()
+
+	if profileName == ProfileCPU {
+		return nil, errs.New(errs.ErrInvalid, "CPU 전용 세션 유형은 MIG 인스턴스가 아닙니다")
+	}
+	if _, ok := m.GetAvailableProfiles()[profileName]; !ok {
+		return nil, errs.New(errs.ErrInvalid, "알 수 없는 MIG 프로파일입니다: %s", profileName)
+	}
+	if Fake {
+		return m.createFakeInstance(gpuIndex, profileName)
+	}
+	if !m.nvidia {
+		return nil, errs.New(errs.ErrInvalid, "NVIDIA GPU가 없어 MIG 인스턴스를 만들 수 없습니다")
+	}
+	if mode, ok := m.DriverInfo().MIGModes[gpuIndex]; !ok || mode.Current != "Enabled" {
+		return nil, errs.New(errs.ErrInvalid, "GPU %d의 MIG 모드가 켜져 있지 않습니다", gpuIndex)
+	}
+
+	before := make(map[string]bool)
+	for _, instance := range m.ListMIGInstances() {
+		before[instance.UUID] = true
+	}
+
+	output, err := runMIG("mig", "-i", strconv.Itoa(gpuIndex), "-cgi", profileName, "-C")
+	if err != nil {
+		return nil, migCommandError(fmt.Sprintf("GPU %d에 %s 인스턴스 생성 실패", gpuIndex, profileName), output, err)
+	}
+	gi := -1
+	if match := createdGILinePattern.FindStringSubmatch(string(output)); match != nil {
+		gi, _ = strconv.Atoi(match[1])
+	}
+
+	if err := m.RefreshMIGInstances(); err != nil {
+		return nil, fmt.Errorf("MIG 인스턴스를 만들었지만 목록을 다시 읽지 못했습니다: %v", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var created *MIGInstance
+	for uuid, instance := range m.migInstances {
+		if before[uuid] || instance.GPUIndex != gpuIndex || instance.Profile.Name != profileName {
+			continue
+		}
+		if gi >= 0 && instance.placement != nil && instance.placement.gi != gi {
+			continue
+		}
+		created = instance
+		break
+	}
+	if created == nil {
+		return nil, fmt.Errorf("GPU %d에 만든 %s 인스턴스를 nvidia-smi -L에서 찾지 못했습니다", gpuIndex, profileName)
+	}
+	log.Printf("🧩 MIG 인스턴스 생성: %s (%s, GPU %d)", created.Name, created.UUID, gpuIndex)
+	return created, nil
+}
+
+// CarveMIGInstance MIG 모드가 켜진 GPU를 인덱스 순서로 돌며 프로파일 인스턴스를 하나 만듦 (first-fit)
+// 이렇게 만든 인스턴스는 OnDemand로 표시해 세션이 끝나면 지울 수 있게 함
+func (m *Manager) CarveMIGInstance(profileName string) (*MIGInstance, error) {
+	var lastErr error
+	for _, index := range m.migEnabledGPUs() {
+		instance, err := m.CreateMIGInstance(index, profileName)
+		if err == nil {
+			m.mu.Lock()
+			instance.OnDemand = true
+			m.mu.Unlock()
+			return instance, nil
+		}
+		if !errors.Is(err, errs.ErrNoCapacity) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		return nil, errs.New(errs.ErrNoCapacity, "MIG 모드가 켜진 GPU가 없어 %s 인스턴스를 만들 수 없습니다", profileName)
+	}
+	return nil, errs.New(errs.ErrNoCapacity, "%s 인스턴스를 만들 여유가 있는 GPU가 없습니다: %v", profileName, lastErr)
+}
+
+// DeleteMIGInstance 사용 중이 아닌 인스턴스의 컴퓨트 인스턴스와 (비게 되면) GPU 인스턴스를 지움
+func (m *Manager) DeleteMIGInstance(instanceUUID string) error {
+	m.opMu.Lock()
+	defer m.opMu.Unlock()
+
+	m.mu.RLock()
+	instance, ok := m.lookup(instanceUUID)
+	var snapshot MIGInstance
+	shared := false
+	if ok {
+		snapshot = *instance
+		for _, other := range m.migInstances {
+			if other != instance && other.GPUIndex == instance.GPUIndex && other.placement != nil && instance.placement != nil &&
+				other.placement.gi == instance.placement.gi {
+				shared = true
+			}
+		}
+	}
+	m.mu.RUnlock()
+	if !ok {
+		return errs.New(errs.ErrNotFound, "MIG 인스턴스 %s를 찾을 수 없습니다", instanceUUID)
+	}
+	if snapshot.InUse {
+		return errs.New(errs.ErrConflict, "MIG 인스턴스 %s는 사용 중입니다 (사용자: %s)", snapshot.Name, snapshot.CreatedBy)
+	}
+
+	if Fake {
+		m.mu.Lock()
+		delete(m.migInstances, snapshot.UUID)
+		m.mu.Unlock()
+		log.Printf("🗑️ MIG 인스턴스 삭제: %s (%s)", snapshot.Name, snapshot.UUID)
+		return nil
+	}
+	if snapshot.placement == nil {
+		return errs.New(errs.ErrConflict, "MIG 인스턴스 %s의 GPU 인스턴스 ID를 알 수 없어 지울 수 없습니다", snapshot.Name)
+	}
+
+	gpuArg := strconv.Itoa(snapshot.GPUIndex)
+	giArg := strconv.Itoa(snapshot.placement.gi)
+	output, err := runMIG("mig", "-i", gpuArg, "-gi", giArg, "-ci", strconv.Itoa(snapshot.placement.ci), "-dci")
+	if err != nil {
+		return migCommandError(fmt.Sprintf("%s 컴퓨트 인스턴스 삭제 실패", snapshot.Name), output, err)
+	}
+	// 같은 GPU 인스턴스를 나눠 쓰는 다른 컴퓨트 인스턴스가 있으면 GPU 인스턴스는 남김
+	if !shared {
+		output, err = runMIG("mig", "-i", gpuArg, "-gi", giArg, "-dgi")
+		if err != nil {
+			return migCommandError(fmt.Sprintf("%s GPU 인스턴스 삭제 실패", snapshot.Name), output, err)
+		}
+	}
+
+	m.mu.Lock()
+	delete(m.migInstances, snapshot.UUID)
+	m.mu.Unlock()
+	log.Printf("🗑️ MIG 인스턴스 삭제: %s (%s)", snapshot.Name, snapshot.UUID)
+	return nil
+}
+
+// Instance UUID 또는 이름으로 인스턴스 사본 조회
+func (m *Manager) Instance(id string) (MIGInstance, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	instance, ok := m.lookup(id)
+	if !ok {
+		return MIGInstance{}, false
+	}
+	return *instance, true
+}
+
+// migEnabledGPUs MIG 모드가 켜진 GPU 인덱스 (오름차순)
+func (m *Manager) migEnabledGPUs() []int {
+	if Fake {
+		return []int{0}
+	}
+	var indexes []int
+	for index, mode := range m.DriverInfo().MIGModes {
+		if mode.Current == "Enabled" {
+			indexes = append(indexes, index)
+		}
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// migCommandTimeout MIG 인스턴스 생성/삭제 제한 시간 (GPU가 바쁘면 수십 초 걸림)
+const migCommandTimeout = 2 * time.Minute
+
+// runMIG nvidia-smi mig 실행 (stdout과 stderr를 합친 출력)
+func runMIG(args ...string) ([]byte, error) {
+	result, err := command.Run(context.Background(), command.Options{Timeout: migCommandTimeout, Combined: true}, "nvidia-smi", args...)
+	return result.Stdout, err
+}
+
+// migCommandError nvidia-smi mig 실패를 오류로 변환 (슬라이스 부족은 ErrNoCapacity)
+func migCommandError(action string, output []byte, err error) error {
+	message := strings.TrimSpace(string(output))
+	if message == "" {
+		message = err.Error()
+	}
+	if strings.Contains(message, "Insufficient Resources") || strings.Contains(message, "No more space") {
+		return errs.New(errs.ErrNoCapacity, "%s: %s", action, message)
+	}
+	return fmt.Errorf("%s: %s", action, message)
+}
+
+// createFakeInstance 가짜 GPU 모드의 인스턴스 생성 (GPU 0의 7 슬라이스 안에서만)
+func (m *Manager) createFakeInstance(gpuIndex int, profileName string) (*MIGInstance, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if gpuIndex != 0 {
+		return nil, errs.New(errs.ErrNotFound, "GPU 인덱스 %d가 유효하지 않음", gpuIndex)
+	}
+	profile := m.profiles[profileName]
+	used := 0
+	for _, instance := range m.migInstances {
+		used += max(instance.Profile.GPUSlice, 1)
+	}
+	if used+max(profile.GPUSlice, 1) > fakeGPUSlices {
+		return nil, errs.New(errs.ErrNoCapacity, "GPU 0에 %s 인스턴스를 만들 슬라이스가 없습니다 (사용 중 %d/%d)", profileName, used, fakeGPUSlices)
+	}
+
+	m.fakeSeq++
+	uuid := fmt.Sprintf("MIG-fake-ondemand-%s-%d", profileName, m.fakeSeq)
+	instance := &MIGInstance{UUID: uuid, Name: FriendlyName(0, used, profileName, 0), Profile: profile}
+	m.migInstances[uuid] = instance
+	log.Printf("🧩 MIG 인스턴스 생성: %s (%s, GPU 0)", instance.Name, uuid)
+	return instance, nil
+}
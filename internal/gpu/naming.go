@@ -0,0 +1,124 @@
+package gpu
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/command"
+)
+
+// migKey GPU 인덱스와 GPU 안의 번호 (MIG 장치 번호 또는 GPU 인스턴스 ID)
+type migKey struct {
+	gpu int
+	id  int
+}
+
+// migDevice nvidia-smi의 MIG devices 표 한 줄
+type migDevice struct {
+	gi int
+	ci int
+}
+
+// migPlacement 인스턴스의 GPU 인스턴스 ID, 컴퓨트 인스턴스 ID, 배치 시작 위치
+type migPlacement struct {
+	gi    int
+	ci    int
+	start int
+}
+
+var (
+	// "|   0  MIG 3g.40gb          9        2          4:4     |" (nvidia-smi mig -lgi)
+	lgiLinePattern = regexp.MustCompile(`^\|\s+(\d+)\s+MIG\s+\S+\s+\d+\s+(\d+)\s+(\d+):(\d+)\s+\|`)
+	// "GPU 0: NVIDIA A100-SXM4-80GB (UUID: GPU-...)" / "  MIG 3g.40gb     Device  1: (UUID: MIG-...)" (nvidia-smi -L)
+	gpuLinePattern = regexp.MustCompile(`^GPU (\d+):`)
+	migLinePattern = regexp.MustCompile(`^MIG\s+(\S+)\s+Device\s+(\d+):\s+\(UUID:\s+(MIG-[^)\s]+)\)`)
+	// "|  0    2   0   1  |  ..." (nvidia-smi의 MIG devices 표: GPU, GI ID, CI ID, MIG Dev)
+	migDeviceLinePattern = regexp.MustCompile(`^\|\s+(\d+)\s+(\d+)\s+(\d+)\s+(\d+)\s+\|`)
+)
+
+// FriendlyName 대시보드에 보여 줄 안정적인 인스턴스 이름 (예: gpu0-slot4-3g.40gb)
+// slot은 GPU 인스턴스 배치 시작 위치라 MIG 구성을 바꾸지 않는 한 재부팅해도 같음
+// 한 GPU 인스턴스를 여러 컴퓨트 인스턴스로 나눈 경우에만 -ci<N>을 붙임
+func FriendlyName(gpuIndex, slot int, profile string, ci int) string {
+	name := fmt.Sprintf("gpu%d-slot%d-%s", gpuIndex, slot, profile)
+	if ci > 0 {
+		name += fmt.Sprintf("-ci%d", ci)
+	}
+	return name
+}
+
+// parseGIPlacements nvidia-smi mig -lgi 출력에서 (GPU, GI ID) → 배치 시작 위치
+func parseGIPlacements(output string) map[migKey]int {
+	placements := make(map[migKey]int)
+	for _, line := range strings.Split(output, "\n") {
+		m := lgiLinePattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		gpu, _ := strconv.Atoi(m[1])
+		gi, _ := strconv.Atoi(m[2])
+		start, _ := strconv.Atoi(m[3])
+		placements[migKey{gpu, gi}] = start
+	}
+	return placements
+}
+
+// parseMIGDevices nvidia-smi 기본 출력의 MIG devices 표에서 (GPU, MIG Dev) → GI/CI ID
+func parseMIGDevices(output string) map[migKey]migDevice {
+	devices := make(map[migKey]migDevice)
+	inTable := false
+	for _, line := range strings.Split(output, "\n") {
+		if strings.Contains(line, "MIG devices:") {
+			inTable = true
+			continue
+		}
+		if strings.Contains(line, "Processes:") {
+			break
+		}
+		if !inTable {
+			continue
+		}
+		m := migDeviceLinePattern.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		gpu, _ := strconv.Atoi(m[1])
+		gi, _ := strconv.Atoi(m[2])
+		ci, _ := strconv.Atoi(m[3])
+		dev, _ := strconv.Atoi(m[4])
+		devices[migKey{gpu, dev}] = migDevice{gi: gi, ci: ci}
+	}
+	return devices
+}
+
+// assignNames 발견한 인스턴스에 이름과 배치 정보 부여 (배치 정보를 못 읽으면 MIG 장치 번호를 slot으로 사용)
+// deviceIndex는 UUID → nvidia-smi -L의 Device 번호
+func assignNames(instances map[string]*MIGInstance, deviceIndex map[string]int) {
+	var devices map[migKey]migDevice
+	var placements map[migKey]int
+	if output, err := command.Output("nvidia-smi"); err == nil {
+		devices = parseMIGDevices(string(output))
+	} else {
+		log.Printf("⚠️ MIG 장치 표 조회 실패, 장치 번호로 이름을 만듭니다: %v", err)
+	}
+	if output, err := command.Output("nvidia-smi", "mig", "-lgi"); err == nil {
+		placements = parseGIPlacements(string(output))
+	} else {
+		log.Printf("⚠️ GPU 인스턴스 배치 조회 실패, 장치 번호로 이름을 만듭니다: %v", err)
+	}
+
+	for uuid, instance := range instances {
+		dev := deviceIndex[uuid]
+		slot, ci := dev, 0
+		if device, ok := devices[migKey{instance.GPUIndex, dev}]; ok {
+			if start, ok := placements[migKey{instance.GPUIndex, device.gi}]; ok {
+				slot, ci = start, device.ci
+				instance.placement = &migPlacement{gi: device.gi, ci: device.ci, start: start}
+			}
+		}
+		instance.Name = FriendlyName(instance.GPUIndex, slot, instance.Profile.Name, ci)
+	}
+}
@@ -0,0 +1,455 @@
+package gpu
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+)
+
+const (
+	PolicyFirstFit        = "first-fit"
+	PolicyBinPack         = "bin-pack"
+	PolicyFairShare       = "fair-share"
+	PolicyPriorityPreempt = "priority-preempt"
+	PolicySpread          = "spread"
+	PolicyProfileAffinity = "profile-affinity"
+)
+
+// PlacementRequest 스케줄러에 전달되는 할당 요청
+type PlacementRequest struct {
+	UserID    string
+	ProjectID string
+	Profile   string
+	Priority  int
+
+	// Reserved 다른 사용자에게 소프트 예약된 인스턴스 UUID (다른 빈 인스턴스가 있으면 배치 대상에서 제외)
+	Reserved map[string]bool
+
+	// Hint pack 또는 spread (정책이 빈 인스턴스를 고르면 그중 힌트에 맞는 GPU/NUMA 노드로 바꿈)
+	Hint string
+
+	// GPUs GPU 인덱스별 사용 현황 (다른 프로파일 인스턴스 포함, Manager.Schedule이 채움)
+	GPUs map[int]GPUUsage
+}
+
+// GPUUsage 한 GPU의 컴퓨트 슬라이스 사용 현황 (프로파일이 섞인 GPU에서 정책이 GPU 전체를 보도록)
+type GPUUsage struct {
+	Slices     int            // 이 GPU에 만들어진 인스턴스의 슬라이스 합
+	UsedSlices int            // 사용 중인 인스턴스의 슬라이스 합
+	Profiles   map[string]int // 사용 중인 인스턴스의 프로파일별 수
+}
+
+// Holder 사용 중인 인스턴스를 점유한 세션
+type Holder struct {
+	SessionID string
+	UserID    string
+	ProjectID string
+	Priority  int
+	CreatedAt time.Time
+}
+
+// Placement 스케줄러 결정
+// Preempt가 true면 Instance를 점유한 세션을 먼저 정리한 뒤 할당
+type Placement struct {
+	Instance *MIGInstance
+	Preempt  bool
+	Holder   Holder // Preempt일 때 밀려나는 세션
+}
+
+// Scheduler MIG 인스턴스 배치 정책
+// instances는 요청한 프로파일의 전체 인스턴스 사본(사용 중 포함), holders는 UUID별 점유 세션
+type Scheduler interface {
+	Name() string
+	Place(req PlacementRequest, instances []*MIGInstance, holders map[string]Holder) (*Placement, error)
+}
+
+// Factory config의 scheduler.params로 정책을 생성
+type Factory func(params map[string]float64) (Scheduler, error)
+
+var (
+	policiesMu sync.RWMutex
+	policies   = map[string]Factory{}
+)
+
+// RegisterPolicy 정책 등록 (사이트 전용 정책은 init()에서 등록해 함께 빌드)
+func RegisterPolicy(name string, factory Factory) {
+	policiesMu.Lock()
+	defer policiesMu.Unlock()
+	if _, exists := policies[name]; exists {
+		panic("gpu: 스케줄러 정책이 이미 등록되어 있습니다: " + name)
+	}
+	policies[name] = factory
+}
+
+// Policies 등록된 정책 이름 목록
+func Policies() []string {
+	policiesMu.RLock()
+	defer policiesMu.RUnlock()
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewScheduler 이름과 파라미터로 정책 생성 (빈 이름은 first-fit)
+func NewScheduler(name string, params map[string]float64) (Scheduler, error) {
+	if name == "" {
+		name = PolicyFirstFit
+	}
+	policiesMu.RLock()
+	factory, ok := policies[name]
+	policiesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("알 수 없는 스케줄러 정책입니다: %q (사용 가능: %v)", name, Policies())
+	}
+	return factory(params)
+}
+
+func init() {
+	RegisterPolicy(PolicyFirstFit, func(params map[string]float64) (Scheduler, error) {
+		return firstFit{}, checkParams(PolicyFirstFit, params)
+	})
+	RegisterPolicy(PolicyBinPack, func(params map[string]float64) (Scheduler, error) {
+		return binPack{}, checkParams(PolicyBinPack, params)
+	})
+	RegisterPolicy(PolicyFairShare, newFairShare)
+	RegisterPolicy(PolicyPriorityPreempt, newPriorityPreempt)
+	RegisterPolicy(PolicySpread, func(params map[string]float64) (Scheduler, error) {
+		return spread{}, checkParams(PolicySpread, params)
+	})
+	RegisterPolicy(PolicyProfileAffinity, func(params map[string]float64) (Scheduler, error) {
+		return profileAffinity{}, checkParams(PolicyProfileAffinity, params)
+	})
+}
+
+// checkParams 정책이 모르는 파라미터가 있으면 오타로 보고 거부
+func checkParams(policy string, params map[string]float64, known ...string) error {
+	for key := range params {
+		found := false
+		for _, k := range known {
+			if key == k {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s 정책에 없는 파라미터입니다: %s (사용 가능: %v)", policy, key, known)
+		}
+	}
+	return nil
+}
+
+// freeInstances 사용 가능한 인스턴스를 GPU 인덱스, UUID 순으로 정렬해 반환
+func freeInstances(instances []*MIGInstance) []*MIGInstance {
+	free := make([]*MIGInstance, 0, len(instances))
+	for _, instance := range instances {
+		if !instance.InUse {
+			free = append(free, instance)
+		}
+	}
+	sort.Slice(free, func(i, j int) bool {
+		if free[i].GPUIndex != free[j].GPUIndex {
+			return free[i].GPUIndex < free[j].GPUIndex
+		}
+		return free[i].UUID < free[j].UUID
+	})
+	return free
+}
+
+// slices 인스턴스가 차지하는 컴퓨트 슬라이스 (알 수 없으면 1)
+func slices(instance *MIGInstance) int {
+	return max(instance.Profile.GPUSlice, 1)
+}
+
+// gpuUsage GPU별 사용 현황 (req.GPUs가 없으면 넘겨받은 같은 프로파일 인스턴스만으로 계산)
+func gpuUsage(req PlacementRequest, instances []*MIGInstance) map[int]GPUUsage {
+	if req.GPUs != nil {
+		return req.GPUs
+	}
+	return summarizeGPUs(instances)
+}
+
+func summarizeGPUs(instances []*MIGInstance) map[int]GPUUsage {
+	usage := make(map[int]GPUUsage)
+	for _, instance := range instances {
+		u := usage[instance.GPUIndex]
+		if u.Profiles == nil {
+			u.Profiles = make(map[string]int)
+		}
+		u.Slices += slices(instance)
+		if instance.InUse {
+			u.UsedSlices += slices(instance)
+			u.Profiles[instance.Profile.Name]++
+		}
+		usage[instance.GPUIndex] = u
+	}
+	return usage
+}
+
+// pickFree better(후보, 지금까지 최선)가 true인 빈 인스턴스 (같으면 GPU 인덱스, UUID 순으로 앞선 것)
+func pickFree(instances []*MIGInstance, better func(candidate, best *MIGInstance) bool) *MIGInstance {
+	var best *MIGInstance
+	for _, instance := range freeInstances(instances) {
+		if best == nil || better(instance, best) {
+			best = instance
+		}
+	}
+	return best
+}
+
+func noCapacity(profile string) error {
+	return errs.New(errs.ErrNoCapacity, "프로파일 %s의 사용 가능한 MIG 인스턴스가 없습니다", profile)
+}
+
+// firstFit 첫 번째 빈 인스턴스 (GPU 인덱스, UUID 순)
+type firstFit struct{}
+
+func (firstFit) Name() string { return PolicyFirstFit }
+
+func (firstFit) Place(req PlacementRequest, instances []*MIGInstance, _ map[string]Holder) (*Placement, error) {
+	free := freeInstances(instances)
+	if len(free) == 0 {
+		return nil, noCapacity(req.Profile)
+	}
+	return &Placement{Instance: free[0]}, nil
+}
+
+// binPack 사용 중인 슬라이스가 가장 많은 GPU에 채워 넣어 빈 GPU를 남김 (큰 프로파일과 GPU 전체 요청이 자리를 찾도록)
+type binPack struct{}
+
+func (binPack) Name() string { return PolicyBinPack }
+
+func (binPack) Place(req PlacementRequest, instances []*MIGInstance, _ map[string]Holder) (*Placement, error) {
+	usage := gpuUsage(req, instances)
+	best := pickFree(instances, func(candidate, best *MIGInstance) bool {
+		return usage[candidate.GPUIndex].UsedSlices > usage[best.GPUIndex].UsedSlices
+	})
+	if best == nil {
+		return nil, noCapacity(req.Profile)
+	}
+	return &Placement{Instance: best}, nil
+}
+
+// spread 사용 중인 슬라이스가 가장 적은 GPU에 배치해 부하와 발열을 GPU마다 고르게 나눔
+type spread struct{}
+
+func (spread) Name() string { return PolicySpread }
+
+func (spread) Place(req PlacementRequest, instances []*MIGInstance, _ map[string]Holder) (*Placement, error) {
+	usage := gpuUsage(req, instances)
+	best := pickFree(instances, func(candidate, best *MIGInstance) bool {
+		return usage[candidate.GPUIndex].UsedSlices < usage[best.GPUIndex].UsedSlices
+	})
+	if best == nil {
+		return nil, noCapacity(req.Profile)
+	}
+	return &Placement{Instance: best}, nil
+}
+
+// profileAffinity 같은 프로파일을 이미 쓰고 있는 GPU에 모으고, 다른 프로파일이 쓰는 GPU는 피함
+// GPU마다 한 프로파일만 남아 작은 세션이 끝나면 GPU가 통째로 비므로 큰 프로파일이 조각화로 밀리지 않음
+// 나머지가 같으면 bin-pack처럼 더 많이 사용 중인 GPU
+type profileAffinity struct{}
+
+func (profileAffinity) Name() string { return PolicyProfileAffinity }
+
+func (profileAffinity) Place(req PlacementRequest, instances []*MIGInstance, _ map[string]Holder) (*Placement, error) {
+	usage := gpuUsage(req, instances)
+	others := func(u GPUUsage) int {
+		n := 0
+		for profile, count := range u.Profiles {
+			if profile != req.Profile {
+				n += count
+			}
+		}
+		return n
+	}
+	best := pickFree(instances, func(candidate, best *MIGInstance) bool {
+		c, b := usage[candidate.GPUIndex], usage[best.GPUIndex]
+		if c.Profiles[req.Profile] != b.Profiles[req.Profile] {
+			return c.Profiles[req.Profile] > b.Profiles[req.Profile]
+		}
+		if others(c) != others(b) {
+			return others(c) < others(b)
+		}
+		return c.UsedSlices > b.UsedSlices
+	})
+	if best == nil {
+		return nil, noCapacity(req.Profile)
+	}
+	return &Placement{Instance: best}, nil
+}
+
+// fairShare 한 프로젝트(프로젝트가 없으면 사용자)가 프로파일 인스턴스의 max_share 비율 이상을 점유하지 못하게 제한
+type fairShare struct {
+	maxShare float64
+}
+
+func newFairShare(params map[string]float64) (Scheduler, error) {
+	if err := checkParams(PolicyFairShare, params, "max_share"); err != nil {
+		return nil, err
+	}
+	maxShare, ok := params["max_share"]
+	if !ok {
+		maxShare = 0.5
+	}
+	if maxShare <= 0 || maxShare > 1 {
+		return nil, fmt.Errorf("fair-share max_share는 0보다 크고 1 이하여야 합니다: %g", maxShare)
+	}
+	return fairShare{maxShare: maxShare}, nil
+}
+
+func (fairShare) Name() string { return PolicyFairShare }
+
+// ShareLimit 프로파일 인스턴스가 total개일 때 한 주체가 가질 수 있는 최대 인스턴스 수
+func (p fairShare) ShareLimit(total int) int {
+	return max(int(math.Ceil(p.maxShare*float64(total))), 1)
+}
+
+func (p fairShare) Place(req PlacementRequest, instances []*MIGInstance, holders map[string]Holder) (*Placement, error) {
+	subject := ShareSubject(req.UserID, req.ProjectID)
+	held := 0
+	for _, instance := range instances {
+		if holder, ok := holders[instance.UUID]; ok && instance.InUse && ShareSubject(holder.UserID, holder.ProjectID) == subject {
+			held++
+		}
+	}
+
+	limit := p.ShareLimit(len(instances))
+	if held >= limit {
+		return nil, errs.Wrap(errs.ErrNoCapacity, &ShareExceededError{Subject: subject, Profile: req.Profile, Held: held, Limit: limit})
+	}
+	return firstFit{}.Place(req, instances, holders)
+}
+
+// ShareLimiter 주체별 인스턴스 수를 제한하는 정책 (쿼터 조회에서 한도를 보여 줄 때 사용)
+type ShareLimiter interface {
+	ShareLimit(total int) int
+}
+
+// ShareExceededError 프로젝트(또는 사용자)가 프로파일 인스턴스의 공정 몫을 이미 다 쓰고 있음
+type ShareExceededError struct {
+	Subject string
+	Profile string
+	Held    int
+	Limit   int
+}
+
+func (e *ShareExceededError) Error() string {
+	return fmt.Sprintf("%s가 프로파일 %s 인스턴스의 공정 몫(%d개)을 이미 사용 중입니다", e.Subject, e.Profile, e.Limit)
+}
+
+// ShareSubject 공정 몫을 세는 단위 (프로젝트가 있으면 프로젝트, 없으면 사용자)
+func ShareSubject(userID, projectID string) string {
+	if projectID != "" {
+		return "project:" + projectID
+	}
+	return "user:" + userID
+}
+
+// priorityPreempt 빈 인스턴스가 없으면 우선순위가 min_gap 이상 낮은 세션 중 가장 낮은(동률이면 가장 늦게 시작한) 세션을 밀어냄
+type priorityPreempt struct {
+	minGap int
+}
+
+func newPriorityPreempt(params map[string]float64) (Scheduler, error) {
+	if err := checkParams(PolicyPriorityPreempt, params, "min_gap"); err != nil {
+		return nil, err
+	}
+	minGap := 1
+	if v, ok := params["min_gap"]; ok {
+		minGap = int(v)
+	}
+	if minGap < 1 {
+		return nil, fmt.Errorf("priority-preempt min_gap은 1 이상이어야 합니다: %d", minGap)
+	}
+	return priorityPreempt{minGap: minGap}, nil
+}
+
+func (priorityPreempt) Name() string { return PolicyPriorityPreempt }
+
+func (p priorityPreempt) Place(req PlacementRequest, instances []*MIGInstance, holders map[string]Holder) (*Placement, error) {
+	if placement, err := (firstFit{}).Place(req, instances, holders); err == nil {
+		return placement, nil
+	}
+
+	var victim *MIGInstance
+	for _, instance := range instances {
+		holder, ok := holders[instance.UUID]
+		if !instance.InUse || !ok || req.Priority-holder.Priority < p.minGap {
+			continue
+		}
+		if victim == nil {
+			victim = instance
+			continue
+		}
+		current := holders[victim.UUID]
+		if holder.Priority < current.Priority || (holder.Priority == current.Priority && holder.CreatedAt.After(current.CreatedAt)) {
+			victim = instance
+		}
+	}
+	if victim == nil {
+		return nil, noCapacity(req.Profile)
+	}
+	return &Placement{Instance: victim, Preempt: true, Holder: holders[victim.UUID]}, nil
+}
+
+// Schedule 정책에 프로파일의 인스턴스 사본을 넘겨 배치 결정 (할당은 하지 않음)
+func (m *Manager) Schedule(scheduler Scheduler, req PlacementRequest, holders map[string]Holder) (*Placement, error) {
+	m.mu.RLock()
+	instances := make([]*MIGInstance, 0, len(m.migInstances))
+	all := make([]*MIGInstance, 0, len(m.migInstances))
+	numa := make(map[int]int)
+	for _, instance := range m.migInstances {
+		all = append(all, instance)
+		if instance.Profile.Name == req.Profile {
+			copied := *instance
+			instances = append(instances, &copied)
+		}
+	}
+	req.GPUs = summarizeGPUs(all)
+	for index, t := range m.topology {
+		numa[index] = t.NUMANode
+	}
+	m.mu.RUnlock()
+
+	if len(instances) == 0 {
+		return nil, noCapacity(req.Profile)
+	}
+	hideReserved(instances, req.Reserved)
+	placement, err := scheduler.Place(req, instances, holders)
+	if err != nil {
+		return nil, err
+	}
+	applyPlacementHint(req.Hint, placement, instances, numa)
+	return placement, nil
+}
+
+// hideReserved 예약되지 않은 빈 인스턴스가 하나라도 있으면 예약된 빈 인스턴스를 사용 중으로 표시 (사본에만 적용)
+// 예약된 인스턴스만 남았으면 그대로 두어 예약 때문에 요청이 거부되거나 선점이 일어나지 않게 함
+func hideReserved(instances []*MIGInstance, reserved map[string]bool) {
+	if len(reserved) == 0 {
+		return
+	}
+	unreserved := false
+	for _, instance := range instances {
+		if !instance.InUse && !reserved[instance.UUID] {
+			unreserved = true
+			break
+		}
+	}
+	if !unreserved {
+		return
+	}
+	for _, instance := range instances {
+		if !instance.InUse && reserved[instance.UUID] {
+			instance.InUse = true
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package gpu
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// 배치 힌트 (CreateRequest.placement)
+const (
+	PlacementPack   = "pack"   // 이미 많이 쓰는 GPU/NUMA 노드에 붙여 배치 (다른 세션과 가까이)
+	PlacementSpread = "spread" // 가장 덜 쓰는 GPU/NUMA 노드에 배치 (메모리 대역폭, PCIe 경합 회피)
+)
+
+// sysPCIDevices GPU의 NUMA 노드를 읽는 sysfs 경로
+const sysPCIDevices = "/sys/bus/pci/devices"
+
+// Topology 물리 GPU의 위치 (PCI 버스와 NUMA 노드)
+type Topology struct {
+	GPUIndex int    `json:"gpu_index"`
+	PCIBusID string `json:"pci_bus_id,omitempty"`
+	NUMANode int    `json:"numa_node"` // -1이면 알 수 없음 (단일 소켓이거나 확인 실패)
+}
+
+// ValidPlacementHint 알려진 배치 힌트인지 (빈 값은 힌트 없음)
+func ValidPlacementHint(hint string) bool {
+	return hint == "" || hint == PlacementPack || hint == PlacementSpread
+}
+
+// readNUMANode nvidia-smi의 버스 ID(00000000:3B:00.0)를 sysfs 형식(0000:3b:00.0)으로 바꿔 numa_node를 읽음
+func readNUMANode(busID string) int {
+	id := strings.ToLower(busID)
+	if domain, rest, ok := strings.Cut(id, ":"); ok && len(domain) > 4 {
+		id = domain[len(domain)-4:] + ":" + rest
+	}
+	raw, err := os.ReadFile(filepath.Join(sysPCIDevices, id, "numa_node"))
+	if err != nil {
+		return -1
+	}
+	node, err := strconv.Atoi(strings.TrimSpace(string(raw)))
+	if err != nil {
+		return -1
+	}
+	return node
+}
+
+// Topology GPU 인덱스의 위치 (확인하지 못했으면 NUMA 노드 -1)
+func (m *Manager) Topology(gpuIndex int) Topology {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if t, ok := m.topology[gpuIndex]; ok {
+		return t
+	}
+	return Topology{GPUIndex: gpuIndex, NUMANode: -1}
+}
+
+// applyPlacementHint 정책이 고른 빈 인스턴스 대신 힌트에 맞는 빈 인스턴스를 고름
+// 선점 결정이나 할당 가능 여부는 바꾸지 않음 (정책이 빈 인스턴스를 골랐을 때만 적용)
+func applyPlacementHint(hint string, placement *Placement, instances []*MIGInstance, numa map[int]int) {
+	if hint == "" || placement == nil || placement.Preempt {
+		return
+	}
+	usedGPU := make(map[int]int)
+	usedNUMA := make(map[int]int)
+	for _, instance := range instances {
+		if instance.InUse {
+			usedGPU[instance.GPUIndex]++
+			usedNUMA[numa[instance.GPUIndex]]++
+		}
+	}
+
+	var best *MIGInstance
+	for _, instance := range freeInstances(instances) {
+		if best == nil {
+			best = instance
+			continue
+		}
+		g, bg := usedGPU[instance.GPUIndex], usedGPU[best.GPUIndex]
+		n, bn := usedNUMA[numa[instance.GPUIndex]], usedNUMA[numa[best.GPUIndex]]
+		switch hint {
+		case PlacementPack:
+			if g > bg || (g == bg && n > bn) {
+				best = instance
+			}
+		case PlacementSpread:
+			if g < bg || (g == bg && n < bn) {
+				best = instance
+			}
+		}
+	}
+	if best != nil {
+		placement.Instance = best
+	}
+}
@@ -0,0 +1,58 @@
+package gpu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MinMIGCUDA MIG 인스턴스를 인식하는 최소 CUDA 버전 (그 이전 런타임은 MIG 장치를 보지 못함)
+const MinMIGCUDA = "11.0"
+
+// CompareVersions "535.104.05", "12.2" 같은 점 구분 숫자 버전 비교 (a<b면 -1, 같으면 0, a>b면 1)
+// 빠진 자리는 0으로 봄 ("12" == "12.0")
+func CompareVersions(a, b string) (int, error) {
+	pa, err := parseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	pb, err := parseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < max(len(pa), len(pb)); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		switch {
+		case x < y:
+			return -1, nil
+		case x > y:
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// ValidVersion 점 구분 숫자 버전인지 확인
+func ValidVersion(v string) bool {
+	_, err := parseVersion(v)
+	return err == nil
+}
+
+func parseVersion(v string) ([]int, error) {
+	parts := strings.Split(strings.TrimSpace(v), ".")
+	nums := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("버전 형식이 잘못되었습니다: %q", v)
+		}
+		nums[i] = n
+	}
+	return nums, nil
+}
@@ -0,0 +1,188 @@
+package lock
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+const (
+	// DefaultTTL 보유자가 죽었을 때 다른 복제본이 락을 가져갈 수 있기까지의 시간
+	DefaultTTL = 30 * time.Second
+	// DefaultWaitTimeout 락 획득 대기 한도 (교착 상태로 요청이 영원히 멈추지 않도록)
+	DefaultWaitTimeout = 60 * time.Second
+
+	pollInterval = 100 * time.Millisecond
+)
+
+var (
+	acquireTotal = metrics.NewCounter("sandman_lock_acquire_total", "분산 락 획득 시도 결과", "name", "result")
+	waitSeconds  = metrics.NewHistogram("sandman_lock_wait_seconds", "분산 락 획득까지 대기한 시간", nil, "name")
+	holdSeconds  = metrics.NewHistogram("sandman_lock_hold_seconds", "분산 락 보유 시간", nil, "name")
+	heldGauge    = metrics.NewGauge("sandman_lock_held", "이 프로세스가 현재 보유 중인 분산 락", "name")
+	lostTotal    = metrics.NewCounter("sandman_lock_lost_total", "보유 중 갱신에 실패해 잃은 분산 락", "name")
+)
+
+// Locker 저장소 임대 테이블을 이용한 복제본 간 상호 배제
+type Locker struct {
+	store       store.Store
+	owner       string
+	ttl         time.Duration
+	waitTimeout time.Duration
+}
+
+// Lease 획득한 락. 보유 중에는 백그라운드에서 TTL을 갱신
+type Lease struct {
+	locker     *Locker
+	name       string
+	acquiredAt time.Time
+	stop       chan struct{}
+	once       sync.Once
+
+	mu        sync.Mutex
+	renewedAt time.Time
+	lost      bool
+}
+
+func NewLocker(store store.Store, owner string) *Locker {
+	return &Locker{
+		store:       store,
+		owner:       owner,
+		ttl:         DefaultTTL,
+		waitTimeout: DefaultWaitTimeout,
+	}
+}
+
+// DefaultOwner 호스트명, PID, 난수로 만든 프로세스 식별자
+func DefaultOwner() string {
+	host, _ := os.Hostname()
+	b := make([]byte, 4)
+	rand.Read(b)
+	return fmt.Sprintf("%s:%d:%s", host, os.Getpid(), hex.EncodeToString(b))
+}
+
+func (l *Locker) Owner() string {
+	return l.owner
+}
+
+// Acquire 락을 얻을 때까지 대기하고, 대기 한도를 넘으면 에러 반환
+func (l *Locker) Acquire(name string) (*Lease, error) {
+	start := time.Now()
+	deadline := start.Add(l.waitTimeout)
+
+	for {
+		ok, err := l.store.TryAcquireLock(name, l.owner, l.ttl)
+		if err != nil {
+			log.Printf("⚠️ 락 %s 획득 시도 실패: %v", name, err)
+		}
+		if ok {
+			acquireTotal.Inc(name, "acquired")
+			waitSeconds.Observe(time.Since(start).Seconds(), name)
+			return l.newLease(name), nil
+		}
+
+		if time.Now().After(deadline) {
+			acquireTotal.Inc(name, "timeout")
+			return nil, fmt.Errorf("락 %s 획득 대기 시간(%v) 초과", name, l.waitTimeout)
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// TryAcquire 한 번만 시도하고, 다른 보유자가 있으면 nil 반환
+func (l *Locker) TryAcquire(name string) (*Lease, error) {
+	ok, err := l.store.TryAcquireLock(name, l.owner, l.ttl)
+	if err != nil {
+		acquireTotal.Inc(name, "error")
+		return nil, err
+	}
+	if !ok {
+		acquireTotal.Inc(name, "busy")
+		return nil, nil
+	}
+	acquireTotal.Inc(name, "acquired")
+	return l.newLease(name), nil
+}
+
+// WithLock 락을 보유한 상태로 fn 실행
+func (l *Locker) WithLock(name string, fn func() error) error {
+	lease, err := l.Acquire(name)
+	if err != nil {
+		return err
+	}
+	defer lease.Release()
+	return fn()
+}
+
+func (l *Locker) newLease(name string) *Lease {
+	lease := &Lease{
+		locker:     l,
+		name:       name,
+		acquiredAt: time.Now(),
+		renewedAt:  time.Now(),
+		stop:       make(chan struct{}),
+	}
+	heldGauge.Inc(name)
+	go lease.renew()
+	return lease
+}
+
+func (lease *Lease) renew() {
+	ticker := time.NewTicker(lease.locker.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			ok, err := lease.locker.store.TryAcquireLock(lease.name, lease.locker.owner, lease.locker.ttl)
+			if err != nil || !ok {
+				log.Printf("⚠️ 락 %s 갱신 실패 (다른 복제본이 가져갔을 수 있음): %v", lease.name, err)
+			}
+			lease.mu.Lock()
+			switch {
+			case ok:
+				lease.renewedAt = time.Now()
+			case err == nil && !lease.lost:
+				// 다른 보유자가 있다는 뜻이므로 다시 갱신되더라도 잃은 것으로 봄
+				lease.lost = true
+				lostTotal.Inc(lease.name)
+			}
+			lease.mu.Unlock()
+		case <-lease.stop:
+			return
+		}
+	}
+}
+
+// Check 락을 아직 보유하고 있는지 확인 (다른 복제본이 가져갔거나 TTL 동안 갱신하지 못했으면 에러)
+// 락 아래에서 한 작업을 저장소에 기록하기 직전에 호출
+func (lease *Lease) Check() error {
+	lease.mu.Lock()
+	defer lease.mu.Unlock()
+	if lease.lost {
+		return fmt.Errorf("락 %s를 다른 복제본이 가져갔습니다", lease.name)
+	}
+	if stale := time.Since(lease.renewedAt); stale >= lease.locker.ttl {
+		return fmt.Errorf("락 %s를 %v 동안 갱신하지 못했습니다", lease.name, stale.Round(time.Second))
+	}
+	return nil
+}
+
+// Release 갱신을 멈추고 락 해제 (여러 번 호출해도 안전)
+func (lease *Lease) Release() {
+	lease.once.Do(func() {
+		close(lease.stop)
+		if err := lease.locker.store.ReleaseLock(lease.name, lease.locker.owner); err != nil {
+			log.Printf("⚠️ 락 %s 해제 실패: %v", lease.name, err)
+		}
+		heldGauge.Dec(lease.name)
+		holdSeconds.Observe(time.Since(lease.acquiredAt).Seconds(), lease.name)
+	})
+}
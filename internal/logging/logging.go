@@ -0,0 +1,36 @@
+package logging
+
+import (
+	"fmt"
+	"log"
+	"sync/atomic"
+)
+
+const (
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+)
+
+var debugEnabled atomic.Bool
+
+// ValidLevel 지원하는 로그 레벨인지 확인
+func ValidLevel(level string) bool {
+	return level == LevelDebug || level == LevelInfo
+}
+
+// SetLevel 로그 레벨 변경 (info가 기본이며, debug에서만 Debugf가 출력됨)
+func SetLevel(level string) {
+	debugEnabled.Store(level == LevelDebug)
+}
+
+// DebugEnabled 현재 debug 레벨인지 여부
+func DebugEnabled() bool {
+	return debugEnabled.Load()
+}
+
+// Debugf debug 레벨에서만 출력되는 로그
+func Debugf(format string, args ...interface{}) {
+	if debugEnabled.Load() {
+		log.Output(2, fmt.Sprintf("🐛 "+format, args...))
+	}
+}
@@ -0,0 +1,128 @@
+package logging
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+)
+
+// 반복 로그 처리 방식 (log_sampling.mode)
+const (
+	SamplingAll     = "all"     // 매번 출력
+	SamplingSummary = "summary" // 창마다 첫 줄만 출력하고 나머지는 창이 끝날 때 건수로 요약
+	SamplingOff     = "off"     // 정상 경로 로그는 출력하지 않음 (경고는 summary처럼 요약)
+)
+
+var suppressedLines = metrics.NewCounter("sandman_log_lines_suppressed_total", "샘플링으로 출력하지 않은 로그 줄 수", "key")
+
+// sampler 키별로 현재 창에서 생략한 로그 수와 마지막 내용
+type sampler struct {
+	mu      sync.Mutex
+	mode    string
+	window  time.Duration
+	entries map[string]*sampledEntry
+}
+
+type sampledEntry struct {
+	start   time.Time
+	skipped int
+	last    string
+	timer   *time.Timer
+}
+
+var sampling = &sampler{
+	mode:    SamplingSummary,
+	window:  time.Hour,
+	entries: make(map[string]*sampledEntry),
+}
+
+// ValidSamplingMode 지원하는 반복 로그 처리 방식인지 확인
+func ValidSamplingMode(mode string) bool {
+	return mode == SamplingAll || mode == SamplingSummary || mode == SamplingOff
+}
+
+// SetSampling 반복 로그 처리 방식과 요약 창 변경 (설정 리로드 시 호출, 진행 중인 창은 그대로 끝남)
+func SetSampling(mode string, window time.Duration) {
+	sampling.mu.Lock()
+	defer sampling.mu.Unlock()
+	sampling.mode = mode
+	sampling.window = window
+}
+
+// Routine 주기 작업의 정상 경로 로그 (같은 key는 요약되거나 off에서 생략, debug 레벨에서는 항상 출력)
+// key는 메트릭 레이블로도 쓰므로 세션 ID 같은 값을 넣지 말 것
+func Routine(key, format string, args ...interface{}) {
+	sampling.output(key, fmt.Sprintf(format, args...), true)
+}
+
+// Repeated 매 주기 같은 내용이 반복될 수 있는 경고 (all이 아니면 창마다 첫 줄과 요약만 출력)
+func Repeated(key, format string, args ...interface{}) {
+	sampling.output(key, fmt.Sprintf(format, args...), false)
+}
+
+func (s *sampler) output(key, msg string, routine bool) {
+	if debugEnabled.Load() {
+		log.Output(3, msg)
+		return
+	}
+
+	s.mu.Lock()
+	mode := s.mode
+	if mode == SamplingAll {
+		s.mu.Unlock()
+		log.Output(3, msg)
+		return
+	}
+	if mode == SamplingOff && routine {
+		s.mu.Unlock()
+		suppressedLines.Inc(key)
+		return
+	}
+
+	if e, ok := s.entries[key]; ok {
+		e.skipped++
+		e.last = msg
+		s.mu.Unlock()
+		suppressedLines.Inc(key)
+		return
+	}
+	e := &sampledEntry{start: time.Now()}
+	e.timer = time.AfterFunc(s.window, func() { s.flush(key, e) })
+	s.entries[key] = e
+	s.mu.Unlock()
+	log.Output(3, msg)
+}
+
+// flush 창이 끝난 key의 요약 출력 (생략한 줄이 없으면 조용히 창만 닫음)
+func (s *sampler) flush(key string, e *sampledEntry) {
+	s.mu.Lock()
+	if s.entries[key] != e {
+		// FlushSampled가 먼저 처리함
+		s.mu.Unlock()
+		return
+	}
+	delete(s.entries, key)
+	skipped, last := e.skipped, e.last
+	s.mu.Unlock()
+	if skipped > 0 {
+		log.Printf("🔁 [%s] 같은 종류의 로그 %d건 생략 (지난 %v) - 마지막: %s",
+			key, skipped, time.Since(e.start).Round(time.Second), last)
+	}
+}
+
+// FlushSampled 진행 중인 모든 창의 요약을 바로 출력 (종료 시 생략한 건수가 사라지지 않도록)
+func FlushSampled() {
+	sampling.mu.Lock()
+	entries := make(map[string]*sampledEntry, len(sampling.entries))
+	for key, e := range sampling.entries {
+		e.timer.Stop()
+		entries[key] = e
+	}
+	sampling.mu.Unlock()
+	for key, e := range entries {
+		sampling.flush(key, e)
+	}
+}
@@ -0,0 +1,104 @@
+// Package manifest 세션 환경 매니페스트에 서명하고 검증하는 ed25519 키
+//
+// 매니페스트는 JSON 본문 바이트 그대로 서명한다. 검증하는 쪽은 GET /manifests/public-key의
+// 공개키로 내려받은 payload 필드(base64 디코딩한 본문 바이트)와 signature를 확인하면 된다.
+package manifest
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// Algorithm 서명 알고리즘 이름 (응답의 algorithm 필드)
+const Algorithm = "ed25519"
+
+// Signer 매니페스트 서명 키 (복제본이 여러 개면 같은 키 파일을 나눠 써야 서로의 매니페스트를 검증할 수 있음)
+type Signer struct {
+	key ed25519.PrivateKey
+}
+
+// LoadOrCreate path의 서명 키를 읽고, 없으면 새 ed25519 키를 만들어 저장
+func LoadOrCreate(path string) (*Signer, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		data, err = generateKey(path)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("🔏 새 매니페스트 서명 키 생성: %s", path)
+	} else if err != nil {
+		return nil, fmt.Errorf("매니페스트 서명 키 읽기 실패: %v", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("매니페스트 서명 키 파싱 실패: PEM 블록이 없습니다")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("매니페스트 서명 키 파싱 실패: %v", err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("매니페스트 서명 키는 ed25519 키여야 합니다")
+	}
+
+	signer := &Signer{key: key}
+	log.Printf("🔏 매니페스트 서명 키 로드 완료: %s", signer.KeyID())
+	return signer, nil
+}
+
+func generateKey(path string) ([]byte, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("매니페스트 서명 키 생성 실패: %v", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, fmt.Errorf("매니페스트 서명 키 인코딩 실패: %v", err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("매니페스트 서명 키 디렉토리 생성 실패: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return nil, fmt.Errorf("매니페스트 서명 키 저장 실패: %v", err)
+	}
+	return data, nil
+}
+
+// PublicKey base64로 인코딩한 공개키 (32바이트)
+func (s *Signer) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(s.key.Public().(ed25519.PublicKey))
+}
+
+// KeyID 공개키 SHA-256의 앞 16바이트 (매니페스트마다 어느 키로 서명했는지 기록)
+func (s *Signer) KeyID() string {
+	sum := sha256.Sum256(s.key.Public().(ed25519.PublicKey))
+	return hex.EncodeToString(sum[:16])
+}
+
+// Sign 본문 바이트에 대한 base64 서명
+func (s *Signer) Sign(payload []byte) string {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, payload))
+}
+
+// Verify 이 키로 만든 서명인지 확인
+func (s *Signer) Verify(payload []byte, signature string) bool {
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(s.key.Public().(ed25519.PublicKey), payload, sig)
+}
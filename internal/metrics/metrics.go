@@ -0,0 +1,256 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets 초 단위 지연 시간 히스토그램 기본 구간
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+type collector interface {
+	name() string
+	write(w io.Writer)
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[string]collector{}
+)
+
+func register(c collector) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, exists := registry[c.name()]; exists {
+		panic("metrics: 중복 등록된 메트릭 " + c.name())
+	}
+	registry[c.name()] = c
+}
+
+// WriteText 등록된 모든 메트릭을 Prometheus 텍스트 형식으로 출력
+func WriteText(w io.Writer) {
+	registryMu.Lock()
+	collectors := make([]collector, 0, len(registry))
+	for _, c := range registry {
+		collectors = append(collectors, c)
+	}
+	registryMu.Unlock()
+
+	sort.Slice(collectors, func(i, j int) bool { return collectors[i].name() < collectors[j].name() })
+	for _, c := range collectors {
+		c.write(w)
+	}
+}
+
+// Handler /metrics 엔드포인트용 HTTP 핸들러
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		WriteText(w)
+	})
+}
+
+// vec 레이블 조합별 값을 보관하는 공통 구조
+type vec struct {
+	metricName string
+	help       string
+	kind       string
+	labelNames []string
+
+	mu     sync.Mutex
+	series map[string][]string // key -> 레이블 값
+}
+
+func newVec(name, help, kind string, labelNames []string) vec {
+	return vec{
+		metricName: name,
+		help:       help,
+		kind:       kind,
+		labelNames: labelNames,
+		series:     map[string][]string{},
+	}
+}
+
+func (v *vec) name() string { return v.metricName }
+
+func (v *vec) key(labels []string) string {
+	if len(labels) != len(v.labelNames) {
+		panic(fmt.Sprintf("metrics: %s 레이블 개수 불일치 (기대 %d, 실제 %d)", v.metricName, len(v.labelNames), len(labels)))
+	}
+	key := strings.Join(labels, "\xff")
+	if _, ok := v.series[key]; !ok {
+		v.series[key] = append([]string(nil), labels...)
+	}
+	return key
+}
+
+func (v *vec) sortedKeys() []string {
+	keys := make([]string, 0, len(v.series))
+	for key := range v.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (v *vec) header(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", v.metricName, v.help, v.metricName, v.kind)
+}
+
+func formatLabels(names, values []string, extra ...string) string {
+	pairs := make([]string, 0, len(names)+len(extra)/2)
+	for i, name := range names {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, escape(values[i])))
+	}
+	for i := 0; i+1 < len(extra); i += 2 {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extra[i], escape(extra[i+1])))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	return fmt.Sprintf("%g", f)
+}
+
+// Counter 단조 증가 값
+type Counter struct {
+	vec
+	values map[string]float64
+}
+
+func NewCounter(name, help string, labelNames ...string) *Counter {
+	c := &Counter{vec: newVec(name, help, "counter", labelNames), values: map[string]float64{}}
+	register(c)
+	return c
+}
+
+func (c *Counter) Inc(labels ...string) { c.Add(1, labels...) }
+
+func (c *Counter) Add(delta float64, labels ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[c.key(labels)] += delta
+}
+
+func (c *Counter) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.header(w)
+	for _, key := range c.sortedKeys() {
+		fmt.Fprintf(w, "%s%s %s\n", c.metricName, formatLabels(c.labelNames, c.series[key]), formatFloat(c.values[key]))
+	}
+}
+
+// Gauge 증감 가능한 현재 값
+type Gauge struct {
+	vec
+	values map[string]float64
+}
+
+func NewGauge(name, help string, labelNames ...string) *Gauge {
+	g := &Gauge{vec: newVec(name, help, "gauge", labelNames), values: map[string]float64{}}
+	register(g)
+	return g
+}
+
+func (g *Gauge) Set(value float64, labels ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.values[g.key(labels)] = value
+}
+
+func (g *Gauge) Add(delta float64, labels ...string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.values[g.key(labels)] += delta
+}
+
+func (g *Gauge) Inc(labels ...string) { g.Add(1, labels...) }
+func (g *Gauge) Dec(labels ...string) { g.Add(-1, labels...) }
+
+func (g *Gauge) write(w io.Writer) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.header(w)
+	for _, key := range g.sortedKeys() {
+		fmt.Fprintf(w, "%s%s %s\n", g.metricName, formatLabels(g.labelNames, g.series[key]), formatFloat(g.values[key]))
+	}
+}
+
+// Histogram 구간별 누적 관측 횟수
+type Histogram struct {
+	vec
+	buckets []float64
+	counts  map[string][]uint64 // 구간별 (비누적) 횟수, 마지막은 +Inf
+	sums    map[string]float64
+}
+
+func NewHistogram(name, help string, buckets []float64, labelNames ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	h := &Histogram{
+		vec:     newVec(name, help, "histogram", labelNames),
+		buckets: append([]float64(nil), buckets...),
+		counts:  map[string][]uint64{},
+		sums:    map[string]float64{},
+	}
+	sort.Float64s(h.buckets)
+	register(h)
+	return h
+}
+
+func (h *Histogram) Observe(value float64, labels ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := h.key(labels)
+	counts, ok := h.counts[key]
+	if !ok {
+		counts = make([]uint64, len(h.buckets)+1)
+		h.counts[key] = counts
+	}
+
+	idx := sort.SearchFloat64s(h.buckets, value)
+	counts[idx]++
+	h.sums[key] += value
+}
+
+func (h *Histogram) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.header(w)
+	for _, key := range h.sortedKeys() {
+		labels := h.series[key]
+		counts := h.counts[key]
+
+		var cumulative uint64
+		for i, upper := range append(append([]float64(nil), h.buckets...), math.Inf(1)) {
+			cumulative += counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.metricName, formatLabels(h.labelNames, labels, "le", formatFloat(upper)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.metricName, formatLabels(h.labelNames, labels), formatFloat(h.sums[key]))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.metricName, formatLabels(h.labelNames, labels), cumulative)
+	}
+}
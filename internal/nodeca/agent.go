@@ -0,0 +1,298 @@
+package nodeca
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnrollRequest 일회용 가입 토큰으로 첫 인증서를 받는 요청 (POST /nodes/enroll)
+type EnrollRequest struct {
+	NodeID string `json:"node_id" binding:"required"`
+	Token  string `json:"token" binding:"required"`
+	CSR    string `json:"csr" binding:"required"` // PEM
+}
+
+// RenewRequest 현재 인증서로 인증한 채 새 인증서를 받는 요청 (에이전트 리스너의 POST /agent/nodes/:id/certificate)
+type RenewRequest struct {
+	CSR string `json:"csr" binding:"required"`
+}
+
+// CertificateResponse 발급된 노드 인증서와 신뢰할 CA 인증서
+type CertificateResponse struct {
+	NodeID        string    `json:"node_id"`
+	SPIFFEID      string    `json:"spiffe_id"`
+	Serial        string    `json:"serial"`
+	Certificate   string    `json:"certificate"`
+	CACertificate string    `json:"ca_certificate"`
+	ExpiresAt     time.Time `json:"expires_at"`
+}
+
+// Agent 노드 에이전트 쪽 mTLS 신원
+// 키는 노드에서 만들고 CSR만 보내며, 인증서 유효 기간의 2/3가 지나면 현재 인증서로 인증해 새로 받음
+type Agent struct {
+	nodeID     string
+	dir        string // node.key, node.crt, ca.crt
+	controlURL string // 에이전트 리스너 주소 (https://host:port)
+	client     *http.Client
+
+	mu   sync.Mutex
+	cert *tls.Certificate
+	pool *x509.CertPool
+}
+
+// NewAgent dir에 이전에 받은 인증서가 있으면 읽어 둠 (없으면 Enroll 필요)
+func NewAgent(nodeID, dir, controlURL string) (*Agent, error) {
+	a := &Agent{nodeID: nodeID, dir: dir, controlURL: strings.TrimRight(controlURL, "/")}
+	a.client = &http.Client{Timeout: 30 * time.Second, Transport: &http.Transport{TLSClientConfig: a.TLSConfig()}}
+
+	certPEM, err := os.ReadFile(filepath.Join(dir, "node.crt"))
+	if os.IsNotExist(err) {
+		return a, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("노드 인증서 읽기 실패: %v", err)
+	}
+	keyPEM, err := os.ReadFile(filepath.Join(dir, "node.key"))
+	if err != nil {
+		return nil, fmt.Errorf("노드 키 읽기 실패: %v", err)
+	}
+	caPEM, err := os.ReadFile(filepath.Join(dir, "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("CA 인증서 읽기 실패: %v", err)
+	}
+	if err := a.install(keyPEM, certPEM, caPEM); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Enrolled 쓸 수 있는 인증서가 있는지 (만료된 인증서로는 갱신할 수 없으므로 다시 가입해야 함)
+func (a *Agent) Enrolled() bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.cert != nil && time.Now().Before(a.cert.Leaf.NotAfter)
+}
+
+// Enroll 관리자가 발급한 일회용 가입 토큰으로 첫 인증서를 받음
+// 가입 요청은 일반 API로 가므로, caFingerprint가 있으면 받은 CA 인증서가 그 지문인지 확인
+func (a *Agent) Enroll(ctx context.Context, apiURL, token, caFingerprint string) error {
+	keyPEM, csrPEM, err := newCSR(a.nodeID)
+	if err != nil {
+		return err
+	}
+	var resp CertificateResponse
+	req := EnrollRequest{NodeID: a.nodeID, Token: token, CSR: string(csrPEM)}
+	if err := post(ctx, http.DefaultClient, strings.TrimRight(apiURL, "/")+"/nodes/enroll", req, &resp); err != nil {
+		return fmt.Errorf("노드 가입 실패: %v", err)
+	}
+	if caFingerprint != "" {
+		ca, err := ParseCertificate([]byte(resp.CACertificate))
+		if err != nil {
+			return fmt.Errorf("CA 인증서 파싱 실패: %v", err)
+		}
+		if !strings.EqualFold(Fingerprint(ca), caFingerprint) {
+			return fmt.Errorf("CA 지문이 다릅니다: %s (기대값 %s)", Fingerprint(ca), caFingerprint)
+		}
+	}
+	if err := a.save(keyPEM, resp); err != nil {
+		return err
+	}
+	log.Printf("🔏 노드 %s 가입 완료 (%s, 만료: %s)", a.nodeID, resp.SPIFFEID, resp.ExpiresAt.Format(time.RFC3339))
+	return nil
+}
+
+// Renew 현재 인증서로 인증해 새 키와 인증서로 교체
+func (a *Agent) Renew(ctx context.Context) error {
+	keyPEM, csrPEM, err := newCSR(a.nodeID)
+	if err != nil {
+		return err
+	}
+	var resp CertificateResponse
+	url := a.controlURL + "/agent/nodes/" + a.nodeID + "/certificate"
+	if err := post(ctx, a.client, url, RenewRequest{CSR: string(csrPEM)}, &resp); err != nil {
+		return fmt.Errorf("노드 인증서 갱신 실패: %v", err)
+	}
+	if err := a.save(keyPEM, resp); err != nil {
+		return err
+	}
+	log.Printf("🔏 노드 인증서 갱신 (만료: %s)", resp.ExpiresAt.Format(time.RFC3339))
+	return nil
+}
+
+// Run ctx가 끝날 때까지 인증서를 갱신 (가입한 뒤 호출, 실패하면 남은 시간의 1/10, 최대 1분 뒤 다시 시도)
+func (a *Agent) Run(ctx context.Context) {
+	for {
+		a.mu.Lock()
+		if a.cert == nil {
+			a.mu.Unlock()
+			return
+		}
+		leaf := a.cert.Leaf
+		a.mu.Unlock()
+
+		wait := time.Until(leaf.NotBefore.Add(leaf.NotAfter.Sub(leaf.NotBefore) * 2 / 3))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		if err := a.Renew(ctx); err != nil {
+			retry := time.Until(leaf.NotAfter) / 10
+			if retry > time.Minute || retry <= 0 {
+				retry = time.Minute
+			}
+			log.Printf("⚠️ %v (%v 뒤 다시 시도)", err, retry)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(retry):
+			}
+		}
+	}
+}
+
+// TLSConfig 컨트롤 플레인 에이전트 리스너에 접속할 때 쓰는 설정 (갱신된 인증서를 바로 사용)
+func (a *Agent) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			a.mu.Lock()
+			defer a.mu.Unlock()
+			if a.cert == nil {
+				return &tls.Certificate{}, nil
+			}
+			return a.cert, nil
+		},
+		// RootCAs는 갱신으로 바뀔 수 있어 직접 검증
+		InsecureSkipVerify: true,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			a.mu.Lock()
+			pool := a.pool
+			a.mu.Unlock()
+			if pool == nil {
+				return fmt.Errorf("신뢰할 CA 인증서가 없습니다 (먼저 가입하세요)")
+			}
+			opts := x509.VerifyOptions{Roots: pool, DNSName: state.ServerName, Intermediates: x509.NewCertPool()}
+			for _, cert := range state.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(cert)
+			}
+			_, err := state.PeerCertificates[0].Verify(opts)
+			return err
+		},
+	}
+}
+
+func (a *Agent) save(keyPEM []byte, resp CertificateResponse) error {
+	if err := a.install(keyPEM, []byte(resp.Certificate), []byte(resp.CACertificate)); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(a.dir, 0700); err != nil {
+		return fmt.Errorf("노드 인증서 디렉토리 생성 실패: %v", err)
+	}
+	files := []struct {
+		name string
+		data []byte
+		mode os.FileMode
+	}{
+		{"node.key", keyPEM, 0600},
+		{"node.crt", []byte(resp.Certificate), 0644},
+		{"ca.crt", []byte(resp.CACertificate), 0644},
+	}
+	for _, f := range files {
+		// 중간에 멈춰도 키와 인증서가 어긋나지 않도록 임시 파일에 쓰고 이름을 바꿈
+		tmp := filepath.Join(a.dir, f.name+".tmp")
+		if err := os.WriteFile(tmp, f.data, f.mode); err != nil {
+			return fmt.Errorf("%s 저장 실패: %v", f.name, err)
+		}
+		if err := os.Rename(tmp, filepath.Join(a.dir, f.name)); err != nil {
+			return fmt.Errorf("%s 저장 실패: %v", f.name, err)
+		}
+	}
+	return nil
+}
+
+func (a *Agent) install(keyPEM, certPEM, caPEM []byte) error {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("노드 인증서와 키가 맞지 않습니다: %v", err)
+	}
+	if cert.Leaf == nil {
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return err
+		}
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return fmt.Errorf("CA 인증서를 읽을 수 없습니다")
+	}
+	if _, err := cert.Leaf.Verify(x509.VerifyOptions{Roots: pool, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}}); err != nil {
+		return fmt.Errorf("노드 인증서가 CA로 검증되지 않습니다: %v", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.cert, a.pool = &cert, pool
+	return nil
+}
+
+func newCSR(nodeID string) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, fmt.Errorf("노드 키 생성 실패: %v", err)
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{Subject: pkix.Name{CommonName: nodeID}}, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("CSR 생성 실패: %v", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}), nil
+}
+
+func post(ctx context.Context, client *http.Client, url string, body, out interface{}) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	payload, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		var failure struct {
+			Error string `json:"error"`
+		}
+		json.Unmarshal(payload, &failure)
+		return fmt.Errorf("%s: %s", resp.Status, failure.Error)
+	}
+	return json.Unmarshal(payload, out)
+}
@@ -0,0 +1,312 @@
+// Package nodeca 노드 에이전트와 컨트롤 플레인 사이 mTLS에 쓰는 X.509 인증 기관
+//
+// 노드 인증서의 ID는 SPIFFE 형식 URI SAN(spiffe://<trust-domain>/node/<node-id>) 하나로 노드 ID에 묶인다.
+// 컨트롤 플레인의 에이전트 리스너 인증서도 같은 CA가 발급하므로 에이전트는 CA 인증서 하나만 신뢰하면 된다.
+package nodeca
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTrustDomain = "sandman.local"
+	DefaultCertTTL     = 24 * time.Hour
+
+	caTTL     = 10 * 365 * 24 * time.Hour
+	serverTTL = 7 * 24 * time.Hour
+	// clockSkew 노드와 컨트롤 플레인 시계 차이를 고려해 유효 시작 시각을 앞당기는 여유
+	clockSkew = 5 * time.Minute
+)
+
+var nodeIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,62}$`)
+
+// CA 노드 인증서와 에이전트 리스너 인증서에 서명하는 인증 기관
+type CA struct {
+	key         crypto.Signer
+	cert        *x509.Certificate
+	certPEM     []byte
+	pool        *x509.CertPool
+	trustDomain string
+
+	mu     sync.Mutex
+	server *tls.Certificate // 에이전트 리스너 인증서 (만료 전에 다시 발급)
+	hosts  []string
+}
+
+// LoadOrCreate dir의 ca.key/ca.crt를 읽고, 없으면 새 ECDSA P-256 CA를 만들어 저장
+func LoadOrCreate(dir, trustDomain string) (*CA, error) {
+	if trustDomain == "" {
+		trustDomain = DefaultTrustDomain
+	}
+	keyPath, certPath := filepath.Join(dir, "ca.key"), filepath.Join(dir, "ca.crt")
+
+	keyPEM, err := os.ReadFile(keyPath)
+	if os.IsNotExist(err) {
+		if err := generate(dir, keyPath, certPath, trustDomain); err != nil {
+			return nil, err
+		}
+		log.Printf("🔏 새 노드 CA 생성: %s", dir)
+		keyPEM, err = os.ReadFile(keyPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("노드 CA 키 읽기 실패: %v", err)
+	}
+	certPEM, err := os.ReadFile(certPath)
+	if err != nil {
+		return nil, fmt.Errorf("노드 CA 인증서 읽기 실패: %v", err)
+	}
+
+	key, err := parseKey(keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("노드 CA 키 파싱 실패: %v", err)
+	}
+	cert, err := ParseCertificate(certPEM)
+	if err != nil {
+		return nil, fmt.Errorf("노드 CA 인증서 파싱 실패: %v", err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	ca := &CA{key: key, cert: cert, certPEM: certPEM, pool: pool, trustDomain: trustDomain}
+	log.Printf("🔏 노드 CA 로드 완료: %s (trust domain: %s, 만료: %s)", ca.Fingerprint(), trustDomain, cert.NotAfter.Format("2006-01-02"))
+	return ca, nil
+}
+
+func generate(dir, keyPath, certPath, trustDomain string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return fmt.Errorf("노드 CA 키 생성 실패: %v", err)
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          randomSerial(),
+		Subject:               pkix.Name{CommonName: "sandman node CA", Organization: []string{trustDomain}},
+		URIs:                  []*url.URL{{Scheme: "spiffe", Host: trustDomain}},
+		NotBefore:             now.Add(-clockSkew),
+		NotAfter:              now.Add(caTTL),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return fmt.Errorf("노드 CA 인증서 생성 실패: %v", err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("노드 CA 키 인코딩 실패: %v", err)
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("노드 CA 디렉토리 생성 실패: %v", err)
+	}
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0600); err != nil {
+		return fmt.Errorf("노드 CA 키 저장 실패: %v", err)
+	}
+	if err := os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		return fmt.Errorf("노드 CA 인증서 저장 실패: %v", err)
+	}
+	return nil
+}
+
+func parseKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("PEM 블록이 없습니다")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("서명할 수 없는 키 형식입니다")
+	}
+	return signer, nil
+}
+
+// ParseCertificate PEM 인증서 하나를 파싱
+func ParseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("CERTIFICATE PEM 블록이 없습니다")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func randomSerial() *big.Int {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		panic(err)
+	}
+	return serial
+}
+
+// Fingerprint 인증서 DER의 SHA-256 (16진수)
+func Fingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// Fingerprint CA 인증서 지문 (에이전트가 부트스트랩할 때 확인)
+func (ca *CA) Fingerprint() string {
+	return Fingerprint(ca.cert)
+}
+
+func (ca *CA) TrustDomain() string {
+	return ca.trustDomain
+}
+
+// CertificatePEM 에이전트가 신뢰할 CA 인증서
+func (ca *CA) CertificatePEM() string {
+	return string(ca.certPEM)
+}
+
+// ValidNodeID 인증서 ID에 넣을 수 있는 노드 ID인지 (SPIFFE 경로 구성 요소와 호스트 이름에 맞는 문자만)
+func ValidNodeID(nodeID string) bool {
+	return nodeIDPattern.MatchString(nodeID)
+}
+
+// SPIFFEID 노드 인증서의 URI SAN
+func SPIFFEID(trustDomain, nodeID string) string {
+	return "spiffe://" + trustDomain + "/node/" + nodeID
+}
+
+// SignNode CSR의 공개키로 노드 ID에 묶인 클라이언트 인증서를 발급 (CSR의 주체/SAN은 무시)
+func (ca *CA) SignNode(nodeID string, csrPEM []byte, ttl time.Duration) (*x509.Certificate, string, error) {
+	if !ValidNodeID(nodeID) {
+		return nil, "", fmt.Errorf("인증서에 쓸 수 없는 노드 ID입니다: %q", nodeID)
+	}
+	block, _ := pem.Decode(csrPEM)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, "", fmt.Errorf("CERTIFICATE REQUEST PEM 블록이 없습니다")
+	}
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, "", fmt.Errorf("CSR 파싱 실패: %v", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, "", fmt.Errorf("CSR 서명이 올바르지 않습니다: %v", err)
+	}
+
+	id, _ := url.Parse(SPIFFEID(ca.trustDomain, nodeID))
+	now := time.Now()
+	notAfter := now.Add(ttl)
+	if notAfter.After(ca.cert.NotAfter) {
+		notAfter = ca.cert.NotAfter
+	}
+	template := &x509.Certificate{
+		SerialNumber: randomSerial(),
+		Subject:      pkix.Name{CommonName: nodeID, Organization: []string{ca.trustDomain}},
+		URIs:         []*url.URL{id},
+		NotBefore:    now.Add(-clockSkew),
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, csr.PublicKey, ca.key)
+	if err != nil {
+		return nil, "", fmt.Errorf("노드 인증서 서명 실패: %v", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, "", err
+	}
+	return cert, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})), nil
+}
+
+// NodeID 검증된 클라이언트 인증서의 SPIFFE ID에서 노드 ID를 꺼냄 (다른 trust domain이나 형식은 거부)
+func (ca *CA) NodeID(cert *x509.Certificate) (string, error) {
+	if len(cert.URIs) != 1 {
+		return "", fmt.Errorf("인증서에 SPIFFE ID가 하나여야 합니다 (%d개)", len(cert.URIs))
+	}
+	id := cert.URIs[0]
+	if id.Scheme != "spiffe" || id.Host != ca.trustDomain {
+		return "", fmt.Errorf("다른 trust domain의 인증서입니다: %s", id)
+	}
+	nodeID, ok := strings.CutPrefix(id.Path, "/node/")
+	if !ok || !ValidNodeID(nodeID) {
+		return "", fmt.Errorf("노드 인증서가 아닙니다: %s", id)
+	}
+	return nodeID, nil
+}
+
+// ServerTLSConfig 에이전트 리스너용 TLS 설정 (CA가 발급한 클라이언트 인증서를 요구)
+// 리스너 인증서는 hosts(DNS 이름 또는 IP)로 발급하고 유효 기간의 2/3가 지나면 다음 핸드셰이크에서 다시 발급
+func (ca *CA) ServerTLSConfig(hosts []string) *tls.Config {
+	ca.mu.Lock()
+	ca.hosts = hosts
+	ca.mu.Unlock()
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		ClientAuth:     tls.RequireAndVerifyClientCert,
+		ClientCAs:      ca.pool,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) { return ca.serverCertificate() },
+	}
+}
+
+func (ca *CA) serverCertificate() (*tls.Certificate, error) {
+	ca.mu.Lock()
+	defer ca.mu.Unlock()
+	if ca.server != nil && !renewDue(ca.server.Leaf, time.Now()) {
+		return ca.server, nil
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: randomSerial(),
+		Subject:      pkix.Name{CommonName: "sandman control plane", Organization: []string{ca.trustDomain}},
+		NotBefore:    now.Add(-clockSkew),
+		NotAfter:     now.Add(serverTTL),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range ca.hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		return nil, fmt.Errorf("에이전트 리스너 인증서 발급 실패: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	ca.server = &tls.Certificate{Certificate: [][]byte{der, ca.cert.Raw}, PrivateKey: key, Leaf: leaf}
+	log.Printf("🔏 에이전트 리스너 인증서 발급 (%v, 만료: %s)", ca.hosts, leaf.NotAfter.Format(time.RFC3339))
+	return ca.server, nil
+}
+
+// renewDue 유효 기간의 2/3가 지났는지
+func renewDue(cert *x509.Certificate, now time.Time) bool {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return now.After(cert.NotBefore.Add(lifetime * 2 / 3))
+}
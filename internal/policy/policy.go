@@ -0,0 +1,179 @@
+package policy
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/cel-go/cel"
+)
+
+// Rule 변경 요청마다 평가하는 사이트별 규칙 (CEL 표현식)
+// when이 참인 요청은 require도 참이어야 통과하고, 아니면 message와 함께 거부
+type Rule struct {
+	Name    string `json:"name"`
+	When    string `json:"when,omitempty"` // 비어 있으면 모든 변경 요청에 적용
+	Require string `json:"require"`
+	Message string `json:"message,omitempty"` // 거부 시 응답 메시지 (비어 있으면 규칙 이름)
+}
+
+// Input 규칙에서 쓰는 변수
+type Input struct {
+	Actor   map[string]interface{} // id, admin, scopes, project_id (요청에 쓴 API 키)
+	Action  string                 // "POST /sessions" (메서드와 경로 패턴)
+	Request map[string]interface{} // path, params, query, body (JSON 본문)
+	User    map[string]interface{} // id, teams, team_ids (요청 대상 사용자)
+	Usage   map[string]interface{} // 대상 사용자의 쿼터 사용량 (GET /me/quota와 같은 구조)
+	Now     time.Time              // hour, weekday는 배포 시간대 기준
+}
+
+// Denial 규칙에 걸려 거부된 이유
+type Denial struct {
+	Rule    string
+	Message string
+}
+
+func (d *Denial) Error() string {
+	return d.Message
+}
+
+type compiledRule struct {
+	Rule
+	when    cel.Program // nil이면 항상 적용
+	require cel.Program
+}
+
+// Engine 컴파일해 둔 규칙 목록 (설정을 다시 읽을 때 새로 만듦)
+type Engine struct {
+	rules []compiledRule
+}
+
+var env *cel.Env
+
+func init() {
+	var err error
+	env, err = cel.NewEnv(
+		cel.Variable("actor", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("action", cel.StringType),
+		cel.Variable("request", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("user", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("usage", cel.MapType(cel.StringType, cel.DynType)),
+		cel.Variable("now", cel.TimestampType),
+		cel.Variable("hour", cel.IntType),
+		cel.Variable("weekday", cel.IntType),
+		// JSON 본문과 사용량의 숫자는 double이므로 int 리터럴과 비교할 수 있게 함
+		cel.CrossTypeNumericComparisons(true),
+	)
+	if err != nil {
+		panic(fmt.Sprintf("CEL 환경 생성 실패: %v", err))
+	}
+}
+
+// Compile 규칙을 검증하고 컴파일 (표현식 오류나 bool이 아닌 결과 타입은 설정 오류)
+func Compile(rules []Rule) (*Engine, error) {
+	engine := &Engine{}
+	names := make(map[string]bool)
+	for i, rule := range rules {
+		if rule.Name == "" {
+			return nil, fmt.Errorf("[%d]: name이 필요합니다", i)
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("[%d]: 이름이 중복되었습니다: %s", i, rule.Name)
+		}
+		names[rule.Name] = true
+
+		compiled := compiledRule{Rule: rule}
+		var err error
+		if strings.TrimSpace(rule.When) != "" {
+			if compiled.when, err = compile(rule.When); err != nil {
+				return nil, fmt.Errorf("[%s].when: %v", rule.Name, err)
+			}
+		}
+		if strings.TrimSpace(rule.Require) == "" {
+			return nil, fmt.Errorf("[%s]: require가 필요합니다", rule.Name)
+		}
+		if compiled.require, err = compile(rule.Require); err != nil {
+			return nil, fmt.Errorf("[%s].require: %v", rule.Name, err)
+		}
+		engine.rules = append(engine.rules, compiled)
+	}
+	return engine, nil
+}
+
+func compile(expr string) (cel.Program, error) {
+	ast, issues := env.Compile(expr)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType && ast.OutputType() != cel.DynType {
+		return nil, fmt.Errorf("결과가 bool이어야 합니다 (%s)", ast.OutputType())
+	}
+	return env.Program(ast)
+}
+
+// Empty 평가할 규칙이 없는지 (입력 수집을 건너뛰는 데 사용)
+func (e *Engine) Empty() bool {
+	return e == nil || len(e.rules) == 0
+}
+
+// Evaluate 규칙을 순서대로 평가해 처음 걸린 규칙의 Denial을 반환 (모두 통과하면 nil)
+// 평가 오류(없는 필드 등)는 안전하게 거부로 처리
+func (e *Engine) Evaluate(in Input, loc *time.Location) *Denial {
+	if e.Empty() {
+		return nil
+	}
+	local := in.Now.In(loc)
+	vars := map[string]interface{}{
+		"actor":   orEmpty(in.Actor),
+		"action":  in.Action,
+		"request": orEmpty(in.Request),
+		"user":    orEmpty(in.User),
+		"usage":   orEmpty(in.Usage),
+		"now":     in.Now,
+		"hour":    local.Hour(),
+		"weekday": int(local.Weekday()),
+	}
+
+	for _, rule := range e.rules {
+		if rule.when != nil {
+			applies, err := eval(rule.when, vars)
+			if err != nil {
+				return &Denial{Rule: rule.Name, Message: fmt.Sprintf("정책 %s를 평가할 수 없습니다: %v", rule.Name, err)}
+			}
+			if !applies {
+				continue
+			}
+		}
+		allowed, err := eval(rule.require, vars)
+		if err != nil {
+			return &Denial{Rule: rule.Name, Message: fmt.Sprintf("정책 %s를 평가할 수 없습니다: %v", rule.Name, err)}
+		}
+		if !allowed {
+			message := rule.Message
+			if message == "" {
+				message = "정책 " + rule.Name + "에 의해 거부되었습니다"
+			}
+			return &Denial{Rule: rule.Name, Message: message}
+		}
+	}
+	return nil
+}
+
+func eval(program cel.Program, vars map[string]interface{}) (bool, error) {
+	out, _, err := program.Eval(vars)
+	if err != nil {
+		return false, err
+	}
+	result, ok := out.Value().(bool)
+	if !ok {
+		return false, fmt.Errorf("결과가 bool이 아닙니다: %v", out.Value())
+	}
+	return result, nil
+}
+
+func orEmpty(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return map[string]interface{}{}
+	}
+	return m
+}
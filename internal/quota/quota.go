@@ -0,0 +1,195 @@
+// Package quota 관리자가 사용자나 팀에 거는 동시 세션, GPU 슬라이스, TTL 한도
+//
+// 한도는 저장소에 주체("user:<id>", "team:<id>")별로 기록한다. 새 세션은 사용자 한도와
+// 사용자가 속한 모든 팀의 한도를 함께 만족해야 하며, 사용량은 모든 노드의 세션으로 센다.
+package quota
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// 한도 주체 종류
+const (
+	KindUser = "user"
+	KindTeam = "team"
+)
+
+// 거부 사유 (sandman_quota_rejections_total의 limit 레이블)
+const (
+	LimitSessions  = "sessions"
+	LimitGPUSlices = "gpu_slices"
+	LimitTTL       = "ttl"
+)
+
+var rejections = metrics.NewCounter("sandman_quota_rejections_total", "한도 때문에 거부한 세션 생성 (limit: sessions, gpu_slices, ttl)", "kind", "limit")
+
+// Usage 주체 하나의 한도와 현재 사용량
+type Usage struct {
+	store.Quota
+	Sessions  int `json:"sessions"`
+	GPUSlices int `json:"gpu_slices"`
+}
+
+// Demand 새 세션이 쓰려는 양
+type Demand struct {
+	UserID     string
+	GPUSlices  int // CPU 전용 세션은 0
+	TTLMinutes int
+}
+
+// SlicesFunc 프로파일 이름의 컴퓨트 슬라이스 수 (모르는 프로파일은 0)
+type SlicesFunc func(profile string) int
+
+// Manager 저장소에 기록한 한도를 관리하고 새 세션에 적용
+type Manager struct {
+	store store.Store
+}
+
+func NewManager(st store.Store) *Manager {
+	return &Manager{store: st}
+}
+
+// UserSubject 사용자 한도 주체
+func UserSubject(userID string) string {
+	return KindUser + ":" + userID
+}
+
+// TeamSubject 팀 한도 주체
+func TeamSubject(teamID string) string {
+	return KindTeam + ":" + teamID
+}
+
+// ParseSubject "user:<id>" 또는 "team:<id>"를 종류와 ID로 나눔
+func ParseSubject(subject string) (string, string, error) {
+	kind, id, _ := strings.Cut(subject, ":")
+	if (kind != KindUser && kind != KindTeam) || id == "" {
+		return "", "", errs.New(errs.ErrInvalid, "한도 대상은 user:<id> 또는 team:<id> 형식이어야 합니다: %q", subject)
+	}
+	return kind, id, nil
+}
+
+// Set 주체의 한도를 추가하거나 교체 (팀은 이미 있어야 함)
+func (m *Manager) Set(q store.Quota) (*store.Quota, error) {
+	kind, id, err := ParseSubject(q.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if q.MaxSessions < 0 || q.MaxGPUSlices < 0 || q.MaxTTLMinutes < 0 {
+		return nil, errs.New(errs.ErrInvalid, "한도는 0 이상이어야 합니다 (0은 제한 없음)")
+	}
+	if kind == KindTeam {
+		if _, err := m.store.GetTeam(id); err != nil {
+			return nil, fmt.Errorf("팀 %s를 찾을 수 없습니다: %w", id, err)
+		}
+	}
+	q.UpdatedAt = time.Now()
+	if err := m.store.SaveQuota(&q); err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+func (m *Manager) Get(subject string) (*store.Quota, error) {
+	if _, _, err := ParseSubject(subject); err != nil {
+		return nil, err
+	}
+	q, err := m.store.GetQuota(subject)
+	if err != nil {
+		return nil, fmt.Errorf("%s의 한도가 없습니다: %w", subject, err)
+	}
+	return q, nil
+}
+
+func (m *Manager) List() ([]*store.Quota, error) {
+	return m.store.ListQuotas()
+}
+
+func (m *Manager) Delete(subject string) error {
+	if _, _, err := ParseSubject(subject); err != nil {
+		return err
+	}
+	if err := m.store.DeleteQuota(subject); err != nil {
+		return fmt.Errorf("%s의 한도가 없습니다: %w", subject, err)
+	}
+	return nil
+}
+
+// Usage 사용자에게 적용되는 한도(사용자 자신과 속한 팀)와 각각의 현재 사용량
+func (m *Manager) Usage(userID string, slicesOf SlicesFunc) ([]*Usage, error) {
+	members := map[string][]string{UserSubject(userID): {userID}}
+	var quotas []*store.Quota
+	if q, err := m.store.GetQuota(UserSubject(userID)); err == nil {
+		quotas = append(quotas, q)
+	}
+	teams, err := m.store.ListUserTeams(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, team := range teams {
+		q, err := m.store.GetQuota(TeamSubject(team.TeamID))
+		if err != nil {
+			continue
+		}
+		list, err := m.store.ListTeamMembers(team.TeamID)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range list {
+			members[q.Subject] = append(members[q.Subject], member.UserID)
+		}
+		quotas = append(quotas, q)
+	}
+
+	result := make([]*Usage, 0, len(quotas))
+	if len(quotas) == 0 {
+		return result, nil
+	}
+	sessions, err := m.store.ListAllSessions()
+	if err != nil {
+		return nil, err
+	}
+	for _, q := range quotas {
+		usage := &Usage{Quota: *q}
+		for _, session := range sessions {
+			if !slices.Contains(members[q.Subject], session.UserID) {
+				continue
+			}
+			usage.Sessions++
+			if session.GPUUUID != "" {
+				usage.GPUSlices += slicesOf(session.MIGProfile)
+			}
+		}
+		result = append(result, usage)
+	}
+	return result, nil
+}
+
+// Check 새 세션을 더해도 모든 한도 안에 드는지 (넘으면 ErrForbidden)
+func (m *Manager) Check(d Demand, slicesOf SlicesFunc) error {
+	usages, err := m.Usage(d.UserID, slicesOf)
+	if err != nil {
+		return fmt.Errorf("한도 확인 실패: %v", err)
+	}
+	for _, u := range usages {
+		kind, _, _ := ParseSubject(u.Subject)
+		switch {
+		case u.MaxSessions > 0 && u.Sessions+1 > u.MaxSessions:
+			rejections.Inc(kind, LimitSessions)
+			return errs.New(errs.ErrForbidden, "%s의 동시 세션 한도(%d개)를 이미 사용 중입니다", u.Subject, u.MaxSessions)
+		case u.MaxGPUSlices > 0 && u.GPUSlices+d.GPUSlices > u.MaxGPUSlices:
+			rejections.Inc(kind, LimitGPUSlices)
+			return errs.New(errs.ErrForbidden, "%s의 GPU 슬라이스 한도를 넘습니다 (사용 중 %d + 요청 %d > 한도 %d)", u.Subject, u.GPUSlices, d.GPUSlices, u.MaxGPUSlices)
+		case u.MaxTTLMinutes > 0 && d.TTLMinutes > u.MaxTTLMinutes:
+			rejections.Inc(kind, LimitTTL)
+			return errs.New(errs.ErrForbidden, "TTL %d분이 %s의 최대 허용값 %d분을 초과합니다", d.TTLMinutes, u.Subject, u.MaxTTLMinutes)
+		}
+	}
+	return nil
+}
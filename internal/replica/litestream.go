@@ -0,0 +1,217 @@
+package replica
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/command"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+)
+
+const (
+	// stopTimeout SIGTERM 후 litestream이 마지막 WAL을 올리고 끝나기를 기다리는 시간
+	stopTimeout = 30 * time.Second
+	// maxBackoff 하위 프로세스가 계속 죽을 때 재시작 간격 상한
+	maxBackoff = time.Minute
+	// stableRun 이만큼 실행된 뒤 죽으면 재시작 간격을 처음부터 다시 셈
+	stableRun = time.Minute
+	// restoreTimeout 복제본에서 DB를 내려받는 최대 시간
+	restoreTimeout = 10 * time.Minute
+)
+
+var (
+	replicationUp       = metrics.NewGauge("sandman_store_replication_up", "litestream 복제 프로세스 실행 여부 (1: 실행 중)")
+	replicationRestarts = metrics.NewCounter("sandman_store_replication_restarts_total", "비정상 종료 후 litestream 복제 프로세스 재시작 횟수")
+	storeRestores       = metrics.NewCounter("sandman_store_restores_total", "시작 시 복제본에서 DB 복원 시도 수", "outcome")
+)
+
+// Litestream SQLite WAL을 litestream 하위 프로세스로 S3(또는 호환 스토리지)에 스트리밍 복제
+// 단일 노드 SQLite 배포에서 디스크를 잃어도 세션 상태를 복제본에서 되살릴 수 있게 함
+type Litestream struct {
+	binary  string
+	dbPath  string
+	replica string
+
+	mu       sync.Mutex
+	cmd      *exec.Cmd
+	stopChan chan struct{}
+	done     chan struct{}
+	running  bool
+}
+
+// NewLitestream replicaURL은 litestream 복제본 URL (예: s3://bucket/sandman/sessions.db)
+// S3 자격 증명은 AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY 등 환경 변수를 그대로 넘겨 사용
+func NewLitestream(binary, dbPath, replicaURL string) (*Litestream, error) {
+	if !strings.Contains(replicaURL, "://") {
+		return nil, fmt.Errorf("복제본 URL이 잘못되었습니다: %q (예: s3://bucket/path)", replicaURL)
+	}
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return nil, fmt.Errorf("litestream 실행 파일을 찾을 수 없습니다 (%s): %v", binary, err)
+	}
+	return &Litestream{
+		binary:   path,
+		dbPath:   dbPath,
+		replica:  replicaURL,
+		stopChan: make(chan struct{}),
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// Restore 주 DB 파일이 없을 때만 복제본에서 복원 (복제본도 없으면 빈 DB로 시작)
+// 저장소를 열기 전에 호출해야 함. 복원했으면 true
+func (l *Litestream) Restore() (bool, error) {
+	if _, err := os.Stat(l.dbPath); err == nil {
+		return false, nil
+	} else if !os.IsNotExist(err) {
+		return false, err
+	}
+
+	log.Printf("🛟 DB 파일이 없어 복제본에서 복원합니다: %s ← %s", l.dbPath, l.replica)
+	// 클라우드 저장소 자격 증명을 환경 변수로 읽으므로 오케스트레이터 환경을 물려줌
+	result, err := command.Run(context.Background(), command.Options{Timeout: restoreTimeout, InheritEnv: true, Combined: true},
+		l.binary, "restore", "-if-replica-exists", "-o", l.dbPath, l.replica)
+	for _, line := range strings.Split(strings.TrimSpace(string(result.Stdout)), "\n") {
+		if line != "" {
+			log.Printf("[litestream] %s", line)
+		}
+	}
+	if err != nil {
+		storeRestores.Inc("failed")
+		return false, fmt.Errorf("복제본에서 DB 복원 실패: %v", err)
+	}
+
+	if _, err := os.Stat(l.dbPath); err != nil {
+		storeRestores.Inc("no_replica")
+		log.Printf("ℹ️ 복제본이 없어 새 DB로 시작합니다: %s", l.replica)
+		return false, nil
+	}
+	storeRestores.Inc("restored")
+	log.Printf("✅ 복제본에서 DB 복원 완료: %s", l.dbPath)
+	return true, nil
+}
+
+// Start litestream replicate를 실행하고 비정상 종료 시 지수 백오프로 재시작
+func (l *Litestream) Start() {
+	if l.running {
+		return
+	}
+
+	l.running = true
+	go l.supervise()
+	log.Printf("🛰️ DB 복제 시작됨: %s → %s", l.dbPath, l.replica)
+}
+
+// Stop SIGTERM으로 litestream을 멈춤 (종료 전에 남은 WAL을 복제본에 올림)
+// 마지막 쓰기까지 복제되도록 저장소를 닫은 뒤 호출
+func (l *Litestream) Stop() {
+	if !l.running {
+		return
+	}
+
+	l.running = false
+	close(l.stopChan)
+	l.mu.Lock()
+	if l.cmd != nil && l.cmd.Process != nil {
+		l.cmd.Process.Signal(syscall.SIGTERM)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-l.done:
+	case <-time.After(stopTimeout):
+		l.mu.Lock()
+		if l.cmd != nil && l.cmd.Process != nil {
+			l.cmd.Process.Kill()
+		}
+		l.mu.Unlock()
+		<-l.done
+		log.Printf("⚠️ litestream이 %v 안에 끝나지 않아 강제 종료했습니다", stopTimeout)
+	}
+	log.Println("🛰️ DB 복제 중지됨")
+}
+
+func (l *Litestream) supervise() {
+	defer close(l.done)
+
+	backoff := time.Second
+	for {
+		started := time.Now()
+		err := l.run()
+
+		select {
+		case <-l.stopChan:
+			return
+		default:
+		}
+
+		if time.Since(started) >= stableRun {
+			backoff = time.Second
+		}
+		replicationRestarts.Inc()
+		log.Printf("⚠️ litestream 복제 프로세스 종료 (%v), %v 후 재시작", err, backoff)
+
+		select {
+		case <-l.stopChan:
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// run litestream replicate 한 번 실행하고 종료될 때까지 대기
+func (l *Litestream) run() error {
+	cmd := exec.Command(l.binary, "replicate", l.dbPath, l.replica)
+	out := logOutput(cmd)
+	defer out.Close()
+
+	l.mu.Lock()
+	select {
+	case <-l.stopChan:
+		l.mu.Unlock()
+		return nil
+	default:
+	}
+	if err := cmd.Start(); err != nil {
+		l.mu.Unlock()
+		return err
+	}
+	l.cmd = cmd
+	l.mu.Unlock()
+
+	replicationUp.Set(1)
+	err := cmd.Wait()
+	replicationUp.Set(0)
+
+	l.mu.Lock()
+	l.cmd = nil
+	l.mu.Unlock()
+	if err == nil {
+		return fmt.Errorf("예기치 않게 정상 종료됨")
+	}
+	return err
+}
+
+// logOutput litestream 출력을 줄 단위로 오케스트레이터 로그에 남김 (프로세스가 끝나면 Close)
+func logOutput(cmd *exec.Cmd) io.Closer {
+	r, w := io.Pipe()
+	cmd.Stdout = w
+	cmd.Stderr = w
+	go func() {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			log.Printf("[litestream] %s", scanner.Text())
+		}
+	}()
+	return w
+}
@@ -0,0 +1,199 @@
+package report
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Destination 정기 보고서를 올릴 위치
+type Destination interface {
+	Put(name string, data []byte) error
+	String() string
+}
+
+// ParseDestination "/var/reports", "file:///var/reports" 또는 "s3://bucket/prefix"
+// S3 자격 증명과 리전은 AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN, AWS_REGION에서 읽고,
+// AWS_ENDPOINT_URL이 있으면 그 주소(MinIO 등 S3 호환 스토리지)를 path-style로 사용
+func ParseDestination(uri string) (Destination, error) {
+	switch {
+	case strings.HasPrefix(uri, "s3://"):
+		rest := strings.TrimPrefix(uri, "s3://")
+		bucket, prefix, _ := strings.Cut(rest, "/")
+		if bucket == "" {
+			return nil, fmt.Errorf("S3 버킷이 비어 있습니다: %q", uri)
+		}
+		return newS3Destination(bucket, strings.Trim(prefix, "/"))
+	case strings.HasPrefix(uri, "file://"):
+		return &LocalDestination{Dir: strings.TrimPrefix(uri, "file://")}, nil
+	case strings.Contains(uri, "://"):
+		return nil, fmt.Errorf("지원하지 않는 보고서 대상입니다: %q (로컬 경로 또는 s3://)", uri)
+	case uri == "":
+		return nil, fmt.Errorf("보고서 대상이 비어 있습니다")
+	default:
+		return &LocalDestination{Dir: uri}, nil
+	}
+}
+
+// LocalDestination 로컬 디렉토리 (임시 파일에 쓴 뒤 이름을 바꿔 반쯤 쓰인 파일이 보이지 않게 함)
+type LocalDestination struct {
+	Dir string
+}
+
+func (d *LocalDestination) Put(name string, data []byte) error {
+	if err := os.MkdirAll(d.Dir, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(d.Dir, "."+name+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(d.Dir, name))
+}
+
+func (d *LocalDestination) String() string {
+	return d.Dir
+}
+
+// S3Destination S3 PutObject (SigV4 서명, SDK 없이 net/http 사용)
+type S3Destination struct {
+	Bucket   string
+	Prefix   string
+	Region   string
+	Endpoint string // 비어 있으면 https://<bucket>.s3.<region>.amazonaws.com
+
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	client       *http.Client
+}
+
+func newS3Destination(bucket, prefix string) (*S3Destination, error) {
+	d := &S3Destination{
+		Bucket:       bucket,
+		Prefix:       prefix,
+		Region:       os.Getenv("AWS_REGION"),
+		Endpoint:     strings.TrimRight(os.Getenv("AWS_ENDPOINT_URL"), "/"),
+		accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		client:       &http.Client{Timeout: 5 * time.Minute},
+	}
+	if d.Region == "" {
+		d.Region = "us-east-1"
+	}
+	if d.accessKey == "" || d.secretKey == "" {
+		return nil, fmt.Errorf("S3 업로드에 AWS_ACCESS_KEY_ID와 AWS_SECRET_ACCESS_KEY가 필요합니다")
+	}
+	return d, nil
+}
+
+func (d *S3Destination) String() string {
+	if d.Prefix == "" {
+		return "s3://" + d.Bucket
+	}
+	return "s3://" + d.Bucket + "/" + d.Prefix
+}
+
+func (d *S3Destination) objectURL(key string) string {
+	escaped := (&url.URL{Path: "/" + key}).EscapedPath()
+	if d.Endpoint != "" {
+		return d.Endpoint + "/" + d.Bucket + escaped
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com%s", d.Bucket, d.Region, escaped)
+}
+
+func (d *S3Destination) Put(name string, data []byte) error {
+	key := name
+	if d.Prefix != "" {
+		key = d.Prefix + "/" + name
+	}
+
+	req, err := http.NewRequest(http.MethodPut, d.objectURL(key), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.ContentLength = int64(len(data))
+	d.sign(req, data, time.Now().UTC())
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("S3 업로드 실패: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("S3 업로드 실패 (%s): %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// sign AWS Signature Version 4 헤더 서명
+func (d *S3Destination) sign(req *http.Request, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("Host", req.URL.Host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" +
+		"x-amz-content-sha256:" + payloadHash + "\n" +
+		"x-amz-date:" + amzDate + "\n"
+	if d.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", d.sessionToken)
+		signedHeaders += ";x-amz-security-token"
+		canonicalHeaders += "x-amz-security-token:" + d.sessionToken + "\n"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		"",
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + d.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+d.secretKey), date)
+	key = hmacSHA256(key, d.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		d.accessKey, scope, signedHeaders, signature))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
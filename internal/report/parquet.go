@@ -0,0 +1,263 @@
+package report
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+)
+
+// 외부 의존성 없이 BI 도구가 읽을 수 있는 최소한의 Parquet 파일을 기록
+// - 행 그룹 1개, 컬럼마다 PLAIN 인코딩 데이터 페이지 1개, 압축 없음
+// - 모든 컬럼은 REQUIRED (정의/반복 레벨 없음)
+// 메타데이터는 Thrift compact protocol로 직렬화 (parquet-format의 필드 번호를 그대로 사용)
+
+const parquetMagic = "PAR1"
+
+// parquet-format 열거형 값
+const (
+	parquetInt64     = 2
+	parquetByteArray = 6
+
+	parquetRequired = 0
+
+	convertedUTF8            = 0
+	convertedTimestampMillis = 9
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	codecUncompressed = 0
+
+	pageTypeData = 0
+)
+
+func writeParquet(w io.Writer, cols []column, rows [][]interface{}) error {
+	var file bytes.Buffer
+	file.WriteString(parquetMagic)
+
+	var chunks []parquetChunk
+	if len(rows) > 0 {
+		for i, col := range cols {
+			values := encodePlain(col, i, rows)
+
+			header := &thriftWriter{}
+			header.i32Field(1, pageTypeData)
+			header.i32Field(2, int32(len(values)))
+			header.i32Field(3, int32(len(values)))
+			header.structField(5)
+			header.i32Field(1, int32(len(rows)))
+			header.i32Field(2, encodingPlain)
+			header.i32Field(3, encodingRLE)
+			header.i32Field(4, encodingRLE)
+			header.stop()
+			header.endStruct()
+			header.stop()
+
+			offset := int64(file.Len())
+			file.Write(header.Bytes())
+			file.Write(values)
+			chunks = append(chunks, parquetChunk{
+				column: col,
+				offset: offset,
+				size:   int64(header.Len() + len(values)),
+			})
+		}
+	}
+
+	footer := fileMetadata(cols, chunks, int64(len(rows)))
+	file.Write(footer)
+	binary.Write(&file, binary.LittleEndian, uint32(len(footer)))
+	file.WriteString(parquetMagic)
+
+	_, err := w.Write(file.Bytes())
+	return err
+}
+
+type parquetChunk struct {
+	column column
+	offset int64
+	size   int64
+}
+
+func physicalType(col column) int32 {
+	if col.kind == kindString {
+		return parquetByteArray
+	}
+	return parquetInt64
+}
+
+// encodePlain 한 컬럼의 값을 PLAIN 인코딩 (INT64는 8바이트 LE, BYTE_ARRAY는 4바이트 길이 + 내용)
+func encodePlain(col column, index int, rows [][]interface{}) []byte {
+	var buf bytes.Buffer
+	for _, r := range rows {
+		if col.kind == kindString {
+			s := r[index].(string)
+			binary.Write(&buf, binary.LittleEndian, uint32(len(s)))
+			buf.WriteString(s)
+		} else {
+			binary.Write(&buf, binary.LittleEndian, r[index].(int64))
+		}
+	}
+	return buf.Bytes()
+}
+
+// fileMetadata FileMetaData 직렬화
+func fileMetadata(cols []column, chunks []parquetChunk, numRows int64) []byte {
+	t := &thriftWriter{}
+	t.i32Field(1, 1) // version
+
+	// schema: 루트 요소 다음에 컬럼들
+	t.listField(2, thriftStruct, len(cols)+1)
+	t.beginStruct()
+	t.stringField(4, "schema")
+	t.i32Field(5, int32(len(cols)))
+	t.stop()
+	t.endStruct()
+	for _, col := range cols {
+		t.beginStruct()
+		t.i32Field(1, physicalType(col))
+		t.i32Field(3, parquetRequired)
+		t.stringField(4, col.name)
+		switch col.kind {
+		case kindString:
+			t.i32Field(6, convertedUTF8)
+		case kindTimestamp:
+			t.i32Field(6, convertedTimestampMillis)
+		}
+		t.stop()
+		t.endStruct()
+	}
+
+	t.i64Field(3, numRows)
+
+	// row_groups: 행이 없으면 빈 목록
+	groups := 0
+	if len(chunks) > 0 {
+		groups = 1
+	}
+	t.listField(4, thriftStruct, groups)
+	if groups == 1 {
+		var total int64
+		for _, chunk := range chunks {
+			total += chunk.size
+		}
+
+		t.beginStruct()
+		t.listField(1, thriftStruct, len(chunks))
+		for _, chunk := range chunks {
+			t.beginStruct()
+			t.i64Field(2, chunk.offset) // file_offset
+			t.structField(3)            // meta_data
+			t.i32Field(1, physicalType(chunk.column))
+			t.listField(2, thriftI32, 2)
+			t.varint(zigzag32(encodingPlain))
+			t.varint(zigzag32(encodingRLE))
+			t.listField(3, thriftBinary, 1)
+			t.binary(chunk.column.name)
+			t.i32Field(4, codecUncompressed)
+			t.i64Field(5, numRows)
+			t.i64Field(6, chunk.size)
+			t.i64Field(7, chunk.size)
+			t.i64Field(9, chunk.offset) // data_page_offset
+			t.stop()
+			t.endStruct()
+			t.stop()
+			t.endStruct()
+		}
+		t.i64Field(2, total)
+		t.i64Field(3, numRows)
+		t.stop()
+		t.endStruct()
+	}
+
+	t.stringField(6, "sandman")
+	t.stop()
+	return t.Bytes()
+}
+
+// Thrift compact protocol 타입
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter Parquet 메타데이터에 필요한 만큼만 구현한 compact protocol 인코더
+type thriftWriter struct {
+	bytes.Buffer
+	lastField []int16 // 중첩 구조체별 마지막 필드 번호 (델타 인코딩용)
+	current   int16
+}
+
+func (t *thriftWriter) fieldHeader(id int16, typ byte) {
+	delta := id - t.current
+	if delta > 0 && delta <= 15 {
+		t.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.WriteByte(typ)
+		t.varint(uint64(zigzag32(int32(id))))
+	}
+	t.current = id
+}
+
+func (t *thriftWriter) varint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	t.Write(buf[:n])
+}
+
+func zigzag32(v int32) uint64 { return uint64(uint32((v << 1) ^ (v >> 31))) }
+func zigzag64(v int64) uint64 { return uint64((v << 1) ^ (v >> 63)) }
+
+func (t *thriftWriter) i32Field(id int16, v int32) {
+	t.fieldHeader(id, thriftI32)
+	t.varint(zigzag32(v))
+}
+
+func (t *thriftWriter) i64Field(id int16, v int64) {
+	t.fieldHeader(id, thriftI64)
+	t.varint(zigzag64(v))
+}
+
+func (t *thriftWriter) stringField(id int16, s string) {
+	t.fieldHeader(id, thriftBinary)
+	t.binary(s)
+}
+
+func (t *thriftWriter) binary(s string) {
+	t.varint(uint64(len(s)))
+	t.WriteString(s)
+}
+
+func (t *thriftWriter) listField(id int16, elemType byte, size int) {
+	t.fieldHeader(id, thriftList)
+	if size < 15 {
+		t.WriteByte(byte(size)<<4 | elemType)
+	} else {
+		t.WriteByte(0xf0 | elemType)
+		t.varint(uint64(size))
+	}
+}
+
+// structField 구조체 필드 시작 (필드를 쓴 뒤 stop, endStruct로 닫음)
+func (t *thriftWriter) structField(id int16) {
+	t.fieldHeader(id, thriftStruct)
+	t.beginStruct()
+}
+
+// beginStruct 필드 헤더 없이 구조체 시작 (리스트 원소용)
+func (t *thriftWriter) beginStruct() {
+	t.lastField = append(t.lastField, t.current)
+	t.current = 0
+}
+
+func (t *thriftWriter) endStruct() {
+	t.current = t.lastField[len(t.lastField)-1]
+	t.lastField = t.lastField[:len(t.lastField)-1]
+}
+
+func (t *thriftWriter) stop() {
+	t.WriteByte(0)
+}
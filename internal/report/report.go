@@ -0,0 +1,139 @@
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+const (
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+)
+
+// columns 내보내기 컬럼 (CSV 헤더와 Parquet 스키마가 같은 순서를 사용)
+var columns = []column{
+	{name: "session_id", kind: kindString},
+	{name: "user_id", kind: kindString},
+	{name: "project_id", kind: kindString},
+	{name: "node_id", kind: kindString},
+	{name: "mig_profile", kind: kindString},
+	{name: "started_at", kind: kindTimestamp},
+	{name: "ended_at", kind: kindTimestamp},
+	{name: "duration_seconds", kind: kindInt64},
+	{name: "rx_bytes", kind: kindInt64},
+	{name: "tx_bytes", kind: kindInt64},
+	{name: "end_reason", kind: kindString},
+	{name: "tier", kind: kindString},
+	{name: "billable_seconds", kind: kindInt64}, // duration_seconds × 등급 요율 (spot은 spot.rate_factor)
+	{name: "workspace_policy", kind: kindString},
+}
+
+type columnKind int
+
+const (
+	kindString columnKind = iota
+	kindInt64
+	kindTimestamp // UTC 밀리초
+)
+
+type column struct {
+	name string
+	kind columnKind
+}
+
+// row 종료된 세션 한 건을 컬럼 순서대로 (string 또는 int64)
+func row(usage *store.SessionUsage) []interface{} {
+	endedAt := usage.SampledAt
+	if usage.EndedAt != nil {
+		endedAt = *usage.EndedAt
+	}
+	duration := int64(endedAt.Sub(usage.StartedAt) / time.Second)
+	return []interface{}{
+		usage.SessionID,
+		usage.UserID,
+		usage.ProjectID,
+		usage.NodeID,
+		usage.MIGProfile,
+		usage.StartedAt.UnixMilli(),
+		endedAt.UnixMilli(),
+		duration,
+		int64(usage.RxBytes),
+		int64(usage.TxBytes),
+		usage.EndReason,
+		usage.Tier,
+		int64(math.Round(float64(duration) * usage.RateFactor)),
+		usage.WorkspacePolicy,
+	}
+}
+
+// ValidFormat 지원하는 내보내기 형식인지 확인
+func ValidFormat(format string) bool {
+	return format == FormatCSV || format == FormatParquet
+}
+
+func ContentType(format string) string {
+	if format == FormatParquet {
+		return "application/vnd.apache.parquet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// FileName 기간이 드러나는 보고서 파일 이름 (예: sessions-20240101T000000Z-20240102T000000Z.csv)
+func FileName(from, to time.Time, format string) string {
+	const layout = "20060102T150405Z"
+	return fmt.Sprintf("sessions-%s-%s.%s", from.UTC().Format(layout), to.UTC().Format(layout), format)
+}
+
+// Write 종료된 세션 기록을 format 형식으로 기록
+func Write(w io.Writer, format string, usages []*store.SessionUsage) error {
+	rows := make([][]interface{}, 0, len(usages))
+	for _, usage := range usages {
+		rows = append(rows, row(usage))
+	}
+
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, rows)
+	case FormatParquet:
+		return writeParquet(w, columns, rows)
+	default:
+		return fmt.Errorf("지원하지 않는 형식입니다: %q (csv, parquet)", format)
+	}
+}
+
+func writeCSV(w io.Writer, rows [][]interface{}) error {
+	cw := csv.NewWriter(w)
+
+	header := make([]string, len(columns))
+	for i, col := range columns {
+		header[i] = col.name
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	record := make([]string, len(columns))
+	for _, r := range rows {
+		for i, col := range columns {
+			switch col.kind {
+			case kindString:
+				record[i] = r[i].(string)
+			case kindInt64:
+				record[i] = strconv.FormatInt(r[i].(int64), 10)
+			case kindTimestamp:
+				record[i] = time.UnixMilli(r[i].(int64)).UTC().Format(time.RFC3339)
+			}
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,157 @@
+package session
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// 워크스페이스 안의 세션 상태 파일 (컨테이너의 sandman-status와 로그인 알림이 읽음)
+const (
+	agentStatusFile   = ".sandman/status.json"
+	agentWarningsFile = ".sandman/warnings" // 경고 문장만, 없으면 빈 파일
+)
+
+// 세션 경고 종류
+const (
+	WarningExpiry = "expiry" // 곧 만료됨
+	WarningIdle   = "idle"   // 활동이 없어 곧 잠김
+	WarningSpot   = "spot"   // spot 세션이라 회수될 수 있음
+)
+
+// AgentWarning 컨테이너 안에 보여 줄 경고 하나
+type AgentWarning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// AgentStatus 세션 컨테이너 안 /workspace/.sandman/status.json 내용
+type AgentStatus struct {
+	SessionID        string         `json:"session_id"`
+	UserID           string         `json:"user_id"`
+	Profile          string         `json:"profile"`
+	GPUName          string         `json:"gpu_name,omitempty"`
+	Tier             string         `json:"tier,omitempty"`
+	ExpiresAt        time.Time      `json:"expires_at"`
+	ExpiresAtLocal   string         `json:"expires_at_local"`
+	RemainingMinutes int            `json:"remaining_minutes"`
+	MaxTTLMinutes    int            `json:"max_ttl_minutes"` // 연장할 수 있는 최대 TTL
+	IdleLockMinutes  int            `json:"idle_lock_minutes,omitempty"`
+	Warnings         []AgentWarning `json:"warnings"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
+// RefreshAgentStatus 이 노드에서 실행 중인 세션마다 상태 파일과 경고 파일을 다시 씀
+func (s *Service) RefreshAgentStatus() error {
+	sessions, err := s.nodeSessions(s.nodeID)
+	if err != nil {
+		return err
+	}
+	cfg := s.config.Current()
+	now := time.Now()
+	for _, session := range sessions {
+		if session.State != store.SessionRunning {
+			continue
+		}
+		if err := s.writeAgentStatus(s.agentStatus(session, cfg, now)); err != nil {
+			logging.Repeated("agent-status", "⚠️ 세션 %s 상태 파일 기록 실패: %v", session.ID, err)
+		}
+	}
+	return nil
+}
+
+// agentStatus 세션의 만료 시각, 한도, 경고
+func (s *Service) agentStatus(session *store.Session, cfg *config.Config, now time.Time) *AgentStatus {
+	loc := s.userLocation(session.UserID)
+	remaining := int(session.ExpiresAt.Sub(now) / time.Minute)
+	if remaining < 0 {
+		remaining = 0
+	}
+	status := &AgentStatus{
+		SessionID:        session.ID,
+		UserID:           session.UserID,
+		Profile:          session.MIGProfile,
+		GPUName:          session.GPUName,
+		Tier:             session.Tier,
+		ExpiresAt:        session.ExpiresAt,
+		ExpiresAtLocal:   session.ExpiresAt.In(loc).Format(time.RFC3339),
+		RemainingMinutes: remaining,
+		MaxTTLMinutes:    cfg.MaxTTLMinutes,
+		IdleLockMinutes:  cfg.InactivityLock.IdleMinutes,
+		Warnings:         []AgentWarning{},
+		UpdatedAt:        now,
+	}
+
+	if warn := cfg.SessionAgent.ExpiryWarningMinutes; warn > 0 && remaining <= warn {
+		status.Warnings = append(status.Warnings, AgentWarning{
+			Code:    WarningExpiry,
+			Message: fmt.Sprintf("세션이 %d분 뒤(%s) 만료됩니다. 작업을 저장하거나 연장하세요.", remaining, session.ExpiresAt.In(loc).Format("15:04")),
+		})
+	}
+	if idle, warn := cfg.InactivityLock.IdleMinutes, cfg.SessionAgent.IdleWarningMinutes; idle > 0 && warn > 0 {
+		lockIn := int(time.Until(s.lastActive(session, now).Add(time.Duration(idle)*time.Minute)) / time.Minute)
+		if lockIn <= warn {
+			if lockIn < 0 {
+				lockIn = 0
+			}
+			status.Warnings = append(status.Warnings, AgentWarning{
+				Code:    WarningIdle,
+				Message: fmt.Sprintf("활동이 없어 약 %d분 뒤 세션이 잠깁니다. SSH로 다시 접속하면 재개됩니다.", lockIn),
+			})
+		}
+	}
+	if session.Tier == store.TierSpot {
+		status.Warnings = append(status.Warnings, AgentWarning{
+			Code:    WarningSpot,
+			Message: fmt.Sprintf("spot 세션은 다른 요청에 GPU가 필요하면 %d분 예고 후 회수될 수 있습니다.", cfg.Spot.NoticeMinutes),
+		})
+	}
+	return status
+}
+
+// writeAgentStatus 워크스페이스의 상태 파일과 경고 파일 기록 (호스트에서 읽을 수 없는 워크스페이스면 건너뜀)
+func (s *Service) writeAgentStatus(status *AgentStatus) error {
+	workspaceDir, err := s.resolveWorkspaceDir(status.UserID)
+	if errors.Is(err, errWorkspaceNotOnHost) {
+		logging.Debugf("세션 %s 워크스페이스를 호스트에서 읽을 수 없어 상태 파일을 건너뜀", status.SessionID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(status, "", "  ")
+	if err != nil {
+		return err
+	}
+	var warnings strings.Builder
+	for _, w := range status.Warnings {
+		fmt.Fprintf(&warnings, "⚠️  %s\n", w.Message)
+	}
+
+	dir := filepath.Join(workspaceDir, filepath.Dir(agentStatusFile))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(workspaceDir, agentStatusFile), append(data, '\n')); err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(workspaceDir, agentWarningsFile), []byte(warnings.String()))
+}
+
+// writeFileAtomic 임시 파일에 쓰고 rename (컨테이너 안에서 읽는 쪽이 쓰다 만 파일을 보지 않게 함)
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
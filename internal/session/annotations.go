@@ -0,0 +1,36 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+)
+
+// maxAnnotationBytes 네임스페이스 하나에 붙일 수 있는 JSON 크기
+const maxAnnotationBytes = 16 * 1024
+
+// annotationNamespace "booking", "billing.example.com", "example.com/ticketing" 같은 소유 시스템 이름
+var annotationNamespace = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]{0,62}[a-z0-9])?(/[a-z0-9]([a-z0-9._-]{0,62}[a-z0-9])?)?$`)
+
+// PatchAnnotations 외부 시스템 주석을 네임스페이스 단위로 설정 (null이면 삭제, 다른 네임스페이스는 유지)
+func (s *Service) PatchAnnotations(sessionID string, patch map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+	if len(patch) == 0 {
+		return nil, errs.New(errs.ErrInvalid, "변경할 네임스페이스가 없습니다")
+	}
+	for namespace, value := range patch {
+		if !annotationNamespace.MatchString(namespace) {
+			return nil, errs.New(errs.ErrInvalid, "잘못된 주석 네임스페이스입니다: %q (예: booking, example.com/ticketing)", namespace)
+		}
+		if len(value) > maxAnnotationBytes {
+			return nil, errs.New(errs.ErrInvalid, "주석 %s가 최대 크기 %d바이트를 넘습니다", namespace, maxAnnotationBytes)
+		}
+	}
+
+	annotations, err := s.store.PatchSessionAnnotations(sessionID, patch)
+	if err != nil {
+		return nil, fmt.Errorf("세션을 찾을 수 없습니다: %w", err)
+	}
+	return annotations, nil
+}
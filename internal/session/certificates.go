@@ -0,0 +1,132 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/sshca"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+	"golang.org/x/crypto/ssh"
+)
+
+// IssuedCertificate 발급된 인증서와 그 기록
+type IssuedCertificate struct {
+	*store.SSHCertificate
+	Certificate string `json:"certificate"` // id_xxx-cert.pub 파일에 저장할 한 줄
+	SSHUser     string `json:"ssh_user"`
+}
+
+// SetSSHCA 세션 컨테이너가 신뢰할 SSH CA 설정 (nil이면 인증서 모드 비활성)
+func (s *Service) SetSSHCA(ca *sshca.CA) {
+	s.sshCA = ca
+}
+
+// SSHCAPublicKey CA 공개키 (CA 모드가 아니면 빈 문자열)
+func (s *Service) SSHCAPublicKey() string {
+	if s.sshCA == nil {
+		return ""
+	}
+	return s.sshCA.PublicKey()
+}
+
+// installSSHCA 새 컨테이너가 이 세션 principal의 인증서를 받아들이도록 설정
+func (s *Service) installSSHCA(session *store.Session) error {
+	if s.sshCA == nil {
+		return nil
+	}
+	return s.dockerClient.InstallSSHCA(session.ContainerID, session.UserID, s.sshCA.PublicKey(), sshca.SessionPrincipal(session.ID))
+}
+
+// IssueCertificate 사용자 공개키에 세션 principal로 제한된 단기 인증서를 발급
+// 유효 시간은 certificate_ttl_minutes와 세션 만료 시각 중 이른 쪽까지
+func (s *Service) IssueCertificate(sessionID, publicKey string) (*IssuedCertificate, error) {
+	if s.sshCA == nil {
+		return nil, errs.New(errs.ErrConflict, "SSH CA가 설정되지 않았습니다 (--ssh-ca-key)")
+	}
+
+	session, err := s.store.GetSession(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("세션을 찾을 수 없습니다: %w", err)
+	}
+
+	key, err := ValidatePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	validBefore := time.Now().Add(time.Duration(s.config.Current().CertificateTTLMinutes) * time.Minute)
+	if session.ExpiresAt.Before(validBefore) {
+		validBefore = session.ExpiresAt
+	}
+
+	record := &store.SSHCertificate{
+		SessionID:   session.ID,
+		UserID:      session.UserID,
+		KeyID:       fmt.Sprintf("%s@%s", session.UserID, session.ID),
+		Principal:   sshca.SessionPrincipal(session.ID),
+		PublicKey:   key.KeyLine,
+		Fingerprint: key.Fingerprint,
+		ValidBefore: validBefore,
+		CreatedAt:   time.Now(),
+	}
+	// 일련번호를 먼저 받아 인증서에 기록 (폐기/감사 시 서로 대응)
+	if err := s.store.CreateSSHCertificate(record); err != nil {
+		return nil, fmt.Errorf("인증서 기록 저장 실패: %v", err)
+	}
+
+	cert, err := s.sshCA.Sign(key.key, record.Serial, record.KeyID, []string{record.Principal}, validBefore)
+	if err != nil {
+		s.store.RevokeSSHCertificate(session.ID, record.Serial)
+		return nil, err
+	}
+
+	log.Printf("🔏 SSH 인증서 발급: 세션=%s, 일련번호=%d, 만료=%s", session.ID, record.Serial, validBefore.Format(time.RFC3339))
+	return &IssuedCertificate{
+		SSHCertificate: record,
+		Certificate:    strings.TrimSpace(string(ssh.MarshalAuthorizedKey(cert))),
+		SSHUser:        session.UserID,
+	}, nil
+}
+
+func (s *Service) ListCertificates(sessionID string) ([]*store.SSHCertificate, error) {
+	if _, err := s.store.GetSession(sessionID); err != nil {
+		return nil, fmt.Errorf("세션을 찾을 수 없습니다: %w", err)
+	}
+	return s.store.ListSSHCertificates(sessionID)
+}
+
+// RevokeCertificate 인증서를 폐기하고 컨테이너 sshd가 해당 키를 즉시 거부하도록 함
+func (s *Service) RevokeCertificate(sessionID string, serial uint64) error {
+	session, err := s.store.GetSession(sessionID)
+	if err != nil {
+		return fmt.Errorf("세션을 찾을 수 없습니다: %w", err)
+	}
+
+	certs, err := s.store.ListSSHCertificates(sessionID)
+	if err != nil {
+		return err
+	}
+	var target *store.SSHCertificate
+	for _, cert := range certs {
+		if cert.Serial == serial {
+			target = cert
+			break
+		}
+	}
+	if target == nil || target.RevokedAt != nil {
+		return errs.New(errs.ErrNotFound, "유효한 인증서 %d를 찾을 수 없습니다", serial)
+	}
+
+	if err := s.store.RevokeSSHCertificate(sessionID, serial); err != nil {
+		return fmt.Errorf("인증서 폐기 기록 실패: %v", err)
+	}
+	if err := s.dockerClient.RevokeSSHKeys(session.ContainerID, []string{target.PublicKey}); err != nil {
+		return err
+	}
+
+	log.Printf("🚫 SSH 인증서 폐기: 세션=%s, 일련번호=%d (%s)", sessionID, serial, target.Fingerprint)
+	return nil
+}
@@ -0,0 +1,199 @@
+package session
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/command"
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+const (
+	// defaultCleanupHookTimeout timeout_seconds를 지정하지 않은 훅의 실행 제한 시간
+	defaultCleanupHookTimeout = 30 * time.Second
+	// cleanupHookOutputLimit 실패 로그에 남길 명령 출력 길이
+	cleanupHookOutputLimit = 2048
+)
+
+var cleanupHookRuns = metrics.NewCounter("sandman_cleanup_hooks_total", "세션 정리 훅 실행 수 (result: ok, failed)", "hook", "result")
+
+// CleanupHookPayload 정리 훅에 넘기는 세션 정보 (명령은 stdin, 웹훅은 요청 본문)
+type CleanupHookPayload struct {
+	Event           string    `json:"event"`
+	SessionID       string    `json:"session_id"`
+	UserID          string    `json:"user_id"`
+	ProjectID       string    `json:"project_id,omitempty"`
+	NodeID          string    `json:"node_id,omitempty"`
+	ContainerID     string    `json:"container_id"`
+	GPUUUID         string    `json:"gpu_uuid"`
+	MIGProfile      string    `json:"mig_profile"`
+	Tier            string    `json:"tier"`
+	Reason          string    `json:"reason"`
+	Workspace       string    `json:"workspace"`
+	WorkspacePolicy string    `json:"workspace_policy"`
+	CreatedAt       time.Time `json:"created_at"`
+	EndedAt         time.Time `json:"ended_at"`
+}
+
+// runCleanupHooks 정리가 끝난 세션에 설정된 훅을 백그라운드에서 실행
+// 훅 실패는 세션 정리 결과에 영향을 주지 않고 로그/감사 기록/메트릭으로만 남김
+func (s *Service) runCleanupHooks(session *store.Session, reason string, plan workspacePlan, endedAt time.Time) {
+	hooks := s.config.Current().CleanupHooks
+	if len(hooks) == 0 {
+		return
+	}
+
+	payload := CleanupHookPayload{
+		Event:           "session.ended",
+		SessionID:       session.ID,
+		UserID:          session.UserID,
+		ProjectID:       session.ProjectID,
+		NodeID:          session.NodeID,
+		ContainerID:     session.ContainerID,
+		GPUUUID:         session.GPUUUID,
+		MIGProfile:      session.MIGProfile,
+		Tier:            session.Tier,
+		Reason:          reason,
+		Workspace:       session.Metadata["workspace"],
+		WorkspacePolicy: plan.Policy,
+		CreatedAt:       session.CreatedAt,
+		EndedAt:         endedAt,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("⚠️ 정리 훅 본문 생성 실패: %v", err)
+		return
+	}
+
+	for _, hook := range hooks {
+		if !hookMatchesReason(hook, reason) {
+			continue
+		}
+		s.hooks.Add(1)
+		go func(hook config.CleanupHook) {
+			defer s.hooks.Done()
+			s.runCleanupHook(hook, payload, body)
+		}(hook)
+	}
+}
+
+// hookMatchesReason 훅의 reasons 필터에 종료 사유가 포함되는지 (비어 있으면 모두)
+func hookMatchesReason(hook config.CleanupHook, reason string) bool {
+	if len(hook.Reasons) == 0 {
+		return true
+	}
+	for _, r := range hook.Reasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Service) runCleanupHook(hook config.CleanupHook, payload CleanupHookPayload, body []byte) {
+	timeout := defaultCleanupHookTimeout
+	if hook.TimeoutSeconds > 0 {
+		timeout = time.Duration(hook.TimeoutSeconds) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	start := time.Now()
+	var err error
+	if len(hook.Command) > 0 {
+		err = runHookCommand(ctx, hook, payload, body)
+	} else {
+		err = postHookWebhook(ctx, hook, body)
+	}
+
+	if err != nil {
+		cleanupHookRuns.Inc(hook.Name, "failed")
+		log.Printf("⚠️ 정리 훅 실패: %s (세션: %s): %v", hook.Name, payload.SessionID, err)
+		s.audit("system", "session.cleanup_hook_failed", payload.SessionID, fmt.Sprintf("%s: %v", hook.Name, err))
+		return
+	}
+	cleanupHookRuns.Inc(hook.Name, "ok")
+	log.Printf("🪝 정리 훅 완료: %s (세션: %s, %s)", hook.Name, payload.SessionID, time.Since(start).Round(time.Millisecond))
+}
+
+// runHookCommand 셸 없이 명령 실행, 세션 정보는 stdin JSON과 SANDMAN_* 환경 변수로 전달
+// 오케스트레이터 환경 변수는 PATH 같은 기본 변수와 pass_env에 적은 것만 넘김
+func runHookCommand(ctx context.Context, hook config.CleanupHook, payload CleanupHookPayload, body []byte) error {
+	env := []string{
+		"SANDMAN_EVENT=" + payload.Event,
+		"SANDMAN_SESSION_ID=" + payload.SessionID,
+		"SANDMAN_USER_ID=" + payload.UserID,
+		"SANDMAN_PROJECT_ID=" + payload.ProjectID,
+		"SANDMAN_NODE_ID=" + payload.NodeID,
+		"SANDMAN_CONTAINER_ID=" + payload.ContainerID,
+		"SANDMAN_GPU_UUID=" + payload.GPUUUID,
+		"SANDMAN_MIG_PROFILE=" + payload.MIGProfile,
+		"SANDMAN_REASON=" + payload.Reason,
+		"SANDMAN_WORKSPACE=" + payload.Workspace,
+		"SANDMAN_WORKSPACE_POLICY=" + payload.WorkspacePolicy,
+	}
+	for _, key := range hook.PassEnv {
+		if value, ok := os.LookupEnv(key); ok {
+			env = append(env, key+"="+value)
+		}
+	}
+	result, err := command.Run(ctx, command.Options{
+		Env:      env,
+		Stdin:    bytes.NewReader(body),
+		Combined: true,
+	}, hook.Command[0], hook.Command[1:]...)
+	if command.IsTimeout(err) {
+		return fmt.Errorf("제한 시간 초과")
+	}
+	if err != nil {
+		return fmt.Errorf("%v: %s", err, tailOutput(result.Stdout))
+	}
+	return nil
+}
+
+// postHookWebhook 세션 정보를 POST (secret_env가 있으면 본문 HMAC-SHA256 서명을 붙임)
+func postHookWebhook(ctx context.Context, hook config.CleanupHook, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sandman-Event", "session.ended")
+	if hook.SecretEnv != "" {
+		mac := hmac.New(sha256.New, []byte(os.Getenv(hook.SecretEnv)))
+		mac.Write(body)
+		req.Header.Set("X-Sandman-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, cleanupHookOutputLimit))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	return nil
+}
+
+// tailOutput 명령 출력의 마지막 부분 (실패 원인은 보통 끝에 있음)
+func tailOutput(output []byte) string {
+	if len(output) > cleanupHookOutputLimit {
+		output = output[len(output)-cleanupHookOutputLimit:]
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -0,0 +1,191 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// clockProbeTimeout 컨테이너 하나의 시각 확인 제한 시간
+const clockProbeTimeout = 5 * time.Second
+
+var (
+	containerClockOffset = metrics.NewGauge("sandman_container_clock_offset_seconds", "마지막 확인에서 가장 큰 컨테이너-호스트 시계 차이 (절댓값)")
+	hostClockSynced      = metrics.NewGauge("sandman_host_clock_synchronized", "호스트 시계의 NTP 동기화 여부 (1: 동기화됨)")
+	clockDriftWarnings   = metrics.NewCounter("sandman_clock_drift_warnings_total", "허용 범위를 넘은 시계 차이 감지 수", "source")
+)
+
+// ClockStatus 이 노드의 마지막 시계 확인 결과
+// 만료 시각은 DB(오케스트레이터 시계) 기준이고 컨테이너 안 cron/토큰 만료는 컨테이너 시계 기준이라 둘이 어긋나면 만료 판단이 틀어짐
+type ClockStatus struct {
+	CheckedAt         time.Time `json:"checked_at"`
+	NTPSynchronized   *bool     `json:"ntp_synchronized,omitempty"` // 확인할 수 없는 플랫폼이면 없음
+	MaxErrorMs        int64     `json:"max_error_ms"`               // 커널의 최대 오차 추정치
+	ContainerOffsetMs int64     `json:"container_offset_ms"`        // 가장 크게 어긋난 컨테이너의 차이 (양수면 컨테이너가 앞섬)
+	Warning           string    `json:"warning,omitempty"`
+}
+
+type clockState struct {
+	mu     sync.Mutex
+	status ClockStatus
+}
+
+// ClockStatus 마지막 시계 확인 결과 (아직 확인 전이면 CheckedAt이 비어 있음)
+func (s *Service) ClockStatus() ClockStatus {
+	s.clock.mu.Lock()
+	defer s.clock.mu.Unlock()
+	return s.clock.status
+}
+
+// checkContainerClock 시작한 컨테이너의 시계가 호스트와 허용 범위 안에 있는지 확인
+// 세션은 그대로 만들고 경고만 남김 (다음 주기 확인에서 노드 상태에 반영)
+func (s *Service) checkContainerClock(sessionID, containerID string, cfg *config.Config) {
+	if cfg.ClockToleranceMs <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), clockProbeTimeout)
+	defer cancel()
+	offset, uncertainty, err := s.dockerClient.ClockOffset(ctx, containerID)
+	if err != nil {
+		logging.Debugf("세션 %s 컨테이너 시각 확인 불가: %v", sessionID, err)
+		return
+	}
+
+	tolerance := time.Duration(cfg.ClockToleranceMs) * time.Millisecond
+	if exceedsTolerance(offset, uncertainty, tolerance) {
+		clockDriftWarnings.Inc("container")
+		logging.Repeated("clock-offset", "⏱️ 세션 %s 컨테이너 시계가 호스트와 %v 차이 납니다 (허용 %v) - 만료 처리가 어긋날 수 있습니다", sessionID, offset.Round(time.Millisecond), tolerance)
+		s.audit("system", "session.clock_drift", sessionID, fmt.Sprintf("offset_ms=%d", offset.Milliseconds()))
+	}
+}
+
+// ProbeClocks 호스트 NTP 동기화 상태와 이 노드의 실행 중인 세션 컨테이너 시계 차이를 확인해 노드 상태에 반영
+func (s *Service) ProbeClocks() error {
+	cfg := s.config.Current()
+	if cfg.ClockToleranceMs <= 0 {
+		return nil
+	}
+	tolerance := time.Duration(cfg.ClockToleranceMs) * time.Millisecond
+
+	status := ClockStatus{CheckedAt: time.Now()}
+	warnings := []string{}
+
+	if synced, maxError, err := hostClockSync(); err != nil {
+		logging.Debugf("호스트 NTP 상태 확인 불가: %v", err)
+	} else {
+		status.NTPSynchronized = &synced
+		status.MaxErrorMs = maxError.Milliseconds()
+		if synced {
+			hostClockSynced.Set(1)
+		} else {
+			hostClockSynced.Set(0)
+			clockDriftWarnings.Inc("host")
+			warnings = append(warnings, "호스트 시계가 NTP와 동기화되지 않았습니다")
+		}
+	}
+
+	sessions, err := s.nodeSessions(s.nodeID)
+	if err != nil {
+		return err
+	}
+	var worst time.Duration
+	drifted := 0
+	for _, session := range sessions {
+		if session.ContainerID == "" || session.State != store.SessionRunning {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), clockProbeTimeout)
+		offset, uncertainty, err := s.dockerClient.ClockOffset(ctx, session.ContainerID)
+		cancel()
+		if err != nil {
+			logging.Debugf("세션 %s 컨테이너 시각 확인 불가: %v", session.ID, err)
+			continue
+		}
+		if offset.Abs() > worst.Abs() {
+			worst = offset
+		}
+		if exceedsTolerance(offset, uncertainty, tolerance) {
+			drifted++
+		}
+	}
+	status.ContainerOffsetMs = worst.Milliseconds()
+	containerClockOffset.Set(worst.Abs().Seconds())
+	if drifted > 0 {
+		clockDriftWarnings.Inc("container")
+		warnings = append(warnings, fmt.Sprintf("컨테이너 %d개의 시계가 호스트와 최대 %v 차이 납니다 (허용 %v)", drifted, worst.Round(time.Millisecond), tolerance))
+	}
+	status.Warning = strings.Join(warnings, "; ")
+
+	s.clock.mu.Lock()
+	previous := s.clock.status.Warning
+	s.clock.status = status
+	s.clock.mu.Unlock()
+
+	// 차이 값은 확인할 때마다 달라지므로 경고가 생기거나 사라질 때만 기록
+	if status.Warning != "" && previous == "" {
+		log.Printf("⏱️ 시계 차이 경고: %s", status.Warning)
+	} else if status.Warning == "" && previous != "" {
+		log.Println("✅ 시계 차이가 허용 범위 안으로 돌아왔습니다")
+	}
+	return nil
+}
+
+// recordNodeClock 하트비트에 실린 시계 상태를 노드 정보에 기록
+// sentAt이 있으면 노드 시계와 이 오케스트레이터(DB 기록) 시계의 차이도 함께 확인
+func (s *Service) recordNodeClock(id string, hb NodeHeartbeat, receivedAt time.Time) error {
+	offsetMs, warning := hb.ClockOffsetMs, hb.ClockWarning
+
+	cfg := s.config.Current()
+	if hb.SentAt != nil && cfg.ClockToleranceMs > 0 {
+		skew := hb.SentAt.Sub(receivedAt)
+		if skew.Abs().Milliseconds() > abs64(offsetMs) {
+			offsetMs = skew.Milliseconds()
+		}
+		if tolerance := time.Duration(cfg.ClockToleranceMs) * time.Millisecond; skew.Abs() > tolerance {
+			clockDriftWarnings.Inc("node")
+			nodeWarning := fmt.Sprintf("노드 시계가 오케스트레이터와 %v 차이 납니다 (허용 %v)", skew.Round(time.Millisecond), tolerance)
+			if warning != "" {
+				warning += "; " + nodeWarning
+			} else {
+				warning = nodeWarning
+			}
+		}
+	}
+
+	previous, err := s.store.GetNode(id)
+	if err != nil {
+		return err
+	}
+	if err := s.store.SetNodeClock(id, offsetMs, warning); err != nil {
+		return err
+	}
+	if warning != "" && previous.ClockWarning == "" {
+		log.Printf("⏱️ 노드 %s 시계 차이 경고: %s", id, warning)
+		s.audit("node:"+id, "node.clock_drift", id, warning)
+	} else if warning == "" && previous.ClockWarning != "" {
+		log.Printf("✅ 노드 %s 시계 차이 해소", id)
+		s.audit("node:"+id, "node.clock_recovered", id, "")
+	}
+	return nil
+}
+
+// exceedsTolerance 측정 오차를 빼고도 허용 범위를 넘는지 (exec가 느려서 생긴 차이로 경고하지 않도록)
+func exceedsTolerance(offset, uncertainty, tolerance time.Duration) bool {
+	return offset.Abs()-uncertainty > tolerance
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,24 @@
+//go:build linux
+
+package session
+
+import (
+	"syscall"
+	"time"
+)
+
+const (
+	timeError = 5      // adjtimex 반환값 TIME_ERROR: 시계가 동기화되지 않음
+	staUnsync = 0x0040 // timex.status STA_UNSYNC
+)
+
+// hostClockSync 커널이 보고하는 NTP 동기화 여부와 최대 오차 추정치 (adjtimex 읽기 전용 호출)
+func hostClockSync() (bool, time.Duration, error) {
+	var tx syscall.Timex
+	state, err := syscall.Adjtimex(&tx)
+	if err != nil {
+		return false, 0, err
+	}
+	synced := state != timeError && tx.Status&staUnsync == 0
+	return synced, time.Duration(tx.Maxerror) * time.Microsecond, nil
+}
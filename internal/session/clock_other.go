@@ -0,0 +1,13 @@
+//go:build !linux
+
+package session
+
+import (
+	"errors"
+	"time"
+)
+
+// hostClockSync 리눅스가 아니면 커널 NTP 상태를 확인할 수 없음
+func hostClockSync() (bool, time.Duration, error) {
+	return false, 0, errors.New("이 플랫폼에서는 NTP 동기화 상태를 확인할 수 없습니다")
+}
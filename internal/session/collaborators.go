@@ -0,0 +1,84 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// collaboratorTag 협업자 키를 authorized_keys에서 식별하는 주석
+func collaboratorTag(userID string) string {
+	return "sandman-collab:" + userID
+}
+
+// AddCollaborator 세션 컨테이너에 협업자의 공개키를 등록해 SSH 접근을 허용
+func (s *Service) AddCollaborator(sessionID, userID, publicKey string) (*store.Collaborator, error) {
+	if err := validateUserID(userID); err != nil {
+		return nil, err
+	}
+
+	session, err := s.store.GetSession(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("세션을 찾을 수 없습니다: %w", err)
+	}
+	if session.UserID == userID {
+		return nil, errs.New(errs.ErrInvalid, "세션 소유자는 협업자로 추가할 수 없습니다")
+	}
+
+	key, err := ValidatePublicKey(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	// 주석을 제거한 정규화된 키만 기록 (주석 자리는 태그로 사용)
+	keyLine := key.KeyLine
+
+	collab := &store.Collaborator{
+		SessionID:   sessionID,
+		UserID:      userID,
+		PublicKey:   keyLine,
+		Fingerprint: key.Fingerprint,
+		GrantedAt:   time.Now(),
+	}
+	if err := s.store.AddCollaborator(collab); err != nil {
+		return nil, fmt.Errorf("협업자 저장 실패: %w", err)
+	}
+
+	if err := s.dockerClient.AppendAuthorizedKey(session.ContainerID, session.UserID, keyLine, collaboratorTag(userID)); err != nil {
+		s.store.RemoveCollaborator(sessionID, userID)
+		return nil, err
+	}
+	s.publishCollaboratorRoute(session, userID)
+
+	log.Printf("🤝 협업자 추가: 세션=%s, 사용자=%s (%s)", sessionID, userID, collab.Fingerprint)
+	return collab, nil
+}
+
+func (s *Service) ListCollaborators(sessionID string) ([]*store.Collaborator, error) {
+	if _, err := s.store.GetSession(sessionID); err != nil {
+		return nil, fmt.Errorf("세션을 찾을 수 없습니다: %w", err)
+	}
+	return s.store.ListCollaborators(sessionID)
+}
+
+// RemoveCollaborator 협업자의 키를 컨테이너에서 제거하고 권한을 회수
+func (s *Service) RemoveCollaborator(sessionID, userID string) error {
+	session, err := s.store.GetSession(sessionID)
+	if err != nil {
+		return fmt.Errorf("세션을 찾을 수 없습니다: %w", err)
+	}
+
+	if err := s.store.RemoveCollaborator(sessionID, userID); err != nil {
+		return errs.New(errs.ErrNotFound, "협업자 %s를 찾을 수 없습니다", userID)
+	}
+	s.withdrawCollaboratorRoute(session, userID)
+
+	if err := s.dockerClient.RemoveAuthorizedKey(session.ContainerID, session.UserID, collaboratorTag(userID)); err != nil {
+		return err
+	}
+
+	log.Printf("🚫 협업자 제거: 세션=%s, 사용자=%s", sessionID, userID)
+	return nil
+}
@@ -0,0 +1,69 @@
+package session
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// checkImageCompatibility 이미지가 MIG 인스턴스, 프로파일, 호스트 드라이버에서 실행 가능한지 생성 전에 확인
+// image_compatibility에 없으면 카탈로그의 CUDA 버전으로만 검사하고, profile이 비어 있으면(UUID 지정) 프로파일 조건은 생략
+func (s *Service) checkImageCompatibility(image string, catalog *store.CatalogImage, profile string, cfg *config.Config) error {
+	compat, ok := cfg.ImageCompatibility[image]
+	if !ok && catalog != nil && catalog.CUDAVersion != "" {
+		compat, ok = config.ImageCompatibility{CUDA: catalog.CUDAVersion}, true
+	}
+	if !ok {
+		return nil
+	}
+	driver := s.gpuManager.DriverVersion()
+	reason := incompatibility(compat, profile, driver)
+	if reason == "" {
+		return nil
+	}
+
+	var alternatives []string
+	for name, other := range cfg.ImageCompatibility {
+		if incompatibility(other, profile, driver) == "" {
+			alternatives = append(alternatives, name)
+		}
+	}
+	sort.Strings(alternatives)
+	hint := "호환되는 등록 이미지가 없습니다"
+	if len(alternatives) > 0 {
+		hint = "사용 가능한 이미지: " + strings.Join(alternatives, ", ")
+	}
+	return errs.New(errs.ErrInvalid, "이미지 %s를 사용할 수 없습니다: %s (%s)", image, reason, hint)
+}
+
+// incompatibility 호환되지 않는 이유와 해결 방법 (호환되면 빈 문자열)
+func incompatibility(compat config.ImageCompatibility, profile, driver string) string {
+	if profile != "" && len(compat.Profiles) > 0 && !slices.Contains(compat.Profiles, profile) {
+		return fmt.Sprintf("프로파일 %s에서는 실행할 수 없습니다 (가능한 프로파일: %s)", profile, strings.Join(compat.Profiles, ", "))
+	}
+	// CPU 전용 세션은 GPU 조건과 무관
+	if profile == gpu.ProfileCPU {
+		return ""
+	}
+	// GPU 세션은 항상 MIG 인스턴스
+	if compat.MIGAware != nil && !*compat.MIGAware {
+		return "MIG 인스턴스를 지원하지 않는 이미지입니다"
+	}
+	if compat.CUDA != "" {
+		if cmp, _ := gpu.CompareVersions(compat.CUDA, gpu.MinMIGCUDA); cmp < 0 {
+			return fmt.Sprintf("CUDA %s는 MIG 인스턴스를 인식하지 못합니다 (CUDA %s 이상 이미지 필요)", compat.CUDA, gpu.MinMIGCUDA)
+		}
+	}
+	if compat.MinDriver != "" && driver != "" {
+		if cmp, err := gpu.CompareVersions(driver, compat.MinDriver); err == nil && cmp < 0 {
+			return fmt.Sprintf("NVIDIA 드라이버 %s 이상이 필요하지만 이 노드는 %s입니다 (낮은 CUDA 이미지를 선택하거나 드라이버 업그레이드 필요)", compat.MinDriver, driver)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,448 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/sshpiper"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// 일관성 검사에서 찾는 불일치 종류
+const (
+	IssueMissingContainer = "missing_container"  // 세션 기록은 있는데 컨테이너가 없음
+	IssueOrphanContainer  = "orphan_container"   // 세션 기록 없는 컨테이너
+	IssueContainerStopped = "container_stopped"  // 실행 중인 세션의 컨테이너가 멈춤
+	IssueGPUMismatch      = "gpu_mismatch"       // 컨테이너에 붙은 GPU가 세션 기록과 다름
+	IssueGPUNotAllocated  = "gpu_not_allocated"  // 세션이 쓰는 인스턴스가 할당 표시되지 않음
+	IssueGPULeaked        = "gpu_leaked"         // 할당 표시된 인스턴스를 쓰는 세션이 없음
+	IssueRouteMissing     = "route_missing"      // SSHPiper에 세션 라우트가 없거나 다른 곳을 가리킴
+	IssueRouteStale       = "route_stale"        // 세션 없는 SSHPiper 라우트
+	IssuePortLeaseStale   = "port_lease_stale"   // 어떤 컨테이너에도 바인딩되지 않은 포트 기록
+	IssuePortLeaseMissing = "port_lease_missing" // 컨테이너가 쓰는 포트가 기록되지 않음 (다른 세션에 줄 수 있음)
+	IssueIPMismatch       = "ip_mismatch"        // 컨테이너 IP가 세션 기록과 다름
+	IssueIPConflict       = "ip_conflict"        // 여러 컨테이너가 같은 IP를 씀
+	IssuePortConflict     = "port_conflict"      // 여러 컨테이너가 같은 호스트 포트를 씀
+)
+
+var consistencyIssues = metrics.NewCounter("sandman_consistency_issues_total", "일관성 검사에서 찾은 불일치 수 (action: repaired, alerted, failed, pending)", "kind", "action")
+
+// ConsistencyIssue 저장소, Docker, GPU 할당, 라우트, 포트/IP 기록 사이의 불일치 하나
+type ConsistencyIssue struct {
+	Kind      string `json:"kind"`
+	Resource  string `json:"resource"` // 컨테이너 ID, 인스턴스 UUID, SSH 사용자, 포트, IP
+	SessionID string `json:"session_id,omitempty"`
+	Detail    string `json:"detail"`
+	// Safe 자동으로 고쳐도 되는 불일치 (아니면 알리기만 함)
+	Safe     bool   `json:"safe"`
+	Repaired bool   `json:"repaired"`
+	Error    string `json:"error,omitempty"` // 고치지 못한 이유
+}
+
+func (i ConsistencyIssue) key() string {
+	return i.Kind + "|" + i.Resource
+}
+
+// ConsistencyReport 마지막 일관성 검사 결과 (이 노드 기준)
+type ConsistencyReport struct {
+	NodeID    string             `json:"node_id"`
+	CheckedAt time.Time          `json:"checked_at"`
+	Duration  string             `json:"duration"`
+	Checked   map[string]int     `json:"checked"` // 종류별로 확인한 대상 수
+	Issues    []ConsistencyIssue `json:"issues"`
+	Repaired  int                `json:"repaired"`
+	Unsafe    int                `json:"unsafe"` // 자동으로 고치지 않고 알린 불일치
+	Healthy   bool               `json:"healthy"`
+}
+
+// consistencyState 마지막 보고서와 지난 검사에서 본 불일치
+// 세션 생성 중에는 잠시 어긋나 보일 수 있으므로, 생성 도중에 생기는 종류는 두 번 연속 보일 때만 고침
+type consistencyState struct {
+	mu      sync.Mutex
+	last    *ConsistencyReport
+	seen    map[string]bool
+	alerted map[string]bool // 이미 알린 불일치 (사라질 때까지 다시 알리지 않음)
+}
+
+// transientIssues 세션 생성/정리 도중에도 잠깐 보이는 불일치 (두 번 연속일 때만 고치거나 알림)
+var transientIssues = map[string]bool{
+	IssueOrphanContainer:  true,
+	IssueMissingContainer: true,
+	IssueGPULeaked:        true,
+	IssuePortLeaseStale:   true,
+}
+
+// LastConsistencyReport 마지막 검사 결과 (아직 검사하지 않았으면 nil)
+func (s *Service) LastConsistencyReport() *ConsistencyReport {
+	s.consistency.mu.Lock()
+	defer s.consistency.mu.Unlock()
+	return s.consistency.last
+}
+
+// CheckConsistency 이 노드의 세션 기록을 컨테이너, GPU 할당, 라우트, 포트/IP 기록과 맞춰 보고 안전한 불일치는 고침
+// 세션을 지우거나 컨테이너를 없애야 하는 불일치는 고치지 않고 감사 기록과 메트릭으로 알림
+func (s *Service) CheckConsistency() (*ConsistencyReport, error) {
+	done, err := s.beginOperation()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	// 같은 노드의 복제본 중 하나만 검사 (다른 복제본이 검사 중이면 마지막 결과 반환)
+	lease, err := s.locker.TryAcquire("consistency:" + s.localNodeName())
+	if err != nil {
+		return nil, err
+	}
+	if lease == nil {
+		if last := s.LastConsistencyReport(); last != nil {
+			return last, nil
+		}
+		return nil, errs.New(errs.ErrConflict, "다른 복제본이 일관성 검사 중입니다")
+	}
+	defer lease.Release()
+
+	started := time.Now()
+	report := &ConsistencyReport{
+		NodeID:    s.localNodeName(),
+		CheckedAt: started,
+		Checked:   make(map[string]int),
+		Issues:    []ConsistencyIssue{},
+	}
+
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		return nil, err
+	}
+	containers, err := s.dockerClient.ListManagedContainers()
+	if err != nil {
+		return nil, err
+	}
+	var local []*store.Session
+	for _, session := range sessions {
+		if session.NodeID == "" || session.NodeID == s.nodeID {
+			local = append(local, session)
+		}
+	}
+	report.Checked["sessions"] = len(local)
+	report.Checked["containers"] = len(containers)
+
+	var found []ConsistencyIssue
+	found = append(found, s.checkContainers(local, containers)...)
+	found = append(found, s.checkGPUAllocations(local, report)...)
+	found = append(found, s.checkRoutes(report)...)
+	found = append(found, s.checkLeases(containers, report)...)
+
+	// 두 번 연속 보인 불일치만 처리하고, 새로 보인 것은 다음 검사까지 기다림
+	s.consistency.mu.Lock()
+	previous, alerted := s.consistency.seen, s.consistency.alerted
+	s.consistency.mu.Unlock()
+	seen := make(map[string]bool, len(found))
+	stillAlerted := make(map[string]bool)
+	for _, issue := range found {
+		seen[issue.key()] = true
+		if transientIssues[issue.Kind] && !previous[issue.key()] {
+			consistencyIssues.Inc(issue.Kind, "pending")
+			continue
+		}
+		s.handleIssue(&issue, alerted[issue.key()])
+		if !issue.Safe {
+			stillAlerted[issue.key()] = true
+		}
+		if issue.Repaired {
+			report.Repaired++
+		} else if !issue.Safe {
+			report.Unsafe++
+		}
+		report.Issues = append(report.Issues, issue)
+	}
+	sort.Slice(report.Issues, func(i, j int) bool { return report.Issues[i].key() < report.Issues[j].key() })
+	report.Healthy = len(report.Issues) == 0
+	report.Duration = time.Since(started).Round(time.Millisecond).String()
+
+	s.consistency.mu.Lock()
+	s.consistency.seen = seen
+	s.consistency.alerted = stillAlerted
+	s.consistency.last = report
+	s.consistency.mu.Unlock()
+	if !report.Healthy {
+		log.Printf("🩺 일관성 검사: 불일치 %d건 (복구 %d, 경고 %d)", len(report.Issues), report.Repaired, report.Unsafe)
+	}
+	return report, nil
+}
+
+// checkContainers 세션 기록과 컨테이너의 존재, 상태, GPU, IP를 비교
+func (s *Service) checkContainers(sessions []*store.Session, containers []*docker.ManagedContainer) []ConsistencyIssue {
+	var issues []ConsistencyIssue
+	byID := make(map[string]*docker.ManagedContainer, len(containers))
+	for _, c := range containers {
+		byID[c.ID] = c
+	}
+	owned := make(map[string]bool, len(sessions))
+	for _, session := range sessions {
+		owned[session.ContainerID] = true
+		c, ok := byID[session.ContainerID]
+		if !ok {
+			issues = append(issues, ConsistencyIssue{
+				Kind: IssueMissingContainer, Resource: shortContainerID(session.ContainerID), SessionID: session.ID,
+				Detail: fmt.Sprintf("사용자 %s의 세션 컨테이너가 없습니다", session.UserID),
+			})
+			continue
+		}
+		if session.State == store.SessionRunning && c.State != "running" {
+			issues = append(issues, ConsistencyIssue{
+				Kind: IssueContainerStopped, Resource: shortContainerID(c.ID), SessionID: session.ID,
+				Detail: fmt.Sprintf("세션은 실행 중인데 컨테이너 상태가 %s입니다", c.State),
+			})
+		}
+		// 가짜 GPU 빌드와 CPU 전용 세션은 장치를 붙이지 않으므로 컨테이너 쪽 UUID가 비어 있음
+		// 인스턴스를 내놓고 일시 중지한 세션의 컨테이너에는 이전 인스턴스가 남아 있음
+		if c.GPUUUID != "" && c.GPUUUID != session.GPUUUID && session.State != store.SessionPaused {
+			issues = append(issues, ConsistencyIssue{
+				Kind: IssueGPUMismatch, Resource: shortContainerID(c.ID), SessionID: session.ID,
+				Detail: fmt.Sprintf("컨테이너 GPU %s, 세션 기록 %s", c.GPUUUID, session.GPUUUID),
+			})
+		}
+		if c.IP != "" && session.ContainerIP != "" && c.IP != session.ContainerIP {
+			issues = append(issues, ConsistencyIssue{
+				Kind: IssueIPMismatch, Resource: c.IP, SessionID: session.ID, Safe: true,
+				Detail: fmt.Sprintf("컨테이너 IP %s, 세션 기록 %s", c.IP, session.ContainerIP),
+			})
+		}
+	}
+
+	ips := make(map[string][]string)
+	for _, c := range containers {
+		if !owned[c.ID] {
+			issues = append(issues, ConsistencyIssue{
+				Kind: IssueOrphanContainer, Resource: shortContainerID(c.ID),
+				Detail: fmt.Sprintf("세션 기록 없는 컨테이너 %s (%s)", c.Name, c.State),
+			})
+		}
+		if c.IP != "" {
+			ips[c.IP] = append(ips[c.IP], c.Name)
+		}
+	}
+	for ip, names := range ips {
+		if len(names) > 1 {
+			sort.Strings(names)
+			issues = append(issues, ConsistencyIssue{
+				Kind: IssueIPConflict, Resource: ip,
+				Detail: fmt.Sprintf("컨테이너 %v가 같은 IP를 씁니다", names),
+			})
+		}
+	}
+	return issues
+}
+
+// checkGPUAllocations 세션이 쓰는 인스턴스와 GPU 매니저의 할당 표시를 비교
+func (s *Service) checkGPUAllocations(sessions []*store.Session, report *ConsistencyReport) []ConsistencyIssue {
+	var issues []ConsistencyIssue
+	holders := make(map[string]*store.Session, len(sessions))
+	for _, session := range sessions {
+		if session.GPUUUID != "" {
+			holders[session.GPUUUID] = session
+		}
+	}
+	// 할당 기록이 있는 인스턴스는 다른 복제본이 세션을 만드는 중일 수 있으므로 누수로 보지 않음
+	claimed := map[string]bool{}
+	if claims, err := s.store.ListGPUClaims(s.nodeID); err == nil {
+		for _, claim := range claims {
+			claimed[claim.InstanceUUID] = true
+		}
+	}
+	instances := s.gpuManager.ListMIGInstances()
+	report.Checked["gpu_instances"] = len(instances)
+	known := make(map[string]bool, len(instances))
+	for _, instance := range instances {
+		known[instance.UUID] = true
+		session, held := holders[instance.UUID]
+		switch {
+		case held && !instance.InUse:
+			issues = append(issues, ConsistencyIssue{
+				Kind: IssueGPUNotAllocated, Resource: instance.UUID, SessionID: session.ID, Safe: true,
+				Detail: fmt.Sprintf("세션이 쓰는 인스턴스 %s가 빈 인스턴스로 표시되어 있습니다", instance.Name),
+			})
+		case !held && instance.InUse && !claimed[instance.UUID]:
+			issues = append(issues, ConsistencyIssue{
+				Kind: IssueGPULeaked, Resource: instance.UUID, Safe: true,
+				Detail: fmt.Sprintf("인스턴스 %s가 할당되어 있지만 쓰는 세션이 없습니다 (할당: %s)", instance.Name, instance.CreatedBy),
+			})
+		}
+	}
+	for uuid, session := range holders {
+		if !known[uuid] {
+			issues = append(issues, ConsistencyIssue{
+				Kind: IssueGPUMismatch, Resource: uuid, SessionID: session.ID,
+				Detail: "세션이 쓰는 인스턴스가 GPU 매니저에 없습니다 (MIG 구성이 바뀌었을 수 있음)",
+			})
+		}
+	}
+	return issues
+}
+
+// checkRoutes SSHPiper 설정 파일의 라우트를 이 노드 세션과 비교 (반영 대기 중인 변경은 건너뜀)
+func (s *Service) checkRoutes(report *ConsistencyReport) []ConsistencyIssue {
+	if s.router == nil {
+		return nil
+	}
+	snapshot, err := s.RouteSnapshot()
+	if err != nil {
+		logging.Debugf("일관성 검사: 라우트 테이블 조회 실패: %v", err)
+		return nil
+	}
+	report.Checked["routes"] = len(snapshot.Loaded)
+
+	var issues []ConsistencyIssue
+	for _, drift := range snapshot.Drift {
+		if drift.Pending {
+			continue
+		}
+		switch drift.Issue {
+		case sshpiper.DriftMissing, sshpiper.DriftMismatch:
+			issues = append(issues, ConsistencyIssue{
+				Kind: IssueRouteMissing, Resource: drift.User, SessionID: drift.Expected.SessionID, Safe: true,
+				Detail: fmt.Sprintf("SSHPiper 라우트 %s (%s)", drift.User, drift.Issue),
+			})
+		case sshpiper.DriftStale:
+			issues = append(issues, ConsistencyIssue{
+				Kind: IssueRouteStale, Resource: drift.User, SessionID: drift.Loaded.SessionID, Safe: true,
+				Detail: fmt.Sprintf("세션 없는 SSHPiper 라우트 %s", drift.User),
+			})
+		}
+	}
+	return issues
+}
+
+// checkLeases 포트 기록을 컨테이너 포트 바인딩과 비교하고 포트 중복을 찾음
+func (s *Service) checkLeases(containers []*docker.ManagedContainer, report *ConsistencyReport) []ConsistencyIssue {
+	var issues []ConsistencyIssue
+	bound := make(map[int][]string)
+	for _, c := range containers {
+		if c.SSHPort > 0 {
+			bound[c.SSHPort] = append(bound[c.SSHPort], c.Name)
+		}
+	}
+	leases := s.dockerClient.PortLeases()
+	report.Checked["port_leases"] = len(leases)
+	leased := make(map[int]bool, len(leases))
+	for _, port := range leases {
+		leased[port] = true
+		if _, ok := bound[port]; !ok {
+			issues = append(issues, ConsistencyIssue{
+				Kind: IssuePortLeaseStale, Resource: strconv.Itoa(port), Safe: true,
+				Detail: fmt.Sprintf("포트 %d가 기록되어 있지만 바인딩한 컨테이너가 없습니다", port),
+			})
+		}
+	}
+	for port, names := range bound {
+		if !leased[port] {
+			issues = append(issues, ConsistencyIssue{
+				Kind: IssuePortLeaseMissing, Resource: strconv.Itoa(port), Safe: true,
+				Detail: fmt.Sprintf("컨테이너 %v가 쓰는 포트 %d가 기록되어 있지 않습니다", names, port),
+			})
+		}
+		if len(names) > 1 {
+			sort.Strings(names)
+			issues = append(issues, ConsistencyIssue{
+				Kind: IssuePortConflict, Resource: strconv.Itoa(port),
+				Detail: fmt.Sprintf("컨테이너 %v가 같은 호스트 포트 %d를 씁니다", names, port),
+			})
+		}
+	}
+	return issues
+}
+
+// handleIssue 안전한 불일치는 고치고, 아니면 처음 보였을 때만 감사 기록으로 알림
+func (s *Service) handleIssue(issue *ConsistencyIssue, alerted bool) {
+	if !issue.Safe {
+		consistencyIssues.Inc(issue.Kind, "alerted")
+		if !alerted {
+			log.Printf("🚨 일관성 검사: %s %s - %s", issue.Kind, issue.Resource, issue.Detail)
+			s.audit("system", "consistency.alert", issue.Resource, issue.Kind+": "+issue.Detail)
+		}
+		return
+	}
+
+	if err := s.repairIssue(*issue); err != nil {
+		issue.Error = err.Error()
+		consistencyIssues.Inc(issue.Kind, "failed")
+		log.Printf("⚠️ 일관성 검사: %s %s 복구 실패: %v", issue.Kind, issue.Resource, err)
+		return
+	}
+	issue.Repaired = true
+	consistencyIssues.Inc(issue.Kind, "repaired")
+	log.Printf("🩹 일관성 검사: %s %s 복구 - %s", issue.Kind, issue.Resource, issue.Detail)
+	s.audit("system", "consistency.repaired", issue.Resource, issue.Kind+": "+issue.Detail)
+}
+
+func (s *Service) repairIssue(issue ConsistencyIssue) error {
+	switch issue.Kind {
+	case IssueGPUNotAllocated:
+		session, err := s.store.GetSession(issue.SessionID)
+		if err != nil {
+			return err
+		}
+		_, err = s.gpuManager.AllocateMIGByUUID(issue.Resource, session.UserID)
+		return err
+	case IssueGPULeaked:
+		instance, ok := s.gpuManager.Instance(issue.Resource)
+		if !ok {
+			return nil
+		}
+		return s.gpuManager.ReleaseMIG(issue.Resource, instance.CreatedBy)
+	case IssueRouteMissing:
+		session, err := s.store.GetSession(issue.SessionID)
+		if err != nil {
+			return err
+		}
+		for _, route := range s.expectedRoutes(session) {
+			if route.User == issue.Resource {
+				s.router.Upsert(route)
+			}
+		}
+		return nil
+	case IssueRouteStale:
+		s.router.Remove(issue.Resource)
+		return nil
+	case IssuePortLeaseStale, IssuePortLeaseMissing:
+		port, err := strconv.Atoi(issue.Resource)
+		if err != nil {
+			return err
+		}
+		if issue.Kind == IssuePortLeaseStale {
+			s.dockerClient.ReleasePortLease(port)
+		} else {
+			s.dockerClient.LeasePort(port)
+		}
+		return nil
+	case IssueIPMismatch:
+		return s.locker.WithLock("session-lock:"+issue.SessionID, func() error {
+			session, err := s.store.GetSession(issue.SessionID)
+			if err != nil {
+				return err
+			}
+			session.ContainerIP = issue.Resource
+			if err := s.store.UpdateSession(session); err != nil {
+				return err
+			}
+			s.publishRoute(session)
+			return nil
+		})
+	}
+	return fmt.Errorf("자동 복구를 지원하지 않는 불일치입니다: %s", issue.Kind)
+}
+
+func shortContainerID(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
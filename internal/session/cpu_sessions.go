@@ -0,0 +1,33 @@
+package session
+
+import (
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+)
+
+// defaultProfile 프로파일을 지정하지 않은 요청의 기본값 (GPU가 없는 노드는 CPU 전용)
+func (s *Service) defaultProfile(cfg *config.Config) string {
+	if !s.gpuManager.HasGPU() {
+		return gpu.ProfileCPU
+	}
+	return cfg.DefaultMIGProfile
+}
+
+// checkSessionType GPU가 없는 노드에 GPU 세션을 요청하거나 CPU 세션에 인스턴스를 지정하면 생성 전에 거부
+func (s *Service) checkSessionType(req CreateRequest) error {
+	if req.MIGProfile == gpu.ProfileCPU {
+		if req.MIGInstanceUUID != "" {
+			return errs.New(errs.ErrInvalid, "CPU 전용 세션(mig_profile: %q)에는 mig_instance_uuid를 지정할 수 없습니다", gpu.ProfileCPU)
+		}
+		return nil
+	}
+	if !s.gpuManager.HasGPU() {
+		requested := req.MIGProfile
+		if requested == "" {
+			requested = req.MIGInstanceUUID
+		}
+		return errs.New(errs.ErrInvalid, "이 노드에는 NVIDIA GPU가 없어 %s를 할당할 수 없습니다 (CPU 전용 세션은 mig_profile: %q)", requested, gpu.ProfileCPU)
+	}
+	return nil
+}
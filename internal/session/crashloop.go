@@ -0,0 +1,165 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// crashLogLines 크래시 루프로 멈춘 컨테이너에서 워크스페이스에 보존하는 로그 줄 수
+const crashLogLines = 2000
+
+var crashLoopsTotal = metrics.NewCounter("sandman_session_crash_loops_total", "크래시 루프로 failed 처리된 세션 수")
+
+// restartTracker 세션별 재시작 시각 (Docker는 누적 횟수만 알려주므로 변화량을 시각으로 기록)
+type restartTracker struct {
+	mu      sync.Mutex
+	history map[string]*restartHistory
+}
+
+type restartHistory struct {
+	count    int
+	restarts []time.Time
+}
+
+func newRestartTracker() *restartTracker {
+	return &restartTracker{history: make(map[string]*restartHistory)}
+}
+
+// observe 누적 재시작 횟수를 기록하고 window 안의 재시작 수를 반환
+// 처음 보는 세션은 기존 횟수를 기준점으로만 사용 (오케스트레이터 재시작 전 재시작은 시각을 모름)
+func (t *restartTracker) observe(sessionID string, count int, now time.Time, window time.Duration) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	h, ok := t.history[sessionID]
+	if !ok {
+		t.history[sessionID] = &restartHistory{count: count}
+		return 0
+	}
+	for i := h.count; i < count; i++ {
+		h.restarts = append(h.restarts, now)
+	}
+	h.count = count
+
+	kept := h.restarts[:0]
+	for _, at := range h.restarts {
+		if now.Sub(at) <= window {
+			kept = append(kept, at)
+		}
+	}
+	h.restarts = kept
+	return len(kept)
+}
+
+// retain 목록에 없는 세션 기록 삭제
+func (t *restartTracker) retain(ids map[string]bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id := range t.history {
+		if !ids[id] {
+			delete(t.history, id)
+		}
+	}
+}
+
+// CheckCrashLoops 이 노드 세션 컨테이너의 재시작 횟수를 확인해 크래시 루프인 세션을 failed로 표시
+func (s *Service) CheckCrashLoops() error {
+	cfg := s.config.Current()
+	if cfg.CrashLoopRestarts == 0 {
+		return nil
+	}
+	window := time.Duration(cfg.CrashLoopWindowMinutes) * time.Minute
+
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		return err
+	}
+
+	active := make(map[string]bool, len(sessions))
+	now := time.Now()
+	for _, session := range sessions {
+		if session.State != store.SessionRunning || (session.NodeID != "" && session.NodeID != s.nodeID) {
+			continue
+		}
+		active[session.ID] = true
+
+		count, err := s.dockerClient.RestartCount(session.ContainerID)
+		if err != nil {
+			if !errors.Is(err, errs.ErrNotFound) {
+				log.Printf("⚠️ 세션 %s 재시작 횟수 확인 실패: %v", session.ID, err)
+			}
+			continue
+		}
+		if restarts := s.restarts.observe(session.ID, count, now, window); restarts > cfg.CrashLoopRestarts {
+			s.markCrashLoop(session, restarts, cfg.CrashLoopWindowMinutes)
+		}
+	}
+	s.restarts.retain(active)
+	return nil
+}
+
+// markCrashLoop 재시작을 멈추고 로그를 워크스페이스에 남긴 뒤 세션을 failed로 표시
+// 컨테이너와 GPU는 세션이 삭제되거나 만료될 때까지 유지 (docker logs로도 확인 가능)
+func (s *Service) markCrashLoop(session *store.Session, restarts, windowMinutes int) {
+	if err := s.dockerClient.DisableRestart(session.ContainerID); err != nil {
+		log.Printf("⚠️ 세션 %s 재시작 중지 실패: %v", session.ID, err)
+	}
+	if err := s.dockerClient.StopContainer(session.ContainerID); err != nil {
+		log.Printf("⚠️ 세션 %s 컨테이너 중지 실패: %v", session.ID, err)
+	}
+
+	reason := fmt.Sprintf("crash_loop: %d분 동안 %d회 재시작", windowMinutes, restarts)
+	if session.Metadata == nil {
+		session.Metadata = make(map[string]string)
+	}
+	session.Metadata["failure_reason"] = reason
+	if path, err := s.preserveCrashLog(session); err != nil {
+		log.Printf("⚠️ 세션 %s 로그 보존 실패: %v", session.ID, err)
+	} else {
+		session.Metadata["crash_log"] = path
+	}
+
+	session.State = store.SessionFailed
+	if err := s.store.UpdateSession(session); err != nil {
+		log.Printf("⚠️ 세션 %s 상태 갱신 실패: %v", session.ID, err)
+		return
+	}
+	s.withdrawRoute(session)
+	s.liveness.put(s.probeLiveness(session, nil))
+
+	crashLoopsTotal.Inc()
+	log.Printf("💥 크래시 루프 감지: 세션 %s (사용자: %s) - %s", session.ID, session.UserID, reason)
+	s.audit("system", "session.crash_loop", session.ID, reason)
+	s.recordEvent(session, EventFailure, "system", reason, "crash_loop")
+}
+
+// preserveCrashLog 컨테이너 로그를 사용자 워크스페이스의 .sandman/ 아래에 저장 (컨테이너 안에서는 /workspace/.sandman/)
+func (s *Service) preserveCrashLog(session *store.Session) (string, error) {
+	logs, err := s.dockerClient.LogTail(session.ContainerID, crashLogLines)
+	if err != nil {
+		return "", err
+	}
+	workspaceDir, err := s.resolveWorkspaceDir(session.UserID)
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(workspaceDir, ".sandman")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	name := fmt.Sprintf("crash-%s.log", time.Now().UTC().Format("20060102T150405Z"))
+	if err := os.WriteFile(filepath.Join(dir, name), logs, 0644); err != nil {
+		return "", err
+	}
+	return "/workspace/.sandman/" + name, nil
+}
@@ -0,0 +1,188 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+	"golang.org/x/crypto/ssh"
+)
+
+// 키 교체 상태를 담는 세션 메타데이터 (공개키만 저장, 개인키는 일회용 링크로만 전달)
+const (
+	metaSSHPublicKey         = "ssh_public_key"
+	metaSSHPreviousPublicKey = "ssh_previous_public_key"
+	metaSSHPreviousRevokeAt  = "ssh_previous_revoke_at" // RFC3339, 이 시각 이후 만료 세션 정리 주기에서 폐기
+)
+
+// RotatedCredentials 키 교체 결과 (새 개인키는 credentials_token 링크로만 받을 수 있음)
+type RotatedCredentials struct {
+	SessionID            string     `json:"session_id"`
+	SSHUser              string     `json:"ssh_user"`
+	Fingerprint          string     `json:"fingerprint"`
+	PreviousFingerprint  string     `json:"previous_fingerprint"`
+	PreviousRevokeAt     time.Time  `json:"previous_revoke_at"` // 이전 키가 거부되기 시작하는 시각
+	CredentialsToken     string     `json:"credentials_token,omitempty"`
+	CredentialsExpiresAt *time.Time `json:"credentials_expires_at,omitempty"`
+}
+
+// RotateCredentials 실행 중인 세션의 SSH 키 쌍을 새로 만들어 컨테이너에 설치하고 새 일회용 링크를 발급
+// 이전 키는 credential_rotation_grace_minutes 동안 함께 허용한 뒤 폐기 (그 사이에 다시 교체하면 그 전 키는 즉시 폐기)
+func (s *Service) RotateCredentials(sessionID, actor string) (*RotatedCredentials, error) {
+	var result *RotatedCredentials
+	err := s.locker.WithLock("credentials:"+sessionID, func() error {
+		session, err := s.store.GetSession(sessionID)
+		if err != nil {
+			return fmt.Errorf("세션을 찾을 수 없습니다: %w", err)
+		}
+		if session.NodeID != "" && session.NodeID != s.nodeID {
+			return errs.New(errs.ErrConflict, "세션 %s는 노드 %s에서 실행 중입니다", session.ID, session.NodeID)
+		}
+		oldKey := session.Metadata[metaSSHPublicKey]
+		if oldKey == "" {
+			return errs.New(errs.ErrConflict, "세션 %s는 공개키 기록이 없어 키를 교체할 수 없습니다 (이전 버전에서 만든 세션)", session.ID)
+		}
+
+		publicKey, privateKey, err := s.dockerClient.GenerateSSHKeyPair(session.UserID)
+		if err != nil {
+			return fmt.Errorf("SSH 키 생성 실패: %v", err)
+		}
+		publicKey = strings.TrimSpace(publicKey)
+		if err := s.dockerClient.AppendAuthorizedKey(session.ContainerID, session.UserID, publicKey, sessionKeyTag(session.ID)); err != nil {
+			return err
+		}
+
+		// 아직 유예 중인 그 전 키는 더 기다리지 않고 폐기
+		if pending := session.Metadata[metaSSHPreviousPublicKey]; pending != "" {
+			if err := s.revokeSessionKey(session, pending); err != nil {
+				log.Printf("⚠️ 유예 중인 이전 키 폐기 실패 (세션: %s): %v", session.ID, err)
+			}
+		}
+
+		now := time.Now()
+		revokeAt := now.Add(time.Duration(s.config.Current().CredentialRotationGraceMinutes) * time.Minute)
+		session.Metadata[metaSSHPublicKey] = publicKey
+		session.Metadata[metaSSHPreviousPublicKey] = oldKey
+		session.Metadata[metaSSHPreviousRevokeAt] = revokeAt.Format(time.RFC3339)
+		if err := s.store.UpdateSession(session); err != nil {
+			s.dockerClient.RemoveAuthorizedKeyMaterial(session.ContainerID, session.UserID, publicKey)
+			return fmt.Errorf("세션 키 기록 실패: %v", err)
+		}
+
+		// 이전 개인키가 담긴 링크는 더 이상 쓰지 않도록 지우고 새 키로 다시 발급
+		if err := s.store.DeleteCredentialLinks(session.ID); err != nil {
+			log.Printf("⚠️ 이전 자격 증명 링크 삭제 실패: %v", err)
+		}
+		result = &RotatedCredentials{
+			SessionID:           session.ID,
+			SSHUser:             session.UserID,
+			Fingerprint:         keyFingerprint(publicKey),
+			PreviousFingerprint: keyFingerprint(oldKey),
+			PreviousRevokeAt:    revokeAt,
+		}
+		token, expires, err := s.issueCredentialLink(session, privateKey)
+		if err != nil {
+			log.Printf("⚠️ 세션 %s 자격 증명 링크 발급 실패: %v", session.ID, err)
+		} else {
+			result.CredentialsToken = token
+			result.CredentialsExpiresAt = &expires
+		}
+
+		// 공개키 라우트면 sshpiperd가 새 세션 키로 로그인하도록 키 파일을 바꾸고, 컨테이너 주소가 바뀐 경우를 대비해 다시 게시
+		if err := s.installGatewayKey(session, privateKey); err != nil {
+			log.Printf("⚠️ 세션 %s 게이트웨이 키 교체 실패 (이전 키 폐기 전까지만 접속 가능): %v", session.ID, err)
+		}
+		s.publishRoute(session)
+
+		if !revokeAt.After(now) {
+			s.revokePreviousKey(session)
+		}
+
+		s.audit(actor, "session.credentials_rotated", session.ID,
+			fmt.Sprintf("user=%s fingerprint=%s previous=%s revoke_at=%s", session.UserID, result.Fingerprint, result.PreviousFingerprint, revokeAt.Format(time.RFC3339)))
+		log.Printf("🔑 세션 SSH 키 교체: %s (%s → %s, 이전 키 폐기: %s)", session.ID, result.PreviousFingerprint, result.Fingerprint, revokeAt.Format(time.RFC3339))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// revokeRotatedKeys 유예 시간이 지난 이전 키 폐기 (만료 세션 정리 주기에 함께 실행)
+func (s *Service) revokeRotatedKeys() {
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		log.Printf("⚠️ 교체된 키 확인용 세션 목록 조회 실패: %v", err)
+		return
+	}
+	now := time.Now()
+	for _, session := range sessions {
+		if session.NodeID != "" && session.NodeID != s.nodeID {
+			continue
+		}
+		revokeAt, err := time.Parse(time.RFC3339, session.Metadata[metaSSHPreviousRevokeAt])
+		if err != nil || revokeAt.After(now) {
+			continue
+		}
+		err = s.locker.WithLock("credentials:"+session.ID, func() error {
+			// 락을 기다리는 사이 다시 교체됐을 수 있으므로 최신 상태로 처리
+			current, err := s.store.GetSession(session.ID)
+			if err != nil {
+				return err
+			}
+			s.revokePreviousKey(current)
+			return nil
+		})
+		if err != nil {
+			log.Printf("⚠️ 세션 %s 이전 키 폐기 실패: %v", session.ID, err)
+		}
+	}
+}
+
+// revokePreviousKey 유예 중인 이전 키를 폐기하고 메타데이터에서 지움 (credentials 락을 잡은 상태에서 호출)
+func (s *Service) revokePreviousKey(session *store.Session) {
+	previous := session.Metadata[metaSSHPreviousPublicKey]
+	if previous == "" {
+		return
+	}
+	if err := s.revokeSessionKey(session, previous); err != nil {
+		log.Printf("⚠️ 세션 %s 이전 키 폐기 실패 (다음 주기에 재시도): %v", session.ID, err)
+		return
+	}
+	delete(session.Metadata, metaSSHPreviousPublicKey)
+	delete(session.Metadata, metaSSHPreviousRevokeAt)
+	if err := s.store.UpdateSession(session); err != nil {
+		log.Printf("⚠️ 세션 %s 키 교체 상태 기록 실패: %v", session.ID, err)
+	}
+	s.audit("system", "session.credentials_previous_revoked", session.ID, "fingerprint="+keyFingerprint(previous))
+	log.Printf("🚫 교체된 이전 SSH 키 폐기: 세션=%s (%s)", session.ID, keyFingerprint(previous))
+}
+
+// revokeSessionKey authorized_keys에서 키를 지우고, CA 모드면 RevokedKeys에도 추가해 그 키로 받은 인증서까지 거부
+func (s *Service) revokeSessionKey(session *store.Session, keyLine string) error {
+	if err := s.dockerClient.RemoveAuthorizedKeyMaterial(session.ContainerID, session.UserID, keyLine); err != nil {
+		return err
+	}
+	if s.sshCA != nil {
+		return s.dockerClient.RevokeSSHKeys(session.ContainerID, []string{keyLine})
+	}
+	return nil
+}
+
+// sessionKeyTag 교체로 추가한 세션 키의 authorized_keys 주석
+func sessionKeyTag(sessionID string) string {
+	return "sandman-session-" + sessionID
+}
+
+// keyFingerprint authorized_keys 한 줄의 SHA256 지문 (해석할 수 없으면 빈 문자열)
+func keyFingerprint(keyLine string) string {
+	pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(keyLine))
+	if err != nil {
+		return ""
+	}
+	return ssh.FingerprintSHA256(pub)
+}
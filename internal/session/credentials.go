@@ -0,0 +1,88 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// 세션 종료 사유 (감사 기록에 남음)
+const (
+	EndReasonDeleted = "deleted"
+	EndReasonExpired = "expired"
+)
+
+// audit 감사 기록 추가 (실패해도 본 작업은 계속 진행)
+func (s *Service) audit(actor, action, target, detail string) {
+	entry := &store.AuditEntry{
+		Actor:     actor,
+		Action:    action,
+		Target:    target,
+		Detail:    detail,
+		CreatedAt: time.Now(),
+	}
+	if err := s.store.AppendAudit(entry); err != nil {
+		log.Printf("⚠️ 감사 기록 저장 실패 (%s %s): %v", action, target, err)
+	}
+}
+
+// revokeCredentials 컨테이너를 내리기 전에 세션 자격 증명을 무효화
+// 컨테이너 제거가 실패해 남더라도 발급된 인증서와 협업자 키로는 더 이상 접속할 수 없게 하고,
+// 저장소에는 지문만 남기고 키/비밀번호는 지움
+func (s *Service) revokeCredentials(session *store.Session, reason string) {
+	var revokedKeys []string
+
+	certs, err := s.store.ListSSHCertificates(session.ID)
+	if err != nil {
+		log.Printf("⚠️ 인증서 목록 조회 실패: %v", err)
+	}
+	activeCerts := 0
+	for _, cert := range certs {
+		if cert.RevokedAt == nil && cert.PublicKey != "" {
+			revokedKeys = append(revokedKeys, cert.PublicKey)
+			activeCerts++
+		}
+	}
+
+	collabs, err := s.store.ListCollaborators(session.ID)
+	if err != nil {
+		log.Printf("⚠️ 협업자 목록 조회 실패: %v", err)
+	}
+	for _, collab := range collabs {
+		revokedKeys = append(revokedKeys, collab.PublicKey)
+	}
+
+	// 컨테이너 sshd가 즉시 거부하도록 (실행 중인 경우에만 의미 있음)
+	if len(revokedKeys) > 0 {
+		if err := s.dockerClient.RevokeSSHKeys(session.ContainerID, revokedKeys); err != nil {
+			log.Printf("⚠️ 컨테이너 키 폐기 실패 (컨테이너 제거로 대체): %v", err)
+		}
+	}
+
+	if err := s.store.RevokeSSHCertificates(session.ID); err != nil {
+		log.Printf("⚠️ 인증서 폐기 기록 실패: %v", err)
+	}
+	if err := s.store.ScrubSSHCertificates(session.ID); err != nil {
+		log.Printf("⚠️ 인증서 공개키 삭제 실패: %v", err)
+	}
+	if err := s.store.DeleteCollaborators(session.ID); err != nil {
+		log.Printf("⚠️ 협업자 정보 삭제 실패: %v", err)
+	}
+	if err := s.store.DeleteCredentialLinks(session.ID); err != nil {
+		log.Printf("⚠️ 자격 증명 링크 삭제 실패: %v", err)
+	}
+
+	// 세션 레코드 삭제가 실패해도 비밀번호가 남지 않도록 먼저 지움
+	if _, ok := session.Metadata["ssh_password"]; ok {
+		delete(session.Metadata, "ssh_password")
+		if err := s.store.UpdateSession(session); err != nil {
+			log.Printf("⚠️ 세션 비밀번호 삭제 실패: %v", err)
+		}
+	}
+
+	s.audit("system", "session.credentials_revoked", session.ID,
+		fmt.Sprintf("user=%s reason=%s certificates=%d collaborators=%d", session.UserID, reason, activeCerts, len(collabs)))
+	log.Printf("🔒 세션 자격 증명 폐기: %s (인증서 %d, 협업자 %d)", session.ID, activeCerts, len(collabs))
+}
@@ -0,0 +1,86 @@
+package session
+
+import (
+	"strings"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// MaxDNSQueryLimit 관리자 조회 한 번에 돌려주는 최대 질의 수
+const MaxDNSQueryLimit = 1000
+
+var (
+	dnsQueriesRecorded = metrics.NewCounter("sandman_dns_queries_total", "DNS 감사로 기록한 세션 질의 수", "rcode")
+	dnsQueriesPruned   = metrics.NewCounter("sandman_dns_queries_pruned_total", "보존 기간/행 수 제한으로 삭제한 DNS 질의 기록 수")
+)
+
+// RecordDNSQueries 사이드카 로그에서 모은 질의를 질의한 주소의 세션과 묶어 기록
+func (s *Service) RecordDNSQueries(queries []docker.DNSQuery) error {
+	if len(queries) == 0 {
+		return nil
+	}
+
+	sessions, err := s.nodeSessions(s.nodeID)
+	if err != nil {
+		return err
+	}
+	byIP := make(map[string]*store.Session, len(sessions))
+	for _, session := range sessions {
+		byIP[session.ContainerIP] = session
+	}
+
+	records := make([]*store.DNSQueryRecord, 0, len(queries))
+	for _, query := range queries {
+		record := &store.DNSQueryRecord{
+			NodeID:    s.nodeID,
+			ClientIP:  query.ClientIP,
+			Name:      strings.ToLower(query.Name),
+			Type:      query.Type,
+			RCode:     query.RCode,
+			QueriedAt: query.At,
+		}
+		if session, ok := byIP[query.ClientIP]; ok {
+			record.SessionID = session.ID
+			record.UserID = session.UserID
+		}
+		records = append(records, record)
+	}
+	if err := s.store.InsertDNSQueries(records); err != nil {
+		return err
+	}
+	for _, record := range records {
+		dnsQueriesRecorded.Inc(record.RCode)
+	}
+	return nil
+}
+
+// PruneDNSQueries dns_audit_retention_days보다 오래된 기록과 dns_audit_max_rows를 넘는 기록 삭제
+func (s *Service) PruneDNSQueries() error {
+	cfg := s.config.Current()
+	before := time.Now().AddDate(0, 0, -cfg.DNSAuditRetentionDays)
+	deleted, err := s.store.PruneDNSQueries(before, cfg.DNSAuditMaxRows)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		dnsQueriesPruned.Add(float64(deleted))
+		logging.Routine("dns-audit-purge", "🧹 DNS 감사 기록 %d건 삭제 (보존 %d일, 최대 %d건)", deleted, cfg.DNSAuditRetentionDays, cfg.DNSAuditMaxRows)
+	}
+	return nil
+}
+
+// ListDNSQueries 관리자용 DNS 질의 조회
+func (s *Service) ListDNSQueries(filter store.DNSQueryFilter) ([]*store.DNSQueryRecord, error) {
+	if filter.Limit <= 0 || filter.Limit > MaxDNSQueryLimit {
+		return nil, errs.New(errs.ErrInvalid, "limit은 1~%d여야 합니다: %d", MaxDNSQueryLimit, filter.Limit)
+	}
+	if !filter.Since.IsZero() && !filter.Until.IsZero() && !filter.Until.After(filter.Since) {
+		return nil, errs.New(errs.ErrInvalid, "until은 since보다 뒤여야 합니다")
+	}
+	return s.store.ListDNSQueries(filter)
+}
@@ -0,0 +1,124 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+)
+
+// recentDockerEvents 상태에 남기는 최근 리소스 이벤트 수
+const recentDockerEvents = 20
+
+var (
+	dockerResourceEvents = metrics.NewCounter("sandman_docker_resource_events_total", "이 배포의 Docker 리소스 삭제 이벤트 수 (external: 오케스트레이터가 지운 것이 아님)", "type", "action", "external")
+	dockerResourcesOK    = metrics.NewGauge("sandman_docker_resources_healthy", "세션에 필요한 Docker 리소스(네트워크)가 있는지 (1: 정상)")
+	dockerReconciles     = metrics.NewCounter("sandman_docker_reconciliations_total", "Docker 리소스 복구 시도 수 (result: ok, failed)", "result")
+)
+
+// DockerResourceStatus 세션에 필요한 Docker 리소스 상태와 최근 외부 변경
+type DockerResourceStatus struct {
+	Healthy      bool                   `json:"healthy"`
+	Issues       []string               `json:"issues"`
+	ReconciledAt time.Time              `json:"reconciled_at,omitempty"`
+	Recent       []docker.ResourceEvent `json:"recent"` // 최신 이벤트가 앞
+}
+
+type dockerEventState struct {
+	mu     sync.Mutex
+	issues []string
+	status DockerResourceStatus
+}
+
+// DockerResourceStatus 마지막 확인 결과 (확인 전이면 정상으로 봄)
+func (s *Service) DockerResourceStatus() DockerResourceStatus {
+	s.dockerEvents.mu.Lock()
+	defer s.dockerEvents.mu.Unlock()
+	status := s.dockerEvents.status
+	status.Issues = append([]string{}, s.dockerEvents.issues...)
+	status.Healthy = len(status.Issues) == 0
+	status.Recent = append([]docker.ResourceEvent{}, status.Recent...)
+	return status
+}
+
+// HandleDockerEvent 이 배포의 리소스가 삭제된 이벤트를 기록하고, 외부에서 지운 것이면 알리고 복구
+func (s *Service) HandleDockerEvent(event docker.ResourceEvent) {
+	dockerResourceEvents.Inc(event.Type, event.Action, fmt.Sprint(event.External))
+
+	s.dockerEvents.mu.Lock()
+	recent := append([]docker.ResourceEvent{event}, s.dockerEvents.status.Recent...)
+	if len(recent) > recentDockerEvents {
+		recent = recent[:recentDockerEvents]
+	}
+	s.dockerEvents.status.Recent = recent
+	s.dockerEvents.mu.Unlock()
+
+	if !event.External {
+		return
+	}
+	detail := fmt.Sprintf("type=%s action=%s name=%s", event.Type, event.Action, event.Name)
+	s.audit("docker", "docker.resource_removed", event.Name, detail)
+
+	switch event.Type {
+	case "network":
+		log.Printf("🚨 세션 네트워크 %s가 외부에서 삭제되었습니다 - 다시 만듭니다", event.Name)
+		if err := s.ReconcileDockerResources(); err != nil {
+			log.Printf("⚠️ Docker 리소스 복구 실패: %v", err)
+		}
+	case "volume":
+		if session, err := s.store.GetSessionByUserID(event.UserID); err == nil && session != nil {
+			log.Printf("🚨 실행 중인 세션 %s의 워크스페이스 볼륨 %s가 외부에서 삭제되었습니다", session.ID, event.Name)
+		} else {
+			log.Printf("⚠️ 워크스페이스 볼륨 %s가 외부에서 삭제되었습니다 (사용자 %s의 데이터가 사라졌을 수 있음)", event.Name, event.UserID)
+		}
+	case "image":
+		// 사용자 이미지는 다음 세션 생성 때 다시 빌드하므로 기록만 남김
+		log.Printf("⚠️ 사용자 이미지 %s가 외부에서 삭제되었습니다 (%s)", event.Name, event.Action)
+	}
+}
+
+// ReconcileDockerResources 세션에 필요한 리소스가 있는지 확인하고 없으면 다시 만듦
+// 이벤트 스트림에 다시 연결할 때마다 호출해 끊긴 사이의 변경도 반영
+func (s *Service) ReconcileDockerResources() error {
+	var issues []string
+	exists, err := s.dockerClient.NetworkExists()
+	switch {
+	case err != nil:
+		issues = append(issues, fmt.Sprintf("세션 네트워크 확인 실패: %v", err))
+	case !exists:
+		if err := s.dockerClient.EnsureNetwork(); err != nil {
+			issues = append(issues, fmt.Sprintf("세션 네트워크 %s가 없고 다시 만들지 못했습니다: %v", s.dockerClient.Naming().NetworkName, err))
+		} else {
+			s.audit("system", "docker.resource_reconciled", s.dockerClient.Naming().NetworkName, "network recreated")
+			log.Printf("🔧 세션 네트워크 복구: %s", s.dockerClient.Naming().NetworkName)
+		}
+	}
+
+	s.dockerEvents.mu.Lock()
+	s.dockerEvents.issues = issues
+	s.dockerEvents.status.ReconciledAt = time.Now()
+	s.dockerEvents.mu.Unlock()
+
+	if len(issues) > 0 {
+		dockerResourcesOK.Set(0)
+		dockerReconciles.Inc("failed")
+		return fmt.Errorf("%s", issues[0])
+	}
+	dockerResourcesOK.Set(1)
+	dockerReconciles.Inc("ok")
+	return nil
+}
+
+// checkDockerResources 세션에 필요한 리소스가 없으면 생성 전에 이유와 함께 거부
+func (s *Service) checkDockerResources() error {
+	s.dockerEvents.mu.Lock()
+	defer s.dockerEvents.mu.Unlock()
+	if len(s.dockerEvents.issues) > 0 {
+		return errs.New(errs.ErrNoCapacity, "Docker 리소스 문제로 세션을 생성할 수 없습니다: %s", s.dockerEvents.issues[0])
+	}
+	return nil
+}
@@ -0,0 +1,191 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+const (
+	DrainImmediate   = "immediate"   // 남은 세션을 즉시 종료
+	DrainAtTTL       = "ttl"         // 세션이 원래 TTL에 만료될 때까지 대기
+	DrainMaintenance = "maintenance" // 점검 시작 시각(maintenance_at)에 남은 세션을 만료
+
+	// EndReasonDrained 노드 드레인으로 종료된 세션
+	EndReasonDrained = "drained"
+)
+
+// DrainRequest 노드 드레인 정책
+type DrainRequest struct {
+	Policy        string `json:"policy"`
+	MaintenanceAt string `json:"maintenance_at,omitempty"` // RFC3339, maintenance 정책에서만 사용
+}
+
+// DrainSession 드레인 중인 노드에 남아 있는 세션
+type DrainSession struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// DrainStatus 드레인 진행 상황 (세션이 모두 끝나면 empty가 되고 노드를 제거할 수 있음)
+type DrainStatus struct {
+	Node              *store.Node     `json:"node"`
+	RemainingSessions []*DrainSession `json:"remaining_sessions"`
+	Remaining         int             `json:"remaining"`
+	Empty             bool            `json:"empty"`
+	EstimatedEmptyAt  *time.Time      `json:"estimated_empty_at,omitempty"` // 남은 세션 중 가장 늦은 만료 시각
+}
+
+// DrainNode 노드에 새 세션 배치를 멈추고 정책에 따라 기존 세션을 정리
+// 컨테이너는 노드의 로컬 Docker에 묶여 있어 다른 노드로의 이전은 지원하지 않음
+func (s *Service) DrainNode(id string, req DrainRequest) (*DrainStatus, error) {
+	node, err := s.store.GetNode(id)
+	if err != nil {
+		return nil, fmt.Errorf("노드 %s를 찾을 수 없습니다: %w", id, err)
+	}
+
+	now := time.Now()
+	var deadline *time.Time
+	switch req.Policy {
+	case DrainImmediate:
+		deadline = &now
+	case DrainAtTTL, "":
+		req.Policy = DrainAtTTL
+	case DrainMaintenance:
+		at, err := time.Parse(time.RFC3339, req.MaintenanceAt)
+		if err != nil {
+			return nil, errs.New(errs.ErrInvalid, "maintenance_at 형식이 잘못되었습니다 (RFC3339): %q", req.MaintenanceAt)
+		}
+		if !at.After(now) {
+			return nil, errs.New(errs.ErrInvalid, "점검 시작 시각 %s가 이미 지났습니다", req.MaintenanceAt)
+		}
+		deadline = &at
+	default:
+		return nil, errs.New(errs.ErrInvalid, "알 수 없는 드레인 정책입니다: %q (immediate, ttl, maintenance)", req.Policy)
+	}
+
+	startedAt := now
+	if node.DrainStartedAt != nil && (node.Status == store.NodeDraining || node.Status == store.NodeDrained) {
+		startedAt = *node.DrainStartedAt
+	}
+	if err := s.store.SetNodeDrain(id, store.NodeDraining, req.Policy, &startedAt, deadline); err != nil {
+		return nil, fmt.Errorf("노드 드레인 시작 실패: %v", err)
+	}
+	log.Printf("🚧 노드 드레인 시작: %s (정책: %s)", id, req.Policy)
+	detail := "policy=" + req.Policy
+	if deadline != nil {
+		detail += " deadline=" + deadline.Format(time.RFC3339)
+	}
+	s.audit("admin", "node.drain_started", id, detail)
+
+	if deadline != nil {
+		if err := s.expireNodeSessions(id, *deadline); err != nil {
+			return nil, err
+		}
+	}
+	return s.DrainStatus(id)
+}
+
+// expireNodeSessions 노드 세션의 만료 시각을 deadline으로 앞당기고, 이미 지났으면 바로 정리
+func (s *Service) expireNodeSessions(nodeID string, deadline time.Time) error {
+	done, err := s.beginOperation()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	sessions, err := s.nodeSessions(nodeID)
+	if err != nil {
+		return err
+	}
+
+	for _, session := range sessions {
+		if !deadline.After(time.Now()) {
+			log.Printf("🚧 드레인으로 세션 종료: %s (사용자: %s, 노드: %s)", session.ID, session.UserID, nodeID)
+			if err := s.cleanupSession(session, EndReasonDrained); err != nil {
+				log.Printf("⚠️ 드레인 세션 정리 실패: %v", err)
+			}
+			continue
+		}
+		if session.ExpiresAt.After(deadline) {
+			session.ExpiresAt = deadline
+			if err := s.store.UpdateSession(session); err != nil {
+				log.Printf("⚠️ 세션 %s 만료 시각 변경 실패: %v", session.ID, err)
+				continue
+			}
+			log.Printf("🚧 점검 시각에 맞춰 세션 만료 조정: %s → %s", session.ID, deadline.Format(time.RFC3339))
+			if err := s.renderWelcome(session); err != nil {
+				log.Printf("⚠️ 세션 %s 환영 메시지 갱신 실패: %v", session.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// DrainStatus 드레인 진행 상황
+func (s *Service) DrainStatus(id string) (*DrainStatus, error) {
+	node, err := s.store.GetNode(id)
+	if err != nil {
+		return nil, fmt.Errorf("노드 %s를 찾을 수 없습니다: %w", id, err)
+	}
+	sessions, err := s.nodeSessions(id)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &DrainStatus{
+		Node:              node,
+		RemainingSessions: make([]*DrainSession, 0, len(sessions)),
+		Remaining:         len(sessions),
+		Empty:             len(sessions) == 0,
+	}
+	for _, session := range sessions {
+		status.RemainingSessions = append(status.RemainingSessions, &DrainSession{
+			ID:        session.ID,
+			UserID:    session.UserID,
+			ExpiresAt: session.ExpiresAt,
+		})
+		if status.EstimatedEmptyAt == nil || session.ExpiresAt.After(*status.EstimatedEmptyAt) {
+			expiresAt := session.ExpiresAt
+			status.EstimatedEmptyAt = &expiresAt
+		}
+	}
+	return status, nil
+}
+
+// CancelDrain 드레인을 취소하고 다시 세션을 받도록 복귀 (이미 앞당긴 만료 시각은 되돌리지 않음)
+func (s *Service) CancelDrain(id string) error {
+	node, err := s.store.GetNode(id)
+	if err != nil {
+		return fmt.Errorf("노드 %s를 찾을 수 없습니다: %w", id, err)
+	}
+	if node.Status != store.NodeDraining && node.Status != store.NodeDrained {
+		return errs.New(errs.ErrConflict, "노드 %s는 드레인 중이 아닙니다 (상태: %s)", id, node.Status)
+	}
+
+	if err := s.store.SetNodeDrain(id, store.NodeReady, "", nil, nil); err != nil {
+		return err
+	}
+	log.Printf("✅ 노드 드레인 취소: %s", id)
+	s.audit("admin", "node.drain_cancelled", id, "")
+	return nil
+}
+
+func (s *Service) nodeSessions(nodeID string) ([]*store.Session, error) {
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []*store.Session{}
+	for _, session := range sessions {
+		if session.NodeID == nodeID {
+			result = append(result, session)
+		}
+	}
+	return result, nil
+}
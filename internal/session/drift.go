@@ -0,0 +1,213 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// driftExecTimeout 컨테이너 안에서 패키지 목록을 조회하는 최대 시간
+const driftExecTimeout = 30 * time.Second
+
+// PackageChange 기준선 대비 패키지 변경
+type PackageChange struct {
+	Name            string `json:"name"` // "dpkg:<이름>" 또는 "pip:<이름>"
+	Version         string `json:"version,omitempty"`
+	BaselineVersion string `json:"baseline_version,omitempty"`
+}
+
+// DriftReport 실행 중인 컨테이너와 템플릿 이미지 기준선 비교 결과
+type DriftReport struct {
+	SessionID          string              `json:"session_id"`
+	Image              string              `json:"image"`
+	Modified           bool                `json:"modified"`
+	BaselineCapturedAt *time.Time          `json:"baseline_captured_at,omitempty"` // 없으면 패키지 비교 생략 (기준선 기록 이전 세션)
+	PackagesAdded      []PackageChange     `json:"packages_added"`
+	PackagesRemoved    []PackageChange     `json:"packages_removed"`
+	PackagesChanged    []PackageChange     `json:"packages_changed"`
+	Files              []docker.FileChange `json:"files"`
+	CheckedAt          time.Time           `json:"checked_at"`
+}
+
+// captureBaseline 세션 시작 직후 패키지 목록을 기준선으로 기록 (실패해도 세션 생성은 계속)
+func (s *Service) captureBaseline(session *store.Session) {
+	ctx, cancel := context.WithTimeout(context.Background(), driftExecTimeout)
+	defer cancel()
+
+	packages, err := s.dockerClient.InstalledPackages(ctx, session.ContainerID)
+	if err != nil {
+		log.Printf("⚠️ 세션 %s 기준선 기록 실패: %v", session.ID, err)
+		return
+	}
+	baseline := &store.SessionBaseline{
+		SessionID:  session.ID,
+		Image:      session.Metadata["image"],
+		Packages:   packages,
+		CapturedAt: time.Now(),
+	}
+	if err := s.store.SaveSessionBaseline(baseline); err != nil {
+		log.Printf("⚠️ 세션 %s 기준선 저장 실패: %v", session.ID, err)
+	}
+}
+
+// SessionDrift 컨테이너의 설치 패키지와 파일시스템이 기준선(템플릿 이미지)에서 얼마나 바뀌었는지 보고
+func (s *Service) SessionDrift(sessionID string) (*DriftReport, error) {
+	session, err := s.store.GetSession(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("세션을 찾을 수 없습니다: %w", err)
+	}
+	if session.NodeID != "" && session.NodeID != s.nodeID {
+		return nil, errs.New(errs.ErrConflict, "세션 %s는 노드 %s에서 실행 중입니다", sessionID, session.NodeID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), driftExecTimeout)
+	defer cancel()
+
+	report := &DriftReport{
+		SessionID:       session.ID,
+		Image:           session.Metadata["image"],
+		PackagesAdded:   []PackageChange{},
+		PackagesRemoved: []PackageChange{},
+		PackagesChanged: []PackageChange{},
+		CheckedAt:       time.Now(),
+	}
+
+	report.Files, err = s.dockerClient.FilesystemChanges(ctx, session.ContainerID)
+	if err != nil {
+		return nil, err
+	}
+
+	baseline, err := s.store.GetSessionBaseline(session.ID)
+	if err != nil && !errors.Is(err, errs.ErrNotFound) {
+		return nil, err
+	}
+	if baseline != nil {
+		current, err := s.dockerClient.InstalledPackages(ctx, session.ContainerID)
+		if err != nil {
+			return nil, err
+		}
+		report.BaselineCapturedAt = &baseline.CapturedAt
+		diffPackages(report, baseline.Packages, current)
+	}
+
+	report.Modified = len(report.Files) > 0 || len(report.PackagesAdded) > 0 ||
+		len(report.PackagesRemoved) > 0 || len(report.PackagesChanged) > 0
+	return report, nil
+}
+
+func diffPackages(report *DriftReport, baseline, current map[string]string) {
+	for name, version := range current {
+		base, ok := baseline[name]
+		switch {
+		case !ok:
+			report.PackagesAdded = append(report.PackagesAdded, PackageChange{Name: name, Version: version})
+		case base != version:
+			report.PackagesChanged = append(report.PackagesChanged, PackageChange{Name: name, Version: version, BaselineVersion: base})
+		}
+	}
+	for name, version := range baseline {
+		if _, ok := current[name]; !ok {
+			report.PackagesRemoved = append(report.PackagesRemoved, PackageChange{Name: name, BaselineVersion: version})
+		}
+	}
+	for _, list := range [][]PackageChange{report.PackagesAdded, report.PackagesRemoved, report.PackagesChanged} {
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	}
+}
+
+// ResetToBaseline 컨테이너를 템플릿 이미지로 다시 만들어 환경 변경을 되돌림
+// 워크스페이스, SSH 포트, 비밀번호, 키는 유지되며 협업자 키와 SSH CA 설정은 다시 적용
+func (s *Service) ResetToBaseline(sessionID string) (*store.Session, error) {
+	done, err := s.beginOperation()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	session, err := s.store.GetSession(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("세션을 찾을 수 없습니다: %w", err)
+	}
+	if session.NodeID != "" && session.NodeID != s.nodeID {
+		return nil, errs.New(errs.ErrConflict, "세션 %s는 노드 %s에서 실행 중입니다", sessionID, session.NodeID)
+	}
+	if session.State == store.SessionPaused {
+		return nil, errs.New(errs.ErrConflict, "일시 중지한 세션은 먼저 재개해야 합니다")
+	}
+	if session.State == store.SessionStopped {
+		return nil, errs.New(errs.ErrConflict, "멈춘 세션은 먼저 시작해야 합니다")
+	}
+	if err := checkNotQuarantined(session); err != nil {
+		return nil, err
+	}
+
+	cfg := s.config.Current()
+	releaseSlot, err := s.acquireNodeSlot(cfg)
+	if err != nil {
+		return nil, err
+	}
+	info, err := s.dockerClient.RecreateContainer(session.ContainerID, stopOptions(session, cfg))
+	releaseSlot()
+	if err != nil {
+		return nil, err
+	}
+	previous := session.ContainerID
+	session.ContainerID = info.ID
+	if info.IP != "" {
+		session.ContainerIP = info.IP
+	}
+	// 크래시 루프로 멈춘 세션도 새 컨테이너로 다시 시작 (재시작 정책은 "no"로 남음)
+	session.State = store.SessionRunning
+	delete(session.Metadata, "failure_reason")
+	if err := s.store.UpdateSession(session); err != nil {
+		return nil, fmt.Errorf("세션 갱신 실패: %w", err)
+	}
+
+	// 컨테이너 안에만 있던 접근 설정 복구
+	if err := s.installSSHCA(session); err != nil {
+		log.Printf("⚠️ 세션 %s SSH CA 재설치 실패: %v", session.ID, err)
+	}
+	s.reapplyAccess(session)
+	if err := s.provisionWorkspaceSSH(session); err != nil {
+		log.Printf("⚠️ 세션 %s 워크스페이스 .ssh 재구성 실패: %v", session.ID, err)
+	}
+	if err := s.verifyGPUAttachment(session.ContainerID, session.GPUUUID, s.config.Current()); err != nil {
+		log.Printf("⚠️ 세션 %s GPU 확인 실패: %v", session.ID, err)
+	}
+	s.liveness.put(s.probeLiveness(session, nil))
+	s.publishRoute(session)
+
+	log.Printf("♻️ 세션 환경 초기화: %s (컨테이너 %s → %s)", session.ID, previous[:12], session.ContainerID[:12])
+	s.audit("api", "session.reset_to_baseline", session.ID, fmt.Sprintf("previous_container=%s", previous))
+	return session, nil
+}
+
+// reapplyAccess 협업자 키와 폐기된 인증서 키를 새 컨테이너에 다시 반영
+func (s *Service) reapplyAccess(session *store.Session) {
+	if collabs, err := s.store.ListCollaborators(session.ID); err == nil {
+		for _, collab := range collabs {
+			if err := s.dockerClient.AppendAuthorizedKey(session.ContainerID, session.UserID, collab.PublicKey, collaboratorTag(collab.UserID)); err != nil {
+				log.Printf("⚠️ 협업자 %s 키 재적용 실패: %v", collab.UserID, err)
+			}
+		}
+	}
+
+	if certs, err := s.store.ListSSHCertificates(session.ID); err == nil {
+		var revoked []string
+		for _, cert := range certs {
+			if cert.RevokedAt != nil && cert.PublicKey != "" {
+				revoked = append(revoked, cert.PublicKey)
+			}
+		}
+		if err := s.dockerClient.RevokeSSHKeys(session.ContainerID, revoked); err != nil {
+			log.Printf("⚠️ 폐기된 인증서 키 재적용 실패: %v", err)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package session
+
+import (
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// 세션 이벤트 종류 (store.SessionEvent.Type)
+const (
+	EventCreate       = "create"
+	EventFailure      = "failure" // 생성 실패, 크래시 루프
+	EventExtend       = "extend"
+	EventPause        = "pause"
+	EventResume       = "resume"
+	EventStop         = "stop"
+	EventStart        = "start"
+	EventImage        = "image"      // 세션 이미지 교체 (detail: from, to)
+	EventQuarantine   = "quarantine" // reason: 관리자가 적은 격리 사유
+	EventUnquarantine = "unquarantine"
+	EventExpire       = "expire"
+	EventDelete       = "delete"
+	EventEnd          = "end" // 그 밖의 이유로 끝남 (reason: idle, drained, preempted, purged 등)
+)
+
+// maxAuditLimit 감사 기록 한 번에 조회할 수 있는 최대 개수
+const maxAuditLimit = 1000
+
+var sessionEventsTotal = metrics.NewCounter("sandman_session_events_total", "기록한 세션 이벤트 수", "type")
+
+// recordEvent 세션 이벤트 추가 (저장에 실패해도 작업은 계속)
+func (s *Service) recordEvent(session *store.Session, eventType, actor, reason, detail string) {
+	event := &store.SessionEvent{
+		SessionID: session.ID,
+		UserID:    session.UserID,
+		Type:      eventType,
+		Actor:     actor,
+		Reason:    reason,
+		Detail:    detail,
+		CreatedAt: time.Now(),
+	}
+	if err := s.store.AppendSessionEvent(event); err != nil {
+		log.Printf("⚠️ 세션 이벤트 저장 실패 (%s %s): %v", eventType, session.ID, err)
+		return
+	}
+	sessionEventsTotal.Inc(eventType)
+}
+
+// endEventType 세션 종료 이유에 맞는 이벤트 종류
+func endEventType(reason string) string {
+	switch reason {
+	case EndReasonExpired:
+		return EventExpire
+	case EndReasonDeleted:
+		return EventDelete
+	default:
+		return EventEnd
+	}
+}
+
+// ListSessionEvents 세션에 일어난 일 (기록 순, 세션이 끝난 뒤에도 조회 가능)
+func (s *Service) ListSessionEvents(sessionID string) ([]*store.SessionEvent, error) {
+	events, err := s.store.ListSessionEvents(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		if _, err := s.store.GetSession(sessionID); err != nil {
+			return nil, errs.New(errs.ErrNotFound, "세션 %s의 기록이 없습니다", sessionID)
+		}
+	}
+	return events, nil
+}
+
+// QueryAudit 감사 기록 조회 (limit은 1~1000, 0이면 100)
+func (s *Service) QueryAudit(filter store.AuditFilter) ([]*store.AuditEntry, error) {
+	if filter.Limit == 0 {
+		filter.Limit = 100
+	}
+	if filter.Limit < 0 || filter.Limit > maxAuditLimit {
+		return nil, errs.New(errs.ErrInvalid, "limit은 1~%d이어야 합니다: %d", maxAuditLimit, filter.Limit)
+	}
+	return s.store.QueryAudit(filter)
+}
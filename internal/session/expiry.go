@@ -0,0 +1,161 @@
+package session
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+)
+
+const (
+	ExpiryEndOfDay  = "end_of_day"
+	ExpiryEndOfWeek = "end_of_week"
+)
+
+// cronSearchLimit cron 표현식의 다음 시각을 찾는 최대 범위 (그 안에 없으면 거부)
+const cronSearchLimit = 366 * 24 * time.Hour
+
+// resolveExpiryPolicy 달력 기반 만료 정책을 배포 시간대(timezone) 기준 절대 시각으로 변환
+// "end_of_day"는 오늘 day_end, "end_of_week"는 이번 주 week_end 요일의 day_end,
+// 그 밖의 값은 5필드 cron 표현식("0 18 * * 1-5")으로 보고 now 이후 첫 시각을 사용
+func resolveExpiryPolicy(policy string, now time.Time, cfg *config.Config) (time.Time, error) {
+	loc := cfg.Location()
+	local := now.In(loc)
+	dayEnd, _ := time.Parse("15:04", cfg.DayEnd)
+
+	switch policy {
+	case ExpiryEndOfDay:
+		t := time.Date(local.Year(), local.Month(), local.Day(), dayEnd.Hour(), dayEnd.Minute(), 0, 0, loc)
+		if !t.After(now) {
+			return time.Time{}, errs.New(errs.ErrInvalid, "오늘 종료 시각 %s(%s)이 이미 지났습니다", cfg.DayEnd, loc.String())
+		}
+		return t, nil
+	case ExpiryEndOfWeek:
+		weekEnd, _ := config.ParseWeekday(cfg.WeekEnd)
+		days := (int(weekEnd) - int(local.Weekday()) + 7) % 7
+		t := time.Date(local.Year(), local.Month(), local.Day()+days, dayEnd.Hour(), dayEnd.Minute(), 0, 0, loc)
+		if !t.After(now) {
+			return time.Time{}, errs.New(errs.ErrInvalid, "이번 주 종료 시각(%s %s, %s)이 이미 지났습니다", cfg.WeekEnd, cfg.DayEnd, loc.String())
+		}
+		return t, nil
+	}
+
+	expr, err := parseCron(policy)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, ok := expr.next(local)
+	if !ok {
+		return time.Time{}, errs.New(errs.ErrInvalid, "cron 표현식 %q에 해당하는 시각이 1년 안에 없습니다", policy)
+	}
+	return t, nil
+}
+
+// cronExpr 분, 시, 일, 월, 요일 필드별 허용 값
+type cronExpr struct {
+	minute, hour, dom, month, dow []bool
+	domAny, dowAny                bool
+}
+
+// parseCron "분 시 일 월 요일" 형식 파싱 (*, 목록 "1,15", 범위 "1-5", 간격 "*/15" 지원, 요일 0과 7은 일요일)
+func parseCron(expr string) (*cronExpr, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, errs.New(errs.ErrInvalid, "알 수 없는 만료 정책입니다: %q (end_of_day, end_of_week 또는 \"분 시 일 월 요일\" cron 표현식)", expr)
+	}
+
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	names := [5]string{"분", "시", "일", "월", "요일"}
+	sets := make([][]bool, 5)
+	for i, field := range fields {
+		set, err := parseCronField(field, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, errs.New(errs.ErrInvalid, "cron %s 필드가 잘못되었습니다 (%q): %v", names[i], field, err)
+		}
+		sets[i] = set
+	}
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+
+	return &cronExpr{
+		minute: sets[0], hour: sets[1], dom: sets[2], month: sets[3], dow: sets[4],
+		domAny: fields[2] == "*", dowAny: fields[4] == "*",
+	}, nil
+}
+
+func parseCronField(field string, lo, hi int) ([]bool, error) {
+	set := make([]bool, hi+1)
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return nil, errs.New(errs.ErrInvalid, "간격은 양의 정수여야 합니다")
+			}
+			step = n
+		}
+
+		start, end := lo, hi
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = strconv.Atoi(from); err != nil {
+				return nil, errs.New(errs.ErrInvalid, "숫자가 아닙니다: %s", from)
+			}
+			end = start
+			if isRange {
+				if end, err = strconv.Atoi(to); err != nil {
+					return nil, errs.New(errs.ErrInvalid, "숫자가 아닙니다: %s", to)
+				}
+			} else if hasStep {
+				end = hi
+			}
+		}
+		if start < lo || end > hi || start > end {
+			return nil, errs.New(errs.ErrInvalid, "범위는 %d~%d여야 합니다", lo, hi)
+		}
+		for v := start; v <= end; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+// dayMatches 일/요일 중 하나만 지정되면 그 필드만, 둘 다 지정되면 어느 한쪽이 맞으면 일치 (표준 cron 규칙)
+func (e *cronExpr) dayMatches(t time.Time) bool {
+	dom, dow := e.dom[t.Day()], e.dow[int(t.Weekday())]
+	switch {
+	case e.domAny && e.dowAny:
+		return true
+	case e.domAny:
+		return dow
+	case e.dowAny:
+		return dom
+	}
+	return dom || dow
+}
+
+// next t 이후(같은 분 제외) 표현식과 일치하는 첫 시각 (t의 시간대 기준)
+func (e *cronExpr) next(t time.Time) (time.Time, bool) {
+	limit := t.Add(cronSearchLimit)
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	for t.Before(limit) {
+		if !e.month[int(t.Month())] || !e.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !e.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if e.minute[t.Minute()] {
+			return t, true
+		}
+		t = t.Add(time.Minute)
+	}
+	return time.Time{}, false
+}
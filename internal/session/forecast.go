@@ -0,0 +1,69 @@
+package session
+
+import (
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/capacity"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+)
+
+// Forecast 최근 세션 기록(프로파일별 시간당 수요)으로 앞으로 horizonHours 동안의 수요와 소진 시각 예측
+// 용량은 what-if와 같은 기준(준비된 노드의 전체 MIG 인스턴스 수), 시간대는 배포 시간대를 사용
+func (s *Service) Forecast(lookbackDays, horizonHours int, profile string) (*capacity.Forecast, error) {
+	if err := capacity.ValidateForecastRange(lookbackDays, horizonHours); err != nil {
+		return nil, errs.Wrap(errs.ErrInvalid, err)
+	}
+
+	now := time.Now()
+	in := capacity.ForecastInput{
+		Capacity:     make(map[string]int),
+		Now:          now,
+		Location:     s.config.Current().Location(),
+		LookbackDays: lookbackDays,
+		HorizonHours: horizonHours,
+	}
+
+	snapshot, err := s.capacitySnapshot()
+	if err != nil {
+		return nil, err
+	}
+	for _, profiles := range snapshot.Capacity {
+		for name, count := range profiles {
+			in.Capacity[name] += count
+		}
+	}
+
+	// 종료된 세션은 사용량 기록에서, 실행 중인 세션은 세션 목록에서 (사용량 기록과 겹치지 않게)
+	usages, err := s.store.ListSessionUsage("", "")
+	if err != nil {
+		return nil, err
+	}
+	since := now.AddDate(0, 0, -lookbackDays)
+	for _, usage := range usages {
+		if usage.EndedAt == nil || usage.EndedAt.Before(since) {
+			continue
+		}
+		in.History = append(in.History, capacity.Occupancy{Profile: usage.MIGProfile, Start: usage.StartedAt, End: *usage.EndedAt})
+	}
+
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		return nil, err
+	}
+	for _, session := range sessions {
+		in.History = append(in.History, capacity.Occupancy{Profile: session.MIGProfile, Start: session.CreatedAt, End: now})
+		in.Committed = append(in.Committed, capacity.Occupancy{Profile: session.MIGProfile, Start: now, End: session.ExpiresAt})
+	}
+
+	forecast, err := capacity.BuildForecast(in)
+	if err != nil {
+		return nil, errs.Wrap(errs.ErrInvalid, err)
+	}
+	if profile != "" {
+		forecast = forecast.ForProfile(profile)
+		if len(forecast.Profiles) == 0 {
+			return nil, errs.New(errs.ErrNotFound, "프로파일 %s의 용량이나 수요 기록이 없습니다", profile)
+		}
+	}
+	return forecast, nil
+}
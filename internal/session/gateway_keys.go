@@ -0,0 +1,65 @@
+package session
+
+import (
+	"log"
+	"strings"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// metaGatewayKeys 게이트웨이(sshpiperd)에서 받는 사용자 공개키 (정규화한 authorized_keys 줄을 줄바꿈으로 이음)
+const metaGatewayKeys = "gateway_authorized_keys"
+
+// maxGatewayKeys 세션 하나에 등록할 수 있는 공개키 수
+const maxGatewayKeys = 10
+
+// checkGatewayKeys 요청한 공개키를 검증해 정규화된 줄로 바꿈 (라우트 게시가 없으면 쓸 곳이 없으므로 거부)
+func (s *Service) checkGatewayKeys(req *CreateRequest) error {
+	if len(req.SSHPublicKeys) == 0 {
+		return nil
+	}
+	if s.router == nil {
+		return errs.New(errs.ErrConflict, "ssh_public_keys는 SSHPiper 라우트 게시(-sshpiper-config)가 설정된 경우에만 쓸 수 있습니다")
+	}
+	if len(req.SSHPublicKeys) > maxGatewayKeys {
+		return errs.New(errs.ErrInvalid, "ssh_public_keys는 최대 %d개입니다", maxGatewayKeys)
+	}
+	keys := make([]string, 0, len(req.SSHPublicKeys))
+	for _, input := range req.SSHPublicKeys {
+		info, err := ValidatePublicKey(input)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, info.KeyLine)
+	}
+	req.SSHPublicKeys = keys
+	return nil
+}
+
+// gatewayKeys 세션에 등록된 게이트웨이 공개키 (없으면 nil, 비밀번호 전달 라우트)
+func gatewayKeys(session *store.Session) []string {
+	if session.Metadata[metaGatewayKeys] == "" {
+		return nil
+	}
+	return strings.Split(session.Metadata[metaGatewayKeys], "\n")
+}
+
+// installGatewayKey sshpiperd가 대상 sshd에 로그인할 세션 개인키를 키 파일로 저장
+// 공개키 라우트를 쓰지 않는 세션이면 아무것도 하지 않음
+func (s *Service) installGatewayKey(session *store.Session, privateKey string) error {
+	if s.router == nil || len(gatewayKeys(session)) == 0 {
+		return nil
+	}
+	return s.router.WriteKey(session.ID, privateKey)
+}
+
+// removeGatewayKey 세션을 정리할 때 키 파일 삭제
+func (s *Service) removeGatewayKey(session *store.Session) {
+	if s.router == nil || len(gatewayKeys(session)) == 0 {
+		return
+	}
+	if err := s.router.RemoveKey(session.ID); err != nil {
+		log.Printf("⚠️ %v", err)
+	}
+}
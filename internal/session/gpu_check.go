@@ -0,0 +1,29 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+)
+
+var gpuAttachmentFailures = metrics.NewCounter("sandman_gpu_attachment_failures_total", "컨테이너 안에서 요청한 GPU 장치가 보이지 않아 실패한 세션 생성 수")
+
+// verifyGPUAttachment 컨테이너 안에서 nvidia-smi -L로 할당한 장치 UUID가 보이는지 확인 (CPU 전용 세션은 생략)
+func (s *Service) verifyGPUAttachment(containerID, gpuUUID string, cfg *config.Config) error {
+	if !cfg.VerifyGPUAttachment || gpuUUID == "" {
+		return nil
+	}
+
+	timeout := time.Duration(cfg.ProvisioningTimeouts.GPUVerifySeconds) * time.Second
+	if err := s.dockerClient.VerifyGPUAttachment(containerID, gpuUUID, timeout); err != nil {
+		gpuAttachmentFailures.Inc()
+		log.Printf("🚨 GPU 장치 확인 실패: %s (컨테이너: %s): %v", gpuUUID, containerID[:12], err)
+		return fmt.Errorf("GPU 장치 연결 확인 실패: %v", err)
+	}
+
+	log.Printf("✅ GPU 장치 확인 완료: %s (컨테이너: %s)", gpuUUID, containerID[:12])
+	return nil
+}
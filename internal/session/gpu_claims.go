@@ -0,0 +1,123 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+	"github.com/sandman/gpu-ssh-gateway/internal/lock"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// staleClaimAge 세션 기록이 없는 할당 기록을 버려진 것으로 보는 시간 (다른 복제본이 아직 세션을 만드는 중일 수 있어 넉넉히)
+const staleClaimAge = time.Hour
+
+// gpuClaimState 다른 복제본의 할당 기록을 보고 사용 중으로 표시한 인스턴스
+type gpuClaimState struct {
+	mu      sync.Mutex
+	adopted map[string]bool
+}
+
+// syncGPUClaims 저장소의 할당 기록을 GPU 매니저에 반영 (gpu-allocation 락을 잡은 직후 호출)
+// 다른 복제본이 할당한 인스턴스는 사용 중으로 표시하고, 그 기록이 지워졌으면 다시 비움
+func (s *Service) syncGPUClaims() error {
+	claims, err := s.store.ListGPUClaims(s.nodeID)
+	if err != nil {
+		return fmt.Errorf("GPU 할당 기록 조회 실패: %w", err)
+	}
+	claimed := make(map[string]bool, len(claims))
+	for _, claim := range claims {
+		claimed[claim.InstanceUUID] = true
+	}
+
+	s.gpuClaims.mu.Lock()
+	defer s.gpuClaims.mu.Unlock()
+	if s.gpuClaims.adopted == nil {
+		s.gpuClaims.adopted = map[string]bool{}
+	}
+	for id := range s.gpuClaims.adopted {
+		if claimed[id] {
+			continue
+		}
+		if instance, ok := s.gpuManager.Instance(id); ok && instance.InUse {
+			s.gpuManager.ReleaseMIG(id, instance.CreatedBy)
+		}
+		delete(s.gpuClaims.adopted, id)
+	}
+	for _, claim := range claims {
+		instance, ok := s.gpuManager.Instance(claim.InstanceUUID)
+		if !ok || instance.InUse {
+			continue
+		}
+		if _, err := s.gpuManager.AllocateMIGByUUID(claim.InstanceUUID, claim.UserID); err != nil {
+			log.Printf("⚠️ GPU 할당 기록 반영 실패: %s (세션 %s): %v", claim.InstanceUUID, claim.SessionID, err)
+			continue
+		}
+		s.gpuClaims.adopted[claim.InstanceUUID] = true
+	}
+	return nil
+}
+
+// claimGPU 할당한 인스턴스를 저장소에 기록 (락을 잃었으면 기록하지 않고 에러, 호출자가 인스턴스를 내놓음)
+func (s *Service) claimGPU(lease *lock.Lease, instance *gpu.MIGInstance, sessionID, userID string) error {
+	if instance.UUID == "" {
+		return nil
+	}
+	if err := lease.Check(); err != nil {
+		return errs.New(errs.ErrConflict, "GPU 인스턴스 %s 할당 중 락을 잃었습니다: %v", instance.UUID, err)
+	}
+	err := s.store.ClaimGPU(&store.GPUClaim{
+		InstanceUUID: instance.UUID,
+		NodeID:       s.nodeID,
+		SessionID:    sessionID,
+		UserID:       userID,
+		ClaimedAt:    time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	s.gpuClaims.mu.Lock()
+	delete(s.gpuClaims.adopted, instance.UUID)
+	s.gpuClaims.mu.Unlock()
+	return nil
+}
+
+// releaseMIG 세션의 인스턴스를 내놓고 할당 기록을 지움
+func (s *Service) releaseMIG(sessionID, instanceUUID, userID string) error {
+	if err := s.gpuManager.ReleaseMIG(instanceUUID, userID); err != nil {
+		return err
+	}
+	if instanceUUID == "" {
+		return nil
+	}
+	if err := s.store.ReleaseGPUClaim(instanceUUID, sessionID); err != nil {
+		log.Printf("⚠️ GPU 할당 기록 삭제 실패: %s: %v", instanceUUID, err)
+	}
+	return nil
+}
+
+// pruneGPUClaims 세션 기록이 없는 오래된 할당 기록 삭제 (세션을 만들다 죽은 복제본이 남긴 것)
+func (s *Service) pruneGPUClaims(sessions []*store.Session) {
+	claims, err := s.store.ListGPUClaims(s.nodeID)
+	if err != nil {
+		log.Printf("⚠️ GPU 할당 기록 조회 실패: %v", err)
+		return
+	}
+	live := make(map[string]bool, len(sessions))
+	for _, session := range sessions {
+		live[session.ID] = true
+	}
+	for _, claim := range claims {
+		if live[claim.SessionID] || time.Since(claim.ClaimedAt) < staleClaimAge {
+			continue
+		}
+		if err := s.store.ReleaseGPUClaim(claim.InstanceUUID, claim.SessionID); err != nil {
+			log.Printf("⚠️ GPU 할당 기록 삭제 실패: %s: %v", claim.InstanceUUID, err)
+			continue
+		}
+		log.Printf("🧹 버려진 GPU 할당 기록 삭제: %s (세션 %s)", claim.InstanceUUID, claim.SessionID)
+	}
+}
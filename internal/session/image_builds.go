@@ -0,0 +1,105 @@
+package session
+
+import (
+	"log"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// ImageBuildSummary 템플릿별 이미지 빌드 집계 (성공한 빌드 기준, 실패는 sandman_image_builds_total 메트릭에만 남음)
+type ImageBuildSummary struct {
+	Template     string  `json:"template"`
+	Builds       int     `json:"builds"`
+	AvgSeconds   float64 `json:"avg_seconds"`
+	P50Seconds   float64 `json:"p50_seconds"`
+	P95Seconds   float64 `json:"p95_seconds"`
+	Steps        int     `json:"steps"`
+	CachedSteps  int     `json:"cached_steps"`
+	CacheHitRate float64 `json:"cache_hit_rate"`
+	AvgSizeBytes int64   `json:"avg_size_bytes"`
+}
+
+// recordImageBuild 세션 컨테이너의 이미지 빌드 기록 저장 (실패해도 세션 생성은 계속)
+func (s *Service) recordImageBuild(session *store.Session, build *docker.BuildStats) {
+	if build == nil {
+		return
+	}
+	record := &store.ImageBuild{
+		SessionID:   session.ID,
+		UserID:      build.UserID,
+		ProjectID:   session.ProjectID,
+		Template:    build.Template,
+		BaseImage:   build.BaseImage,
+		Image:       build.Image,
+		StartedAt:   build.StartedAt,
+		DurationMs:  build.Duration.Milliseconds(),
+		Steps:       build.Steps,
+		CachedSteps: build.CachedSteps,
+		SizeBytes:   build.SizeBytes,
+	}
+	if err := s.store.SaveImageBuild(record); err != nil {
+		log.Printf("⚠️ 세션 %s 이미지 빌드 기록 저장 실패: %v", session.ID, err)
+	}
+}
+
+// GetImageBuild 세션 컨테이너를 만들 때의 이미지 빌드 기록
+func (s *Service) GetImageBuild(sessionID string) (*store.ImageBuild, error) {
+	return s.store.GetImageBuild(sessionID)
+}
+
+// SummarizeImageBuilds since 이후 빌드를 템플릿별로 집계 (템플릿 이름순)
+func (s *Service) SummarizeImageBuilds(since time.Time) ([]*ImageBuildSummary, error) {
+	builds, err := s.store.ListImageBuilds(since)
+	if err != nil {
+		return nil, err
+	}
+
+	durations := make(map[string][]float64)
+	summaries := make(map[string]*ImageBuildSummary)
+	sizes := make(map[string]int64)
+	for _, b := range builds {
+		summary, ok := summaries[b.Template]
+		if !ok {
+			summary = &ImageBuildSummary{Template: b.Template}
+			summaries[b.Template] = summary
+		}
+		summary.Builds++
+		summary.Steps += b.Steps
+		summary.CachedSteps += b.CachedSteps
+		sizes[b.Template] += b.SizeBytes
+		durations[b.Template] = append(durations[b.Template], float64(b.DurationMs)/1000)
+	}
+
+	result := make([]*ImageBuildSummary, 0, len(summaries))
+	for template, summary := range summaries {
+		seconds := durations[template]
+		sort.Float64s(seconds)
+		total := 0.0
+		for _, d := range seconds {
+			total += d
+		}
+		summary.AvgSeconds = total / float64(len(seconds))
+		summary.P50Seconds = percentile(seconds, 0.5)
+		summary.P95Seconds = percentile(seconds, 0.95)
+		if summary.Steps > 0 {
+			summary.CacheHitRate = float64(summary.CachedSteps) / float64(summary.Steps)
+		}
+		summary.AvgSizeBytes = sizes[template] / int64(summary.Builds)
+		result = append(result, summary)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Template < result[j].Template })
+	return result, nil
+}
+
+// percentile 정렬된 값의 nearest-rank 백분위수
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return sorted[max(rank, 0)]
+}
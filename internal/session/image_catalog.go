@@ -0,0 +1,88 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// catalogNamePattern 카탈로그 이미지 이름 (설정의 이미지별 항목 키와 세션 template 메타데이터로도 쓰임)
+var catalogNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,63}$`)
+
+// CatalogImageRequest 이미지 등록/교체 요청
+type CatalogImageRequest struct {
+	BaseImage   string `json:"base_image" binding:"required"` // 워크스페이스 이미지의 FROM (repo:tag 또는 repo@sha256:...)
+	CUDAVersion string `json:"cuda_version"`
+	Description string `json:"description"`
+}
+
+func (s *Service) ListCatalogImages() ([]*store.CatalogImage, error) {
+	return s.store.ListCatalogImages()
+}
+
+// RegisterCatalogImage 세션에서 쓸 수 있는 이미지 등록 (이미 있으면 내용 교체, 실행 중인 세션에는 영향 없음)
+func (s *Service) RegisterCatalogImage(name string, req CatalogImageRequest) (*store.CatalogImage, error) {
+	if name == docker.DefaultImage {
+		return nil, errs.New(errs.ErrInvalid, "기본 이미지 %s는 등록하지 않아도 항상 쓸 수 있습니다", docker.DefaultImage)
+	}
+	if !catalogNamePattern.MatchString(name) {
+		return nil, errs.New(errs.ErrInvalid, "이미지 이름은 영문 소문자, 숫자, '.', '_', '-'로 64자 이내여야 합니다: %q", name)
+	}
+	if req.BaseImage == "" || strings.ContainsAny(req.BaseImage, " \t\n") {
+		return nil, errs.New(errs.ErrInvalid, "base_image가 잘못되었습니다: %q", req.BaseImage)
+	}
+	if req.CUDAVersion != "" && !gpu.ValidVersion(req.CUDAVersion) {
+		return nil, errs.New(errs.ErrInvalid, "cuda_version이 잘못되었습니다: %q", req.CUDAVersion)
+	}
+
+	now := time.Now()
+	img := &store.CatalogImage{
+		Name:        name,
+		BaseImage:   req.BaseImage,
+		CUDAVersion: req.CUDAVersion,
+		Description: req.Description,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if existing, err := s.store.GetCatalogImage(name); err == nil {
+		img.CreatedAt = existing.CreatedAt
+	}
+	if err := s.store.SaveCatalogImage(img); err != nil {
+		return nil, fmt.Errorf("이미지 카탈로그 저장 실패: %v", err)
+	}
+	log.Printf("🖼️ 이미지 카탈로그 등록: %s (베이스: %s)", img.Name, img.BaseImage)
+	s.audit("admin", "image.registered", img.Name, fmt.Sprintf("base_image=%s cuda_version=%s", img.BaseImage, img.CUDAVersion))
+	return img, nil
+}
+
+// DeregisterCatalogImage 카탈로그에서 이미지 제거 (이후 그 이미지로는 세션을 만들 수 없음)
+func (s *Service) DeregisterCatalogImage(name string) error {
+	if err := s.store.DeleteCatalogImage(name); err != nil {
+		return fmt.Errorf("카탈로그에 이미지 %s가 없습니다: %w", name, err)
+	}
+	log.Printf("🖼️ 이미지 카탈로그 제거: %s", name)
+	s.audit("admin", "image.deregistered", name, "")
+	return nil
+}
+
+// catalogImage 요청한 이미지의 카탈로그 항목 (기본 이미지는 nil, 등록되지 않은 이미지는 ErrInvalid)
+func (s *Service) catalogImage(image string) (*store.CatalogImage, error) {
+	if image == docker.DefaultImage {
+		return nil, nil
+	}
+	img, err := s.store.GetCatalogImage(image)
+	if errors.Is(err, errs.ErrNotFound) {
+		return nil, errs.New(errs.ErrInvalid, "이미지 %s는 카탈로그에 없습니다 (GET /images로 사용 가능한 이미지 확인)", image)
+	} else if err != nil {
+		return nil, fmt.Errorf("이미지 카탈로그 조회 실패: %v", err)
+	}
+	return img, nil
+}
@@ -0,0 +1,191 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+var imageChannelRollbacks = metrics.NewCounter("sandman_image_channel_rollbacks_total", "세션 실패율 급증으로 자동 롤백된 이미지 채널 수", "channel")
+
+// ImageChannelUpdate 채널 변경 요청 (생략한 필드는 유지)
+type ImageChannelUpdate struct {
+	BaseImage      *string `json:"base_image"`
+	RolloutPercent *int    `json:"rollout_percent"`
+}
+
+func (s *Service) ListImageChannels() ([]*store.ImageChannel, error) {
+	return s.store.ListImageChannels()
+}
+
+// UpdateImageChannel 채널의 베이스 이미지나 롤아웃 비율 변경
+// 베이스 이미지가 바뀌면 이전 이미지를 롤백 대상으로 보관하고 실패율 집계를 새로 시작
+func (s *Service) UpdateImageChannel(name string, update ImageChannelUpdate) (*store.ImageChannel, error) {
+	if name != store.ChannelStable && name != store.ChannelNext {
+		return nil, errs.New(errs.ErrInvalid, "알 수 없는 이미지 채널입니다: %q (stable, next)", name)
+	}
+	if update.RolloutPercent != nil {
+		if name != store.ChannelNext {
+			return nil, errs.New(errs.ErrInvalid, "rollout_percent는 next 채널에만 설정할 수 있습니다")
+		}
+		if *update.RolloutPercent < 0 || *update.RolloutPercent > 100 {
+			return nil, errs.New(errs.ErrInvalid, "rollout_percent는 0~100이어야 합니다: %d", *update.RolloutPercent)
+		}
+	}
+
+	ch, err := s.store.GetImageChannel(name)
+	if errors.Is(err, errs.ErrNotFound) {
+		ch = &store.ImageChannel{Name: name}
+	} else if err != nil {
+		return nil, err
+	}
+
+	if update.BaseImage != nil && *update.BaseImage != ch.BaseImage {
+		ch.PreviousBaseImage = ch.BaseImage
+		ch.BaseImage = *update.BaseImage
+		ch.Attempts, ch.Failures = 0, 0
+		ch.RolledBackAt = nil
+	}
+	if update.RolloutPercent != nil {
+		ch.RolloutPercent = *update.RolloutPercent
+		if ch.RolloutPercent > 0 {
+			ch.RolledBackAt = nil
+		}
+	}
+	ch.UpdatedAt = time.Now()
+
+	if err := s.store.SaveImageChannel(ch); err != nil {
+		return nil, fmt.Errorf("이미지 채널 저장 실패: %v", err)
+	}
+	log.Printf("🖼️ 이미지 채널 변경: %s (이미지: %q, 롤아웃: %d%%)", ch.Name, ch.BaseImage, ch.RolloutPercent)
+	s.audit("admin", "image_channel.updated", ch.Name, fmt.Sprintf("base_image=%s rollout_percent=%d", ch.BaseImage, ch.RolloutPercent))
+	return ch, nil
+}
+
+func (s *Service) ListImagePins() ([]*store.ImagePin, error) {
+	return s.store.ListImagePins()
+}
+
+// PinImageChannel 사용자("user:<id>") 또는 프로젝트 코호트("project:<id>")를 채널에 고정
+func (s *Service) PinImageChannel(subject, channel string) (*store.ImagePin, error) {
+	kind, id, _ := strings.Cut(subject, ":")
+	if (kind != "user" && kind != "project") || id == "" {
+		return nil, errs.New(errs.ErrInvalid, "고정 대상은 user:<id> 또는 project:<id> 형식이어야 합니다: %q", subject)
+	}
+	if channel != store.ChannelStable && channel != store.ChannelNext {
+		return nil, errs.New(errs.ErrInvalid, "알 수 없는 이미지 채널입니다: %q (stable, next)", channel)
+	}
+
+	pin := &store.ImagePin{Subject: subject, Channel: channel, CreatedAt: time.Now()}
+	if err := s.store.SaveImagePin(pin); err != nil {
+		return nil, err
+	}
+	s.audit("admin", "image_channel.pinned", subject, "channel="+channel)
+	return pin, nil
+}
+
+func (s *Service) UnpinImageChannel(subject string) error {
+	if err := s.store.DeleteImagePin(subject); err != nil {
+		return fmt.Errorf("고정 %s를 찾을 수 없습니다: %w", subject, err)
+	}
+	s.audit("admin", "image_channel.unpinned", subject, "")
+	return nil
+}
+
+// selectImageChannel 새 세션의 이미지 채널 결정 (채널이 하나도 없으면 nil)
+// 우선순위: 사용자 고정 > 프로젝트 고정 > next 롤아웃 비율(사용자별로 고정된 해시) > stable
+func (s *Service) selectImageChannel(userID, projectID string) *store.ImageChannel {
+	subjects := []string{"user:" + userID}
+	if projectID != "" {
+		subjects = append(subjects, "project:"+projectID)
+	}
+	for _, subject := range subjects {
+		if pin, err := s.store.GetImagePin(subject); err == nil {
+			if ch, err := s.store.GetImageChannel(pin.Channel); err == nil {
+				return ch
+			}
+		}
+	}
+
+	if next, err := s.store.GetImageChannel(store.ChannelNext); err == nil && next.RolloutPercent > 0 {
+		if rolloutBucket(userID) < next.RolloutPercent {
+			return next
+		}
+	}
+	if stable, err := s.store.GetImageChannel(store.ChannelStable); err == nil {
+		return stable
+	}
+	return nil
+}
+
+// rolloutBucket 사용자를 0~99 구간에 고르게 배정 (같은 사용자는 항상 같은 값)
+func rolloutBucket(userID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(userID))
+	return int(h.Sum32() % 100)
+}
+
+// recordImageChannelOutcome 세션 생성 결과를 누적하고, 실패율이 기준을 넘으면 채널을 롤백
+func (s *Service) recordImageChannelOutcome(name string, failed bool) {
+	if err := s.store.RecordImageChannelOutcome(name, failed); err != nil {
+		log.Printf("⚠️ 이미지 채널 %s 결과 기록 실패: %v", name, err)
+		return
+	}
+	if !failed {
+		return
+	}
+
+	cfg := s.config.Current()
+	if cfg.ImageRollbackFailureRate <= 0 {
+		return
+	}
+
+	// 여러 복제본이 같은 채널을 동시에 롤백하지 않도록 직렬화
+	err := s.locker.WithLock("image-channels", func() error {
+		ch, err := s.store.GetImageChannel(name)
+		if err != nil {
+			return err
+		}
+		if ch.RolledBackAt != nil || ch.Attempts < cfg.ImageRollbackMinSessions {
+			return nil
+		}
+		rate := float64(ch.Failures) / float64(ch.Attempts)
+		if rate <= cfg.ImageRollbackFailureRate {
+			return nil
+		}
+		return s.rollbackImageChannel(ch, rate)
+	})
+	if err != nil {
+		log.Printf("⚠️ 이미지 채널 %s 롤백 확인 실패: %v", name, err)
+	}
+}
+
+// rollbackImageChannel 이전 베이스 이미지로 되돌리고 next 채널이면 롤아웃을 멈춤
+func (s *Service) rollbackImageChannel(ch *store.ImageChannel, rate float64) error {
+	failedImage := ch.BaseImage
+	if ch.PreviousBaseImage != "" {
+		ch.BaseImage, ch.PreviousBaseImage = ch.PreviousBaseImage, ""
+	}
+	if ch.Name == store.ChannelNext {
+		ch.RolloutPercent = 0
+	}
+	now := time.Now()
+	ch.RolledBackAt = &now
+	if err := s.store.SaveImageChannel(ch); err != nil {
+		return err
+	}
+
+	imageChannelRollbacks.Inc(ch.Name)
+	log.Printf("🚨 이미지 채널 %s 자동 롤백: 세션 실패율 %.0f%% (%d/%d), %q → %q",
+		ch.Name, rate*100, ch.Failures, ch.Attempts, failedImage, ch.BaseImage)
+	s.audit("image-rollout", "image_channel.rolled_back", ch.Name,
+		fmt.Sprintf("failure_rate=%.2f attempts=%d failed_image=%s base_image=%s", rate, ch.Attempts, failedImage, ch.BaseImage))
+	return nil
+}
@@ -0,0 +1,142 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+var sessionImageChanges = metrics.NewCounter("sandman_session_image_changes_total", "세션 이미지 교체 수 (result: changed, failed)", "result")
+
+// ChangeSessionImage 세션 컨테이너를 다른 카탈로그 이미지로 다시 만듦
+// 워크스페이스, GPU 인스턴스, SSH 포트, IP, 라우트는 그대로이고 SSH 키와 비밀번호, CA, 협업자 키는 새 컨테이너에 다시 넣음
+// 멈춘 세션(POST /sessions/:id/stop)도 바꿀 수 있으며 새 이미지로 시작됨
+func (s *Service) ChangeSessionImage(sessionID, actor, image string) (*store.Session, error) {
+	if image == "" {
+		return nil, errs.New(errs.ErrInvalid, "image가 필요합니다")
+	}
+
+	done, err := s.beginOperation()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	var changed *store.Session
+	err = s.locker.WithLock("session-lock:"+sessionID, func() error {
+		session, err := s.localSession(sessionID)
+		if err != nil {
+			return err
+		}
+		if session.State != store.SessionRunning && session.State != store.SessionStopped {
+			return errs.New(errs.ErrConflict, "세션 %s는 %s 상태라 이미지를 바꿀 수 없습니다", session.ID, session.State)
+		}
+		previous := session.Metadata["template"]
+		if previous == "" {
+			previous = docker.DefaultImage
+		}
+		if image == previous {
+			return errs.New(errs.ErrConflict, "세션 %s는 이미 이미지 %s를 쓰고 있습니다", session.ID, image)
+		}
+		publicKey := session.Metadata["ssh_public_key"]
+		if publicKey == "" {
+			return errs.New(errs.ErrConflict, "세션 %s에 SSH 공개키 기록이 없어 이미지를 다시 빌드할 수 없습니다", session.ID)
+		}
+
+		// 카탈로그에 없거나 이 인스턴스/드라이버와 맞지 않는 이미지는 컨테이너를 건드리기 전에 거부
+		cfg := s.config.Current()
+		catalog, err := s.catalogImage(image)
+		if err != nil {
+			return err
+		}
+		if err := s.checkImageCompatibility(image, catalog, session.MIGProfile, cfg); err != nil {
+			return err
+		}
+		var channel *store.ImageChannel
+		baseImage := ""
+		if image == docker.DefaultImage {
+			if channel = s.selectImageChannel(session.UserID, session.ProjectID); channel != nil {
+				baseImage = channel.BaseImage
+			}
+		} else {
+			baseImage = catalog.BaseImage
+		}
+
+		releaseSlot, err := s.acquireNodeSlot(cfg)
+		if err != nil {
+			return err
+		}
+		info, err := s.dockerClient.RecreateContainerWithImage(session.ContainerID, docker.ImageOverride{
+			UserID:    session.UserID,
+			Template:  image,
+			BaseImage: baseImage,
+			PublicKey: publicKey,
+			Timeout:   stepTimeouts(cfg).ImageBuild,
+			Stop:      stopOptions(session, cfg),
+		})
+		releaseSlot()
+		if channel != nil {
+			s.recordImageChannelOutcome(channel.Name, err != nil)
+		}
+		if err != nil {
+			sessionImageChanges.Inc("failed")
+			return fmt.Errorf("이미지 교체 실패: %w", err)
+		}
+
+		previousContainer := session.ContainerID
+		session.ContainerID = info.ID
+		if info.IP != "" {
+			session.ContainerIP = info.IP
+		}
+		session.State = store.SessionRunning
+		delete(session.Metadata, metaStoppedAt)
+		delete(session.Metadata, metaStopHoldUntil)
+		session.Metadata["image"] = info.Image
+		session.Metadata["template"] = image
+		delete(session.Metadata, "image_channel")
+		delete(session.Metadata, "base_image")
+		if channel != nil {
+			session.Metadata["image_channel"] = channel.Name
+			session.Metadata["base_image"] = channel.BaseImage
+		} else if catalog != nil {
+			session.Metadata["base_image"] = baseImage
+		}
+		if err := s.store.UpdateSession(session); err != nil {
+			return fmt.Errorf("세션 갱신 실패: %w", err)
+		}
+
+		// 컨테이너 안에만 있던 접근 설정 복구
+		if err := s.installSSHCA(session); err != nil {
+			log.Printf("⚠️ 세션 %s SSH CA 재설치 실패: %v", session.ID, err)
+		}
+		s.reapplyAccess(session)
+		if err := s.provisionWorkspaceSSH(session); err != nil {
+			log.Printf("⚠️ 세션 %s 워크스페이스 .ssh 재구성 실패: %v", session.ID, err)
+		}
+		if err := s.verifyGPUAttachment(session.ContainerID, session.GPUUUID, cfg); err != nil {
+			log.Printf("⚠️ 세션 %s GPU 확인 실패: %v", session.ID, err)
+		}
+		s.recordImageBuild(session, info.Build)
+		s.captureBaseline(session)
+		s.markActive(session.ID, time.Now())
+		s.publishRoute(session)
+		s.liveness.put(s.probeLiveness(session, nil))
+
+		sessionImageChanges.Inc("changed")
+		detail := fmt.Sprintf("from=%s to=%s", previous, image)
+		s.audit(actor, "session.image_changed", session.ID, fmt.Sprintf("user=%s %s previous_container=%s", session.UserID, detail, previousContainer))
+		s.recordEvent(session, EventImage, actor, "", detail)
+		log.Printf("🖼️ 세션 이미지 교체: %s (%s → %s, 컨테이너 %s → %s)", session.ID, previous, image, previousContainer[:12], session.ContainerID[:12])
+		changed = session
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
@@ -0,0 +1,426 @@
+package session
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// EndReasonIdle 잠금 후 hold_minutes 안에 다시 접속하지 않아 GPU를 회수함
+const EndReasonIdle = "idle"
+
+// 잠금 상태를 담는 세션 메타데이터 (RFC3339)
+const (
+	metaLockedAt     = "locked_at"
+	metaReclaimAt    = "reclaim_at"    // hold_minutes가 0이면 없음
+	metaLastActivity = "last_activity" // 마지막으로 SSH 연결이나 CPU 사용을 확인한 시각
+)
+
+const (
+	// wakeLinger 재개 후에도 깨우기 리스너를 유지하는 시간 (SSHPiper가 원래 라우트를 읽기 전에 들어온 연결 처리)
+	wakeLinger = time.Minute
+	// wakeDialTimeout 재개한 컨테이너 sshd에 연결을 기다리는 최대 시간
+	wakeDialTimeout = 10 * time.Second
+	// activityPersistInterval last_activity를 저장소에 다시 쓰는 최소 간격 (확인할 때마다 쓰지 않도록)
+	activityPersistInterval = 5 * time.Minute
+)
+
+var (
+	inactivityActions = metrics.NewCounter("sandman_inactivity_lock_total", "비활성 잠금 처리 수 (action: locked, unlocked, reclaimed)", "action")
+	sessionsLocked    = metrics.NewGauge("sandman_sessions_locked", "이 노드에서 비활성으로 일시 정지된 세션 수")
+)
+
+// inactivityTracker 세션별 마지막 활동 시각과 CPU 누적 사용량, 잠긴 세션의 깨우기 리스너
+// 마지막 활동 시각은 세션 메타데이터 last_activity에도 남겨 재시작 후 이어서 셈 (최대 activityPersistInterval만큼 늦음)
+type inactivityTracker struct {
+	mu         sync.Mutex
+	lastActive map[string]time.Time
+	lastCPU    map[string]cpuSample
+	wakers     map[string]net.Listener
+}
+
+type cpuSample struct {
+	nanos uint64
+	at    time.Time
+}
+
+func newInactivityTracker() *inactivityTracker {
+	return &inactivityTracker{
+		lastActive: make(map[string]time.Time),
+		lastCPU:    make(map[string]cpuSample),
+		wakers:     make(map[string]net.Listener),
+	}
+}
+
+// SetWakeHost 잠긴 세션의 깨우기 리스너를 열 주소 (SSHPiper가 접속할 수 있어야 함, 비어 있으면 SSH 연결로 재개하지 않음)
+func (s *Service) SetWakeHost(host string) {
+	s.wakeHost = host
+}
+
+// CheckInactivity 이 노드 세션의 활동을 확인해 idle_minutes가 지나면 잠그고, hold_minutes가 지난 잠긴 세션은 정리
+func (s *Service) CheckInactivity() error {
+	done, err := s.beginOperation()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	// 같은 노드의 복제본 중 하나만 처리
+	lease, err := s.locker.TryAcquire("inactivity:" + s.localNodeName())
+	if err != nil {
+		return err
+	}
+	if lease == nil {
+		logging.Debugf("다른 복제본이 비활성 세션 확인 중 - 건너뜀")
+		return nil
+	}
+	defer lease.Release()
+
+	cfg := s.config.Current().InactivityLock
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool, len(sessions))
+	locked := 0
+	for _, session := range sessions {
+		if session.NodeID != "" && session.NodeID != s.nodeID {
+			continue
+		}
+		seen[session.ID] = true
+
+		switch session.State {
+		case store.SessionLocked:
+			if reclaimAt, err := time.Parse(time.RFC3339, session.Metadata[metaReclaimAt]); err == nil && now.After(reclaimAt) {
+				log.Printf("💤 잠긴 세션 GPU 회수: %s (사용자: %s)", session.ID, session.UserID)
+				if err := s.cleanupSession(session, EndReasonIdle); err != nil {
+					log.Printf("⚠️ 잠긴 세션 정리 실패: %v", err)
+					continue
+				}
+				inactivityActions.Inc("reclaimed")
+				s.audit("system", "session.idle_reclaimed", session.ID, "user="+session.UserID)
+				continue
+			}
+			locked++
+			s.ensureWaker(session)
+		case store.SessionStopped:
+			s.releaseStoppedSession(session, now)
+		case store.SessionRunning:
+			if cfg.IdleMinutes == 0 {
+				continue
+			}
+			if s.sessionActive(session, cfg, now) {
+				continue
+			}
+			if now.Sub(s.lastActive(session, now)) < time.Duration(cfg.IdleMinutes)*time.Minute {
+				continue
+			}
+			if err := s.lockSession(session, cfg, now); err != nil {
+				log.Printf("⚠️ 세션 %s 잠금 실패: %v", session.ID, err)
+				continue
+			}
+			locked++
+		}
+	}
+	sessionsLocked.Set(float64(locked))
+	s.pruneInactivity(seen)
+	return nil
+}
+
+// sessionActive SSH 연결이 있거나 CPU 사용률이 cpu_active_percent 이상이면 활동 중으로 기록
+// 확인에 실패하면 잘못 잠그지 않도록 활동 중으로 봄
+func (s *Service) sessionActive(session *store.Session, cfg config.InactivityLock, now time.Time) bool {
+	conns, cpuNanos, err := s.dockerClient.ContainerActivity(session.ContainerID)
+	if err != nil {
+		logging.Debugf("세션 %s 활동 확인 실패: %v", session.ID, err)
+		s.markActive(session.ID, now)
+		return true
+	}
+
+	t := s.inactivity
+	t.mu.Lock()
+	prev, hasPrev := t.lastCPU[session.ID]
+	t.lastCPU[session.ID] = cpuSample{nanos: cpuNanos, at: now}
+	t.mu.Unlock()
+
+	busy := false
+	if hasPrev && cpuNanos >= prev.nanos {
+		if elapsed := now.Sub(prev.at); elapsed > 0 {
+			percent := float64(cpuNanos-prev.nanos) / float64(elapsed.Nanoseconds()) * 100
+			busy = percent >= cfg.CPUActivePercent
+		}
+	}
+
+	if conns > 0 || busy {
+		s.markActive(session.ID, now)
+		s.persistActivity(session, now)
+		return true
+	}
+	if !hasPrev {
+		// 첫 샘플은 CPU 사용률을 알 수 없으므로 저장된 last_activity가 없을 때만 지금부터 셈
+		s.lastActive(session, now)
+		return true
+	}
+	return false
+}
+
+// persistActivity 저장된 last_activity가 activityPersistInterval보다 오래되었으면 갱신
+func (s *Service) persistActivity(session *store.Session, now time.Time) {
+	if saved, err := time.Parse(time.RFC3339, session.Metadata[metaLastActivity]); err == nil && now.Sub(saved) < activityPersistInterval {
+		return
+	}
+	// 목록을 읽은 뒤 바뀐 필드(TTL 연장 등)를 덮어쓰지 않도록 다시 읽어서 기록
+	err := s.locker.WithLock("session-lock:"+session.ID, func() error {
+		current, err := s.store.GetSession(session.ID)
+		if err != nil {
+			return err
+		}
+		if current.Metadata == nil {
+			current.Metadata = make(map[string]string)
+		}
+		current.Metadata[metaLastActivity] = now.UTC().Format(time.RFC3339)
+		return s.store.UpdateSession(current)
+	})
+	if err != nil {
+		logging.Debugf("세션 %s last_activity 기록 실패: %v", session.ID, err)
+	}
+}
+
+func (s *Service) markActive(sessionID string, now time.Time) {
+	s.inactivity.mu.Lock()
+	s.inactivity.lastActive[sessionID] = now
+	s.inactivity.mu.Unlock()
+}
+
+// lastActive 마지막 활동 시각 (처음 보는 세션은 저장된 last_activity, 없으면 지금부터 셈)
+func (s *Service) lastActive(session *store.Session, now time.Time) time.Time {
+	s.inactivity.mu.Lock()
+	defer s.inactivity.mu.Unlock()
+	last, ok := s.inactivity.lastActive[session.ID]
+	if !ok {
+		last = now
+		if saved, err := time.Parse(time.RFC3339, session.Metadata[metaLastActivity]); err == nil && saved.Before(now) {
+			last = saved
+		}
+		s.inactivity.lastActive[session.ID] = last
+	}
+	return last
+}
+
+// pruneInactivity 정리된 세션의 기록 삭제
+func (s *Service) pruneInactivity(seen map[string]bool) {
+	t := s.inactivity
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id := range t.lastActive {
+		if !seen[id] {
+			delete(t.lastActive, id)
+			delete(t.lastCPU, id)
+		}
+	}
+}
+
+// lockSession 컨테이너를 일시 정지하고 세션을 잠금 상태로 기록
+func (s *Service) lockSession(session *store.Session, cfg config.InactivityLock, now time.Time) error {
+	return s.locker.WithLock("session-lock:"+session.ID, func() error {
+		if err := s.dockerClient.PauseContainer(session.ContainerID); err != nil {
+			return err
+		}
+		session.State = store.SessionLocked
+		session.Metadata[metaLockedAt] = now.Format(time.RFC3339)
+		delete(session.Metadata, metaReclaimAt)
+		if cfg.HoldMinutes > 0 {
+			session.Metadata[metaReclaimAt] = now.Add(time.Duration(cfg.HoldMinutes) * time.Minute).Format(time.RFC3339)
+		}
+		if err := s.store.UpdateSession(session); err != nil {
+			s.dockerClient.UnpauseContainer(session.ContainerID)
+			return fmt.Errorf("세션 잠금 기록 실패: %v", err)
+		}
+
+		s.liveness.put(s.probeLiveness(session, nil))
+		s.ensureWaker(session)
+		inactivityActions.Inc("locked")
+		s.audit("system", "session.locked", session.ID,
+			fmt.Sprintf("user=%s idle_minutes=%d reclaim_at=%s", session.UserID, cfg.IdleMinutes, session.Metadata[metaReclaimAt]))
+		log.Printf("💤 비활성 세션 잠금: %s (사용자: %s, GPU 회수: %s)", session.ID, session.UserID, reclaimLabel(session))
+		return nil
+	})
+}
+
+// UnlockSession 잠긴 세션의 컨테이너를 재개 (SSH 연결을 기다리지 않고 API로 재개할 때)
+func (s *Service) UnlockSession(sessionID, actor string) (*store.Session, error) {
+	session, err := s.store.GetSession(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("세션을 찾을 수 없습니다: %w", err)
+	}
+	if session.NodeID != "" && session.NodeID != s.nodeID {
+		return nil, errs.New(errs.ErrConflict, "세션 %s는 노드 %s에서 실행 중입니다", session.ID, session.NodeID)
+	}
+	return s.unlockSession(sessionID, actor)
+}
+
+func (s *Service) unlockSession(sessionID, actor string) (*store.Session, error) {
+	var unlocked *store.Session
+	err := s.locker.WithLock("session-lock:"+sessionID, func() error {
+		session, err := s.store.GetSession(sessionID)
+		if err != nil {
+			return fmt.Errorf("세션을 찾을 수 없습니다: %w", err)
+		}
+		if session.State != store.SessionLocked {
+			// 동시에 들어온 다른 연결이 이미 재개함
+			unlocked = session
+			return nil
+		}
+
+		if err := s.dockerClient.UnpauseContainer(session.ContainerID); err != nil {
+			return err
+		}
+		now := time.Now()
+		session.State = store.SessionRunning
+		delete(session.Metadata, metaLockedAt)
+		delete(session.Metadata, metaReclaimAt)
+		session.Metadata[metaLastActivity] = now.UTC().Format(time.RFC3339)
+		if err := s.store.UpdateSession(session); err != nil {
+			return fmt.Errorf("세션 잠금 해제 기록 실패: %v", err)
+		}
+
+		s.markActive(session.ID, now)
+		s.publishRoute(session)
+		s.liveness.put(s.probeLiveness(session, nil))
+		s.closeWakerLater(session.ID)
+		inactivityActions.Inc("unlocked")
+		s.audit(actor, "session.unlocked", session.ID, "user="+session.UserID)
+		log.Printf("☀️ 잠긴 세션 재개: %s (사용자: %s, 요청: %s)", session.ID, session.UserID, actor)
+		unlocked = session
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return unlocked, nil
+}
+
+// ensureWaker 잠긴 세션의 깨우기 리스너를 열고 SSHPiper 라우트를 리스너로 돌림 (이미 있으면 그대로)
+func (s *Service) ensureWaker(session *store.Session) {
+	if s.router == nil || s.wakeHost == "" {
+		return
+	}
+	t := s.inactivity
+	t.mu.Lock()
+	if _, ok := t.wakers[session.ID]; ok {
+		t.mu.Unlock()
+		return
+	}
+	ln, err := net.Listen("tcp", net.JoinHostPort(s.wakeHost, "0"))
+	if err != nil {
+		t.mu.Unlock()
+		log.Printf("⚠️ 세션 %s 깨우기 리스너 생성 실패 (API로만 재개 가능): %v", session.ID, err)
+		return
+	}
+	t.wakers[session.ID] = ln
+	t.mu.Unlock()
+
+	go s.serveWake(session.ID, ln)
+	port := ln.Addr().(*net.TCPAddr).Port
+	if route, ok := s.sessionRoute(session); ok {
+		route.Host, route.Port, route.Wake = s.wakeHost, port, true
+		s.router.Upsert(route)
+		for _, collab := range s.collaboratorRoutes(session, route) {
+			s.router.Upsert(collab)
+		}
+	}
+	logging.Debugf("세션 %s 깨우기 리스너: %s", session.ID, ln.Addr())
+}
+
+// serveWake 잠긴 세션으로 들어온 SSH 연결을 받아 컨테이너를 재개하고 sshd로 전달
+func (s *Service) serveWake(sessionID string, ln net.Listener) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handleWake(sessionID, conn)
+	}
+}
+
+func (s *Service) handleWake(sessionID string, conn net.Conn) {
+	defer conn.Close()
+
+	// SSH 클라이언트는 먼저 버전 문자열을 보내므로 첫 바이트를 받은 뒤에 재개 (포트 확인만 하는 연결은 무시)
+	first := make([]byte, 1)
+	conn.SetReadDeadline(time.Now().Add(wakeDialTimeout))
+	if _, err := io.ReadFull(conn, first); err != nil {
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+
+	session, err := s.unlockSession(sessionID, "ssh")
+	if err != nil {
+		log.Printf("⚠️ SSH 연결로 세션 %s 재개 실패: %v", sessionID, err)
+		return
+	}
+
+	upstream, err := dialWithRetry(net.JoinHostPort(session.ContainerIP, "22"), wakeDialTimeout)
+	if err != nil {
+		log.Printf("⚠️ 재개한 세션 %s의 sshd에 연결 실패: %v", sessionID, err)
+		return
+	}
+	defer upstream.Close()
+	if _, err := upstream.Write(first); err != nil {
+		return
+	}
+
+	copied := make(chan struct{}, 2)
+	go func() { io.Copy(upstream, conn); copied <- struct{}{} }()
+	go func() { io.Copy(conn, upstream); copied <- struct{}{} }()
+	<-copied
+}
+
+// closeWakerLater 재개 후 wakeLinger가 지나면 깨우기 리스너를 닫음 (이미 전달 중인 연결은 유지)
+func (s *Service) closeWakerLater(sessionID string) {
+	time.AfterFunc(wakeLinger, func() { s.closeWaker(sessionID) })
+}
+
+func (s *Service) closeWaker(sessionID string) {
+	t := s.inactivity
+	t.mu.Lock()
+	ln, ok := t.wakers[sessionID]
+	delete(t.wakers, sessionID)
+	t.mu.Unlock()
+	if ok {
+		ln.Close()
+	}
+}
+
+// dialWithRetry 재개 직후 sshd가 응답할 때까지 잠시 재시도
+func dialWithRetry(addr string, timeout time.Duration) (net.Conn, error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		conn, err := net.DialTimeout("tcp", addr, time.Second)
+		if err == nil {
+			return conn, nil
+		}
+		if time.Now().After(deadline) {
+			return nil, err
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+func reclaimLabel(session *store.Session) string {
+	if at := session.Metadata[metaReclaimAt]; at != "" {
+		return at
+	}
+	return "만료 시각 " + session.ExpiresAt.Format(time.RFC3339)
+}
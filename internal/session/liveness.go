@@ -0,0 +1,199 @@
+package session
+
+import (
+	"bufio"
+	"errors"
+	"log"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// sshProbeTimeout SSH 배너를 기다리는 최대 시간 (프로브 한 번당)
+const sshProbeTimeout = 2 * time.Second
+
+// Liveness 외부 로드 밸런서/SSO 런처용 간단한 세션 상태
+type Liveness struct {
+	SessionID   string    `json:"id"`
+	UserID      string    `json:"-"`
+	ProjectID   string    `json:"-"`
+	Running     bool      `json:"running"`
+	SSHReady    bool      `json:"ssh_ready"`
+	Locked      bool      `json:"locked,omitempty"`      // 비활성으로 일시 정지됨 (SSH로 접속하면 재개)
+	Paused      bool      `json:"paused,omitempty"`      // 사용자가 멈춤 (POST /sessions/:id/resume으로 재개)
+	Stopped     bool      `json:"stopped,omitempty"`     // 사용자가 멈추고 GPU를 잡아 둠 (POST /sessions/:id/start로 다시 시작)
+	Quarantined bool      `json:"quarantined,omitempty"` // 관리자가 네트워크를 끊고 격리함
+	ExpiresIn   int64     `json:"expires_in"`            // 만료까지 남은 초
+	ExpiresAt   time.Time `json:"expires_at"`
+	CheckedAt   time.Time `json:"checked_at"` // 마지막으로 컨테이너/SSH를 확인한 시각
+
+	// LastActivity 마지막으로 SSH 연결이나 CPU 사용이 확인된 시각 (inactivity_lock을 켰을 때만 기록)
+	LastActivity *time.Time `json:"last_activity,omitempty"`
+}
+
+// livenessCache 세션별 마지막 확인 결과 (요청마다 SQLite나 Docker를 조회하지 않도록 메모리에 보관)
+type livenessCache struct {
+	mu      sync.RWMutex
+	entries map[string]*Liveness
+}
+
+func newLivenessCache() *livenessCache {
+	return &livenessCache{entries: make(map[string]*Liveness)}
+}
+
+func (c *livenessCache) get(id string) (Liveness, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.entries[id]
+	if !ok {
+		return Liveness{}, false
+	}
+	return *entry, true
+}
+
+func (c *livenessCache) put(entry *Liveness) {
+	c.mu.Lock()
+	c.entries[entry.SessionID] = entry
+	c.mu.Unlock()
+}
+
+func (c *livenessCache) remove(id string) {
+	c.mu.Lock()
+	delete(c.entries, id)
+	c.mu.Unlock()
+}
+
+// replace 전체 갱신 결과로 교체 (그 사이 정리된 세션은 사라짐)
+func (c *livenessCache) replace(entries map[string]*Liveness) {
+	c.mu.Lock()
+	c.entries = entries
+	c.mu.Unlock()
+}
+
+// SessionLiveness 캐시된 세션 상태 반환
+// 캐시에 없으면(다른 복제본이 방금 만든 세션) 한 번만 조회해 캐시에 넣음
+func (s *Service) SessionLiveness(sessionID string) (*Liveness, error) {
+	entry, ok := s.liveness.get(sessionID)
+	if !ok {
+		session, err := s.store.GetSession(sessionID)
+		if err != nil {
+			return nil, err
+		}
+		probed := s.probeLiveness(session, s.nodeAddresses())
+		s.liveness.put(probed)
+		entry = *probed
+	}
+
+	if !time.Now().Before(entry.ExpiresAt) {
+		entry.Running, entry.SSHReady = false, false
+	}
+	entry.ExpiresIn = max(int64(time.Until(entry.ExpiresAt).Seconds()), 0)
+	return &entry, nil
+}
+
+// RefreshLiveness 모든 세션의 컨테이너 실행 여부와 SSH 응답을 확인해 캐시를 갱신
+func (s *Service) RefreshLiveness() error {
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		return err
+	}
+
+	addresses := s.nodeAddresses()
+	entries := make(map[string]*Liveness, len(sessions))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, session := range sessions {
+		wg.Add(1)
+		go func(session *store.Session) {
+			defer wg.Done()
+			entry := s.probeLiveness(session, addresses)
+			mu.Lock()
+			entries[session.ID] = entry
+			mu.Unlock()
+		}(session)
+	}
+	wg.Wait()
+
+	s.liveness.replace(entries)
+	return nil
+}
+
+// nodeAddresses 다른 노드 세션의 SSH 포트를 확인할 때 쓰는 노드 주소
+func (s *Service) nodeAddresses() map[string]string {
+	addresses := make(map[string]string)
+	nodes, err := s.store.ListNodes()
+	if err != nil {
+		log.Printf("⚠️ 노드 목록 조회 실패: %v", err)
+		return addresses
+	}
+	for _, node := range nodes {
+		if node.Address != "" {
+			addresses[node.ID] = node.Address
+		}
+	}
+	return addresses
+}
+
+// probeLiveness 이 노드의 세션은 Docker 상태와 컨테이너 SSH를, 다른 노드의 세션은 노드 주소의 SSH 포트를 확인
+func (s *Service) probeLiveness(session *store.Session, addresses map[string]string) *Liveness {
+	entry := &Liveness{
+		SessionID:   session.ID,
+		UserID:      session.UserID,
+		ProjectID:   session.ProjectID,
+		ExpiresAt:   session.ExpiresAt,
+		CheckedAt:   time.Now(),
+		Locked:      session.State == store.SessionLocked,
+		Paused:      session.State == store.SessionPaused,
+		Stopped:     session.State == store.SessionStopped,
+		Quarantined: session.State == store.SessionQuarantined,
+	}
+	if at, err := time.Parse(time.RFC3339, session.Metadata[metaLastActivity]); err == nil {
+		entry.LastActivity = &at
+	}
+
+	if session.NodeID == "" || session.NodeID == s.nodeID {
+		info, err := s.dockerClient.GetContainerInfo(session.ContainerID)
+		if err != nil {
+			if !errors.Is(err, errs.ErrNotFound) {
+				log.Printf("⚠️ 세션 %s 컨테이너 확인 실패: %v", session.ID, err)
+			}
+			return entry
+		}
+		entry.Running = info.Status == "running"
+		if entry.Running && info.IP != "" {
+			entry.SSHReady = sshReady(net.JoinHostPort(info.IP, "22"))
+		}
+		return entry
+	}
+
+	// 다른 노드의 컨테이너는 직접 볼 수 없으므로 SSH 포트가 응답하면 실행 중으로 판단
+	if host := addresses[session.NodeID]; host != "" && session.SSHPort > 0 {
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		entry.SSHReady = sshReady(net.JoinHostPort(host, strconv.Itoa(session.SSHPort)))
+		entry.Running = entry.SSHReady
+	}
+	return entry
+}
+
+// sshReady 주소에 연결해 SSH 배너("SSH-")를 받으면 준비된 것으로 판단
+func sshReady(addr string) bool {
+	conn, err := net.DialTimeout("tcp", addr, sshProbeTimeout)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(sshProbeTimeout))
+	banner, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && banner == "" {
+		return false
+	}
+	return strings.HasPrefix(banner, "SSH-")
+}
@@ -0,0 +1,28 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// SessionLogs 세션 컨테이너의 stdout/stderr 스트림 (follow면 ctx가 끝날 때까지 이어짐, 호출자가 닫아야 함)
+func (s *Service) SessionLogs(ctx context.Context, sessionID string, opts docker.LogOptions) (*store.Session, io.ReadCloser, error) {
+	session, err := s.store.GetSession(sessionID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("세션을 찾을 수 없습니다: %w", err)
+	}
+	if session.NodeID != "" && session.NodeID != s.nodeID {
+		return nil, nil, errs.New(errs.ErrConflict, "세션 %s는 노드 %s에서 실행 중입니다", sessionID, session.NodeID)
+	}
+
+	reader, err := s.dockerClient.StreamLogs(ctx, session.ContainerID, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return session, reader, nil
+}
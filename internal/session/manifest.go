@@ -0,0 +1,270 @@
+package session
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/command"
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/manifest"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+	"github.com/sandman/gpu-ssh-gateway/internal/workspace"
+)
+
+// manifestVersion 매니페스트 형식 버전 (필드 의미가 바뀌면 올리고, 다른 버전은 재현에 쓰지 않음)
+const manifestVersion = 1
+
+// manifestTimeout 컨테이너 조회와 git 커밋 확인에 쓰는 시간
+const manifestTimeout = 30 * time.Second
+
+// SessionManifest 세션을 같은 환경으로 다시 만드는 데 필요한 내용
+type SessionManifest struct {
+	Version   int       `json:"version"`
+	SessionID string    `json:"session_id"`
+	UserID    string    `json:"user_id"`
+	ProjectID string    `json:"project_id,omitempty"`
+	NodeID    string    `json:"node_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Image    ManifestImage       `json:"image"`
+	GPU      ManifestGPU         `json:"gpu"`
+	Env      []string            `json:"env"`
+	Mounts   []docker.MountSpec  `json:"mounts"`
+	Repos    []ManifestRepo      `json:"repos"`
+	Datasets []workspace.Dataset `json:"datasets"`
+}
+
+// ManifestImage 이미지 템플릿과 베이스 이미지, 빌드 결과
+type ManifestImage struct {
+	Template        string   `json:"template"`
+	TemplateDigest  string   `json:"template_digest,omitempty"` // 워크스페이스 Dockerfile SHA-256
+	BaseImage       string   `json:"base_image,omitempty"`
+	BaseImageDigest string   `json:"base_image_digest,omitempty"` // repo@sha256:... (레지스트리에서 받은 이미지만)
+	Channel         string   `json:"channel,omitempty"`
+	Image           string   `json:"image"` // 사용자별로 빌드한 이미지 이름
+	ImageID         string   `json:"image_id,omitempty"`
+	RepoDigests     []string `json:"repo_digests,omitempty"`
+}
+
+// ManifestGPU 할당받은 프로파일과 GPU 모델, 드라이버
+type ManifestGPU struct {
+	Profile       string `json:"profile"`
+	Model         string `json:"model,omitempty"`
+	DriverVersion string `json:"driver_version,omitempty"`
+	CUDAVersion   string `json:"cuda_version,omitempty"`
+}
+
+// ManifestRepo 워크스페이스에 미리 받은 저장소와 세션 시작 시점의 커밋
+type ManifestRepo struct {
+	URL    string `json:"url"`
+	Path   string `json:"path"`
+	Ref    string `json:"ref,omitempty"`
+	Commit string `json:"commit,omitempty"` // 호스트에서 읽을 수 없는 워크스페이스면 빈 값
+}
+
+// SignedManifest 내려받는 매니페스트 (payload를 base64 디코딩한 바이트가 서명 대상)
+type SignedManifest struct {
+	Manifest  *SessionManifest `json:"manifest"`
+	Payload   string           `json:"payload"`
+	Signature string           `json:"signature,omitempty"`
+	KeyID     string           `json:"key_id,omitempty"`
+	Algorithm string           `json:"algorithm,omitempty"`
+	Verified  bool             `json:"verified"` // 이 오케스트레이터의 서명 키로 확인했는지
+}
+
+// ManifestKey 매니페스트 서명을 검증할 공개키
+type ManifestKey struct {
+	KeyID     string `json:"key_id"`
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"public_key"` // base64 ed25519 공개키
+}
+
+// SetManifestSigner 매니페스트 서명 키 설정 (없으면 서명 없이 기록)
+func (s *Service) SetManifestSigner(signer *manifest.Signer) {
+	s.manifestSigner = signer
+}
+
+// ManifestKey 서명 키의 공개키 (서명 키가 없으면 ErrNotFound)
+func (s *Service) ManifestKey() (*ManifestKey, error) {
+	if s.manifestSigner == nil {
+		return nil, errs.New(errs.ErrNotFound, "매니페스트 서명 키가 설정되지 않았습니다")
+	}
+	return &ManifestKey{
+		KeyID:     s.manifestSigner.KeyID(),
+		Algorithm: manifest.Algorithm,
+		PublicKey: s.manifestSigner.PublicKey(),
+	}, nil
+}
+
+// recordManifest 생성한 세션의 이미지, 환경 변수, 마운트, 저장소 커밋을 기록하고 서명
+// 실패해도 세션 생성은 계속함 (로그만 남김)
+func (s *Service) recordManifest(session *store.Session, baseImage, workspaceDir string, cfg *config.Config) {
+	ctx, cancel := context.WithTimeout(context.Background(), manifestTimeout)
+	defer cancel()
+
+	spec, err := s.dockerClient.InspectContainerSpec(ctx, session.ContainerID)
+	if err != nil {
+		log.Printf("⚠️ 세션 %s 매니페스트 기록 실패: %v", session.ID, err)
+		return
+	}
+
+	m := &SessionManifest{
+		Version:   manifestVersion,
+		SessionID: session.ID,
+		UserID:    session.UserID,
+		ProjectID: session.ProjectID,
+		NodeID:    session.NodeID,
+		CreatedAt: session.CreatedAt.UTC(),
+		Image: ManifestImage{
+			Template:    session.Metadata["template"],
+			BaseImage:   baseImage,
+			Channel:     session.Metadata["image_channel"],
+			Image:       session.Metadata["image"],
+			ImageID:     spec.ImageID,
+			RepoDigests: spec.RepoDigests,
+		},
+		GPU:      s.manifestGPU(session),
+		Env:      spec.Env,
+		Mounts:   spec.Mounts,
+		Repos:    manifestRepos(ctx, cfg.WorkspaceProvisioning.GitRepos, workspaceDir),
+		Datasets: cfg.WorkspaceProvisioning.Datasets,
+	}
+	if m.Datasets == nil {
+		m.Datasets = []workspace.Dataset{}
+	}
+	if digest, defaultBase, err := docker.WorkspaceTemplate(); err != nil {
+		log.Printf("⚠️ 세션 %s 매니페스트에 템플릿 버전을 기록하지 못함: %v", session.ID, err)
+	} else {
+		m.Image.TemplateDigest = digest
+		if m.Image.BaseImage == "" {
+			m.Image.BaseImage = defaultBase
+		}
+	}
+	if m.Image.BaseImage != "" {
+		m.Image.BaseImageDigest = s.dockerClient.ImageDigest(ctx, m.Image.BaseImage)
+	}
+
+	payload, err := json.Marshal(m)
+	if err != nil {
+		log.Printf("⚠️ 세션 %s 매니페스트 인코딩 실패: %v", session.ID, err)
+		return
+	}
+	record := &store.SessionManifest{
+		SessionID: session.ID,
+		UserID:    session.UserID,
+		ProjectID: session.ProjectID,
+		Version:   manifestVersion,
+		Payload:   payload,
+		CreatedAt: time.Now(),
+	}
+	if s.manifestSigner != nil {
+		record.Signature = s.manifestSigner.Sign(payload)
+		record.KeyID = s.manifestSigner.KeyID()
+	}
+	if err := s.store.SaveSessionManifest(record); err != nil {
+		log.Printf("⚠️ 세션 %s 매니페스트 저장 실패: %v", session.ID, err)
+	}
+}
+
+// manifestGPU 세션 프로파일과 할당된 GPU의 모델, 드라이버 버전
+func (s *Service) manifestGPU(session *store.Session) ManifestGPU {
+	result := ManifestGPU{Profile: session.MIGProfile}
+	if session.GPUUUID == "" {
+		return result
+	}
+	driver := s.gpuManager.DriverInfo()
+	result.DriverVersion, result.CUDAVersion = driver.DriverVersion, driver.CUDAVersion
+	if index, err := strconv.Atoi(session.Metadata[metaGPUIndex]); err == nil {
+		for _, info := range s.gpuManager.GetGPUInfo() {
+			if info.Index == index {
+				result.Model = info.Name
+			}
+		}
+	}
+	return result
+}
+
+// manifestRepos 설정한 저장소마다 워크스페이스에 받아 둔 HEAD 커밋
+func manifestRepos(ctx context.Context, repos []workspace.GitRepo, workspaceDir string) []ManifestRepo {
+	result := make([]ManifestRepo, 0, len(repos))
+	for _, repo := range repos {
+		entry := ManifestRepo{URL: repo.URL, Path: repo.Path, Ref: repo.Ref}
+		if workspaceDir != "" {
+			result, err := command.Run(ctx, command.Options{}, "git", "-C", filepath.Join(workspaceDir, repo.Path), "rev-parse", "HEAD")
+			if err == nil {
+				entry.Commit = strings.TrimSpace(string(result.Stdout))
+			}
+		}
+		result = append(result, entry)
+	}
+	return result
+}
+
+// SessionManifest 세션의 서명된 매니페스트 (세션을 지운 뒤에도 조회 가능)
+func (s *Service) SessionManifest(sessionID string) (*SignedManifest, error) {
+	record, err := s.store.GetSessionManifest(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("세션 %s의 매니페스트를 찾을 수 없습니다: %w", sessionID, err)
+	}
+	m := &SessionManifest{}
+	if err := json.Unmarshal(record.Payload, m); err != nil {
+		return nil, fmt.Errorf("매니페스트 해석 실패: %v", err)
+	}
+
+	signed := &SignedManifest{
+		Manifest:  m,
+		Payload:   base64.StdEncoding.EncodeToString(record.Payload),
+		Signature: record.Signature,
+		KeyID:     record.KeyID,
+	}
+	if record.Signature != "" {
+		signed.Algorithm = manifest.Algorithm
+		signed.Verified = s.manifestSigner != nil && record.KeyID == s.manifestSigner.KeyID() &&
+			s.manifestSigner.Verify(record.Payload, record.Signature)
+	}
+	return signed, nil
+}
+
+// applyManifest from_manifest로 요청한 세션에 매니페스트의 템플릿과 프로파일을 채우고 고정할 베이스 이미지를 반환
+// 서명이 맞지 않거나 워크스페이스 Dockerfile이 바뀌어 같은 이미지를 만들 수 없으면 거부
+func (s *Service) applyManifest(req *CreateRequest) (string, error) {
+	signed, err := s.SessionManifest(req.FromManifest)
+	if err != nil {
+		return "", err
+	}
+	if signed.Signature != "" && !signed.Verified {
+		return "", errs.New(errs.ErrConflict, "매니페스트 %s의 서명을 이 오케스트레이터의 키(%s)로 확인할 수 없습니다", req.FromManifest, signed.KeyID)
+	}
+	m := signed.Manifest
+	if m.Version != manifestVersion {
+		return "", errs.New(errs.ErrConflict, "매니페스트 버전 %d는 재현할 수 없습니다 (지원: %d)", m.Version, manifestVersion)
+	}
+	if digest, _, err := docker.WorkspaceTemplate(); err != nil {
+		return "", err
+	} else if m.Image.TemplateDigest != "" && digest != m.Image.TemplateDigest {
+		return "", errs.New(errs.ErrConflict, "워크스페이스 템플릿이 바뀌어 매니페스트 %s의 이미지를 다시 만들 수 없습니다 (기록: %s, 현재: %s)",
+			req.FromManifest, m.Image.TemplateDigest, digest)
+	}
+
+	if req.Image == "" {
+		req.Image = m.Image.Template
+	} else if req.Image != m.Image.Template {
+		return "", errs.New(errs.ErrInvalid, "from_manifest와 다른 이미지를 지정할 수 없습니다 (매니페스트: %s)", m.Image.Template)
+	}
+	if req.MIGProfile == "" && req.MIGInstanceUUID == "" {
+		req.MIGProfile = m.GPU.Profile
+	}
+	if m.Image.BaseImageDigest != "" {
+		return m.Image.BaseImageDigest, nil
+	}
+	return m.Image.BaseImage, nil
+}
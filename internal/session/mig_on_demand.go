@@ -0,0 +1,99 @@
+package session
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+var migOnDemandOps = metrics.NewCounter("sandman_mig_on_demand_total", "요청에 맞춰 만들거나 지운 MIG 인스턴스 수 (result: created, failed, deleted, delete_failed)", "profile", "result")
+
+// carveMIG 빈 인스턴스가 없을 때 GPU에 새 인스턴스를 만들어 할당 (gpu-allocation 락을 잡은 상태에서 호출)
+// 만들 수 없으면 nil을 반환하고 호출자가 spot 회수/선점으로 넘어감
+func (s *Service) carveMIG(req CreateRequest) *gpu.MIGInstance {
+	created, err := s.gpuManager.CarveMIGInstance(req.MIGProfile)
+	if err != nil {
+		migOnDemandOps.Inc(req.MIGProfile, "failed")
+		log.Printf("⚠️ %s 인스턴스를 새로 만들지 못했습니다: %v", req.MIGProfile, err)
+		return nil
+	}
+	migOnDemandOps.Inc(req.MIGProfile, "created")
+	s.audit("scheduler", "mig.created", created.UUID, fmt.Sprintf("name=%s profile=%s gpu=%d user=%s", created.Name, req.MIGProfile, created.GPUIndex, req.UserID))
+
+	instance, err := s.gpuManager.AllocateMIGByUUID(created.UUID, req.UserID)
+	if err != nil {
+		log.Printf("⚠️ 새로 만든 MIG 인스턴스 %s 할당 실패: %v", created.UUID, err)
+		// 쓰지 못한 인스턴스가 GPU 슬라이스를 계속 차지하지 않도록 지움
+		if err := s.deleteMIG(created.UUID, "scheduler"); err != nil {
+			migOnDemandOps.Inc(req.MIGProfile, "delete_failed")
+			log.Printf("⚠️ 할당하지 못한 MIG 인스턴스 %s 삭제 실패: %v", created.Name, err)
+		} else {
+			migOnDemandOps.Inc(req.MIGProfile, "deleted")
+		}
+		return nil
+	}
+	return instance
+}
+
+// releaseOnDemandMIG 요청으로 만든 인스턴스를 세션 종료 후 지움 (지웠으면 true, 소프트 예약은 하지 않음)
+// 선점/spot 회수로 gpu-allocation 락을 잡은 채 불릴 수 있어 락 없이 GPU 매니저의 직렬화에 맡김
+func (s *Service) releaseOnDemandMIG(session *store.Session) bool {
+	if !s.config.Current().MIGOnDemand.DeleteOnRelease {
+		return false
+	}
+	instance, ok := s.gpuManager.Instance(session.GPUUUID)
+	if !ok || !instance.OnDemand {
+		return false
+	}
+	if err := s.deleteMIG(instance.UUID, "system"); err != nil {
+		migOnDemandOps.Inc(instance.Profile.Name, "delete_failed")
+		log.Printf("⚠️ 세션 %s의 MIG 인스턴스 %s 삭제 실패 (인스턴스는 남겨 둠): %v", session.ID, instance.Name, err)
+		return false
+	}
+	migOnDemandOps.Inc(instance.Profile.Name, "deleted")
+	return true
+}
+
+// CreateMIGInstance 관리자가 GPU에 MIG 인스턴스를 직접 만듦
+func (s *Service) CreateMIGInstance(gpuIndex int, profile string) (*gpu.MIGInstance, error) {
+	lease, err := s.locker.Acquire("gpu-allocation")
+	if err != nil {
+		return nil, fmt.Errorf("GPU 할당 락 획득 실패: %v", err)
+	}
+	defer lease.Release()
+
+	instance, err := s.gpuManager.CreateMIGInstance(gpuIndex, profile)
+	if err != nil {
+		return nil, err
+	}
+	s.audit("admin", "mig.created", instance.UUID, fmt.Sprintf("name=%s profile=%s gpu=%d", instance.Name, profile, gpuIndex))
+	return instance, nil
+}
+
+// DeleteMIGInstance 사용 중이 아닌 MIG 인스턴스를 GPU에서 지움
+func (s *Service) DeleteMIGInstance(id, actor string) error {
+	lease, err := s.locker.Acquire("gpu-allocation")
+	if err != nil {
+		return fmt.Errorf("GPU 할당 락 획득 실패: %v", err)
+	}
+	defer lease.Release()
+	// 다른 복제본이 세션에 준 인스턴스를 지우지 않도록 할당 기록을 먼저 반영
+	if err := s.syncGPUClaims(); err != nil {
+		return err
+	}
+	return s.deleteMIG(id, actor)
+}
+
+func (s *Service) deleteMIG(id, actor string) error {
+	instance, ok := s.gpuManager.Instance(id)
+	if err := s.gpuManager.DeleteMIGInstance(id); err != nil {
+		return err
+	}
+	if ok {
+		s.audit(actor, "mig.deleted", instance.UUID, fmt.Sprintf("name=%s profile=%s gpu=%d", instance.Name, instance.Profile.Name, instance.GPUIndex))
+	}
+	return nil
+}
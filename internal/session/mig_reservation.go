@@ -0,0 +1,133 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// 이전 인스턴스 예약 사용 결과
+const (
+	reservationReused      = "reused"      // 이전 인스턴스를 다시 배정
+	reservationOtherNode   = "other_node"  // 이전 인스턴스가 다른 노드에 있음
+	reservationProfile     = "profile"     // 요청한 프로파일이 다름
+	reservationUnavailable = "unavailable" // 다른 세션이 쓰고 있거나 인스턴스가 사라짐
+)
+
+var migReservationOutcomes = metrics.NewCounter("sandman_mig_reservations_total", "이전 MIG 인스턴스 소프트 예약 사용 결과", "outcome")
+
+// reserveMIG 세션이 끝난 인스턴스를 사용자에게 mig_reservation_minutes 동안 소프트 예약
+// 선점/드레인으로 끝난 세션은 인스턴스나 노드를 다시 줄 수 없으므로, CPU 전용 세션은 인스턴스가 없으므로 예약하지 않음
+func (s *Service) reserveMIG(session *store.Session, reason string, endedAt time.Time) {
+	minutes := s.config.Current().MIGReservationMinutes
+	if minutes == 0 || reason == EndReasonPreempted || reason == EndReasonDrained || session.GPUUUID == "" {
+		return
+	}
+	reservation := &store.MIGReservation{
+		UserID:     session.UserID,
+		NodeID:     session.NodeID,
+		GPUUUID:    session.GPUUUID,
+		GPUName:    session.GPUName,
+		MIGProfile: session.MIGProfile,
+		SessionID:  session.ID,
+		ReleasedAt: endedAt,
+		ExpiresAt:  endedAt.Add(time.Duration(minutes) * time.Minute),
+	}
+	if err := s.store.SaveMIGReservation(reservation); err != nil {
+		log.Printf("⚠️ 이전 MIG 인스턴스 예약 저장 실패 (사용자: %s): %v", session.UserID, err)
+		return
+	}
+	logging.Debugf("이전 MIG 인스턴스 예약: %s → 사용자 %s (%d분)", session.GPUUUID, session.UserID, minutes)
+}
+
+// allocateReserved 사용자의 예약이 이 노드의 같은 프로파일 인스턴스이고 비어 있으면 그 인스턴스를 할당
+// 예약을 쓸 수 없으면 nil을 반환하고 호출자가 일반 배치로 넘어감 (gpu-allocation 락을 잡은 상태에서 호출)
+func (s *Service) allocateReserved(req CreateRequest) *gpu.MIGInstance {
+	reservation, err := s.store.GetMIGReservation(req.UserID, time.Now())
+	if err != nil {
+		if !errors.Is(err, errs.ErrNotFound) {
+			log.Printf("⚠️ 이전 MIG 인스턴스 예약 조회 실패 (사용자: %s): %v", req.UserID, err)
+		}
+		return nil
+	}
+
+	outcome := reservationReused
+	var instance *gpu.MIGInstance
+	switch {
+	case reservation.NodeID != s.nodeID:
+		outcome = reservationOtherNode
+	case reservation.MIGProfile != req.MIGProfile:
+		outcome = reservationProfile
+	default:
+		instance, err = s.gpuManager.AllocateMIGByUUID(reservation.GPUUUID, req.UserID)
+		if err != nil {
+			outcome = reservationUnavailable
+		}
+	}
+	migReservationOutcomes.Inc(outcome)
+
+	if instance == nil {
+		log.Printf("🧭 이전 인스턴스 우선 배치 불가 (%s): 사용자=%s, 인스턴스=%s, 노드=%s → 일반 배치",
+			outcome, req.UserID, reservationLabel(reservation), reservation.NodeID)
+		// 다른 노드의 예약은 그 노드에서 새 세션을 만들 때 쓸 수 있도록 남겨 둠
+		if outcome != reservationOtherNode {
+			s.dropReservation(req.UserID)
+		}
+		return nil
+	}
+
+	log.Printf("🧭 배치 결정 (이전 인스턴스): 프로파일=%s, 인스턴스=%s (%s), GPU=%d, 이전 세션=%s",
+		req.MIGProfile, instance.Name, instance.UUID, instance.GPUIndex, reservation.SessionID)
+	s.dropReservation(req.UserID)
+	return instance
+}
+
+// reservedForOthers 이 노드에서 다른 사용자에게 예약된 인스턴스 (일반 배치에서 뒤로 미룸)
+func (s *Service) reservedForOthers(userID string) map[string]bool {
+	reservations, err := s.store.ListMIGReservations(s.nodeID, time.Now())
+	if err != nil {
+		log.Printf("⚠️ 이전 MIG 인스턴스 예약 목록 조회 실패: %v", err)
+		return nil
+	}
+	reserved := make(map[string]bool, len(reservations))
+	for _, r := range reservations {
+		if r.UserID != userID {
+			reserved[r.GPUUUID] = true
+		}
+	}
+	return reserved
+}
+
+func (s *Service) dropReservation(userID string) {
+	if err := s.store.DeleteMIGReservation(userID); err != nil {
+		log.Printf("⚠️ 이전 MIG 인스턴스 예약 삭제 실패 (사용자: %s): %v", userID, err)
+	}
+}
+
+// ListMIGReservations 이 노드의 유효한 소프트 예약
+func (s *Service) ListMIGReservations() ([]*store.MIGReservation, error) {
+	return s.store.ListMIGReservations(s.nodeID, time.Now())
+}
+
+// purgeMIGReservations 만료된 예약 정리 (만료 세션 정리 주기에 함께 실행)
+func (s *Service) purgeMIGReservations() {
+	if n, err := s.store.PurgeMIGReservations(time.Now()); err != nil {
+		log.Printf("⚠️ 만료된 MIG 인스턴스 예약 정리 실패: %v", err)
+	} else if n > 0 {
+		logging.Debugf("만료된 MIG 인스턴스 예약 %d개 정리", n)
+	}
+}
+
+func reservationLabel(r *store.MIGReservation) string {
+	if r.GPUName != "" {
+		return fmt.Sprintf("%s (%s)", r.GPUName, r.GPUUUID)
+	}
+	return r.GPUUUID
+}
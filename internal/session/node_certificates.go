@@ -0,0 +1,204 @@
+package session
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/auth"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/nodeca"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// 노드 인증서 발급 종류 (store.NodeCertificate.Kind)
+const (
+	NodeCertEnroll = "enroll" // 일회용 가입 토큰으로 발급
+	NodeCertRenew  = "renew"  // 현재 인증서로 인증해 발급
+)
+
+// defaultJoinTokenTTL 가입 토큰 유효 시간 기본값
+const defaultJoinTokenTTL = time.Hour
+
+var (
+	nodeCertsIssued  = metrics.NewCounter("sandman_node_certificates_issued_total", "노드 에이전트 mTLS 인증서 발급 수 (kind: enroll, renew)", "kind")
+	nodeAuthFailures = metrics.NewCounter("sandman_node_auth_failures_total", "노드 에이전트 인증서 인증 실패 수 (reason: identity, unknown, revoked, mismatch)", "reason")
+)
+
+// NodeJoinToken 관리자에게 한 번만 보여 주는 노드 가입 토큰
+type NodeJoinToken struct {
+	NodeID        string    `json:"node_id"`
+	Token         string    `json:"token"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	CAFingerprint string    `json:"ca_fingerprint"` // 에이전트가 가입 응답의 CA를 확인할 지문
+	NodeCertTTL   string    `json:"node_cert_ttl"`
+}
+
+// SetNodeCA 노드 에이전트 인증서를 발급할 CA와 인증서 유효 시간 설정 (nil이면 mTLS 비활성)
+func (s *Service) SetNodeCA(ca *nodeca.CA, certTTL time.Duration) {
+	s.nodeCA = ca
+	s.nodeCertTTL = certTTL
+}
+
+func (s *Service) requireNodeCA() error {
+	if s.nodeCA == nil {
+		return errs.New(errs.ErrConflict, "노드 CA가 설정되지 않았습니다 (-node-ca-dir)")
+	}
+	return nil
+}
+
+// CreateNodeJoinToken 노드 하나가 첫 인증서를 받을 수 있는 일회용 토큰 발급 (ttl이 0이면 1시간)
+func (s *Service) CreateNodeJoinToken(nodeID string, ttl time.Duration) (*NodeJoinToken, error) {
+	if err := s.requireNodeCA(); err != nil {
+		return nil, err
+	}
+	if !nodeca.ValidNodeID(nodeID) {
+		return nil, errs.New(errs.ErrInvalid, "노드 ID는 영문자/숫자로 시작하고 영문자, 숫자, '.', '_', '-'만 쓸 수 있습니다: %q", nodeID)
+	}
+	if ttl <= 0 {
+		ttl = defaultJoinTokenTTL
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("가입 토큰 생성 실패: %v", err)
+	}
+	raw := hex.EncodeToString(b)
+	now := time.Now()
+	token := &store.NodeJoinToken{TokenHash: auth.HashToken(raw), NodeID: nodeID, CreatedAt: now, ExpiresAt: now.Add(ttl)}
+	if err := s.store.CreateNodeJoinToken(token); err != nil {
+		return nil, fmt.Errorf("가입 토큰 저장 실패: %v", err)
+	}
+
+	log.Printf("🎫 노드 가입 토큰 발급: %s (만료: %s)", nodeID, token.ExpiresAt.Format(time.RFC3339))
+	s.audit("admin", "node.join_token_created", nodeID, "expires "+token.ExpiresAt.Format(time.RFC3339))
+	return &NodeJoinToken{
+		NodeID:        nodeID,
+		Token:         raw,
+		ExpiresAt:     token.ExpiresAt,
+		CAFingerprint: s.nodeCA.Fingerprint(),
+		NodeCertTTL:   s.nodeCertTTL.String(),
+	}, nil
+}
+
+// EnrollNode 일회용 가입 토큰을 확인하고 노드 ID에 묶인 첫 인증서 발급
+func (s *Service) EnrollNode(req nodeca.EnrollRequest) (*nodeca.CertificateResponse, error) {
+	if err := s.requireNodeCA(); err != nil {
+		return nil, err
+	}
+	token, err := s.store.TakeNodeJoinToken(auth.HashToken(req.Token))
+	if err != nil {
+		return nil, errs.New(errs.ErrForbidden, "이미 사용했거나 존재하지 않는 가입 토큰입니다")
+	}
+	if time.Now().After(token.ExpiresAt) {
+		return nil, errs.New(errs.ErrForbidden, "만료된 가입 토큰입니다")
+	}
+	if token.NodeID != req.NodeID {
+		return nil, errs.New(errs.ErrForbidden, "가입 토큰은 노드 %s용입니다", token.NodeID)
+	}
+	return s.issueNodeCertificate(req.NodeID, req.CSR, NodeCertEnroll)
+}
+
+// RenewNodeCertificate 인증서로 인증된 노드에게 새 인증서 발급 (이전 인증서는 만료될 때까지 유효)
+func (s *Service) RenewNodeCertificate(nodeID string, req nodeca.RenewRequest) (*nodeca.CertificateResponse, error) {
+	if err := s.requireNodeCA(); err != nil {
+		return nil, err
+	}
+	return s.issueNodeCertificate(nodeID, req.CSR, NodeCertRenew)
+}
+
+func (s *Service) issueNodeCertificate(nodeID, csr, kind string) (*nodeca.CertificateResponse, error) {
+	cert, certPEM, err := s.nodeCA.SignNode(nodeID, []byte(csr), s.nodeCertTTL)
+	if err != nil {
+		return nil, errs.New(errs.ErrInvalid, "%v", err)
+	}
+	spiffeID := nodeca.SPIFFEID(s.nodeCA.TrustDomain(), nodeID)
+	record := &store.NodeCertificate{
+		Serial:      cert.SerialNumber.Text(16),
+		NodeID:      nodeID,
+		SPIFFEID:    spiffeID,
+		Fingerprint: nodeca.Fingerprint(cert),
+		Kind:        kind,
+		NotAfter:    cert.NotAfter,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.store.CreateNodeCertificate(record); err != nil {
+		return nil, fmt.Errorf("인증서 기록 저장 실패: %v", err)
+	}
+
+	nodeCertsIssued.Inc(kind)
+	log.Printf("🔏 노드 인증서 발급 (%s): %s serial=%s 만료=%s", kind, spiffeID, record.Serial, cert.NotAfter.Format(time.RFC3339))
+	s.audit("node:"+nodeID, "node.certificate_issued", nodeID, kind+" serial="+record.Serial)
+	return &nodeca.CertificateResponse{
+		NodeID:        nodeID,
+		SPIFFEID:      spiffeID,
+		Serial:        record.Serial,
+		Certificate:   certPEM,
+		CACertificate: s.nodeCA.CertificatePEM(),
+		ExpiresAt:     cert.NotAfter,
+	}, nil
+}
+
+// AuthorizeNode TLS 핸드셰이크에서 검증된 클라이언트 인증서가 nodeID의 것인지 확인
+// 발급 기록이 없거나 폐기된 인증서는 거부 (연결을 재사용하는 요청도 매번 확인)
+func (s *Service) AuthorizeNode(cert *x509.Certificate, nodeID string) error {
+	if err := s.requireNodeCA(); err != nil {
+		return err
+	}
+	certNode, err := s.nodeCA.NodeID(cert)
+	if err != nil {
+		nodeAuthFailures.Inc("identity")
+		return errs.New(errs.ErrForbidden, "%v", err)
+	}
+	serial := cert.SerialNumber.Text(16)
+	record, err := s.store.GetNodeCertificate(serial)
+	if err != nil {
+		nodeAuthFailures.Inc("unknown")
+		return errs.New(errs.ErrForbidden, "발급 기록이 없는 인증서입니다 (serial=%s)", serial)
+	}
+	if record.RevokedAt != nil {
+		nodeAuthFailures.Inc("revoked")
+		return errs.New(errs.ErrForbidden, "폐기된 인증서입니다 (노드 %s, %s)", certNode, record.RevokedAt.Format(time.RFC3339))
+	}
+	if record.NodeID != certNode || certNode != nodeID {
+		nodeAuthFailures.Inc("mismatch")
+		return errs.New(errs.ErrForbidden, "노드 %s의 인증서로 노드 %s를 대신할 수 없습니다", certNode, nodeID)
+	}
+	return nil
+}
+
+func (s *Service) ListNodeCertificates(nodeID string) ([]*store.NodeCertificate, error) {
+	return s.store.ListNodeCertificates(nodeID)
+}
+
+// RevokeNodeCertificates 노드의 인증서와 사용하지 않은 가입 토큰을 모두 폐기 (다시 쓰려면 새 가입 토큰 필요)
+func (s *Service) RevokeNodeCertificates(nodeID, actor, reason string) (int64, error) {
+	revoked, err := s.store.RevokeNodeCertificates(nodeID, time.Now())
+	if err != nil {
+		return 0, fmt.Errorf("노드 인증서 폐기 실패: %v", err)
+	}
+	if err := s.store.DeleteNodeJoinTokens(nodeID); err != nil {
+		return revoked, fmt.Errorf("가입 토큰 삭제 실패: %v", err)
+	}
+	if revoked > 0 {
+		log.Printf("🔒 노드 %s 인증서 %d개 폐기 (%s)", nodeID, revoked, reason)
+		s.audit(actor, "node.certificates_revoked", nodeID, fmt.Sprintf("%d revoked: %s", revoked, reason))
+	}
+	return revoked, nil
+}
+
+// NodeCAInfo 에이전트가 신뢰할 CA 인증서와 지문 (mTLS 비활성이면 nil)
+func (s *Service) NodeCAInfo() map[string]string {
+	if s.nodeCA == nil {
+		return nil
+	}
+	return map[string]string{
+		"trust_domain":   s.nodeCA.TrustDomain(),
+		"fingerprint":    s.nodeCA.Fingerprint(),
+		"ca_certificate": s.nodeCA.CertificatePEM(),
+	}
+}
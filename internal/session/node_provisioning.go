@@ -0,0 +1,107 @@
+package session
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/lock"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+)
+
+// nodeSlotPollInterval 노드 슬롯이 모두 차 있을 때 다시 시도하는 간격
+const nodeSlotPollInterval = 500 * time.Millisecond
+
+var (
+	nodeProvisioningLimit   = metrics.NewGauge("sandman_node_provisioning_limit", "노드별 동시 이미지 빌드/컨테이너 생성 한도 (0이면 제한 없음)", "node")
+	nodeProvisioningBusy    = metrics.NewGauge("sandman_node_provisioning_busy", "이 프로세스가 보유한 노드 프로비저닝 슬롯 수", "node")
+	nodeProvisioningWaiting = metrics.NewGauge("sandman_node_provisioning_waiting", "노드 프로비저닝 슬롯을 기다리는 요청 수", "node")
+	nodeProvisioningWait    = metrics.NewHistogram("sandman_node_provisioning_wait_seconds", "노드 프로비저닝 슬롯을 얻기까지 기다린 시간",
+		[]float64{0.01, 0.5, 1, 5, 15, 30, 60, 120}, "node")
+)
+
+// nodeSlotPrefix 노드의 프로비저닝 슬롯 락 이름 접두사 (슬롯마다 node-provisioning:<노드>:<번호>)
+func nodeSlotPrefix(nodeID string) string {
+	return fmt.Sprintf("node-provisioning:%s:", nodeID)
+}
+
+// nodeWaiting 이 프로세스에서 노드 슬롯을 기다리는 요청 수
+var nodeWaiting atomic.Int64
+
+// acquireNodeSlot 이미지 빌드/컨테이너 생성 전에 노드 슬롯을 얻고 반납 함수를 반환
+// 같은 노드를 쓰는 복제본들이 번호 붙은 분산 락을 나눠 가지므로 한도는 노드 전체 합계이며,
+// 슬롯이 모두 차 있으면 queue_wait_seconds까지 기다림 (대기 순서는 보장하지 않음)
+func (s *Service) acquireNodeSlot(cfg *config.Config) (func(), error) {
+	limit := cfg.NodeProvisioningLimitFor(s.nodeID)
+	nodeProvisioningLimit.Set(float64(limit), s.nodeID)
+	if limit == 0 {
+		return func() {}, nil
+	}
+
+	start := time.Now()
+	var deadline time.Time
+	if cfg.ProvisioningTimeouts.QueueWaitSeconds > 0 {
+		deadline = start.Add(time.Duration(cfg.ProvisioningTimeouts.QueueWaitSeconds) * time.Second)
+	}
+
+	nodeProvisioningWaiting.Set(float64(nodeWaiting.Add(1)), s.nodeID)
+	defer func() { nodeProvisioningWaiting.Set(float64(nodeWaiting.Add(-1)), s.nodeID) }()
+
+	for {
+		lease, err := s.tryNodeSlot(limit)
+		if err != nil {
+			return nil, err
+		}
+		if lease != nil {
+			nodeProvisioningWait.Observe(time.Since(start).Seconds(), s.nodeID)
+			nodeProvisioningBusy.Inc(s.nodeID)
+			return func() {
+				lease.Release()
+				nodeProvisioningBusy.Dec(s.nodeID)
+			}, nil
+		}
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			provisioningRejected.Inc("node_timeout")
+			return nil, errs.New(errs.ErrNoCapacity, "노드 %s의 동시 프로비저닝 한도(%d)로 대기 시간 초과 (%ds)",
+				s.nodeID, limit, cfg.ProvisioningTimeouts.QueueWaitSeconds)
+		}
+		time.Sleep(nodeSlotPollInterval)
+	}
+}
+
+// tryNodeSlot 빈 슬롯 하나를 잡음 (모두 차 있으면 nil)
+func (s *Service) tryNodeSlot(limit int) (*lock.Lease, error) {
+	prefix := nodeSlotPrefix(s.nodeID)
+	for i := 0; i < limit; i++ {
+		lease, err := s.locker.TryAcquire(fmt.Sprintf("%s%d", prefix, i))
+		if err != nil {
+			return nil, fmt.Errorf("노드 프로비저닝 슬롯 획득 실패: %v", err)
+		}
+		if lease != nil {
+			logging.Debugf("노드 프로비저닝 슬롯 %d/%d 획득 (노드: %s)", i+1, limit, s.nodeID)
+			return lease, nil
+		}
+	}
+	return nil, nil
+}
+
+// nodeSlotsBusy 노드 전체에서 사용 중인 프로비저닝 슬롯 수 (한도를 줄였으면 예전 번호의 슬롯도 포함)
+func (s *Service) nodeSlotsBusy() int {
+	leases, err := s.store.ListLocks()
+	if err != nil {
+		return 0
+	}
+	prefix := nodeSlotPrefix(s.nodeID)
+	now := time.Now()
+	busy := 0
+	for _, lease := range leases {
+		if strings.HasPrefix(lease.Name, prefix) && lease.ExpiresAt.After(now) {
+			busy++
+		}
+	}
+	return busy
+}
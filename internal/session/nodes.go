@@ -0,0 +1,323 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+var nodesGauge = metrics.NewGauge("sandman_nodes", "상태별 등록 노드 수", "status")
+
+// NodeRegistration 에이전트가 등록할 때 보내는 노드 정보
+type NodeRegistration struct {
+	ID            string          `json:"id" binding:"required"`
+	Hostname      string          `json:"hostname"`
+	Address       string          `json:"address,omitempty"`
+	Version       string          `json:"version,omitempty"`
+	DriverVersion string          `json:"driver_version,omitempty"`
+	CUDAVersion   string          `json:"cuda_version,omitempty"`
+	NVMLVersion   string          `json:"nvml_version,omitempty"`
+	Capabilities  []string        `json:"capabilities"`
+	GPUs          []store.NodeGPU `json:"gpus"`
+	Capacity      map[string]int  `json:"capacity"`
+}
+
+// NodeHeartbeat 하트비트마다 갱신되는 인벤토리
+type NodeHeartbeat struct {
+	GPUs     []store.NodeGPU `json:"gpus"`
+	Capacity map[string]int  `json:"capacity"`
+
+	// 드라이버 정보 (driver_version이 있으면 노드 정보를 갱신, 호스트 드라이버 업그레이드 반영)
+	DriverVersion string `json:"driver_version,omitempty"`
+	CUDAVersion   string `json:"cuda_version,omitempty"`
+	NVMLVersion   string `json:"nvml_version,omitempty"`
+
+	// 노드 쪽 시계 상태 (sent_at이 있으면 받는 쪽 시계와의 차이도 확인)
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	ClockOffsetMs int64      `json:"clock_offset_ms,omitempty"`
+	ClockWarning  string     `json:"clock_warning,omitempty"`
+}
+
+// NodeView 노드 정보와 배치된 세션 수
+type NodeView struct {
+	*store.Node
+	Sessions int `json:"sessions"`
+}
+
+// SetNodeID 이 오케스트레이터가 세션을 배치하는 노드 (설정하지 않으면 노드 상태 확인 없이 동작)
+func (s *Service) SetNodeID(id string) {
+	s.nodeID = id
+}
+
+func (s *Service) NodeID() string {
+	return s.nodeID
+}
+
+// RegisterNode 노드 등록 또는 재등록 (재등록하면 인벤토리를 교체하고 ready로 복귀, 드레인 중이면 드레인 유지)
+func (s *Service) RegisterNode(reg NodeRegistration) (*store.Node, error) {
+	if err := validateUserID(reg.ID); err != nil {
+		return nil, errs.New(errs.ErrInvalid, "잘못된 노드 ID입니다: %q", reg.ID)
+	}
+	if reg.Hostname == "" {
+		reg.Hostname = reg.ID
+	}
+	if reg.Capabilities == nil {
+		reg.Capabilities = []string{}
+	}
+
+	now := time.Now()
+	node := &store.Node{
+		ID:            reg.ID,
+		Hostname:      reg.Hostname,
+		Address:       reg.Address,
+		Version:       reg.Version,
+		DriverVersion: reg.DriverVersion,
+		CUDAVersion:   reg.CUDAVersion,
+		NVMLVersion:   reg.NVMLVersion,
+		Capabilities:  reg.Capabilities,
+		GPUs:          reg.GPUs,
+		Capacity:      reg.Capacity,
+		Status:        store.NodeReady,
+		RegisteredAt:  now,
+		LastHeartbeat: now,
+	}
+	if err := s.store.RegisterNode(node); err != nil {
+		return nil, fmt.Errorf("노드 등록 실패: %v", err)
+	}
+
+	log.Printf("🖥️ 노드 등록: %s (%s, GPU %d개, 드라이버 %s, CUDA %s)", node.ID, node.Hostname, len(node.GPUs), node.DriverVersion, node.CUDAVersion)
+	s.audit("node:"+node.ID, "node.registered", node.ID, fmt.Sprintf("hostname=%s gpus=%d", node.Hostname, len(node.GPUs)))
+	return s.store.GetNode(node.ID)
+}
+
+// HeartbeatNode 하트비트 기록 (연결이 끊겼던 노드는 다시 ready로 복귀)
+func (s *Service) HeartbeatNode(id string, hb NodeHeartbeat) error {
+	now := time.Now()
+	if err := s.store.HeartbeatNode(id, hb.GPUs, hb.Capacity, now); err != nil {
+		return fmt.Errorf("노드 %s 하트비트 실패: %w", id, err)
+	}
+	if hb.DriverVersion != "" {
+		if err := s.store.SetNodeDriver(id, hb.DriverVersion, hb.CUDAVersion, hb.NVMLVersion); err != nil {
+			log.Printf("⚠️ 노드 %s 드라이버 정보 기록 실패: %v", id, err)
+		}
+	}
+	if err := s.recordNodeClock(id, hb, now); err != nil {
+		log.Printf("⚠️ 노드 %s 시계 상태 기록 실패: %v", id, err)
+	}
+
+	recovered, err := s.store.SetNodeStatus(id, store.NodeUnreachable, store.NodeReady)
+	if err != nil {
+		return err
+	}
+	if recovered {
+		log.Printf("✅ 노드 %s 하트비트 재개 - ready로 복귀", id)
+		s.audit("node:"+id, "node.recovered", id, "")
+	}
+	return nil
+}
+
+// CheckNodeHeartbeats timeout 동안 하트비트가 없는 노드를 unreachable로 표시하고,
+// 세션이 모두 끝난 드레인 노드를 drained로 전환
+func (s *Service) CheckNodeHeartbeats(timeout time.Duration) error {
+	lease, err := s.locker.TryAcquire("node-monitor")
+	if err != nil {
+		return err
+	}
+	if lease == nil {
+		logging.Debugf("다른 복제본이 노드 상태 확인 중 - 건너뜀")
+		return nil
+	}
+	defer lease.Release()
+
+	nodes, err := s.store.ListNodes()
+	if err != nil {
+		return err
+	}
+
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		return err
+	}
+	perNode := make(map[string]int)
+	for _, session := range sessions {
+		perNode[session.NodeID]++
+	}
+
+	counts := map[string]float64{store.NodeReady: 0, store.NodeUnreachable: 0, store.NodeDraining: 0, store.NodeDrained: 0}
+	for _, node := range nodes {
+		if node.Status == store.NodeDraining && perNode[node.ID] == 0 {
+			if changed, err := s.store.SetNodeStatus(node.ID, store.NodeDraining, store.NodeDrained); err == nil && changed {
+				node.Status = store.NodeDrained
+				log.Printf("✅ 노드 %s 드레인 완료 - 세션이 없어 제거할 수 있습니다", node.ID)
+				s.audit("node-monitor", "node.drained", node.ID, "")
+			}
+		}
+		if node.Status == store.NodeReady && time.Since(node.LastHeartbeat) > timeout {
+			changed, err := s.store.SetNodeStatus(node.ID, store.NodeReady, store.NodeUnreachable)
+			if err != nil {
+				log.Printf("⚠️ 노드 %s 상태 변경 실패: %v", node.ID, err)
+			} else if changed {
+				node.Status = store.NodeUnreachable
+				log.Printf("🚨 노드 %s 하트비트 없음 (마지막: %s) - unreachable로 표시", node.ID, node.LastHeartbeat.Format(time.RFC3339))
+				s.audit("node-monitor", "node.unreachable", node.ID, "last_heartbeat="+node.LastHeartbeat.Format(time.RFC3339))
+			}
+		}
+		counts[node.Status]++
+	}
+	for status, count := range counts {
+		nodesGauge.Set(count, status)
+	}
+	return nil
+}
+
+// ListNodes 등록된 노드와 노드별 세션 수
+func (s *Service) ListNodes() ([]*NodeView, error) {
+	nodes, err := s.store.ListNodes()
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		return nil, err
+	}
+
+	perNode := make(map[string]int)
+	for _, session := range sessions {
+		perNode[session.NodeID]++
+	}
+
+	views := make([]*NodeView, 0, len(nodes))
+	for _, node := range nodes {
+		views = append(views, &NodeView{Node: node, Sessions: perNode[node.ID]})
+	}
+	return views, nil
+}
+
+// DeleteNode 세션이 남아 있지 않은 노드의 등록 해제
+func (s *Service) DeleteNode(id string) error {
+	if _, err := s.store.GetNode(id); err != nil {
+		return fmt.Errorf("노드 %s를 찾을 수 없습니다: %w", id, err)
+	}
+
+	sessions, err := s.nodeSessions(id)
+	if err != nil {
+		return err
+	}
+	if len(sessions) > 0 {
+		return errs.New(errs.ErrConflict, "노드 %s에 세션 %d개가 남아 있습니다 (POST /admin/nodes/%s/drain으로 비우세요)", id, len(sessions), id)
+	}
+
+	if err := s.store.DeleteNode(id); err != nil {
+		return err
+	}
+	log.Printf("🗑️ 노드 등록 해제: %s", id)
+	s.audit("admin", "node.deleted", id, "")
+	// 해제한 노드의 인증서로는 다시 등록하거나 하트비트를 보낼 수 없도록 폐기
+	if _, err := s.RevokeNodeCertificates(id, "admin", "node deleted"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// LocalNodeRegistration 이 호스트의 GPU 인벤토리와 기능으로 만든 등록 정보 (내장 에이전트용)
+func (s *Service) LocalNodeRegistration(hostname, address, version string) NodeRegistration {
+	gpus, capacity := s.localInventory()
+
+	capabilities := []string{}
+	if s.dockerClient.NVIDIARuntimeStatus().Available {
+		capabilities = append(capabilities, "nvidia-runtime")
+	}
+	for _, gpu := range gpus {
+		if gpu.MIGEnabled {
+			capabilities = append(capabilities, "mig")
+			break
+		}
+	}
+	if s.sshCA != nil {
+		capabilities = append(capabilities, "ssh-ca")
+	}
+
+	driver := s.gpuManager.DriverInfo()
+	return NodeRegistration{
+		ID:            s.nodeID,
+		Hostname:      hostname,
+		Address:       address,
+		Version:       version,
+		DriverVersion: driver.DriverVersion,
+		CUDAVersion:   driver.CUDAVersion,
+		NVMLVersion:   driver.NVMLVersion,
+		Capabilities:  capabilities,
+		GPUs:          gpus,
+		Capacity:      capacity,
+	}
+}
+
+// LocalHeartbeat 이 호스트의 현재 인벤토리와 마지막 시계 확인 결과
+func (s *Service) LocalHeartbeat() NodeHeartbeat {
+	gpus, capacity := s.localInventory()
+	clock := s.ClockStatus()
+	driver := s.gpuManager.DriverInfo()
+	return NodeHeartbeat{
+		GPUs:          gpus,
+		Capacity:      capacity,
+		DriverVersion: driver.DriverVersion,
+		CUDAVersion:   driver.CUDAVersion,
+		NVMLVersion:   driver.NVMLVersion,
+		ClockOffsetMs: clock.ContainerOffsetMs,
+		ClockWarning:  clock.Warning,
+	}
+}
+
+// RefreshDriverInfo 호스트 드라이버 정보를 다시 읽음 (다음 하트비트부터 노드 정보에 반영)
+func (s *Service) RefreshDriverInfo() error {
+	return s.gpuManager.RefreshDriverInfo()
+}
+
+func (s *Service) localInventory() ([]store.NodeGPU, map[string]int) {
+	gpus := []store.NodeGPU{}
+	for _, info := range s.gpuManager.GetGPUInfo() {
+		gpu := store.NodeGPU{
+			Index:        info.Index,
+			UUID:         info.UUID,
+			Name:         info.Name,
+			MemoryTotal:  info.MemoryTotal,
+			MIGEnabled:   info.MIGEnabled,
+			MIGMode:      info.MIGMode,
+			MIGPending:   info.MIGModePending,
+			MIGInstances: len(info.MIGInstances),
+		}
+		for _, instance := range info.MIGInstances {
+			if !instance.InUse {
+				gpu.MIGAvailable++
+			}
+		}
+		gpus = append(gpus, gpu)
+	}
+
+	capacity := make(map[string]int)
+	for _, instance := range s.gpuManager.GetAvailableMIGInstances() {
+		capacity[instance.Profile.Name]++
+	}
+	return gpus, capacity
+}
+
+// checkNodeSchedulable 이 노드가 새 세션을 받을 수 있는 상태인지 확인 (스케줄러 입력)
+func (s *Service) checkNodeSchedulable() error {
+	if s.nodeID == "" {
+		return nil
+	}
+	node, err := s.store.GetNode(s.nodeID)
+	if err != nil {
+		// 아직 등록 전이면 배치 허용 (에이전트가 곧 등록)
+		return nil
+	}
+	if node.Status != store.NodeReady {
+		return errs.New(errs.ErrNoCapacity, "노드 %s가 %s 상태여서 세션을 배치할 수 없습니다", node.ID, node.Status)
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// EndReasonPaused 일시 중지하며 내놓은 인스턴스의 소프트 예약 사유 (세션이 끝난 것은 아님)
+const EndReasonPaused = "paused"
+
+// 일시 중지 상태를 담는 세션 메타데이터
+const (
+	metaPausedAt      = "paused_at"
+	metaPausedGPU     = "paused_gpu"      // 내놓은 인스턴스 UUID (keep_gpu면 없음)
+	metaPausedProfile = "paused_profile"  // 재개 때 다시 받을 프로파일
+	metaPausedGPUName = "paused_gpu_name" // 내놓은 인스턴스 이름 (표시용)
+)
+
+var sessionPauses = metrics.NewCounter("sandman_session_pauses_total", "세션 일시 중지/재개 수 (action: paused, resumed; gpu: kept, same, other)", "action", "gpu")
+
+// PauseSession 컨테이너를 멈추고 세션 기록과 워크스페이스는 유지
+// keepGPU가 false면 인스턴스를 내놓고 mig_reservation_minutes 동안 사용자에게 소프트 예약
+func (s *Service) PauseSession(sessionID, actor string, keepGPU bool) (*store.Session, error) {
+	done, err := s.beginOperation()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	var paused *store.Session
+	err = s.locker.WithLock("session-lock:"+sessionID, func() error {
+		session, err := s.localSession(sessionID)
+		if err != nil {
+			return err
+		}
+		if session.State != store.SessionRunning && session.State != store.SessionLocked {
+			return errs.New(errs.ErrConflict, "세션 %s는 %s 상태라 일시 중지할 수 없습니다", session.ID, session.State)
+		}
+
+		// 비활성으로 잠긴 세션은 재개한 뒤 멈춰야 정상 종료 신호를 받음
+		s.closeWaker(session.ID)
+		if session.State == store.SessionLocked {
+			if err := s.dockerClient.UnpauseContainer(session.ContainerID); err != nil {
+				log.Printf("⚠️ 잠긴 컨테이너 재개 실패: %v", err)
+			}
+			delete(session.Metadata, metaLockedAt)
+			delete(session.Metadata, metaReclaimAt)
+		}
+		s.withdrawRoute(session)
+		if err := s.stopSessionContainer(session); err != nil {
+			return fmt.Errorf("컨테이너 중지 실패: %w", err)
+		}
+
+		now := time.Now()
+		gpuLabel := "kept"
+		session.State = store.SessionPaused
+		session.Metadata[metaPausedAt] = now.UTC().Format(time.RFC3339)
+		session.Metadata[metaPausedProfile] = session.MIGProfile
+		if !keepGPU && session.GPUUUID != "" {
+			if err := s.releaseMIG(session.ID, session.GPUUUID, session.UserID); err != nil {
+				log.Printf("⚠️ GPU 인스턴스 해제 실패: %v", err)
+			} else if !s.releaseOnDemandMIG(session) {
+				s.reserveMIG(session, EndReasonPaused, now)
+			}
+			session.Metadata[metaPausedGPU] = session.GPUUUID
+			session.Metadata[metaPausedGPUName] = session.GPUName
+			session.GPUUUID, session.GPUName = "", ""
+			gpuLabel = "released"
+		}
+		if err := s.store.UpdateSession(session); err != nil {
+			return fmt.Errorf("세션 일시 중지 기록 실패: %w", err)
+		}
+
+		s.liveness.put(s.probeLiveness(session, nil))
+		sessionPauses.Inc("paused", gpuLabel)
+		s.audit(actor, "session.paused", session.ID, fmt.Sprintf("user=%s gpu=%s", session.UserID, gpuLabel))
+		s.recordEvent(session, EventPause, actor, "", "gpu="+gpuLabel)
+		log.Printf("⏸️ 세션 일시 중지: %s (사용자: %s, GPU: %s)", session.ID, session.UserID, gpuLabel)
+		paused = session
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return paused, nil
+}
+
+// ResumeSession 일시 중지한 세션의 컨테이너를 다시 시작
+// 내놓은 인스턴스는 예약이 남아 있으면 그대로, 아니면 같은 프로파일로 다시 배치 (다른 인스턴스면 컨테이너를 다시 만듦)
+func (s *Service) ResumeSession(sessionID, actor string) (*store.Session, error) {
+	done, err := s.beginOperation()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	var resumed *store.Session
+	err = s.locker.WithLock("session-lock:"+sessionID, func() error {
+		session, err := s.localSession(sessionID)
+		if err != nil {
+			return err
+		}
+		if session.State != store.SessionPaused {
+			return errs.New(errs.ErrConflict, "세션 %s는 일시 중지 상태가 아닙니다 (%s)", session.ID, session.State)
+		}
+
+		gpuLabel := "kept"
+		previous := session.Metadata[metaPausedGPU]
+		if previous != "" {
+			instance, err := s.reacquireMIG(session)
+			if err != nil {
+				return err
+			}
+			gpuLabel = "same"
+			if instance.UUID != previous {
+				gpuLabel = "other"
+				info, err := s.dockerClient.RecreateContainerWithGPU(session.ContainerID, instance.UUID, stopOptions(session, s.config.Current()))
+				if err != nil {
+					s.releaseMIG(session.ID, instance.UUID, session.UserID)
+					return fmt.Errorf("새 인스턴스로 컨테이너 재생성 실패: %w", err)
+				}
+				session.ContainerID = info.ID
+				if info.IP != "" {
+					session.ContainerIP = info.IP
+				}
+			}
+			session.GPUUUID, session.GPUName = instance.UUID, instance.Name
+			s.recordTopology(session, instance, session.Metadata[metaPlacement])
+		}
+		if gpuLabel != "other" {
+			if err := s.dockerClient.StartContainer(session.ContainerID); err != nil {
+				if previous != "" {
+					s.releaseMIG(session.ID, session.GPUUUID, session.UserID)
+				}
+				return err
+			}
+		}
+
+		session.State = store.SessionRunning
+		for _, key := range []string{metaPausedAt, metaPausedGPU, metaPausedProfile, metaPausedGPUName} {
+			delete(session.Metadata, key)
+		}
+		if err := s.store.UpdateSession(session); err != nil {
+			return fmt.Errorf("세션 재개 기록 실패: %w", err)
+		}
+
+		// 다시 만든 컨테이너에는 컨테이너 안에만 있던 접근 설정이 없음
+		if gpuLabel == "other" {
+			if err := s.installSSHCA(session); err != nil {
+				log.Printf("⚠️ 세션 %s SSH CA 재설치 실패: %v", session.ID, err)
+			}
+			s.reapplyAccess(session)
+			if err := s.provisionWorkspaceSSH(session); err != nil {
+				log.Printf("⚠️ 세션 %s 워크스페이스 .ssh 재구성 실패: %v", session.ID, err)
+			}
+		}
+		s.markActive(session.ID, time.Now())
+		s.publishRoute(session)
+		s.liveness.put(s.probeLiveness(session, nil))
+		sessionPauses.Inc("resumed", gpuLabel)
+		s.audit(actor, "session.resumed", session.ID, fmt.Sprintf("user=%s gpu=%s instance=%s", session.UserID, gpuLabel, session.GPUName))
+		s.recordEvent(session, EventResume, actor, "", "gpu="+gpuLabel)
+		log.Printf("▶️ 세션 재개: %s (사용자: %s, GPU: %s %s)", session.ID, session.UserID, gpuLabel, session.GPUName)
+		resumed = session
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resumed, nil
+}
+
+// reacquireMIG 일시 중지하며 내놓은 프로파일의 인스턴스를 다시 할당 (예약이 있으면 같은 인스턴스)
+func (s *Service) reacquireMIG(session *store.Session) (*gpu.MIGInstance, error) {
+	lease, err := s.locker.Acquire("gpu-allocation")
+	if err != nil {
+		return nil, fmt.Errorf("GPU 할당 락 획득 실패: %v", err)
+	}
+	defer lease.Release()
+	if err := s.syncGPUClaims(); err != nil {
+		return nil, err
+	}
+
+	req := CreateRequest{
+		UserID:     session.UserID,
+		ProjectID:  session.ProjectID,
+		MIGProfile: session.Metadata[metaPausedProfile],
+		Priority:   session.Priority,
+		Tier:       session.Tier,
+		Placement:  session.Metadata[metaPlacement],
+	}
+	if req.MIGProfile == "" {
+		req.MIGProfile = session.MIGProfile
+	}
+	instance, err := s.scheduleMIG(req, s.config.Current())
+	if err != nil {
+		return nil, fmt.Errorf("GPU 인스턴스를 다시 할당할 수 없습니다: %w", err)
+	}
+	if err := s.claimGPU(lease, instance, session.ID, session.UserID); err != nil {
+		s.gpuManager.ReleaseMIG(instance.UUID, session.UserID)
+		return nil, fmt.Errorf("GPU 할당 기록 실패: %w", err)
+	}
+	return instance, nil
+}
+
+// localSession 이 노드에서 실행되는 세션 (다른 노드 세션은 그 노드에 요청해야 함)
+func (s *Service) localSession(sessionID string) (*store.Session, error) {
+	session, err := s.store.GetSession(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("세션을 찾을 수 없습니다: %w", err)
+	}
+	if session.NodeID != "" && session.NodeID != s.nodeID {
+		return nil, errs.New(errs.ErrConflict, "세션 %s는 노드 %s에서 실행 중입니다", session.ID, session.NodeID)
+	}
+	return session, nil
+}
@@ -0,0 +1,242 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// ProfileRequest 프로파일 정의 생성/변경 요청 (변경은 이름을 뺀 전체 교체)
+type ProfileRequest struct {
+	Name              string                  `json:"name"`
+	DisplayName       string                  `json:"display_name"`
+	Description       string                  `json:"description"`
+	Enabled           *bool                   `json:"enabled"` // 생략하면 true
+	Resources         *store.ProfileResources `json:"resources"`
+	PricePerHour      float64                 `json:"price_per_hour"`
+	DefaultTTLMinutes int                     `json:"default_ttl_minutes"`
+	MaxTTLMinutes     int                     `json:"max_ttl_minutes"`
+}
+
+// ProfileHardware 이 노드의 GPU가 프로파일을 얼마나 제공하는지
+type ProfileHardware struct {
+	Supported bool   `json:"supported"` // GPU 관리자가 아는 MIG 프로파일인지
+	Memory    string `json:"memory,omitempty"`
+	Instances int    `json:"instances"`
+	Available int    `json:"available"`
+}
+
+// ProfileView 프로파일 정의와 하드웨어 현황
+type ProfileView struct {
+	*store.Profile
+	Hardware ProfileHardware `json:"hardware"`
+}
+
+func (s *Service) ListProfiles() ([]*ProfileView, error) {
+	profiles, err := s.store.ListProfiles()
+	if err != nil {
+		return nil, err
+	}
+	views := make([]*ProfileView, 0, len(profiles))
+	for _, p := range profiles {
+		views = append(views, &ProfileView{Profile: p, Hardware: s.profileHardware(p.Name)})
+	}
+	return views, nil
+}
+
+func (s *Service) GetProfile(name string) (*ProfileView, error) {
+	p, err := s.store.GetProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	return &ProfileView{Profile: p, Hardware: s.profileHardware(p.Name)}, nil
+}
+
+// OfferedProfiles 사용자에게 보여 줄 활성 프로파일 정의 (정의가 없으면 빈 목록)
+func (s *Service) OfferedProfiles() ([]*ProfileView, error) {
+	views, err := s.ListProfiles()
+	if err != nil {
+		return nil, err
+	}
+	offered := []*ProfileView{}
+	for _, v := range views {
+		if v.Enabled {
+			offered = append(offered, v)
+		}
+	}
+	return offered, nil
+}
+
+func (s *Service) CreateProfile(req ProfileRequest) (*ProfileView, error) {
+	now := time.Now()
+	p := profileFromRequest(req)
+	p.CreatedAt, p.UpdatedAt = now, now
+	if err := s.validateProfile(p, s.config.Current()); err != nil {
+		return nil, err
+	}
+	if err := s.store.CreateProfile(p); err != nil {
+		return nil, fmt.Errorf("프로파일 저장 실패: %w", err)
+	}
+	log.Printf("🧩 프로파일 정의 추가: %s (%s, 활성: %t)", p.Name, p.DisplayName, p.Enabled)
+	s.audit("admin", "profile.created", p.Name, profileDetail(p))
+	return &ProfileView{Profile: p, Hardware: s.profileHardware(p.Name)}, nil
+}
+
+func (s *Service) UpdateProfile(name string, req ProfileRequest) (*ProfileView, error) {
+	existing, err := s.store.GetProfile(name)
+	if err != nil {
+		return nil, err
+	}
+	req.Name = name
+	p := profileFromRequest(req)
+	p.CreatedAt, p.UpdatedAt = existing.CreatedAt, time.Now()
+	if err := s.validateProfile(p, s.config.Current()); err != nil {
+		return nil, err
+	}
+	if err := s.store.UpdateProfile(p); err != nil {
+		return nil, fmt.Errorf("프로파일 저장 실패: %w", err)
+	}
+	log.Printf("🧩 프로파일 정의 변경: %s (활성: %t)", p.Name, p.Enabled)
+	s.audit("admin", "profile.updated", p.Name, profileDetail(p))
+	return &ProfileView{Profile: p, Hardware: s.profileHardware(p.Name)}, nil
+}
+
+// DeleteProfile 정의 삭제 (실행 중인 세션은 그대로, 마지막 정의를 지우면 다시 설정 파일 기준으로 모든 프로파일 제공)
+func (s *Service) DeleteProfile(name string) error {
+	if err := s.store.DeleteProfile(name); err != nil {
+		return err
+	}
+	log.Printf("🧩 프로파일 정의 삭제: %s", name)
+	s.audit("admin", "profile.deleted", name, "")
+	return nil
+}
+
+func profileFromRequest(req ProfileRequest) *store.Profile {
+	p := &store.Profile{
+		Name:              strings.TrimSpace(req.Name),
+		DisplayName:       strings.TrimSpace(req.DisplayName),
+		Description:       req.Description,
+		Enabled:           req.Enabled == nil || *req.Enabled,
+		Resources:         req.Resources,
+		PricePerHour:      req.PricePerHour,
+		DefaultTTLMinutes: req.DefaultTTLMinutes,
+		MaxTTLMinutes:     req.MaxTTLMinutes,
+	}
+	if p.DisplayName == "" {
+		p.DisplayName = p.Name
+	}
+	return p
+}
+
+func profileDetail(p *store.Profile) string {
+	return fmt.Sprintf("enabled=%t price_per_hour=%g default_ttl=%d max_ttl=%d", p.Enabled, p.PricePerHour, p.DefaultTTLMinutes, p.MaxTTLMinutes)
+}
+
+// validateProfile 값 범위와, 활성 프로파일이면 이 노드의 GPU가 실제로 그 인스턴스를 제공하는지 확인
+func (s *Service) validateProfile(p *store.Profile, cfg *config.Config) error {
+	if p.Name == "" {
+		return errs.New(errs.ErrInvalid, "프로파일 이름이 필요합니다")
+	}
+	hw := s.profileHardware(p.Name)
+	if !hw.Supported {
+		return errs.New(errs.ErrInvalid, "알 수 없는 MIG 프로파일입니다: %s", p.Name)
+	}
+	if p.Enabled {
+		// CPU 전용 세션은 GPU 인스턴스가 필요 없음
+		if hw.Instances == 0 && p.Name != gpu.ProfileCPU {
+			return errs.New(errs.ErrInvalid, "이 노드의 GPU에는 %s 인스턴스가 없습니다 (비활성으로 정의한 뒤 MIG 구성을 바꾸세요)", p.Name)
+		}
+		if !cfg.ProfileAllowed(p.Name) {
+			return errs.New(errs.ErrInvalid, "프로파일 %s는 설정 파일의 allowed_profiles에 없습니다", p.Name)
+		}
+	}
+	if p.Resources != nil {
+		if err := config.ResourceClass(*p.Resources).Validate(); err != nil {
+			return errs.New(errs.ErrInvalid, "resources: %v", err)
+		}
+	}
+	if p.PricePerHour < 0 {
+		return errs.New(errs.ErrInvalid, "price_per_hour는 0 이상이어야 합니다")
+	}
+	if p.DefaultTTLMinutes < 0 || p.MaxTTLMinutes < 0 {
+		return errs.New(errs.ErrInvalid, "TTL은 0 이상이어야 합니다")
+	}
+	if p.MaxTTLMinutes > cfg.MaxTTLMinutes {
+		return errs.New(errs.ErrInvalid, "max_ttl_minutes(%d)가 배포 최대값 %d분을 넘습니다", p.MaxTTLMinutes, cfg.MaxTTLMinutes)
+	}
+	if p.MaxTTLMinutes > 0 && p.DefaultTTLMinutes > p.MaxTTLMinutes {
+		return errs.New(errs.ErrInvalid, "default_ttl_minutes(%d)가 max_ttl_minutes(%d)보다 큽니다", p.DefaultTTLMinutes, p.MaxTTLMinutes)
+	}
+	if p.DefaultTTLMinutes > cfg.MaxTTLMinutes {
+		return errs.New(errs.ErrInvalid, "default_ttl_minutes(%d)가 배포 최대값 %d분을 넘습니다", p.DefaultTTLMinutes, cfg.MaxTTLMinutes)
+	}
+	return nil
+}
+
+func (s *Service) profileHardware(name string) ProfileHardware {
+	hw := ProfileHardware{}
+	if known, ok := s.gpuManager.GetAvailableProfiles()[name]; ok {
+		hw.Supported = true
+		hw.Memory = known.Memory
+	}
+	for _, instance := range s.gpuManager.ListMIGInstances() {
+		if instance.Profile.Name != name {
+			continue
+		}
+		hw.Instances++
+		if !instance.InUse {
+			hw.Available++
+		}
+	}
+	return hw
+}
+
+// applyProfilePolicy 관리자가 정의한 프로파일이 있으면 제공 여부를 확인하고 프로파일별 TTL 정책 적용
+// 정의가 하나도 없으면 nil (설정 파일만으로 동작)
+func (s *Service) applyProfilePolicy(req *CreateRequest, profile string, ttlRequested bool) (*store.Profile, error) {
+	profiles, err := s.store.ListProfiles()
+	if err != nil {
+		return nil, fmt.Errorf("프로파일 정의 조회 실패: %v", err)
+	}
+	if len(profiles) == 0 {
+		return nil, nil
+	}
+
+	var def *store.Profile
+	offered := []string{}
+	for _, p := range profiles {
+		if p.Enabled {
+			offered = append(offered, p.Name)
+		}
+		if p.Name == profile {
+			def = p
+		}
+	}
+	if def == nil || !def.Enabled {
+		sort.Strings(offered)
+		return nil, errs.New(errs.ErrInvalid, "프로파일 %s는 제공되지 않습니다 (제공: %s)", profile, strings.Join(offered, ", "))
+	}
+
+	if !ttlRequested && def.DefaultTTLMinutes > 0 {
+		req.TTLMinutes = def.DefaultTTLMinutes
+	}
+	if def.MaxTTLMinutes > 0 && req.TTLMinutes > def.MaxTTLMinutes {
+		return nil, s.quotaError(errs.New(errs.ErrInvalid, "TTL %d분이 프로파일 %s의 최대 허용값 %d분을 초과합니다", req.TTLMinutes, profile, def.MaxTTLMinutes), req.UserID, req.ProjectID)
+	}
+	return def, nil
+}
+
+// profileResources 프로파일 정의에 리소스가 있으면 그것을, 없으면 설정 파일의 resource_classes
+func profileResources(def *store.Profile, profile string, cfg *config.Config) config.ResourceClass {
+	if def != nil && def.Resources != nil {
+		return config.ResourceClass(*def.Resources)
+	}
+	return cfg.ResourceClassFor(profile)
+}
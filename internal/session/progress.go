@@ -0,0 +1,252 @@
+package session
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+)
+
+// 비동기 생성 진행 단계 (이미지 빌드, 컨테이너 생성/시작은 docker.Progress* 이름을 그대로 씀)
+const (
+	ProgressQueued    = "queued"    // 생성 워커를 기다리는 중
+	ProgressWorkspace = "workspace" // 워크스페이스 준비
+	ProgressGPU       = "gpu"       // GPU 인스턴스 할당
+	ProgressVerify    = "verify"    // 컨테이너에 GPU가 붙었는지 확인
+	ProgressFinalize  = "finalize"  // 세션 기록, 접근 설정, 라우트 게시
+	ProgressReady     = "ready"
+	ProgressFailed    = "failed"
+)
+
+const (
+	progressLogTail     = 50               // 새 구독자에게 다시 보내는 빌드 로그 줄 수
+	progressBuffer      = 256              // 구독자별 버퍼 (가득 차면 로그 줄부터 버림)
+	progressRetention   = 10 * time.Minute // 끝난 생성의 진행 기록을 남겨 두는 시간
+	progressMaxLogBytes = 4096             // 로그 한 줄 최대 길이
+)
+
+var asyncCreates = metrics.NewCounter("sandman_async_session_creates_total", "비동기 세션 생성 결과 (result: ready, failed)", "result")
+
+// ProgressEvent 세션 생성 진행 이벤트 하나 (단계 시작, 빌드 로그 한 줄, 완료/실패)
+type ProgressEvent struct {
+	Step    string          `json:"step"`
+	Message string          `json:"message,omitempty"`
+	Log     string          `json:"log,omitempty"` // 이미지 빌드 로그 한 줄
+	Error   string          `json:"error,omitempty"`
+	Result  *CreateResponse `json:"result,omitempty"` // ready 이벤트에만 (비밀번호와 개인키는 빼고 credentials_token으로 전달)
+	At      time.Time       `json:"at"`
+	Done    bool            `json:"done,omitempty"`
+}
+
+// ProvisioningProgress 비동기 생성 하나의 현재 단계와 지금까지의 이벤트
+type ProvisioningProgress struct {
+	SessionID string          `json:"session_id"`
+	UserID    string          `json:"user_id"`
+	ProjectID string          `json:"project_id,omitempty"`
+	Step      string          `json:"step"`
+	StartedAt time.Time       `json:"started_at"`
+	Done      bool            `json:"done"`
+	Events    []ProgressEvent `json:"events"`
+}
+
+type progressEntry struct {
+	progress    ProvisioningProgress
+	subscribers map[chan ProgressEvent]struct{}
+}
+
+// progressTracker 비동기 생성의 진행 기록과 WebSocket 구독자 (복제본 안에서만 유지)
+type progressTracker struct {
+	mu      sync.Mutex
+	entries map[string]*progressEntry
+}
+
+func newProgressTracker() *progressTracker {
+	return &progressTracker{entries: make(map[string]*progressEntry)}
+}
+
+func (t *progressTracker) start(sessionID, userID, projectID string) {
+	t.mu.Lock()
+	t.entries[sessionID] = &progressEntry{
+		progress: ProvisioningProgress{
+			SessionID: sessionID,
+			UserID:    userID,
+			ProjectID: projectID,
+			StartedAt: time.Now(),
+			Events:    []ProgressEvent{},
+		},
+		subscribers: make(map[chan ProgressEvent]struct{}),
+	}
+	t.mu.Unlock()
+	t.step(sessionID, ProgressQueued, "")
+}
+
+// step 단계 시작 알림 (추적하지 않는 세션, 즉 동기 생성이면 무시)
+func (t *progressTracker) step(sessionID, step, message string) {
+	t.publish(sessionID, ProgressEvent{Step: step, Message: message, At: time.Now()})
+}
+
+// log 빌드 로그 한 줄 (마지막 progressLogTail줄만 기록에 남김)
+func (t *progressTracker) log(sessionID, step, line string) {
+	if len(line) > progressMaxLogBytes {
+		line = line[:progressMaxLogBytes]
+	}
+	t.publish(sessionID, ProgressEvent{Step: step, Log: line, At: time.Now()})
+}
+
+// finish 완료/실패 이벤트를 보내고 구독을 닫음 (기록은 progressRetention 뒤에 지움)
+func (t *progressTracker) finish(sessionID string, resp *CreateResponse, err error) {
+	event := ProgressEvent{Step: ProgressReady, At: time.Now(), Done: true}
+	if err != nil {
+		event.Step, event.Error = ProgressFailed, err.Error()
+	} else if resp != nil {
+		result := *resp
+		result.SSHPassword, result.SSHPrivateKey = "", ""
+		event.Result = &result
+	}
+	t.publish(sessionID, event)
+	time.AfterFunc(progressRetention, func() {
+		t.mu.Lock()
+		delete(t.entries, sessionID)
+		t.mu.Unlock()
+	})
+}
+
+func (t *progressTracker) publish(sessionID string, event ProgressEvent) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entry, ok := t.entries[sessionID]
+	if !ok || entry.progress.Done {
+		return
+	}
+
+	p := &entry.progress
+	if event.Log == "" {
+		p.Step = event.Step
+	}
+	p.Events = append(p.Events, event)
+	if event.Log != "" {
+		trimLogTail(p)
+	}
+	p.Done = event.Done
+
+	for ch := range entry.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// 로그 줄은 버려도 되지만 단계 이벤트를 놓친 구독자는 끊고 다시 연결하게 함
+			if event.Log == "" {
+				delete(entry.subscribers, ch)
+				close(ch)
+			}
+		}
+	}
+	if event.Done {
+		for ch := range entry.subscribers {
+			close(ch)
+		}
+		entry.subscribers = nil
+	}
+}
+
+// trimLogTail 가장 오래된 로그 이벤트부터 지워 로그 줄을 progressLogTail개 이하로 유지
+func trimLogTail(p *ProvisioningProgress) {
+	logs := 0
+	for _, e := range p.Events {
+		if e.Log != "" {
+			logs++
+		}
+	}
+	if logs <= progressLogTail {
+		return
+	}
+	for i, e := range p.Events {
+		if e.Log != "" {
+			p.Events = append(p.Events[:i], p.Events[i+1:]...)
+			return
+		}
+	}
+}
+
+// snapshot 현재 기록의 사본
+func (t *progressTracker) snapshot(sessionID string) (*ProvisioningProgress, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entry, ok := t.entries[sessionID]
+	if !ok {
+		return nil, false
+	}
+	p := entry.progress
+	p.Events = append([]ProgressEvent(nil), entry.progress.Events...)
+	return &p, true
+}
+
+// subscribe 지금까지의 기록과 이후 이벤트를 받을 채널 (끝난 생성이면 채널은 이미 닫혀 있음)
+func (t *progressTracker) subscribe(sessionID string) (*ProvisioningProgress, <-chan ProgressEvent, func(), bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	entry, ok := t.entries[sessionID]
+	if !ok {
+		return nil, nil, nil, false
+	}
+	p := entry.progress
+	p.Events = append([]ProgressEvent(nil), entry.progress.Events...)
+
+	ch := make(chan ProgressEvent, progressBuffer)
+	if p.Done {
+		close(ch)
+		return &p, ch, func() {}, true
+	}
+	entry.subscribers[ch] = struct{}{}
+	cancel := func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if _, ok := entry.subscribers[ch]; ok {
+			delete(entry.subscribers, ch)
+			close(ch)
+		}
+	}
+	return &p, ch, cancel, true
+}
+
+// StartSession 세션 ID를 바로 반환하고 생성은 백그라운드에서 진행 (진행 상황은 SessionProgress/SubscribeProgress로 확인)
+func (s *Service) StartSession(req CreateRequest) (string, error) {
+	done, err := s.beginOperation()
+	if err != nil {
+		return "", err
+	}
+
+	req.sessionID = uuid.New().String()
+	req.submittedAt = time.Now()
+	s.progress.start(req.sessionID, req.UserID, req.ProjectID)
+	go func() {
+		defer done()
+		resp, err := s.createSession(req)
+		s.progress.finish(req.sessionID, resp, err)
+		if err != nil {
+			asyncCreates.Inc("failed")
+			return
+		}
+		asyncCreates.Inc("ready")
+	}()
+	return req.sessionID, nil
+}
+
+// SessionProgress 비동기 생성의 진행 기록 (끝난 뒤 progressRetention 동안 조회 가능)
+func (s *Service) SessionProgress(sessionID string) (*ProvisioningProgress, error) {
+	p, ok := s.progress.snapshot(sessionID)
+	if !ok {
+		return nil, errs.New(errs.ErrNotFound, "세션 %s의 생성 진행 기록이 없습니다 (비동기 생성이 아니거나 기록 보관 시간이 지남)", sessionID)
+	}
+	return p, nil
+}
+
+// SubscribeProgress 지금까지의 기록과 이후 이벤트 채널 (생성이 끝나면 채널이 닫힘, cancel로 구독 해제)
+func (s *Service) SubscribeProgress(sessionID string) (*ProvisioningProgress, <-chan ProgressEvent, func(), error) {
+	p, ch, cancel, ok := s.progress.subscribe(sessionID)
+	if !ok {
+		return nil, nil, nil, errs.New(errs.ErrNotFound, "세션 %s의 생성 진행 기록이 없습니다 (비동기 생성이 아니거나 기록 보관 시간이 지남)", sessionID)
+	}
+	return p, ch, cancel, nil
+}
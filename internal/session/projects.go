@@ -0,0 +1,148 @@
+package session
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// ProjectUsage 프로젝트 단위 리소스 사용량 집계
+type ProjectUsage struct {
+	ProjectID      string `json:"project_id"`
+	ProjectName    string `json:"project_name"`
+	ActiveSessions int    `json:"active_sessions"`
+	GPUSlices      int    `json:"gpu_slices"`
+}
+
+// OrganizationUsage 조직 단위 사용량 (프로젝트 사용량의 합)
+type OrganizationUsage struct {
+	OrgID          string          `json:"org_id"`
+	ActiveSessions int             `json:"active_sessions"`
+	GPUSlices      int             `json:"gpu_slices"`
+	Projects       []*ProjectUsage `json:"projects"`
+}
+
+func (s *Service) CreateOrganization(name string) (*store.Organization, error) {
+	if name == "" {
+		return nil, errs.New(errs.ErrInvalid, "조직 이름이 필요합니다")
+	}
+
+	org := &store.Organization{
+		ID:        uuid.New().String(),
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+	if err := s.store.CreateOrganization(org); err != nil {
+		return nil, fmt.Errorf("조직 생성 실패: %w", err)
+	}
+	return org, nil
+}
+
+func (s *Service) ListOrganizations() ([]*store.Organization, error) {
+	return s.store.ListOrganizations()
+}
+
+func (s *Service) GetOrganization(orgID string) (*store.Organization, error) {
+	return s.store.GetOrganization(orgID)
+}
+
+func (s *Service) DeleteOrganization(orgID string) error {
+	projects, err := s.store.ListProjects(orgID)
+	if err != nil {
+		return err
+	}
+	if len(projects) > 0 {
+		return errs.New(errs.ErrConflict, "조직 %s에 프로젝트 %d개가 남아 있습니다", orgID, len(projects))
+	}
+	return s.store.DeleteOrganization(orgID)
+}
+
+func (s *Service) CreateProject(orgID, name string) (*store.Project, error) {
+	if name == "" {
+		return nil, errs.New(errs.ErrInvalid, "프로젝트 이름이 필요합니다")
+	}
+	if _, err := s.store.GetOrganization(orgID); err != nil {
+		return nil, fmt.Errorf("조직 %s를 찾을 수 없습니다: %w", orgID, err)
+	}
+
+	project := &store.Project{
+		ID:        uuid.New().String(),
+		OrgID:     orgID,
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+	if err := s.store.CreateProject(project); err != nil {
+		return nil, fmt.Errorf("프로젝트 생성 실패: %w", err)
+	}
+	return project, nil
+}
+
+func (s *Service) GetProject(projectID string) (*store.Project, error) {
+	return s.store.GetProject(projectID)
+}
+
+func (s *Service) ListProjects(orgID string) ([]*store.Project, error) {
+	return s.store.ListProjects(orgID)
+}
+
+func (s *Service) DeleteProject(projectID string) error {
+	sessions, err := s.store.ListSessionsByProject(projectID)
+	if err != nil {
+		return err
+	}
+	if len(sessions) > 0 {
+		return errs.New(errs.ErrConflict, "프로젝트 %s에 활성 세션 %d개가 남아 있습니다", projectID, len(sessions))
+	}
+	return s.store.DeleteProject(projectID)
+}
+
+func (s *Service) ListProjectSessions(projectID string) ([]*store.Session, error) {
+	return s.store.ListSessionsByProject(projectID)
+}
+
+// GetProjectUsage 프로젝트에 속한 활성 세션 수와 GPU 슬라이스 사용량 집계
+func (s *Service) GetProjectUsage(project *store.Project) (*ProjectUsage, error) {
+	sessions, err := s.store.ListSessionsByProject(project.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	profiles := s.gpuManager.GetAvailableProfiles()
+	usage := &ProjectUsage{
+		ProjectID:      project.ID,
+		ProjectName:    project.Name,
+		ActiveSessions: len(sessions),
+	}
+	for _, session := range sessions {
+		if profile, ok := profiles[session.MIGProfile]; ok {
+			usage.GPUSlices += profile.GPUSlice
+		}
+	}
+	return usage, nil
+}
+
+// GetOrganizationUsage 조직 산하 모든 프로젝트 사용량을 합산
+func (s *Service) GetOrganizationUsage(orgID string) (*OrganizationUsage, error) {
+	projects, err := s.store.ListProjects(orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &OrganizationUsage{
+		OrgID:    orgID,
+		Projects: make([]*ProjectUsage, 0, len(projects)),
+	}
+	for _, project := range projects {
+		projectUsage, err := s.GetProjectUsage(project)
+		if err != nil {
+			return nil, err
+		}
+		usage.ActiveSessions += projectUsage.ActiveSessions
+		usage.GPUSlices += projectUsage.GPUSlices
+		usage.Projects = append(usage.Projects, projectUsage)
+	}
+	return usage, nil
+}
@@ -0,0 +1,170 @@
+package session
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+)
+
+var (
+	provisioningWorkersGauge = metrics.NewGauge("sandman_provisioning_workers", "세션 생성 워커 수", "state")
+	provisioningQueueGauge   = metrics.NewGauge("sandman_provisioning_queue_depth", "워커를 기다리는 세션 생성 요청 수")
+	provisioningRejected     = metrics.NewCounter("sandman_provisioning_rejected_total", "대기열이 가득 차거나 대기 시간이 초과되어 거부된 요청 수", "reason")
+	provisioningWait         = metrics.NewHistogram("sandman_provisioning_queue_wait_seconds", "워커 배정까지 기다린 시간",
+		[]float64{0.01, 0.1, 0.5, 1, 5, 15, 30, 60, 120})
+)
+
+// ProvisioningStatus 세션 생성 워커 풀 현황 (설정값과 현재 사용량)
+type ProvisioningStatus struct {
+	Workers    int                         `json:"workers"`
+	Busy       int                         `json:"busy"`
+	Queued     int                         `json:"queued"`
+	QueueLimit int                         `json:"queue_limit"`
+	Timeouts   config.ProvisioningTimeouts `json:"timeouts"`
+	Completed  int64                       `json:"completed"`
+	Rejected   int64                       `json:"rejected"`
+
+	// 이미지 빌드/컨테이너 생성 단계의 노드 전체 한도 (node_provisioning_limits, 0이면 제한 없음)
+	NodeID      string `json:"node_id"`
+	NodeLimit   int    `json:"node_limit"`
+	NodeBusy    int    `json:"node_busy"`
+	NodeWaiting int64  `json:"node_waiting"` // 이 복제본에서 기다리는 요청 수
+}
+
+// provisioningPool 동시에 진행되는 세션 생성 수를 provisioning_workers로 제한하고
+// 나머지는 provisioning_queue_limit까지 FIFO로 대기 (리로드한 설정은 다음 요청/반납부터 반영)
+type provisioningPool struct {
+	workers func() int // 현재 설정의 워커 수
+
+	mu        sync.Mutex
+	busy      int
+	waiters   []chan struct{}
+	completed int64
+	rejected  int64
+}
+
+// acquire 워커 슬롯을 얻을 때까지 대기하고 반납 함수를 반환
+func (p *provisioningPool) acquire(cfg *config.Config) (func(), error) {
+	start := time.Now()
+
+	p.mu.Lock()
+	if p.busy < cfg.ProvisioningWorkers && len(p.waiters) == 0 {
+		p.busy++
+		p.updateGauges(cfg.ProvisioningWorkers)
+		p.mu.Unlock()
+		provisioningWait.Observe(0)
+		return p.releaseFunc(), nil
+	}
+	if len(p.waiters) >= cfg.ProvisioningQueueLimit {
+		p.rejected++
+		p.mu.Unlock()
+		provisioningRejected.Inc("queue_full")
+		return nil, errs.New(errs.ErrNoCapacity, "세션 생성 대기열이 가득 찼습니다 (워커 %d개, 대기 %d건) - 잠시 후 다시 시도하세요",
+			cfg.ProvisioningWorkers, cfg.ProvisioningQueueLimit)
+	}
+	ready := make(chan struct{})
+	p.waiters = append(p.waiters, ready)
+	p.updateGauges(cfg.ProvisioningWorkers)
+	p.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if cfg.ProvisioningTimeouts.QueueWaitSeconds > 0 {
+		timer := time.NewTimer(time.Duration(cfg.ProvisioningTimeouts.QueueWaitSeconds) * time.Second)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-ready:
+		provisioningWait.Observe(time.Since(start).Seconds())
+		return p.releaseFunc(), nil
+	case <-timeout:
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if p.removeWaiter(ready) {
+			p.rejected++
+			p.updateGauges(cfg.ProvisioningWorkers)
+			provisioningRejected.Inc("queue_timeout")
+			return nil, errs.New(errs.ErrNoCapacity, "세션 생성 대기 시간 초과 (%ds)", cfg.ProvisioningTimeouts.QueueWaitSeconds)
+		}
+		// 시간 초과와 동시에 슬롯을 받은 경우
+		provisioningWait.Observe(time.Since(start).Seconds())
+		return p.releaseFunc(), nil
+	}
+}
+
+func (p *provisioningPool) releaseFunc() func() {
+	var once sync.Once
+	return func() { once.Do(p.release) }
+}
+
+// release 슬롯 반납 후 워커 수 안에서 대기 중인 요청을 순서대로 깨움
+func (p *provisioningPool) release() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.busy--
+	p.completed++
+	limit := p.workers()
+	for p.busy < limit && len(p.waiters) > 0 {
+		ready := p.waiters[0]
+		p.waiters = p.waiters[1:]
+		p.busy++
+		close(ready)
+	}
+	p.updateGauges(limit)
+}
+
+func (p *provisioningPool) removeWaiter(ready chan struct{}) bool {
+	for i, w := range p.waiters {
+		if w == ready {
+			p.waiters = append(p.waiters[:i], p.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (p *provisioningPool) updateGauges(workers int) {
+	provisioningWorkersGauge.Set(float64(p.busy), "busy")
+	provisioningWorkersGauge.Set(float64(max(workers-p.busy, 0)), "idle")
+	provisioningQueueGauge.Set(float64(len(p.waiters)))
+}
+
+// ProvisioningStatus 워커 풀 현황
+func (s *Service) ProvisioningStatus() *ProvisioningStatus {
+	cfg := s.config.Current()
+
+	nodeBusy := s.nodeSlotsBusy()
+
+	s.provisioning.mu.Lock()
+	defer s.provisioning.mu.Unlock()
+	return &ProvisioningStatus{
+		Workers:    cfg.ProvisioningWorkers,
+		Busy:       s.provisioning.busy,
+		Queued:     len(s.provisioning.waiters),
+		QueueLimit: cfg.ProvisioningQueueLimit,
+		Timeouts:   cfg.ProvisioningTimeouts,
+		Completed:  s.provisioning.completed,
+		Rejected:   s.provisioning.rejected,
+
+		NodeID:      s.nodeID,
+		NodeLimit:   cfg.NodeProvisioningLimitFor(s.nodeID),
+		NodeBusy:    nodeBusy,
+		NodeWaiting: nodeWaiting.Load(),
+	}
+}
+
+// stepTimeouts 컨테이너 생성 단계별 제한 시간
+func stepTimeouts(cfg *config.Config) docker.StepTimeouts {
+	t := cfg.ProvisioningTimeouts
+	return docker.StepTimeouts{
+		ImageBuild:      time.Duration(t.ImageBuildSeconds) * time.Second,
+		ContainerCreate: time.Duration(t.ContainerCreateSeconds) * time.Second,
+		ContainerStart:  time.Duration(t.ContainerStartSeconds) * time.Second,
+	}
+}
@@ -0,0 +1,83 @@
+package session
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"strings"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"golang.org/x/crypto/ssh"
+)
+
+// minRSAKeyBits 허용하는 최소 RSA 키 길이
+const minRSAKeyBits = 2048
+
+// PublicKeyInfo 검증을 통과한 공개키 정보
+type PublicKeyInfo struct {
+	Type        string `json:"type"`
+	Bits        int    `json:"bits,omitempty"`
+	Fingerprint string `json:"fingerprint"`
+	Comment     string `json:"comment,omitempty"`
+	// KeyLine 주석을 제거한 정규화된 authorized_keys 한 줄
+	KeyLine string `json:"key_line"`
+
+	key ssh.PublicKey
+}
+
+// ValidatePublicKey 사용자가 제출한 공개키 한 줄을 해석하고 약한 키, 개인키, 인증서를 거부
+// 협업자 등록과 인증서 발급이 같은 검사를 쓰므로 잘못된 키는 컨테이너 작업 전에 걸러짐
+func ValidatePublicKey(input string) (*PublicKeyInfo, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return nil, errs.New(errs.ErrInvalid, "공개키가 비어 있습니다")
+	}
+	if looksLikePrivateKey(input) {
+		return nil, errs.New(errs.ErrInvalid, "개인키가 입력되었습니다: 공개키(.pub 파일 내용)만 보내고, 이 개인키는 노출되었으니 새로 만드세요")
+	}
+
+	pub, comment, _, rest, err := ssh.ParseAuthorizedKey([]byte(input))
+	if err != nil {
+		return nil, errs.New(errs.ErrInvalid, "공개키 파싱 실패: %v", err)
+	}
+	if len(strings.TrimSpace(string(rest))) > 0 {
+		return nil, errs.New(errs.ErrInvalid, "공개키는 한 번에 하나만 등록할 수 있습니다")
+	}
+	if _, ok := pub.(*ssh.Certificate); ok {
+		return nil, errs.New(errs.ErrInvalid, "인증서가 아닌 일반 공개키가 필요합니다")
+	}
+
+	info := &PublicKeyInfo{
+		Type:        pub.Type(),
+		Fingerprint: ssh.FingerprintSHA256(pub),
+		Comment:     comment,
+		KeyLine:     strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub))),
+		key:         pub,
+	}
+	switch pub.Type() {
+	case ssh.KeyAlgoDSA:
+		return nil, errs.New(errs.ErrInvalid, "DSA 키는 허용하지 않습니다: ed25519 또는 %d비트 이상 RSA 키를 사용하세요", minRSAKeyBits)
+	case ssh.KeyAlgoED25519, ssh.KeyAlgoSKED25519:
+		info.Bits = 256
+	}
+	if crypto, ok := pub.(ssh.CryptoPublicKey); ok {
+		switch key := crypto.CryptoPublicKey().(type) {
+		case *rsa.PublicKey:
+			info.Bits = key.N.BitLen()
+			if info.Bits < minRSAKeyBits {
+				return nil, errs.New(errs.ErrInvalid, "RSA 키가 너무 짧습니다 (%d비트): %d비트 이상이어야 합니다", info.Bits, minRSAKeyBits)
+			}
+		case *ecdsa.PublicKey:
+			info.Bits = key.Curve.Params().BitSize
+		}
+	}
+	return info, nil
+}
+
+// looksLikePrivateKey PEM/OpenSSH/PuTTY 개인키 형식인지 (실수로 id_rsa를 붙여넣은 경우)
+func looksLikePrivateKey(input string) bool {
+	if strings.Contains(input, "PRIVATE KEY-----") || strings.HasPrefix(input, "PuTTY-User-Key-File-") {
+		return true
+	}
+	_, err := ssh.ParseRawPrivateKey([]byte(input))
+	return err == nil
+}
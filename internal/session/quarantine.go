@@ -0,0 +1,130 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// 격리 상태를 담는 세션 메타데이터
+const (
+	metaQuarantinedAt      = "quarantined_at"
+	metaQuarantinedBy      = "quarantined_by"
+	metaQuarantineReason   = "quarantine_reason"
+	metaQuarantinePrevious = "quarantine_previous_state" // 해제할 때 돌아갈 상태
+)
+
+var sessionQuarantines = metrics.NewCounter("sandman_session_quarantines_total", "세션 격리/해제 수 (action: quarantined, lifted)", "action")
+
+// QuarantineSession 침해가 의심되는 세션의 네트워크를 끊고 조사용으로 그대로 보존
+// 컨테이너, 워크스페이스, GPU는 유지되며 해제할 때까지 만료, 연장, 삭제를 포함한 모든 정리가 막힘
+func (s *Service) QuarantineSession(sessionID, actor, reason string) (*store.Session, error) {
+	done, err := s.beginOperation()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	var quarantined *store.Session
+	err = s.locker.WithLock("session-lock:"+sessionID, func() error {
+		session, err := s.localSession(sessionID)
+		if err != nil {
+			return err
+		}
+		if session.State == store.SessionQuarantined {
+			return errs.New(errs.ErrConflict, "세션 %s는 이미 격리되어 있습니다", session.ID)
+		}
+
+		// 잠긴 세션의 깨우기 리스너가 SSH 연결로 컨테이너를 재개하지 않도록 먼저 닫음
+		s.closeWaker(session.ID)
+		s.withdrawRoute(session)
+		if err := s.dockerClient.IsolateContainer(session.ContainerID); err != nil {
+			return err
+		}
+
+		session.Metadata[metaQuarantinePrevious] = session.State
+		session.Metadata[metaQuarantinedAt] = time.Now().UTC().Format(time.RFC3339)
+		session.Metadata[metaQuarantinedBy] = actor
+		session.Metadata[metaQuarantineReason] = reason
+		session.State = store.SessionQuarantined
+		if err := s.store.UpdateSession(session); err != nil {
+			return fmt.Errorf("세션 격리 기록 실패: %w", err)
+		}
+
+		s.liveness.put(s.probeLiveness(session, nil))
+		sessionQuarantines.Inc("quarantined")
+		s.audit(actor, "session.quarantined", session.ID, fmt.Sprintf("user=%s container=%s reason=%s", session.UserID, session.ContainerID, reason))
+		s.recordEvent(session, EventQuarantine, actor, reason, "previous_state="+session.Metadata[metaQuarantinePrevious])
+		log.Printf("🚨 세션 격리: %s (사용자: %s, 사유: %s)", session.ID, session.UserID, reason)
+		quarantined = session
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return quarantined, nil
+}
+
+// LiftQuarantine 세션을 네트워크에 다시 연결하고 격리 전 상태로 되돌림 (이후 삭제, 만료가 다시 가능)
+func (s *Service) LiftQuarantine(sessionID, actor string) (*store.Session, error) {
+	done, err := s.beginOperation()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	var lifted *store.Session
+	err = s.locker.WithLock("session-lock:"+sessionID, func() error {
+		session, err := s.localSession(sessionID)
+		if err != nil {
+			return err
+		}
+		if session.State != store.SessionQuarantined {
+			return errs.New(errs.ErrConflict, "세션 %s는 격리 상태가 아닙니다 (%s)", session.ID, session.State)
+		}
+
+		ip, err := s.dockerClient.ReconnectContainer(session.ContainerID, session.ContainerIP)
+		if err != nil {
+			return err
+		}
+		session.ContainerIP = ip
+		session.State = session.Metadata[metaQuarantinePrevious]
+		if session.State == "" {
+			session.State = store.SessionRunning
+		}
+		for _, key := range []string{metaQuarantinedAt, metaQuarantinedBy, metaQuarantineReason, metaQuarantinePrevious} {
+			delete(session.Metadata, key)
+		}
+		if err := s.store.UpdateSession(session); err != nil {
+			return fmt.Errorf("세션 격리 해제 기록 실패: %w", err)
+		}
+
+		// 잠긴 세션은 다음 비활성 확인에서 깨우기 리스너가 다시 열림
+		if session.State == store.SessionRunning {
+			s.publishRoute(session)
+		}
+		s.liveness.put(s.probeLiveness(session, nil))
+		sessionQuarantines.Inc("lifted")
+		s.audit(actor, "session.quarantine_lifted", session.ID, fmt.Sprintf("user=%s state=%s ip=%s", session.UserID, session.State, ip))
+		s.recordEvent(session, EventUnquarantine, actor, "", "state="+session.State)
+		log.Printf("🔓 세션 격리 해제: %s (사용자: %s, 상태: %s)", session.ID, session.UserID, session.State)
+		lifted = session
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lifted, nil
+}
+
+// checkNotQuarantined 격리된 세션이면 ErrConflict (연장, 삭제, 만료 정리 전에 확인)
+func checkNotQuarantined(session *store.Session) error {
+	if session.State == store.SessionQuarantined {
+		return errs.New(errs.ErrConflict, "세션 %s는 격리되어 있어 관리자가 해제하기 전에는 바꾸거나 정리할 수 없습니다", session.ID)
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+	"github.com/sandman/gpu-ssh-gateway/internal/quota"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// QuotaUsage 현재 사용량과 한도 (Limit 0은 정책상 한도 없음)
+type QuotaUsage struct {
+	Used  int `json:"used"`
+	Limit int `json:"limit"`
+}
+
+// SliceQuota 프로파일별 MIG 인스턴스 점유 현황 (fair-share 정책일 때만 Limit이 있음)
+type SliceQuota struct {
+	Profile string `json:"profile"`
+	QuotaUsage
+	Free  int `json:"free"`
+	Total int `json:"total"`
+}
+
+// TTLQuota 요청할 수 있는 세션 시간과 현재 세션의 남은 시간
+type TTLQuota struct {
+	DefaultMinutes   int `json:"default_minutes"`
+	MaxMinutes       int `json:"max_minutes"`
+	RemainingMinutes int `json:"remaining_minutes,omitempty"`
+}
+
+// QuotaStatus 사용자가 지금 세션을 만들 수 있는지 스스로 확인할 수 있도록 모은 한도와 사용량
+type QuotaStatus struct {
+	UserID          string         `json:"user_id"`
+	ProjectID       string         `json:"project_id,omitempty"`
+	ShareSubject    string         `json:"share_subject"` // 인스턴스 점유를 세는 단위 (project:<id> 또는 user:<id>)
+	SchedulerPolicy string         `json:"scheduler_policy"`
+	Sessions        QuotaUsage     `json:"sessions"`
+	ActiveSessionID string         `json:"active_session_id,omitempty"`
+	TTL             TTLQuota       `json:"ttl"`
+	Slices          []SliceQuota   `json:"slices"`
+	Quotas          []*quota.Usage `json:"quotas"` // 관리자가 건 사용자/팀 한도와 사용량 (모든 노드 기준)
+	CheckedAt       time.Time      `json:"checked_at"`
+}
+
+// QuotaError 한도 때문에 세션 생성이 거부됨 (응답에 현재 사용량과 한도를 함께 보여 줌)
+type QuotaError struct {
+	err   error
+	Quota *QuotaStatus
+}
+
+func (e *QuotaError) Error() string { return e.err.Error() }
+func (e *QuotaError) Unwrap() error { return e.err }
+
+// QuotaStatus 이 노드 기준 사용자(프로젝트)의 동시 세션, MIG 인스턴스, TTL 한도와 사용량
+func (s *Service) QuotaStatus(userID, projectID string) (*QuotaStatus, error) {
+	if err := validateUserID(userID); err != nil {
+		return nil, err
+	}
+	cfg := s.config.Current()
+	status := &QuotaStatus{
+		UserID:          userID,
+		ProjectID:       projectID,
+		ShareSubject:    gpu.ShareSubject(userID, projectID),
+		SchedulerPolicy: cfg.Scheduler.Policy,
+		Sessions:        QuotaUsage{Limit: 1}, // 사용자당 동시 세션은 하나
+		TTL:             TTLQuota{DefaultMinutes: cfg.DefaultTTLMinutes, MaxMinutes: cfg.MaxTTLMinutes},
+		Slices:          []SliceQuota{},
+		CheckedAt:       time.Now(),
+	}
+
+	if session, err := s.store.GetSessionByUserID(userID); err == nil && session != nil {
+		status.Sessions.Used = 1
+		status.ActiveSessionID = session.ID
+		if remaining := time.Until(session.ExpiresAt); remaining > 0 {
+			status.TTL.RemainingMinutes = int(remaining / time.Minute)
+		}
+	}
+
+	quotas, err := s.quotas.Usage(userID, s.profileSlices())
+	if err != nil {
+		return nil, err
+	}
+	status.Quotas = quotas
+	for _, usage := range quotas {
+		if usage.MaxTTLMinutes > 0 && usage.MaxTTLMinutes < status.TTL.MaxMinutes {
+			status.TTL.MaxMinutes = usage.MaxTTLMinutes
+		}
+	}
+
+	holders, err := s.migHolders()
+	if err != nil {
+		return nil, err
+	}
+	var limiter gpu.ShareLimiter
+	if scheduler, err := gpu.NewScheduler(cfg.Scheduler.Policy, cfg.Scheduler.Params); err == nil {
+		limiter, _ = scheduler.(gpu.ShareLimiter)
+	}
+
+	slices := make(map[string]*SliceQuota)
+	for _, instance := range s.gpuManager.ListMIGInstances() {
+		name := instance.Profile.Name
+		if !cfg.ProfileAllowed(name) {
+			continue
+		}
+		slice, ok := slices[name]
+		if !ok {
+			slice = &SliceQuota{Profile: name}
+			slices[name] = slice
+		}
+		slice.Total++
+		if !instance.InUse {
+			slice.Free++
+			continue
+		}
+		if holder, ok := holders[instance.UUID]; ok && gpu.ShareSubject(holder.UserID, holder.ProjectID) == status.ShareSubject {
+			slice.Used++
+		}
+	}
+	for _, slice := range slices {
+		if limiter != nil {
+			slice.Limit = limiter.ShareLimit(slice.Total)
+		}
+		status.Slices = append(status.Slices, *slice)
+	}
+	sort.Slice(status.Slices, func(i, j int) bool { return status.Slices[i].Profile < status.Slices[j].Profile })
+	return status, nil
+}
+
+// quotaError 한도 초과 오류에 현재 사용량을 붙임 (조회에 실패하면 원래 오류만 반환)
+func (s *Service) quotaError(err error, userID, projectID string) error {
+	status, qerr := s.QuotaStatus(userID, projectID)
+	if qerr != nil {
+		return err
+	}
+	return &QuotaError{err: err, Quota: status}
+}
+
+// isShareExceeded 스케줄러가 공정 몫 초과로 거부했는지
+func isShareExceeded(err error) bool {
+	var share *gpu.ShareExceededError
+	return errors.As(err, &share)
+}
+
+// profileSlices 프로파일 이름의 컴퓨트 슬라이스 수 (CPU 전용이나 이 노드에 없는 프로파일은 0)
+func (s *Service) profileSlices() quota.SlicesFunc {
+	profiles := s.gpuManager.GetAvailableProfiles()
+	return func(name string) int { return profiles[name].GPUSlice }
+}
+
+// checkUserQuota 관리자가 건 사용자/팀 한도 안에서 profile 세션을 하나 더 만들 수 있는지
+func (s *Service) checkUserQuota(req CreateRequest, profile string) error {
+	slicesOf := s.profileSlices()
+	demand := quota.Demand{UserID: req.UserID, GPUSlices: slicesOf(profile), TTLMinutes: req.TTLMinutes}
+	if err := s.quotas.Check(demand, slicesOf); err != nil {
+		if errors.Is(err, errs.ErrForbidden) {
+			return s.quotaError(err, req.UserID, req.ProjectID)
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *Service) ListQuotas() ([]*store.Quota, error) {
+	return s.quotas.List()
+}
+
+func (s *Service) GetQuota(subject string) (*store.Quota, error) {
+	return s.quotas.Get(subject)
+}
+
+// SetQuota 사용자 또는 팀의 한도 설정 (이미 실행 중인 세션에는 영향 없음)
+func (s *Service) SetQuota(q store.Quota) (*store.Quota, error) {
+	saved, err := s.quotas.Set(q)
+	if err != nil {
+		return nil, err
+	}
+	s.audit("admin", "quota.set", saved.Subject, fmt.Sprintf("max_sessions=%d max_gpu_slices=%d max_ttl_minutes=%d",
+		saved.MaxSessions, saved.MaxGPUSlices, saved.MaxTTLMinutes))
+	return saved, nil
+}
+
+func (s *Service) DeleteQuota(subject string) error {
+	if err := s.quotas.Delete(subject); err != nil {
+		return err
+	}
+	s.audit("admin", "quota.deleted", subject, "")
+	return nil
+}
@@ -0,0 +1,76 @@
+package session
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/report"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// ExportSessions from 이후, to 이전에 종료된 세션 기록과 사용량을 format(csv, parquet)으로 기록
+// projectID가 있으면 해당 프로젝트 세션만 포함하며, 기록한 세션 수를 반환
+func (s *Service) ExportSessions(w io.Writer, from, to time.Time, projectID, userID, format string) (int, error) {
+	if !report.ValidFormat(format) {
+		return 0, errs.New(errs.ErrInvalid, "지원하지 않는 형식입니다: %q (csv, parquet)", format)
+	}
+	if !from.Before(to) {
+		return 0, errs.New(errs.ErrInvalid, "from(%s)이 to(%s)보다 앞서야 합니다", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	usages, err := s.store.ListEndedSessionUsage(from, to)
+	if err != nil {
+		return 0, err
+	}
+	if projectID != "" || userID != "" {
+		filtered := []*store.SessionUsage{}
+		for _, usage := range usages {
+			if (projectID == "" || usage.ProjectID == projectID) && (userID == "" || usage.UserID == userID) {
+				filtered = append(filtered, usage)
+			}
+		}
+		usages = filtered
+	}
+
+	if err := report.Write(w, format, usages); err != nil {
+		return 0, err
+	}
+	return len(usages), nil
+}
+
+// RunSessionReport 직전 interval 구간(간격 단위로 정렬)에 종료된 세션을 dest에 내보냄
+// 여러 복제본 중 하나만 실행하며, 놓친 구간은 GET /reports/sessions로 채움
+func (s *Service) RunSessionReport(dest report.Destination, format string, interval time.Duration) error {
+	lease, err := s.locker.TryAcquire("session-report")
+	if err != nil {
+		return err
+	}
+	if lease == nil {
+		logging.Debugf("다른 복제본이 세션 보고서 생성 중 - 건너뜀")
+		return nil
+	}
+	defer lease.Release()
+
+	to := time.Now().UTC().Truncate(interval)
+	from := to.Add(-interval)
+
+	var buf bytes.Buffer
+	count, err := s.ExportSessions(&buf, from, to, "", "", format)
+	if err != nil {
+		return err
+	}
+
+	name := report.FileName(from, to, format)
+	if err := dest.Put(name, buf.Bytes()); err != nil {
+		return fmt.Errorf("세션 보고서 업로드 실패 (%s): %w", dest, err)
+	}
+
+	log.Printf("📑 세션 보고서 생성: %s/%s (세션 %d개)", dest, name, count)
+	s.audit("session-report", "report.exported", name, fmt.Sprintf("sessions=%d destination=%s", count, dest))
+	return nil
+}
@@ -0,0 +1,64 @@
+package session
+
+import (
+	"fmt"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+)
+
+// 세션에 실제로 적용한 CPU/메모리 상한 (요청하지 않았으면 프로파일 기본값)
+const (
+	metaCPULimit      = "cpu_limit"
+	metaMemoryLimitMB = "memory_limit_mb"
+)
+
+// resourceLimits 설정의 리소스 클래스를 Docker 리소스 단위로 변환
+func resourceLimits(class config.ResourceClass) docker.ResourceLimits {
+	return docker.ResourceLimits{
+		CPUReservation:    class.CPUReservation,
+		CPULimit:          class.CPULimit,
+		MemoryReservation: class.MemoryReservationMB << 20,
+		MemoryLimit:       class.MemoryLimitMB << 20,
+	}
+}
+
+// requestedResources 요청한 cpu_cores/memory_gb를 프로파일의 상한 대신 적용
+// 최대는 max_cpu_limit/max_memory_limit_mb, 없으면 기본 상한 (둘 다 없으면 제한 없음)
+func requestedResources(class config.ResourceClass, req CreateRequest) (config.ResourceClass, error) {
+	if req.CPUCores > 0 {
+		if ceiling := effectiveMax(class.MaxCPULimit, class.CPULimit); ceiling > 0 && req.CPUCores > ceiling {
+			return class, errs.New(errs.ErrInvalid, "cpu_cores %g가 이 프로파일의 최대 %g를 넘습니다", req.CPUCores, ceiling)
+		}
+		class.CPULimit = req.CPUCores
+		if class.CPUReservation > class.CPULimit {
+			class.CPUReservation = class.CPULimit
+		}
+	}
+	if req.MemoryGB > 0 {
+		memoryMB := int64(req.MemoryGB * 1024)
+		if memoryMB < 64 {
+			return class, errs.New(errs.ErrInvalid, "memory_gb %g는 너무 작습니다 (최소 64MB)", req.MemoryGB)
+		}
+		if ceiling := int64(effectiveMax(float64(class.MaxMemoryLimitMB), float64(class.MemoryLimitMB))); ceiling > 0 && memoryMB > ceiling {
+			return class, errs.New(errs.ErrInvalid, "memory_gb %g가 이 프로파일의 최대 %s를 넘습니다", req.MemoryGB, formatGB(ceiling))
+		}
+		class.MemoryLimitMB = memoryMB
+		if class.MemoryReservationMB >= class.MemoryLimitMB {
+			class.MemoryReservationMB = 0
+		}
+	}
+	return class, nil
+}
+
+func effectiveMax(maximum, limit float64) float64 {
+	if maximum > 0 {
+		return maximum
+	}
+	return limit
+}
+
+func formatGB(mb int64) string {
+	return fmt.Sprintf("%gGB", float64(mb)/1024)
+}
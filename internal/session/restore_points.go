@@ -0,0 +1,264 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/snapshot"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+const (
+	RestorePointScheduled  = "scheduled"
+	RestorePointManual     = "manual"
+	RestorePointPreRestore = "pre-restore" // 복원 직전 워크스페이스 (복원을 되돌릴 때 사용)
+
+	// restorePointDirName 워크스페이스 루트 아래 스냅샷 보관 위치 (사용자 ID는 '.'으로 시작할 수 없어 겹치지 않음)
+	restorePointDirName = ".restore-points"
+)
+
+var restorePointsTotal = metrics.NewCounter("sandman_restore_points_total", "워크스페이스 복원 지점 생성 결과", "reason", "result")
+
+// RestorePointDiff 복원 지점 이후 워크스페이스에서 바뀐 파일
+type RestorePointDiff struct {
+	RestorePoint *store.RestorePoint `json:"restore_point"`
+	*snapshot.Diff
+}
+
+// CreateDueRestorePoints 마지막 복원 지점 이후 설정된 간격이 지난 세션의 워크스페이스 스냅샷 생성
+func (s *Service) CreateDueRestorePoints() error {
+	interval := time.Duration(s.config.Current().RestorePointIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		return nil
+	}
+	if volumes := s.dockerClient.WorkspaceVolumes(); volumes != nil && !volumes.HostReadable() {
+		logging.Debugf("워크스페이스 볼륨을 호스트에서 읽을 수 없어 정기 복원 지점을 만들지 않음")
+		return nil
+	}
+
+	// 같은 워크스페이스를 여러 복제본이 동시에 스냅샷하지 않도록 한 곳에서만 실행
+	lease, err := s.locker.TryAcquire("restore-point-scheduler")
+	if err != nil {
+		return err
+	}
+	if lease == nil {
+		logging.Debugf("다른 복제본이 복원 지점 생성 중 - 건너뜀")
+		return nil
+	}
+	defer lease.Release()
+
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		last := session.CreatedAt
+		if points, err := s.store.ListRestorePoints(session.ID); err == nil && len(points) > 0 {
+			last = points[0].CreatedAt
+		}
+		if time.Since(last) < interval {
+			continue
+		}
+		if _, err := s.createRestorePoint(session.ID, session.UserID, session.ProjectID, RestorePointScheduled); err != nil {
+			log.Printf("⚠️ 세션 %s 복원 지점 생성 실패: %v", session.ID, err)
+		}
+	}
+	return nil
+}
+
+// CreateRestorePoint 세션 워크스페이스의 복원 지점을 즉시 생성
+func (s *Service) CreateRestorePoint(sessionID string) (*store.RestorePoint, error) {
+	session, err := s.store.GetSession(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("세션을 찾을 수 없습니다: %w", err)
+	}
+	return s.createRestorePoint(session.ID, session.UserID, session.ProjectID, RestorePointManual)
+}
+
+// ListRestorePoints 세션에서 만들어진 복원 지점 (세션이 종료된 뒤에도 조회 가능)
+func (s *Service) ListRestorePoints(sessionID string) ([]*store.RestorePoint, error) {
+	return s.store.ListRestorePoints(sessionID)
+}
+
+// GetRestorePoint 세션에 속한 복원 지점
+func (s *Service) GetRestorePoint(sessionID, id string) (*store.RestorePoint, error) {
+	rp, err := s.store.GetRestorePoint(id)
+	if err != nil {
+		return nil, fmt.Errorf("복원 지점 %s를 찾을 수 없습니다: %w", id, err)
+	}
+	if rp.SessionID != sessionID {
+		return nil, errs.New(errs.ErrNotFound, "세션 %s에 복원 지점 %s가 없습니다", sessionID, id)
+	}
+	return rp, nil
+}
+
+// DiffRestorePoint 복원 지점과 사용자의 현재 워크스페이스 비교
+func (s *Service) DiffRestorePoint(sessionID, id string) (*RestorePointDiff, error) {
+	rp, err := s.GetRestorePoint(sessionID, id)
+	if err != nil {
+		return nil, err
+	}
+	workspaceDir, err := s.resolveWorkspaceDir(rp.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	diff, err := snapshot.Compare(rp.Path, workspaceDir)
+	if err != nil {
+		return nil, fmt.Errorf("워크스페이스 비교 실패: %v", err)
+	}
+	return &RestorePointDiff{RestorePoint: rp, Diff: diff}, nil
+}
+
+// createRestorePoint 직전 복원 지점과 바뀌지 않은 파일은 하드 링크로 공유하는 스냅샷 생성 후 보존 개수 정리
+func (s *Service) createRestorePoint(sessionID, userID, projectID, reason string) (*store.RestorePoint, error) {
+	workspaceDir, err := s.resolveWorkspaceDir(userID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(workspaceDir); err != nil {
+		return nil, errs.New(errs.ErrNotFound, "워크스페이스가 없습니다: %s", workspaceDir)
+	}
+	userDir, err := s.restorePointUserDir(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rp *store.RestorePoint
+	err = s.locker.WithLock("restore-points:"+userID, func() error {
+		if err := os.MkdirAll(userDir, 0700); err != nil {
+			return fmt.Errorf("복원 지점 디렉토리 생성 실패: %v", err)
+		}
+
+		var prev string
+		if points, err := s.store.ListUserRestorePoints(userID); err == nil && len(points) > 0 {
+			prev = points[0].Path
+		}
+
+		now := time.Now()
+		id := uuid.New().String()
+		dst := filepath.Join(userDir, now.UTC().Format("20060102T150405Z")+"-"+id[:8])
+
+		start := time.Now()
+		stats, err := snapshot.Create(workspaceDir, dst, prev)
+		if err != nil {
+			return fmt.Errorf("워크스페이스 스냅샷 실패: %v", err)
+		}
+
+		rp = &store.RestorePoint{
+			ID:        id,
+			SessionID: sessionID,
+			UserID:    userID,
+			ProjectID: projectID,
+			Reason:    reason,
+			Path:      dst,
+			Files:     stats.Files,
+			Bytes:     stats.Bytes,
+			CreatedAt: now,
+		}
+		if err := s.store.CreateRestorePoint(rp); err != nil {
+			snapshot.Remove(dst)
+			return fmt.Errorf("복원 지점 저장 실패: %w", err)
+		}
+
+		msg := fmt.Sprintf("📸 복원 지점 생성: %s (세션: %s, 파일: %d개, 새로 복사: %d개, 소요: %v)",
+			id, sessionID, stats.Files, stats.Copied, time.Since(start).Round(time.Millisecond))
+		// 주기 생성은 세션 수만큼 매번 반복되므로 요약 대상
+		if reason == RestorePointScheduled {
+			logging.Routine("restore-point", "%s", msg)
+		} else {
+			log.Print(msg)
+		}
+
+		// 복원 직전 지점은 복원이 끝난 뒤 정리 (복원 대상이 먼저 지워지지 않도록)
+		if reason != RestorePointPreRestore {
+			s.pruneRestorePoints(userID)
+		}
+		return nil
+	})
+	if err != nil {
+		restorePointsTotal.Inc(reason, "error")
+		return nil, err
+	}
+	restorePointsTotal.Inc(reason, "created")
+	return rp, nil
+}
+
+// pruneRestorePoints 사용자별 보존 개수를 넘는 오래된 복원 지점 삭제 (restore-points 락 보유 중 호출)
+func (s *Service) pruneRestorePoints(userID string) {
+	keep := s.config.Current().RestorePointKeep
+	points, err := s.store.ListUserRestorePoints(userID)
+	if err != nil || len(points) <= keep {
+		return
+	}
+
+	for _, rp := range points[keep:] {
+		if err := snapshot.Remove(rp.Path); err != nil {
+			log.Printf("⚠️ 복원 지점 %s 삭제 실패: %v", rp.ID, err)
+			continue
+		}
+		if err := s.store.DeleteRestorePoint(rp.ID); err != nil {
+			log.Printf("⚠️ 복원 지점 %s 기록 삭제 실패: %v", rp.ID, err)
+			continue
+		}
+		logging.Debugf("오래된 복원 지점 삭제: %s (사용자: %s)", rp.ID, userID)
+	}
+}
+
+// restoreWorkspace 새 세션을 시작하기 전에 워크스페이스를 복원 지점 내용으로 교체
+// 현재 내용은 pre-restore 복원 지점으로 먼저 남겨 복원 자체를 되돌릴 수 있게 함
+func (s *Service) restoreWorkspace(sessionID, userID, projectID, workspaceDir, restorePointID string) error {
+	rp, err := s.lookupRestorePoint(userID, restorePointID)
+	if err != nil {
+		return err
+	}
+
+	if s.workspacePresent(workspaceDir) {
+		if _, err := s.createRestorePoint(sessionID, userID, projectID, RestorePointPreRestore); err != nil {
+			return fmt.Errorf("복원 전 워크스페이스 보존 실패: %w", err)
+		}
+	} else if err := os.MkdirAll(workspaceDir, 0755); err != nil {
+		return fmt.Errorf("워크스페이스 생성 실패: %v", err)
+	}
+
+	return s.locker.WithLock("restore-points:"+userID, func() error {
+		stats, err := snapshot.Restore(rp.Path, workspaceDir)
+		if err != nil {
+			return fmt.Errorf("워크스페이스 복원 실패: %v", err)
+		}
+		log.Printf("♻️ 워크스페이스 복원: 사용자 %s ← 복원 지점 %s (%s, 파일 %d개)",
+			userID, rp.ID, rp.CreatedAt.Format(time.RFC3339), stats.Files)
+		s.pruneRestorePoints(userID)
+		return nil
+	})
+}
+
+// lookupRestorePoint 사용자의 복원 지점과 스냅샷이 있는지 확인 (세션 생성 요청을 검증할 때도 사용)
+func (s *Service) lookupRestorePoint(userID, restorePointID string) (*store.RestorePoint, error) {
+	rp, err := s.store.GetRestorePoint(restorePointID)
+	if err != nil {
+		return nil, fmt.Errorf("복원 지점 %s를 찾을 수 없습니다: %w", restorePointID, err)
+	}
+	if rp.UserID != userID {
+		return nil, errs.New(errs.ErrInvalid, "복원 지점 %s는 사용자 %s의 워크스페이스가 아닙니다", restorePointID, userID)
+	}
+	if _, err := os.Stat(rp.Path); err != nil {
+		return nil, errs.New(errs.ErrNotFound, "복원 지점 %s의 스냅샷이 없습니다", restorePointID)
+	}
+	return rp, nil
+}
+
+func (s *Service) restorePointUserDir(userID string) (string, error) {
+	root, err := filepath.Abs(s.workspaceRoot)
+	if err != nil {
+		return "", fmt.Errorf("워크스페이스 루트 경로 확인 실패: %v", err)
+	}
+	return filepath.Join(root, restorePointDirName, userID), nil
+}
@@ -0,0 +1,213 @@
+package session
+
+import (
+	"context"
+	"log"
+	"net"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/sshpiper"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// SetRouter SSHPiper 라우트 게시 활성화 (이 노드 세션으로 테이블을 다시 만듦)
+func (s *Service) SetRouter(router *sshpiper.Router) error {
+	s.router = router
+
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		return err
+	}
+	var routes []sshpiper.Route
+	for _, session := range sessions {
+		if route, ok := s.sessionRoute(session); ok {
+			routes = append(routes, route)
+			routes = append(routes, s.collaboratorRoutes(session, route)...)
+		}
+	}
+	router.Sync(routes)
+	log.Printf("🔀 SSHPiper 라우트 %d개 동기화 요청", len(routes))
+	return nil
+}
+
+// sessionRoute 이 노드 세션의 컨테이너 sshd로 가는 라우트 (SSH 사용자 이름 = 사용자 ID, 라우터가 있을 때만 호출)
+func (s *Service) sessionRoute(session *store.Session) (sshpiper.Route, bool) {
+	if session.ContainerIP == "" || (session.NodeID != "" && session.NodeID != s.nodeID) {
+		return sshpiper.Route{}, false
+	}
+	route := sshpiper.Route{
+		User:       session.UserID,
+		Host:       session.ContainerIP,
+		Port:       22,
+		TargetUser: session.UserID,
+		SessionID:  session.ID,
+		CreatedAt:  session.CreatedAt,
+	}
+	// 사용자 공개키를 등록한 세션은 게이트웨이에서 그 키로 받고 세션 키로 대상에 로그인
+	if keys := gatewayKeys(session); len(keys) > 0 {
+		route.AuthorizedKeys = keys
+		route.PrivateKeyFile = s.router.KeyPath(session.ID)
+	}
+	return route, true
+}
+
+// collaboratorRoutes 협업자가 <협업자>@gateway로 세션 컨테이너에 소유자 계정으로 들어가는 라우트 (대상은 owner와 같음)
+// 라우트는 SSH 사용자 이름마다 하나이므로 협업자가 이 노드에 자기 세션을 가졌으면 그 라우트가 우선
+func (s *Service) collaboratorRoutes(session *store.Session, owner sshpiper.Route) []sshpiper.Route {
+	collabs, err := s.store.ListCollaborators(session.ID)
+	if err != nil {
+		log.Printf("⚠️ 세션 %s 협업자 조회 실패: %v", session.ID, err)
+		return nil
+	}
+	var routes []sshpiper.Route
+	for _, collab := range collabs {
+		if s.hasOwnRoute(collab.UserID) {
+			continue
+		}
+		route := owner
+		route.User = collab.UserID
+		// 게이트웨이에서 키로 받는 세션이면 협업자 키만 받고 세션 키로 대상에 로그인
+		if len(owner.AuthorizedKeys) > 0 {
+			route.AuthorizedKeys = []string{collab.PublicKey}
+		}
+		routes = append(routes, route)
+	}
+	return routes
+}
+
+// hasOwnRoute 사용자가 이 노드에 자기 세션 라우트를 가지고 있는지
+func (s *Service) hasOwnRoute(userID string) bool {
+	session, err := s.store.GetSessionByUserID(userID)
+	if err != nil {
+		return false
+	}
+	_, ok := s.sessionRoute(session)
+	return ok
+}
+
+// publishRoute 라우트 추가/변경을 큐에 넣음 (파일 반영은 라우터가 묶어서 처리)
+func (s *Service) publishRoute(session *store.Session) {
+	s.publishRouteContext(context.Background(), session)
+}
+
+// publishRouteContext ctx의 span을 라우트 반영 span에 링크로 남김
+func (s *Service) publishRouteContext(ctx context.Context, session *store.Session) {
+	if s.router == nil {
+		return
+	}
+	if route, ok := s.sessionRoute(session); ok {
+		s.router.UpsertContext(ctx, route)
+		for _, collab := range s.collaboratorRoutes(session, route) {
+			s.router.UpsertContext(ctx, collab)
+		}
+	}
+}
+
+// publishCollaboratorRoute <협업자>@gateway로 들어올 수 있게 협업자 라우트만 게시 (잠긴 세션이면 깨우기 리스너로)
+func (s *Service) publishCollaboratorRoute(session *store.Session, userID string) {
+	if s.router == nil {
+		return
+	}
+	for _, route := range s.expectedRoutes(session) {
+		if route.User == userID {
+			s.router.Upsert(route)
+		}
+	}
+}
+
+// withdrawRoute 소유자와 협업자 라우트를 내림 (협업자 정보를 지우기 전에 호출)
+func (s *Service) withdrawRoute(session *store.Session) {
+	if s.router == nil || (session.NodeID != "" && session.NodeID != s.nodeID) {
+		return
+	}
+	s.router.Remove(session.UserID)
+	collabs, err := s.store.ListCollaborators(session.ID)
+	if err != nil {
+		log.Printf("⚠️ 세션 %s 협업자 조회 실패: %v", session.ID, err)
+		return
+	}
+	for _, collab := range collabs {
+		s.withdrawCollaboratorRoute(session, collab.UserID)
+	}
+}
+
+// withdrawCollaboratorRoute 협업자 라우트를 내림 (협업자 자신의 세션 라우트는 그대로 둠)
+func (s *Service) withdrawCollaboratorRoute(session *store.Session, userID string) {
+	if s.router == nil || (session.NodeID != "" && session.NodeID != s.nodeID) || s.hasOwnRoute(userID) {
+		return
+	}
+	s.router.Remove(userID)
+}
+
+// RouteSnapshot 저장소 기준 라우트 테이블과 sshpiperd가 읽는 설정 파일의 비교
+type RouteSnapshot struct {
+	ConfigPath string                `json:"config_path"`
+	Routes     []sshpiper.Route      `json:"routes"`
+	Loaded     []sshpiper.Route      `json:"loaded"`
+	LoadError  string                `json:"load_error,omitempty"`
+	Drift      []sshpiper.RouteDrift `json:"drift"`
+	InSync     bool                  `json:"in_sync"`
+	CheckedAt  time.Time             `json:"checked_at"`
+}
+
+// RouteSnapshot 이 노드 세션으로 만든 라우트 테이블과 설정 파일 라우트의 차이
+func (s *Service) RouteSnapshot() (*RouteSnapshot, error) {
+	if s.router == nil {
+		return nil, errs.New(errs.ErrConflict, "SSHPiper 라우트 게시가 설정되지 않았습니다 (-sshpiper-config)")
+	}
+
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &RouteSnapshot{
+		ConfigPath: s.router.Path(),
+		Routes:     []sshpiper.Route{},
+		CheckedAt:  time.Now(),
+	}
+	for _, session := range sessions {
+		snapshot.Routes = append(snapshot.Routes, s.expectedRoutes(session)...)
+	}
+
+	loaded, err := s.router.Loaded()
+	if err != nil {
+		snapshot.LoadError = err.Error()
+	}
+	if loaded == nil {
+		loaded = []sshpiper.Route{}
+	}
+	snapshot.Loaded = loaded
+	snapshot.Drift = sshpiper.Diff(snapshot.Routes, loaded, s.router.Pending())
+	if snapshot.Drift == nil {
+		snapshot.Drift = []sshpiper.RouteDrift{}
+	}
+	snapshot.InSync = err == nil && len(snapshot.Drift) == 0
+	return snapshot, nil
+}
+
+// expectedRoutes 세션에 대해 지금 게시돼 있어야 하는 소유자와 협업자 라우트
+func (s *Service) expectedRoutes(session *store.Session) []sshpiper.Route {
+	route, ok := s.expectedRoute(session)
+	if !ok {
+		return nil
+	}
+	return append([]sshpiper.Route{route}, s.collaboratorRoutes(session, route)...)
+}
+
+// expectedRoute 지금 게시돼 있어야 하는 라우트 (잠긴 세션은 깨우기 리스너가 있으면 그쪽)
+func (s *Service) expectedRoute(session *store.Session) (sshpiper.Route, bool) {
+	route, ok := s.sessionRoute(session)
+	if !ok || session.State != store.SessionLocked {
+		return route, ok
+	}
+	s.inactivity.mu.Lock()
+	ln, waking := s.inactivity.wakers[session.ID]
+	s.inactivity.mu.Unlock()
+	if waking {
+		route.Host = s.wakeHost
+		route.Port = ln.Addr().(*net.TCPAddr).Port
+		route.Wake = true
+	}
+	return route, ok
+}
@@ -0,0 +1,139 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// EndReasonPreempted 더 높은 우선순위 요청에 인스턴스를 내주고 종료됨
+const EndReasonPreempted = "preempted"
+
+var schedulerPlacements = metrics.NewCounter("sandman_scheduler_placements_total", "스케줄러 배치 결정 수", "policy", "outcome")
+
+// scheduleMIG 설정된 정책으로 인스턴스를 고르고 할당 (gpu-allocation 락을 잡은 상태에서 호출)
+func (s *Service) scheduleMIG(req CreateRequest, cfg *config.Config) (*gpu.MIGInstance, error) {
+	scheduler, err := gpu.NewScheduler(cfg.Scheduler.Policy, cfg.Scheduler.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	spot := req.Tier == store.TierSpot
+	if spot {
+		if err := s.checkSpotPlacement(req.MIGProfile); err != nil {
+			schedulerPlacements.Inc(scheduler.Name(), "rejected")
+			return nil, err
+		}
+	}
+
+	// 최근 세션이 끝난 사용자는 이전 인스턴스를 우선 배정 (데이터 지역성), 다른 사용자는 예약된 인스턴스를 뒤로 미룸
+	var reserved map[string]bool
+	if cfg.MIGReservationMinutes > 0 {
+		if instance := s.allocateReserved(req); instance != nil {
+			schedulerPlacements.Inc(scheduler.Name(), "reserved")
+			return instance, nil
+		}
+		reserved = s.reservedForOthers(req.UserID)
+	}
+
+	holders, err := s.migHolders()
+	if err != nil {
+		return nil, err
+	}
+
+	placement, err := s.gpuManager.Schedule(scheduler, gpu.PlacementRequest{
+		UserID:    req.UserID,
+		ProjectID: req.ProjectID,
+		Profile:   req.MIGProfile,
+		Priority:  req.Priority,
+		Reserved:  reserved,
+		Hint:      req.Placement,
+	}, holders)
+	// 빈 인스턴스가 없으면 spot 회수나 선점보다 먼저 GPU의 남는 슬라이스에 새로 만듦
+	if cfg.MIGOnDemand.Enabled && ((err != nil && errors.Is(err, errs.ErrNoCapacity)) || (err == nil && placement.Preempt)) {
+		if instance := s.carveMIG(req); instance != nil {
+			schedulerPlacements.Inc(scheduler.Name(), "carved")
+			return instance, nil
+		}
+	}
+	if err != nil && !spot && errors.Is(err, errs.ErrNoCapacity) {
+		// 빈 인스턴스가 없으면 spot 세션을 회수해 standard 요청에 내줌
+		instance, reclaimErr := s.reclaimSpot(req, cfg)
+		if instance != nil {
+			schedulerPlacements.Inc(scheduler.Name(), "reclaimed")
+			return instance, nil
+		}
+		if reclaimErr != nil {
+			err = reclaimErr
+		}
+	}
+	if err != nil {
+		schedulerPlacements.Inc(scheduler.Name(), "rejected")
+		return nil, err
+	}
+
+	// spot 세션은 남는 인스턴스만 쓰고 다른 세션을 밀어내지 않음
+	if placement.Preempt && spot {
+		schedulerPlacements.Inc(scheduler.Name(), "rejected")
+		return nil, errs.New(errs.ErrNoCapacity, "프로파일 %s의 남는 인스턴스가 없어 spot 세션을 만들 수 없습니다", req.MIGProfile)
+	}
+
+	if placement.Preempt {
+		if err := s.preempt(placement, req); err != nil {
+			schedulerPlacements.Inc(scheduler.Name(), "rejected")
+			return nil, err
+		}
+		schedulerPlacements.Inc(scheduler.Name(), "preempted")
+	} else {
+		schedulerPlacements.Inc(scheduler.Name(), "placed")
+	}
+
+	log.Printf("🧭 배치 결정 (%s): 프로파일=%s, 인스턴스=%s (%s), GPU=%d", scheduler.Name(), req.MIGProfile, placement.Instance.Name, placement.Instance.UUID, placement.Instance.GPUIndex)
+	return s.gpuManager.AllocateMIGByUUID(placement.Instance.UUID, req.UserID)
+}
+
+// migHolders 이 노드에서 MIG 인스턴스를 점유 중인 세션 (UUID별)
+func (s *Service) migHolders() (map[string]gpu.Holder, error) {
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		return nil, fmt.Errorf("세션 목록 조회 실패: %v", err)
+	}
+
+	holders := make(map[string]gpu.Holder, len(sessions))
+	for _, session := range sessions {
+		if (session.NodeID != "" && session.NodeID != s.nodeID) || session.GPUUUID == "" {
+			continue
+		}
+		holders[session.GPUUUID] = gpu.Holder{
+			SessionID: session.ID,
+			UserID:    session.UserID,
+			ProjectID: session.ProjectID,
+			Priority:  session.Priority,
+			CreatedAt: session.CreatedAt,
+		}
+	}
+	return holders, nil
+}
+
+// preempt 스케줄러가 고른 낮은 우선순위 세션을 정리해 인스턴스를 비움
+func (s *Service) preempt(placement *gpu.Placement, req CreateRequest) error {
+	victim, err := s.store.GetSession(placement.Holder.SessionID)
+	if err != nil {
+		return fmt.Errorf("선점 대상 세션 %s 조회 실패: %w", placement.Holder.SessionID, err)
+	}
+
+	log.Printf("⏏️ 세션 선점: %s (사용자: %s, 우선순위 %d) → 사용자 %s (우선순위 %d)",
+		victim.ID, victim.UserID, victim.Priority, req.UserID, req.Priority)
+	if err := s.cleanupSession(victim, EndReasonPreempted); err != nil {
+		return fmt.Errorf("선점 대상 세션 %s 정리 실패: %w", victim.ID, err)
+	}
+	s.audit("scheduler", "session.preempted", victim.ID,
+		fmt.Sprintf("user=%s priority=%d preempted_by=%s priority=%d", victim.UserID, victim.Priority, req.UserID, req.Priority))
+	return nil
+}
@@ -0,0 +1,118 @@
+package session
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/auth"
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// sensitiveMetadata 세션 조회/목록 응답에서 빼는 메타데이터 (GET /sessions/:id/credentials로만 조회)
+var sensitiveMetadata = []string{"ssh_password"}
+
+// SessionCredentials 세션 접속 자격 증명
+// 개인키는 저장하지 않으므로 생성 응답과 일회용 링크에서만 포함됨
+type SessionCredentials struct {
+	SessionID     string `json:"session_id"`
+	SSHUser       string `json:"ssh_user"`
+	SSHPort       int    `json:"ssh_port"`
+	SSHPassword   string `json:"ssh_password,omitempty"`
+	SSHPrivateKey string `json:"ssh_private_key,omitempty"`
+
+	Deployment *config.Deployment `json:"deployment,omitempty"` // 어느 설치의 세션인지 (설정하지 않았으면 없음)
+}
+
+// redactSession 민감한 메타데이터를 뺀 사본 (원본 세션은 바꾸지 않음)
+func redactSession(session *store.Session) *store.Session {
+	redacted := *session
+	redacted.Metadata = make(map[string]string, len(session.Metadata))
+	for key, value := range session.Metadata {
+		redacted.Metadata[key] = value
+	}
+	for _, key := range sensitiveMetadata {
+		delete(redacted.Metadata, key)
+	}
+	return &redacted
+}
+
+// issueCredentialLink 자격 증명을 한 번만 내려주는 링크 토큰 발급 (평문 토큰은 호출자에게만 반환)
+func (s *Service) issueCredentialLink(session *store.Session, privateKey string) (string, time.Time, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, fmt.Errorf("링크 토큰 생성 실패: %v", err)
+	}
+	raw := hex.EncodeToString(b)
+
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(s.config.Current().CredentialLinkMinutes) * time.Minute)
+	if expiresAt.After(session.ExpiresAt) {
+		expiresAt = session.ExpiresAt
+	}
+
+	link := &store.CredentialLink{
+		TokenHash:     auth.HashToken(raw),
+		SessionID:     session.ID,
+		SSHPassword:   session.Metadata["ssh_password"],
+		SSHPrivateKey: privateKey,
+		CreatedAt:     now,
+		ExpiresAt:     expiresAt,
+	}
+	if err := s.store.CreateCredentialLink(link); err != nil {
+		return "", time.Time{}, fmt.Errorf("자격 증명 링크 저장 실패: %v", err)
+	}
+	return raw, expiresAt, nil
+}
+
+// RedeemCredentialLink 일회용 링크로 자격 증명 조회 (사용 즉시 삭제, 만료된 링크는 거부)
+func (s *Service) RedeemCredentialLink(raw string) (*SessionCredentials, error) {
+	link, err := s.store.TakeCredentialLink(auth.HashToken(raw))
+	if err != nil {
+		return nil, errs.New(errs.ErrNotFound, "이미 사용했거나 존재하지 않는 링크입니다")
+	}
+	if time.Now().After(link.ExpiresAt) {
+		return nil, errs.New(errs.ErrNotFound, "만료된 링크입니다")
+	}
+
+	session, err := s.store.GetSession(link.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("세션을 찾을 수 없습니다: %w", err)
+	}
+
+	log.Printf("🔗 일회용 자격 증명 링크 사용: 세션=%s", session.ID)
+	s.audit("credential-link", "session.credentials_redeemed", session.ID, "")
+	return &SessionCredentials{
+		SessionID:     session.ID,
+		SSHUser:       session.UserID,
+		SSHPort:       session.SSHPort,
+		SSHPassword:   link.SSHPassword,
+		SSHPrivateKey: link.SSHPrivateKey,
+		Deployment:    s.deployment(),
+	}, nil
+}
+
+// GetSessionCredentials 세션 소유자/credentials:read 토큰용 자격 증명 조회 (개인키는 포함되지 않음)
+func (s *Service) GetSessionCredentials(session *store.Session, actor string) *SessionCredentials {
+	s.audit(actor, "session.credentials_read", session.ID, "")
+	return &SessionCredentials{
+		SessionID:   session.ID,
+		SSHUser:     session.UserID,
+		SSHPort:     session.SSHPort,
+		SSHPassword: session.Metadata["ssh_password"],
+		Deployment:  s.deployment(),
+	}
+}
+
+// deployment 접속 정보에 넣을 배포 정보 (설정하지 않았으면 nil)
+func (s *Service) deployment() *config.Deployment {
+	d := s.config.Current().Deployment
+	if d.IsZero() {
+		return nil
+	}
+	return &d
+}
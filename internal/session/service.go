@@ -1,42 +1,119 @@
 package session
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
-	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
 	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
 	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+	"github.com/sandman/gpu-ssh-gateway/internal/lock"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/manifest"
+	"github.com/sandman/gpu-ssh-gateway/internal/nodeca"
+	"github.com/sandman/gpu-ssh-gateway/internal/quota"
+	"github.com/sandman/gpu-ssh-gateway/internal/sshca"
+	"github.com/sandman/gpu-ssh-gateway/internal/sshpiper"
 	"github.com/sandman/gpu-ssh-gateway/internal/store"
+	"github.com/sandman/gpu-ssh-gateway/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type CreateRequest struct {
-	UserID          string `json:"user_id" binding:"required"`
-	TTLMinutes      int    `json:"ttl_minutes"`
-	MIGProfile      string `json:"mig_profile"`
-	MIGInstanceUUID string `json:"mig_instance_uuid,omitempty"`
-	Image           string `json:"image,omitempty"`
+	UserID             string   `json:"user_id" binding:"required"`
+	ProjectID          string   `json:"project_id,omitempty"`
+	TTLMinutes         int      `json:"ttl_minutes"`
+	ExpireAt           string   `json:"expire_at,omitempty"`     // "18:00" (사용자 시간대 기준 오늘) 또는 RFC3339
+	ExpiryPolicy       string   `json:"expiry_policy,omitempty"` // "end_of_day", "end_of_week" 또는 cron 표현식 (배포 시간대 기준)
+	MIGProfile         string   `json:"mig_profile"`
+	MIGInstanceUUID    string   `json:"mig_instance_uuid,omitempty"` // UUID 또는 인스턴스 이름 (gpu0-slot4-3g.40gb)
+	Image              string   `json:"image,omitempty"`
+	RestorePointID     string   `json:"restore_point_id,omitempty"`     // 시작 전에 워크스페이스를 이 복원 지점으로 되돌림
+	Priority           int      `json:"priority,omitempty"`             // priority-preempt 정책에서 더 낮은 우선순위 세션을 밀어낼 수 있음
+	Tier               string   `json:"tier,omitempty"`                 // "standard"(기본) 또는 "spot" (남는 인스턴스를 싼 요율로, 회수될 수 있음)
+	Placement          string   `json:"placement,omitempty"`            // "pack" 또는 "spread" (빈 인스턴스 중 GPU/NUMA 노드 선택 힌트)
+	FromManifest       string   `json:"from_manifest,omitempty"`        // 이 세션 ID의 매니페스트와 같은 템플릿, 프로파일, 베이스 이미지로 생성
+	StopTimeoutSeconds int      `json:"stop_timeout_seconds,omitempty"` // 종료 시 SIGTERM 후 SIGKILL까지 (없으면 템플릿의 stop_policies)
+	CPUCores           float64  `json:"cpu_cores,omitempty"`            // CPU 상한 코어 수 (없으면 프로파일의 cpu_limit)
+	MemoryGB           float64  `json:"memory_gb,omitempty"`            // 메모리 상한 GB (없으면 프로파일의 memory_limit_mb)
+	SSHPublicKeys      []string `json:"ssh_public_keys,omitempty"`      // 게이트웨이 로그인에 쓸 사용자 공개키 (있으면 비밀번호 없이 세션 키로 전달)
+	WorkspaceQuotaGB   int      `json:"workspace_quota_gb,omitempty"`   // 워크스페이스 디스크 한도 GB (없으면 workspace_quota.default_gb)
+
+	sessionID   string          // 비동기 생성에서 미리 정한 세션 ID (비어 있으면 새로 만듦)
+	ctx         context.Context // 생성 span의 부모 트레이스 (WithContext)
+	submittedAt time.Time       // 요청을 받은 시각 (대기 시간 통계, 비어 있으면 생성 시작 시각)
+}
+
+// WithContext 생성 span을 ctx의 트레이스 아래에 만듦 (취소는 따르지 않으므로 요청이 끊겨도 생성은 계속됨)
+func (r CreateRequest) WithContext(ctx context.Context) CreateRequest {
+	r.ctx = tracing.Detach(ctx)
+	return r
 }
 
 type CreateResponse struct {
-	SessionID     string    `json:"session_id"`
-	ContainerID   string    `json:"container_id"`
-	SSHUser       string    `json:"ssh_user"`
-	SSHHost       string    `json:"ssh_host"`
-	SSHPort       int       `json:"ssh_port"`
-	SSHPrivateKey string    `json:"ssh_private_key,omitempty"`
-	GPUUUID       string    `json:"gpu_uuid"`
-	CreatedAt     time.Time `json:"created_at"`
-	ExpiresAt     time.Time `json:"expires_at"`
+	SessionID      string    `json:"session_id"`
+	ProjectID      string    `json:"project_id,omitempty"`
+	ContainerID    string    `json:"container_id"`
+	SSHUser        string    `json:"ssh_user"`
+	SSHHost        string    `json:"ssh_host"`
+	SSHPort        int       `json:"ssh_port"`
+	SSHPrivateKey  string    `json:"ssh_private_key,omitempty"`
+	GPUUUID        string    `json:"gpu_uuid"`
+	GPUName        string    `json:"gpu_name,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	ExpiresAtLocal string    `json:"expires_at_local"`
+	Timezone       string    `json:"timezone"`
+	SSHPrincipal   string    `json:"ssh_principal,omitempty"` // SSH CA 모드에서 인증서에 들어가는 principal
+	SSHPassword    string    `json:"ssh_password"`
+
+	WorkspaceQuotaGB int `json:"workspace_quota_gb,omitempty"` // 워크스페이스에 건 디스크 한도 (쿼터가 없으면 없음)
+
+	// 자격 증명을 최종 사용자에게 넘길 때 쓰는 일회용 링크 (GET /credentials/{token})
+	CredentialsToken     string     `json:"credentials_token,omitempty"`
+	CredentialsExpiresAt *time.Time `json:"credentials_expires_at,omitempty"`
+
+	Deployment *config.Deployment `json:"deployment,omitempty"` // 어느 설치의 세션인지 (설정하지 않았으면 없음)
 }
 
 type Service struct {
-	store         store.Store
-	dockerClient  *docker.Client
-	gpuManager    *gpu.Manager
-	workspaceRoot string
+	store          store.Store
+	dockerClient   *docker.Client
+	gpuManager     *gpu.Manager
+	workspaceRoot  string
+	config         *config.Manager
+	locker         *lock.Locker
+	sshCA          *sshca.CA
+	manifestSigner *manifest.Signer
+	nodeID         string
+	provisioning   *provisioningPool
+	liveness       *livenessCache
+	progress       *progressTracker
+	router         *sshpiper.Router
+	restarts       *restartTracker
+	clock          clockState
+	dockerEvents   dockerEventState
+	status         statusCache
+	inactivity     *inactivityTracker
+	wakeHost       string
+	consistency    consistencyState
+	quotas         *quota.Manager
+	nodeCA         *nodeca.CA
+	nodeCertTTL    time.Duration
+	gpuClaims      gpuClaimState
+
+	// 종료 시 진행 중인 생성/정리 작업을 기다리기 위한 추적
+	opMu     sync.Mutex
+	closing  bool
+	inflight sync.WaitGroup
+	hooks    sync.WaitGroup // 실행 중인 정리 훅
 }
 
 func NewService(
@@ -44,109 +121,529 @@ func NewService(
 	dockerClient *docker.Client,
 	gpuManager *gpu.Manager,
 	workspaceRoot string,
+	cfg *config.Manager,
+	locker *lock.Locker,
 ) *Service {
-	return &Service{
+	s := &Service{
 		store:         store,
 		dockerClient:  dockerClient,
 		gpuManager:    gpuManager,
 		workspaceRoot: workspaceRoot,
+		config:        cfg,
+		locker:        locker,
+		provisioning: &provisioningPool{
+			workers: func() int { return cfg.Current().ProvisioningWorkers },
+		},
+		liveness:   newLivenessCache(),
+		progress:   newProgressTracker(),
+		restarts:   newRestartTracker(),
+		inactivity: newInactivityTracker(),
+		quotas:     quota.NewManager(store),
 	}
+	cfg.AddGuard(s.guardConfigChange)
+	return s
 }
 
 func (s *Service) CreateSession(req CreateRequest) (*CreateResponse, error) {
+	done, err := s.beginOperation()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+	return s.createSession(req)
+}
+
+// createSession 세션 생성 본체 (호출자가 beginOperation으로 종료 대기 대상에 등록)
+// session.create span 아래에 진행 단계마다 span을 만들고, 실패하면 마지막 단계와 전체 span에 오류를 기록
+func (s *Service) createSession(req CreateRequest) (resp *CreateResponse, err error) {
+	ctx, span := tracing.Start(req.ctx, "session.create",
+		attribute.String("sandman.user_id", req.UserID),
+		attribute.String("sandman.project_id", req.ProjectID),
+		attribute.String("sandman.mig_profile", req.MIGProfile),
+		attribute.Bool("sandman.async", req.sessionID != ""))
+	phases := tracing.NewPhases(ctx, "session.create.")
+	sessionID := req.sessionID
+	defer func() {
+		phases.End(err)
+		// ID를 정한 뒤의 실패만 세션 이벤트로 남김 (비동기 생성은 요청을 받을 때 ID가 정해짐)
+		if err != nil && sessionID != "" {
+			s.recordEvent(&store.Session{ID: sessionID, UserID: req.UserID}, EventFailure, "user:"+req.UserID, err.Error(), "")
+		}
+		if resp != nil {
+			span.SetAttributes(attribute.String("sandman.session_id", resp.SessionID), attribute.String("sandman.gpu_uuid", resp.GPUUUID))
+		}
+		tracing.End(span, err)
+	}()
+	if req.submittedAt.IsZero() {
+		req.submittedAt = time.Now()
+	}
+	step := func(sessionID, name string) {
+		s.progress.step(sessionID, name, "")
+		phases.Start(name)
+	}
+
+	// 워크스페이스 경로 검증 (경로 탐색, 심볼릭 링크 탈출 방지)
+	// 호스트에서 읽을 수 없는 볼륨은 컨테이너가 직접 마운트하고 호스트 측 파일 기능은 건너뜀
+	workspaceDir, err := s.resolveWorkspaceDir(req.UserID)
+	if errors.Is(err, errWorkspaceNotOnHost) {
+		workspaceDir = ""
+	} else if err != nil {
+		return nil, err
+	}
+
 	// 기존 세션 확인
 	existingSession, err := s.store.GetSessionByUserID(req.UserID)
 	if err == nil && existingSession != nil {
-		return nil, fmt.Errorf("사용자 %s의 세션이 이미 존재합니다", req.UserID)
+		return nil, s.quotaError(errs.New(errs.ErrAlreadyExists, "사용자 %s의 세션이 이미 존재합니다", req.UserID), req.UserID, req.ProjectID)
+	}
+
+	// 프로젝트 확인
+	if req.ProjectID != "" {
+		if _, err := s.store.GetProject(req.ProjectID); err != nil {
+			return nil, errs.New(errs.ErrNotFound, "프로젝트 %s를 찾을 수 없습니다", req.ProjectID)
+		}
+	}
+
+	// 하트비트가 끊긴 노드에는 배치하지 않음
+	if err := s.checkNodeSchedulable(); err != nil {
+		return nil, err
+	}
+
+	// 워커 슬롯을 얻을 때까지 대기 (provisioning_workers, provisioning_queue_limit)
+	release, err := s.provisioning.acquire(s.config.Current())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	if sessionID == "" {
+		sessionID = uuid.New().String()
+	}
+	step(sessionID, ProgressWorkspace)
+
+	// 세션 종료 후 보관 예약된 워크스페이스는 예약을 취소하고, 이미 보관되었으면 복원
+	if err := s.reviveWorkspace(req.UserID, workspaceDir); err != nil {
+		return nil, err
+	}
+
+	// 복원 지점은 여기서는 확인만 하고, 검증과 GPU 할당이 끝난 뒤 컨테이너를 만들기 직전에 복원
+	// (거부되거나 할당에 실패한 요청이 사용자의 워크스페이스를 바꾸지 않도록)
+	if req.RestorePointID != "" {
+		if workspaceDir == "" {
+			return nil, errWorkspaceNotOnHost
+		}
+		if _, err := s.lookupRestorePoint(req.UserID, req.RestorePointID); err != nil {
+			return nil, err
+		}
+	}
+
+	// 벽시계 만료 시각이나 달력 기반 만료 정책이 지정되면 TTL을 그에 맞춰 계산
+	loc := s.userLocation(req.UserID)
+	var expireAt time.Time
+	if req.ExpireAt != "" && req.ExpiryPolicy != "" {
+		return nil, errs.New(errs.ErrInvalid, "expire_at과 expiry_policy는 함께 지정할 수 없습니다")
+	}
+	if req.ExpireAt != "" || req.ExpiryPolicy != "" {
+		if req.ExpireAt != "" {
+			expireAt, err = resolveExpireAt(req.ExpireAt, time.Now(), loc)
+		} else {
+			expireAt, err = resolveExpiryPolicy(req.ExpiryPolicy, time.Now(), s.config.Current())
+		}
+		if err != nil {
+			return nil, err
+		}
+		req.TTLMinutes = int(time.Until(expireAt).Round(time.Minute) / time.Minute)
+		if req.TTLMinutes <= 0 {
+			req.TTLMinutes = 1
+		}
 	}
 
 	// 기본값 설정
+	cfg := s.config.Current()
+	ttlRequested := req.TTLMinutes > 0
 	if req.TTLMinutes <= 0 {
-		req.TTLMinutes = 60 // 기본 1시간
+		req.TTLMinutes = cfg.DefaultTTLMinutes
+	}
+	if req.TTLMinutes > cfg.MaxTTLMinutes {
+		return nil, s.quotaError(errs.New(errs.ErrInvalid, "TTL %d분이 최대 허용값 %d분을 초과합니다", req.TTLMinutes, cfg.MaxTTLMinutes), req.UserID, req.ProjectID)
+	}
+	// 매니페스트로 재현하는 세션은 기록된 템플릿과 프로파일, 베이스 이미지 다이제스트를 씀
+	var pinnedBase string
+	if req.FromManifest != "" {
+		if pinnedBase, err = s.applyManifest(&req); err != nil {
+			return nil, err
+		}
 	}
 	if req.MIGProfile == "" && req.MIGInstanceUUID == "" {
-		req.MIGProfile = "3g.20gb" // 기본 프로파일
+		req.MIGProfile = s.defaultProfile(cfg)
+	}
+	if err := s.checkSessionType(req); err != nil {
+		return nil, err
+	}
+	if err := checkPlacementHint(req); err != nil {
+		return nil, err
+	}
+	if err := checkStopTimeout(req, cfg); err != nil {
+		return nil, err
+	}
+	if req.CPUCores < 0 || req.MemoryGB < 0 {
+		return nil, errs.New(errs.ErrInvalid, "cpu_cores와 memory_gb는 0 이상이어야 합니다")
+	}
+	if err := s.checkGatewayKeys(&req); err != nil {
+		return nil, err
+	}
+	workspaceQuotaGB, err := s.resolveWorkspaceQuota(req, cfg)
+	if err != nil {
+		return nil, err
+	}
+	cpuOnly := req.MIGProfile == gpu.ProfileCPU
+	if req.Tier, err = validateTier(req.Tier, cfg); err != nil {
+		return nil, err
+	}
+	if req.MIGProfile != "" && !cfg.ProfileAllowed(req.MIGProfile) {
+		return nil, errs.New(errs.ErrInvalid, "프로파일 %s는 이 배포에서 허용되지 않습니다 (허용: %v)", req.MIGProfile, cfg.AllowedProfiles)
+	}
+	var profileDef *store.Profile
+	if req.MIGProfile != "" {
+		if profileDef, err = s.applyProfilePolicy(&req, req.MIGProfile, ttlRequested); err != nil {
+			return nil, err
+		}
+		// 관리자가 건 사용자/팀 한도 (인스턴스를 지정한 요청은 프로파일을 알게 되는 할당 직후에 확인)
+		if err := s.checkUserQuota(req, req.MIGProfile); err != nil {
+			return nil, err
+		}
 	}
 
-	// GPU 할당 - UUID 지정 여부에 따라 다른 방식 사용
+	// 카탈로그에 없는 이미지나 MIG/드라이버와 맞지 않는 이미지는 GPU를 할당하기 전에 거부 (프로파일은 UUID 지정 시 할당 후 확인)
+	image := req.Image
+	if image == "" {
+		image = docker.DefaultImage
+	}
+	catalog, err := s.catalogImage(image)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkImageCompatibility(image, catalog, req.MIGProfile, cfg); err != nil {
+		return nil, err
+	}
+
+	// 세션 네트워크가 외부에서 지워진 뒤 복구하지 못했으면 컨테이너 생성 단계에서 실패하므로 미리 거부
+	if err := s.checkDockerResources(); err != nil {
+		return nil, err
+	}
+
+	// 디스크 한도를 먼저 걸어 준비 단계에서 받는 저장소와 파일도 한도에 포함
+	if err := s.applyWorkspaceQuota(req.UserID, workspaceDir, workspaceQuotaGB, cfg); err != nil {
+		return nil, err
+	}
+
+	// 워크스페이스 준비 (디렉토리, 기본 파일, 템플릿, 저장소, 데이터셋 링크)
+	// 복원 지점으로 시작하면 복원이 워크스페이스를 비우므로 복원한 뒤에 준비
+	if req.RestorePointID == "" {
+		if err := s.provisionWorkspace(req.UserID, workspaceDir, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	// NVIDIA 런타임이 없으면 컨테이너 안에서 CUDA 오류가 나므로 미리 거부 (CPU 전용 세션은 런타임 불필요)
+	if !cpuOnly {
+		if runtime := s.dockerClient.NVIDIARuntimeStatus(); !runtime.Available {
+			return nil, errs.New(errs.ErrNoCapacity, "GPU 세션을 생성할 수 없습니다: %s", runtime.Error)
+		}
+	}
+
+	// GPU 할당 - UUID 지정 여부에 따라 다른 방식 사용 (복제본 간 중복 할당 방지)
+	step(sessionID, ProgressGPU)
+	allocLease, err := s.locker.Acquire("gpu-allocation")
+	if err != nil {
+		return nil, fmt.Errorf("GPU 할당 락 획득 실패: %v", err)
+	}
+	// 다른 복제본이 할당한 인스턴스를 먼저 반영해야 같은 인스턴스를 고르지 않음
+	if err := s.syncGPUClaims(); err != nil {
+		allocLease.Release()
+		return nil, err
+	}
 	var migInstance *gpu.MIGInstance
-	if req.MIGInstanceUUID != "" {
+	if cpuOnly {
+		// CPU 전용 세션은 할당할 GPU 인스턴스가 없음
+		migInstance = gpu.CPUInstance()
+	} else if req.MIGInstanceUUID != "" {
 		// 특정 UUID로 할당
 		migInstance, err = s.gpuManager.AllocateMIGByUUID(req.MIGInstanceUUID, req.UserID)
 		if err != nil {
-			return nil, fmt.Errorf("지정된 GPU 인스턴스 할당 실패: %v", err)
+			allocLease.Release()
+			return nil, fmt.Errorf("지정된 GPU 인스턴스 할당 실패: %w", err)
+		}
+		if !cfg.ProfileAllowed(migInstance.Profile.Name) {
+			s.releaseMIG(sessionID, migInstance.UUID, req.UserID)
+			allocLease.Release()
+			return nil, errs.New(errs.ErrInvalid, "프로파일 %s는 이 배포에서 허용되지 않습니다", migInstance.Profile.Name)
+		}
+		if profileDef, err = s.applyProfilePolicy(&req, migInstance.Profile.Name, ttlRequested); err != nil {
+			s.releaseMIG(sessionID, migInstance.UUID, req.UserID)
+			allocLease.Release()
+			return nil, err
+		}
+		if err := s.checkImageCompatibility(image, catalog, migInstance.Profile.Name, cfg); err != nil {
+			s.releaseMIG(sessionID, migInstance.UUID, req.UserID)
+			allocLease.Release()
+			return nil, err
+		}
+		if err := s.checkUserQuota(req, migInstance.Profile.Name); err != nil {
+			s.releaseMIG(sessionID, migInstance.UUID, req.UserID)
+			allocLease.Release()
+			return nil, err
 		}
 	} else {
-		// 프로파일로 할당 (기존 방식)
-		migInstance, err = s.gpuManager.AllocateMIG(req.MIGProfile, req.UserID)
+		// 프로파일로 할당 (설정의 스케줄러 정책이 인스턴스 선택)
+		migInstance, err = s.scheduleMIG(req, cfg)
 		if err != nil {
-			return nil, fmt.Errorf("GPU 할당 실패: %v", err)
+			allocLease.Release()
+			err = fmt.Errorf("GPU 할당 실패: %w", err)
+			if isShareExceeded(err) {
+				err = s.quotaError(err, req.UserID, req.ProjectID)
+			}
+			return nil, err
 		}
 	}
+	// 락을 보유한 동안 할당을 기록 (갱신에 실패해 락을 잃었으면 다른 복제본이 같은 인스턴스를 줬을 수 있으므로 중단)
+	if err := s.claimGPU(allocLease, migInstance, sessionID, req.UserID); err != nil {
+		s.gpuManager.ReleaseMIG(migInstance.UUID, req.UserID)
+		allocLease.Release()
+		return nil, fmt.Errorf("GPU 할당 기록 실패: %w", err)
+	}
+	allocLease.Release()
+
+	// 요청한 CPU/메모리가 프로파일의 최대를 넘으면 거부
+	resources, err := requestedResources(profileResources(profileDef, migInstance.Profile.Name, cfg), req)
+	if err != nil {
+		s.releaseMIG(sessionID, migInstance.UUID, req.UserID)
+		return nil, err
+	}
 
-	// 워크스페이스 디렉토리 경로
-	workspaceDir := filepath.Join(s.workspaceRoot, req.UserID)
+	// 모든 검증과 할당이 끝났으므로 복원 지점으로 워크스페이스를 되돌리고 준비 단계를 이어서 실행
+	if req.RestorePointID != "" {
+		if err := s.restoreWorkspace(sessionID, req.UserID, req.ProjectID, workspaceDir, req.RestorePointID); err != nil {
+			s.releaseMIG(sessionID, migInstance.UUID, req.UserID)
+			return nil, err
+		}
+		if err := s.provisionWorkspace(req.UserID, workspaceDir, cfg); err != nil {
+			s.releaseMIG(sessionID, migInstance.UUID, req.UserID)
+			return nil, err
+		}
+	}
 
 	// 컨테이너 생성
+	tz, locale := s.containerLocale(req.UserID, image)
+
+	// 기본 워크스페이스 이미지는 이미지 채널(stable/next)의 베이스 이미지로, 카탈로그 이미지는 등록한 베이스 이미지로 빌드
+	// (매니페스트로 고정한 베이스 이미지는 채널과 카탈로그보다 우선)
+	var channel *store.ImageChannel
+	if image == docker.DefaultImage && pinnedBase == "" {
+		channel = s.selectImageChannel(req.UserID, req.ProjectID)
+	}
+	baseImage := pinnedBase
+	if channel != nil {
+		baseImage = channel.BaseImage
+	} else if catalog != nil && baseImage == "" {
+		baseImage = catalog.BaseImage
+	}
+
+	restart := cfg.RestartPolicyFor(image)
+	stopTimeout := cfg.StopPolicyFor(image).TimeoutSeconds
+	if req.StopTimeoutSeconds > 0 {
+		stopTimeout = req.StopTimeoutSeconds
+	}
 	containerConfig := docker.ContainerConfig{
 		UserID:       req.UserID,
 		GPUUUID:      migInstance.UUID,
 		WorkspaceDir: workspaceDir,
 		Image:        req.Image,
+		BaseImage:    baseImage,
+		Timezone:     tz,
+		Locale:       locale,
+		Timeouts:     stepTimeouts(cfg),
+		Resources:    resourceLimits(resources),
+		RestartPolicy: docker.RestartPolicy{
+			Name:       restart.Policy,
+			MaxRetries: restart.MaxRetries,
+		},
+		StopTimeout: time.Duration(stopTimeout) * time.Second,
+		Progress: func(step, line string) {
+			if line == "" {
+				s.progress.step(sessionID, step, "")
+			} else {
+				s.progress.log(sessionID, step, line)
+			}
+		},
 	}
 
+	// 이미지 빌드와 컨테이너 생성은 디스크 I/O가 커서 노드 전체의 동시 실행 수를 제한 (node_provisioning_limits)
+	phases.Start("container", attribute.String("sandman.image", image), attribute.String("sandman.gpu_uuid", migInstance.UUID))
+	containerConfig.Context = phases.Context()
+	releaseSlot, err := s.acquireNodeSlot(cfg)
+	if err != nil {
+		s.releaseMIG(sessionID, migInstance.UUID, req.UserID)
+		return nil, err
+	}
 	containerInfo, err := s.dockerClient.CreateContainer(containerConfig)
+	releaseSlot()
 	if err != nil {
 		// GPU 할당 롤백
-		s.gpuManager.ReleaseMIG(migInstance.UUID, req.UserID)
-		return nil, fmt.Errorf("컨테이너 생성 실패: %v", err)
+		s.releaseMIG(sessionID, migInstance.UUID, req.UserID)
+		if channel != nil {
+			s.recordImageChannelOutcome(channel.Name, true)
+		}
+		return nil, fmt.Errorf("컨테이너 생성 실패: %w", err)
 	}
 
+	// 요청한 MIG/GPU 장치가 실제로 컨테이너에 붙었는지 확인 (사용자가 CUDA 오류로 알게 되기 전에 실패 처리)
+	step(sessionID, ProgressVerify)
+	err = s.verifyGPUAttachment(containerInfo.ID, migInstance.UUID, cfg)
+	if channel != nil {
+		s.recordImageChannelOutcome(channel.Name, err != nil)
+	}
+	if err != nil {
+		s.dockerClient.RemoveContainer(containerInfo.ID)
+		s.releaseMIG(sessionID, migInstance.UUID, req.UserID)
+		return nil, err
+	}
+	s.checkContainerClock(sessionID, containerInfo.ID, cfg)
+
 	// 세션 정보 저장
+	step(sessionID, ProgressFinalize)
 	now := time.Now()
 	expiresAt := now.Add(time.Duration(req.TTLMinutes) * time.Minute)
+	if !expireAt.IsZero() {
+		expiresAt = expireAt
+	}
 
 	session := &store.Session{
-		ID:          uuid.New().String(),
+		ID:          sessionID,
 		UserID:      req.UserID,
+		ProjectID:   req.ProjectID,
+		NodeID:      s.nodeID,
 		ContainerID: containerInfo.ID,
 		ContainerIP: containerInfo.IP,
 		SSHPort:     containerInfo.SSHPort,
 		GPUUUID:     migInstance.UUID,
+		GPUName:     migInstance.Name,
 		MIGProfile:  migInstance.Profile.Name, // 실제 할당된 프로파일 사용
+		Priority:    req.Priority,
+		Tier:        req.Tier,
 		TTLMinutes:  req.TTLMinutes,
 		CreatedAt:   now,
 		ExpiresAt:   expiresAt,
 		Metadata: map[string]string{
-			"image":        containerInfo.Image,
-			"workspace":    workspaceDir,
-			"ssh_password": containerConfig.SSHPassword,
-			"ssh_port":     fmt.Sprintf("%d", containerInfo.SSHPort),
+			"image":          containerInfo.Image,
+			"template":       image,
+			"workspace":      s.workspaceRef(req.UserID, workspaceDir),
+			"ssh_password":   containerConfig.SSHPassword,
+			"ssh_public_key": containerInfo.SSHPublicKey,
+			"ssh_port":       fmt.Sprintf("%d", containerInfo.SSHPort),
 		},
 	}
+	if channel != nil {
+		session.Metadata["image_channel"] = channel.Name
+		session.Metadata["base_image"] = channel.BaseImage
+	} else if catalog != nil {
+		session.Metadata["base_image"] = baseImage
+	}
+	if workspaceQuotaGB > 0 {
+		session.Metadata[metaWorkspaceQuota] = fmt.Sprintf("%d", workspaceQuotaGB)
+	}
+	if req.StopTimeoutSeconds > 0 {
+		session.Metadata[metaStopTimeout] = fmt.Sprintf("%d", req.StopTimeoutSeconds)
+	}
+	if len(req.SSHPublicKeys) > 0 {
+		// 공개키 라우트 세션은 비밀번호를 저장하거나 내려주지 않음
+		session.Metadata[metaGatewayKeys] = strings.Join(req.SSHPublicKeys, "\n")
+		delete(session.Metadata, "ssh_password")
+	}
+	if resources.CPULimit > 0 {
+		session.Metadata[metaCPULimit] = fmt.Sprintf("%g", resources.CPULimit)
+	}
+	if resources.MemoryLimitMB > 0 {
+		session.Metadata[metaMemoryLimitMB] = fmt.Sprintf("%d", resources.MemoryLimitMB)
+	}
+	s.recordTopology(session, migInstance, req.Placement)
+
+	// CA 모드면 컨테이너가 이 세션 principal의 인증서를 신뢰하도록 설정
+	if err := s.installSSHCA(session); err != nil {
+		s.dockerClient.RemoveContainer(containerInfo.ID)
+		s.releaseMIG(sessionID, migInstance.UUID, req.UserID)
+		return nil, err
+	}
 
-	if err := s.store.CreateSession(session); err != nil {
+	_, dbSpan := tracing.Start(phases.Context(), "store.CreateSession", attribute.String("sandman.session_id", session.ID))
+	err = s.store.CreateSession(session)
+	tracing.End(dbSpan, err)
+	if err != nil {
 		// 리소스 정리
 		s.dockerClient.RemoveContainer(containerInfo.ID)
-		s.gpuManager.ReleaseMIG(migInstance.UUID, req.UserID)
-		return nil, fmt.Errorf("세션 저장 실패: %v", err)
+		s.releaseMIG(sessionID, migInstance.UUID, req.UserID)
+		return nil, fmt.Errorf("세션 저장 실패: %w", err)
+	}
+
+	s.recordImageBuild(session, containerInfo.Build)
+	s.recordSessionWait(session, req.submittedAt)
+	s.recordEvent(session, EventCreate, "user:"+req.UserID, "", fmt.Sprintf("profile=%s gpu=%s node=%s ttl_minutes=%d", session.MIGProfile, session.GPUUUID, session.NodeID, session.TTLMinutes))
+	s.captureBaseline(session)
+	s.recordManifest(session, baseImage, workspaceDir, cfg)
+	if err := s.renderWelcome(session); err != nil {
+		log.Printf("⚠️ 세션 %s 환영 메시지 기록 실패: %v", session.ID, err)
+	}
+	if err := s.provisionWorkspaceSSH(session); err != nil {
+		log.Printf("⚠️ 세션 %s 워크스페이스 .ssh 구성 실패: %v", session.ID, err)
 	}
 
 	log.Printf("✅ 세션 생성 완료: %s (사용자: %s, GPU: %s, SSH 포트: %d)", session.ID, req.UserID, migInstance.UUID, containerInfo.SSHPort)
+	s.liveness.put(s.probeLiveness(session, nil))
+	if err := s.installGatewayKey(session, containerInfo.SSHPrivateKey); err != nil {
+		log.Printf("⚠️ 세션 %s 게이트웨이 키 저장 실패: %v", session.ID, err)
+	}
+	phases.Start("route")
+	s.publishRouteContext(phases.Context(), session)
 
 	// SSH 개인키를 응답에 포함하되, 보안을 위해 메모리에서 즉시 클리어
 	sshPrivateKey := containerInfo.SSHPrivateKey
 
+	var principal string
+	if s.sshCA != nil {
+		principal = sshca.SessionPrincipal(session.ID)
+	}
+
+	var linkExpiresAt *time.Time
+	linkToken, expires, err := s.issueCredentialLink(session, sshPrivateKey)
+	if err != nil {
+		log.Printf("⚠️ 세션 %s 자격 증명 링크 발급 실패: %v", session.ID, err)
+	} else {
+		linkExpiresAt = &expires
+	}
+
 	return &CreateResponse{
-		SessionID:     session.ID,
-		ContainerID:   containerInfo.ID,
-		SSHUser:       req.UserID,
-		SSHHost:       "localhost", // 실제 환경에서는 설정 가능하게
-		SSHPort:       containerInfo.SSHPort,
-		SSHPrivateKey: sshPrivateKey,
-		GPUUUID:       migInstance.UUID,
-		CreatedAt:     now,
-		ExpiresAt:     expiresAt,
+		SessionID:      session.ID,
+		ProjectID:      session.ProjectID,
+		ContainerID:    containerInfo.ID,
+		SSHUser:        req.UserID,
+		SSHHost:        "localhost", // 실제 환경에서는 설정 가능하게
+		SSHPort:        containerInfo.SSHPort,
+		SSHPrivateKey:  sshPrivateKey,
+		GPUUUID:        migInstance.UUID,
+		GPUName:        migInstance.Name,
+		CreatedAt:      now,
+		ExpiresAt:      expiresAt,
+		ExpiresAtLocal: expiresAt.In(loc).Format(time.RFC3339),
+		Timezone:       loc.String(),
+		SSHPrincipal:   principal,
+		SSHPassword:    session.Metadata["ssh_password"],
+
+		WorkspaceQuotaGB: workspaceQuotaGB,
+
+		CredentialsToken:     linkToken,
+		CredentialsExpiresAt: linkExpiresAt,
+
+		Deployment: s.deployment(),
 	}, nil
 }
 
@@ -159,28 +656,71 @@ func (s *Service) GetSessionByUserID(userID string) (*store.Session, error) {
 }
 
 func (s *Service) DeleteSession(sessionID string) error {
+	return s.deleteSession(sessionID, "api")
+}
+
+func (s *Service) deleteSession(sessionID, actor string) error {
+	done, err := s.beginOperation()
+	if err != nil {
+		return err
+	}
+	defer done()
+
 	session, err := s.store.GetSession(sessionID)
 	if err != nil {
 		return err
 	}
 
-	return s.cleanupSession(session)
+	return s.cleanupSessionAs(session, EndReasonDeleted, actor)
 }
 
 func (s *Service) DeleteSessionByUserID(userID string) error {
+	done, err := s.beginOperation()
+	if err != nil {
+		return err
+	}
+	defer done()
+
 	session, err := s.store.GetSessionByUserID(userID)
 	if err != nil {
 		return err
 	}
 
-	return s.cleanupSession(session)
+	return s.cleanupSessionAs(session, EndReasonDeleted, "api")
 }
 
-func (s *Service) cleanupSession(session *store.Session) error {
+func (s *Service) cleanupSession(session *store.Session, reason string) error {
+	return s.cleanupSessionAs(session, reason, "system")
+}
+
+// cleanupSessionAs 세션을 정리하고 actor가 끝낸 것으로 세션 이벤트를 남김
+func (s *Service) cleanupSessionAs(session *store.Session, reason, actor string) error {
+	// 격리된 세션은 조사가 끝날 때까지 컨테이너와 GPU를 그대로 둠
+	if err := checkNotQuarantined(session); err != nil {
+		return err
+	}
 	log.Printf("🧹 세션 정리 시작: %s (사용자: %s)", session.ID, session.UserID)
 
+	// 컨테이너를 내리기 전에 자격 증명부터 무효화하고 최종 사용량 기록 (워크스페이스 처리 결정 포함)
+	endedAt := time.Now()
+	plan := s.planWorkspace(session, endedAt)
+	// 협업자 라우트를 찾을 수 있도록 자격 증명(협업자 정보)을 지우기 전에 라우트를 내림
+	s.withdrawRoute(session)
+	s.revokeCredentials(session, reason)
+	s.recordFinalUsage(session, reason, plan)
+	s.liveness.remove(session.ID)
+	s.removeGatewayKey(session)
+
+	// 잠긴 세션은 먼저 재개해야 정상 종료 신호를 받음
+	s.closeWaker(session.ID)
+	if session.State == store.SessionLocked {
+		if err := s.dockerClient.UnpauseContainer(session.ContainerID); err != nil {
+			log.Printf("⚠️ 잠긴 컨테이너 재개 실패: %v", err)
+		}
+	}
+
 	// 컨테이너 중지 및 제거
-	if err := s.dockerClient.StopContainer(session.ContainerID); err != nil {
+	if err := s.stopSessionContainer(session); err != nil {
 		log.Printf("⚠️ 컨테이너 중지 실패: %v", err)
 	}
 
@@ -188,9 +728,11 @@ func (s *Service) cleanupSession(session *store.Session) error {
 		log.Printf("⚠️ 컨테이너 제거 실패: %v", err)
 	}
 
-	// GPU 인스턴스 해제
-	if err := s.gpuManager.ReleaseMIG(session.GPUUUID, session.UserID); err != nil {
+	// GPU 인스턴스 해제 (유예 시간 동안 같은 사용자에게 소프트 예약)
+	if err := s.releaseMIG(session.ID, session.GPUUUID, session.UserID); err != nil {
 		log.Printf("⚠️ GPU 인스턴스 해제 실패: %v", err)
+	} else if !s.releaseOnDemandMIG(session) {
+		s.reserveMIG(session, reason, endedAt)
 	}
 
 	// 데이터베이스에서 세션 삭제
@@ -198,23 +740,60 @@ func (s *Service) cleanupSession(session *store.Session) error {
 		log.Printf("⚠️ 세션 데이터 삭제 실패: %v", err)
 		return err
 	}
+	s.recordEvent(session, endEventType(reason), actor, reason, "")
+	if err := s.store.DeleteSessionBaseline(session.ID); err != nil {
+		log.Printf("⚠️ 세션 기준선 삭제 실패: %v", err)
+	}
+	s.removeWorkspaceSSH(session)
+	s.applyWorkspacePlan(session, plan, endedAt)
+	s.runCleanupHooks(session, reason, plan, endedAt)
 
 	log.Printf("✅ 세션 정리 완료: %s", session.ID)
 	return nil
 }
 
 func (s *Service) CleanupExpiredSessions() error {
+	done, err := s.beginOperation()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	// 키 교체 유예가 끝난 이전 키는 컨테이너가 있는 노드마다 폐기 (ttl-reaper 락과 무관)
+	s.revokeRotatedKeys()
+	return s.cleanupExpired()
+}
+
+func (s *Service) cleanupExpired() error {
+	// 여러 복제본 중 하나만 만료 세션을 정리
+	lease, err := s.locker.TryAcquire("ttl-reaper")
+	if err != nil {
+		return err
+	}
+	if lease == nil {
+		logging.Debugf("다른 복제본이 만료 세션 정리 중 - 건너뜀")
+		return nil
+	}
+	defer lease.Release()
+
 	expiredSessions, err := s.store.ListExpiredSessions()
 	if err != nil {
 		return err
 	}
+	logging.Routine("ttl-cleanup", "⏰ 만료 세션 확인: %d개", len(expiredSessions))
 
 	for _, session := range expiredSessions {
+		if session.State == store.SessionQuarantined {
+			logging.Debugf("격리된 세션 %s는 만료되어도 정리하지 않음", session.ID)
+			continue
+		}
 		log.Printf("⏰ 만료된 세션 정리: %s (사용자: %s)", session.ID, session.UserID)
-		if err := s.cleanupSession(session); err != nil {
+		if err := s.cleanupSession(session, endReasonForExpired(session)); err != nil {
 			log.Printf("⚠️ 만료된 세션 정리 실패: %v", err)
 		}
 	}
+	s.purgeMIGReservations()
+	s.purgeSessionWaits()
 
 	return nil
 }
@@ -224,16 +803,42 @@ func (s *Service) ListAllSessions() ([]*store.Session, error) {
 }
 
 func (s *Service) DeleteAllSessions() error {
+	done, err := s.beginOperation()
+	if err != nil {
+		return err
+	}
+	defer done()
+
 	sessions, err := s.store.ListAllSessions()
 	if err != nil {
 		return err
 	}
 
 	for _, session := range sessions {
-		if err := s.cleanupSession(session); err != nil {
+		if err := s.cleanupSession(session, EndReasonDeleted); err != nil {
 			log.Printf("⚠️ 세션 삭제 실패: %v", err)
 		}
 	}
 
 	return nil
 }
+
+// guardConfigChange 실행 중인 세션이 사용하는 프로파일을 허용 목록에서 빼는 설정 변경을 거부
+func (s *Service) guardConfigChange(old, new *config.Config) error {
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		return fmt.Errorf("세션 목록 조회 실패: %v", err)
+	}
+
+	for _, session := range sessions {
+		if !new.ProfileAllowed(session.MIGProfile) {
+			return errs.New(errs.ErrConflict, "실행 중인 세션 %s(사용자: %s)가 프로파일 %s를 사용 중입니다", session.ID, session.UserID, session.MIGProfile)
+		}
+	}
+	return nil
+}
+
+// ListLocks 현재 임대 중인 분산 락 목록
+func (s *Service) ListLocks() ([]*store.LockLease, error) {
+	return s.store.ListLocks()
+}
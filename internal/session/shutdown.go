@@ -0,0 +1,72 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// ErrShuttingDown 종료 절차가 시작된 뒤 들어온 세션 생성/삭제 요청
+var ErrShuttingDown = errors.New("오케스트레이터가 종료 중입니다")
+
+// beginOperation Docker/GPU 상태를 바꾸는 작업 시작을 등록 (종료 중이면 거부)
+func (s *Service) beginOperation() (func(), error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	if s.closing {
+		return nil, ErrShuttingDown
+	}
+	s.inflight.Add(1)
+	return s.inflight.Done, nil
+}
+
+// Shutdown 새 작업을 거부하고, 진행 중인 생성/정리 작업이 끝날 때까지 ctx 기한 내에서 대기한 뒤
+// 아직 정리되지 않은 만료 세션을 마지막으로 정리하고 남은 정리 훅을 기다림
+func (s *Service) Shutdown(ctx context.Context) error {
+	s.opMu.Lock()
+	s.closing = true
+	s.opMu.Unlock()
+
+	log.Println("⏳ 진행 중인 세션 작업 완료 대기 중...")
+	done := make(chan struct{})
+	go func() {
+		s.inflight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		log.Println("✅ 진행 중인 세션 작업 완료")
+	case <-ctx.Done():
+		return fmt.Errorf("진행 중인 세션 작업 대기 시간 초과: %v", ctx.Err())
+	}
+
+	// 만료됐지만 아직 정리되지 않은 세션 정리 (종료 중에도 정리 작업 자체는 허용)
+	flushed := make(chan error, 1)
+	go func() { flushed <- s.cleanupExpired() }()
+
+	select {
+	case err := <-flushed:
+		if err != nil {
+			return err
+		}
+	case <-ctx.Done():
+		return fmt.Errorf("만료 세션 정리 대기 시간 초과: %v", ctx.Err())
+	}
+
+	// 정리 훅은 세션 정리와 별도로 실행되므로 따로 기다림
+	hooksDone := make(chan struct{})
+	go func() {
+		s.hooks.Wait()
+		close(hooksDone)
+	}()
+
+	select {
+	case <-hooksDone:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("정리 훅 대기 시간 초과: %v", ctx.Err())
+	}
+}
@@ -0,0 +1,163 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+var spotReclaims = metrics.NewCounter("sandman_spot_reclaims_total", "standard 요청을 위한 spot 세션 회수 수", "mode")
+
+// validateTier 요청 등급 확인 (비어 있으면 standard)
+func validateTier(tier string, cfg *config.Config) (string, error) {
+	switch tier {
+	case "", store.TierStandard:
+		return store.TierStandard, nil
+	case store.TierSpot:
+		if !cfg.Spot.Enabled {
+			return "", errs.New(errs.ErrInvalid, "이 배포에서는 spot 세션을 사용할 수 없습니다")
+		}
+		return store.TierSpot, nil
+	}
+	return "", errs.New(errs.ErrInvalid, "알 수 없는 세션 등급입니다: %q (standard, spot)", tier)
+}
+
+// rateFactor 세션 등급의 정산 요율 배율 (사용량 기록을 처음 만들 때 고정되어 이후 설정 변경의 영향을 받지 않음)
+func rateFactor(tier string, cfg *config.Config) float64 {
+	if tier == store.TierSpot {
+		return cfg.Spot.RateFactor
+	}
+	return 1
+}
+
+// spotSessions 이 노드에서 프로파일을 쓰는 spot 세션
+func (s *Service) spotSessions(profile string) ([]*store.Session, error) {
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		return nil, fmt.Errorf("세션 목록 조회 실패: %v", err)
+	}
+
+	var spot []*store.Session
+	for _, session := range sessions {
+		if session.NodeID != "" && session.NodeID != s.nodeID {
+			continue
+		}
+		if session.Tier == store.TierSpot && session.MIGProfile == profile {
+			spot = append(spot, session)
+		}
+	}
+	return spot, nil
+}
+
+// pendingReclaim 회수 예고 중인 spot 세션 (없으면 nil)
+func pendingReclaim(sessions []*store.Session) *store.Session {
+	for _, session := range sessions {
+		if session.EvictAt != nil {
+			return session
+		}
+	}
+	return nil
+}
+
+// checkSpotPlacement spot 요청은 회수 예고 중인 인스턴스를 기다리는 standard 요청이 있으면 받지 않음
+func (s *Service) checkSpotPlacement(profile string) error {
+	sessions, err := s.spotSessions(profile)
+	if err != nil {
+		return err
+	}
+	if pending := pendingReclaim(sessions); pending != nil {
+		return errs.New(errs.ErrNoCapacity, "프로파일 %s는 standard 요청을 위해 spot 세션을 회수하는 중입니다", profile)
+	}
+	return nil
+}
+
+// reclaimSpot standard 요청에 줄 인스턴스가 없을 때 가장 늦게 시작한 spot 세션을 회수
+// 예고 시간이 없으면 바로 정리하고 비운 인스턴스를 할당, 있으면 만료 시각을 예고 시각으로 앞당기고 다시 시도하라는 오류를 반환
+// 같은 프로파일에 회수 예고 중인 세션이 이미 있으면 더 회수하지 않음 (재시도마다 spot 세션이 줄줄이 회수되지 않도록)
+// 회수할 spot 세션이 없으면 nil, nil (gpu-allocation 락을 잡은 상태에서 호출)
+func (s *Service) reclaimSpot(req CreateRequest, cfg *config.Config) (*gpu.MIGInstance, error) {
+	// 공정 몫 초과처럼 빈 인스턴스가 있는데 거부된 경우는 회수 대상이 아님
+	for _, instance := range s.gpuManager.GetAvailableMIGInstances() {
+		if instance.Profile.Name == req.MIGProfile {
+			return nil, nil
+		}
+	}
+
+	sessions, err := s.spotSessions(req.MIGProfile)
+	if err != nil {
+		return nil, err
+	}
+	if pending := pendingReclaim(sessions); pending != nil {
+		return nil, errs.New(errs.ErrNoCapacity, "프로파일 %s의 spot 세션을 회수하는 중입니다. %s 이후 다시 시도하세요",
+			req.MIGProfile, pending.EvictAt.Format(time.RFC3339))
+	}
+
+	var victim *store.Session
+	for _, session := range sessions {
+		if victim == nil || session.CreatedAt.After(victim.CreatedAt) {
+			victim = session
+		}
+	}
+	if victim == nil {
+		return nil, nil
+	}
+
+	if cfg.Spot.NoticeMinutes == 0 {
+		log.Printf("⏏️ spot 세션 즉시 회수: %s (사용자: %s) → 사용자 %s", victim.ID, victim.UserID, req.UserID)
+		if err := s.cleanupSession(victim, EndReasonPreempted); err != nil {
+			return nil, fmt.Errorf("spot 세션 %s 정리 실패: %w", victim.ID, err)
+		}
+		spotReclaims.Inc("immediate")
+		s.audit("scheduler", "session.spot_reclaimed", victim.ID, fmt.Sprintf("user=%s reclaimed_by=%s", victim.UserID, req.UserID))
+		return s.gpuManager.AllocateMIGByUUID(victim.GPUUUID, req.UserID)
+	}
+
+	evictAt := time.Now().Add(time.Duration(cfg.Spot.NoticeMinutes) * time.Minute)
+	victim.EvictAt = &evictAt
+	if victim.ExpiresAt.After(evictAt) {
+		victim.ExpiresAt = evictAt
+	}
+	if err := s.store.UpdateSession(victim); err != nil {
+		return nil, fmt.Errorf("spot 세션 %s 회수 예고 실패: %w", victim.ID, err)
+	}
+	spotReclaims.Inc("scheduled")
+	log.Printf("⏏️ spot 세션 회수 예고: %s (사용자: %s) → %s에 종료", victim.ID, victim.UserID, evictAt.Format(time.RFC3339))
+	s.audit("scheduler", "session.spot_reclaim_scheduled", victim.ID,
+		fmt.Sprintf("user=%s evict_at=%s requested_by=%s", victim.UserID, evictAt.Format(time.RFC3339), req.UserID))
+	s.notifyEviction(victim)
+
+	return nil, errs.New(errs.ErrNoCapacity, "프로파일 %s의 spot 세션 회수를 예약했습니다. %s 이후 다시 시도하세요",
+		req.MIGProfile, evictAt.Format(time.RFC3339))
+}
+
+// notifyEviction 환영 메시지의 만료 시각을 갱신하고 로그인 중인 터미널에 회수 예고를 알림 (실패해도 회수는 진행)
+func (s *Service) notifyEviction(session *store.Session) {
+	if err := s.renderWelcome(session); err != nil {
+		log.Printf("⚠️ 세션 %s 환영 메시지 갱신 실패: %v", session.ID, err)
+	}
+
+	loc := s.userLocation(session.UserID)
+	message := fmt.Sprintf("⏏️ 이 spot 세션은 다른 요청을 위해 %s에 회수됩니다. /workspace 밖의 작업은 그 전에 저장하세요.",
+		session.EvictAt.In(loc).Format("2006-01-02 15:04"))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := s.dockerClient.ExecOK(ctx, session.ContainerID, []string{"wall", message}); err != nil {
+		logging.Debugf("세션 %s 회수 예고 알림 실패: %v", session.ID, err)
+	}
+}
+
+// endReasonForExpired 회수 예고 시각에 만료된 spot 세션은 선점으로 기록 (원래 만료가 더 빨랐으면 만료)
+func endReasonForExpired(session *store.Session) string {
+	if session.EvictAt != nil && !session.ExpiresAt.Before(*session.EvictAt) {
+		return EndReasonPreempted
+	}
+	return EndReasonExpired
+}
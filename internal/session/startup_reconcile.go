@@ -0,0 +1,132 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+var startupReconciled = metrics.NewCounter("sandman_startup_reconciliations_total", "시작 시 복구한 할당 상태 (resource: port, gpu, ip, orphan, orphan_failed)", "resource")
+
+// StartupReconciliation 시작 시 살아 있는 컨테이너와 세션 기록으로 다시 만든 할당 상태
+type StartupReconciliation struct {
+	Containers     int      `json:"containers"`
+	Sessions       int      `json:"sessions"`
+	PortsLeased    int      `json:"ports_leased"`
+	GPUsAllocated  int      `json:"gpus_allocated"`
+	IPsUpdated     int      `json:"ips_updated"`
+	OrphansRemoved []string `json:"orphans_removed"`
+	OrphansKept    []string `json:"orphans_kept"` // 유예 시간 안이거나 제거에 실패한 세션 없는 컨테이너
+}
+
+// ReconcileStartup 재시작으로 비어 있는 포트/GPU 할당 기록을 세션 네트워크의 컨테이너와 세션 기록으로 다시 채우고
+// 세션 기록 없는 컨테이너 중 orphanGrace보다 오래된 것을 제거 (복제본이 만들고 있는 컨테이너는 아직 기록이 없음)
+// IP는 컨테이너에서 바로 계산하므로 세션 기록만 실제 IP로 맞춤. 세션은 있는데 컨테이너가 없는 경우는 일관성 검사가 알림
+func (s *Service) ReconcileStartup(orphanGrace time.Duration) (*StartupReconciliation, error) {
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		return nil, fmt.Errorf("세션 목록 조회 실패: %v", err)
+	}
+	containers, err := s.dockerClient.ListManagedContainers()
+	if err != nil {
+		return nil, err
+	}
+	result := &StartupReconciliation{
+		Containers:     len(containers),
+		OrphansRemoved: []string{},
+		OrphansKept:    []string{},
+	}
+
+	// 다른 노드의 세션 컨테이너가 보일 일은 없지만, 기록이 있는 컨테이너는 어느 노드 것이든 지우지 않음
+	owners := make(map[string]*store.Session, len(sessions))
+	for _, session := range sessions {
+		if session.ContainerID != "" {
+			owners[session.ContainerID] = session
+		}
+	}
+
+	// 포트: 세션 없는 컨테이너의 포트도 제거할 때까지는 바인딩되어 있으므로 모두 잡아 둠
+	for _, c := range containers {
+		if c.SSHPort > 0 {
+			s.dockerClient.LeasePort(c.SSHPort)
+			result.PortsLeased++
+		}
+	}
+
+	// GPU: 이 노드 세션이 쓰는 인스턴스를 세션 사용자에게 다시 할당 표시하고 할당 기록을 맞춤
+	allocLease, err := s.locker.Acquire("gpu-allocation")
+	if err != nil {
+		return nil, fmt.Errorf("GPU 할당 락 획득 실패: %v", err)
+	}
+	if err := s.syncGPUClaims(); err != nil {
+		log.Printf("⚠️ 시작 복구: %v", err)
+	}
+	for _, session := range sessions {
+		if session.NodeID != "" && session.NodeID != s.nodeID {
+			continue
+		}
+		result.Sessions++
+		if session.GPUUUID == "" {
+			continue
+		}
+		instance, ok := s.gpuManager.Instance(session.GPUUUID)
+		if !ok {
+			continue
+		}
+		if !instance.InUse {
+			if _, err := s.gpuManager.AllocateMIGByUUID(session.GPUUUID, session.UserID); err != nil {
+				log.Printf("⚠️ 시작 복구: 세션 %s의 GPU 인스턴스 %s 할당 표시 실패: %v", session.ID, session.GPUUUID, err)
+				continue
+			}
+			result.GPUsAllocated++
+		}
+		if err := s.claimGPU(allocLease, &instance, session.ID, session.UserID); err != nil {
+			log.Printf("⚠️ 시작 복구: 세션 %s의 GPU 인스턴스 %s 할당 기록 실패: %v", session.ID, session.GPUUUID, err)
+		}
+	}
+	s.pruneGPUClaims(sessions)
+	allocLease.Release()
+
+	now := time.Now()
+	for _, c := range containers {
+		session, owned := owners[c.ID]
+		if owned {
+			// IP: 재시작 전에 바뀐 컨테이너 IP를 세션 기록과 라우트에 반영
+			if c.IP != "" && session.ContainerIP != "" && c.IP != session.ContainerIP {
+				if err := s.repairIssue(ConsistencyIssue{Kind: IssueIPMismatch, Resource: c.IP, SessionID: session.ID}); err != nil {
+					log.Printf("⚠️ 시작 복구: 세션 %s의 IP 갱신 실패: %v", session.ID, err)
+				} else {
+					result.IPsUpdated++
+				}
+			}
+			continue
+		}
+
+		name := fmt.Sprintf("%s (%s)", c.Name, shortContainerID(c.ID))
+		if !c.CreatedAt.IsZero() && now.Sub(c.CreatedAt) < orphanGrace {
+			log.Printf("⏳ 시작 복구: 세션 기록 없는 컨테이너 %s는 만든 지 %v라 남겨 둡니다", name, now.Sub(c.CreatedAt).Round(time.Second))
+			result.OrphansKept = append(result.OrphansKept, name)
+			continue
+		}
+		if err := s.dockerClient.RemoveContainer(c.ID); err != nil {
+			log.Printf("⚠️ 시작 복구: 세션 기록 없는 컨테이너 %s 제거 실패: %v", name, err)
+			startupReconciled.Inc("orphan_failed")
+			result.OrphansKept = append(result.OrphansKept, name)
+			continue
+		}
+		result.OrphansRemoved = append(result.OrphansRemoved, name)
+		s.audit("system", "startup.orphan_removed", shortContainerID(c.ID), fmt.Sprintf("세션 기록 없는 컨테이너 %s (%s)", c.Name, c.State))
+	}
+
+	startupReconciled.Add(float64(result.PortsLeased), "port")
+	startupReconciled.Add(float64(result.GPUsAllocated), "gpu")
+	startupReconciled.Add(float64(result.IPsUpdated), "ip")
+	startupReconciled.Add(float64(len(result.OrphansRemoved)), "orphan")
+	log.Printf("🔁 시작 복구: 컨테이너 %d개, 세션 %d개 → 포트 %d개, GPU %d개, IP %d개 복구, 고아 컨테이너 %d개 제거 (%d개 남김)",
+		result.Containers, result.Sessions, result.PortsLeased, result.GPUsAllocated, result.IPsUpdated,
+		len(result.OrphansRemoved), len(result.OrphansKept))
+	return result, nil
+}
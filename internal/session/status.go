@@ -0,0 +1,287 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// 공개 상태 페이지의 전체 상태
+const (
+	StatusOperational = "operational"
+	StatusDegraded    = "degraded"
+	StatusOutage      = "outage"
+)
+
+// 프로파일별 가용성 (정확한 인스턴스 수는 공개하지 않음)
+const (
+	AvailabilityAvailable = "available"
+	AvailabilityLimited   = "limited" // 남은 인스턴스가 전체의 limitedShare 이하
+	AvailabilityNone      = "none"
+)
+
+const (
+	// StatusCacheTTL 공개 상태를 다시 계산하는 간격 (상태 페이지가 자주 폴링해도 DB/Docker에 부담이 없도록)
+	StatusCacheTTL = 30 * time.Second
+	limitedShare   = 0.2
+)
+
+// PublicStatus 인증 없이 공개하는 서비스 상태 (GET /status)
+type PublicStatus struct {
+	Status    string                `json:"status"`
+	UpdatedAt time.Time             `json:"updated_at"`
+	Profiles  []ProfileAvailability `json:"profiles"`
+	Incidents []PublicIncident      `json:"incidents"`
+}
+
+// ProfileAvailability 프로파일별 대략적인 가용성
+type ProfileAvailability struct {
+	Profile      string `json:"profile"`
+	Availability string `json:"availability"`
+}
+
+// PublicIncident 해결되지 않은 공지 (내부 식별 정보 없이)
+type PublicIncident struct {
+	Title     string    `json:"title"`
+	Message   string    `json:"message,omitempty"`
+	Severity  string    `json:"severity"`
+	StartedAt time.Time `json:"started_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IncidentRequest 공지 생성/수정 요청 (수정 시 비어 있는 필드는 유지)
+type IncidentRequest struct {
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+}
+
+type statusCache struct {
+	mu      sync.Mutex
+	status  *PublicStatus
+	expires time.Time
+}
+
+func (c *statusCache) invalidate() {
+	c.mu.Lock()
+	c.status = nil
+	c.mu.Unlock()
+}
+
+// PublicStatus StatusCacheTTL 동안 캐시한 공개 상태
+func (s *Service) PublicStatus() (*PublicStatus, error) {
+	s.status.mu.Lock()
+	defer s.status.mu.Unlock()
+
+	if s.status.status != nil && time.Now().Before(s.status.expires) {
+		return s.status.status, nil
+	}
+	status, err := s.buildPublicStatus()
+	if err != nil {
+		return nil, err
+	}
+	s.status.status = status
+	s.status.expires = time.Now().Add(StatusCacheTTL)
+	return status, nil
+}
+
+// buildPublicStatus Docker 응답, 노드 상태, 공지 심각도 중 가장 나쁜 것을 전체 상태로 사용
+func (s *Service) buildPublicStatus() (*PublicStatus, error) {
+	status := &PublicStatus{
+		Status:    StatusOperational,
+		UpdatedAt: time.Now(),
+		Profiles:  []ProfileAvailability{},
+		Incidents: []PublicIncident{},
+	}
+	worsen := func(level string) {
+		if statusRank(level) > statusRank(status.Status) {
+			status.Status = level
+		}
+	}
+
+	if err := s.dockerClient.Ping(); err != nil {
+		worsen(StatusOutage)
+	}
+
+	nodes, err := s.store.ListNodes()
+	if err != nil {
+		return nil, err
+	}
+	ready, unreachable := 0, 0
+	for _, node := range nodes {
+		switch node.Status {
+		case store.NodeReady:
+			ready++
+		case store.NodeUnreachable:
+			unreachable++
+		}
+	}
+	if len(nodes) > 0 && ready == 0 {
+		worsen(StatusOutage)
+	} else if unreachable > 0 {
+		worsen(StatusDegraded)
+	}
+
+	snapshot, err := s.capacitySnapshot()
+	if err != nil {
+		return nil, err
+	}
+	total := make(map[string]int)
+	for _, profiles := range snapshot.Capacity {
+		for name, count := range profiles {
+			total[name] += count
+		}
+	}
+	cfg := s.config.Current()
+	for name, capacity := range total {
+		if !cfg.ProfileAllowed(name) {
+			continue
+		}
+		status.Profiles = append(status.Profiles, ProfileAvailability{
+			Profile:      name,
+			Availability: availability(capacity-snapshot.Demand[name], capacity),
+		})
+	}
+	sort.Slice(status.Profiles, func(i, j int) bool { return status.Profiles[i].Profile < status.Profiles[j].Profile })
+
+	incidents, err := s.store.ListIncidents(true)
+	if err != nil {
+		return nil, err
+	}
+	for _, incident := range incidents {
+		worsen(incident.Severity)
+		status.Incidents = append(status.Incidents, PublicIncident{
+			Title:     incident.Title,
+			Message:   incident.Message,
+			Severity:  incident.Severity,
+			StartedAt: incident.CreatedAt,
+			UpdatedAt: incident.UpdatedAt,
+		})
+	}
+	return status, nil
+}
+
+func availability(free, capacity int) string {
+	switch {
+	case free <= 0:
+		return AvailabilityNone
+	case float64(free) <= float64(capacity)*limitedShare:
+		return AvailabilityLimited
+	default:
+		return AvailabilityAvailable
+	}
+}
+
+// statusRank 전체 상태 비교용 순위 (info 공지는 상태에 영향 없음)
+func statusRank(level string) int {
+	switch level {
+	case StatusOutage:
+		return 2
+	case StatusDegraded:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func validateIncidentSeverity(severity string) error {
+	switch severity {
+	case store.IncidentInfo, store.IncidentDegraded, store.IncidentOutage:
+		return nil
+	}
+	return errs.New(errs.ErrInvalid, "severity는 info, degraded, outage 중 하나여야 합니다: %q", severity)
+}
+
+func (s *Service) CreateIncident(req IncidentRequest) (*store.Incident, error) {
+	if req.Title == "" {
+		return nil, errs.New(errs.ErrInvalid, "공지 제목이 필요합니다")
+	}
+	if req.Severity == "" {
+		req.Severity = store.IncidentInfo
+	}
+	if err := validateIncidentSeverity(req.Severity); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	incident := &store.Incident{
+		ID:        uuid.New().String(),
+		Title:     req.Title,
+		Message:   req.Message,
+		Severity:  req.Severity,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.store.CreateIncident(incident); err != nil {
+		return nil, fmt.Errorf("공지 생성 실패: %w", err)
+	}
+	s.status.invalidate()
+
+	log.Printf("📢 장애 공지 게시: %s (%s)", incident.Title, incident.Severity)
+	s.audit("admin", "incident.created", incident.ID, fmt.Sprintf("severity=%s title=%s", incident.Severity, incident.Title))
+	return incident, nil
+}
+
+func (s *Service) ListIncidents(activeOnly bool) ([]*store.Incident, error) {
+	return s.store.ListIncidents(activeOnly)
+}
+
+// UpdateIncident 진행 상황에 맞춰 공지 내용이나 심각도 변경
+func (s *Service) UpdateIncident(id string, req IncidentRequest) (*store.Incident, error) {
+	incident, err := s.store.GetIncident(id)
+	if err != nil {
+		return nil, err
+	}
+	if incident.ResolvedAt != nil {
+		return nil, errs.New(errs.ErrConflict, "이미 해결된 공지입니다: %s", id)
+	}
+	if req.Severity != "" {
+		if err := validateIncidentSeverity(req.Severity); err != nil {
+			return nil, err
+		}
+		incident.Severity = req.Severity
+	}
+	if req.Title != "" {
+		incident.Title = req.Title
+	}
+	if req.Message != "" {
+		incident.Message = req.Message
+	}
+	incident.UpdatedAt = time.Now()
+
+	if err := s.store.UpdateIncident(incident); err != nil {
+		return nil, fmt.Errorf("공지 수정 실패: %w", err)
+	}
+	s.status.invalidate()
+	s.audit("admin", "incident.updated", incident.ID, "severity="+incident.Severity)
+	return incident, nil
+}
+
+// ResolveIncident 공지를 해결로 표시해 공개 상태에서 내림 (기록은 남김)
+func (s *Service) ResolveIncident(id string) (*store.Incident, error) {
+	incident, err := s.store.GetIncident(id)
+	if err != nil {
+		return nil, err
+	}
+	if incident.ResolvedAt != nil {
+		return incident, nil
+	}
+
+	now := time.Now()
+	incident.ResolvedAt = &now
+	incident.UpdatedAt = now
+	if err := s.store.UpdateIncident(incident); err != nil {
+		return nil, fmt.Errorf("공지 해결 처리 실패: %w", err)
+	}
+	s.status.invalidate()
+
+	log.Printf("✅ 장애 공지 해결: %s", incident.Title)
+	s.audit("admin", "incident.resolved", incident.ID, "")
+	return incident, nil
+}
@@ -0,0 +1,40 @@
+package session
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// metaStopTimeout 세션 생성 시 요청한 종료 제한 시간 (없으면 템플릿의 stop_policies)
+const metaStopTimeout = "stop_timeout_seconds"
+
+// checkStopTimeout 요청한 종료 제한 시간이 max_stop_timeout_seconds 안인지 확인
+func checkStopTimeout(req CreateRequest, cfg *config.Config) error {
+	if req.StopTimeoutSeconds < 0 || req.StopTimeoutSeconds > cfg.MaxStopTimeoutSeconds {
+		return errs.New(errs.ErrInvalid, "stop_timeout_seconds는 0~%d 사이여야 합니다: %d", cfg.MaxStopTimeoutSeconds, req.StopTimeoutSeconds)
+	}
+	return nil
+}
+
+// stopOptions 세션 템플릿의 종료 절차 (세션이 요청한 제한 시간이 있으면 그것을 씀)
+func stopOptions(session *store.Session, cfg *config.Config) docker.StopOptions {
+	policy := cfg.StopPolicyFor(session.Metadata["template"])
+	if seconds, err := strconv.Atoi(session.Metadata[metaStopTimeout]); err == nil && seconds > 0 {
+		policy.TimeoutSeconds = seconds
+	}
+	return docker.StopOptions{
+		Timeout:     time.Duration(policy.TimeoutSeconds) * time.Second,
+		PreStop:     policy.PreStop,
+		PreStopWait: time.Duration(policy.PreStopWaitSeconds) * time.Second,
+	}
+}
+
+// stopSessionContainer 세션 컨테이너를 종료 절차(pre-stop, SIGTERM, SIGKILL)에 따라 멈춤
+func (s *Service) stopSessionContainer(session *store.Session) error {
+	return s.dockerClient.StopContainerWith(session.ContainerID, stopOptions(session, s.config.Current()))
+}
@@ -0,0 +1,182 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// EndReasonStopHold 멈춘 세션을 stop_hold_minutes 안에 다시 시작하지 않아 GPU를 내놓음
+const EndReasonStopHold = "stop_hold_expired"
+
+// 멈춘 상태를 담는 세션 메타데이터 (RFC3339)
+const (
+	metaStoppedAt     = "stopped_at"
+	metaStopHoldUntil = "stop_hold_until" // 지나면 세션 정리
+)
+
+var sessionStops = metrics.NewCounter("sandman_session_stops_total", "사용자 세션 중지/시작 수 (action: stopped, started, released)", "action")
+
+// StopSession 컨테이너를 멈추되 GPU와 세션 기록은 holdMinutes(0이면 stop_hold_minutes) 동안 잡아 둠
+// 일시 중지와 달리 시작할 때 컨테이너를 새로 만들며, 그 전에 시간이 지나면 세션을 정리
+func (s *Service) StopSession(sessionID, actor string, holdMinutes int) (*store.Session, error) {
+	cfg := s.config.Current()
+	if holdMinutes < 0 || holdMinutes > cfg.MaxStopHoldMinutes {
+		return nil, errs.New(errs.ErrInvalid, "hold_minutes는 0~%d이어야 합니다", cfg.MaxStopHoldMinutes)
+	}
+	if holdMinutes == 0 {
+		holdMinutes = cfg.StopHoldMinutes
+	}
+
+	done, err := s.beginOperation()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	var stopped *store.Session
+	err = s.locker.WithLock("session-lock:"+sessionID, func() error {
+		session, err := s.localSession(sessionID)
+		if err != nil {
+			return err
+		}
+		if session.State != store.SessionRunning && session.State != store.SessionLocked {
+			return errs.New(errs.ErrConflict, "세션 %s는 %s 상태라 멈출 수 없습니다", session.ID, session.State)
+		}
+
+		s.closeWaker(session.ID)
+		if session.State == store.SessionLocked {
+			if err := s.dockerClient.UnpauseContainer(session.ContainerID); err != nil {
+				log.Printf("⚠️ 잠긴 컨테이너 재개 실패: %v", err)
+			}
+			delete(session.Metadata, metaLockedAt)
+			delete(session.Metadata, metaReclaimAt)
+		}
+		s.withdrawRoute(session)
+		if err := s.stopSessionContainer(session); err != nil {
+			return fmt.Errorf("컨테이너 중지 실패: %w", err)
+		}
+
+		now := time.Now()
+		holdUntil := now.Add(time.Duration(holdMinutes) * time.Minute)
+		session.State = store.SessionStopped
+		session.Metadata[metaStoppedAt] = now.UTC().Format(time.RFC3339)
+		session.Metadata[metaStopHoldUntil] = holdUntil.UTC().Format(time.RFC3339)
+		if err := s.store.UpdateSession(session); err != nil {
+			return fmt.Errorf("세션 중지 기록 실패: %w", err)
+		}
+
+		s.liveness.put(s.probeLiveness(session, nil))
+		sessionStops.Inc("stopped")
+		detail := fmt.Sprintf("hold_until=%s", session.Metadata[metaStopHoldUntil])
+		s.audit(actor, "session.stopped", session.ID, fmt.Sprintf("user=%s %s", session.UserID, detail))
+		s.recordEvent(session, EventStop, actor, "", detail)
+		log.Printf("⏹️ 세션 중지: %s (사용자: %s, GPU 유지: %s까지)", session.ID, session.UserID, holdUntil.Format(time.RFC3339))
+		stopped = session
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stopped, nil
+}
+
+// StartStoppedSession 멈춘 세션의 컨테이너를 이미지에서 다시 만들어 시작
+// 워크스페이스, GPU, SSH 포트, IP는 그대로이며 컨테이너 안에만 있던 접근 설정은 다시 적용
+func (s *Service) StartStoppedSession(sessionID, actor string) (*store.Session, error) {
+	done, err := s.beginOperation()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	var started *store.Session
+	err = s.locker.WithLock("session-lock:"+sessionID, func() error {
+		session, err := s.localSession(sessionID)
+		if err != nil {
+			return err
+		}
+		if session.State != store.SessionStopped {
+			return errs.New(errs.ErrConflict, "세션 %s는 멈춘 상태가 아닙니다 (%s)", session.ID, session.State)
+		}
+
+		cfg := s.config.Current()
+		releaseSlot, err := s.acquireNodeSlot(cfg)
+		if err != nil {
+			return err
+		}
+		info, err := s.dockerClient.RecreateContainer(session.ContainerID, stopOptions(session, cfg))
+		releaseSlot()
+		if err != nil {
+			return fmt.Errorf("컨테이너 재생성 실패: %w", err)
+		}
+		previous := session.ContainerID
+		session.ContainerID = info.ID
+		if info.IP != "" {
+			session.ContainerIP = info.IP
+		}
+		session.State = store.SessionRunning
+		delete(session.Metadata, metaStoppedAt)
+		delete(session.Metadata, metaStopHoldUntil)
+		if err := s.store.UpdateSession(session); err != nil {
+			return fmt.Errorf("세션 시작 기록 실패: %w", err)
+		}
+
+		if err := s.installSSHCA(session); err != nil {
+			log.Printf("⚠️ 세션 %s SSH CA 재설치 실패: %v", session.ID, err)
+		}
+		s.reapplyAccess(session)
+		if err := s.provisionWorkspaceSSH(session); err != nil {
+			log.Printf("⚠️ 세션 %s 워크스페이스 .ssh 재구성 실패: %v", session.ID, err)
+		}
+		if err := s.verifyGPUAttachment(session.ContainerID, session.GPUUUID, cfg); err != nil {
+			log.Printf("⚠️ 세션 %s GPU 확인 실패: %v", session.ID, err)
+		}
+		s.markActive(session.ID, time.Now())
+		s.publishRoute(session)
+		s.liveness.put(s.probeLiveness(session, nil))
+		sessionStops.Inc("started")
+		s.audit(actor, "session.started", session.ID, fmt.Sprintf("user=%s previous_container=%s", session.UserID, previous))
+		s.recordEvent(session, EventStart, actor, "", "image="+info.Image)
+		log.Printf("▶️ 멈춘 세션 시작: %s (컨테이너 %s → %s)", session.ID, previous[:12], session.ContainerID[:12])
+		started = session
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return started, nil
+}
+
+// releaseStoppedSession stop_hold_until이 지난 멈춘 세션을 정리해 GPU를 내놓음 (CheckInactivity에서 호출)
+// 같은 때 들어온 start와 겹치지 않도록 세션 락 안에서 상태를 다시 확인
+func (s *Service) releaseStoppedSession(session *store.Session, now time.Time) {
+	if holdUntil, err := time.Parse(time.RFC3339, session.Metadata[metaStopHoldUntil]); err == nil && now.Before(holdUntil) {
+		return
+	}
+	released := false
+	err := s.locker.WithLock("session-lock:"+session.ID, func() error {
+		current, err := s.store.GetSession(session.ID)
+		if err != nil || current.State != store.SessionStopped {
+			return nil
+		}
+		log.Printf("⏹️ 멈춘 세션 GPU 회수: %s (사용자: %s)", current.ID, current.UserID)
+		if err := s.cleanupSession(current, EndReasonStopHold); err != nil {
+			return err
+		}
+		released = true
+		return nil
+	})
+	if err != nil {
+		log.Printf("⚠️ 멈춘 세션 정리 실패: %v", err)
+		return
+	}
+	if released {
+		sessionStops.Inc("released")
+		s.audit("system", "session.stop_hold_expired", session.ID, "user="+session.UserID)
+	}
+}
@@ -0,0 +1,158 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// TeamDetail 팀과 구성원 목록
+type TeamDetail struct {
+	*store.Team
+	Members []*store.TeamMember `json:"members"`
+}
+
+func (s *Service) CreateTeam(name string) (*store.Team, error) {
+	if name == "" {
+		return nil, errs.New(errs.ErrInvalid, "팀 이름이 필요합니다")
+	}
+
+	team := &store.Team{
+		ID:        uuid.New().String(),
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+	if err := s.store.CreateTeam(team); err != nil {
+		return nil, fmt.Errorf("팀 생성 실패: %w", err)
+	}
+	s.audit("admin", "team.created", team.ID, "name="+name)
+	return team, nil
+}
+
+func (s *Service) ListTeams() ([]*store.Team, error) {
+	return s.store.ListTeams()
+}
+
+func (s *Service) GetTeam(teamID string) (*TeamDetail, error) {
+	team, err := s.store.GetTeam(teamID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.store.ListTeamMembers(teamID)
+	if err != nil {
+		return nil, err
+	}
+	return &TeamDetail{Team: team, Members: members}, nil
+}
+
+func (s *Service) DeleteTeam(teamID string) error {
+	if err := s.store.DeleteTeam(teamID); err != nil {
+		return err
+	}
+	s.audit("admin", "team.deleted", teamID, "")
+	return nil
+}
+
+// SetTeamMember 구성원 추가 또는 역할 변경 (lead 또는 member)
+func (s *Service) SetTeamMember(teamID, userID, role string) (*store.TeamMember, error) {
+	if role != store.RoleLead && role != store.RoleMember {
+		return nil, errs.New(errs.ErrInvalid, "역할은 %s 또는 %s여야 합니다: %q", store.RoleLead, store.RoleMember, role)
+	}
+	if err := validateUserID(userID); err != nil {
+		return nil, err
+	}
+	if _, err := s.store.GetTeam(teamID); err != nil {
+		return nil, err
+	}
+
+	member := &store.TeamMember{TeamID: teamID, UserID: userID, Role: role, AddedAt: time.Now()}
+	if err := s.store.SaveTeamMember(member); err != nil {
+		return nil, fmt.Errorf("팀 구성원 저장 실패: %w", err)
+	}
+	s.audit("admin", "team.member_set", teamID, fmt.Sprintf("user=%s role=%s", userID, role))
+	return member, nil
+}
+
+func (s *Service) RemoveTeamMember(teamID, userID string) error {
+	if err := s.store.RemoveTeamMember(teamID, userID); err != nil {
+		return err
+	}
+	s.audit("admin", "team.member_removed", teamID, "user="+userID)
+	return nil
+}
+
+// ListTeamSessions 팀 구성원들의 세션 (권한 확인은 호출 전에 auth에서 수행)
+func (s *Service) ListTeamSessions(teamID string) ([]*store.Session, error) {
+	members, err := s.store.ListTeamMembers(teamID)
+	if err != nil {
+		return nil, err
+	}
+	inTeam := make(map[string]bool, len(members))
+	for _, member := range members {
+		inTeam[member.UserID] = true
+	}
+
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		return nil, err
+	}
+	teamSessions := []*store.Session{}
+	for _, session := range sessions {
+		if inTeam[session.UserID] {
+			teamSessions = append(teamSessions, session)
+		}
+	}
+	return teamSessions, nil
+}
+
+// ExtendSession 세션 만료 시각을 minutes만큼 늦춤 (지금부터 남은 시간이 최대 TTL을 넘지 않아야 함)
+func (s *Service) ExtendSession(sessionID string, minutes int, actor string) (*store.Session, error) {
+	if minutes <= 0 {
+		return nil, errs.New(errs.ErrInvalid, "연장 시간은 1분 이상이어야 합니다: %d", minutes)
+	}
+	session, err := s.store.GetSession(sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkNotQuarantined(session); err != nil {
+		return nil, err
+	}
+	if session.EvictAt != nil {
+		return nil, errs.New(errs.ErrConflict, "회수 예고된 spot 세션은 연장할 수 없습니다 (회수 시각: %s)", session.EvictAt.Format(time.RFC3339))
+	}
+
+	cfg := s.config.Current()
+	expiresAt := session.ExpiresAt.Add(time.Duration(minutes) * time.Minute)
+	if limit := time.Now().Add(time.Duration(cfg.MaxTTLMinutes) * time.Minute); expiresAt.After(limit) {
+		return nil, errs.New(errs.ErrInvalid, "연장 후 남은 시간이 최대 허용값 %d분을 초과합니다", cfg.MaxTTLMinutes)
+	}
+
+	previous := session.ExpiresAt
+	session.ExpiresAt = expiresAt
+	session.TTLMinutes += minutes
+	if err := s.store.UpdateSession(session); err != nil {
+		return nil, fmt.Errorf("세션 만료 시각 변경 실패: %w", err)
+	}
+
+	if err := s.renderWelcome(session); err != nil {
+		log.Printf("⚠️ 세션 %s 환영 메시지 갱신 실패: %v", session.ID, err)
+	}
+
+	log.Printf("⏳ 세션 연장: %s (사용자: %s) %s → %s", session.ID, session.UserID, previous.Format(time.RFC3339), expiresAt.Format(time.RFC3339))
+	s.audit(actor, "session.extended", session.ID, fmt.Sprintf("minutes=%d expires_at=%s", minutes, expiresAt.Format(time.RFC3339)))
+	s.recordEvent(session, EventExtend, actor, "", fmt.Sprintf("minutes=%d expires_at=%s", minutes, expiresAt.Format(time.RFC3339)))
+	return session, nil
+}
+
+// DeleteSessionAs 다른 사용자가 세션을 삭제할 때 (팀 리드 등) 감사 기록을 남기고 삭제
+func (s *Service) DeleteSessionAs(sessionID, actor string) error {
+	if err := s.deleteSession(sessionID, actor); err != nil {
+		return err
+	}
+	s.audit(actor, "session.deleted", sessionID, "")
+	return nil
+}
@@ -0,0 +1,132 @@
+package session
+
+import (
+	"errors"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+	"github.com/sandman/gpu-ssh-gateway/internal/workspace"
+)
+
+// SessionView API 응답용 세션 표현 (저장되지 않는 표시용 필드 포함)
+type SessionView struct {
+	*store.Session
+	Timezone       string              `json:"timezone"`
+	ExpiresAtLocal string              `json:"expires_at_local"`
+	Usage          *store.SessionUsage `json:"usage,omitempty"`
+	Workspace      *workspace.Usage    `json:"workspace,omitempty"` // 워크스페이스 쿼터를 건 세션의 디스크 사용량과 한도
+}
+
+// userLocation 사용자 시간대 설정을 우선 적용하고, 없으면 배포 기본값 사용
+func (s *Service) userLocation(userID string) *time.Location {
+	if settings, err := s.store.GetUserSettings(userID); err == nil && settings.Timezone != "" {
+		if loc, err := time.LoadLocation(settings.Timezone); err == nil {
+			return loc
+		}
+	}
+	return s.config.Current().Location()
+}
+
+// View 세션을 사용자 시간대 기준 표시 정보와 함께 반환 (비밀번호 등 민감한 필드 제외)
+func (s *Service) View(session *store.Session) *SessionView {
+	loc := s.userLocation(session.UserID)
+	view := &SessionView{
+		Session:        redactSession(session),
+		Timezone:       loc.String(),
+		ExpiresAtLocal: session.ExpiresAt.In(loc).Format(time.RFC3339),
+	}
+	if usage, err := s.store.GetSessionUsage(session.ID); err == nil {
+		view.Usage = usage
+	}
+	view.Workspace = s.workspaceUsage(session)
+	return view
+}
+
+func (s *Service) Views(sessions []*store.Session) []*SessionView {
+	views := make([]*SessionView, 0, len(sessions))
+	for _, session := range sessions {
+		views = append(views, s.View(session))
+	}
+	return views
+}
+
+func (s *Service) GetUserSettings(userID string) (*store.UserSettings, error) {
+	settings, err := s.store.GetUserSettings(userID)
+	if errors.Is(err, errs.ErrNotFound) {
+		return &store.UserSettings{UserID: userID}, nil
+	}
+	return settings, err
+}
+
+func (s *Service) UpdateUserSettings(settings *store.UserSettings) error {
+	if settings.Timezone != "" {
+		if _, err := time.LoadLocation(settings.Timezone); err != nil {
+			return errs.New(errs.ErrInvalid, "알 수 없는 시간대입니다: %s", settings.Timezone)
+		}
+	}
+	if settings.Locale != "" && !config.ValidLocale(settings.Locale) {
+		return errs.New(errs.ErrInvalid, "잘못된 로케일입니다: %s (예: ko_KR.UTF-8)", settings.Locale)
+	}
+	if settings.WorkspacePolicy != "" {
+		retention := config.WorkspaceRetention{Policy: settings.WorkspacePolicy, Days: settings.WorkspaceRetentionDays}
+		if err := retention.Validate(); err != nil {
+			return errs.New(errs.ErrInvalid, "워크스페이스 정책이 잘못되었습니다: %v", err)
+		}
+	} else if settings.WorkspaceRetentionDays != 0 {
+		return errs.New(errs.ErrInvalid, "workspace_retention_days는 workspace_policy와 함께 지정해야 합니다")
+	}
+
+	settings.UpdatedAt = time.Now()
+	return s.store.SaveUserSettings(settings)
+}
+
+// containerLocale 컨테이너 시간대와 로케일 결정
+// 우선순위: 사용자 설정 > 이미지(템플릿) 기본값 > 배포 기본값(container_timezone/timezone, container_locale)
+func (s *Service) containerLocale(userID, image string) (string, string) {
+	cfg := s.config.Current()
+	tz, locale := cfg.ContainerTimezone, cfg.ContainerLocale
+	if tz == "" {
+		tz = cfg.Timezone
+	}
+	if defaults, ok := cfg.ImageLocales[image]; ok {
+		if defaults.Timezone != "" {
+			tz = defaults.Timezone
+		}
+		if defaults.Locale != "" {
+			locale = defaults.Locale
+		}
+	}
+	if settings, err := s.store.GetUserSettings(userID); err == nil {
+		if settings.Timezone != "" {
+			tz = settings.Timezone
+		}
+		if settings.Locale != "" {
+			locale = settings.Locale
+		}
+	}
+	return tz, locale
+}
+
+// resolveExpireAt "18:00" 같은 벽시계 시각(사용자 시간대 기준 오늘) 또는 RFC3339 시각을 절대 시각으로 변환
+func resolveExpireAt(value string, now time.Time, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		if !t.After(now) {
+			return time.Time{}, errs.New(errs.ErrInvalid, "만료 시각 %s가 이미 지났습니다", value)
+		}
+		return t, nil
+	}
+
+	clock, err := time.Parse("15:04", value)
+	if err != nil {
+		return time.Time{}, errs.New(errs.ErrInvalid, "만료 시각 형식이 잘못되었습니다 (HH:MM 또는 RFC3339): %s", value)
+	}
+
+	local := now.In(loc)
+	t := time.Date(local.Year(), local.Month(), local.Day(), clock.Hour(), clock.Minute(), 0, 0, loc)
+	if !t.After(now) {
+		return time.Time{}, errs.New(errs.ErrInvalid, "오늘 %s(%s)는 이미 지났습니다", value, loc.String())
+	}
+	return t, nil
+}
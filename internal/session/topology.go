@@ -0,0 +1,52 @@
+package session
+
+import (
+	"strconv"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/gpu"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// 할당된 GPU의 위치를 담는 세션 메타데이터
+const (
+	metaGPUIndex  = "gpu_index"
+	metaNUMANode  = "numa_node"  // 알 수 없으면 없음
+	metaPCIBusID  = "pci_bus_id" // 알 수 없으면 없음
+	metaPlacement = "placement"  // 요청한 배치 힌트 (없으면 없음)
+)
+
+// checkPlacementHint 알 수 없는 힌트나 힌트를 적용할 수 없는 요청(인스턴스 지정, CPU 전용)을 거부
+func checkPlacementHint(req CreateRequest) error {
+	if req.Placement == "" {
+		return nil
+	}
+	if !gpu.ValidPlacementHint(req.Placement) {
+		return errs.New(errs.ErrInvalid, "placement는 %q 또는 %q여야 합니다: %q", gpu.PlacementPack, gpu.PlacementSpread, req.Placement)
+	}
+	if req.MIGInstanceUUID != "" {
+		return errs.New(errs.ErrInvalid, "mig_instance_uuid를 지정한 요청에는 placement를 쓸 수 없습니다")
+	}
+	if req.MIGProfile == gpu.ProfileCPU {
+		return errs.New(errs.ErrInvalid, "CPU 전용 세션에는 placement를 쓸 수 없습니다")
+	}
+	return nil
+}
+
+// recordTopology 할당된 인스턴스의 GPU 인덱스, NUMA 노드, PCI 버스와 요청한 힌트를 세션에 기록
+func (s *Service) recordTopology(session *store.Session, instance *gpu.MIGInstance, hint string) {
+	if instance.UUID == "" {
+		return
+	}
+	t := s.gpuManager.Topology(instance.GPUIndex)
+	session.Metadata[metaGPUIndex] = strconv.Itoa(t.GPUIndex)
+	if t.NUMANode >= 0 {
+		session.Metadata[metaNUMANode] = strconv.Itoa(t.NUMANode)
+	}
+	if t.PCIBusID != "" {
+		session.Metadata[metaPCIBusID] = t.PCIBusID
+	}
+	if hint != "" {
+		session.Metadata[metaPlacement] = hint
+	}
+}
@@ -0,0 +1,124 @@
+package session
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+var networkBytesTotal = metrics.NewCounter("sandman_session_network_bytes_total", "세션 컨테이너 네트워크 전송량", "direction")
+
+// SampleNetworkUsage 모든 세션 컨테이너의 네트워크 카운터를 읽어 사용량에 누적
+func (s *Service) SampleNetworkUsage() error {
+	// 여러 복제본이 같은 증분을 중복 누적하지 않도록 한 곳에서만 수집
+	lease, err := s.locker.TryAcquire("usage-sampler")
+	if err != nil {
+		return err
+	}
+	if lease == nil {
+		logging.Debugf("다른 복제본이 사용량 수집 중 - 건너뜀")
+		return nil
+	}
+	defer lease.Release()
+
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if _, err := s.sampleUsage(session, "", nil); err != nil {
+			logging.Debugf("세션 %s 사용량 수집 실패: %v", session.ID, err)
+		}
+	}
+	return nil
+}
+
+// sampleUsage 컨테이너 카운터 증분을 누적 (컨테이너 재시작으로 카운터가 줄면 새 값 전체를 증분으로 봄)
+// endReason이 있으면 최종 수집이므로 카운터를 읽지 못해도 지금까지의 값으로 종료 처리하고 워크스페이스 처리(plan)도 기록
+func (s *Service) sampleUsage(session *store.Session, endReason string, plan *workspacePlan) (*store.SessionUsage, error) {
+	final := endReason != ""
+	now := time.Now()
+	usage, err := s.store.GetSessionUsage(session.ID)
+	if errors.Is(err, errs.ErrNotFound) {
+		usage = &store.SessionUsage{
+			SessionID:  session.ID,
+			UserID:     session.UserID,
+			ProjectID:  session.ProjectID,
+			NodeID:     session.NodeID,
+			MIGProfile: session.MIGProfile,
+			Tier:       session.Tier,
+			RateFactor: rateFactor(session.Tier, s.config.Current()),
+			StartedAt:  session.CreatedAt,
+		}
+	} else if err != nil {
+		return nil, err
+	} else if usage.EndedAt != nil && !final {
+		// 최종 보고가 이미 남은 세션 (정리 중 주기 수집과 겹침)
+		return usage, nil
+	}
+
+	rx, tx, sampleErr := s.dockerClient.NetworkCounters(session.ContainerID)
+	if sampleErr != nil && !final {
+		return nil, sampleErr
+	}
+	if sampleErr == nil {
+		rxDelta, txDelta := counterDelta(usage.LastRawRx, rx), counterDelta(usage.LastRawTx, tx)
+		usage.RxBytes += rxDelta
+		usage.TxBytes += txDelta
+		usage.LastRawRx, usage.LastRawTx = rx, tx
+		networkBytesTotal.Add(float64(rxDelta), "rx")
+		networkBytesTotal.Add(float64(txDelta), "tx")
+	}
+
+	usage.SampledAt = now
+	if final {
+		usage.EndedAt = &now
+		usage.EndReason = endReason
+		if plan != nil {
+			usage.WorkspacePolicy = plan.Policy
+			usage.WorkspaceArchiveAt = plan.ArchiveAt
+		}
+	}
+	if err := s.store.SaveSessionUsage(usage); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+func counterDelta(last, current uint64) uint64 {
+	if current < last {
+		return current
+	}
+	return current - last
+}
+
+// recordFinalUsage 세션 종료 직전 마지막 사용량을 수집해 최종 보고로 남김
+// 주기 수집과 같은 기록을 읽고 고쳐 쓰므로 같은 리스를 잡아 마지막 증분이 두 번 누적되지 않게 함
+func (s *Service) recordFinalUsage(session *store.Session, reason string, plan workspacePlan) {
+	if lease, err := s.locker.Acquire("usage-sampler"); err != nil {
+		log.Printf("⚠️ 사용량 수집 락 획득 실패 (락 없이 기록): %v", err)
+	} else {
+		defer lease.Release()
+	}
+	usage, err := s.sampleUsage(session, reason, &plan)
+	if err != nil {
+		log.Printf("⚠️ 세션 최종 사용량 기록 실패: %v", err)
+		return
+	}
+	log.Printf("📊 세션 최종 사용량: %s (사용자: %s, 수신: %d bytes, 송신: %d bytes, 사용 시간: %v, 워크스페이스: %s)",
+		session.ID, session.UserID, usage.RxBytes, usage.TxBytes, usage.EndedAt.Sub(usage.StartedAt).Round(time.Second), plan.Policy)
+}
+
+// GetUsage 세션 사용량 (종료된 세션 포함)
+func (s *Service) GetUsage(sessionID string) (*store.SessionUsage, error) {
+	return s.store.GetSessionUsage(sessionID)
+}
+
+func (s *Service) ListUsage(userID, projectID string) ([]*store.SessionUsage, error) {
+	return s.store.ListSessionUsage(userID, projectID)
+}
@@ -0,0 +1,200 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sandman/gpu-ssh-gateway/internal/auth"
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/snapshot"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// EndReasonPurged 사용자 데이터 삭제 요청으로 세션을 종료함
+const EndReasonPurged = "purged"
+
+// UserPurgeReport 사용자 데이터 삭제 결과 (오프보딩, 개인정보 삭제 요청 처리 기록)
+type UserPurgeReport struct {
+	UserID    string `json:"user_id"`
+	Pseudonym string `json:"pseudonym,omitempty"` // anonymize로 남긴 기록에서 사용자 ID 대신 쓰인 값
+	History   string `json:"history"`
+	Audit     string `json:"audit"`
+
+	SessionsTerminated   []string `json:"sessions_terminated"`
+	CollaborationsEnded  []string `json:"collaborations_ended"` // 협업자로 등록되어 있던 다른 사용자의 세션
+	APIKeysRevoked       []string `json:"api_keys_revoked"`
+	WorkspaceRemoved     bool     `json:"workspace_removed"`
+	ArchivesRemoved      bool     `json:"archives_removed"`
+	RestorePointsRemoved int      `json:"restore_points_removed"`
+
+	// 테이블별로 삭제하거나 가명으로 바꾼 행 수
+	Records map[string]int64 `json:"records"`
+
+	Errors     []string  `json:"errors,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+func (r *UserPurgeReport) fail(format string, args ...interface{}) {
+	msg := fmt.Sprintf(format, args...)
+	log.Printf("⚠️ 사용자 %s 데이터 삭제: %s", r.UserID, msg)
+	r.Errors = append(r.Errors, msg)
+}
+
+// PurgeUserData 사용자의 세션을 끝내고 워크스페이스, 복원 지점, 키, 설정, 기록을 지움
+// 세션 기록과 감사 기록은 user_purge 설정(요청에서 덮어쓸 수 있음)에 따라 삭제하거나 가명으로 바꿈
+// 일부 단계가 실패해도 나머지는 계속 진행하고 보고서의 errors에 남김
+func (s *Service) PurgeUserData(userID string, policy config.UserPurge) (*UserPurgeReport, error) {
+	if err := validateUserID(userID); err != nil {
+		return nil, err
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, errs.New(errs.ErrInvalid, "%v", err)
+	}
+	done, err := s.beginOperation()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	report := &UserPurgeReport{
+		UserID:              userID,
+		History:             policy.History,
+		Audit:               policy.Audit,
+		SessionsTerminated:  []string{},
+		CollaborationsEnded: []string{},
+		APIKeysRevoked:      []string{},
+		StartedAt:           time.Now(),
+	}
+	if policy.History == store.PurgeAnonymize || policy.Audit == store.PurgeAnonymize {
+		report.Pseudonym = "purged-" + uuid.New().String()[:8]
+	}
+	log.Printf("🧽 사용자 데이터 삭제 시작: %s (기록: %s, 감사: %s)", userID, policy.History, policy.Audit)
+
+	// 세션 종료 (최종 사용량은 정리 중에 기록되고 아래에서 함께 처리됨)
+	if session, err := s.store.GetSessionByUserID(userID); err == nil {
+		if err := s.cleanupSession(session, EndReasonPurged); err != nil {
+			report.fail("세션 %s 정리 실패: %v", session.ID, err)
+		} else {
+			report.SessionsTerminated = append(report.SessionsTerminated, session.ID)
+		}
+	}
+	s.endCollaborations(userID, report)
+	s.revokeUserKeys(userID, report)
+
+	// 워크스페이스, 보관본, 복원 지점 파일
+	err = s.locker.WithLock("workspace-lifecycle:"+userID, func() error {
+		if err := s.removeWorkspace(userID); err != nil {
+			return err
+		}
+		report.WorkspaceRemoved = true
+		return nil
+	})
+	if err != nil {
+		report.fail("워크스페이스 삭제 실패: %v", err)
+	}
+	if root, err := filepath.Abs(s.workspaceRoot); err != nil {
+		report.fail("워크스페이스 루트 경로 확인 실패: %v", err)
+	} else if err := os.RemoveAll(filepath.Join(root, workspaceArchiveDirName, userID)); err != nil {
+		report.fail("워크스페이스 보관본 삭제 실패: %v", err)
+	} else {
+		report.ArchivesRemoved = true
+	}
+	err = s.locker.WithLock("restore-points:"+userID, func() error {
+		points, err := s.store.ListUserRestorePoints(userID)
+		if err != nil {
+			return err
+		}
+		for _, rp := range points {
+			if err := snapshot.Remove(rp.Path); err != nil {
+				report.fail("복원 지점 %s 삭제 실패: %v", rp.ID, err)
+				continue
+			}
+			report.RestorePointsRemoved++
+		}
+		dir, err := s.restorePointUserDir(userID)
+		if err != nil {
+			return err
+		}
+		return os.RemoveAll(dir)
+	})
+	if err != nil {
+		report.fail("복원 지점 삭제 실패: %v", err)
+	}
+
+	// 저장소의 사용자 행 (설정, 팀, 인증서, 예약, DNS 기록, 사용량, 감사 기록)
+	records, err := s.store.PurgeUserData(userID, store.UserPurgeOptions{
+		History:   policy.History,
+		Audit:     policy.Audit,
+		Pseudonym: report.Pseudonym,
+	})
+	if err != nil {
+		report.fail("저장소 기록 삭제 실패: %v", err)
+		records = map[string]int64{}
+	}
+	report.Records = records
+	report.FinishedAt = time.Now()
+
+	// 감사 기록을 남기지 않는 정책이면 삭제 사실만 가명으로 남김
+	target := userID
+	if policy.Audit != store.PurgeKeep {
+		target = report.Pseudonym
+		if target == "" {
+			target = "purged-user"
+		}
+	}
+	s.audit("admin", "user.purged", target, fmt.Sprintf("history=%s audit=%s sessions=%d keys=%d errors=%d",
+		policy.History, policy.Audit, len(report.SessionsTerminated), len(report.APIKeysRevoked), len(report.Errors)))
+	log.Printf("🧽 사용자 데이터 삭제 완료: %s (세션 %d개, 키 %d개, 오류 %d건)",
+		userID, len(report.SessionsTerminated), len(report.APIKeysRevoked), len(report.Errors))
+	return report, nil
+}
+
+// endCollaborations 사용자가 협업자로 등록된 다른 세션에서 키를 제거
+func (s *Service) endCollaborations(userID string, report *UserPurgeReport) {
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		report.fail("세션 목록 조회 실패: %v", err)
+		return
+	}
+	for _, session := range sessions {
+		collaborators, err := s.store.ListCollaborators(session.ID)
+		if err != nil {
+			continue
+		}
+		for _, collab := range collaborators {
+			if collab.UserID != userID {
+				continue
+			}
+			if err := s.RemoveCollaborator(session.ID, userID); err != nil {
+				report.fail("세션 %s 협업자 키 제거 실패: %v", session.ID, err)
+				continue
+			}
+			report.CollaborationsEnded = append(report.CollaborationsEnded, session.ID)
+		}
+	}
+}
+
+// revokeUserKeys 사용자 범위(user:<id>)가 붙은 API 키를 폐기
+func (s *Service) revokeUserKeys(userID string, report *UserPurgeReport) {
+	tokens, err := s.store.ListAPITokens("")
+	if err != nil {
+		report.fail("API 키 목록 조회 실패: %v", err)
+		return
+	}
+	for _, token := range tokens {
+		if token.RevokedAt != nil || auth.TokenUser(token) != userID {
+			continue
+		}
+		if err := s.store.RevokeAPIToken(token.ID); err != nil {
+			report.fail("API 키 %s 폐기 실패: %v", token.ID, err)
+			continue
+		}
+		report.APIKeysRevoked = append(report.APIKeysRevoked, token.ID)
+	}
+}
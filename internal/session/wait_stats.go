@@ -0,0 +1,154 @@
+package session
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// maxWaitWindows 한 번에 조회할 수 있는 구간 수
+const maxWaitWindows = 8
+
+var sessionWaitSeconds = metrics.NewHistogram("sandman_session_wait_seconds", "세션 생성 요청부터 실행까지 걸린 시간 (대기열, 이미지 빌드, 컨테이너 시작 포함)",
+	[]float64{1, 5, 15, 30, 60, 120, 300, 600, 1800, 3600}, "profile")
+
+// WaitTimeStats 한 프로파일의 요청부터 실행까지 걸린 시간 분포
+type WaitTimeStats struct {
+	Profile    string  `json:"profile"`
+	Sessions   int     `json:"sessions"`
+	AvgSeconds float64 `json:"avg_seconds"`
+	P50Seconds float64 `json:"p50_seconds"`
+	P95Seconds float64 `json:"p95_seconds"`
+	MaxSeconds float64 `json:"max_seconds"`
+}
+
+// WaitTimeWindow 최근 Window 동안 실행된 세션의 프로파일별 대기 시간 (프로파일 이름순)
+type WaitTimeWindow struct {
+	Window   string           `json:"window"`
+	Since    time.Time        `json:"since"`
+	Profiles []*WaitTimeStats `json:"profiles"`
+}
+
+// ParseWaitWindow "90m", "24h", "7d" 같은 구간 (Go duration에 일 단위 d 추가)
+func ParseWaitWindow(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	var window time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, errs.New(errs.ErrInvalid, "잘못된 구간입니다: %q (예: 1h, 24h, 7d)", value)
+		}
+		window = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, errs.New(errs.ErrInvalid, "잘못된 구간입니다: %q (예: 1h, 24h, 7d)", value)
+		}
+		window = d
+	}
+	if window <= 0 {
+		return 0, errs.New(errs.ErrInvalid, "구간은 0보다 커야 합니다: %q", value)
+	}
+	return window, nil
+}
+
+// recordSessionWait 생성 요청을 받은 때부터 세션이 실행될 때까지 걸린 시간 기록 (실패해도 세션 생성은 계속)
+func (s *Service) recordSessionWait(session *store.Session, submittedAt time.Time) {
+	wait := session.CreatedAt.Sub(submittedAt)
+	if wait < 0 {
+		wait = 0
+	}
+	sessionWaitSeconds.Observe(wait.Seconds(), session.MIGProfile)
+	record := &store.SessionWait{
+		SessionID:   session.ID,
+		UserID:      session.UserID,
+		ProjectID:   session.ProjectID,
+		Profile:     session.MIGProfile,
+		Tier:        session.Tier,
+		NodeID:      session.NodeID,
+		SubmittedAt: submittedAt,
+		RunningAt:   session.CreatedAt,
+		WaitMs:      wait.Milliseconds(),
+	}
+	if err := s.store.SaveSessionWait(record); err != nil {
+		log.Printf("⚠️ 세션 %s 대기 시간 기록 저장 실패: %v", session.ID, err)
+	}
+}
+
+// WaitTimeStats 구간마다 그 안에 실행된 세션의 프로파일별 p50/p95 대기 시간 (profile이 있으면 그 프로파일만)
+// 구간은 wait_stats_retention_days보다 길 수 없음
+func (s *Service) WaitTimeStats(windows []string, profile string) ([]*WaitTimeWindow, error) {
+	if len(windows) == 0 || len(windows) > maxWaitWindows {
+		return nil, errs.New(errs.ErrInvalid, "구간은 1~%d개여야 합니다", maxWaitWindows)
+	}
+	retention := time.Duration(s.config.Current().WaitStatsRetentionDays) * 24 * time.Hour
+	durations := make([]time.Duration, len(windows))
+	longest := time.Duration(0)
+	for i, value := range windows {
+		d, err := ParseWaitWindow(value)
+		if err != nil {
+			return nil, err
+		}
+		if d > retention {
+			return nil, errs.New(errs.ErrInvalid, "구간 %s가 보존 기간 %d일보다 깁니다 (wait_stats_retention_days)", value, s.config.Current().WaitStatsRetentionDays)
+		}
+		durations[i] = d
+		longest = max(longest, d)
+	}
+
+	now := time.Now()
+	waits, err := s.store.ListSessionWaits(now.Add(-longest))
+	if err != nil {
+		return nil, fmt.Errorf("대기 시간 기록 조회 실패: %v", err)
+	}
+
+	result := make([]*WaitTimeWindow, len(windows))
+	for i, window := range durations {
+		since := now.Add(-window)
+		seconds := make(map[string][]float64)
+		for _, w := range waits {
+			if w.RunningAt.Before(since) || (profile != "" && w.Profile != profile) {
+				continue
+			}
+			seconds[w.Profile] = append(seconds[w.Profile], float64(w.WaitMs)/1000)
+		}
+
+		stats := make([]*WaitTimeStats, 0, len(seconds))
+		for name, values := range seconds {
+			sort.Float64s(values)
+			total := 0.0
+			for _, v := range values {
+				total += v
+			}
+			stats = append(stats, &WaitTimeStats{
+				Profile:    name,
+				Sessions:   len(values),
+				AvgSeconds: total / float64(len(values)),
+				P50Seconds: percentile(values, 0.5),
+				P95Seconds: percentile(values, 0.95),
+				MaxSeconds: values[len(values)-1],
+			})
+		}
+		sort.Slice(stats, func(a, b int) bool { return stats[a].Profile < stats[b].Profile })
+		result[i] = &WaitTimeWindow{Window: strings.TrimSpace(windows[i]), Since: since, Profiles: stats}
+	}
+	return result, nil
+}
+
+// purgeSessionWaits 보존 기간이 지난 대기 시간 기록 정리 (만료 세션 정리 주기에 함께 실행)
+func (s *Service) purgeSessionWaits() {
+	days := s.config.Current().WaitStatsRetentionDays
+	if n, err := s.store.PurgeSessionWaits(time.Now().AddDate(0, 0, -days)); err != nil {
+		log.Printf("⚠️ 대기 시간 기록 정리 실패: %v", err)
+	} else if n > 0 {
+		logging.Debugf("보존 기간(%d일)이 지난 대기 시간 기록 %d건 정리", days, n)
+	}
+}
@@ -0,0 +1,59 @@
+package session
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// welcomeFile 워크스페이스 안의 환영 메시지 경로 (컨테이너의 /etc/profile.d/sandman-welcome.sh가 로그인 시 출력)
+const welcomeFile = ".sandman/motd"
+
+// renderWelcome 배포 템플릿으로 환영 메시지를 만들어 세션 워크스페이스에 기록
+// 세션 생성과 만료 시각 변경(연장, 드레인 조정) 때마다 다시 렌더링하고 세션 상태 파일도 함께 갱신
+func (s *Service) renderWelcome(session *store.Session) error {
+	cfg := s.config.Current()
+	loc := s.userLocation(session.UserID)
+	data := config.WelcomeData{
+		UserID:         session.UserID,
+		SessionID:      session.ID,
+		Profile:        session.MIGProfile,
+		GPUUUID:        session.GPUUUID,
+		GPUName:        session.GPUName,
+		SSHPort:        session.SSHPort,
+		ExpiresAt:      session.ExpiresAt.In(loc).Format("2006-01-02 15:04"),
+		Timezone:       loc.String(),
+		SupportContact: cfg.Welcome.SupportContact,
+		DocsURL:        cfg.Welcome.DocsURL,
+	}
+
+	var buf bytes.Buffer
+	if err := cfg.WelcomeTemplate().Execute(&buf, data); err != nil {
+		return fmt.Errorf("환영 메시지 렌더링 실패: %v", err)
+	}
+
+	workspaceDir, err := s.resolveWorkspaceDir(session.UserID)
+	if errors.Is(err, errWorkspaceNotOnHost) {
+		logging.Debugf("세션 %s 워크스페이스를 호스트에서 읽을 수 없어 환영 메시지를 건너뜀", session.ID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(workspaceDir, welcomeFile)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	// 바뀐 만료 시각이 다음 감시 주기 전에도 sandman-status에 보이도록 상태 파일도 갱신
+	return s.writeAgentStatus(s.agentStatus(session, cfg, time.Now()))
+}
@@ -0,0 +1,77 @@
+package session
+
+import (
+	"github.com/sandman/gpu-ssh-gateway/internal/capacity"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// WhatIf 가상의 노드 추가/MIG 재분할을 현재 용량과 수요(실행 중인 세션 + 가정한 추가 수요)에 비교
+// 실제 GPU나 노드 상태는 바꾸지 않음
+func (s *Service) WhatIf(scenario capacity.Scenario) (*capacity.Result, error) {
+	snapshot, err := s.capacitySnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := capacity.Simulate(snapshot, scenario, s.gpuManager.GetAvailableProfiles())
+	if err != nil {
+		return nil, errs.Wrap(errs.ErrInvalid, err)
+	}
+	return result, nil
+}
+
+// capacitySnapshot 노드별 프로파일별 전체 MIG 인스턴스 수(남은 용량 + 실행 중인 세션)와 프로파일별 수요
+// 등록된 노드가 없으면 이 호스트의 GPU 매니저를 기준으로 계산
+func (s *Service) capacitySnapshot() (capacity.Snapshot, error) {
+	snapshot := capacity.Snapshot{
+		Capacity: make(map[string]map[string]int),
+		Demand:   make(map[string]int),
+	}
+
+	sessions, err := s.store.ListAllSessions()
+	if err != nil {
+		return snapshot, err
+	}
+	for _, session := range sessions {
+		snapshot.Demand[session.MIGProfile]++
+	}
+
+	nodes, err := s.store.ListNodes()
+	if err != nil {
+		return snapshot, err
+	}
+	if len(nodes) == 0 {
+		local := make(map[string]int)
+		for _, instance := range s.gpuManager.ListMIGInstances() {
+			local[instance.Profile.Name]++
+		}
+		snapshot.Capacity[s.localNodeName()] = local
+		return snapshot, nil
+	}
+
+	for _, node := range nodes {
+		// 새 세션을 받을 수 없는 노드는 용량에서 제외
+		if node.Status != store.NodeReady {
+			continue
+		}
+		total := make(map[string]int, len(node.Capacity))
+		for name, available := range node.Capacity {
+			total[name] = available
+		}
+		snapshot.Capacity[node.ID] = total
+	}
+	for _, session := range sessions {
+		if total, ok := snapshot.Capacity[session.NodeID]; ok {
+			total[session.MIGProfile]++
+		}
+	}
+	return snapshot, nil
+}
+
+func (s *Service) localNodeName() string {
+	if s.nodeID != "" {
+		return s.nodeID
+	}
+	return "local"
+}
@@ -0,0 +1,180 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+)
+
+// userIDPattern 경로, 컨테이너 이름, authorized_keys 태그에 안전하게 쓸 수 있는 사용자 ID
+var userIDPattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]{0,63}$`)
+
+func validateUserID(userID string) error {
+	if !userIDPattern.MatchString(userID) || strings.Contains(userID, "..") {
+		return errs.New(errs.ErrInvalid, "잘못된 사용자 ID입니다: %q (영문, 숫자, '.', '_', '-'만 허용)", userID)
+	}
+	return nil
+}
+
+// errWorkspaceNotOnHost 볼륨 드라이버가 컨테이너에 붙일 때만 저장소를 마운트해서 호스트에서 워크스페이스 파일을 다룰 수 없음
+var errWorkspaceNotOnHost = errs.New(errs.ErrInvalid, "워크스페이스 볼륨 내용을 호스트에서 읽을 수 없습니다 (볼륨 드라이버가 원격 저장소를 사용)")
+
+// resolveWorkspaceDir 사용자 워크스페이스 경로를 계산하고 workspaceRoot 밖으로 벗어나지 않는지 검증
+// 이미 존재하는 경로는 심볼릭 링크를 따라간 실제 위치까지 확인
+// 볼륨 백엔드는 사용자 볼륨을 만들고 그 마운트 지점을 반환
+func (s *Service) resolveWorkspaceDir(userID string) (string, error) {
+	if err := validateUserID(userID); err != nil {
+		return "", err
+	}
+	if volumes := s.dockerClient.WorkspaceVolumes(); volumes != nil {
+		if !volumes.HostReadable() {
+			return "", errWorkspaceNotOnHost
+		}
+		vol, err := s.dockerClient.EnsureWorkspaceVolume(userID)
+		if err != nil {
+			return "", err
+		}
+		return vol.Mountpoint, nil
+	}
+
+	root, err := filepath.Abs(s.workspaceRoot)
+	if err != nil {
+		return "", fmt.Errorf("워크스페이스 루트 경로 확인 실패: %v", err)
+	}
+
+	dir := filepath.Join(root, userID)
+	if !withinRoot(root, dir) {
+		return "", errs.New(errs.ErrInvalid, "워크스페이스 경로가 루트를 벗어납니다: %s", dir)
+	}
+
+	info, err := os.Lstat(dir)
+	if os.IsNotExist(err) {
+		return dir, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("워크스페이스 경로 확인 실패: %v", err)
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		return "", errs.New(errs.ErrInvalid, "워크스페이스 경로가 심볼릭 링크입니다: %s", dir)
+	}
+	if !info.IsDir() {
+		return "", errs.New(errs.ErrInvalid, "워크스페이스 경로가 디렉토리가 아닙니다: %s", dir)
+	}
+
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", fmt.Errorf("워크스페이스 루트 경로 확인 실패: %v", err)
+	}
+	realDir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return "", fmt.Errorf("워크스페이스 경로 확인 실패: %v", err)
+	}
+	if !withinRoot(realRoot, realDir) {
+		return "", errs.New(errs.ErrInvalid, "워크스페이스 실제 경로가 루트를 벗어납니다: %s", realDir)
+	}
+
+	return dir, nil
+}
+
+// workspacePresent 워크스페이스에 이미 내용이 있는지
+// 볼륨 백엔드는 resolveWorkspaceDir가 빈 볼륨을 만들어 두므로 디렉토리가 비어 있으면 없는 것으로 봄
+func (s *Service) workspacePresent(dir string) bool {
+	if s.dockerClient.WorkspaceVolumes() == nil {
+		_, err := os.Lstat(dir)
+		return err == nil
+	}
+	entries, err := os.ReadDir(dir)
+	return err == nil && len(entries) > 0
+}
+
+// removeWorkspace 사용자 워크스페이스 삭제 (볼륨 백엔드는 볼륨째 삭제)
+func (s *Service) removeWorkspace(userID string) error {
+	if s.dockerClient.WorkspaceVolumes() != nil {
+		err := s.dockerClient.RemoveWorkspaceVolume(userID)
+		if errors.Is(err, errs.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	dir, err := s.resolveWorkspaceDir(userID)
+	if err != nil {
+		return err
+	}
+	if err := s.releaseWorkspaceQuota(userID, dir); err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
+
+// workspaceRef 세션 메타데이터에 남길 워크스페이스 위치 (볼륨 백엔드는 볼륨 이름)
+func (s *Service) workspaceRef(userID, dir string) string {
+	if s.dockerClient.WorkspaceVolumes() != nil {
+		return s.dockerClient.Naming().WorkspaceVolumeName(userID)
+	}
+	return dir
+}
+
+// withinRoot path가 root 하위(자기 자신 제외)에 있는지 확인
+func withinRoot(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) && !filepath.IsAbs(rel)
+}
+
+// WorkspaceVolumeStatus 워크스페이스 볼륨과 그 볼륨을 쓰는 활성 세션
+type WorkspaceVolumeStatus struct {
+	*docker.WorkspaceVolume
+	SessionID string `json:"session_id,omitempty"`
+}
+
+// ListWorkspaceVolumes 이 배포의 워크스페이스 볼륨 (볼륨 백엔드가 아니면 ErrInvalid)
+func (s *Service) ListWorkspaceVolumes() ([]*WorkspaceVolumeStatus, error) {
+	if s.dockerClient.WorkspaceVolumes() == nil {
+		return nil, errs.New(errs.ErrInvalid, "이 배포는 워크스페이스를 bind 마운트로 제공합니다")
+	}
+	volumes, err := s.dockerClient.ListWorkspaceVolumes()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*WorkspaceVolumeStatus, 0, len(volumes))
+	for _, vol := range volumes {
+		status := &WorkspaceVolumeStatus{WorkspaceVolume: vol}
+		if session, err := s.store.GetSessionByUserID(vol.UserID); err == nil {
+			status.SessionID = session.ID
+		}
+		result = append(result, status)
+	}
+	return result, nil
+}
+
+// DeleteWorkspaceVolume 세션이 없는 사용자의 워크스페이스 볼륨 삭제 (보관 예약도 함께 취소)
+func (s *Service) DeleteWorkspaceVolume(userID string) error {
+	if s.dockerClient.WorkspaceVolumes() == nil {
+		return errs.New(errs.ErrInvalid, "이 배포는 워크스페이스를 bind 마운트로 제공합니다")
+	}
+	if err := validateUserID(userID); err != nil {
+		return err
+	}
+	return s.locker.WithLock("workspace-lifecycle:"+userID, func() error {
+		if session, err := s.store.GetSessionByUserID(userID); err == nil {
+			return errs.New(errs.ErrConflict, "사용자 %s의 세션 %s가 워크스페이스를 사용 중입니다", userID, session.ID)
+		}
+		if err := s.dockerClient.RemoveWorkspaceVolume(userID); err != nil {
+			return err
+		}
+		if lc, err := s.store.GetWorkspaceLifecycle(userID); err == nil && lc.ArchivedAt == nil {
+			s.store.DeleteWorkspaceLifecycle(userID)
+		}
+		s.audit("admin", "workspace.volume_deleted", userID, "volume="+s.dockerClient.Naming().WorkspaceVolumeName(userID))
+		return nil
+	})
+}
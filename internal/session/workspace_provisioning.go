@@ -0,0 +1,47 @@
+package session
+
+import (
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/workspace"
+)
+
+// provisionWorkspace 세션 컨테이너가 워크스페이스를 마운트하기 전에 workspace_provisioning 설정대로 준비
+// workspaceDir은 호스트에서 접근할 수 있는 경로 (볼륨을 호스트에서 읽을 수 없으면 "")
+func (s *Service) provisionWorkspace(userID, workspaceDir string, cfg *config.Config) error {
+	settings := cfg.WorkspaceProvisioning
+	spec := workspace.Spec{
+		UserID:      userID,
+		HostDir:     workspaceDir,
+		UID:         workspaceUID,
+		GID:         workspaceGID,
+		TemplateDir: settings.TemplateDir,
+		Repos:       settings.GitRepos,
+		Datasets:    settings.Datasets,
+	}
+	if s.dockerClient.WorkspaceVolumes() != nil {
+		vol, err := s.dockerClient.EnsureWorkspaceVolume(userID)
+		if err != nil {
+			return err
+		}
+		spec.Volume = vol.Name
+	}
+
+	var provisioner workspace.Provisioner = workspace.HostProvisioner{}
+	if settings.Mode == workspace.ModeInitContainer {
+		provisioner = workspace.NewContainerProvisioner(s.dockerClient, settings.InitImage)
+	} else if workspaceDir == "" {
+		// 호스트에서 읽을 수 없는 볼륨은 컨테이너가 빈 볼륨을 그대로 마운트
+		logging.Debugf("워크스페이스 준비 건너뜀 (호스트에서 읽을 수 없는 볼륨, init-container 방식 필요): %s", userID)
+		return nil
+	}
+
+	_, err := workspace.Run(provisioner, spec, workspace.Policy{
+		Timeout: time.Duration(settings.TimeoutSeconds) * time.Second,
+		Retries: settings.Retries,
+		Backoff: time.Duration(settings.RetryBackoffSeconds) * time.Second,
+	})
+	return err
+}
@@ -0,0 +1,100 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+	"github.com/sandman/gpu-ssh-gateway/internal/workspace"
+)
+
+// metaWorkspaceQuota 세션 생성 때 워크스페이스에 건 한도 (GB)
+const metaWorkspaceQuota = "workspace_quota_gb"
+
+// workspaceQuota 설정된 쿼터 백엔드 (꺼져 있거나 볼륨 백엔드면 nil)
+func (s *Service) workspaceQuota(cfg *config.Config) workspace.Quota {
+	if !cfg.WorkspaceQuota.Enabled() || s.dockerClient.WorkspaceVolumes() != nil {
+		return nil
+	}
+	switch cfg.WorkspaceQuota.Mode {
+	case workspace.QuotaXFS:
+		return &workspace.XFSQuota{MountPoint: cfg.WorkspaceQuota.MountPoint, ProjectIDBase: cfg.WorkspaceQuota.ProjectIDBase, Projects: s.store}
+	case workspace.QuotaLoopback:
+		return &workspace.LoopbackQuota{ImageDir: cfg.WorkspaceQuota.ImageDir}
+	}
+	return nil
+}
+
+// resolveWorkspaceQuota 요청의 workspace_quota_gb를 검증하고 적용할 한도를 정함 (0이면 쿼터 없음)
+func (s *Service) resolveWorkspaceQuota(req CreateRequest, cfg *config.Config) (int, error) {
+	settings := cfg.WorkspaceQuota
+	if req.WorkspaceQuotaGB < 0 {
+		return 0, errs.New(errs.ErrInvalid, "workspace_quota_gb는 0 이상이어야 합니다")
+	}
+	if s.workspaceQuota(cfg) == nil {
+		if req.WorkspaceQuotaGB > 0 {
+			return 0, errs.New(errs.ErrInvalid, "이 배포는 워크스페이스 쿼터를 지원하지 않습니다 (workspace_quota.mode 미설정 또는 볼륨 백엔드)")
+		}
+		return 0, nil
+	}
+	if req.WorkspaceQuotaGB > settings.MaxGB {
+		return 0, errs.New(errs.ErrInvalid, "workspace_quota_gb %d가 최대 %dGB를 넘습니다", req.WorkspaceQuotaGB, settings.MaxGB)
+	}
+	if req.WorkspaceQuotaGB > 0 {
+		return req.WorkspaceQuotaGB, nil
+	}
+	return settings.DefaultGB, nil
+}
+
+// applyWorkspaceQuota 컨테이너가 워크스페이스를 마운트하기 전에 한도를 걺 (준비 단계의 파일도 한도에 포함)
+func (s *Service) applyWorkspaceQuota(userID, workspaceDir string, quotaGB int, cfg *config.Config) error {
+	if quotaGB == 0 {
+		return nil
+	}
+	if workspaceDir == "" {
+		return errWorkspaceNotOnHost
+	}
+	return workspace.ApplyQuota(s.workspaceQuota(cfg), workspace.QuotaTarget{
+		UserID:     userID,
+		Dir:        workspaceDir,
+		LimitBytes: int64(quotaGB) << 30,
+		UID:        workspaceUID,
+		GID:        workspaceGID,
+	})
+}
+
+// releaseWorkspaceQuota 워크스페이스를 지우기 전에 쿼터를 거둠 (loopback은 마운트를 풀어야 디렉토리를 지울 수 있음)
+func (s *Service) releaseWorkspaceQuota(userID, workspaceDir string) error {
+	backend := s.workspaceQuota(s.config.Current())
+	if backend == nil {
+		return nil
+	}
+	if err := backend.Release(context.Background(), userID, workspaceDir); err != nil {
+		return fmt.Errorf("워크스페이스 쿼터 해제 실패 (%s): %w", backend.Name(), err)
+	}
+	return nil
+}
+
+// workspaceUsage 쿼터를 건 로컬 세션의 워크스페이스 사용량 (조회 실패나 다른 노드 세션은 nil)
+func (s *Service) workspaceUsage(session *store.Session) *workspace.Usage {
+	if _, err := strconv.Atoi(session.Metadata[metaWorkspaceQuota]); err != nil {
+		return nil
+	}
+	if session.NodeID != "" && session.NodeID != s.nodeID {
+		return nil
+	}
+	backend := s.workspaceQuota(s.config.Current())
+	if backend == nil {
+		return nil
+	}
+	usage, err := backend.Usage(session.Metadata["workspace"])
+	if err != nil {
+		logging.Debugf("세션 %s 워크스페이스 사용량 조회 실패: %v", session.ID, err)
+		return nil
+	}
+	return usage
+}
@@ -0,0 +1,225 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/config"
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/snapshot"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+)
+
+// workspaceArchiveDirName 워크스페이스 루트 아래 보관본 위치 (사용자 ID는 '.'으로 시작할 수 없어 겹치지 않음)
+const workspaceArchiveDirName = ".workspace-archives"
+
+var workspaceLifecycleTotal = metrics.NewCounter("sandman_workspace_lifecycle_total", "세션 종료 후 워크스페이스 처리 결과", "action", "result")
+
+// workspacePlan 세션 종료 시 정한 워크스페이스 처리 (최종 사용량 보고에 함께 기록)
+type workspacePlan struct {
+	Policy    string
+	ArchiveAt *time.Time
+}
+
+// workspaceRetentionFor 사용자 설정 > 이미지(템플릿) 설정 > "default" > 보존 순으로 결정
+func (s *Service) workspaceRetentionFor(userID, image string, cfg *config.Config) config.WorkspaceRetention {
+	if settings, err := s.store.GetUserSettings(userID); err == nil && settings.WorkspacePolicy != "" {
+		return config.WorkspaceRetention{Policy: settings.WorkspacePolicy, Days: settings.WorkspaceRetentionDays}
+	}
+	return cfg.WorkspaceRetentionFor(image)
+}
+
+// planWorkspace 끝나는 세션의 워크스페이스 처리 결정
+func (s *Service) planWorkspace(session *store.Session, endedAt time.Time) workspacePlan {
+	image := session.Metadata["template"]
+	if image == "" {
+		image = docker.DefaultImage
+	}
+	retention := s.workspaceRetentionFor(session.UserID, image, s.config.Current())
+
+	plan := workspacePlan{Policy: retention.Policy}
+	if volumes := s.dockerClient.WorkspaceVolumes(); retention.Policy == config.WorkspaceArchive && volumes != nil && !volumes.HostReadable() {
+		// 호스트에서 볼륨 내용을 읽을 수 없으면 보관본을 만들 수 없으므로 그대로 둠
+		log.Printf("⚠️ 워크스페이스 볼륨을 호스트에서 읽을 수 없어 보관 대신 유지합니다: 사용자 %s", session.UserID)
+		plan.Policy = config.WorkspaceKeep
+	}
+	if plan.Policy == config.WorkspaceArchive {
+		archiveAt := endedAt.Add(time.Duration(retention.Days) * 24 * time.Hour)
+		plan.ArchiveAt = &archiveAt
+	}
+	return plan
+}
+
+// applyWorkspacePlan 컨테이너를 내린 뒤 워크스페이스 처리 (delete는 즉시 삭제, archive는 수명 주기 작업에 예약)
+func (s *Service) applyWorkspacePlan(session *store.Session, plan workspacePlan, endedAt time.Time) {
+	switch plan.Policy {
+	case config.WorkspaceDelete:
+		err := s.locker.WithLock("workspace-lifecycle:"+session.UserID, func() error {
+			return s.removeWorkspace(session.UserID)
+		})
+		if err != nil {
+			workspaceLifecycleTotal.Inc("delete", "error")
+			log.Printf("⚠️ 워크스페이스 삭제 실패 (사용자: %s): %v", session.UserID, err)
+			return
+		}
+		workspaceLifecycleTotal.Inc("delete", "done")
+		log.Printf("🗑️ 워크스페이스 삭제: 사용자 %s (세션 %s 종료)", session.UserID, session.ID)
+		s.audit("system", "workspace.deleted", session.UserID, "session="+session.ID)
+
+	case config.WorkspaceArchive:
+		lc := &store.WorkspaceLifecycle{
+			UserID:    session.UserID,
+			SessionID: session.ID,
+			EndedAt:   endedAt,
+			ArchiveAt: *plan.ArchiveAt,
+		}
+		if err := s.store.SaveWorkspaceLifecycle(lc); err != nil {
+			log.Printf("⚠️ 워크스페이스 보관 예약 실패 (사용자: %s): %v", session.UserID, err)
+			return
+		}
+		log.Printf("🗄️ 워크스페이스 보관 예약: 사용자 %s → %s", session.UserID, lc.ArchiveAt.Format(time.RFC3339))
+		s.audit("system", "workspace.archive_scheduled", session.UserID, "archive_at="+lc.ArchiveAt.Format(time.RFC3339))
+	}
+}
+
+// reviveWorkspace 새 세션을 시작하기 전에 보관 예약을 취소하고, 이미 보관된 워크스페이스는 다시 풀어 놓음
+func (s *Service) reviveWorkspace(userID, workspaceDir string) error {
+	return s.locker.WithLock("workspace-lifecycle:"+userID, func() error {
+		lc, err := s.store.GetWorkspaceLifecycle(userID)
+		if errors.Is(err, errs.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if lc.ArchivedAt == nil {
+			log.Printf("🗄️ 새 세션 시작으로 워크스페이스 보관 예약 취소: 사용자 %s", userID)
+			return s.store.DeleteWorkspaceLifecycle(userID)
+		}
+
+		if workspaceDir == "" {
+			return errWorkspaceNotOnHost
+		}
+		// 보관 후 사용자가 워크스페이스를 다시 만들었으면 덮어쓰지 않고 보관본을 남겨 둠
+		if s.workspacePresent(workspaceDir) {
+			log.Printf("⚠️ 워크스페이스가 이미 있어 보관본을 복원하지 않습니다: %s (보관본: %s)", workspaceDir, lc.ArchivePath)
+			return nil
+		}
+
+		start := time.Now()
+		stats, err := snapshot.Unarchive(lc.ArchivePath, workspaceDir)
+		if err != nil {
+			s.removeWorkspace(userID)
+			workspaceLifecycleTotal.Inc("restore", "error")
+			return fmt.Errorf("보관된 워크스페이스 복원 실패: %v", err)
+		}
+		if err := os.Remove(lc.ArchivePath); err != nil {
+			log.Printf("⚠️ 복원한 보관본 삭제 실패: %v", err)
+		}
+		workspaceLifecycleTotal.Inc("restore", "done")
+		log.Printf("🗄️ 보관된 워크스페이스 복원: 사용자 %s (파일: %d개, 소요: %v)", userID, stats.Files, time.Since(start).Round(time.Millisecond))
+		s.audit("system", "workspace.restored", userID, fmt.Sprintf("files=%d bytes=%d", stats.Files, stats.Bytes))
+		return s.store.DeleteWorkspaceLifecycle(userID)
+	})
+}
+
+// EnforceWorkspaceRetention 보관 시각이 지난 워크스페이스를 tar.gz로 묶고 디렉토리를 삭제 (워크스페이스 수명 주기 작업)
+func (s *Service) EnforceWorkspaceRetention() error {
+	// 같은 워크스페이스를 여러 복제본이 동시에 보관하지 않도록 한 곳에서만 실행
+	lease, err := s.locker.TryAcquire("workspace-lifecycle")
+	if err != nil {
+		return err
+	}
+	if lease == nil {
+		logging.Debugf("다른 복제본이 워크스페이스 수명 주기 작업 중 - 건너뜀")
+		return nil
+	}
+	defer lease.Release()
+
+	entries, err := s.store.ListWorkspaceLifecycle()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, lc := range entries {
+		if lc.ArchivedAt != nil || lc.ArchiveAt.After(now) {
+			continue
+		}
+		if err := s.archiveWorkspace(lc.UserID); err != nil {
+			workspaceLifecycleTotal.Inc("archive", "error")
+			log.Printf("⚠️ 워크스페이스 보관 실패 (사용자: %s): %v", lc.UserID, err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) archiveWorkspace(userID string) error {
+	return s.locker.WithLock("workspace-lifecycle:"+userID, func() error {
+		// 락을 기다리는 동안 새 세션이 예약을 취소했을 수 있음
+		lc, err := s.store.GetWorkspaceLifecycle(userID)
+		if errors.Is(err, errs.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if lc.ArchivedAt != nil {
+			return nil
+		}
+		if _, err := s.store.GetSessionByUserID(userID); err == nil {
+			return s.store.DeleteWorkspaceLifecycle(userID)
+		}
+
+		workspaceDir, err := s.resolveWorkspaceDir(userID)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		if !s.workspacePresent(workspaceDir) {
+			// 관리자가 이미 지운 경우 보관할 것이 없음 (볼륨 백엔드는 조회하며 만든 빈 볼륨도 정리)
+			log.Printf("🗄️ 보관할 워크스페이스가 없습니다: 사용자 %s", userID)
+			s.removeWorkspace(userID)
+			return s.store.DeleteWorkspaceLifecycle(userID)
+		}
+
+		root, err := filepath.Abs(s.workspaceRoot)
+		if err != nil {
+			return fmt.Errorf("워크스페이스 루트 경로 확인 실패: %v", err)
+		}
+		dst := filepath.Join(root, workspaceArchiveDirName, userID, now.UTC().Format("20060102T150405Z")+".tar.gz")
+
+		start := time.Now()
+		stats, err := snapshot.Archive(workspaceDir, dst)
+		if err != nil {
+			return err
+		}
+		lc.ArchivedAt = &now
+		lc.ArchivePath = dst
+		if err := s.store.SaveWorkspaceLifecycle(lc); err != nil {
+			os.Remove(dst)
+			return err
+		}
+		if err := s.removeWorkspace(userID); err != nil {
+			log.Printf("⚠️ 보관한 워크스페이스 삭제 실패: %v", err)
+		}
+
+		workspaceLifecycleTotal.Inc("archive", "done")
+		log.Printf("🗄️ 워크스페이스 보관: 사용자 %s → %s (파일: %d개, %d bytes, 소요: %v)",
+			userID, dst, stats.Files, stats.Bytes, time.Since(start).Round(time.Millisecond))
+		s.audit("system", "workspace.archived", userID, fmt.Sprintf("path=%s files=%d bytes=%d", dst, stats.Files, stats.Bytes))
+		return nil
+	})
+}
+
+// ListWorkspaceLifecycle 보관 예약 중이거나 보관된 워크스페이스
+func (s *Service) ListWorkspaceLifecycle() ([]*store.WorkspaceLifecycle, error) {
+	return s.store.ListWorkspaceLifecycle()
+}
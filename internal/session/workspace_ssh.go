@@ -0,0 +1,157 @@
+package session
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/store"
+	"golang.org/x/crypto/ssh"
+)
+
+const (
+	// workspaceSSHDir 워크스페이스 안의 .ssh (컨테이너의 /etc/ssh/ssh_config.d/sandman-workspace.conf가 config를 Include)
+	workspaceSSHDir = ".ssh"
+	workspaceSSHKey = "id_sandman"
+	// workspaceKeyTag 워크스페이스 키를 authorized_keys에서 식별하는 주석
+	workspaceKeyTag = "sandman-workspace"
+	// workspaceUID 세션 컨테이너 사용자 UID/GID (Dockerfile.gpu-workspace의 UID, GID 빌드 인자)
+	workspaceUID = 1001
+	workspaceGID = 1001
+)
+
+// workspaceSSHFiles 오케스트레이터가 관리하는 파일 (known_hosts 등 사용자가 만든 파일은 건드리지 않음)
+var workspaceSSHFiles = []string{workspaceSSHKey, workspaceSSHKey + ".pub", "config"}
+
+// provisionWorkspaceSSH 워크스페이스에 세션 전용 키와 ssh_config를 만들고, 그 키로 세션 컨테이너에 접속할 수 있게 등록
+// 에이전트 포워딩 없이 세션 안에서 ssh/git으로 자기 세션(sandman 호스트)에 접속하거나
+// 공개키를 등록한 사내 git 서버에 접근할 수 있게 함
+func (s *Service) provisionWorkspaceSSH(session *store.Session) error {
+	cfg := s.config.Current()
+	if !cfg.WorkspaceSSH.Enabled {
+		return nil
+	}
+
+	workspaceDir, err := s.resolveWorkspaceDir(session.UserID)
+	if errors.Is(err, errWorkspaceNotOnHost) {
+		logging.Debugf("세션 %s 워크스페이스를 호스트에서 읽을 수 없어 .ssh 구성을 건너뜀", session.ID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	dir := filepath.Join(workspaceDir, workspaceSSHDir)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	publicKey, privateKey, err := generateWorkspaceKey(fmt.Sprintf("%s@sandman-%s", session.UserID, session.ID[:8]))
+	if err != nil {
+		return fmt.Errorf("워크스페이스 SSH 키 생성 실패: %v", err)
+	}
+
+	files := map[string]struct {
+		content string
+		mode    os.FileMode
+	}{
+		workspaceSSHKey:          {privateKey, 0600},
+		workspaceSSHKey + ".pub": {publicKey, 0644},
+		"config":                 {workspaceSSHConfig(session, cfg.WorkspaceSSH.ExtraConfig), 0644},
+	}
+	for name, file := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(file.content), file.mode); err != nil {
+			return err
+		}
+		// 기존 파일이면 WriteFile이 권한을 바꾸지 않으므로 다시 지정 (ssh는 권한이 넓은 키를 거부)
+		if err := os.Chmod(path, file.mode); err != nil {
+			return err
+		}
+	}
+
+	// 컨테이너 사용자가 읽을 수 있도록 소유자 변경 (ssh는 다른 사용자 소유의 키와 설정을 거부)
+	for _, path := range append([]string{dir}, managedPaths(dir)...) {
+		if err := os.Lchown(path, workspaceUID, workspaceGID); err != nil {
+			return fmt.Errorf("워크스페이스 .ssh 소유자 변경 실패: %v", err)
+		}
+	}
+
+	// 재시작/초기화로 컨테이너가 바뀌어도 키가 하나만 남도록 이전 줄을 지우고 추가
+	if err := s.dockerClient.RemoveAuthorizedKey(session.ContainerID, session.UserID, workspaceKeyTag); err != nil {
+		return err
+	}
+	keyLine := strings.Join(strings.Fields(publicKey)[:2], " ")
+	if err := s.dockerClient.AppendAuthorizedKey(session.ContainerID, session.UserID, keyLine, workspaceKeyTag); err != nil {
+		return err
+	}
+
+	log.Printf("🔑 워크스페이스 .ssh 구성: %s (세션 %s)", dir, session.ID)
+	return nil
+}
+
+// removeWorkspaceSSH 세션이 끝나면 관리 파일을 지우고, 빈 .ssh 디렉토리도 삭제
+func (s *Service) removeWorkspaceSSH(session *store.Session) {
+	workspaceDir, err := s.resolveWorkspaceDir(session.UserID)
+	if err != nil {
+		return
+	}
+	dir := filepath.Join(workspaceDir, workspaceSSHDir)
+	for _, path := range managedPaths(dir) {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			log.Printf("⚠️ 워크스페이스 SSH 파일 삭제 실패: %s: %v", path, err)
+		}
+	}
+	os.Remove(dir) // 사용자 파일이 남아 있으면 실패하고 그대로 둠
+}
+
+func managedPaths(dir string) []string {
+	paths := make([]string, 0, len(workspaceSSHFiles))
+	for _, name := range workspaceSSHFiles {
+		paths = append(paths, filepath.Join(dir, name))
+	}
+	return paths
+}
+
+// workspaceSSHConfig sandman 호스트 별칭(이 세션 컨테이너)과 배포 공통 설정
+func workspaceSSHConfig(session *store.Session, extra string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# sandman이 세션 %s 생성 시 만든 파일입니다. 세션이 끝나면 삭제되고 다음 세션에서 다시 만들어집니다.\n\n", session.ID)
+	fmt.Fprintf(&b, "Host sandman\n")
+	fmt.Fprintf(&b, "    HostName %s\n", session.ContainerIP)
+	fmt.Fprintf(&b, "    User %s\n", session.UserID)
+	fmt.Fprintf(&b, "    StrictHostKeyChecking accept-new\n")
+	fmt.Fprintf(&b, "    UserKnownHostsFile /workspace/%s/known_hosts\n\n", workspaceSSHDir)
+	// ssh_config는 먼저 나온 값이 우선이므로 배포 설정을 Host * 앞에 둠
+	if extra = strings.TrimSpace(extra); extra != "" {
+		fmt.Fprintf(&b, "%s\n\n", extra)
+	}
+	fmt.Fprintf(&b, "Host *\n")
+	fmt.Fprintf(&b, "    IdentityFile /workspace/%s/%s\n", workspaceSSHDir, workspaceSSHKey)
+	fmt.Fprintf(&b, "    IdentityFile ~/.ssh/id_rsa\n")
+	fmt.Fprintf(&b, "    IdentityFile ~/.ssh/id_ed25519\n")
+	return b.String()
+}
+
+// generateWorkspaceKey OpenSSH가 바로 읽을 수 있는 RSA 키 쌍 (공개키는 authorized_keys 형식)
+func generateWorkspaceKey(comment string) (string, string, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 3072)
+	if err != nil {
+		return "", "", err
+	}
+	privateKeyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)})
+
+	pub, err := ssh.NewPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return "", "", err
+	}
+	publicKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub))) + " " + comment + "\n"
+	return publicKey, string(privateKeyPEM), nil
+}
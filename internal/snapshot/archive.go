@@ -0,0 +1,226 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Archive src 트리를 tar.gz 파일 하나로 묶음 (권한, 소유자, 수정 시각, 심볼릭 링크 유지)
+// 만드는 중에는 .partial 파일에 쓰고 완료 후 rename하므로 중간에 실패해도 깨진 보관본이 남지 않음
+func Archive(src, dst string) (*Stats, error) {
+	if _, err := os.Stat(dst); err == nil {
+		return nil, fmt.Errorf("보관 파일이 이미 존재합니다: %s", dst)
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return nil, err
+	}
+
+	partial := dst + partialSuffix
+	stats, err := writeArchive(src, partial)
+	if err != nil {
+		os.Remove(partial)
+		return nil, err
+	}
+	if err := os.Rename(partial, dst); err != nil {
+		os.Remove(partial)
+		return nil, err
+	}
+	return stats, nil
+}
+
+func writeArchive(src, dst string) (*Stats, error) {
+	file, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	stats := &Stats{}
+
+	err = filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		info, err := os.Lstat(path)
+		if err != nil {
+			return err
+		}
+
+		link := ""
+		switch mode := info.Mode(); {
+		case mode.IsDir(), mode.IsRegular():
+		case mode&os.ModeSymlink != 0:
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		default:
+			// 소켓, FIFO, 장치 파일은 보관할 의미가 없어 건너뜀
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		n, err := io.Copy(tw, f)
+		if err != nil {
+			return err
+		}
+		stats.Files++
+		stats.Copied++
+		stats.Bytes += n
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return stats, file.Sync()
+}
+
+// Unarchive Archive로 만든 보관 파일을 dst에 풀어 놓음 (dst는 비어 있거나 없어야 함)
+// dst 밖을 가리키는 경로는 거부
+func Unarchive(archive, dst string) (*Stats, error) {
+	file, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("보관 파일 읽기 실패: %v", err)
+	}
+	defer gz.Close()
+
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return nil, err
+	}
+
+	type dirTime struct {
+		path  string
+		mtime time.Time
+	}
+	var dirs []dirTime
+	stats := &Stats{}
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("보관 파일 읽기 실패: %v", err)
+		}
+
+		name := filepath.Clean(filepath.FromSlash(header.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("보관 파일에 잘못된 경로가 있습니다: %s", header.Name)
+		}
+		// 보관본 안의 심볼릭 링크를 통해 dst 밖에 쓰지 않도록 상위 경로에 링크가 있으면 거부
+		if err := checkNoSymlinkParent(dst, name); err != nil {
+			return nil, err
+		}
+		target := filepath.Join(dst, name)
+		mode := os.FileMode(header.Mode).Perm()
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if info, err := os.Lstat(target); err == nil && !info.IsDir() {
+				return nil, fmt.Errorf("보관 파일의 디렉토리가 다른 항목과 겹칩니다: %s", name)
+			}
+			if err := os.MkdirAll(target, mode); err != nil {
+				return nil, err
+			}
+			os.Chmod(target, mode)
+			dirs = append(dirs, dirTime{target, header.ModTime})
+
+		case tar.TypeSymlink:
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return nil, err
+			}
+
+		case tar.TypeReg:
+			f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+			if err != nil {
+				return nil, err
+			}
+			n, err := io.Copy(f, tr)
+			f.Close()
+			if err != nil {
+				return nil, err
+			}
+			os.Chtimes(target, header.ModTime, header.ModTime)
+			stats.Files++
+			stats.Copied++
+			stats.Bytes += n
+
+		default:
+			continue
+		}
+		os.Lchown(target, header.Uid, header.Gid)
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		os.Chtimes(dirs[i].path, dirs[i].mtime, dirs[i].mtime)
+	}
+	return stats, nil
+}
+
+// checkNoSymlinkParent dst 아래 name의 상위 디렉토리 중 심볼릭 링크가 있으면 오류
+func checkNoSymlinkParent(dst, name string) error {
+	dir := dst
+	for _, part := range strings.Split(filepath.Dir(name), string(filepath.Separator)) {
+		if part == "." {
+			continue
+		}
+		dir = filepath.Join(dir, part)
+		info, err := os.Lstat(dir)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("보관 파일에 심볼릭 링크를 거치는 경로가 있습니다: %s", name)
+		}
+	}
+	return nil
+}
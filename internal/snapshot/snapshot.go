@@ -0,0 +1,270 @@
+package snapshot
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+)
+
+// partialSuffix 만드는 중인 스냅샷 디렉토리 접미사 (완료 후 rename으로 원자적 공개)
+const partialSuffix = ".partial"
+
+// Stats 스냅샷/복원 결과
+type Stats struct {
+	Files  int   `json:"files"`
+	Linked int   `json:"linked"` // 이전 스냅샷과 같아서 하드 링크로 공유한 파일 수
+	Copied int   `json:"copied"`
+	Bytes  int64 `json:"bytes"` // 스냅샷이 가리키는 전체 파일 크기 (공유분 포함)
+}
+
+// Create src 트리를 dst에 스냅샷으로 복사
+// prev(직전 스냅샷)에 크기, 수정 시각, 권한이 같은 파일이 있으면 복사 대신 하드 링크로 공유해 공간을 아낌
+// 원본 작업 공간의 파일은 절대 링크하지 않으므로 이후 원본을 수정해도 스냅샷은 바뀌지 않음
+func Create(src, dst, prev string) (*Stats, error) {
+	if _, err := os.Stat(dst); err == nil {
+		return nil, fmt.Errorf("스냅샷 경로가 이미 존재합니다: %s", dst)
+	}
+
+	partial := dst + partialSuffix
+	if err := os.RemoveAll(partial); err != nil {
+		return nil, err
+	}
+
+	stats, err := copyTree(src, partial, prev)
+	if err != nil {
+		os.RemoveAll(partial)
+		return nil, err
+	}
+	if err := os.Rename(partial, dst); err != nil {
+		os.RemoveAll(partial)
+		return nil, err
+	}
+	return stats, nil
+}
+
+// Restore 스냅샷 내용으로 dst를 교체 (dst 디렉토리 자체와 소유자는 유지)
+// 복원된 파일은 스냅샷과 링크를 공유하지 않도록 모두 복사
+func Restore(snapshotDir, dst string) (*Stats, error) {
+	if err := clearDir(dst); err != nil {
+		return nil, fmt.Errorf("워크스페이스 비우기 실패: %v", err)
+	}
+	return copyTree(snapshotDir, dst, "")
+}
+
+// Remove 스냅샷 디렉토리 삭제 (다른 스냅샷과 공유한 파일은 링크 수만 줄어듦)
+func Remove(dir string) error {
+	return os.RemoveAll(dir)
+}
+
+func copyTree(src, dst, prev string) (*Stats, error) {
+	stats := &Stats{}
+
+	// 디렉토리 수정 시각은 안의 항목을 모두 만든 뒤에 맞춰야 유지됨
+	type dirTime struct {
+		path  string
+		mtime time.Time
+	}
+	var dirs []dirTime
+
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		info, err := os.Lstat(path)
+		if err != nil {
+			return err
+		}
+
+		switch mode := info.Mode(); {
+		case mode.IsDir():
+			if err := os.MkdirAll(target, mode.Perm()); err != nil {
+				return err
+			}
+			os.Chmod(target, mode.Perm())
+			copyOwner(target, info)
+			dirs = append(dirs, dirTime{target, info.ModTime()})
+
+		case mode&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if err := os.Symlink(link, target); err != nil {
+				return err
+			}
+			copyOwner(target, info)
+
+		case mode.IsRegular():
+			stats.Files++
+			stats.Bytes += info.Size()
+			if prev != "" && linkUnchanged(filepath.Join(prev, rel), target, info) {
+				stats.Linked++
+				return nil
+			}
+			if err := copyFile(path, target, info); err != nil {
+				return err
+			}
+			stats.Copied++
+
+		default:
+			// 소켓, FIFO, 장치 파일은 복원할 의미가 없어 건너뜀
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		os.Chtimes(dirs[i].path, dirs[i].mtime, dirs[i].mtime)
+	}
+	return stats, nil
+}
+
+// linkUnchanged 직전 스냅샷의 같은 파일이 변경되지 않았으면 하드 링크 생성
+func linkUnchanged(prevPath, target string, info os.FileInfo) bool {
+	prevInfo, err := os.Lstat(prevPath)
+	if err != nil || !prevInfo.Mode().IsRegular() {
+		return false
+	}
+	if !sameContent(prevInfo, info) || prevInfo.Mode().Perm() != info.Mode().Perm() {
+		return false
+	}
+	return os.Link(prevPath, target) == nil
+}
+
+func sameContent(a, b os.FileInfo) bool {
+	return a.Size() == b.Size() && a.ModTime().Equal(b.ModTime())
+}
+
+func copyFile(src, dst string, info os.FileInfo) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	os.Chmod(dst, info.Mode().Perm())
+	copyOwner(dst, info)
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
+
+// copyOwner 원본 소유자 유지 (root가 아니어서 실패하면 현재 사용자 소유로 남김)
+func copyOwner(path string, info os.FileInfo) {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		os.Lchown(path, int(st.Uid), int(st.Gid))
+	}
+}
+
+func clearDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maxDiffEntries 변경 목록 하나에 담는 최대 경로 수 (큰 작업 공간에서 응답이 과도하게 커지지 않도록)
+const maxDiffEntries = 1000
+
+// Diff 두 트리의 파일 단위 차이 (크기/수정 시각/종류 비교, 경로는 트리 기준 상대 경로)
+type Diff struct {
+	Added     []string `json:"added"`
+	Removed   []string `json:"removed"`
+	Modified  []string `json:"modified"`
+	Truncated bool     `json:"truncated,omitempty"`
+}
+
+// Compare base(예: 복원 지점)에서 current(예: 현재 작업 공간)로 바뀐 내용 계산
+func Compare(base, current string) (*Diff, error) {
+	baseEntries, err := listTree(base)
+	if err != nil {
+		return nil, err
+	}
+	currentEntries, err := listTree(current)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := &Diff{Added: []string{}, Removed: []string{}, Modified: []string{}}
+	add := func(list *[]string, path string) {
+		if len(*list) >= maxDiffEntries {
+			diff.Truncated = true
+			return
+		}
+		*list = append(*list, path)
+	}
+
+	for path, info := range currentEntries {
+		old, ok := baseEntries[path]
+		switch {
+		case !ok:
+			add(&diff.Added, path)
+		case old.Mode().Type() != info.Mode().Type():
+			add(&diff.Modified, path)
+		case info.Mode().IsRegular() && !sameContent(old, info):
+			add(&diff.Modified, path)
+		}
+	}
+	for path := range baseEntries {
+		if _, ok := currentEntries[path]; !ok {
+			add(&diff.Removed, path)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Strings(diff.Modified)
+	return diff, nil
+}
+
+func listTree(root string) (map[string]os.FileInfo, error) {
+	entries := make(map[string]os.FileInfo)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		entries[rel] = info
+		return nil
+	})
+	return entries, err
+}
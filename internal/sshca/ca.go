@@ -0,0 +1,113 @@
+package sshca
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// clockSkew 컨테이너와 오케스트레이터 시계 차이를 고려해 유효 시작 시각을 앞당기는 여유
+const clockSkew = 1 * time.Minute
+
+// CA 세션 사용자 인증서에 서명하는 SSH 인증 기관
+type CA struct {
+	signer ssh.Signer
+}
+
+// LoadOrCreate path의 CA 개인키를 읽고, 없으면 새 ed25519 키를 만들어 저장
+func LoadOrCreate(path string) (*CA, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		data, err = generateKey(path)
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("🔏 새 SSH CA 키 생성: %s", path)
+	} else if err != nil {
+		return nil, fmt.Errorf("SSH CA 키 읽기 실패: %v", err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("SSH CA 키 파싱 실패: %v", err)
+	}
+
+	ca := &CA{signer: signer}
+	log.Printf("🔏 SSH CA 로드 완료: %s", ca.Fingerprint())
+	return ca, nil
+}
+
+func generateKey(path string) ([]byte, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("SSH CA 키 생성 실패: %v", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, fmt.Errorf("SSH CA 키 인코딩 실패: %v", err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("SSH CA 키 디렉토리 생성 실패: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return nil, fmt.Errorf("SSH CA 키 저장 실패: %v", err)
+	}
+	return data, nil
+}
+
+// PublicKey 컨테이너 sshd의 TrustedUserCAKeys에 넣을 공개키 한 줄
+func (ca *CA) PublicKey() string {
+	return strings.TrimSpace(string(ssh.MarshalAuthorizedKey(ca.signer.PublicKey())))
+}
+
+func (ca *CA) Fingerprint() string {
+	return ssh.FingerprintSHA256(ca.signer.PublicKey())
+}
+
+// SessionPrincipal 세션 단위 인증서 principal (같은 사용자의 이전 세션 인증서로는 접속 불가)
+func SessionPrincipal(sessionID string) string {
+	return "sandman-session-" + sessionID
+}
+
+// Sign 사용자 공개키에 principal로 제한된 단기 인증서를 서명
+func (ca *CA) Sign(pub ssh.PublicKey, serial uint64, keyID string, principals []string, validBefore time.Time) (*ssh.Certificate, error) {
+	now := time.Now()
+	if !validBefore.After(now) {
+		return nil, fmt.Errorf("인증서 만료 시각이 이미 지났습니다")
+	}
+
+	cert := &ssh.Certificate{
+		Key:             pub,
+		Serial:          serial,
+		CertType:        ssh.UserCert,
+		KeyId:           keyID,
+		ValidPrincipals: principals,
+		ValidAfter:      uint64(now.Add(-clockSkew).Unix()),
+		ValidBefore:     uint64(validBefore.Unix()),
+		Permissions: ssh.Permissions{
+			Extensions: map[string]string{
+				"permit-pty":              "",
+				"permit-port-forwarding":  "",
+				"permit-agent-forwarding": "",
+				"permit-X11-forwarding":   "",
+				"permit-user-rc":          "",
+			},
+		},
+	}
+	if err := cert.SignCert(rand.Reader, ca.signer); err != nil {
+		return nil, fmt.Errorf("인증서 서명 실패: %v", err)
+	}
+	return cert, nil
+}
@@ -0,0 +1,36 @@
+package sshpiper
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// keyDir 세션 개인키를 두는 디렉토리 (설정 파일 옆 keys/, sshpiperd가 읽을 수 있어야 함)
+func (r *Router) keyDir() string {
+	return filepath.Join(filepath.Dir(r.path), "keys")
+}
+
+// KeyPath 세션의 대상 로그인용 개인키 파일 경로
+func (r *Router) KeyPath(sessionID string) string {
+	return filepath.Join(r.keyDir(), sessionID)
+}
+
+// WriteKey 세션 개인키를 0600 파일로 원자적으로 저장 (키 교체 시 같은 경로를 덮어씀)
+func (r *Router) WriteKey(sessionID, privateKey string) error {
+	if err := os.MkdirAll(r.keyDir(), 0700); err != nil {
+		return fmt.Errorf("SSHPiper 키 디렉토리 생성 실패: %v", err)
+	}
+	if err := writeAtomic(r.KeyPath(sessionID), []byte(privateKey)); err != nil {
+		return fmt.Errorf("SSHPiper 세션 키 저장 실패: %v", err)
+	}
+	return nil
+}
+
+// RemoveKey 세션 개인키 파일 삭제 (없으면 무시)
+func (r *Router) RemoveKey(sessionID string) error {
+	if err := os.Remove(r.KeyPath(sessionID)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("SSHPiper 세션 키 삭제 실패: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,436 @@
+// Package sshpiper SSHPiper yaml 플러그인 설정 파일로 사용자 → 세션 컨테이너 라우트를 게시
+//
+// yaml 플러그인은 새 연결마다 설정 파일을 다시 읽으므로, 파일을 원자적으로 교체하면 sshpiperd를
+// 재시작하거나 시그널을 보내지 않아도 된다. 진행 중인 핸드셰이크는 이미 읽은 라우트로 계속된다.
+// 수업 시작처럼 변경이 몰릴 때는 요청을 큐에 모아 min_interval마다 한 번만 파일을 쓴다.
+package sshpiper
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+	"github.com/sandman/gpu-ssh-gateway/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// verifyAttempts 대상 SSH가 응답하지 않을 때 라우트를 다시 확인하는 횟수 (컨테이너 sshd 기동 대기)
+	verifyAttempts = 3
+	verifyTimeout  = 2 * time.Second
+)
+
+var (
+	routeUpdates       = metrics.NewCounter("sandman_sshpiper_route_updates_total", "SSHPiper 라우트 변경 처리 결과", "op", "outcome")
+	routeUpdateSeconds = metrics.NewHistogram("sandman_sshpiper_route_update_seconds", "라우트 변경 요청부터 설정 파일 반영까지 걸린 시간", nil)
+	routeWrites        = metrics.NewCounter("sandman_sshpiper_config_writes_total", "SSHPiper 설정 파일 쓰기 결과", "outcome")
+	routeBatchSize     = metrics.NewHistogram("sandman_sshpiper_route_batch_size", "한 번의 파일 쓰기에 묶인 라우트 변경 수",
+		[]float64{1, 2, 5, 10, 20, 50, 100, 200})
+	routeQueueLength = metrics.NewGauge("sandman_sshpiper_route_queue", "반영 대기 중인 라우트 변경 수")
+	routeCount       = metrics.NewGauge("sandman_sshpiper_routes", "게시된 라우트 수")
+)
+
+// Route SSH 사용자 이름으로 들어온 연결을 세션 컨테이너 sshd로 전달
+type Route struct {
+	User       string    `json:"user"`
+	Host       string    `json:"host"`
+	Port       int       `json:"port"`
+	TargetUser string    `json:"target_user"`
+	SessionID  string    `json:"session_id"`
+	CreatedAt  time.Time `json:"created_at"`
+
+	// Wake 대상이 일시 정지된 세션의 깨우기 리스너 (첫 바이트를 받아야 재개하므로 배너 확인 생략)
+	Wake bool `json:"wake,omitempty"`
+
+	// AuthorizedKeys 게이트웨이에서 받는 사용자 공개키 (비어 있으면 sshpiperd가 비밀번호를 그대로 전달)
+	AuthorizedKeys []string `json:"authorized_keys,omitempty"`
+	// PrivateKeyFile 대상 sshd에 로그인할 때 쓰는 세션 개인키 파일 (AuthorizedKeys와 함께 설정)
+	PrivateKeyFile string `json:"private_key_file,omitempty"`
+}
+
+func (r Route) target() string {
+	return net.JoinHostPort(r.Host, strconv.Itoa(r.Port))
+}
+
+// change 큐에 쌓인 변경 (같은 사용자의 변경은 마지막 것만 남음)
+type change struct {
+	route    *Route // nil이면 삭제
+	queuedAt time.Time
+	attempts int
+	origin   trace.SpanContext // 변경을 요청한 span (반영 span에 링크)
+}
+
+// Router 라우트 테이블을 보관하고 큐에 쌓인 변경을 묶어 설정 파일에 반영
+type Router struct {
+	path        string
+	gatewayAddr string // 설정 반영 후 배너를 확인할 sshpiperd 주소 (비어 있으면 생략)
+	minInterval time.Duration
+
+	mu      sync.Mutex
+	routes  map[string]Route
+	pending map[string]*change
+	wake    chan struct{}
+
+	stopChan chan struct{}
+	done     chan struct{}
+	running  bool
+}
+
+func NewRouter(path, gatewayAddr string, minInterval time.Duration) *Router {
+	return &Router{
+		path:        path,
+		gatewayAddr: gatewayAddr,
+		minInterval: minInterval,
+		routes:      make(map[string]Route),
+		pending:     make(map[string]*change),
+		wake:        make(chan struct{}, 1),
+		stopChan:    make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+// Path 게시 중인 설정 파일 경로
+func (r *Router) Path() string {
+	return r.path
+}
+
+func (r *Router) Start() {
+	if r.running {
+		return
+	}
+
+	r.running = true
+	go r.run()
+	log.Printf("🔀 SSHPiper 라우트 게시 시작됨 (%s, 최소 간격: %v)", r.path, r.minInterval)
+}
+
+// Stop 남은 변경을 반영한 뒤 중지
+func (r *Router) Stop() {
+	if !r.running {
+		return
+	}
+
+	r.running = false
+	close(r.stopChan)
+	<-r.done
+	log.Println("🔀 SSHPiper 라우트 게시 중지됨")
+}
+
+// Upsert 라우트 추가/변경 요청 (바로 반환, 다음 배치에서 반영)
+func (r *Router) Upsert(route Route) {
+	r.UpsertContext(context.Background(), route)
+}
+
+// UpsertContext Upsert와 같고, 배치를 반영하는 sshpiper.flush span이 ctx의 span을 링크로 가리킴
+func (r *Router) UpsertContext(ctx context.Context, route Route) {
+	r.enqueue(route.User, &change{route: &route, queuedAt: time.Now(), origin: trace.SpanContextFromContext(ctx)})
+}
+
+// Remove 라우트 삭제 요청
+func (r *Router) Remove(user string) {
+	r.enqueue(user, &change{queuedAt: time.Now()})
+}
+
+// Sync 테이블을 routes로 통째로 교체 (시작 시 저장소 기준 재구성)
+func (r *Router) Sync(routes []Route) {
+	now := time.Now()
+	r.mu.Lock()
+	keep := make(map[string]bool, len(routes))
+	for i := range routes {
+		keep[routes[i].User] = true
+		r.pending[routes[i].User] = &change{route: &routes[i], queuedAt: now}
+	}
+	for user := range r.routes {
+		if !keep[user] {
+			r.pending[user] = &change{queuedAt: now}
+		}
+	}
+	routeQueueLength.Set(float64(len(r.pending)))
+	r.mu.Unlock()
+	r.signal()
+}
+
+// Routes 현재 게시된 라우트 (사용자 이름 순)
+func (r *Router) Routes() []Route {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	routes := make([]Route, 0, len(r.routes))
+	for _, route := range r.routes {
+		routes = append(routes, route)
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].User < routes[j].User })
+	return routes
+}
+
+func (r *Router) enqueue(user string, c *change) {
+	r.mu.Lock()
+	if prev, ok := r.pending[user]; ok {
+		// 먼저 들어온 요청 시각을 유지해 지연 시간이 줄어 보이지 않게 함
+		c.queuedAt = prev.queuedAt
+	}
+	r.pending[user] = c
+	routeQueueLength.Set(float64(len(r.pending)))
+	r.mu.Unlock()
+	r.signal()
+}
+
+func (r *Router) signal() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (r *Router) run() {
+	defer close(r.done)
+
+	var last time.Time
+	for {
+		select {
+		case <-r.wake:
+		case <-r.stopChan:
+			r.flush()
+			return
+		}
+
+		// 직전 쓰기 후 min_interval이 지날 때까지 기다리며 그 사이 변경을 한 배치로 모음
+		if wait := r.minInterval - time.Since(last); wait > 0 {
+			select {
+			case <-time.After(wait):
+			case <-r.stopChan:
+				r.flush()
+				return
+			}
+		}
+		r.flush()
+		last = time.Now()
+	}
+}
+
+// flush 대기 중인 변경을 테이블에 적용하고 설정 파일을 한 번 씀
+func (r *Router) flush() {
+	r.mu.Lock()
+	if len(r.pending) == 0 {
+		r.mu.Unlock()
+		return
+	}
+	batch := r.pending
+	r.pending = make(map[string]*change)
+	previous := make(map[string]Route, len(r.routes))
+	for user, route := range r.routes {
+		previous[user] = route
+	}
+	for user, c := range batch {
+		if c.route == nil {
+			delete(r.routes, user)
+		} else {
+			r.routes[user] = *c.route
+		}
+	}
+	data := render(r.routes)
+	count := len(r.routes)
+	routeQueueLength.Set(0)
+	r.mu.Unlock()
+
+	// 배치는 여러 요청의 변경을 묶으므로 요청 span의 자식이 아니라 링크로 연결
+	var links []trace.Link
+	for _, c := range batch {
+		if c.origin.IsValid() && c.attempts == 0 {
+			links = append(links, trace.Link{SpanContext: c.origin})
+		}
+	}
+	span := tracing.StartLinked("sshpiper.flush", links,
+		attribute.Int("sandman.route_batch", len(batch)), attribute.Int("sandman.routes", count))
+
+	routeBatchSize.Observe(float64(len(batch)))
+	if err := writeAtomic(r.path, data); err != nil {
+		// 파일은 그대로이므로 테이블을 되돌리고 다음 배치에서 다시 시도
+		log.Printf("⚠️ SSHPiper 설정 쓰기 실패 (%d건 재시도 예정): %v", len(batch), err)
+		routeWrites.Inc("failed")
+		r.mu.Lock()
+		r.routes = previous
+		for user, c := range batch {
+			if _, newer := r.pending[user]; !newer {
+				r.pending[user] = c
+			}
+		}
+		routeQueueLength.Set(float64(len(r.pending)))
+		r.mu.Unlock()
+		tracing.End(span, err)
+		r.retryLater()
+		return
+	}
+	defer span.End()
+	routeWrites.Inc("ok")
+	routeCount.Set(float64(count))
+
+	now := time.Now()
+	for _, c := range batch {
+		if c.attempts == 0 {
+			routeUpdateSeconds.Observe(now.Sub(c.queuedAt).Seconds())
+		}
+	}
+	r.verify(batch)
+}
+
+// verify 반영 후 게이트웨이와 새 라우트의 대상 sshd가 SSH 배너로 응답하는지 확인
+func (r *Router) verify(batch map[string]*change) {
+	if r.gatewayAddr != "" && !sshBanner(r.gatewayAddr) {
+		log.Printf("⚠️ 라우트 반영 후 SSHPiper(%s)가 응답하지 않습니다", r.gatewayAddr)
+		routeUpdates.Inc("gateway", "unreachable")
+	}
+
+	// 배치가 클 수 있으므로 대상 확인은 동시에 진행
+	reachable := make(map[string]bool, len(batch))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for user, c := range batch {
+		if c.route == nil {
+			continue
+		}
+		if c.route.Wake {
+			mu.Lock()
+			reachable[user] = true
+			mu.Unlock()
+			continue
+		}
+		wg.Add(1)
+		go func(user, target string) {
+			defer wg.Done()
+			ok := sshBanner(target)
+			mu.Lock()
+			reachable[user] = ok
+			mu.Unlock()
+		}(user, c.route.target())
+	}
+	wg.Wait()
+
+	var retry bool
+	for user, c := range batch {
+		if c.route == nil {
+			routeUpdates.Inc("remove", "ok")
+			continue
+		}
+		if reachable[user] {
+			routeUpdates.Inc("upsert", "ok")
+			continue
+		}
+
+		c.attempts++
+		if c.attempts >= verifyAttempts {
+			log.Printf("⚠️ 라우트 확인 실패: %s → %s (%d회)", user, c.route.target(), c.attempts)
+			routeUpdates.Inc("upsert", "unverified")
+			continue
+		}
+		// 컨테이너 sshd가 아직 뜨지 않았을 수 있으므로 다음 배치에서 다시 확인 (이미 새 변경이 있으면 생략)
+		r.mu.Lock()
+		if _, newer := r.pending[user]; !newer {
+			r.pending[user] = c
+			retry = true
+		}
+		routeQueueLength.Set(float64(len(r.pending)))
+		r.mu.Unlock()
+	}
+	if retry {
+		r.retryLater()
+	}
+}
+
+func (r *Router) retryLater() {
+	go func() {
+		select {
+		case <-time.After(r.minInterval):
+			r.signal()
+		case <-r.stopChan:
+		}
+	}()
+}
+
+// render yaml 플러그인 설정 (사용자 이름 순으로 정렬해 같은 테이블은 같은 파일이 되게 함)
+func render(routes map[string]Route) []byte {
+	users := make([]string, 0, len(routes))
+	for user := range routes {
+		users = append(users, user)
+	}
+	sort.Strings(users)
+
+	var b strings.Builder
+	b.WriteString("# sandman이 관리하는 파일입니다. 직접 수정하지 마세요.\n")
+	b.WriteString("version: \"1.0\"\n")
+	if len(users) == 0 {
+		b.WriteString("pipes: []\n")
+		return []byte(b.String())
+	}
+	b.WriteString("pipes:\n")
+	for _, user := range users {
+		route := routes[user]
+		fmt.Fprintf(&b, "  # session: %s\n", route.SessionID)
+		fmt.Fprintf(&b, "  - from:\n      - username: %s\n", strconv.Quote(route.User))
+		if len(route.AuthorizedKeys) > 0 {
+			b.WriteString("        authorized_keys_data:\n")
+			for _, key := range route.AuthorizedKeys {
+				fmt.Fprintf(&b, "          - %s\n", strconv.Quote(base64.StdEncoding.EncodeToString([]byte(key+"\n"))))
+			}
+		}
+		fmt.Fprintf(&b, "    to:\n      host: %s\n      username: %s\n      ignore_hostkey: true\n",
+			strconv.Quote(route.target()), strconv.Quote(route.TargetUser))
+		if route.PrivateKeyFile != "" {
+			fmt.Fprintf(&b, "      private_key: %s\n", strconv.Quote(route.PrivateKeyFile))
+		}
+	}
+	return []byte(b.String())
+}
+
+// writeAtomic 같은 디렉토리의 임시 파일에 쓰고 rename (읽는 쪽은 이전 파일이나 새 파일 전체만 봄)
+func writeAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// sshBanner 주소에 연결해 SSH 배너("SSH-")를 받으면 true
+func sshBanner(addr string) bool {
+	conn, err := net.DialTimeout("tcp", addr, verifyTimeout)
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(verifyTimeout))
+	banner, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil && banner == "" {
+		return false
+	}
+	return strings.HasPrefix(banner, "SSH-")
+}
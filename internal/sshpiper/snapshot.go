@@ -0,0 +1,158 @@
+package sshpiper
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 라우트 차이 종류
+const (
+	DriftMissing  = "missing"  // 세션은 있는데 설정 파일에 라우트가 없음
+	DriftStale    = "stale"    // 설정 파일에만 남아 있는 라우트 (끝난 세션, 직접 추가한 항목)
+	DriftMismatch = "mismatch" // 대상 주소, 대상 사용자 또는 키 매핑이 다름
+)
+
+// RouteDrift 기대한 라우트와 sshpiperd가 읽는 설정 파일의 차이 한 건
+type RouteDrift struct {
+	User     string `json:"user"`
+	Issue    string `json:"issue"`
+	Expected *Route `json:"expected,omitempty"`
+	Loaded   *Route `json:"loaded,omitempty"`
+	// Pending 라우터 큐에 이 사용자의 변경이 있어 곧 반영될 예정 (잠깐의 차이는 정상)
+	Pending bool `json:"pending"`
+}
+
+// Pending 반영 대기 중인 변경이 있는 사용자
+func (r *Router) Pending() map[string]bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	users := make(map[string]bool, len(r.pending))
+	for user := range r.pending {
+		users[user] = true
+	}
+	return users
+}
+
+// Loaded 설정 파일을 읽어 sshpiperd가 실제로 쓰는 라우트 (yaml 플러그인은 연결마다 이 파일을 읽음)
+func (r *Router) Loaded() ([]Route, error) {
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return nil, err
+	}
+	return parseConfig(string(data))
+}
+
+// parseConfig render가 쓰는 형식의 yaml 플러그인 설정 해석
+// yaml 라이브러리 없이 pipes[].from[].username, authorized_keys_data, to.host, to.username, to.private_key와
+// 세션 주석만 읽음
+func parseConfig(data string) ([]Route, error) {
+	var routes []Route
+	var current *Route
+	var section, sessionID string
+	var inKeys bool
+	for i, line := range strings.Split(data, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if value, ok := strings.CutPrefix(trimmed, "# session:"); ok {
+			sessionID = strings.TrimSpace(value)
+			continue
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if trimmed == "- from:" {
+			routes = append(routes, Route{SessionID: sessionID})
+			current = &routes[len(routes)-1]
+			section, sessionID, inKeys = "from", "", false
+			continue
+		}
+		if trimmed == "to:" {
+			section, inKeys = "to", false
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if item, ok := strings.CutPrefix(trimmed, "- "); ok && inKeys && !strings.Contains(item, ":") {
+			key, err := base64.StdEncoding.DecodeString(unquote(strings.TrimSpace(item)))
+			if err != nil {
+				return nil, fmt.Errorf("%d번째 줄: 잘못된 authorized_keys_data", i+1)
+			}
+			current.AuthorizedKeys = append(current.AuthorizedKeys, strings.TrimSpace(string(key)))
+			continue
+		}
+
+		key, value, ok := strings.Cut(strings.TrimPrefix(trimmed, "- "), ":")
+		if !ok {
+			continue
+		}
+		inKeys = key == "authorized_keys_data" && section == "from"
+		value = unquote(strings.TrimSpace(value))
+		switch {
+		case key == "username" && section == "from":
+			current.User = value
+		case key == "username" && section == "to":
+			current.TargetUser = value
+		case key == "private_key" && section == "to":
+			current.PrivateKeyFile = value
+		case key == "host" && section == "to":
+			host, port, err := net.SplitHostPort(value)
+			if err != nil {
+				host, port = value, "22"
+			}
+			current.Host = host
+			if current.Port, err = strconv.Atoi(port); err != nil {
+				return nil, fmt.Errorf("%d번째 줄: 잘못된 포트 %q", i+1, port)
+			}
+		}
+	}
+	return routes, nil
+}
+
+// sameKeys 공개키 목록과 개인키 파일이 같은지
+func sameKeys(a, b Route) bool {
+	return a.PrivateKeyFile == b.PrivateKeyFile && strings.Join(a.AuthorizedKeys, "\n") == strings.Join(b.AuthorizedKeys, "\n")
+}
+
+func unquote(value string) string {
+	if s, err := strconv.Unquote(value); err == nil {
+		return s
+	}
+	return strings.Trim(value, "'")
+}
+
+// Diff 기대한 라우트와 설정 파일 라우트를 사용자별로 비교 (사용자 이름 순)
+func Diff(expected, loaded []Route, pending map[string]bool) []RouteDrift {
+	want := make(map[string]Route, len(expected))
+	for _, route := range expected {
+		want[route.User] = route
+	}
+	have := make(map[string]Route, len(loaded))
+	for _, route := range loaded {
+		have[route.User] = route
+	}
+
+	var drift []RouteDrift
+	for user, w := range want {
+		w := w
+		h, ok := have[user]
+		switch {
+		case !ok:
+			drift = append(drift, RouteDrift{User: user, Issue: DriftMissing, Expected: &w, Pending: pending[user]})
+		case h.target() != w.target() || h.TargetUser != w.TargetUser || !sameKeys(h, w):
+			drift = append(drift, RouteDrift{User: user, Issue: DriftMismatch, Expected: &w, Loaded: &h, Pending: pending[user]})
+		}
+	}
+	for user, h := range have {
+		h := h
+		if _, ok := want[user]; !ok {
+			drift = append(drift, RouteDrift{User: user, Issue: DriftStale, Loaded: &h, Pending: pending[user]})
+		}
+	}
+	sort.Slice(drift, func(i, j int) bool { return drift[i].User < drift[j].User })
+	return drift
+}
@@ -0,0 +1,42 @@
+package store
+
+import (
+	"encoding/json"
+)
+
+// PatchSessionAnnotations 네임스페이스별 주석을 병합 (값이 null이면 해당 네임스페이스 삭제) 후 결과 반환
+// 읽기와 쓰기를 한 트랜잭션으로 묶어 동시에 다른 네임스페이스를 수정해도 덮어쓰지 않음
+func (s *SQLiteStore) PatchSessionAnnotations(sessionID string, patch map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var raw string
+	if err := tx.QueryRow(`SELECT annotations FROM sessions WHERE id = ?`, sessionID).Scan(&raw); err != nil {
+		return nil, classify(err)
+	}
+
+	annotations := make(map[string]json.RawMessage)
+	json.Unmarshal([]byte(raw), &annotations)
+	for namespace, value := range patch {
+		if string(value) == "null" {
+			delete(annotations, namespace)
+			continue
+		}
+		annotations[namespace] = value
+	}
+
+	merged, err := json.Marshal(annotations)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`UPDATE sessions SET annotations = ? WHERE id = ?`, string(merged), sessionID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return annotations, nil
+}
@@ -0,0 +1,93 @@
+package store
+
+import (
+	"strings"
+	"time"
+)
+
+// AuditEntry 보안 관련 조치(자격 증명 폐기 등)의 기록
+type AuditEntry struct {
+	ID        int64     `json:"id"`
+	Actor     string    `json:"actor"`
+	Action    string    `json:"action"`
+	Target    string    `json:"target"`
+	Detail    string    `json:"detail,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s *SQLiteStore) AppendAudit(entry *AuditEntry) error {
+	query := `INSERT INTO audit_log (actor, action, target, detail, created_at) VALUES (?, ?, ?, ?, ?) RETURNING id`
+	return s.db.QueryRow(query, entry.Actor, entry.Action, entry.Target, entry.Detail, entry.CreatedAt).Scan(&entry.ID)
+}
+
+// AuditFilter 감사 기록 조회 조건 (빈 값은 조건 없음)
+type AuditFilter struct {
+	Actor  string
+	Action string // 끝이 '.'이면 접두사로 비교 (예: "session.")
+	Target string
+	Since  time.Time
+	Limit  int
+}
+
+// QueryAudit 조건에 맞는 기록을 최근 기록부터 최대 filter.Limit개 조회
+func (s *SQLiteStore) QueryAudit(filter AuditFilter) ([]*AuditEntry, error) {
+	query := `SELECT id, actor, action, target, detail, created_at FROM audit_log WHERE 1=1`
+	args := []interface{}{}
+	if filter.Actor != "" {
+		query += ` AND actor = ?`
+		args = append(args, filter.Actor)
+	}
+	if strings.HasSuffix(filter.Action, ".") {
+		query += ` AND action LIKE ?`
+		args = append(args, filter.Action+"%")
+	} else if filter.Action != "" {
+		query += ` AND action = ?`
+		args = append(args, filter.Action)
+	}
+	if filter.Target != "" {
+		query += ` AND target = ?`
+		args = append(args, filter.Target)
+	}
+	if !filter.Since.IsZero() {
+		query += ` AND created_at >= ?`
+		args = append(args, filter.Since)
+	}
+	query += ` ORDER BY id DESC LIMIT ?`
+	args = append(args, filter.Limit)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*AuditEntry{}
+	for rows.Next() {
+		entry := &AuditEntry{}
+		if err := rows.Scan(&entry.ID, &entry.Actor, &entry.Action, &entry.Target, &entry.Detail, &entry.CreatedAt); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// ListAudit 최근 기록부터 최대 limit개 조회
+func (s *SQLiteStore) ListAudit(limit int) ([]*AuditEntry, error) {
+	query := `SELECT id, actor, action, target, detail, created_at FROM audit_log ORDER BY id DESC LIMIT ?`
+	rows, err := s.db.Query(query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*AuditEntry{}
+	for rows.Next() {
+		entry := &AuditEntry{}
+		if err := rows.Scan(&entry.ID, &entry.Actor, &entry.Action, &entry.Target, &entry.Detail, &entry.CreatedAt); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
@@ -0,0 +1,43 @@
+package store
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// SessionBaseline 세션 시작 직후 기록한 컨테이너 환경 (드리프트 비교 기준)
+type SessionBaseline struct {
+	SessionID  string            `json:"session_id"`
+	Image      string            `json:"image"`
+	Packages   map[string]string `json:"packages"` // "dpkg:<이름>" / "pip:<이름>" → 버전
+	CapturedAt time.Time         `json:"captured_at"`
+}
+
+func (s *SQLiteStore) SaveSessionBaseline(baseline *SessionBaseline) error {
+	packagesJSON, err := json.Marshal(baseline.Packages)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`
+		INSERT INTO session_baselines (session_id, image, packages, captured_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET image = excluded.image, packages = excluded.packages, captured_at = excluded.captured_at
+	`, baseline.SessionID, baseline.Image, string(packagesJSON), baseline.CapturedAt)
+	return err
+}
+
+func (s *SQLiteStore) GetSessionBaseline(sessionID string) (*SessionBaseline, error) {
+	baseline := &SessionBaseline{}
+	var packagesJSON string
+	err := s.db.QueryRow(`SELECT session_id, image, packages, captured_at FROM session_baselines WHERE session_id = ?`, sessionID).
+		Scan(&baseline.SessionID, &baseline.Image, &packagesJSON, &baseline.CapturedAt)
+	if err != nil {
+		return nil, classify(err)
+	}
+	json.Unmarshal([]byte(packagesJSON), &baseline.Packages)
+	return baseline, nil
+}
+
+func (s *SQLiteStore) DeleteSessionBaseline(sessionID string) error {
+	_, err := s.db.Exec(`DELETE FROM session_baselines WHERE session_id = ?`, sessionID)
+	return err
+}
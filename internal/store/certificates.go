@@ -0,0 +1,90 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+)
+
+// SSHCertificate 세션 사용자에게 발급한 SSH 인증서 기록 (인증서 자체가 아닌 폐기/감사용 정보)
+type SSHCertificate struct {
+	Serial      uint64     `json:"serial"`
+	SessionID   string     `json:"session_id"`
+	UserID      string     `json:"user_id"`
+	KeyID       string     `json:"key_id"`
+	Principal   string     `json:"principal"`
+	PublicKey   string     `json:"public_key"`
+	Fingerprint string     `json:"fingerprint"`
+	ValidBefore time.Time  `json:"valid_before"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+}
+
+// CreateSSHCertificate 인증서 기록을 저장하고 자동 증가 일련번호를 cert.Serial에 설정
+func (s *SQLiteStore) CreateSSHCertificate(cert *SSHCertificate) error {
+	query := `
+		INSERT INTO ssh_certificates (session_id, user_id, key_id, principal, public_key, fingerprint, valid_before, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING serial
+	`
+	var id int64
+	err := s.db.QueryRow(query, cert.SessionID, cert.UserID, cert.KeyID, cert.Principal,
+		cert.PublicKey, cert.Fingerprint, cert.ValidBefore, cert.CreatedAt).Scan(&id)
+	if err != nil {
+		return err
+	}
+	cert.Serial = uint64(id)
+	return nil
+}
+
+func (s *SQLiteStore) ListSSHCertificates(sessionID string) ([]*SSHCertificate, error) {
+	query := `
+		SELECT serial, session_id, user_id, key_id, principal, public_key, fingerprint, valid_before, created_at, revoked_at
+		FROM ssh_certificates WHERE session_id = ? ORDER BY serial
+	`
+	rows, err := s.db.Query(query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	certs := []*SSHCertificate{}
+	for rows.Next() {
+		cert := &SSHCertificate{}
+		var revokedAt sql.NullTime
+		if err := rows.Scan(&cert.Serial, &cert.SessionID, &cert.UserID, &cert.KeyID, &cert.Principal,
+			&cert.PublicKey, &cert.Fingerprint, &cert.ValidBefore, &cert.CreatedAt, &revokedAt); err != nil {
+			continue
+		}
+		if revokedAt.Valid {
+			cert.RevokedAt = &revokedAt.Time
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// RevokeSSHCertificate 세션의 인증서 하나를 폐기 처리
+func (s *SQLiteStore) RevokeSSHCertificate(sessionID string, serial uint64) error {
+	result, err := s.db.Exec(`UPDATE ssh_certificates SET revoked_at = ? WHERE session_id = ? AND serial = ? AND revoked_at IS NULL`,
+		time.Now(), sessionID, serial)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errNoRows
+	}
+	return nil
+}
+
+// RevokeSSHCertificates 세션의 아직 유효한 인증서를 모두 폐기 처리
+func (s *SQLiteStore) RevokeSSHCertificates(sessionID string) error {
+	_, err := s.db.Exec(`UPDATE ssh_certificates SET revoked_at = ? WHERE session_id = ? AND revoked_at IS NULL`,
+		time.Now(), sessionID)
+	return err
+}
+
+// ScrubSSHCertificates 종료된 세션 인증서 기록에서 공개키를 지우고 지문만 남김
+func (s *SQLiteStore) ScrubSSHCertificates(sessionID string) error {
+	_, err := s.db.Exec(`UPDATE ssh_certificates SET public_key = '' WHERE session_id = ?`, sessionID)
+	return err
+}
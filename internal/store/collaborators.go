@@ -0,0 +1,61 @@
+package store
+
+import (
+	"time"
+)
+
+// Collaborator 세션 소유자가 SSH 접근을 허용한 다른 사용자
+type Collaborator struct {
+	SessionID   string    `json:"session_id"`
+	UserID      string    `json:"user_id"`
+	PublicKey   string    `json:"public_key"`
+	Fingerprint string    `json:"fingerprint"`
+	GrantedAt   time.Time `json:"granted_at"`
+}
+
+func (s *SQLiteStore) AddCollaborator(collab *Collaborator) error {
+	query := `
+		INSERT INTO session_collaborators (session_id, user_id, public_key, fingerprint, granted_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	_, err := s.db.Exec(query, collab.SessionID, collab.UserID, collab.PublicKey, collab.Fingerprint, collab.GrantedAt)
+	return classify(err)
+}
+
+func (s *SQLiteStore) ListCollaborators(sessionID string) ([]*Collaborator, error) {
+	query := `
+		SELECT session_id, user_id, public_key, fingerprint, granted_at
+		FROM session_collaborators WHERE session_id = ? ORDER BY granted_at
+	`
+	rows, err := s.db.Query(query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	collabs := []*Collaborator{}
+	for rows.Next() {
+		collab := &Collaborator{}
+		if err := rows.Scan(&collab.SessionID, &collab.UserID, &collab.PublicKey, &collab.Fingerprint, &collab.GrantedAt); err != nil {
+			continue
+		}
+		collabs = append(collabs, collab)
+	}
+	return collabs, nil
+}
+
+func (s *SQLiteStore) RemoveCollaborator(sessionID, userID string) error {
+	result, err := s.db.Exec(`DELETE FROM session_collaborators WHERE session_id = ? AND user_id = ?`, sessionID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errNoRows
+	}
+	return nil
+}
+
+func (s *SQLiteStore) DeleteCollaborators(sessionID string) error {
+	_, err := s.db.Exec(`DELETE FROM session_collaborators WHERE session_id = ?`, sessionID)
+	return err
+}
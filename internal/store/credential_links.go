@@ -0,0 +1,51 @@
+package store
+
+import "time"
+
+// CredentialLink 세션 자격 증명을 한 번만 내려주는 링크 (토큰은 해시만 저장)
+type CredentialLink struct {
+	TokenHash     string
+	SessionID     string
+	SSHPassword   string
+	SSHPrivateKey string
+	CreatedAt     time.Time
+	ExpiresAt     time.Time
+}
+
+func (s *SQLiteStore) CreateCredentialLink(link *CredentialLink) error {
+	_, err := s.db.Exec(`
+		INSERT INTO credential_links (token_hash, session_id, ssh_password, ssh_private_key, created_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, link.TokenHash, link.SessionID, link.SSHPassword, link.SSHPrivateKey, link.CreatedAt, link.ExpiresAt)
+	return classify(err)
+}
+
+// TakeCredentialLink 링크를 조회하면서 삭제 (동시에 두 번 사용되지 않도록 한 트랜잭션에서 처리)
+func (s *SQLiteStore) TakeCredentialLink(tokenHash string) (*CredentialLink, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	link := &CredentialLink{}
+	err = tx.QueryRow(`
+		SELECT token_hash, session_id, ssh_password, ssh_private_key, created_at, expires_at
+		FROM credential_links WHERE token_hash = ?
+	`, tokenHash).Scan(&link.TokenHash, &link.SessionID, &link.SSHPassword, &link.SSHPrivateKey, &link.CreatedAt, &link.ExpiresAt)
+	if err != nil {
+		return nil, classify(err)
+	}
+	if _, err := tx.Exec(`DELETE FROM credential_links WHERE token_hash = ?`, tokenHash); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+func (s *SQLiteStore) DeleteCredentialLinks(sessionID string) error {
+	_, err := s.db.Exec(`DELETE FROM credential_links WHERE session_id = ?`, sessionID)
+	return err
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+)
+
+// dialect 쿼리를 실행할 데이터베이스 종류
+// 쿼리와 스키마는 SQLite 문법으로 한 벌만 작성하고, PostgreSQL은 실행 직전에 변환
+type dialect int
+
+const (
+	dialectSQLite dialect = iota
+	dialectPostgres
+)
+
+// postgresTypes SQLite 스키마의 컬럼 타입을 PostgreSQL 타입으로 (긴 것부터 바꿈)
+var postgresTypes = strings.NewReplacer(
+	"INTEGER PRIMARY KEY AUTOINCREMENT", "BIGSERIAL PRIMARY KEY",
+	"DATETIME", "TIMESTAMPTZ",
+	"INTEGER", "BIGINT",
+	"REAL", "DOUBLE PRECISION",
+)
+
+// rebind ? 자리 표시자를 $1, $2...로 바꾸고 SQLite 전용 함수를 대응 함수로 바꿈 (문자열 리터럴 안은 그대로)
+func (d dialect) rebind(query string) string {
+	if d != dialectPostgres {
+		return query
+	}
+	query = strings.ReplaceAll(query, "datetime('now')", "now()")
+
+	var b strings.Builder
+	b.Grow(len(query) + 16)
+	n := 0
+	quoted := false
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case ch == '\'':
+			quoted = !quoted
+		case ch == '?' && !quoted:
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(ch)
+	}
+	return b.String()
+}
+
+// ddl CREATE TABLE/ALTER TABLE 문의 컬럼 타입 변환
+func (d dialect) ddl(schema string) string {
+	if d != dialectPostgres {
+		return schema
+	}
+	return postgresTypes.Replace(schema)
+}
+
+// conn 쿼리를 dialect에 맞게 바꿔 실행하는 *sql.DB
+type conn struct {
+	*sql.DB
+	dialect dialect
+}
+
+func (c *conn) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return c.DB.Exec(c.dialect.rebind(query), args...)
+}
+
+func (c *conn) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return c.DB.Query(c.dialect.rebind(query), args...)
+}
+
+func (c *conn) QueryRow(query string, args ...interface{}) *sql.Row {
+	return c.DB.QueryRow(c.dialect.rebind(query), args...)
+}
+
+func (c *conn) Begin() (*txConn, error) {
+	tx, err := c.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	return &txConn{Tx: tx, dialect: c.dialect}, nil
+}
+
+// txConn 쿼리를 dialect에 맞게 바꿔 실행하는 *sql.Tx
+type txConn struct {
+	*sql.Tx
+	dialect dialect
+}
+
+func (t *txConn) Exec(query string, args ...interface{}) (sql.Result, error) {
+	return t.Tx.Exec(t.dialect.rebind(query), args...)
+}
+
+func (t *txConn) Query(query string, args ...interface{}) (*sql.Rows, error) {
+	return t.Tx.Query(t.dialect.rebind(query), args...)
+}
+
+func (t *txConn) QueryRow(query string, args ...interface{}) *sql.Row {
+	return t.Tx.QueryRow(t.dialect.rebind(query), args...)
+}
+
+func (t *txConn) Prepare(query string) (*sql.Stmt, error) {
+	return t.Tx.Prepare(t.dialect.rebind(query))
+}
@@ -0,0 +1,127 @@
+package store
+
+import (
+	"strings"
+	"time"
+)
+
+// DNSQueryRecord 세션 컨테이너가 보낸 DNS 질의 한 건 (DNS 감사 사이드카 로그에서 수집)
+type DNSQueryRecord struct {
+	ID        int64     `json:"id"`
+	SessionID string    `json:"session_id,omitempty"` // 질의 시점에 주소가 세션과 맞지 않으면 비어 있음
+	UserID    string    `json:"user_id,omitempty"`
+	NodeID    string    `json:"node_id,omitempty"`
+	ClientIP  string    `json:"client_ip"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	RCode     string    `json:"rcode"`
+	QueriedAt time.Time `json:"queried_at"`
+}
+
+// DNSQueryFilter 관리자 조회 조건 (빈 값은 조건 없음, Name은 접미사 일치)
+type DNSQueryFilter struct {
+	SessionID string
+	UserID    string
+	Name      string
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+}
+
+// InsertDNSQueries 질의 묶음을 한 트랜잭션으로 기록
+func (s *SQLiteStore) InsertDNSQueries(records []*DNSQueryRecord) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`INSERT INTO dns_queries (session_id, user_id, node_id, client_ip, name, qtype, rcode, queried_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, r := range records {
+		if _, err := stmt.Exec(r.SessionID, r.UserID, r.NodeID, r.ClientIP, r.Name, r.Type, r.RCode, r.QueriedAt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ListDNSQueries 최근 질의부터 조회
+func (s *SQLiteStore) ListDNSQueries(filter DNSQueryFilter) ([]*DNSQueryRecord, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if filter.SessionID != "" {
+		conditions = append(conditions, "session_id = ?")
+		args = append(args, filter.SessionID)
+	}
+	if filter.UserID != "" {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, filter.UserID)
+	}
+	if filter.Name != "" {
+		// example.com은 example.com과 그 하위 도메인 모두 일치
+		name := strings.ToLower(strings.TrimSuffix(filter.Name, "."))
+		conditions = append(conditions, "(name = ? OR name LIKE ? ESCAPE '\\')")
+		args = append(args, name, "%."+escapeLike(name))
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "queried_at >= ?")
+		args = append(args, filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "queried_at < ?")
+		args = append(args, filter.Until)
+	}
+
+	query := `SELECT id, session_id, user_id, node_id, client_ip, name, qtype, rcode, queried_at FROM dns_queries`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY queried_at DESC, id DESC LIMIT ?"
+	args = append(args, filter.Limit)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []*DNSQueryRecord{}
+	for rows.Next() {
+		r := &DNSQueryRecord{}
+		if err := rows.Scan(&r.ID, &r.SessionID, &r.UserID, &r.NodeID, &r.ClientIP, &r.Name, &r.Type, &r.RCode, &r.QueriedAt); err != nil {
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+// PruneDNSQueries before 이전 질의와, maxRows를 넘는 오래된 질의를 삭제 (maxRows가 0이면 개수 제한 없음)
+func (s *SQLiteStore) PruneDNSQueries(before time.Time, maxRows int) (int64, error) {
+	result, err := s.db.Exec(`DELETE FROM dns_queries WHERE queried_at < ?`, before)
+	if err != nil {
+		return 0, err
+	}
+	deleted, _ := result.RowsAffected()
+
+	if maxRows > 0 {
+		result, err = s.db.Exec(`DELETE FROM dns_queries WHERE id <= (SELECT id FROM dns_queries ORDER BY id DESC LIMIT 1 OFFSET ?)`, maxRows)
+		if err != nil {
+			return deleted, err
+		}
+		n, _ := result.RowsAffected()
+		deleted += n
+	}
+	return deleted, nil
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,34 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+)
+
+// errNoRows 갱신/삭제 대상이 없을 때 반환 (sql.ErrNoRows와 errs.ErrNotFound 모두로 판별 가능)
+var errNoRows = errs.Wrap(errs.ErrNotFound, sql.ErrNoRows)
+
+// classify 드라이버 오류를 errs 종류로 변환 (행 없음 → ErrNotFound, 고유 제약 위반 → ErrAlreadyExists)
+func classify(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return errNoRows
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) &&
+		(sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique || sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey) {
+		return errs.Wrap(errs.ErrAlreadyExists, err)
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation
+		return errs.Wrap(errs.ErrAlreadyExists, err)
+	}
+	return err
+}
@@ -0,0 +1,64 @@
+package store
+
+import (
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+)
+
+// GPUClaim gpu-allocation 락 안에서 기록하는 인스턴스 할당 (복제본마다 메모리에 따로 둔 할당 상태를 맞추는 기준)
+type GPUClaim struct {
+	InstanceUUID string    `json:"instance_uuid"`
+	NodeID       string    `json:"node_id,omitempty"`
+	SessionID    string    `json:"session_id"`
+	UserID       string    `json:"user_id"`
+	ClaimedAt    time.Time `json:"claimed_at"`
+}
+
+const gpuClaimColumns = `instance_uuid, node_id, session_id, user_id, claimed_at`
+
+// ClaimGPU 인스턴스 할당 기록 (같은 세션이 다시 기록하면 그대로, 다른 세션이 가진 인스턴스면 ErrConflict)
+func (s *SQLiteStore) ClaimGPU(claim *GPUClaim) error {
+	result, err := s.db.Exec(`INSERT INTO gpu_claims (`+gpuClaimColumns+`) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(instance_uuid) DO NOTHING`,
+		claim.InstanceUUID, claim.NodeID, claim.SessionID, claim.UserID, claim.ClaimedAt.UTC())
+	if err != nil {
+		return classify(err)
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 1 {
+		return err
+	}
+	var owner string
+	if err := s.db.QueryRow(`SELECT session_id FROM gpu_claims WHERE instance_uuid = ?`, claim.InstanceUUID).Scan(&owner); err != nil {
+		return classify(err)
+	}
+	if owner != claim.SessionID {
+		return errs.New(errs.ErrConflict, "GPU 인스턴스 %s는 세션 %s에 할당되어 있습니다", claim.InstanceUUID, owner)
+	}
+	return nil
+}
+
+// ReleaseGPUClaim 세션의 인스턴스 할당 기록 삭제 (없거나 다른 세션의 기록이면 아무것도 하지 않음)
+func (s *SQLiteStore) ReleaseGPUClaim(instanceUUID, sessionID string) error {
+	_, err := s.db.Exec(`DELETE FROM gpu_claims WHERE instance_uuid = ? AND session_id = ?`, instanceUUID, sessionID)
+	return classify(err)
+}
+
+// ListGPUClaims 노드의 인스턴스 할당 기록
+func (s *SQLiteStore) ListGPUClaims(nodeID string) ([]*GPUClaim, error) {
+	rows, err := s.db.Query(`SELECT `+gpuClaimColumns+` FROM gpu_claims WHERE node_id = ?`, nodeID)
+	if err != nil {
+		return nil, classify(err)
+	}
+	defer rows.Close()
+
+	claims := []*GPUClaim{}
+	for rows.Next() {
+		claim := &GPUClaim{}
+		if err := rows.Scan(&claim.InstanceUUID, &claim.NodeID, &claim.SessionID, &claim.UserID, &claim.ClaimedAt); err != nil {
+			return nil, classify(err)
+		}
+		claims = append(claims, claim)
+	}
+	return claims, rows.Err()
+}
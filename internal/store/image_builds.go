@@ -0,0 +1,64 @@
+package store
+
+import (
+	"time"
+)
+
+// ImageBuild 세션 컨테이너를 만들 때 수행한 워크스페이스 이미지 빌드 기록 (세션이 끝나도 남음)
+type ImageBuild struct {
+	SessionID   string    `json:"session_id"`
+	UserID      string    `json:"user_id"`
+	ProjectID   string    `json:"project_id,omitempty"`
+	Template    string    `json:"template"`
+	BaseImage   string    `json:"base_image,omitempty"`
+	Image       string    `json:"image"`
+	StartedAt   time.Time `json:"started_at"`
+	DurationMs  int64     `json:"duration_ms"`
+	Steps       int       `json:"steps"`
+	CachedSteps int       `json:"cached_steps"`
+	SizeBytes   int64     `json:"size_bytes"`
+}
+
+const imageBuildColumns = `session_id, user_id, project_id, template, base_image, image, started_at, duration_ms, steps, cached_steps, size_bytes`
+
+func scanImageBuild(row rowScanner) (*ImageBuild, error) {
+	b := &ImageBuild{}
+	err := row.Scan(&b.SessionID, &b.UserID, &b.ProjectID, &b.Template, &b.BaseImage, &b.Image, &b.StartedAt, &b.DurationMs, &b.Steps, &b.CachedSteps, &b.SizeBytes)
+	if err != nil {
+		return nil, classify(err)
+	}
+	return b, nil
+}
+
+// SaveImageBuild 빌드 기록 저장 (컨테이너를 다시 만들면 같은 세션의 기록을 교체)
+func (s *SQLiteStore) SaveImageBuild(b *ImageBuild) error {
+	_, err := s.db.Exec(`INSERT INTO image_builds (`+imageBuildColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET user_id = excluded.user_id, project_id = excluded.project_id, template = excluded.template,
+			base_image = excluded.base_image, image = excluded.image, started_at = excluded.started_at, duration_ms = excluded.duration_ms,
+			steps = excluded.steps, cached_steps = excluded.cached_steps, size_bytes = excluded.size_bytes`,
+		b.SessionID, b.UserID, b.ProjectID, b.Template, b.BaseImage, b.Image, b.StartedAt, b.DurationMs, b.Steps, b.CachedSteps, b.SizeBytes)
+	return classify(err)
+}
+
+func (s *SQLiteStore) GetImageBuild(sessionID string) (*ImageBuild, error) {
+	return scanImageBuild(s.db.QueryRow(`SELECT `+imageBuildColumns+` FROM image_builds WHERE session_id = ?`, sessionID))
+}
+
+// ListImageBuilds since 이후 시작한 빌드 (시작 시각 순)
+func (s *SQLiteStore) ListImageBuilds(since time.Time) ([]*ImageBuild, error) {
+	rows, err := s.db.Query(`SELECT `+imageBuildColumns+` FROM image_builds WHERE started_at >= ? ORDER BY started_at`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	builds := []*ImageBuild{}
+	for rows.Next() {
+		b, err := scanImageBuild(rows)
+		if err != nil {
+			continue
+		}
+		builds = append(builds, b)
+	}
+	return builds, nil
+}
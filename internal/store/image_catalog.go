@@ -0,0 +1,71 @@
+package store
+
+import (
+	"time"
+)
+
+// CatalogImage 세션 요청에 쓸 수 있는 이미지 (image 필드의 이름으로 고르고 base_image로 워크스페이스 이미지를 빌드)
+type CatalogImage struct {
+	Name        string    `json:"name"`
+	BaseImage   string    `json:"base_image"`
+	CUDAVersion string    `json:"cuda_version,omitempty"`
+	Description string    `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+const catalogImageColumns = `name, base_image, cuda_version, description, created_at, updated_at`
+
+func scanCatalogImage(row rowScanner) (*CatalogImage, error) {
+	img := &CatalogImage{}
+	if err := row.Scan(&img.Name, &img.BaseImage, &img.CUDAVersion, &img.Description, &img.CreatedAt, &img.UpdatedAt); err != nil {
+		return nil, classify(err)
+	}
+	return img, nil
+}
+
+func (s *SQLiteStore) GetCatalogImage(name string) (*CatalogImage, error) {
+	return scanCatalogImage(s.db.QueryRow(`SELECT `+catalogImageColumns+` FROM image_catalog WHERE name = ?`, name))
+}
+
+func (s *SQLiteStore) ListCatalogImages() ([]*CatalogImage, error) {
+	rows, err := s.db.Query(`SELECT ` + catalogImageColumns + ` FROM image_catalog ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	images := []*CatalogImage{}
+	for rows.Next() {
+		img, err := scanCatalogImage(rows)
+		if err != nil {
+			continue
+		}
+		images = append(images, img)
+	}
+	return images, nil
+}
+
+// SaveCatalogImage 이미지 등록 또는 내용 교체 (처음 등록한 시각은 유지)
+func (s *SQLiteStore) SaveCatalogImage(img *CatalogImage) error {
+	_, err := s.db.Exec(`
+		INSERT INTO image_catalog (`+catalogImageColumns+`) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET
+			base_image = excluded.base_image,
+			cuda_version = excluded.cuda_version,
+			description = excluded.description,
+			updated_at = excluded.updated_at
+	`, img.Name, img.BaseImage, img.CUDAVersion, img.Description, img.CreatedAt, img.UpdatedAt)
+	return err
+}
+
+func (s *SQLiteStore) DeleteCatalogImage(name string) error {
+	result, err := s.db.Exec(`DELETE FROM image_catalog WHERE name = ?`, name)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errNoRows
+	}
+	return nil
+}
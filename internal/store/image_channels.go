@@ -0,0 +1,137 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+)
+
+const (
+	ChannelStable = "stable"
+	ChannelNext   = "next"
+)
+
+// ImageChannel 워크스페이스 베이스 이미지 채널 (stable/next 블루-그린 업그레이드)
+type ImageChannel struct {
+	Name              string     `json:"name"`
+	BaseImage         string     `json:"base_image"`                    // 비어 있으면 Dockerfile 기본값
+	PreviousBaseImage string     `json:"previous_base_image,omitempty"` // 자동 롤백 시 되돌릴 이미지
+	RolloutPercent    int        `json:"rollout_percent"`               // next 채널을 받는 새 세션 비율 (고정되지 않은 사용자 기준)
+	Attempts          int        `json:"attempts"`                      // 마지막 변경 이후 세션 생성 시도 수
+	Failures          int        `json:"failures"`                      // 그중 실패 수
+	UpdatedAt         time.Time  `json:"updated_at"`
+	RolledBackAt      *time.Time `json:"rolled_back_at,omitempty"`
+}
+
+// ImagePin 사용자("user:<id>") 또는 코호트(프로젝트, "project:<id>")를 특정 채널에 고정
+type ImagePin struct {
+	Subject   string    `json:"subject"`
+	Channel   string    `json:"channel"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+const imageChannelColumns = `name, base_image, previous_base_image, rollout_percent, attempts, failures, updated_at, rolled_back_at`
+
+func scanImageChannel(row rowScanner) (*ImageChannel, error) {
+	ch := &ImageChannel{}
+	var rolledBackAt sql.NullTime
+	err := row.Scan(&ch.Name, &ch.BaseImage, &ch.PreviousBaseImage, &ch.RolloutPercent, &ch.Attempts, &ch.Failures, &ch.UpdatedAt, &rolledBackAt)
+	if err != nil {
+		return nil, classify(err)
+	}
+	if rolledBackAt.Valid {
+		ch.RolledBackAt = &rolledBackAt.Time
+	}
+	return ch, nil
+}
+
+func (s *SQLiteStore) GetImageChannel(name string) (*ImageChannel, error) {
+	return scanImageChannel(s.db.QueryRow(`SELECT `+imageChannelColumns+` FROM image_channels WHERE name = ?`, name))
+}
+
+func (s *SQLiteStore) ListImageChannels() ([]*ImageChannel, error) {
+	rows, err := s.db.Query(`SELECT ` + imageChannelColumns + ` FROM image_channels ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	channels := []*ImageChannel{}
+	for rows.Next() {
+		ch, err := scanImageChannel(rows)
+		if err != nil {
+			continue
+		}
+		channels = append(channels, ch)
+	}
+	return channels, nil
+}
+
+// SaveImageChannel 채널 생성 또는 교체 (시도/실패 집계 포함)
+func (s *SQLiteStore) SaveImageChannel(ch *ImageChannel) error {
+	query := `
+		INSERT INTO image_channels (` + imageChannelColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET
+			base_image = excluded.base_image, previous_base_image = excluded.previous_base_image,
+			rollout_percent = excluded.rollout_percent, attempts = excluded.attempts, failures = excluded.failures,
+			updated_at = excluded.updated_at, rolled_back_at = excluded.rolled_back_at
+	`
+	_, err := s.db.Exec(query, ch.Name, ch.BaseImage, ch.PreviousBaseImage, ch.RolloutPercent, ch.Attempts, ch.Failures, ch.UpdatedAt, ch.RolledBackAt)
+	return err
+}
+
+// RecordImageChannelOutcome 채널의 세션 생성 결과 누적 (채널이 없으면 무시)
+func (s *SQLiteStore) RecordImageChannelOutcome(name string, failed bool) error {
+	failure := 0
+	if failed {
+		failure = 1
+	}
+	_, err := s.db.Exec(`UPDATE image_channels SET attempts = attempts + 1, failures = failures + ? WHERE name = ?`, failure, name)
+	return err
+}
+
+func (s *SQLiteStore) GetImagePin(subject string) (*ImagePin, error) {
+	pin := &ImagePin{}
+	err := s.db.QueryRow(`SELECT subject, channel, created_at FROM image_pins WHERE subject = ?`, subject).
+		Scan(&pin.Subject, &pin.Channel, &pin.CreatedAt)
+	if err != nil {
+		return nil, classify(err)
+	}
+	return pin, nil
+}
+
+func (s *SQLiteStore) ListImagePins() ([]*ImagePin, error) {
+	rows, err := s.db.Query(`SELECT subject, channel, created_at FROM image_pins ORDER BY subject`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pins := []*ImagePin{}
+	for rows.Next() {
+		pin := &ImagePin{}
+		if err := rows.Scan(&pin.Subject, &pin.Channel, &pin.CreatedAt); err != nil {
+			continue
+		}
+		pins = append(pins, pin)
+	}
+	return pins, nil
+}
+
+func (s *SQLiteStore) SaveImagePin(pin *ImagePin) error {
+	_, err := s.db.Exec(`
+		INSERT INTO image_pins (subject, channel, created_at) VALUES (?, ?, ?)
+		ON CONFLICT(subject) DO UPDATE SET channel = excluded.channel, created_at = excluded.created_at
+	`, pin.Subject, pin.Channel, pin.CreatedAt)
+	return err
+}
+
+func (s *SQLiteStore) DeleteImagePin(subject string) error {
+	result, err := s.db.Exec(`DELETE FROM image_pins WHERE subject = ?`, subject)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errNoRows
+	}
+	return nil
+}
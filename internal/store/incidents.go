@@ -0,0 +1,87 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+)
+
+// 장애 공지 심각도 (공개 상태 페이지의 전체 상태에 반영)
+const (
+	IncidentInfo     = "info"     // 안내만 (전체 상태는 그대로)
+	IncidentDegraded = "degraded" // 일부 기능 저하
+	IncidentOutage   = "outage"   // 서비스 중단
+)
+
+// Incident 관리자가 게시하는 장애/점검 공지
+type Incident struct {
+	ID         string     `json:"id"`
+	Title      string     `json:"title"`
+	Message    string     `json:"message"`
+	Severity   string     `json:"severity"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+const incidentColumns = `id, title, message, severity, created_at, updated_at, resolved_at`
+
+func scanIncident(row rowScanner) (*Incident, error) {
+	incident := &Incident{}
+	var resolvedAt sql.NullTime
+	err := row.Scan(&incident.ID, &incident.Title, &incident.Message, &incident.Severity,
+		&incident.CreatedAt, &incident.UpdatedAt, &resolvedAt)
+	if err != nil {
+		return nil, classify(err)
+	}
+	if resolvedAt.Valid {
+		incident.ResolvedAt = &resolvedAt.Time
+	}
+	return incident, nil
+}
+
+func (s *SQLiteStore) CreateIncident(incident *Incident) error {
+	_, err := s.db.Exec(`INSERT INTO incidents (`+incidentColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		incident.ID, incident.Title, incident.Message, incident.Severity,
+		incident.CreatedAt, incident.UpdatedAt, incident.ResolvedAt)
+	return classify(err)
+}
+
+// UpdateIncident 제목, 내용, 심각도, 해결 시각 갱신
+func (s *SQLiteStore) UpdateIncident(incident *Incident) error {
+	result, err := s.db.Exec(`UPDATE incidents SET title = ?, message = ?, severity = ?, updated_at = ?, resolved_at = ? WHERE id = ?`,
+		incident.Title, incident.Message, incident.Severity, incident.UpdatedAt, incident.ResolvedAt, incident.ID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errNoRows
+	}
+	return nil
+}
+
+func (s *SQLiteStore) GetIncident(id string) (*Incident, error) {
+	return scanIncident(s.db.QueryRow(`SELECT `+incidentColumns+` FROM incidents WHERE id = ?`, id))
+}
+
+// ListIncidents 최근 공지부터 (activeOnly면 해결되지 않은 공지만)
+func (s *SQLiteStore) ListIncidents(activeOnly bool) ([]*Incident, error) {
+	query := `SELECT ` + incidentColumns + ` FROM incidents`
+	if activeOnly {
+		query += ` WHERE resolved_at IS NULL`
+	}
+	rows, err := s.db.Query(query + ` ORDER BY created_at DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	incidents := []*Incident{}
+	for rows.Next() {
+		incident, err := scanIncident(rows)
+		if err != nil {
+			continue
+		}
+		incidents = append(incidents, incident)
+	}
+	return incidents, nil
+}
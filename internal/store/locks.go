@@ -0,0 +1,58 @@
+package store
+
+import (
+	"time"
+)
+
+// LockLease 분산 락 테이블의 현재 보유 정보
+type LockLease struct {
+	Name      string    `json:"name"`
+	Owner     string    `json:"owner"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// TryAcquireLock 락이 비어 있거나 만료되었거나 이미 owner가 보유 중이면 ttl만큼 임대하고 true 반환
+func (s *SQLiteStore) TryAcquireLock(name, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	query := `
+		INSERT INTO distributed_locks (name, owner, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT(name) DO UPDATE SET owner = excluded.owner, expires_at = excluded.expires_at
+		WHERE distributed_locks.expires_at < ? OR distributed_locks.owner = excluded.owner
+	`
+	result, err := s.db.Exec(query, name, owner, now.Add(ttl).UnixNano(), now.UnixNano())
+	if err != nil {
+		return false, err
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
+// ReleaseLock owner가 보유한 락만 해제
+func (s *SQLiteStore) ReleaseLock(name, owner string) error {
+	_, err := s.db.Exec(`DELETE FROM distributed_locks WHERE name = ? AND owner = ?`, name, owner)
+	return err
+}
+
+func (s *SQLiteStore) ListLocks() ([]*LockLease, error) {
+	rows, err := s.db.Query(`SELECT name, owner, expires_at FROM distributed_locks ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	leases := []*LockLease{}
+	for rows.Next() {
+		lease := &LockLease{}
+		var expiresAt int64
+		if err := rows.Scan(&lease.Name, &lease.Owner, &expiresAt); err != nil {
+			continue
+		}
+		lease.ExpiresAt = time.Unix(0, expiresAt)
+		leases = append(leases, lease)
+	}
+	return leases, nil
+}
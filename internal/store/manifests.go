@@ -0,0 +1,39 @@
+package store
+
+import "time"
+
+// SessionManifest 세션 생성 시 기록한 환경 매니페스트 (세션을 지운 뒤에도 인용/재현용으로 보관)
+type SessionManifest struct {
+	SessionID string    `json:"session_id"`
+	UserID    string    `json:"user_id"`
+	ProjectID string    `json:"project_id,omitempty"`
+	Version   int       `json:"version"`
+	Payload   []byte    `json:"-"`                   // 서명한 JSON 본문 바이트 그대로
+	Signature string    `json:"signature,omitempty"` // base64 ed25519 서명 (서명 키가 없으면 빈 값)
+	KeyID     string    `json:"key_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s *SQLiteStore) SaveSessionManifest(m *SessionManifest) error {
+	_, err := s.db.Exec(`
+		INSERT INTO session_manifests (session_id, user_id, project_id, version, payload, signature, key_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET version = excluded.version, payload = excluded.payload,
+			signature = excluded.signature, key_id = excluded.key_id, created_at = excluded.created_at
+	`, m.SessionID, m.UserID, m.ProjectID, m.Version, string(m.Payload), m.Signature, m.KeyID, m.CreatedAt)
+	return err
+}
+
+func (s *SQLiteStore) GetSessionManifest(sessionID string) (*SessionManifest, error) {
+	m := &SessionManifest{}
+	var payload string
+	err := s.db.QueryRow(`
+		SELECT session_id, user_id, project_id, version, payload, signature, key_id, created_at
+		FROM session_manifests WHERE session_id = ?
+	`, sessionID).Scan(&m.SessionID, &m.UserID, &m.ProjectID, &m.Version, &payload, &m.Signature, &m.KeyID, &m.CreatedAt)
+	if err != nil {
+		return nil, classify(err)
+	}
+	m.Payload = []byte(payload)
+	return m, nil
+}
@@ -0,0 +1,77 @@
+package store
+
+import (
+	"time"
+)
+
+// MIGReservation 세션이 끝난 사용자의 이전 MIG 인스턴스에 대한 소프트 예약
+// 유예 시간 안에 같은 사용자가 새 세션을 만들면 같은 노드의 같은 인스턴스를 우선 배정 (다른 사용자를 막지는 않음)
+type MIGReservation struct {
+	UserID     string    `json:"user_id"`
+	NodeID     string    `json:"node_id,omitempty"`
+	GPUUUID    string    `json:"gpu_uuid"`
+	GPUName    string    `json:"gpu_name,omitempty"`
+	MIGProfile string    `json:"mig_profile"`
+	SessionID  string    `json:"session_id"` // 인스턴스를 쓰던 이전 세션
+	ReleasedAt time.Time `json:"released_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+const migReservationColumns = `user_id, node_id, gpu_uuid, gpu_name, mig_profile, session_id, released_at, expires_at`
+
+func scanMIGReservation(row rowScanner) (*MIGReservation, error) {
+	r := &MIGReservation{}
+	err := row.Scan(&r.UserID, &r.NodeID, &r.GPUUUID, &r.GPUName, &r.MIGProfile, &r.SessionID, &r.ReleasedAt, &r.ExpiresAt)
+	if err != nil {
+		return nil, classify(err)
+	}
+	return r, nil
+}
+
+// SaveMIGReservation 사용자의 예약을 새 값으로 교체 (사용자당 하나)
+func (s *SQLiteStore) SaveMIGReservation(r *MIGReservation) error {
+	_, err := s.db.Exec(`INSERT INTO mig_reservations (`+migReservationColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET node_id = excluded.node_id, gpu_uuid = excluded.gpu_uuid, gpu_name = excluded.gpu_name,
+			mig_profile = excluded.mig_profile, session_id = excluded.session_id, released_at = excluded.released_at, expires_at = excluded.expires_at`,
+		r.UserID, r.NodeID, r.GPUUUID, r.GPUName, r.MIGProfile, r.SessionID, r.ReleasedAt, r.ExpiresAt)
+	return classify(err)
+}
+
+// GetMIGReservation 사용자의 유효한 예약 (없거나 만료됐으면 ErrNotFound)
+func (s *SQLiteStore) GetMIGReservation(userID string, now time.Time) (*MIGReservation, error) {
+	return scanMIGReservation(s.db.QueryRow(`SELECT `+migReservationColumns+` FROM mig_reservations WHERE user_id = ? AND expires_at > ?`, userID, now))
+}
+
+// ListMIGReservations 노드의 유효한 예약 (다른 사용자 배치에서 예약된 인스턴스를 뒤로 미루는 데 사용)
+func (s *SQLiteStore) ListMIGReservations(nodeID string, now time.Time) ([]*MIGReservation, error) {
+	rows, err := s.db.Query(`SELECT `+migReservationColumns+` FROM mig_reservations WHERE node_id = ? AND expires_at > ? ORDER BY released_at`, nodeID, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reservations := []*MIGReservation{}
+	for rows.Next() {
+		r, err := scanMIGReservation(rows)
+		if err != nil {
+			continue
+		}
+		reservations = append(reservations, r)
+	}
+	return reservations, nil
+}
+
+// DeleteMIGReservation 예약 삭제 (사용했거나 더 이상 의미가 없을 때, 없어도 오류 아님)
+func (s *SQLiteStore) DeleteMIGReservation(userID string) error {
+	_, err := s.db.Exec(`DELETE FROM mig_reservations WHERE user_id = ?`, userID)
+	return err
+}
+
+// PurgeMIGReservations 만료된 예약 정리
+func (s *SQLiteStore) PurgeMIGReservations(now time.Time) (int64, error) {
+	result, err := s.db.Exec(`DELETE FROM mig_reservations WHERE expires_at <= ?`, now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
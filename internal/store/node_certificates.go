@@ -0,0 +1,131 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+)
+
+// NodeCertificate 노드 에이전트에게 발급한 mTLS 인증서 기록 (인증서 자체가 아닌 폐기/감사용 정보)
+type NodeCertificate struct {
+	Serial      string     `json:"serial"` // 16진수
+	NodeID      string     `json:"node_id"`
+	SPIFFEID    string     `json:"spiffe_id"`
+	Fingerprint string     `json:"fingerprint"`
+	Kind        string     `json:"kind"` // enroll, renew
+	NotAfter    time.Time  `json:"not_after"`
+	CreatedAt   time.Time  `json:"created_at"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+}
+
+// NodeJoinToken 노드가 첫 인증서를 받을 때 쓰는 일회용 토큰 (해시만 저장)
+type NodeJoinToken struct {
+	TokenHash string
+	NodeID    string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+const nodeCertificateColumns = `serial, node_id, spiffe_id, fingerprint, kind, not_after, created_at, revoked_at`
+
+func scanNodeCertificate(row rowScanner) (*NodeCertificate, error) {
+	cert := &NodeCertificate{}
+	var revokedAt sql.NullTime
+	if err := row.Scan(&cert.Serial, &cert.NodeID, &cert.SPIFFEID, &cert.Fingerprint, &cert.Kind,
+		&cert.NotAfter, &cert.CreatedAt, &revokedAt); err != nil {
+		return nil, classify(err)
+	}
+	if revokedAt.Valid {
+		cert.RevokedAt = &revokedAt.Time
+	}
+	return cert, nil
+}
+
+func (s *SQLiteStore) CreateNodeCertificate(cert *NodeCertificate) error {
+	_, err := s.db.Exec(`INSERT INTO node_certificates (`+nodeCertificateColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, NULL)`,
+		cert.Serial, cert.NodeID, cert.SPIFFEID, cert.Fingerprint, cert.Kind, cert.NotAfter, cert.CreatedAt)
+	return classify(err)
+}
+
+func (s *SQLiteStore) GetNodeCertificate(serial string) (*NodeCertificate, error) {
+	return scanNodeCertificate(s.db.QueryRow(`SELECT `+nodeCertificateColumns+` FROM node_certificates WHERE serial = ?`, serial))
+}
+
+// ListNodeCertificates nodeID가 비어 있으면 모든 노드의 인증서를 반환
+func (s *SQLiteStore) ListNodeCertificates(nodeID string) ([]*NodeCertificate, error) {
+	query := `SELECT ` + nodeCertificateColumns + ` FROM node_certificates`
+	args := []interface{}{}
+	if nodeID != "" {
+		query += ` WHERE node_id = ?`
+		args = append(args, nodeID)
+	}
+	query += ` ORDER BY created_at DESC`
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	certs := []*NodeCertificate{}
+	for rows.Next() {
+		cert, err := scanNodeCertificate(rows)
+		if err != nil {
+			continue
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// RevokeNodeCertificates 노드의 아직 폐기되지 않은 인증서를 모두 폐기하고 폐기한 수를 반환
+func (s *SQLiteStore) RevokeNodeCertificates(nodeID string, at time.Time) (int64, error) {
+	result, err := s.db.Exec(`UPDATE node_certificates SET revoked_at = ? WHERE node_id = ? AND revoked_at IS NULL`, at, nodeID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// PurgeNodeCertificates before 이전에 만료된 인증서 기록 삭제
+func (s *SQLiteStore) PurgeNodeCertificates(before time.Time) (int64, error) {
+	result, err := s.db.Exec(`DELETE FROM node_certificates WHERE not_after < ?`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (s *SQLiteStore) CreateNodeJoinToken(token *NodeJoinToken) error {
+	_, err := s.db.Exec(`INSERT INTO node_join_tokens (token_hash, node_id, created_at, expires_at) VALUES (?, ?, ?, ?)`,
+		token.TokenHash, token.NodeID, token.CreatedAt, token.ExpiresAt)
+	return classify(err)
+}
+
+// TakeNodeJoinToken 토큰을 조회하면서 삭제 (동시에 두 번 사용되지 않도록 한 트랜잭션에서 처리)
+func (s *SQLiteStore) TakeNodeJoinToken(tokenHash string) (*NodeJoinToken, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	token := &NodeJoinToken{}
+	err = tx.QueryRow(`SELECT token_hash, node_id, created_at, expires_at FROM node_join_tokens WHERE token_hash = ?`, tokenHash).
+		Scan(&token.TokenHash, &token.NodeID, &token.CreatedAt, &token.ExpiresAt)
+	if err != nil {
+		return nil, classify(err)
+	}
+	if _, err := tx.Exec(`DELETE FROM node_join_tokens WHERE token_hash = ?`, tokenHash); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// DeleteNodeJoinTokens 노드의 사용하지 않은 가입 토큰 삭제
+func (s *SQLiteStore) DeleteNodeJoinTokens(nodeID string) error {
+	_, err := s.db.Exec(`DELETE FROM node_join_tokens WHERE node_id = ?`, nodeID)
+	return err
+}
@@ -0,0 +1,210 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+const (
+	NodeReady       = "ready"
+	NodeUnreachable = "unreachable" // 하트비트가 끊겨 스케줄링 대상에서 제외됨
+	NodeDraining    = "draining"    // 새 세션을 받지 않고 기존 세션이 끝나기를 기다림
+	NodeDrained     = "drained"     // 드레인 완료, 세션이 없어 제거 가능
+)
+
+// Node 세션 컨테이너를 실행하는 GPU 호스트 (에이전트가 스스로 등록하고 주기적으로 하트비트)
+type Node struct {
+	ID            string         `json:"id"`
+	Hostname      string         `json:"hostname"`
+	Address       string         `json:"address,omitempty"`
+	Version       string         `json:"version,omitempty"`
+	DriverVersion string         `json:"driver_version,omitempty"`
+	CUDAVersion   string         `json:"cuda_version,omitempty"`
+	NVMLVersion   string         `json:"nvml_version,omitempty"`
+	Capabilities  []string       `json:"capabilities"`
+	GPUs          []NodeGPU      `json:"gpus"`
+	Capacity      map[string]int `json:"capacity"` // MIG 프로파일별 남은 인스턴스 수
+	Status        string         `json:"status"`
+	RegisteredAt  time.Time      `json:"registered_at"`
+	LastHeartbeat time.Time      `json:"last_heartbeat"`
+
+	DrainPolicy    string     `json:"drain_policy,omitempty"`
+	DrainStartedAt *time.Time `json:"drain_started_at,omitempty"`
+	DrainDeadline  *time.Time `json:"drain_deadline,omitempty"` // 이 시각까지 남은 세션을 만료시킴 (ttl 정책이면 없음)
+
+	ClockOffsetMs int64  `json:"clock_offset_ms"`         // 노드가 보고한 가장 큰 시계 차이 (컨테이너-호스트 또는 노드-오케스트레이터)
+	ClockWarning  string `json:"clock_warning,omitempty"` // 허용 범위를 넘은 시계 차이나 NTP 미동기화
+}
+
+// NodeGPU 노드가 보고한 GPU 인벤토리
+type NodeGPU struct {
+	Index        int    `json:"index"`
+	UUID         string `json:"uuid"`
+	Name         string `json:"name"`
+	MemoryTotal  uint64 `json:"memory_total"`
+	MIGEnabled   bool   `json:"mig_enabled"`
+	MIGMode      string `json:"mig_mode,omitempty"`         // nvidia-smi가 보고한 현재 MIG 모드
+	MIGPending   string `json:"mig_mode_pending,omitempty"` // GPU 재설정 후 적용될 MIG 모드
+	MIGInstances int    `json:"mig_instances"`
+	MIGAvailable int    `json:"mig_available"`
+}
+
+const nodeColumns = `id, hostname, address, version, driver_version, capabilities, gpus, capacity, status, registered_at, last_heartbeat,
+	drain_policy, drain_started_at, drain_deadline, clock_offset_ms, clock_warning, cuda_version, nvml_version`
+
+func scanNode(row rowScanner) (*Node, error) {
+	node := &Node{}
+	var capabilities, gpus, capacity string
+	var drainStartedAt, drainDeadline sql.NullTime
+	err := row.Scan(&node.ID, &node.Hostname, &node.Address, &node.Version, &node.DriverVersion,
+		&capabilities, &gpus, &capacity, &node.Status, &node.RegisteredAt, &node.LastHeartbeat,
+		&node.DrainPolicy, &drainStartedAt, &drainDeadline, &node.ClockOffsetMs, &node.ClockWarning, &node.CUDAVersion, &node.NVMLVersion)
+	if err != nil {
+		return nil, classify(err)
+	}
+	if drainStartedAt.Valid {
+		node.DrainStartedAt = &drainStartedAt.Time
+	}
+	if drainDeadline.Valid {
+		node.DrainDeadline = &drainDeadline.Time
+	}
+
+	json.Unmarshal([]byte(capabilities), &node.Capabilities)
+	json.Unmarshal([]byte(gpus), &node.GPUs)
+	json.Unmarshal([]byte(capacity), &node.Capacity)
+	if node.Capabilities == nil {
+		node.Capabilities = []string{}
+	}
+	if node.GPUs == nil {
+		node.GPUs = []NodeGPU{}
+	}
+	return node, nil
+}
+
+// RegisterNode 노드 등록 (이미 있으면 인벤토리를 갱신하고 ready로 되돌림, 최초 등록 시각과 드레인 상태는 유지)
+func (s *SQLiteStore) RegisterNode(node *Node) error {
+	capabilities, _ := json.Marshal(node.Capabilities)
+	gpus, _ := json.Marshal(node.GPUs)
+	capacity, _ := json.Marshal(node.Capacity)
+
+	query := `
+		INSERT INTO nodes (` + nodeColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, '', NULL, NULL, 0, '', ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			hostname = excluded.hostname, address = excluded.address, version = excluded.version,
+			driver_version = excluded.driver_version, cuda_version = excluded.cuda_version,
+			nvml_version = excluded.nvml_version, capabilities = excluded.capabilities,
+			gpus = excluded.gpus, capacity = excluded.capacity,
+			status = CASE WHEN nodes.status IN ('draining', 'drained') THEN nodes.status ELSE excluded.status END,
+			last_heartbeat = excluded.last_heartbeat
+	`
+	_, err := s.db.Exec(query, node.ID, node.Hostname, node.Address, node.Version, node.DriverVersion,
+		string(capabilities), string(gpus), string(capacity), node.Status, node.RegisteredAt, node.LastHeartbeat,
+		node.CUDAVersion, node.NVMLVersion)
+	return err
+}
+
+// HeartbeatNode 하트비트 시각과 인벤토리 갱신 (등록되지 않은 노드면 ErrNotFound)
+func (s *SQLiteStore) HeartbeatNode(id string, gpus []NodeGPU, capacity map[string]int, at time.Time) error {
+	gpusJSON, _ := json.Marshal(gpus)
+	capacityJSON, _ := json.Marshal(capacity)
+
+	result, err := s.db.Exec(`UPDATE nodes SET gpus = ?, capacity = ?, last_heartbeat = ? WHERE id = ?`,
+		string(gpusJSON), string(capacityJSON), at, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errNoRows
+	}
+	return nil
+}
+
+// SetNodeDriver 노드가 하트비트로 보고한 드라이버/CUDA/NVML 버전 기록 (재등록 없이 드라이버 업그레이드 반영)
+func (s *SQLiteStore) SetNodeDriver(id, driverVersion, cudaVersion, nvmlVersion string) error {
+	result, err := s.db.Exec(`UPDATE nodes SET driver_version = ?, cuda_version = ?, nvml_version = ? WHERE id = ?`,
+		driverVersion, cudaVersion, nvmlVersion, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errNoRows
+	}
+	return nil
+}
+
+// SetNodeClock 노드가 보고한 시계 차이와 경고 기록
+func (s *SQLiteStore) SetNodeClock(id string, offsetMs int64, warning string) error {
+	result, err := s.db.Exec(`UPDATE nodes SET clock_offset_ms = ?, clock_warning = ? WHERE id = ?`, offsetMs, warning, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errNoRows
+	}
+	return nil
+}
+
+func (s *SQLiteStore) GetNode(id string) (*Node, error) {
+	return scanNode(s.db.QueryRow(`SELECT `+nodeColumns+` FROM nodes WHERE id = ?`, id))
+}
+
+func (s *SQLiteStore) ListNodes() ([]*Node, error) {
+	rows, err := s.db.Query(`SELECT ` + nodeColumns + ` FROM nodes ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	nodes := []*Node{}
+	for rows.Next() {
+		node, err := scanNode(rows)
+		if err != nil {
+			continue
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// SetNodeStatus 노드 상태 변경 (from이 비어 있지 않으면 현재 상태가 from일 때만 변경, 변경 여부 반환)
+func (s *SQLiteStore) SetNodeStatus(id, from, to string) (bool, error) {
+	var (
+		result sql.Result
+		err    error
+	)
+	if from == "" {
+		result, err = s.db.Exec(`UPDATE nodes SET status = ? WHERE id = ?`, to, id)
+	} else {
+		result, err = s.db.Exec(`UPDATE nodes SET status = ? WHERE id = ? AND status = ?`, to, id, from)
+	}
+	if err != nil {
+		return false, err
+	}
+	n, _ := result.RowsAffected()
+	return n > 0, nil
+}
+
+// SetNodeDrain 드레인 시작/취소 (status와 드레인 정보를 함께 갱신)
+func (s *SQLiteStore) SetNodeDrain(id, status, policy string, startedAt, deadline *time.Time) error {
+	result, err := s.db.Exec(`UPDATE nodes SET status = ?, drain_policy = ?, drain_started_at = ?, drain_deadline = ? WHERE id = ?`,
+		status, policy, startedAt, deadline, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errNoRows
+	}
+	return nil
+}
+
+func (s *SQLiteStore) DeleteNode(id string) error {
+	result, err := s.db.Exec(`DELETE FROM nodes WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errNoRows
+	}
+	return nil
+}
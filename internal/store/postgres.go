@@ -0,0 +1,82 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+)
+
+// postgresMigrationLock 복제본이 동시에 시작할 때 스키마 변경을 한 번에 하나씩 하도록 잡는 advisory 락 키
+const postgresMigrationLock = 0x73616e646d616e // "sandman"
+
+// PoolOptions PostgreSQL 연결 풀 설정
+type PoolOptions struct {
+	MaxOpenConns    int           // 0이면 제한 없음
+	MaxIdleConns    int           // 유휴 연결 보관 수
+	ConnMaxLifetime time.Duration // 이 시간이 지난 연결은 닫고 새로 엶 (로드밸런서/장애 조치 뒤 연결 정리, 0이면 무제한)
+}
+
+// DefaultPoolOptions 복제본 하나의 기본 연결 풀 (복제본 수 × MaxOpenConns가 서버의 max_connections를 넘지 않게 조정)
+var DefaultPoolOptions = PoolOptions{
+	MaxOpenConns:    20,
+	MaxIdleConns:    10,
+	ConnMaxLifetime: 30 * time.Minute,
+}
+
+// PostgresStore 여러 오케스트레이터 복제본이 함께 쓰는 PostgreSQL 저장소
+// 쿼리와 스키마는 SQLiteStore와 같은 것을 쓰고 실행 직전에 PostgreSQL 문법으로 변환
+type PostgresStore struct {
+	*SQLiteStore
+}
+
+// NewPostgresStore dsn(postgres://... 또는 key=value 형식)에 연결하고 스키마를 맞춤
+// 비밀번호는 dsn 대신 PGPASSWORD 같은 libpq 환경 변수로 줄 수 있음
+func NewPostgresStore(dsn string) (*PostgresStore, error) {
+	return OpenPostgresStore(dsn, SchemaMigrate)
+}
+
+// OpenPostgresStore 스키마 버전을 확인하고 정책에 따라 스키마를 올린 뒤 PostgreSQL 저장소를 엶
+func OpenPostgresStore(dsn string, policy SchemaPolicy) (*PostgresStore, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("PostgreSQL 연결 실패: %v", err)
+	}
+
+	store := &PostgresStore{SQLiteStore: &SQLiteStore{db: &conn{DB: db, dialect: dialectPostgres}}}
+	if err := store.negotiateSchema(policy); err != nil {
+		db.Close()
+		return nil, err
+	}
+	store.SetPool(DefaultPoolOptions)
+	return store, nil
+}
+
+// negotiateSchema 연결 하나로 advisory 락을 잡은 채 스키마 버전을 확인하고 맞춤 (동시에 시작한 복제본의 CREATE TABLE 충돌 방지)
+func (s *PostgresStore) negotiateSchema(policy SchemaPolicy) error {
+	// 락과 스키마 변경이 같은 세션에서 실행되도록 풀을 연결 하나로 제한
+	s.db.SetMaxOpenConns(1)
+	if _, err := s.db.Exec(`SELECT pg_advisory_lock(?)`, postgresMigrationLock); err != nil {
+		return fmt.Errorf("스키마 변경 락 획득 실패: %v", err)
+	}
+	defer s.db.Exec(`SELECT pg_advisory_unlock(?)`, postgresMigrationLock)
+	return s.SQLiteStore.negotiateSchema(policy)
+}
+
+// SetPool 연결 풀 설정 변경
+func (s *PostgresStore) SetPool(opts PoolOptions) {
+	s.db.SetMaxOpenConns(opts.MaxOpenConns)
+	s.db.SetMaxIdleConns(opts.MaxIdleConns)
+	s.db.SetConnMaxLifetime(opts.ConnMaxLifetime)
+}
+
+// Backup PostgreSQL은 파일 스냅샷을 만들 수 없으므로 pg_dump나 서버의 백업 기능을 사용
+func (s *PostgresStore) Backup(path string) error {
+	return errs.New(errs.ErrInvalid, "PostgreSQL 저장소는 파일로 백업할 수 없습니다 (pg_dump를 사용하세요)")
+}
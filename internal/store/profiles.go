@@ -0,0 +1,129 @@
+package store
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Profile 관리자가 정의한 제공 프로파일 (정의가 하나라도 있으면 활성화된 정의만 세션 생성에 쓸 수 있음)
+type Profile struct {
+	Name              string            `json:"name"` // MIG 프로파일 이름 (예: 3g.20gb)
+	DisplayName       string            `json:"display_name"`
+	Description       string            `json:"description,omitempty"`
+	Enabled           bool              `json:"enabled"`
+	Resources         *ProfileResources `json:"resources,omitempty"` // 없으면 설정 파일의 resource_classes
+	PricePerHour      float64           `json:"price_per_hour"`      // 안내용 시간당 가격 (통화는 배포 규칙)
+	DefaultTTLMinutes int               `json:"default_ttl_minutes,omitempty"`
+	MaxTTLMinutes     int               `json:"max_ttl_minutes,omitempty"`
+	CreatedAt         time.Time         `json:"created_at"`
+	UpdatedAt         time.Time         `json:"updated_at"`
+}
+
+// ProfileResources 프로파일 세션 컨테이너의 CPU/메모리 (resource_classes 항목과 같은 의미)
+type ProfileResources struct {
+	CPUReservation      float64 `json:"cpu_reservation"`
+	CPULimit            float64 `json:"cpu_limit"`
+	MemoryReservationMB int64   `json:"memory_reservation_mb"`
+	MemoryLimitMB       int64   `json:"memory_limit_mb"`
+	MaxCPULimit         float64 `json:"max_cpu_limit,omitempty"`
+	MaxMemoryLimitMB    int64   `json:"max_memory_limit_mb,omitempty"`
+}
+
+const profileColumns = `name, display_name, description, enabled, resources, price_per_hour, default_ttl_minutes, max_ttl_minutes, created_at, updated_at`
+
+func scanProfile(row rowScanner) (*Profile, error) {
+	p := &Profile{}
+	var enabled int
+	var resources string
+	err := row.Scan(&p.Name, &p.DisplayName, &p.Description, &enabled, &resources, &p.PricePerHour, &p.DefaultTTLMinutes, &p.MaxTTLMinutes, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, classify(err)
+	}
+	p.Enabled = enabled != 0
+	if resources != "" {
+		p.Resources = &ProfileResources{}
+		if err := json.Unmarshal([]byte(resources), p.Resources); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+func (s *SQLiteStore) GetProfile(name string) (*Profile, error) {
+	return scanProfile(s.db.QueryRow(`SELECT `+profileColumns+` FROM profiles WHERE name = ?`, name))
+}
+
+func (s *SQLiteStore) ListProfiles() ([]*Profile, error) {
+	rows, err := s.db.Query(`SELECT ` + profileColumns + ` FROM profiles ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	profiles := []*Profile{}
+	for rows.Next() {
+		p, err := scanProfile(rows)
+		if err != nil {
+			continue
+		}
+		profiles = append(profiles, p)
+	}
+	return profiles, nil
+}
+
+func (s *SQLiteStore) CreateProfile(p *Profile) error {
+	resources, enabled, err := profileValues(p)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.Exec(`INSERT INTO profiles (`+profileColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		p.Name, p.DisplayName, p.Description, enabled, resources, p.PricePerHour, p.DefaultTTLMinutes, p.MaxTTLMinutes, p.CreatedAt, p.UpdatedAt)
+	return classify(err)
+}
+
+func (s *SQLiteStore) UpdateProfile(p *Profile) error {
+	resources, enabled, err := profileValues(p)
+	if err != nil {
+		return err
+	}
+	result, err := s.db.Exec(`
+		UPDATE profiles SET display_name = ?, description = ?, enabled = ?, resources = ?, price_per_hour = ?,
+			default_ttl_minutes = ?, max_ttl_minutes = ?, updated_at = ?
+		WHERE name = ?`,
+		p.DisplayName, p.Description, enabled, resources, p.PricePerHour, p.DefaultTTLMinutes, p.MaxTTLMinutes, p.UpdatedAt, p.Name)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errNoRows
+	}
+	return nil
+}
+
+func (s *SQLiteStore) DeleteProfile(name string) error {
+	result, err := s.db.Exec(`DELETE FROM profiles WHERE name = ?`, name)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errNoRows
+	}
+	return nil
+}
+
+// profileValues 컬럼에 저장할 리소스 JSON과 활성 여부 (불리언은 두 저장소 모두 정수로 저장)
+func profileValues(p *Profile) (string, int, error) {
+	resources := ""
+	if p.Resources != nil {
+		data, err := json.Marshal(p.Resources)
+		if err != nil {
+			return "", 0, err
+		}
+		resources = string(data)
+	}
+	enabled := 0
+	if p.Enabled {
+		enabled = 1
+	}
+	return resources, enabled, nil
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"time"
+)
+
+// Organization 프로젝트들을 묶는 최상위 조직
+type Organization struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Project 세션과 API 토큰이 속하는 단위
+type Project struct {
+	ID        string    `json:"id"`
+	OrgID     string    `json:"org_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s *SQLiteStore) CreateOrganization(org *Organization) error {
+	query := `INSERT INTO organizations (id, name, created_at) VALUES (?, ?, ?)`
+	_, err := s.db.Exec(query, org.ID, org.Name, org.CreatedAt)
+	return classify(err)
+}
+
+func (s *SQLiteStore) GetOrganization(id string) (*Organization, error) {
+	query := `SELECT id, name, created_at FROM organizations WHERE id = ?`
+
+	org := &Organization{}
+	if err := s.db.QueryRow(query, id).Scan(&org.ID, &org.Name, &org.CreatedAt); err != nil {
+		return nil, classify(err)
+	}
+	return org, nil
+}
+
+func (s *SQLiteStore) ListOrganizations() ([]*Organization, error) {
+	rows, err := s.db.Query(`SELECT id, name, created_at FROM organizations ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	orgs := []*Organization{}
+	for rows.Next() {
+		org := &Organization{}
+		if err := rows.Scan(&org.ID, &org.Name, &org.CreatedAt); err != nil {
+			continue
+		}
+		orgs = append(orgs, org)
+	}
+	return orgs, nil
+}
+
+func (s *SQLiteStore) DeleteOrganization(id string) error {
+	_, err := s.db.Exec(`DELETE FROM organizations WHERE id = ?`, id)
+	return err
+}
+
+func (s *SQLiteStore) CreateProject(project *Project) error {
+	query := `INSERT INTO projects (id, org_id, name, created_at) VALUES (?, ?, ?, ?)`
+	_, err := s.db.Exec(query, project.ID, project.OrgID, project.Name, project.CreatedAt)
+	return classify(err)
+}
+
+func (s *SQLiteStore) GetProject(id string) (*Project, error) {
+	query := `SELECT id, org_id, name, created_at FROM projects WHERE id = ?`
+
+	project := &Project{}
+	if err := s.db.QueryRow(query, id).Scan(&project.ID, &project.OrgID, &project.Name, &project.CreatedAt); err != nil {
+		return nil, classify(err)
+	}
+	return project, nil
+}
+
+// ListProjects orgID가 비어 있으면 전체 프로젝트를 반환
+func (s *SQLiteStore) ListProjects(orgID string) ([]*Project, error) {
+	query := `SELECT id, org_id, name, created_at FROM projects`
+	args := []interface{}{}
+	if orgID != "" {
+		query += ` WHERE org_id = ?`
+		args = append(args, orgID)
+	}
+	query += ` ORDER BY name`
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	projects := []*Project{}
+	for rows.Next() {
+		project := &Project{}
+		if err := rows.Scan(&project.ID, &project.OrgID, &project.Name, &project.CreatedAt); err != nil {
+			continue
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+func (s *SQLiteStore) DeleteProject(id string) error {
+	_, err := s.db.Exec(`DELETE FROM projects WHERE id = ?`, id)
+	return err
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"time"
+)
+
+// Quota 사용자("user:<id>") 또는 팀("team:<id>")의 한도 (0은 제한 없음)
+type Quota struct {
+	Subject       string    `json:"subject"`
+	MaxSessions   int       `json:"max_sessions"`    // 동시 세션 수
+	MaxGPUSlices  int       `json:"max_gpu_slices"`  // 활성 세션이 점유한 MIG 컴퓨트 슬라이스 합 (3g.40gb는 3)
+	MaxTTLMinutes int       `json:"max_ttl_minutes"` // 새 세션 하나의 TTL
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+const quotaColumns = `subject, max_sessions, max_gpu_slices, max_ttl_minutes, updated_at`
+
+func scanQuota(row rowScanner) (*Quota, error) {
+	q := &Quota{}
+	if err := row.Scan(&q.Subject, &q.MaxSessions, &q.MaxGPUSlices, &q.MaxTTLMinutes, &q.UpdatedAt); err != nil {
+		return nil, classify(err)
+	}
+	return q, nil
+}
+
+func (s *SQLiteStore) GetQuota(subject string) (*Quota, error) {
+	return scanQuota(s.db.QueryRow(`SELECT `+quotaColumns+` FROM quotas WHERE subject = ?`, subject))
+}
+
+func (s *SQLiteStore) ListQuotas() ([]*Quota, error) {
+	rows, err := s.db.Query(`SELECT ` + quotaColumns + ` FROM quotas ORDER BY subject`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	quotas := []*Quota{}
+	for rows.Next() {
+		q, err := scanQuota(rows)
+		if err != nil {
+			continue
+		}
+		quotas = append(quotas, q)
+	}
+	return quotas, nil
+}
+
+// SaveQuota 한도 추가 또는 전체 교체
+func (s *SQLiteStore) SaveQuota(q *Quota) error {
+	_, err := s.db.Exec(`
+		INSERT INTO quotas (`+quotaColumns+`) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(subject) DO UPDATE SET
+			max_sessions = excluded.max_sessions,
+			max_gpu_slices = excluded.max_gpu_slices,
+			max_ttl_minutes = excluded.max_ttl_minutes,
+			updated_at = excluded.updated_at
+	`, q.Subject, q.MaxSessions, q.MaxGPUSlices, q.MaxTTLMinutes, q.UpdatedAt)
+	return err
+}
+
+func (s *SQLiteStore) DeleteQuota(subject string) error {
+	result, err := s.db.Exec(`DELETE FROM quotas WHERE subject = ?`, subject)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errNoRows
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"time"
+)
+
+// RestorePoint 사용자 워크스페이스의 특정 시점 스냅샷 (세션이 끝난 뒤에도 새 세션으로 복원 가능)
+type RestorePoint struct {
+	ID        string    `json:"id"`
+	SessionID string    `json:"session_id"`
+	UserID    string    `json:"user_id"`
+	ProjectID string    `json:"project_id,omitempty"`
+	Reason    string    `json:"reason"` // scheduled, manual, pre-restore
+	Path      string    `json:"-"`
+	Files     int       `json:"files"`
+	Bytes     int64     `json:"bytes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+const restorePointColumns = `id, session_id, user_id, project_id, reason, path, files, bytes, created_at`
+
+func scanRestorePoint(row rowScanner) (*RestorePoint, error) {
+	rp := &RestorePoint{}
+	err := row.Scan(&rp.ID, &rp.SessionID, &rp.UserID, &rp.ProjectID, &rp.Reason, &rp.Path, &rp.Files, &rp.Bytes, &rp.CreatedAt)
+	if err != nil {
+		return nil, classify(err)
+	}
+	return rp, nil
+}
+
+func (s *SQLiteStore) CreateRestorePoint(rp *RestorePoint) error {
+	_, err := s.db.Exec(`INSERT INTO restore_points (`+restorePointColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		rp.ID, rp.SessionID, rp.UserID, rp.ProjectID, rp.Reason, rp.Path, rp.Files, rp.Bytes, rp.CreatedAt)
+	return classify(err)
+}
+
+func (s *SQLiteStore) GetRestorePoint(id string) (*RestorePoint, error) {
+	return scanRestorePoint(s.db.QueryRow(`SELECT `+restorePointColumns+` FROM restore_points WHERE id = ?`, id))
+}
+
+// ListRestorePoints 세션에서 만들어진 복원 지점 (최신순)
+func (s *SQLiteStore) ListRestorePoints(sessionID string) ([]*RestorePoint, error) {
+	return s.queryRestorePoints(`SELECT `+restorePointColumns+` FROM restore_points WHERE session_id = ? ORDER BY created_at DESC`, sessionID)
+}
+
+// ListUserRestorePoints 사용자 워크스페이스의 모든 복원 지점 (최신순, 보존 개수 정리용)
+func (s *SQLiteStore) ListUserRestorePoints(userID string) ([]*RestorePoint, error) {
+	return s.queryRestorePoints(`SELECT `+restorePointColumns+` FROM restore_points WHERE user_id = ? ORDER BY created_at DESC`, userID)
+}
+
+func (s *SQLiteStore) DeleteRestorePoint(id string) error {
+	result, err := s.db.Exec(`DELETE FROM restore_points WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errNoRows
+	}
+	return nil
+}
+
+func (s *SQLiteStore) queryRestorePoints(query string, args ...interface{}) ([]*RestorePoint, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []*RestorePoint{}
+	for rows.Next() {
+		rp, err := scanRestorePoint(rows)
+		if err != nil {
+			continue
+		}
+		points = append(points, rp)
+	}
+	return points, nil
+}
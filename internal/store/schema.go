@@ -0,0 +1,140 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+)
+
+// 스키마 버전 계약 (롤링 업그레이드)
+//
+// 테이블/컬럼 추가처럼 이전 바이너리가 그대로 동작하는 변경은 migrate의 CREATE TABLE IF NOT EXISTS로
+// 현재 버전 안에서 처리하고 SchemaVersion을 올리지 않는다. 이전 바이너리가 깨지는 변경만 SchemaVersion을
+// 하나 올린다. 바이너리는 바로 이전 버전(SchemaVersion-1)의 스키마까지만 올릴 수 있고, 그보다 오래된
+// 스키마는 중간 릴리스로 먼저 올려야 한다 (버전 기록 이전의 스키마는 예외로 항상 그 자리에서 올림).
+// 스키마를 올린 바이너리는 schemaCompatVersion을 함께 기록하며, 이 값보다 오래된 바이너리는
+// 새 스키마에서 시작을 거부한다.
+const (
+	// SchemaVersion 이 바이너리의 migrate가 만드는 스키마 버전 (0은 버전 기록 이전의 스키마)
+	//   1: schema_info (node_certificates, node_join_tokens, session_waits, session_events 같은 테이블 추가는 버전을 올리지 않음)
+	SchemaVersion = 1
+	// schemaCompatVersion 이 바이너리가 만든 스키마에서 실행할 수 있는 가장 오래된 바이너리의 SchemaVersion
+	schemaCompatVersion = SchemaVersion - 1
+)
+
+// SchemaPolicy 시작할 때 저장소 스키마가 바이너리보다 오래되었으면 어떻게 할지
+type SchemaPolicy string
+
+const (
+	SchemaMigrate SchemaPolicy = "migrate" // 바로 이전 버전이면 시작하면서 올림 (기본값)
+	SchemaStrict  SchemaPolicy = "strict"  // 스키마를 바꾸지 않음 (미리 -migrate-only로 올려야 시작)
+)
+
+// ValidSchemaPolicy 알려진 정책인지
+func ValidSchemaPolicy(policy SchemaPolicy) bool {
+	return policy == SchemaMigrate || policy == SchemaStrict
+}
+
+// SchemaInfo 저장소에 기록된 스키마 버전
+type SchemaInfo struct {
+	Version       int       `json:"version"`
+	CompatVersion int       `json:"compat_version"` // 이 스키마에서 실행할 수 있는 가장 오래된 바이너리 버전
+	UpdatedAt     time.Time `json:"updated_at,omitempty"`
+	UpdatedBy     string    `json:"updated_by,omitempty"` // 스키마를 올린 호스트
+}
+
+// SchemaInfo 저장소의 스키마 버전 (버전 기록 이전의 스키마나 빈 저장소는 0)
+func (s *SQLiteStore) SchemaInfo() (*SchemaInfo, error) {
+	if _, err := s.db.Exec(s.db.dialect.ddl(`
+		CREATE TABLE IF NOT EXISTS schema_info (
+			id INTEGER PRIMARY KEY,
+			version INTEGER NOT NULL,
+			compat_version INTEGER NOT NULL,
+			updated_at DATETIME NOT NULL,
+			updated_by TEXT NOT NULL DEFAULT ''
+		)`)); err != nil {
+		return nil, err
+	}
+	info := &SchemaInfo{}
+	err := s.db.QueryRow(`SELECT version, compat_version, updated_at, updated_by FROM schema_info WHERE id = 1`).
+		Scan(&info.Version, &info.CompatVersion, &info.UpdatedAt, &info.UpdatedBy)
+	if err = classify(err); err != nil && !errors.Is(err, errs.ErrNotFound) {
+		return nil, err
+	}
+	return info, nil
+}
+
+// negotiateSchema 저장소 스키마와 이 바이너리가 함께 동작할 수 있는지 확인하고 정책에 따라 스키마를 올림
+func (s *SQLiteStore) negotiateSchema(policy SchemaPolicy) error {
+	info, err := s.SchemaInfo()
+	if err != nil {
+		return fmt.Errorf("스키마 버전 확인 실패: %v", err)
+	}
+
+	// 버전 기록이 없어도 테이블이 하나도 없으면 처음 만드는 저장소이므로 바로 현재 버전으로 만듦
+	fresh := info.Version == 0 && !s.hasTable("sessions")
+	// 테이블은 있는데 버전 기록이 없으면 버전 기록 이전(v0)의 저장소이며, 그 변경은 모두 테이블 추가라 그 자리에서 올릴 수 있음
+	legacy := info.Version == 0 && !fresh
+
+	switch {
+	case fresh:
+	case info.Version > SchemaVersion:
+		// 롤링 업그레이드 중 새 바이너리가 먼저 올린 스키마 (새 스키마가 허용하는 버전이면 그대로 실행)
+		if SchemaVersion < info.CompatVersion {
+			return errs.New(errs.ErrConflict, "저장소 스키마 v%d는 스키마 v%d 이상의 바이너리가 필요합니다 (이 바이너리: v%d)",
+				info.Version, info.CompatVersion, SchemaVersion)
+		}
+		log.Printf("📦 저장소 스키마 v%d가 이 바이너리(v%d)보다 새롭지만 호환됩니다", info.Version, SchemaVersion)
+		return nil
+	case legacy && policy == SchemaStrict:
+		return errs.New(errs.ErrConflict, "저장소에 스키마 버전 기록이 없습니다 (v0, -migrate-only로 먼저 올리거나 -schema-policy=%s 사용)", SchemaMigrate)
+	case legacy:
+	case info.Version < SchemaVersion-1:
+		return errs.New(errs.ErrConflict, "저장소 스키마 v%d는 이 바이너리(v%d)가 올릴 수 없습니다 (스키마 v%d 바이너리로 먼저 올리세요)",
+			info.Version, SchemaVersion, SchemaVersion-1)
+	case info.Version < SchemaVersion && policy == SchemaStrict:
+		return errs.New(errs.ErrConflict, "저장소 스키마 v%d가 이 바이너리(v%d)보다 오래되었습니다 (-migrate-only로 먼저 올리거나 -schema-policy=%s 사용)",
+			info.Version, SchemaVersion, SchemaMigrate)
+	case info.Version == SchemaVersion && policy == SchemaStrict:
+		return nil
+	}
+
+	if err := s.migrate(); err != nil {
+		return err
+	}
+	if info.Version == SchemaVersion {
+		return nil
+	}
+	host, _ := os.Hostname()
+	_, err = s.db.Exec(`
+		INSERT INTO schema_info (id, version, compat_version, updated_at, updated_by) VALUES (1, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			version = excluded.version,
+			compat_version = excluded.compat_version,
+			updated_at = excluded.updated_at,
+			updated_by = excluded.updated_by
+	`, SchemaVersion, schemaCompatVersion, time.Now(), host)
+	if err != nil {
+		return fmt.Errorf("스키마 버전 기록 실패: %v", err)
+	}
+	if fresh {
+		log.Printf("📦 새 저장소를 스키마 v%d로 만들었습니다", SchemaVersion)
+	} else {
+		log.Printf("📦 저장소 스키마를 v%d에서 v%d로 올렸습니다", info.Version, SchemaVersion)
+	}
+	return nil
+}
+
+// hasTable 테이블이 있는지 (SQLite와 PostgreSQL 모두에서 동작하도록 직접 조회해 봄)
+func (s *SQLiteStore) hasTable(name string) bool {
+	rows, err := s.db.Query(`SELECT 1 FROM ` + name + ` LIMIT 1`)
+	if err != nil {
+		return false
+	}
+	rows.Close()
+	return true
+}
@@ -0,0 +1,41 @@
+package store
+
+import (
+	"time"
+)
+
+// SessionEvent 세션 생애 주기 이벤트 (생성, 연장, 일시 중지, 만료, 삭제, 실패), 추가만 하고 고치지 않음
+type SessionEvent struct {
+	ID        int64     `json:"id"`
+	SessionID string    `json:"session_id"`
+	UserID    string    `json:"user_id"`
+	Type      string    `json:"type"`
+	Actor     string    `json:"actor"`
+	Reason    string    `json:"reason,omitempty"`
+	Detail    string    `json:"detail,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s *SQLiteStore) AppendSessionEvent(event *SessionEvent) error {
+	query := `INSERT INTO session_events (session_id, user_id, type, actor, reason, detail, created_at) VALUES (?, ?, ?, ?, ?, ?, ?) RETURNING id`
+	return s.db.QueryRow(query, event.SessionID, event.UserID, event.Type, event.Actor, event.Reason, event.Detail, event.CreatedAt).Scan(&event.ID)
+}
+
+// ListSessionEvents 세션의 이벤트 (기록 순, 세션이 삭제된 뒤에도 남음)
+func (s *SQLiteStore) ListSessionEvents(sessionID string) ([]*SessionEvent, error) {
+	rows, err := s.db.Query(`SELECT id, session_id, user_id, type, actor, reason, detail, created_at FROM session_events WHERE session_id = ? ORDER BY id`, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*SessionEvent{}
+	for rows.Next() {
+		event := &SessionEvent{}
+		if err := rows.Scan(&event.ID, &event.SessionID, &event.UserID, &event.Type, &event.Actor, &event.Reason, &event.Detail, &event.CreatedAt); err != nil {
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
@@ -0,0 +1,67 @@
+package store
+
+import (
+	"time"
+)
+
+// SessionWait 세션 생성 요청을 받은 때부터 세션이 실행될 때까지 걸린 시간 (세션이 끝나도 남음)
+type SessionWait struct {
+	SessionID   string    `json:"session_id"`
+	UserID      string    `json:"user_id"`
+	ProjectID   string    `json:"project_id,omitempty"`
+	Profile     string    `json:"profile"`
+	Tier        string    `json:"tier,omitempty"`
+	NodeID      string    `json:"node_id,omitempty"`
+	SubmittedAt time.Time `json:"submitted_at"`
+	RunningAt   time.Time `json:"running_at"`
+	WaitMs      int64     `json:"wait_ms"`
+}
+
+const sessionWaitColumns = `session_id, user_id, project_id, profile, tier, node_id, submitted_at, running_at, wait_ms`
+
+func scanSessionWait(row rowScanner) (*SessionWait, error) {
+	w := &SessionWait{}
+	err := row.Scan(&w.SessionID, &w.UserID, &w.ProjectID, &w.Profile, &w.Tier, &w.NodeID, &w.SubmittedAt, &w.RunningAt, &w.WaitMs)
+	if err != nil {
+		return nil, classify(err)
+	}
+	return w, nil
+}
+
+// SaveSessionWait 대기 시간 기록 저장 (같은 세션을 다시 기록하면 교체)
+func (s *SQLiteStore) SaveSessionWait(w *SessionWait) error {
+	_, err := s.db.Exec(`INSERT INTO session_waits (`+sessionWaitColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET user_id = excluded.user_id, project_id = excluded.project_id, profile = excluded.profile,
+			tier = excluded.tier, node_id = excluded.node_id, submitted_at = excluded.submitted_at, running_at = excluded.running_at,
+			wait_ms = excluded.wait_ms`,
+		w.SessionID, w.UserID, w.ProjectID, w.Profile, w.Tier, w.NodeID, w.SubmittedAt, w.RunningAt, w.WaitMs)
+	return classify(err)
+}
+
+// ListSessionWaits since 이후 실행된 세션의 대기 시간 (실행 시각 순)
+func (s *SQLiteStore) ListSessionWaits(since time.Time) ([]*SessionWait, error) {
+	rows, err := s.db.Query(`SELECT `+sessionWaitColumns+` FROM session_waits WHERE running_at >= ? ORDER BY running_at`, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	waits := []*SessionWait{}
+	for rows.Next() {
+		w, err := scanSessionWait(rows)
+		if err != nil {
+			continue
+		}
+		waits = append(waits, w)
+	}
+	return waits, nil
+}
+
+// PurgeSessionWaits before 이전에 실행된 기록 삭제
+func (s *SQLiteStore) PurgeSessionWaits(before time.Time) (int64, error) {
+	result, err := s.db.Exec(`DELETE FROM session_waits WHERE running_at < ?`, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -3,23 +3,51 @@ package store
 import (
 	"database/sql"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 )
 
+// 세션 상태
+const (
+	SessionRunning     = "running"
+	SessionFailed      = "failed"      // 크래시 루프 등으로 컨테이너를 멈춘 상태 (만료/삭제 전까지 GPU와 로그 보존)
+	SessionLocked      = "locked"      // 비활성으로 컨테이너를 일시 정지한 상태 (GPU 유지, SSH 연결이 들어오면 재개)
+	SessionPaused      = "paused"      // 사용자가 컨테이너를 멈춘 상태 (워크스페이스와 기록 유지, resume으로 다시 시작)
+	SessionStopped     = "stopped"     // 사용자가 컨테이너를 멈추고 GPU를 잠시 잡아 둔 상태 (start로 컨테이너를 다시 만듦)
+	SessionQuarantined = "quarantined" // 침해 의심으로 네트워크를 끊고 조사용으로 보존한 상태 (관리자가 해제할 때까지 정리 안 함)
+)
+
+// 세션 등급
+const (
+	TierStandard = "standard"
+	TierSpot     = "spot" // 남는 인스턴스를 싼 요율로 사용, standard 요청이 오면 회수될 수 있음
+)
+
 type Session struct {
 	ID          string            `json:"id"`
 	UserID      string            `json:"user_id"`
+	ProjectID   string            `json:"project_id,omitempty"`
+	NodeID      string            `json:"node_id,omitempty"`
 	ContainerID string            `json:"container_id"`
 	ContainerIP string            `json:"container_ip"`
 	SSHPort     int               `json:"ssh_port"`
 	GPUUUID     string            `json:"gpu_uuid"`
+	GPUName     string            `json:"gpu_name,omitempty"` // MIG 인스턴스 이름 (gpu0-slot4-3g.40gb)
 	MIGProfile  string            `json:"mig_profile"`
+	Priority    int               `json:"priority"`
+	Tier        string            `json:"tier"`
+	EvictAt     *time.Time        `json:"evict_at,omitempty"` // spot 회수 예고 시각 (이 시각에 만료되어 정리됨)
+	State       string            `json:"state"`
 	TTLMinutes  int               `json:"ttl_minutes"`
 	CreatedAt   time.Time         `json:"created_at"`
 	ExpiresAt   time.Time         `json:"expires_at"`
 	Metadata    map[string]string `json:"metadata"`
+
+	// Annotations 외부 시스템이 네임스페이스별로 붙이는 JSON (오케스트레이터는 해석하지 않음)
+	Annotations map[string]json.RawMessage `json:"annotations,omitempty"`
 }
 
 type Store interface {
@@ -30,21 +58,194 @@ type Store interface {
 	DeleteSession(id string) error
 	ListExpiredSessions() ([]*Session, error)
 	ListAllSessions() ([]*Session, error)
+	ListSessionsByProject(projectID string) ([]*Session, error)
+	PatchSessionAnnotations(sessionID string, patch map[string]json.RawMessage) (map[string]json.RawMessage, error)
+
+	CreateOrganization(org *Organization) error
+	GetOrganization(id string) (*Organization, error)
+	ListOrganizations() ([]*Organization, error)
+	DeleteOrganization(id string) error
+
+	CreateProject(project *Project) error
+	GetProject(id string) (*Project, error)
+	ListProjects(orgID string) ([]*Project, error)
+	DeleteProject(id string) error
+
+	CreateAPIToken(token *APIToken) error
+	GetAPITokenByHash(hash string) (*APIToken, error)
+	ListAPITokens(projectID string) ([]*APIToken, error)
+	RevokeAPIToken(id string) error
+
+	AddCollaborator(collab *Collaborator) error
+	ListCollaborators(sessionID string) ([]*Collaborator, error)
+	RemoveCollaborator(sessionID, userID string) error
+	DeleteCollaborators(sessionID string) error
+
+	GetUserSettings(userID string) (*UserSettings, error)
+	SaveUserSettings(settings *UserSettings) error
+
+	CreateTeam(team *Team) error
+	GetTeam(id string) (*Team, error)
+	ListTeams() ([]*Team, error)
+	DeleteTeam(id string) error
+	SaveTeamMember(member *TeamMember) error
+	RemoveTeamMember(teamID, userID string) error
+	ListTeamMembers(teamID string) ([]*TeamMember, error)
+	ListUserTeams(userID string) ([]*TeamMember, error)
+
+	CreateSSHCertificate(cert *SSHCertificate) error
+	ListSSHCertificates(sessionID string) ([]*SSHCertificate, error)
+	RevokeSSHCertificate(sessionID string, serial uint64) error
+	RevokeSSHCertificates(sessionID string) error
+	ScrubSSHCertificates(sessionID string) error
+
+	GetSessionUsage(sessionID string) (*SessionUsage, error)
+	SaveSessionUsage(usage *SessionUsage) error
+	ListSessionUsage(userID, projectID string) ([]*SessionUsage, error)
+	ListEndedSessionUsage(from, to time.Time) ([]*SessionUsage, error)
+
+	RegisterNode(node *Node) error
+	HeartbeatNode(id string, gpus []NodeGPU, capacity map[string]int, at time.Time) error
+	SetNodeDriver(id, driverVersion, cudaVersion, nvmlVersion string) error
+	SetNodeClock(id string, offsetMs int64, warning string) error
+	GetNode(id string) (*Node, error)
+	ListNodes() ([]*Node, error)
+	SetNodeStatus(id, from, to string) (bool, error)
+	SetNodeDrain(id, status, policy string, startedAt, deadline *time.Time) error
+	DeleteNode(id string) error
+
+	CreateNodeCertificate(cert *NodeCertificate) error
+	GetNodeCertificate(serial string) (*NodeCertificate, error)
+	ListNodeCertificates(nodeID string) ([]*NodeCertificate, error)
+	RevokeNodeCertificates(nodeID string, at time.Time) (int64, error)
+	PurgeNodeCertificates(before time.Time) (int64, error)
+	CreateNodeJoinToken(token *NodeJoinToken) error
+	TakeNodeJoinToken(tokenHash string) (*NodeJoinToken, error)
+	DeleteNodeJoinTokens(nodeID string) error
+
+	CreateRestorePoint(rp *RestorePoint) error
+	GetRestorePoint(id string) (*RestorePoint, error)
+	ListRestorePoints(sessionID string) ([]*RestorePoint, error)
+	ListUserRestorePoints(userID string) ([]*RestorePoint, error)
+	DeleteRestorePoint(id string) error
+
+	GetImageChannel(name string) (*ImageChannel, error)
+	ListImageChannels() ([]*ImageChannel, error)
+	SaveImageChannel(ch *ImageChannel) error
+	RecordImageChannelOutcome(name string, failed bool) error
+	GetImagePin(subject string) (*ImagePin, error)
+	ListImagePins() ([]*ImagePin, error)
+	SaveImagePin(pin *ImagePin) error
+	DeleteImagePin(subject string) error
+
+	GetCatalogImage(name string) (*CatalogImage, error)
+	ListCatalogImages() ([]*CatalogImage, error)
+	SaveCatalogImage(img *CatalogImage) error
+	DeleteCatalogImage(name string) error
+
+	CreateCredentialLink(link *CredentialLink) error
+	TakeCredentialLink(tokenHash string) (*CredentialLink, error)
+	DeleteCredentialLinks(sessionID string) error
+
+	SaveSessionBaseline(baseline *SessionBaseline) error
+	GetSessionBaseline(sessionID string) (*SessionBaseline, error)
+	DeleteSessionBaseline(sessionID string) error
+
+	SaveSessionManifest(m *SessionManifest) error
+	GetSessionManifest(sessionID string) (*SessionManifest, error)
+
+	CreateIncident(incident *Incident) error
+	UpdateIncident(incident *Incident) error
+	GetIncident(id string) (*Incident, error)
+	ListIncidents(activeOnly bool) ([]*Incident, error)
+
+	SaveWorkspaceLifecycle(lc *WorkspaceLifecycle) error
+	GetWorkspaceLifecycle(userID string) (*WorkspaceLifecycle, error)
+	ListWorkspaceLifecycle() ([]*WorkspaceLifecycle, error)
+	DeleteWorkspaceLifecycle(userID string) error
+
+	InsertDNSQueries(records []*DNSQueryRecord) error
+	ListDNSQueries(filter DNSQueryFilter) ([]*DNSQueryRecord, error)
+	PruneDNSQueries(before time.Time, maxRows int) (int64, error)
+
+	AppendAudit(entry *AuditEntry) error
+	ListAudit(limit int) ([]*AuditEntry, error)
+	QueryAudit(filter AuditFilter) ([]*AuditEntry, error)
+	AppendSessionEvent(event *SessionEvent) error
+	ListSessionEvents(sessionID string) ([]*SessionEvent, error)
+
+	SaveImageBuild(b *ImageBuild) error
+	GetImageBuild(sessionID string) (*ImageBuild, error)
+	ListImageBuilds(since time.Time) ([]*ImageBuild, error)
+	SaveSessionWait(w *SessionWait) error
+	ListSessionWaits(since time.Time) ([]*SessionWait, error)
+	PurgeSessionWaits(before time.Time) (int64, error)
+
+	SaveMIGReservation(r *MIGReservation) error
+	GetMIGReservation(userID string, now time.Time) (*MIGReservation, error)
+	ListMIGReservations(nodeID string, now time.Time) ([]*MIGReservation, error)
+	DeleteMIGReservation(userID string) error
+	PurgeMIGReservations(now time.Time) (int64, error)
+
+	CreateProfile(p *Profile) error
+	UpdateProfile(p *Profile) error
+	GetProfile(name string) (*Profile, error)
+	ListProfiles() ([]*Profile, error)
+	DeleteProfile(name string) error
+
+	GetQuota(subject string) (*Quota, error)
+	ListQuotas() ([]*Quota, error)
+	SaveQuota(q *Quota) error
+	DeleteQuota(subject string) error
+
+	PurgeUserData(userID string, opts UserPurgeOptions) (map[string]int64, error)
+
+	AssignProjectID(userID string, base uint32) (uint32, error)
+	GetProjectID(userID string) (uint32, error)
+
+	ClaimGPU(claim *GPUClaim) error
+	ReleaseGPUClaim(instanceUUID, sessionID string) error
+	ListGPUClaims(nodeID string) ([]*GPUClaim, error)
+
+	TryAcquireLock(name, owner string, ttl time.Duration) (bool, error)
+	ReleaseLock(name, owner string) error
+	ListLocks() ([]*LockLease, error)
+
 	Close() error
 }
 
 type SQLiteStore struct {
-	db *sql.DB
+	db *conn
+}
+
+// sessionColumns sessions 테이블 조회 시 사용하는 컬럼 목록 (scanSession과 순서가 같아야 함)
+const sessionColumns = `id, user_id, project_id, node_id, container_id, container_ip, ssh_port, gpu_uuid, gpu_name, mig_profile, priority, tier, evict_at, state, ttl_minutes, created_at, expires_at, metadata, annotations`
+
+// rowScanner *sql.Row와 *sql.Rows 공통 인터페이스
+type rowScanner interface {
+	Scan(dest ...interface{}) error
 }
 
 func NewSQLiteStore(dbPath string) (*SQLiteStore, error) {
-	db, err := sql.Open("sqlite3", dbPath)
+	return OpenSQLiteStore(dbPath, SchemaMigrate)
+}
+
+// OpenSQLiteStore 스키마 버전을 확인하고 정책에 따라 스키마를 올린 뒤 SQLite 저장소를 엶
+func OpenSQLiteStore(dbPath string, policy SchemaPolicy) (*SQLiteStore, error) {
+	// 락 테이블처럼 동시에 쓰는 경우 SQLITE_BUSY 대신 잠시 대기
+	dsn := dbPath
+	if !strings.Contains(dsn, "?") {
+		dsn += "?_busy_timeout=5000"
+	}
+
+	db, err := sql.Open("sqlite3", dsn)
 	if err != nil {
 		return nil, err
 	}
 
-	store := &SQLiteStore{db: db}
-	if err := store.migrate(); err != nil {
+	store := &SQLiteStore{db: &conn{DB: db, dialect: dialectSQLite}}
+	if err := store.negotiateSchema(policy); err != nil {
+		db.Close()
 		return nil, err
 	}
 
@@ -69,136 +270,428 @@ func (s *SQLiteStore) migrate() error {
 
 	CREATE INDEX IF NOT EXISTS idx_user_id ON sessions(user_id);
 	CREATE INDEX IF NOT EXISTS idx_expires_at ON sessions(expires_at);
-	`
-	_, err := s.db.Exec(query)
-	return err
-}
 
-func (s *SQLiteStore) CreateSession(session *Session) error {
-	metadataJSON, _ := json.Marshal(session.Metadata)
+	CREATE TABLE IF NOT EXISTS organizations (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE,
+		created_at DATETIME NOT NULL
+	);
 
-	query := `
-		INSERT INTO sessions (id, user_id, container_id, container_ip, ssh_port, gpu_uuid, mig_profile, ttl_minutes, created_at, expires_at, metadata)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`
-	_, err := s.db.Exec(query,
-		session.ID, session.UserID, session.ContainerID, session.ContainerIP, session.SSHPort,
-		session.GPUUUID, session.MIGProfile, session.TTLMinutes,
-		session.CreatedAt, session.ExpiresAt, string(metadataJSON))
+	CREATE TABLE IF NOT EXISTS projects (
+		id TEXT PRIMARY KEY,
+		org_id TEXT NOT NULL REFERENCES organizations(id),
+		name TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		UNIQUE(org_id, name)
+	);
 
-	return err
-}
+	CREATE TABLE IF NOT EXISTS api_tokens (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		project_id TEXT,
+		token_hash TEXT NOT NULL UNIQUE,
+		scopes TEXT,
+		created_at DATETIME NOT NULL,
+		revoked_at DATETIME
+	);
 
-func (s *SQLiteStore) GetSession(id string) (*Session, error) {
-	query := `
-		SELECT id, user_id, container_id, container_ip, ssh_port, gpu_uuid, mig_profile, ttl_minutes, created_at, expires_at, metadata
-		FROM sessions WHERE id = ?
-	`
+	CREATE TABLE IF NOT EXISTS session_collaborators (
+		session_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		public_key TEXT NOT NULL,
+		fingerprint TEXT NOT NULL,
+		granted_at DATETIME NOT NULL,
+		PRIMARY KEY (session_id, user_id)
+	);
 
-	session := &Session{}
-	var metadataJSON string
+	CREATE TABLE IF NOT EXISTS user_settings (
+		user_id TEXT PRIMARY KEY,
+		timezone TEXT NOT NULL DEFAULT '',
+		locale TEXT NOT NULL DEFAULT '',
+		updated_at DATETIME NOT NULL
+	);
 
-	err := s.db.QueryRow(query, id).Scan(
-		&session.ID, &session.UserID, &session.ContainerID, &session.ContainerIP, &session.SSHPort,
-		&session.GPUUUID, &session.MIGProfile, &session.TTLMinutes,
-		&session.CreatedAt, &session.ExpiresAt, &metadataJSON)
+	CREATE TABLE IF NOT EXISTS teams (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL UNIQUE,
+		created_at DATETIME NOT NULL
+	);
 
-	if err != nil {
-		return nil, err
-	}
+	CREATE TABLE IF NOT EXISTS team_members (
+		team_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		role TEXT NOT NULL,
+		added_at DATETIME NOT NULL,
+		PRIMARY KEY (team_id, user_id)
+	);
 
-	json.Unmarshal([]byte(metadataJSON), &session.Metadata)
-	return session, nil
-}
+	CREATE TABLE IF NOT EXISTS incidents (
+		id TEXT PRIMARY KEY,
+		title TEXT NOT NULL,
+		message TEXT NOT NULL DEFAULT '',
+		severity TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL,
+		resolved_at DATETIME
+	);
 
-func (s *SQLiteStore) GetSessionByUserID(userID string) (*Session, error) {
-	query := `
-		SELECT id, user_id, container_id, container_ip, ssh_port, gpu_uuid, mig_profile, ttl_minutes, created_at, expires_at, metadata
-		FROM sessions WHERE user_id = ?
-	`
+	CREATE TABLE IF NOT EXISTS dns_queries (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id TEXT NOT NULL DEFAULT '',
+		user_id TEXT NOT NULL DEFAULT '',
+		node_id TEXT NOT NULL DEFAULT '',
+		client_ip TEXT NOT NULL,
+		name TEXT NOT NULL,
+		qtype TEXT NOT NULL,
+		rcode TEXT NOT NULL,
+		queried_at DATETIME NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_dns_queries_session ON dns_queries(session_id, queried_at);
+	CREATE INDEX IF NOT EXISTS idx_dns_queries_time ON dns_queries(queried_at);
 
-	session := &Session{}
-	var metadataJSON string
+	CREATE TABLE IF NOT EXISTS distributed_locks (
+		name TEXT PRIMARY KEY,
+		owner TEXT NOT NULL,
+		expires_at INTEGER NOT NULL
+	);
 
-	err := s.db.QueryRow(query, userID).Scan(
-		&session.ID, &session.UserID, &session.ContainerID, &session.ContainerIP, &session.SSHPort,
-		&session.GPUUUID, &session.MIGProfile, &session.TTLMinutes,
-		&session.CreatedAt, &session.ExpiresAt, &metadataJSON)
+	CREATE TABLE IF NOT EXISTS ssh_certificates (
+		serial INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		key_id TEXT NOT NULL,
+		principal TEXT NOT NULL,
+		public_key TEXT NOT NULL,
+		fingerprint TEXT NOT NULL,
+		valid_before DATETIME NOT NULL,
+		created_at DATETIME NOT NULL,
+		revoked_at DATETIME
+	);
 
-	if err != nil {
-		return nil, err
-	}
+	CREATE INDEX IF NOT EXISTS idx_ssh_certificates_session ON ssh_certificates(session_id);
+
+	CREATE TABLE IF NOT EXISTS session_usage (
+		session_id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		project_id TEXT NOT NULL DEFAULT '',
+		rx_bytes INTEGER NOT NULL DEFAULT 0,
+		tx_bytes INTEGER NOT NULL DEFAULT 0,
+		last_raw_rx INTEGER NOT NULL DEFAULT 0,
+		last_raw_tx INTEGER NOT NULL DEFAULT 0,
+		started_at DATETIME NOT NULL,
+		sampled_at DATETIME NOT NULL,
+		ended_at DATETIME
+	);
 
-	json.Unmarshal([]byte(metadataJSON), &session.Metadata)
-	return session, nil
-}
+	CREATE INDEX IF NOT EXISTS idx_session_usage_user ON session_usage(user_id);
+	CREATE INDEX IF NOT EXISTS idx_session_usage_ended ON session_usage(ended_at);
 
-func (s *SQLiteStore) UpdateSession(session *Session) error {
-	metadataJSON, _ := json.Marshal(session.Metadata)
+	CREATE TABLE IF NOT EXISTS workspace_lifecycle (
+		user_id TEXT PRIMARY KEY,
+		session_id TEXT NOT NULL,
+		ended_at DATETIME NOT NULL,
+		archive_at DATETIME NOT NULL,
+		archived_at DATETIME,
+		archive_path TEXT NOT NULL DEFAULT ''
+	);
 
-	query := `
-		UPDATE sessions SET 
-			container_id = ?, container_ip = ?, ssh_port = ?, gpu_uuid = ?, mig_profile = ?,
-			ttl_minutes = ?, expires_at = ?, metadata = ?
-		WHERE id = ?
+	CREATE TABLE IF NOT EXISTS audit_log (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		actor TEXT NOT NULL,
+		action TEXT NOT NULL,
+		target TEXT NOT NULL,
+		detail TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS restore_points (
+		id TEXT PRIMARY KEY,
+		session_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		project_id TEXT NOT NULL DEFAULT '',
+		reason TEXT NOT NULL,
+		path TEXT NOT NULL,
+		files INTEGER NOT NULL DEFAULT 0,
+		bytes INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME NOT NULL
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_restore_points_session ON restore_points(session_id);
+	CREATE INDEX IF NOT EXISTS idx_restore_points_user ON restore_points(user_id);
+
+	CREATE TABLE IF NOT EXISTS nodes (
+		id TEXT PRIMARY KEY,
+		hostname TEXT NOT NULL,
+		address TEXT NOT NULL DEFAULT '',
+		version TEXT NOT NULL DEFAULT '',
+		driver_version TEXT NOT NULL DEFAULT '',
+		capabilities TEXT NOT NULL DEFAULT '[]',
+		gpus TEXT NOT NULL DEFAULT '[]',
+		capacity TEXT NOT NULL DEFAULT '{}',
+		status TEXT NOT NULL,
+		registered_at DATETIME NOT NULL,
+		last_heartbeat DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS image_channels (
+		name TEXT PRIMARY KEY,
+		base_image TEXT NOT NULL DEFAULT '',
+		previous_base_image TEXT NOT NULL DEFAULT '',
+		rollout_percent INTEGER NOT NULL DEFAULT 0,
+		attempts INTEGER NOT NULL DEFAULT 0,
+		failures INTEGER NOT NULL DEFAULT 0,
+		updated_at DATETIME NOT NULL,
+		rolled_back_at DATETIME
+	);
+
+	CREATE TABLE IF NOT EXISTS image_pins (
+		subject TEXT PRIMARY KEY,
+		channel TEXT NOT NULL,
+		created_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS credential_links (
+		token_hash TEXT PRIMARY KEY,
+		session_id TEXT NOT NULL,
+		ssh_password TEXT NOT NULL,
+		ssh_private_key TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		expires_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS session_baselines (
+		session_id TEXT PRIMARY KEY,
+		image TEXT NOT NULL,
+		packages TEXT NOT NULL,
+		captured_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS session_manifests (
+		session_id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		project_id TEXT NOT NULL DEFAULT '',
+		version INTEGER NOT NULL,
+		payload TEXT NOT NULL,
+		signature TEXT NOT NULL DEFAULT '',
+		key_id TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS image_builds (
+		session_id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		project_id TEXT NOT NULL DEFAULT '',
+		template TEXT NOT NULL,
+		base_image TEXT NOT NULL DEFAULT '',
+		image TEXT NOT NULL,
+		started_at DATETIME NOT NULL,
+		duration_ms INTEGER NOT NULL,
+		steps INTEGER NOT NULL DEFAULT 0,
+		cached_steps INTEGER NOT NULL DEFAULT 0,
+		size_bytes INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX IF NOT EXISTS idx_image_builds_started ON image_builds(started_at);
+
+	CREATE TABLE IF NOT EXISTS mig_reservations (
+		user_id TEXT PRIMARY KEY,
+		node_id TEXT NOT NULL DEFAULT '',
+		gpu_uuid TEXT NOT NULL,
+		gpu_name TEXT NOT NULL DEFAULT '',
+		mig_profile TEXT NOT NULL,
+		session_id TEXT NOT NULL,
+		released_at DATETIME NOT NULL,
+		expires_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS profiles (
+		name TEXT PRIMARY KEY,
+		display_name TEXT NOT NULL DEFAULT '',
+		description TEXT NOT NULL DEFAULT '',
+		enabled INTEGER NOT NULL DEFAULT 1,
+		resources TEXT NOT NULL DEFAULT '',
+		price_per_hour REAL NOT NULL DEFAULT 0,
+		default_ttl_minutes INTEGER NOT NULL DEFAULT 0,
+		max_ttl_minutes INTEGER NOT NULL DEFAULT 0,
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS image_catalog (
+		name TEXT PRIMARY KEY,
+		base_image TEXT NOT NULL,
+		cuda_version TEXT NOT NULL DEFAULT '',
+		description TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL,
+		updated_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS quotas (
+		subject TEXT PRIMARY KEY,
+		max_sessions INTEGER NOT NULL DEFAULT 0,
+		max_gpu_slices INTEGER NOT NULL DEFAULT 0,
+		max_ttl_minutes INTEGER NOT NULL DEFAULT 0,
+		updated_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS node_certificates (
+		serial TEXT PRIMARY KEY,
+		node_id TEXT NOT NULL,
+		spiffe_id TEXT NOT NULL,
+		fingerprint TEXT NOT NULL,
+		kind TEXT NOT NULL,
+		not_after DATETIME NOT NULL,
+		created_at DATETIME NOT NULL,
+		revoked_at DATETIME
+	);
+	CREATE INDEX IF NOT EXISTS idx_node_certificates_node ON node_certificates(node_id);
+
+	CREATE TABLE IF NOT EXISTS node_join_tokens (
+		token_hash TEXT PRIMARY KEY,
+		node_id TEXT NOT NULL,
+		created_at DATETIME NOT NULL,
+		expires_at DATETIME NOT NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS session_waits (
+		session_id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		project_id TEXT NOT NULL DEFAULT '',
+		profile TEXT NOT NULL,
+		tier TEXT NOT NULL DEFAULT '',
+		node_id TEXT NOT NULL DEFAULT '',
+		submitted_at DATETIME NOT NULL,
+		running_at DATETIME NOT NULL,
+		wait_ms INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_session_waits_running ON session_waits(running_at);
+
+	CREATE TABLE IF NOT EXISTS session_events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		session_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		type TEXT NOT NULL,
+		actor TEXT NOT NULL,
+		reason TEXT NOT NULL DEFAULT '',
+		detail TEXT NOT NULL DEFAULT '',
+		created_at DATETIME NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_session_events_session ON session_events(session_id);
+
+	CREATE TABLE IF NOT EXISTS gpu_claims (
+		instance_uuid TEXT PRIMARY KEY,
+		node_id TEXT NOT NULL DEFAULT '',
+		session_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		claimed_at DATETIME NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_gpu_claims_node ON gpu_claims(node_id);
+
+	CREATE TABLE IF NOT EXISTS workspace_project_ids (
+		user_id TEXT PRIMARY KEY,
+		project_id INTEGER NOT NULL UNIQUE
+	);
 	`
-	_, err := s.db.Exec(query,
-		session.ContainerID, session.ContainerIP, session.SSHPort, session.GPUUUID, session.MIGProfile,
-		session.TTLMinutes, session.ExpiresAt, string(metadataJSON), session.ID)
+	if _, err := s.db.Exec(s.db.dialect.ddl(query)); err != nil {
+		return err
+	}
 
-	return err
-}
+	// 기존 데이터베이스에 새 컬럼 추가
+	columns := []struct{ table, name, definition string }{
+		{"sessions", "project_id", "TEXT NOT NULL DEFAULT ''"},
+		{"sessions", "node_id", "TEXT NOT NULL DEFAULT ''"},
+		{"nodes", "drain_policy", "TEXT NOT NULL DEFAULT ''"},
+		{"nodes", "drain_started_at", "DATETIME"},
+		{"nodes", "drain_deadline", "DATETIME"},
+		{"user_settings", "locale", "TEXT NOT NULL DEFAULT ''"},
+		{"session_usage", "node_id", "TEXT NOT NULL DEFAULT ''"},
+		{"session_usage", "mig_profile", "TEXT NOT NULL DEFAULT ''"},
+		{"session_usage", "end_reason", "TEXT NOT NULL DEFAULT ''"},
+		{"sessions", "priority", "INTEGER NOT NULL DEFAULT 0"},
+		{"sessions", "state", "TEXT NOT NULL DEFAULT 'running'"},
+		{"sessions", "annotations", "TEXT NOT NULL DEFAULT '{}'"},
+		{"nodes", "clock_offset_ms", "INTEGER NOT NULL DEFAULT 0"},
+		{"nodes", "clock_warning", "TEXT NOT NULL DEFAULT ''"},
+		{"nodes", "cuda_version", "TEXT NOT NULL DEFAULT ''"},
+		{"nodes", "nvml_version", "TEXT NOT NULL DEFAULT ''"},
+		{"sessions", "tier", "TEXT NOT NULL DEFAULT 'standard'"},
+		{"sessions", "evict_at", "DATETIME"},
+		{"session_usage", "tier", "TEXT NOT NULL DEFAULT 'standard'"},
+		{"session_usage", "rate_factor", "REAL NOT NULL DEFAULT 1"},
+		{"user_settings", "workspace_policy", "TEXT NOT NULL DEFAULT ''"},
+		{"user_settings", "workspace_retention_days", "INTEGER NOT NULL DEFAULT 0"},
+		{"session_usage", "workspace_policy", "TEXT NOT NULL DEFAULT ''"},
+		{"session_usage", "workspace_archive_at", "DATETIME"},
+		{"sessions", "gpu_name", "TEXT NOT NULL DEFAULT ''"},
+	}
+	for _, col := range columns {
+		if err := s.addColumnIfMissing(col.table, col.name, col.definition); err != nil {
+			return err
+		}
+	}
 
-func (s *SQLiteStore) DeleteSession(id string) error {
-	query := `DELETE FROM sessions WHERE id = ?`
-	_, err := s.db.Exec(query, id)
+	_, err := s.db.Exec(`CREATE INDEX IF NOT EXISTS idx_project_id ON sessions(project_id)`)
 	return err
 }
 
-func (s *SQLiteStore) ListExpiredSessions() ([]*Session, error) {
-	query := `
-		SELECT id, user_id, container_id, container_ip, ssh_port, gpu_uuid, mig_profile, ttl_minutes, created_at, expires_at, metadata
-		FROM sessions WHERE expires_at < datetime('now')
-	`
+// addColumnIfMissing 테이블에 컬럼이 없으면 ALTER TABLE로 추가
+func (s *SQLiteStore) addColumnIfMissing(table, column, definition string) error {
+	if s.db.dialect == dialectPostgres {
+		_, err := s.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s %s", table, column, s.db.dialect.ddl(definition)))
+		return err
+	}
 
-	rows, err := s.db.Query(query)
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	var sessions []*Session
 	for rows.Next() {
-		session := &Session{}
-		var metadataJSON string
+		var (
+			cid       int
+			name      string
+			colType   string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &dfltValue, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	rows.Close()
 
-		err := rows.Scan(
-			&session.ID, &session.UserID, &session.ContainerID, &session.ContainerIP, &session.SSHPort,
-			&session.GPUUUID, &session.MIGProfile, &session.TTLMinutes,
-			&session.CreatedAt, &session.ExpiresAt, &metadataJSON)
+	_, err = s.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition))
+	return err
+}
 
-		if err != nil {
-			continue
-		}
+func scanSession(row rowScanner) (*Session, error) {
+	session := &Session{}
+	var metadataJSON, annotationsJSON string
+	var evictAt sql.NullTime
 
-		json.Unmarshal([]byte(metadataJSON), &session.Metadata)
-		sessions = append(sessions, session)
-	}
+	err := row.Scan(
+		&session.ID, &session.UserID, &session.ProjectID, &session.NodeID, &session.ContainerID, &session.ContainerIP, &session.SSHPort,
+		&session.GPUUUID, &session.GPUName, &session.MIGProfile, &session.Priority, &session.Tier, &evictAt, &session.State, &session.TTLMinutes,
+		&session.CreatedAt, &session.ExpiresAt, &metadataJSON, &annotationsJSON)
 
-	if sessions == nil {
-		sessions = []*Session{}
+	if err != nil {
+		return nil, classify(err)
+	}
+	if evictAt.Valid {
+		session.EvictAt = &evictAt.Time
 	}
 
-	return sessions, nil
+	json.Unmarshal([]byte(metadataJSON), &session.Metadata)
+	json.Unmarshal([]byte(annotationsJSON), &session.Annotations)
+	return session, nil
 }
 
-func (s *SQLiteStore) ListAllSessions() ([]*Session, error) {
-	query := `
-		SELECT id, user_id, container_id, container_ip, ssh_port, gpu_uuid, mig_profile, ttl_minutes, created_at, expires_at, metadata
-		FROM sessions ORDER BY created_at DESC
-	`
-
-	rows, err := s.db.Query(query)
+func (s *SQLiteStore) querySessions(query string, args ...interface{}) ([]*Session, error) {
+	rows, err := s.db.Query(query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -206,19 +699,10 @@ func (s *SQLiteStore) ListAllSessions() ([]*Session, error) {
 
 	var sessions []*Session
 	for rows.Next() {
-		session := &Session{}
-		var metadataJSON string
-
-		err := rows.Scan(
-			&session.ID, &session.UserID, &session.ContainerID, &session.ContainerIP, &session.SSHPort,
-			&session.GPUUUID, &session.MIGProfile, &session.TTLMinutes,
-			&session.CreatedAt, &session.ExpiresAt, &metadataJSON)
-
+		session, err := scanSession(rows)
 		if err != nil {
 			continue
 		}
-
-		json.Unmarshal([]byte(metadataJSON), &session.Metadata)
 		sessions = append(sessions, session)
 	}
 
@@ -229,6 +713,84 @@ func (s *SQLiteStore) ListAllSessions() ([]*Session, error) {
 	return sessions, nil
 }
 
+func (s *SQLiteStore) CreateSession(session *Session) error {
+	metadataJSON, _ := json.Marshal(session.Metadata)
+	annotationsJSON, _ := json.Marshal(session.Annotations)
+	if session.Annotations == nil {
+		annotationsJSON = []byte("{}")
+	}
+
+	query := `
+		INSERT INTO sessions (` + sessionColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	if session.State == "" {
+		session.State = SessionRunning
+	}
+	if session.Tier == "" {
+		session.Tier = TierStandard
+	}
+	_, err := s.db.Exec(query,
+		session.ID, session.UserID, session.ProjectID, session.NodeID, session.ContainerID, session.ContainerIP, session.SSHPort,
+		session.GPUUUID, session.GPUName, session.MIGProfile, session.Priority, session.Tier, session.EvictAt, session.State, session.TTLMinutes,
+		session.CreatedAt, session.ExpiresAt, string(metadataJSON), string(annotationsJSON))
+
+	return classify(err)
+}
+
+func (s *SQLiteStore) GetSession(id string) (*Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE id = ?`
+	return scanSession(s.db.QueryRow(query, id))
+}
+
+func (s *SQLiteStore) GetSessionByUserID(userID string) (*Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE user_id = ?`
+	return scanSession(s.db.QueryRow(query, userID))
+}
+
+func (s *SQLiteStore) UpdateSession(session *Session) error {
+	metadataJSON, _ := json.Marshal(session.Metadata)
+
+	query := `
+		UPDATE sessions SET
+			project_id = ?, container_id = ?, container_ip = ?, ssh_port = ?, gpu_uuid = ?, gpu_name = ?, mig_profile = ?,
+			evict_at = ?, state = ?, ttl_minutes = ?, expires_at = ?, metadata = ?
+		WHERE id = ?
+	`
+	_, err := s.db.Exec(query,
+		session.ProjectID, session.ContainerID, session.ContainerIP, session.SSHPort, session.GPUUUID, session.GPUName, session.MIGProfile,
+		session.EvictAt, session.State, session.TTLMinutes, session.ExpiresAt, string(metadataJSON), session.ID)
+
+	return err
+}
+
+func (s *SQLiteStore) DeleteSession(id string) error {
+	query := `DELETE FROM sessions WHERE id = ?`
+	_, err := s.db.Exec(query, id)
+	return err
+}
+
+func (s *SQLiteStore) ListExpiredSessions() ([]*Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE expires_at < datetime('now')`
+	return s.querySessions(query)
+}
+
+func (s *SQLiteStore) ListAllSessions() ([]*Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions ORDER BY created_at DESC`
+	return s.querySessions(query)
+}
+
+func (s *SQLiteStore) ListSessionsByProject(projectID string) ([]*Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE project_id = ? ORDER BY created_at DESC`
+	return s.querySessions(query, projectID)
+}
+
 func (s *SQLiteStore) Close() error {
 	return s.db.Close()
 }
+
+// Backup 실행 중에도 일관된 스냅샷을 path에 기록 (VACUUM INTO)
+func (s *SQLiteStore) Backup(path string) error {
+	_, err := s.db.Exec(`VACUUM INTO ?`, path)
+	return err
+}
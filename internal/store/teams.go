@@ -0,0 +1,130 @@
+package store
+
+import (
+	"time"
+)
+
+// 팀 구성원 역할
+const (
+	RoleLead   = "lead"   // 팀원 세션 조회/연장/삭제 가능
+	RoleMember = "member" // 자기 세션만
+)
+
+// Team 사용자 디렉토리의 팀
+type Team struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TeamMember 팀 구성원과 역할 (한 사용자가 여러 팀에 속할 수 있음)
+type TeamMember struct {
+	TeamID  string    `json:"team_id"`
+	UserID  string    `json:"user_id"`
+	Role    string    `json:"role"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+func (s *SQLiteStore) CreateTeam(team *Team) error {
+	_, err := s.db.Exec(`INSERT INTO teams (id, name, created_at) VALUES (?, ?, ?)`, team.ID, team.Name, team.CreatedAt)
+	return classify(err)
+}
+
+func (s *SQLiteStore) GetTeam(id string) (*Team, error) {
+	team := &Team{}
+	err := s.db.QueryRow(`SELECT id, name, created_at FROM teams WHERE id = ?`, id).Scan(&team.ID, &team.Name, &team.CreatedAt)
+	if err != nil {
+		return nil, classify(err)
+	}
+	return team, nil
+}
+
+func (s *SQLiteStore) ListTeams() ([]*Team, error) {
+	rows, err := s.db.Query(`SELECT id, name, created_at FROM teams ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	teams := []*Team{}
+	for rows.Next() {
+		team := &Team{}
+		if err := rows.Scan(&team.ID, &team.Name, &team.CreatedAt); err != nil {
+			continue
+		}
+		teams = append(teams, team)
+	}
+	return teams, nil
+}
+
+// DeleteTeam 팀과 구성원 기록, 팀 한도 삭제
+func (s *SQLiteStore) DeleteTeam(id string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`DELETE FROM teams WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errNoRows
+	}
+	if _, err := tx.Exec(`DELETE FROM team_members WHERE team_id = ?`, id); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM quotas WHERE subject = ?`, "team:"+id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// SaveTeamMember 구성원 추가 또는 역할 변경
+func (s *SQLiteStore) SaveTeamMember(member *TeamMember) error {
+	query := `
+		INSERT INTO team_members (team_id, user_id, role, added_at) VALUES (?, ?, ?, ?)
+		ON CONFLICT(team_id, user_id) DO UPDATE SET role = excluded.role
+	`
+	_, err := s.db.Exec(query, member.TeamID, member.UserID, member.Role, member.AddedAt)
+	return classify(err)
+}
+
+func (s *SQLiteStore) RemoveTeamMember(teamID, userID string) error {
+	result, err := s.db.Exec(`DELETE FROM team_members WHERE team_id = ? AND user_id = ?`, teamID, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errNoRows
+	}
+	return nil
+}
+
+func (s *SQLiteStore) ListTeamMembers(teamID string) ([]*TeamMember, error) {
+	return s.queryTeamMembers(`SELECT team_id, user_id, role, added_at FROM team_members WHERE team_id = ? ORDER BY user_id`, teamID)
+}
+
+// ListUserTeams 사용자가 속한 팀과 그 팀에서의 역할
+func (s *SQLiteStore) ListUserTeams(userID string) ([]*TeamMember, error) {
+	return s.queryTeamMembers(`SELECT team_id, user_id, role, added_at FROM team_members WHERE user_id = ? ORDER BY team_id`, userID)
+}
+
+func (s *SQLiteStore) queryTeamMembers(query string, arg string) ([]*TeamMember, error) {
+	rows, err := s.db.Query(query, arg)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []*TeamMember{}
+	for rows.Next() {
+		member := &TeamMember{}
+		if err := rows.Scan(&member.TeamID, &member.UserID, &member.Role, &member.AddedAt); err != nil {
+			continue
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
@@ -0,0 +1,94 @@
+package store
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+)
+
+// APIToken API 호출에 사용하는 토큰 (평문은 저장하지 않고 SHA-256 해시만 보관)
+type APIToken struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	ProjectID string     `json:"project_id,omitempty"`
+	TokenHash string     `json:"-"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+const apiTokenColumns = `id, name, project_id, token_hash, scopes, created_at, revoked_at`
+
+func scanAPIToken(row rowScanner) (*APIToken, error) {
+	token := &APIToken{}
+	var (
+		projectID sql.NullString
+		scopes    sql.NullString
+		revokedAt sql.NullTime
+	)
+
+	if err := row.Scan(&token.ID, &token.Name, &projectID, &token.TokenHash, &scopes, &token.CreatedAt, &revokedAt); err != nil {
+		return nil, classify(err)
+	}
+
+	token.ProjectID = projectID.String
+	token.Scopes = []string{}
+	if scopes.String != "" {
+		token.Scopes = strings.Split(scopes.String, ",")
+	}
+	if revokedAt.Valid {
+		token.RevokedAt = &revokedAt.Time
+	}
+	return token, nil
+}
+
+func (s *SQLiteStore) CreateAPIToken(token *APIToken) error {
+	query := `INSERT INTO api_tokens (` + apiTokenColumns + `) VALUES (?, ?, ?, ?, ?, ?, NULL)`
+	_, err := s.db.Exec(query,
+		token.ID, token.Name, token.ProjectID, token.TokenHash,
+		strings.Join(token.Scopes, ","), token.CreatedAt)
+	return classify(err)
+}
+
+func (s *SQLiteStore) GetAPITokenByHash(hash string) (*APIToken, error) {
+	query := `SELECT ` + apiTokenColumns + ` FROM api_tokens WHERE token_hash = ?`
+	return scanAPIToken(s.db.QueryRow(query, hash))
+}
+
+// ListAPITokens projectID가 비어 있으면 전체 토큰을 반환
+func (s *SQLiteStore) ListAPITokens(projectID string) ([]*APIToken, error) {
+	query := `SELECT ` + apiTokenColumns + ` FROM api_tokens`
+	args := []interface{}{}
+	if projectID != "" {
+		query += ` WHERE project_id = ?`
+		args = append(args, projectID)
+	}
+	query += ` ORDER BY created_at DESC`
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tokens := []*APIToken{}
+	for rows.Next() {
+		token, err := scanAPIToken(rows)
+		if err != nil {
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, nil
+}
+
+func (s *SQLiteStore) RevokeAPIToken(id string) error {
+	result, err := s.db.Exec(`UPDATE api_tokens SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL`, time.Now(), id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return errNoRows
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SessionUsage 세션별 사용량 집계 (세션이 삭제된 뒤에도 정산/보고용으로 보존)
+type SessionUsage struct {
+	SessionID  string     `json:"session_id"`
+	UserID     string     `json:"user_id"`
+	ProjectID  string     `json:"project_id,omitempty"`
+	NodeID     string     `json:"node_id,omitempty"`
+	MIGProfile string     `json:"mig_profile,omitempty"`
+	Tier       string     `json:"tier"`
+	RateFactor float64    `json:"rate_factor"` // 정산 요율 배율 (standard 1, spot은 spot.rate_factor)
+	RxBytes    uint64     `json:"rx_bytes"`
+	TxBytes    uint64     `json:"tx_bytes"`
+	StartedAt  time.Time  `json:"started_at"`
+	SampledAt  time.Time  `json:"sampled_at"`
+	EndedAt    *time.Time `json:"ended_at,omitempty"`
+	EndReason  string     `json:"end_reason,omitempty"` // deleted, expired, drained
+
+	// 세션 종료 시 적용한 워크스페이스 처리 (keep, archive, delete)와 보관 예정 시각
+	WorkspacePolicy    string     `json:"workspace_policy,omitempty"`
+	WorkspaceArchiveAt *time.Time `json:"workspace_archive_at,omitempty"`
+
+	// 마지막으로 읽은 컨테이너 카운터 원본 값 (재시작으로 카운터가 줄어든 경우 판별용)
+	LastRawRx uint64 `json:"-"`
+	LastRawTx uint64 `json:"-"`
+}
+
+const usageColumns = `session_id, user_id, project_id, node_id, mig_profile, tier, rate_factor, rx_bytes, tx_bytes, last_raw_rx, last_raw_tx,
+	started_at, sampled_at, ended_at, end_reason, workspace_policy, workspace_archive_at`
+
+func scanUsage(row rowScanner) (*SessionUsage, error) {
+	usage := &SessionUsage{}
+	var endedAt, archiveAt sql.NullTime
+	err := row.Scan(&usage.SessionID, &usage.UserID, &usage.ProjectID, &usage.NodeID, &usage.MIGProfile, &usage.Tier, &usage.RateFactor, &usage.RxBytes, &usage.TxBytes,
+		&usage.LastRawRx, &usage.LastRawTx, &usage.StartedAt, &usage.SampledAt, &endedAt, &usage.EndReason,
+		&usage.WorkspacePolicy, &archiveAt)
+	if err != nil {
+		return nil, classify(err)
+	}
+	if endedAt.Valid {
+		usage.EndedAt = &endedAt.Time
+	}
+	if archiveAt.Valid {
+		usage.WorkspaceArchiveAt = &archiveAt.Time
+	}
+	return usage, nil
+}
+
+func (s *SQLiteStore) GetSessionUsage(sessionID string) (*SessionUsage, error) {
+	return scanUsage(s.db.QueryRow(`SELECT `+usageColumns+` FROM session_usage WHERE session_id = ?`, sessionID))
+}
+
+// SaveSessionUsage 사용량 저장
+// 이미 종료된 기록은 최종 보고이므로 늦게 도착한 주기 수집(ended_at 없음)으로 덮어쓰지 않고, 종료 시각과 사유는 처음 값을 유지
+func (s *SQLiteStore) SaveSessionUsage(usage *SessionUsage) error {
+	query := `
+		INSERT INTO session_usage (` + usageColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(session_id) DO UPDATE SET
+			rx_bytes = excluded.rx_bytes, tx_bytes = excluded.tx_bytes,
+			last_raw_rx = excluded.last_raw_rx, last_raw_tx = excluded.last_raw_tx,
+			sampled_at = excluded.sampled_at,
+			ended_at = COALESCE(session_usage.ended_at, excluded.ended_at),
+			end_reason = CASE WHEN session_usage.ended_at IS NULL THEN excluded.end_reason ELSE session_usage.end_reason END,
+			workspace_policy = excluded.workspace_policy, workspace_archive_at = excluded.workspace_archive_at
+		WHERE session_usage.ended_at IS NULL OR excluded.ended_at IS NOT NULL
+	`
+	// 시각은 UTC로 저장해 SQLite에서도 ended_at 구간을 문자열 비교로 거를 수 있게 함
+	_, err := s.db.Exec(query, usage.SessionID, usage.UserID, usage.ProjectID, usage.NodeID, usage.MIGProfile, usage.Tier, usage.RateFactor, usage.RxBytes, usage.TxBytes,
+		usage.LastRawRx, usage.LastRawTx, usage.StartedAt.UTC(), usage.SampledAt.UTC(), utcTime(usage.EndedAt), usage.EndReason,
+		usage.WorkspacePolicy, utcTime(usage.WorkspaceArchiveAt))
+	return err
+}
+
+func utcTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	utc := t.UTC()
+	return &utc
+}
+
+// ListSessionUsage 사용자/프로젝트로 거른 사용량 기록 (빈 값은 조건 없음)
+func (s *SQLiteStore) ListSessionUsage(userID, projectID string) ([]*SessionUsage, error) {
+	var (
+		conditions []string
+		args       []interface{}
+	)
+	if userID != "" {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, userID)
+	}
+	if projectID != "" {
+		conditions = append(conditions, "project_id = ?")
+		args = append(args, projectID)
+	}
+
+	query := `SELECT ` + usageColumns + ` FROM session_usage`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY started_at DESC"
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usages := []*SessionUsage{}
+	for rows.Next() {
+		usage, err := scanUsage(rows)
+		if err != nil {
+			continue
+		}
+		usages = append(usages, usage)
+	}
+	return usages, nil
+}
+
+// ListEndedSessionUsage from 이후, to 이전에 종료된 세션 기록 (종료 시각 순)
+// 구간은 SQL로 거르되, UTC로 저장하기 전의 기록은 시간대 오프셋이 섞여 있을 수 있어 최대 오프셋만큼 넓혀 읽은 뒤 시각으로 정확히 거름
+// 읽지 못한 기록이 보고서에서 조용히 빠지지 않도록 스캔 오류는 그대로 반환
+func (s *SQLiteStore) ListEndedSessionUsage(from, to time.Time) ([]*SessionUsage, error) {
+	rows, err := s.db.Query(`SELECT `+usageColumns+` FROM session_usage WHERE ended_at >= ? AND ended_at < ? ORDER BY ended_at`,
+		from.Add(-maxZoneOffset).UTC(), to.Add(maxZoneOffset).UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usages := []*SessionUsage{}
+	for rows.Next() {
+		usage, err := scanUsage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("종료된 세션 사용량 읽기 실패: %w", err)
+		}
+		if usage.EndedAt.Before(from) || !usage.EndedAt.Before(to) {
+			continue
+		}
+		usages = append(usages, usage)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(usages, func(i, j int) bool { return usages[i].EndedAt.Before(*usages[j].EndedAt) })
+	return usages, nil
+}
+
+// maxZoneOffset 가장 큰 시간대 오프셋 (UTC+14)
+const maxZoneOffset = 14 * time.Hour
@@ -0,0 +1,93 @@
+package store
+
+// 사용자 데이터 삭제 시 기록 처리 방식
+const (
+	PurgeKeep      = "keep"      // 그대로 둠
+	PurgeAnonymize = "anonymize" // 사용자 ID를 가명으로 바꾸고 집계용 값은 남김
+	PurgeDelete    = "delete"    // 행 삭제
+)
+
+// UserPurgeOptions 세션 기록과 감사 기록을 지울지, 가명으로 바꿀지
+type UserPurgeOptions struct {
+	History   string // 세션 사용량, 이미지 빌드 (anonymize, delete)
+	Audit     string // 감사 기록 (keep, anonymize, delete)
+	Pseudonym string // anonymize에서 사용자 ID 대신 남길 값
+}
+
+// userOwnedTables 사용자 ID로 찾아 항상 삭제하는 테이블
+// 세션 자체와 세션별 기록(기준선, 자격 증명 링크)은 세션 정리에서 이미 지워짐
+var userOwnedTables = []string{
+	"user_settings",
+	"team_members",
+	"session_collaborators",
+	"ssh_certificates",
+	"restore_points",
+	"workspace_lifecycle",
+	"mig_reservations",
+	"dns_queries",
+	"session_manifests",
+}
+
+// PurgeUserData 사용자와 연결된 행을 한 트랜잭션에서 삭제하거나 가명으로 바꾸고 테이블별 처리 행 수를 반환
+// API 키는 사용자 범위를 해석해야 하므로 호출자가 따로 폐기
+func (s *SQLiteStore) PurgeUserData(userID string, opts UserPurgeOptions) (map[string]int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	counts := make(map[string]int64)
+	exec := func(table, query string, args ...interface{}) error {
+		result, err := tx.Exec(query, args...)
+		if err != nil {
+			return err
+		}
+		n, _ := result.RowsAffected()
+		counts[table] += n
+		return nil
+	}
+
+	// 감사 기록은 세션 ID로 남은 항목도 찾아야 하므로 사용량 기록을 바꾸기 전에 처리
+	auditMatch := `actor = ? OR target = ? OR target IN (SELECT session_id FROM session_usage WHERE user_id = ?)`
+	switch opts.Audit {
+	case PurgeDelete:
+		err = exec("audit_log", `DELETE FROM audit_log WHERE `+auditMatch, userID, userID, userID)
+	case PurgeAnonymize:
+		err = exec("audit_log", `
+			UPDATE audit_log SET
+				actor = CASE WHEN actor = ? THEN ? ELSE actor END,
+				target = CASE WHEN target = ? THEN ? ELSE target END,
+				detail = REPLACE(detail, ?, ?)
+			WHERE `+auditMatch,
+			userID, opts.Pseudonym, userID, opts.Pseudonym, userID, opts.Pseudonym, userID, userID, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, table := range []string{"session_usage", "image_builds", "session_waits", "session_events"} {
+		if opts.History == PurgeDelete {
+			err = exec(table, `DELETE FROM `+table+` WHERE user_id = ?`, userID)
+		} else {
+			err = exec(table, `UPDATE `+table+` SET user_id = ? WHERE user_id = ?`, opts.Pseudonym, userID)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, table := range userOwnedTables {
+		if err := exec(table, `DELETE FROM `+table+` WHERE user_id = ?`, userID); err != nil {
+			return nil, err
+		}
+	}
+	if err := exec("quotas", `DELETE FROM quotas WHERE subject = ?`, "user:"+userID); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
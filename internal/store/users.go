@@ -0,0 +1,39 @@
+package store
+
+import (
+	"time"
+)
+
+// UserSettings 사용자별 환경 설정
+type UserSettings struct {
+	UserID    string    `json:"user_id"`
+	Timezone  string    `json:"timezone,omitempty"` // 만료 시각 표시와 컨테이너 TZ에 사용
+	Locale    string    `json:"locale,omitempty"`   // 컨테이너 LANG/LC_ALL
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// 세션 종료 후 워크스페이스 처리 (비어 있으면 이미지/배포 설정의 workspace_retention)
+	WorkspacePolicy        string `json:"workspace_policy,omitempty"`
+	WorkspaceRetentionDays int    `json:"workspace_retention_days,omitempty"`
+}
+
+func (s *SQLiteStore) GetUserSettings(userID string) (*UserSettings, error) {
+	query := `SELECT user_id, timezone, locale, workspace_policy, workspace_retention_days, updated_at FROM user_settings WHERE user_id = ?`
+
+	settings := &UserSettings{}
+	if err := s.db.QueryRow(query, userID).Scan(&settings.UserID, &settings.Timezone, &settings.Locale,
+		&settings.WorkspacePolicy, &settings.WorkspaceRetentionDays, &settings.UpdatedAt); err != nil {
+		return nil, classify(err)
+	}
+	return settings, nil
+}
+
+func (s *SQLiteStore) SaveUserSettings(settings *UserSettings) error {
+	query := `
+		INSERT INTO user_settings (user_id, timezone, locale, workspace_policy, workspace_retention_days, updated_at) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET timezone = excluded.timezone, locale = excluded.locale,
+			workspace_policy = excluded.workspace_policy, workspace_retention_days = excluded.workspace_retention_days, updated_at = excluded.updated_at
+	`
+	_, err := s.db.Exec(query, settings.UserID, settings.Timezone, settings.Locale,
+		settings.WorkspacePolicy, settings.WorkspaceRetentionDays, settings.UpdatedAt)
+	return err
+}
@@ -0,0 +1,68 @@
+package store
+
+import (
+	"database/sql"
+	"time"
+)
+
+// WorkspaceLifecycle 세션이 끝난 뒤 보관을 기다리거나 보관된 워크스페이스 (사용자별 하나)
+type WorkspaceLifecycle struct {
+	UserID      string     `json:"user_id"`
+	SessionID   string     `json:"session_id"` // 마지막으로 워크스페이스를 쓴 세션
+	EndedAt     time.Time  `json:"ended_at"`
+	ArchiveAt   time.Time  `json:"archive_at"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	ArchivePath string     `json:"archive_path,omitempty"`
+}
+
+const workspaceLifecycleColumns = `user_id, session_id, ended_at, archive_at, archived_at, archive_path`
+
+func scanWorkspaceLifecycle(row rowScanner) (*WorkspaceLifecycle, error) {
+	lc := &WorkspaceLifecycle{}
+	var archivedAt sql.NullTime
+	if err := row.Scan(&lc.UserID, &lc.SessionID, &lc.EndedAt, &lc.ArchiveAt, &archivedAt, &lc.ArchivePath); err != nil {
+		return nil, classify(err)
+	}
+	if archivedAt.Valid {
+		lc.ArchivedAt = &archivedAt.Time
+	}
+	return lc, nil
+}
+
+func (s *SQLiteStore) SaveWorkspaceLifecycle(lc *WorkspaceLifecycle) error {
+	_, err := s.db.Exec(`
+		INSERT INTO workspace_lifecycle (`+workspaceLifecycleColumns+`) VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			session_id = excluded.session_id, ended_at = excluded.ended_at, archive_at = excluded.archive_at,
+			archived_at = excluded.archived_at, archive_path = excluded.archive_path
+	`, lc.UserID, lc.SessionID, lc.EndedAt, lc.ArchiveAt, lc.ArchivedAt, lc.ArchivePath)
+	return err
+}
+
+func (s *SQLiteStore) GetWorkspaceLifecycle(userID string) (*WorkspaceLifecycle, error) {
+	return scanWorkspaceLifecycle(s.db.QueryRow(`SELECT `+workspaceLifecycleColumns+` FROM workspace_lifecycle WHERE user_id = ?`, userID))
+}
+
+// ListWorkspaceLifecycle 보관 예정 시각 순
+func (s *SQLiteStore) ListWorkspaceLifecycle() ([]*WorkspaceLifecycle, error) {
+	rows, err := s.db.Query(`SELECT ` + workspaceLifecycleColumns + ` FROM workspace_lifecycle ORDER BY archive_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*WorkspaceLifecycle{}
+	for rows.Next() {
+		lc, err := scanWorkspaceLifecycle(rows)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, lc)
+	}
+	return entries, rows.Err()
+}
+
+func (s *SQLiteStore) DeleteWorkspaceLifecycle(userID string) error {
+	_, err := s.db.Exec(`DELETE FROM workspace_lifecycle WHERE user_id = ?`, userID)
+	return err
+}
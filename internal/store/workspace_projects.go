@@ -0,0 +1,36 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+)
+
+// assignProjectIDAttempts 여러 복제본이 같은 번호를 동시에 고르면 다시 시도할 횟수
+const assignProjectIDAttempts = 5
+
+// AssignProjectID 사용자의 XFS 프로젝트 ID (처음이면 base 이상에서 아직 쓰지 않은 가장 큰 번호 다음을 배정)
+// 배정한 ID는 워크스페이스를 지워도 다른 사용자에게 다시 주지 않음 (남은 파일이 다른 사용자 한도에 잡히지 않도록)
+func (s *SQLiteStore) AssignProjectID(userID string, base uint32) (uint32, error) {
+	for attempt := 0; ; attempt++ {
+		_, err := s.db.Exec(`INSERT INTO workspace_project_ids (user_id, project_id)
+			SELECT ?, COALESCE(MAX(project_id) + 1, ?) FROM workspace_project_ids WHERE project_id >= ?
+			ON CONFLICT(user_id) DO NOTHING`, userID, base, base)
+		if err == nil {
+			break
+		}
+		if err = classify(err); !errors.Is(err, errs.ErrAlreadyExists) || attempt+1 == assignProjectIDAttempts {
+			return 0, err
+		}
+	}
+	return s.GetProjectID(userID)
+}
+
+// GetProjectID 사용자에게 배정된 XFS 프로젝트 ID (없으면 ErrNotFound)
+func (s *SQLiteStore) GetProjectID(userID string) (uint32, error) {
+	var id int64
+	if err := s.db.QueryRow(`SELECT project_id FROM workspace_project_ids WHERE user_id = ?`, userID).Scan(&id); err != nil {
+		return 0, classify(err)
+	}
+	return uint32(id), nil
+}
@@ -0,0 +1,176 @@
+// Package tracing OpenTelemetry 트레이스 설정과 span 도우미
+//
+// 내보낼 곳(-otlp-endpoint)이 없으면 전역 TracerProvider가 기본 no-op으로 남아 span 비용이 거의 없다.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/sandman/gpu-ssh-gateway"
+
+// Config OTLP/HTTP 내보내기 설정
+type Config struct {
+	Endpoint    string            // host:port 또는 http(s)://host:port/path URL (비어 있으면 트레이싱 비활성)
+	Insecure    bool              // host:port 형식일 때 TLS 없이 전송
+	Headers     map[string]string // 인증 헤더 등
+	SampleRatio float64           // 부모가 없는 트레이스의 샘플링 비율 (0~1)
+	ServiceName string
+	NodeID      string
+}
+
+// Setup 전역 TracerProvider와 W3C traceparent 전파기를 설정하고, 남은 span을 내보내는 종료 함수를 반환
+func Setup(cfg Config) (func(context.Context) error, error) {
+	// 트레이싱을 끄더라도 들어온 traceparent는 하위 호출로 전달
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if cfg.Endpoint == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+	if cfg.SampleRatio < 0 || cfg.SampleRatio > 1 {
+		return nil, fmt.Errorf("샘플링 비율은 0~1이어야 합니다: %g", cfg.SampleRatio)
+	}
+
+	opts := []otlptracehttp.Option{}
+	if strings.HasPrefix(cfg.Endpoint, "http://") || strings.HasPrefix(cfg.Endpoint, "https://") {
+		u, err := url.Parse(cfg.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("잘못된 OTLP 엔드포인트: %v", err)
+		}
+		opts = append(opts, otlptracehttp.WithEndpoint(u.Host))
+		if u.Path != "" && u.Path != "/" {
+			opts = append(opts, otlptracehttp.WithURLPath(u.Path))
+		}
+		if u.Scheme == "http" {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+	} else {
+		opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+	}
+	if len(cfg.Headers) > 0 {
+		opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+	}
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("OTLP 내보내기 생성 실패: %v", err)
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = "sandman"
+	}
+	attrs := []attribute.KeyValue{semconv.ServiceName(serviceName)}
+	if cfg.NodeID != "" {
+		attrs = append(attrs, semconv.HostName(cfg.NodeID))
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, attrs...)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		log.Printf("⚠️ 트레이스 내보내기 실패: %v", err)
+	}))
+
+	log.Printf("🛰️ OpenTelemetry 트레이싱 활성화 (%s, 샘플링 %g)", cfg.Endpoint, cfg.SampleRatio)
+	return provider.Shutdown, nil
+}
+
+// ParseHeaders "key=value,key2=value2" 형식의 헤더 목록
+func ParseHeaders(s string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, fmt.Errorf("헤더는 key=value 형식이어야 합니다: %q", pair)
+		}
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
+// Start ctx의 span 아래에 새 span 시작 (ctx가 nil이면 새 트레이스)
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return otel.Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartLinked 새 트레이스의 span을 시작하고 links의 span들을 가리킴 (여러 요청을 묶어 처리하는 배치 작업용)
+func StartLinked(name string, links []trace.Link, attrs ...attribute.KeyValue) trace.Span {
+	_, span := otel.Tracer(tracerName).Start(context.Background(), name, trace.WithLinks(links...), trace.WithAttributes(attrs...))
+	return span
+}
+
+// End 오류가 있으면 span에 기록하고 종료
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Detach ctx의 span은 유지하되 취소와 마감 시간은 끊음 (요청이 끝나도 계속되는 작업의 부모로 사용)
+func Detach(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(ctx))
+}
+
+// Phases 하나의 부모 span 아래에서 차례로 이어지는 단계 span (새 단계를 시작하면 이전 단계가 끝남)
+type Phases struct {
+	parent context.Context
+	prefix string
+	ctx    context.Context
+	span   trace.Span
+}
+
+// NewPhases parent span 아래에 prefix+이름으로 단계 span을 만듦
+func NewPhases(parent context.Context, prefix string) *Phases {
+	return &Phases{parent: parent, prefix: prefix, ctx: parent}
+}
+
+// Start 이전 단계를 끝내고 새 단계 시작
+func (p *Phases) Start(name string, attrs ...attribute.KeyValue) {
+	p.End(nil)
+	p.ctx, p.span = Start(p.parent, p.prefix+name, attrs...)
+}
+
+// Context 현재 단계 span을 담은 컨텍스트 (하위 호출의 부모)
+func (p *Phases) Context() context.Context {
+	return p.ctx
+}
+
+// End 진행 중인 단계를 끝냄 (err는 그 단계의 실패로 기록)
+func (p *Phases) End(err error) {
+	if p.span == nil {
+		return
+	}
+	End(p.span, err)
+	p.span = nil
+	p.ctx = p.parent
+}
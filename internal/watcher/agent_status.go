@@ -0,0 +1,62 @@
+package watcher
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+// AgentStatusWatcher 주기적으로 세션 워크스페이스의 상태 파일(남은 시간, 한도, 경고)을 갱신
+type AgentStatusWatcher struct {
+	sessionService *session.Service
+	interval       time.Duration
+	stopChan       chan struct{}
+	running        bool
+}
+
+func NewAgentStatusWatcher(sessionService *session.Service, interval time.Duration) *AgentStatusWatcher {
+	return &AgentStatusWatcher{
+		sessionService: sessionService,
+		interval:       interval,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+func (w *AgentStatusWatcher) Start() {
+	if w.running {
+		return
+	}
+
+	w.running = true
+	go w.watch()
+	log.Printf("📟 세션 상태 파일 갱신 시작됨 (간격: %v)", w.interval)
+}
+
+func (w *AgentStatusWatcher) Stop() {
+	if !w.running {
+		return
+	}
+
+	w.running = false
+	close(w.stopChan)
+	log.Println("📟 세션 상태 파일 갱신 중지됨")
+}
+
+func (w *AgentStatusWatcher) watch() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.sessionService.RefreshAgentStatus(); err != nil && !errors.Is(err, session.ErrShuttingDown) {
+				logging.Repeated("agent-status", "⚠️ 세션 상태 파일 갱신 중 오류: %v", err)
+			}
+		case <-w.stopChan:
+			return
+		}
+	}
+}
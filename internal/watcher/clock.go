@@ -0,0 +1,66 @@
+package watcher
+
+import (
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+// ClockWatcher 주기적으로 호스트 NTP 동기화와 세션 컨테이너 시계 차이를 확인해 노드 상태에 반영
+type ClockWatcher struct {
+	sessionService *session.Service
+	interval       time.Duration
+	stopChan       chan struct{}
+	running        bool
+}
+
+func NewClockWatcher(sessionService *session.Service, interval time.Duration) *ClockWatcher {
+	return &ClockWatcher{
+		sessionService: sessionService,
+		interval:       interval,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+func (w *ClockWatcher) Start() {
+	if w.running {
+		return
+	}
+
+	w.running = true
+	go w.watch()
+	log.Printf("⏱️ 시계 차이 확인기 시작됨 (간격: %v)", w.interval)
+}
+
+func (w *ClockWatcher) Stop() {
+	if !w.running {
+		return
+	}
+
+	w.running = false
+	close(w.stopChan)
+	log.Println("⏱️ 시계 차이 확인기 중지됨")
+}
+
+func (w *ClockWatcher) watch() {
+	// 시작 직후 한 번 확인해 첫 하트비트부터 시계 상태를 보고
+	if err := w.sessionService.ProbeClocks(); err != nil {
+		logging.Repeated("clock", "⚠️ 시계 차이 확인 중 오류: %v", err)
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.sessionService.ProbeClocks(); err != nil {
+				logging.Repeated("clock", "⚠️ 시계 차이 확인 중 오류: %v", err)
+			}
+		case <-w.stopChan:
+			return
+		}
+	}
+}
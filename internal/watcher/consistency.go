@@ -0,0 +1,62 @@
+package watcher
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+// ConsistencyWatcher 주기적으로 세션 기록, 컨테이너, GPU 할당, 라우트, 포트/IP 기록을 맞춰 보고 안전한 불일치를 고침
+type ConsistencyWatcher struct {
+	sessionService *session.Service
+	interval       time.Duration
+	stopChan       chan struct{}
+	running        bool
+}
+
+func NewConsistencyWatcher(sessionService *session.Service, interval time.Duration) *ConsistencyWatcher {
+	return &ConsistencyWatcher{
+		sessionService: sessionService,
+		interval:       interval,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+func (w *ConsistencyWatcher) Start() {
+	if w.running {
+		return
+	}
+
+	w.running = true
+	go w.watch()
+	log.Printf("🩺 일관성 검사 감시자 시작됨 (간격: %v)", w.interval)
+}
+
+func (w *ConsistencyWatcher) Stop() {
+	if !w.running {
+		return
+	}
+
+	w.running = false
+	close(w.stopChan)
+	log.Println("🩺 일관성 검사 감시자 중지됨")
+}
+
+func (w *ConsistencyWatcher) watch() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := w.sessionService.CheckConsistency(); err != nil && !errors.Is(err, session.ErrShuttingDown) {
+				logging.Repeated("consistency", "⚠️ 일관성 검사 중 오류: %v", err)
+			}
+		case <-w.stopChan:
+			return
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package watcher
+
+import (
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+// CrashLoopWatcher 주기적으로 세션 컨테이너 재시작 횟수를 확인해 크래시 루프인 세션을 failed로 표시
+type CrashLoopWatcher struct {
+	sessionService *session.Service
+	interval       time.Duration
+	stopChan       chan struct{}
+	running        bool
+}
+
+func NewCrashLoopWatcher(sessionService *session.Service, interval time.Duration) *CrashLoopWatcher {
+	return &CrashLoopWatcher{
+		sessionService: sessionService,
+		interval:       interval,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+func (w *CrashLoopWatcher) Start() {
+	if w.running {
+		return
+	}
+
+	w.running = true
+	go w.watch()
+	log.Printf("💥 크래시 루프 감지기 시작됨 (간격: %v)", w.interval)
+}
+
+func (w *CrashLoopWatcher) Stop() {
+	if !w.running {
+		return
+	}
+
+	w.running = false
+	close(w.stopChan)
+	log.Println("💥 크래시 루프 감지기 중지됨")
+}
+
+func (w *CrashLoopWatcher) watch() {
+	// 시작 직후 한 번 실행해 현재 재시작 횟수를 기준점으로 기록
+	if err := w.sessionService.CheckCrashLoops(); err != nil {
+		logging.Repeated("crash-loop", "⚠️ 크래시 루프 확인 중 오류: %v", err)
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.sessionService.CheckCrashLoops(); err != nil {
+				logging.Repeated("crash-loop", "⚠️ 크래시 루프 확인 중 오류: %v", err)
+			}
+		case <-w.stopChan:
+			return
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package watcher
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+const (
+	// maxPendingDNSQueries 기록을 기다리는 질의 상한 (DB가 느려도 메모리가 계속 늘지 않도록, 넘으면 버림)
+	maxPendingDNSQueries  = 10000
+	dnsAuditPruneInterval = time.Hour
+)
+
+// DNSAuditWatcher DNS 감사 사이드카 로그를 따라가며 세션별 질의를 모아 주기적으로 기록하고, 보존 기간이 지난 기록을 삭제
+type DNSAuditWatcher struct {
+	sessionService *session.Service
+	dockerClient   *docker.Client
+	interval       time.Duration
+	stopChan       chan struct{}
+	cancel         context.CancelFunc
+	running        bool
+
+	mu      sync.Mutex
+	pending []docker.DNSQuery
+	dropped int
+}
+
+func NewDNSAuditWatcher(sessionService *session.Service, dockerClient *docker.Client, interval time.Duration) *DNSAuditWatcher {
+	return &DNSAuditWatcher{
+		sessionService: sessionService,
+		dockerClient:   dockerClient,
+		interval:       interval,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+func (w *DNSAuditWatcher) Start() {
+	if w.running {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.running = true
+	go w.follow(ctx)
+	go w.watch()
+	log.Printf("🔎 DNS 감사 수집기 시작됨 (기록 간격: %v)", w.interval)
+}
+
+func (w *DNSAuditWatcher) Stop() {
+	if !w.running {
+		return
+	}
+
+	w.running = false
+	w.cancel()
+	close(w.stopChan)
+	w.flush()
+	log.Println("🔎 DNS 감사 수집기 중지됨")
+}
+
+// follow 로그 스트림이 끊기면 마지막으로 받은 질의 이후부터 다시 따라감
+func (w *DNSAuditWatcher) follow(ctx context.Context) {
+	since := time.Now()
+	backoff := time.Second
+	for {
+		err := w.dockerClient.FollowDNSLog(ctx, since, func(query docker.DNSQuery) {
+			since = query.At.Add(time.Nanosecond)
+			backoff = time.Second
+			w.add(query)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("⚠️ DNS 사이드카 로그 스트림 끊김 (%v), %v 후 다시 연결", err, backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, time.Minute)
+	}
+}
+
+func (w *DNSAuditWatcher) add(query docker.DNSQuery) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.pending) >= maxPendingDNSQueries {
+		w.dropped++
+		return
+	}
+	w.pending = append(w.pending, query)
+}
+
+func (w *DNSAuditWatcher) watch() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	pruneTicker := time.NewTicker(dnsAuditPruneInterval)
+	defer pruneTicker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.flush()
+		case <-pruneTicker.C:
+			if err := w.sessionService.PruneDNSQueries(); err != nil {
+				logging.Repeated("dns-audit-purge", "⚠️ DNS 감사 기록 정리 중 오류: %v", err)
+			}
+		case <-w.stopChan:
+			return
+		}
+	}
+}
+
+func (w *DNSAuditWatcher) flush() {
+	w.mu.Lock()
+	queries, dropped := w.pending, w.dropped
+	w.pending, w.dropped = nil, 0
+	w.mu.Unlock()
+
+	if dropped > 0 {
+		log.Printf("⚠️ DNS 감사 대기열이 가득 차 질의 %d건을 버렸습니다", dropped)
+	}
+	if err := w.sessionService.RecordDNSQueries(queries); err != nil {
+		log.Printf("⚠️ DNS 질의 %d건 기록 실패: %v", len(queries), err)
+	}
+}
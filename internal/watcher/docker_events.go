@@ -0,0 +1,76 @@
+package watcher
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+// DockerEventWatcher Docker 이벤트 스트림에서 이 배포의 네트워크/볼륨/이미지 삭제를 받아 세션 서비스에 전달
+// 연결(재연결)할 때마다 리소스를 다시 확인해 스트림이 끊긴 사이의 변경도 복구
+type DockerEventWatcher struct {
+	sessionService *session.Service
+	dockerClient   *docker.Client
+	cancel         context.CancelFunc
+	running        bool
+}
+
+func NewDockerEventWatcher(sessionService *session.Service, dockerClient *docker.Client) *DockerEventWatcher {
+	return &DockerEventWatcher{
+		sessionService: sessionService,
+		dockerClient:   dockerClient,
+	}
+}
+
+func (w *DockerEventWatcher) Start() {
+	if w.running {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.running = true
+	go w.follow(ctx)
+	log.Println("📡 Docker 이벤트 감시기 시작됨")
+}
+
+func (w *DockerEventWatcher) Stop() {
+	if !w.running {
+		return
+	}
+
+	w.running = false
+	w.cancel()
+	log.Println("📡 Docker 이벤트 감시기 중지됨")
+}
+
+// follow 스트림이 끊기면 마지막으로 받은 이벤트 이후부터 다시 따라감
+func (w *DockerEventWatcher) follow(ctx context.Context) {
+	since := time.Now()
+	backoff := time.Second
+	for {
+		if err := w.sessionService.ReconcileDockerResources(); err != nil {
+			logging.Repeated("docker-reconcile", "⚠️ Docker 리소스 확인 중 오류: %v", err)
+		}
+		err := w.dockerClient.FollowResourceEvents(ctx, since, func(event docker.ResourceEvent) {
+			since = event.At.Add(time.Nanosecond)
+			backoff = time.Second
+			w.sessionService.HandleDockerEvent(event)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		logging.Repeated("docker-events", "⚠️ %v, %v 후 다시 연결", err, backoff)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, time.Minute)
+	}
+}
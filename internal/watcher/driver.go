@@ -0,0 +1,62 @@
+package watcher
+
+import (
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+// DriverWatcher 주기적으로 NVIDIA 드라이버/CUDA/NVML 버전과 MIG 모드를 다시 읽어 GPU 정보와 노드 하트비트에 반영
+type DriverWatcher struct {
+	sessionService *session.Service
+	interval       time.Duration
+	stopChan       chan struct{}
+	running        bool
+}
+
+func NewDriverWatcher(sessionService *session.Service, interval time.Duration) *DriverWatcher {
+	return &DriverWatcher{
+		sessionService: sessionService,
+		interval:       interval,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+func (w *DriverWatcher) Start() {
+	if w.running {
+		return
+	}
+
+	w.running = true
+	go w.watch()
+	log.Printf("🎮 드라이버 정보 갱신기 시작됨 (간격: %v)", w.interval)
+}
+
+func (w *DriverWatcher) Stop() {
+	if !w.running {
+		return
+	}
+
+	w.running = false
+	close(w.stopChan)
+	log.Println("🎮 드라이버 정보 갱신기 중지됨")
+}
+
+func (w *DriverWatcher) watch() {
+	// 시작 시 값은 GPU 관리자가 탐색하면서 이미 읽었음
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.sessionService.RefreshDriverInfo(); err != nil {
+				logging.Repeated("driver-info", "⚠️ 드라이버 정보 갱신 중 오류: %v", err)
+			}
+		case <-w.stopChan:
+			return
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package watcher
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+// InactivityWatcher 주기적으로 유휴 세션을 잠그고(컨테이너 일시 정지), 잠근 채 보류 시간이 지난 세션의 GPU를 회수
+type InactivityWatcher struct {
+	sessionService *session.Service
+	interval       time.Duration
+	stopChan       chan struct{}
+	running        bool
+}
+
+func NewInactivityWatcher(sessionService *session.Service, interval time.Duration) *InactivityWatcher {
+	return &InactivityWatcher{
+		sessionService: sessionService,
+		interval:       interval,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+func (w *InactivityWatcher) Start() {
+	if w.running {
+		return
+	}
+
+	w.running = true
+	go w.watch()
+	log.Printf("💤 비활성 세션 잠금 감시자 시작됨 (간격: %v)", w.interval)
+}
+
+func (w *InactivityWatcher) Stop() {
+	if !w.running {
+		return
+	}
+
+	w.running = false
+	close(w.stopChan)
+	log.Println("💤 비활성 세션 잠금 감시자 중지됨")
+}
+
+func (w *InactivityWatcher) watch() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.sessionService.CheckInactivity(); err != nil && !errors.Is(err, session.ErrShuttingDown) {
+				logging.Repeated("inactivity", "⚠️ 비활성 세션 확인 중 오류: %v", err)
+			}
+		case <-w.stopChan:
+			return
+		}
+	}
+}
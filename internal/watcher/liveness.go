@@ -0,0 +1,66 @@
+package watcher
+
+import (
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+// LivenessWatcher 주기적으로 세션 컨테이너와 SSH 응답을 확인해 /sessions/:id/alive 캐시를 갱신
+type LivenessWatcher struct {
+	sessionService *session.Service
+	interval       time.Duration
+	stopChan       chan struct{}
+	running        bool
+}
+
+func NewLivenessWatcher(sessionService *session.Service, interval time.Duration) *LivenessWatcher {
+	return &LivenessWatcher{
+		sessionService: sessionService,
+		interval:       interval,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+func (w *LivenessWatcher) Start() {
+	if w.running {
+		return
+	}
+
+	w.running = true
+	go w.watch()
+	log.Printf("💓 세션 상태 확인기 시작됨 (간격: %v)", w.interval)
+}
+
+func (w *LivenessWatcher) Stop() {
+	if !w.running {
+		return
+	}
+
+	w.running = false
+	close(w.stopChan)
+	log.Println("💓 세션 상태 확인기 중지됨")
+}
+
+func (w *LivenessWatcher) watch() {
+	// 시작 직후 한 번 채워 두어 첫 폴링부터 캐시에서 응답
+	if err := w.sessionService.RefreshLiveness(); err != nil {
+		logging.Repeated("liveness", "⚠️ 세션 상태 확인 중 오류: %v", err)
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.sessionService.RefreshLiveness(); err != nil {
+				logging.Repeated("liveness", "⚠️ 세션 상태 확인 중 오류: %v", err)
+			}
+		case <-w.stopChan:
+			return
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package watcher
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+// NodeAgent 이 호스트를 노드로 등록하고 주기적으로 하트비트를 보내며,
+// 하트비트가 끊긴 다른 노드를 unreachable로 표시
+type NodeAgent struct {
+	sessionService *session.Service
+	registration   session.NodeRegistration
+	interval       time.Duration
+	timeout        time.Duration
+	stopChan       chan struct{}
+	running        bool
+}
+
+func NewNodeAgent(sessionService *session.Service, registration session.NodeRegistration, interval time.Duration, missedHeartbeats int) *NodeAgent {
+	return &NodeAgent{
+		sessionService: sessionService,
+		registration:   registration,
+		interval:       interval,
+		timeout:        interval * time.Duration(missedHeartbeats),
+		stopChan:       make(chan struct{}),
+	}
+}
+
+func (a *NodeAgent) Start() error {
+	if a.running {
+		return nil
+	}
+
+	if _, err := a.sessionService.RegisterNode(a.registration); err != nil {
+		return err
+	}
+
+	a.running = true
+	go a.watch()
+	log.Printf("🖥️ 노드 에이전트 시작됨 (노드: %s, 하트비트 간격: %v, unreachable 기준: %v)", a.registration.ID, a.interval, a.timeout)
+	return nil
+}
+
+func (a *NodeAgent) Stop() {
+	if !a.running {
+		return
+	}
+
+	a.running = false
+	close(a.stopChan)
+	log.Println("🖥️ 노드 에이전트 중지됨")
+}
+
+func (a *NodeAgent) watch() {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			a.heartbeat()
+			if err := a.sessionService.CheckNodeHeartbeats(a.timeout); err != nil {
+				logging.Repeated("node-status", "⚠️ 노드 상태 확인 중 오류: %v", err)
+			}
+		case <-a.stopChan:
+			return
+		}
+	}
+}
+
+func (a *NodeAgent) heartbeat() {
+	err := a.sessionService.HeartbeatNode(a.registration.ID, a.sessionService.LocalHeartbeat())
+	if errors.Is(err, errs.ErrNotFound) {
+		// 관리자가 등록을 지웠으면 다시 등록
+		log.Printf("🖥️ 노드 %s 등록 정보가 없어 다시 등록합니다", a.registration.ID)
+		_, err = a.sessionService.RegisterNode(a.registration)
+	}
+	if err != nil {
+		log.Printf("⚠️ 노드 하트비트 실패: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package watcher
+
+import (
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/report"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+// reportDelay 구간이 끝난 직후 종료 처리 중인 세션이 기록되도록 잠시 기다린 뒤 내보냄
+const reportDelay = time.Minute
+
+// ReportWatcher interval 구간이 끝날 때마다 종료된 세션 기록을 대상 위치로 내보냄
+type ReportWatcher struct {
+	sessionService *session.Service
+	destination    report.Destination
+	format         string
+	interval       time.Duration
+	stopChan       chan struct{}
+	running        bool
+}
+
+func NewReportWatcher(sessionService *session.Service, destination report.Destination, format string, interval time.Duration) *ReportWatcher {
+	return &ReportWatcher{
+		sessionService: sessionService,
+		destination:    destination,
+		format:         format,
+		interval:       interval,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+func (w *ReportWatcher) Start() {
+	if w.running {
+		return
+	}
+
+	w.running = true
+	go w.watch()
+	log.Printf("📑 세션 보고서 작업 시작됨 (간격: %v, 형식: %s, 대상: %s)", w.interval, w.format, w.destination)
+}
+
+func (w *ReportWatcher) Stop() {
+	if !w.running {
+		return
+	}
+
+	w.running = false
+	close(w.stopChan)
+	log.Println("📑 세션 보고서 작업 중지됨")
+}
+
+func (w *ReportWatcher) watch() {
+	for {
+		// 다음 구간 경계(UTC 기준 interval 단위)까지 대기
+		next := time.Now().UTC().Truncate(w.interval).Add(w.interval).Add(reportDelay)
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-timer.C:
+			if err := w.sessionService.RunSessionReport(w.destination, w.format, w.interval); err != nil {
+				logging.Repeated("session-report", "⚠️ 세션 보고서 생성 중 오류: %v", err)
+			}
+		case <-w.stopChan:
+			timer.Stop()
+			return
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package watcher
+
+import (
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+// RestorePointWatcher 주기적으로 세션 워크스페이스의 복원 지점을 생성
+type RestorePointWatcher struct {
+	sessionService *session.Service
+	interval       time.Duration
+	stopChan       chan struct{}
+	running        bool
+}
+
+func NewRestorePointWatcher(sessionService *session.Service, interval time.Duration) *RestorePointWatcher {
+	return &RestorePointWatcher{
+		sessionService: sessionService,
+		interval:       interval,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+func (w *RestorePointWatcher) Start() {
+	if w.running {
+		return
+	}
+
+	w.running = true
+	go w.watch()
+	log.Printf("📸 복원 지점 생성기 시작됨 (간격: %v)", w.interval)
+}
+
+func (w *RestorePointWatcher) Stop() {
+	if !w.running {
+		return
+	}
+
+	w.running = false
+	close(w.stopChan)
+	log.Println("📸 복원 지점 생성기 중지됨")
+}
+
+func (w *RestorePointWatcher) watch() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.sessionService.CreateDueRestorePoints(); err != nil {
+				logging.Repeated("restore-points", "⚠️ 복원 지점 생성 중 오류: %v", err)
+			}
+		case <-w.stopChan:
+			return
+		}
+	}
+}
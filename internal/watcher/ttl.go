@@ -1,9 +1,11 @@
 package watcher
 
 import (
+	"errors"
 	"log"
 	"time"
 
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
 	"github.com/sandman/gpu-ssh-gateway/internal/session"
 )
 
@@ -49,8 +51,8 @@ func (w *TTLWatcher) watch() {
 	for {
 		select {
 		case <-ticker.C:
-			if err := w.sessionService.CleanupExpiredSessions(); err != nil {
-				log.Printf("⚠️ 만료된 세션 정리 중 오류: %v", err)
+			if err := w.sessionService.CleanupExpiredSessions(); err != nil && !errors.Is(err, session.ErrShuttingDown) {
+				logging.Repeated("ttl-cleanup", "⚠️ 만료된 세션 정리 중 오류: %v", err)
 			}
 		case <-w.stopChan:
 			return
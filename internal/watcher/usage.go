@@ -0,0 +1,61 @@
+package watcher
+
+import (
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+// UsageWatcher 주기적으로 세션 컨테이너의 네트워크 사용량을 수집
+type UsageWatcher struct {
+	sessionService *session.Service
+	interval       time.Duration
+	stopChan       chan struct{}
+	running        bool
+}
+
+func NewUsageWatcher(sessionService *session.Service, interval time.Duration) *UsageWatcher {
+	return &UsageWatcher{
+		sessionService: sessionService,
+		interval:       interval,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+func (w *UsageWatcher) Start() {
+	if w.running {
+		return
+	}
+
+	w.running = true
+	go w.watch()
+	log.Printf("📊 사용량 수집기 시작됨 (간격: %v)", w.interval)
+}
+
+func (w *UsageWatcher) Stop() {
+	if !w.running {
+		return
+	}
+
+	w.running = false
+	close(w.stopChan)
+	log.Println("📊 사용량 수집기 중지됨")
+}
+
+func (w *UsageWatcher) watch() {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.sessionService.SampleNetworkUsage(); err != nil {
+				logging.Repeated("usage", "⚠️ 사용량 수집 중 오류: %v", err)
+			}
+		case <-w.stopChan:
+			return
+		}
+	}
+}
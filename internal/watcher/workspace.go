@@ -0,0 +1,66 @@
+package watcher
+
+import (
+	"log"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/session"
+)
+
+// WorkspaceLifecycleWatcher 보관 시각이 지난 워크스페이스를 주기적으로 보관
+type WorkspaceLifecycleWatcher struct {
+	sessionService *session.Service
+	interval       time.Duration
+	stopChan       chan struct{}
+	running        bool
+}
+
+func NewWorkspaceLifecycleWatcher(sessionService *session.Service, interval time.Duration) *WorkspaceLifecycleWatcher {
+	return &WorkspaceLifecycleWatcher{
+		sessionService: sessionService,
+		interval:       interval,
+		stopChan:       make(chan struct{}),
+	}
+}
+
+func (w *WorkspaceLifecycleWatcher) Start() {
+	if w.running {
+		return
+	}
+
+	w.running = true
+	go w.watch()
+	log.Printf("🗄️ 워크스페이스 수명 주기 작업 시작됨 (간격: %v)", w.interval)
+}
+
+func (w *WorkspaceLifecycleWatcher) Stop() {
+	if !w.running {
+		return
+	}
+
+	w.running = false
+	close(w.stopChan)
+	log.Println("🗄️ 워크스페이스 수명 주기 작업 중지됨")
+}
+
+func (w *WorkspaceLifecycleWatcher) watch() {
+	// 재시작 동안 밀린 보관을 시작 직후 처리
+	if err := w.sessionService.EnforceWorkspaceRetention(); err != nil {
+		logging.Repeated("workspace-lifecycle", "⚠️ 워크스페이스 수명 주기 작업 중 오류: %v", err)
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := w.sessionService.EnforceWorkspaceRetention(); err != nil {
+				logging.Repeated("workspace-lifecycle", "⚠️ 워크스페이스 수명 주기 작업 중 오류: %v", err)
+			}
+		case <-w.stopChan:
+			return
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/command"
+)
+
+// HostProvisioner 오케스트레이터 프로세스가 호스트의 워크스페이스 경로에 직접 준비
+type HostProvisioner struct{}
+
+func (HostProvisioner) Name() string { return ModeHost }
+
+func (HostProvisioner) Provision(ctx context.Context, spec Spec) ([]StepResult, error) {
+	if spec.HostDir == "" {
+		return nil, fmt.Errorf("호스트에서 접근할 수 있는 워크스페이스 경로가 없습니다 (init-container 방식을 사용하세요)")
+	}
+	h := &hostRun{spec: spec}
+	steps := []struct {
+		name string
+		fn   func(ctx context.Context) (string, error)
+	}{
+		{"mkdir", h.mkdir},
+		{"seed", h.seed},
+		{"git", h.clone},
+		{"datasets", h.link},
+		{"chown", h.chown},
+	}
+
+	var results []StepResult
+	for _, step := range steps {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		start := time.Now()
+		output, err := step.fn(ctx)
+		result := StepResult{Name: step.name, Duration: time.Since(start), Output: tail(output)}
+		if err != nil {
+			result.Error = err.Error()
+			results = append(results, result)
+			return results, fmt.Errorf("%s: %v", step.name, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// hostRun 한 번의 준비에서 새로 만든 경로 (소유권은 새로 만든 것에만 적용)
+type hostRun struct {
+	spec    Spec
+	created []string
+	trees   []string // 통째로 소유권을 바꿀 디렉토리 (clone한 저장소)
+}
+
+func (h *hostRun) mkdir(context.Context) (string, error) {
+	if _, err := os.Stat(h.spec.HostDir); os.IsNotExist(err) {
+		if err := os.MkdirAll(h.spec.HostDir, 0755); err != nil {
+			return "", err
+		}
+		h.created = append(h.created, h.spec.HostDir)
+	}
+	return "", nil
+}
+
+func (h *hostRun) seed(context.Context) (string, error) {
+	bashrc := filepath.Join(h.spec.HostDir, ".bashrc")
+	if _, err := os.Lstat(bashrc); os.IsNotExist(err) {
+		if err := os.WriteFile(bashrc, []byte(DefaultBashrc), 0644); err != nil {
+			return "", err
+		}
+		h.created = append(h.created, bashrc)
+	}
+	if h.spec.TemplateDir == "" {
+		return "", nil
+	}
+
+	copied := 0
+	err := filepath.WalkDir(h.spec.TemplateDir, func(src string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(h.spec.TemplateDir, src)
+		if err != nil || rel == "." {
+			return err
+		}
+		dst := filepath.Join(h.spec.HostDir, rel)
+		if _, err := os.Lstat(dst); err == nil {
+			// 사용자가 바꾼 파일은 덮어쓰지 않음
+			return nil
+		}
+		switch {
+		case d.IsDir():
+			if err := os.Mkdir(dst, 0755); err != nil {
+				return err
+			}
+		case d.Type().IsRegular():
+			if err := copyFile(src, dst); err != nil {
+				return err
+			}
+			copied++
+		default:
+			return nil
+		}
+		h.created = append(h.created, dst)
+		return nil
+	})
+	return fmt.Sprintf("템플릿 파일 %d개 복사", copied), err
+}
+
+func (h *hostRun) clone(ctx context.Context) (string, error) {
+	var output []byte
+	for _, repo := range h.spec.Repos {
+		dst := filepath.Join(h.spec.HostDir, repo.Path)
+		if _, err := os.Stat(dst); err == nil {
+			continue
+		}
+		if err := h.mkdirParent(dst); err != nil {
+			return string(output), err
+		}
+		args := []string{"clone", "--quiet"}
+		if repo.Ref != "" {
+			args = append(args, "--branch", repo.Ref)
+		}
+		args = append(args, "--", repo.URL, dst)
+		// 사설 저장소 인증(SSH 에이전트, 자격 증명 도우미)이 환경 변수를 쓰므로 오케스트레이터 환경을 물려줌
+		result, err := command.Run(ctx, command.Options{Env: []string{"GIT_TERMINAL_PROMPT=0"}, InheritEnv: true, Combined: true}, "git", args...)
+		output = append(output, result.Stdout...)
+		if err != nil {
+			// 받다가 끊긴 디렉토리는 다음 시도에서 다시 받도록 지움
+			os.RemoveAll(dst)
+			return string(output), fmt.Errorf("%s: %v", repo.URL, err)
+		}
+		h.trees = append(h.trees, dst)
+	}
+	return string(output), nil
+}
+
+func (h *hostRun) link(context.Context) (string, error) {
+	for _, dataset := range h.spec.Datasets {
+		link := filepath.Join(h.spec.HostDir, dataset.Link)
+		info, err := os.Lstat(link)
+		if err == nil {
+			if info.Mode()&os.ModeSymlink == 0 {
+				return "", fmt.Errorf("%s가 이미 링크가 아닌 파일로 있습니다", dataset.Link)
+			}
+			if current, _ := os.Readlink(link); current == dataset.Target {
+				continue
+			}
+			if err := os.Remove(link); err != nil {
+				return "", err
+			}
+		}
+		if err := h.mkdirParent(link); err != nil {
+			return "", err
+		}
+		if err := os.Symlink(dataset.Target, link); err != nil {
+			return "", err
+		}
+		h.created = append(h.created, link)
+	}
+	return "", nil
+}
+
+// mkdirParent 상위 디렉토리가 없으면 만들고 소유권 설정 대상에 추가
+func (h *hostRun) mkdirParent(p string) error {
+	dir := filepath.Dir(p)
+	if _, err := os.Stat(dir); err == nil {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	h.created = append(h.created, dir)
+	return nil
+}
+
+func (h *hostRun) chown(context.Context) (string, error) {
+	// root가 아니면 소유권을 바꿀 수 없으므로 건너뜀 (세션 컨테이너가 root로 워크스페이스를 다루는 배포)
+	if os.Geteuid() != 0 {
+		return "root가 아니어서 건너뜀", nil
+	}
+	for _, p := range h.created {
+		if err := os.Lchown(p, h.spec.UID, h.spec.GID); err != nil {
+			return "", err
+		}
+	}
+	for _, root := range h.trees {
+		err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			return os.Lchown(p, h.spec.UID, h.spec.GID)
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%d개 경로", len(h.created)+len(h.trees)), nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,132 @@
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/docker"
+)
+
+const (
+	// initWorkspaceMount init 컨테이너 안의 워크스페이스 경로 (세션 컨테이너와 같음)
+	initWorkspaceMount = "/workspace"
+	// initTemplateMount init 컨테이너 안의 템플릿 디렉토리 경로 (읽기 전용)
+	initTemplateMount = "/sandman/templates"
+)
+
+// ContainerProvisioner 워크스페이스를 마운트한 일회용 컨테이너에서 Script를 실행
+// 이미지에는 /bin/sh, find, cp, chown, ln과 (저장소를 받으려면) git이 있어야 함
+type ContainerProvisioner struct {
+	client *docker.Client
+	image  string
+}
+
+// NewContainerProvisioner init 컨테이너 방식 Provisioner
+func NewContainerProvisioner(client *docker.Client, image string) *ContainerProvisioner {
+	return &ContainerProvisioner{client: client, image: image}
+}
+
+func (p *ContainerProvisioner) Name() string { return ModeInitContainer }
+
+func (p *ContainerProvisioner) Provision(ctx context.Context, spec Spec) ([]StepResult, error) {
+	var mounts []docker.InitMount
+	switch {
+	case spec.Volume != "":
+		mounts = append(mounts, docker.InitMount{Source: spec.Volume, Target: initWorkspaceMount, Volume: true})
+	case spec.HostDir != "":
+		mounts = append(mounts, docker.InitMount{Source: spec.HostDir, Target: initWorkspaceMount})
+	default:
+		return nil, fmt.Errorf("마운트할 워크스페이스가 없습니다")
+	}
+	templates := ""
+	if spec.TemplateDir != "" {
+		mounts = append(mounts, docker.InitMount{Source: spec.TemplateDir, Target: initTemplateMount, ReadOnly: true})
+		templates = initTemplateMount
+	}
+
+	start := time.Now()
+	output, err := p.client.RunInitContainer(ctx, spec.UserID, p.image, Script(spec, initWorkspaceMount, templates), mounts)
+	result := StepResult{Name: "init-container", Duration: time.Since(start), Output: tail(output)}
+	if err != nil {
+		result.Error = err.Error()
+		return []StepResult{result}, err
+	}
+	return []StepResult{result}, nil
+}
+
+// Script HostProvisioner와 같은 단계를 수행하는 POSIX 셸 스크립트 (root로 실행)
+// root는 워크스페이스가 마운트된 경로, templates는 템플릿 디렉토리가 마운트된 경로 ("" 이면 생략)
+// 다른 컨테이너 백엔드의 init 단계에서도 그대로 쓸 수 있도록 외부 도구 없이 셸 명령만 사용
+func Script(spec Spec, root, templates string) string {
+	owner := fmt.Sprintf("%d:%d", spec.UID, spec.GID)
+	var b strings.Builder
+	line := func(format string, args ...interface{}) {
+		fmt.Fprintf(&b, format+"\n", args...)
+	}
+
+	line("set -eu")
+	line("echo '== mkdir'")
+	line("mkdir -p %s", quote(root))
+	line("cd %s", quote(root))
+
+	line("echo '== seed'")
+	line("if [ ! -e .bashrc ] && [ ! -L .bashrc ]; then")
+	line("  printf '%%s' %s > .bashrc", quote(DefaultBashrc))
+	line("  chown %s . .bashrc", owner)
+	line("fi")
+	if templates != "" {
+		line("copied=0")
+		line("cd %s", quote(templates))
+		line("find . -mindepth 1 > /tmp/sandman-templates")
+		line("while IFS= read -r p; do")
+		line("  dst=%s/\"$p\"", quote(root))
+		line("  if [ -e \"$dst\" ] || [ -L \"$dst\" ]; then continue; fi")
+		line("  if [ -d \"$p\" ]; then mkdir \"$dst\"; elif [ -f \"$p\" ]; then cp -p \"$p\" \"$dst\"; copied=$((copied+1)); else continue; fi")
+		line("  chown -h %s \"$dst\"", owner)
+		line("done < /tmp/sandman-templates")
+		line("echo \"템플릿 파일 ${copied}개 복사\"")
+		line("cd %s", quote(root))
+	}
+
+	if len(spec.Repos) > 0 {
+		line("echo '== git'")
+		line("export GIT_TERMINAL_PROMPT=0")
+	}
+	for _, repo := range spec.Repos {
+		dst := quote(path.Join(root, repo.Path))
+		branch := ""
+		if repo.Ref != "" {
+			branch = "--branch " + quote(repo.Ref) + " "
+		}
+		line("if [ ! -e %s ]; then", dst)
+		parent(&b, path.Dir(path.Join(root, repo.Path)), owner, "  ")
+		line("  git clone --quiet %s-- %s %s || { rm -rf %s; echo %s >&2; exit 1; }", branch, quote(repo.URL), dst, dst, quote("clone 실패: "+repo.URL))
+		line("  chown -R %s %s", owner, dst)
+		line("fi")
+	}
+
+	if len(spec.Datasets) > 0 {
+		line("echo '== datasets'")
+	}
+	for _, dataset := range spec.Datasets {
+		link := quote(path.Join(root, dataset.Link))
+		line("if [ -e %s ] && [ ! -L %s ]; then echo %s >&2; exit 1; fi", link, link, quote(dataset.Link+"가 이미 링크가 아닌 파일로 있습니다"))
+		parent(&b, path.Dir(path.Join(root, dataset.Link)), owner, "")
+		line("ln -sfn %s %s", quote(dataset.Target), link)
+		line("chown -h %s %s", owner, link)
+	}
+	return b.String()
+}
+
+// parent 저장소/링크의 상위 디렉토리가 없으면 만들고 세션 사용자에게 넘김
+func parent(b *strings.Builder, dir, owner, indent string) {
+	fmt.Fprintf(b, "%sif [ ! -d %s ]; then mkdir -p %s; chown %s %s; fi\n", indent, quote(dir), quote(dir), owner, quote(dir))
+}
+
+// quote 셸 작은따옴표 인용
+func quote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
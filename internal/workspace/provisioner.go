@@ -0,0 +1,155 @@
+// Package workspace 세션 컨테이너가 워크스페이스를 마운트하기 전에 실행하는 준비 단계
+//
+// 디렉토리 생성, 기본 파일/템플릿 시드, git clone, 데이터셋 링크, 소유권 설정을 Provisioner가
+// 한 번의 짧은 작업으로 실행한다. 오케스트레이터 호스트에서 직접 실행하거나(host),
+// 워크스페이스를 마운트한 일회용 컨테이너에서 실행할 수 있어(init-container) 호스트에서
+// 읽을 수 없는 볼륨이나 다른 컨테이너 백엔드의 init 단계에서도 같은 내용을 쓸 수 있다.
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/logging"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+)
+
+const (
+	// ModeHost 오케스트레이터 프로세스가 호스트 경로에 직접 준비 (bind, 호스트에서 읽을 수 있는 볼륨)
+	ModeHost = "host"
+	// ModeInitContainer 워크스페이스를 마운트한 일회용 컨테이너에서 준비 스크립트 실행
+	ModeInitContainer = "init-container"
+
+	// outputLimit 단계 결과에 남길 명령 출력 길이 (실패 원인은 보통 끝에 있음)
+	outputLimit = 4096
+)
+
+var (
+	provisionRuns    = metrics.NewCounter("sandman_workspace_provision_total", "워크스페이스 준비 결과 (result: ok, retried, failed)", "provisioner", "result")
+	provisionSeconds = metrics.NewHistogram("sandman_workspace_provision_seconds", "워크스페이스 준비에 걸린 시간 (재시도 포함)",
+		[]float64{0.01, 0.1, 0.5, 1, 5, 15, 30, 60, 120, 300}, "provisioner")
+)
+
+// DefaultBashrc 새 워크스페이스에 넣는 .bashrc (이미 있으면 건드리지 않음)
+const DefaultBashrc = `# GPU SSH Gateway 워크스페이스
+export PS1='\[\033[01;32m\]\u@\h\[\033[00m\]:\[\033[01;34m\]\w\[\033[00m\]\$ '
+alias ll='ls -alF'
+alias la='ls -A'
+alias l='ls -CF'
+# 로그인 안내는 배포의 환영 메시지 템플릿으로 렌더링됨 (/workspace/.sandman/motd)
+`
+
+// GitRepo 워크스페이스에 미리 받아 둘 저장소 (이미 있으면 다시 받지 않음)
+type GitRepo struct {
+	URL  string `json:"url"`
+	Path string `json:"path"`          // 워크스페이스 기준 상대 경로
+	Ref  string `json:"ref,omitempty"` // 브랜치나 태그 (비우면 기본 브랜치)
+}
+
+// Dataset 워크스페이스 안에 만드는 데이터셋 심볼릭 링크 (대상은 세션 컨테이너 안의 경로)
+type Dataset struct {
+	Link   string `json:"link"`   // 워크스페이스 기준 상대 경로 (예: data/imagenet)
+	Target string `json:"target"` // 절대 경로 (예: /datasets/imagenet)
+}
+
+// Spec 사용자 워크스페이스 하나의 준비 내용
+type Spec struct {
+	UserID      string
+	HostDir     string // 호스트에서 접근할 수 있는 워크스페이스 경로 (없으면 "")
+	Volume      string // 볼륨 백엔드의 사용자 볼륨 이름 (bind 백엔드면 "")
+	UID, GID    int    // 세션 컨테이너 사용자
+	TemplateDir string // 없는 파일만 복사할 템플릿 디렉토리 (호스트 경로)
+	Repos       []GitRepo
+	Datasets    []Dataset
+}
+
+// StepResult 준비 단계 하나의 결과
+type StepResult struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration"`
+	Output   string        `json:"output,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Result 재시도를 포함한 준비 전체 결과
+type Result struct {
+	Provisioner string        `json:"provisioner"`
+	Attempts    int           `json:"attempts"`
+	Steps       []StepResult  `json:"steps"` // 마지막 시도의 단계
+	Duration    time.Duration `json:"duration"`
+}
+
+// Provisioner 워크스페이스 준비 실행 방식 (같은 Spec을 여러 번 실행해도 결과가 같아야 함)
+type Provisioner interface {
+	Name() string
+	Provision(ctx context.Context, spec Spec) ([]StepResult, error)
+}
+
+// Policy 한 번의 시도 제한 시간과 재시도 정책
+type Policy struct {
+	Timeout time.Duration
+	Retries int
+	Backoff time.Duration // 재시도마다 두 배로 늘림
+}
+
+// Run 정책에 따라 Provisioner를 실행 (단계는 모두 멱등이므로 실패하면 처음부터 다시 실행)
+func Run(p Provisioner, spec Spec, policy Policy) (*Result, error) {
+	start := time.Now()
+	result := &Result{Provisioner: p.Name()}
+	backoff := policy.Backoff
+
+	var err error
+	for attempt := 1; attempt <= policy.Retries+1; attempt++ {
+		result.Attempts = attempt
+		ctx, cancel := context.WithTimeout(context.Background(), policy.Timeout)
+		result.Steps, err = p.Provision(ctx, spec)
+		if ctx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("제한 시간 %v 초과", policy.Timeout)
+		}
+		cancel()
+		for _, step := range result.Steps {
+			logging.Debugf("워크스페이스 준비 단계: %s %s (%v) %s", spec.UserID, step.Name, step.Duration.Round(time.Millisecond), step.Error)
+		}
+		if err == nil {
+			break
+		}
+		if attempt <= policy.Retries {
+			log.Printf("⚠️ 워크스페이스 준비 실패 (%s, %d/%d회, %v 후 재시도): %v", spec.UserID, attempt, policy.Retries+1, backoff, err)
+			provisionRuns.Inc(p.Name(), "retried")
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+	}
+	result.Duration = time.Since(start)
+	provisionSeconds.Observe(result.Duration.Seconds(), p.Name())
+
+	if err != nil {
+		provisionRuns.Inc(p.Name(), "failed")
+		return result, fmt.Errorf("워크스페이스 준비 실패 (%s, %d회 시도): %w", p.Name(), result.Attempts, err)
+	}
+	provisionRuns.Inc(p.Name(), "ok")
+	log.Printf("📁 워크스페이스 준비 완료: %s (%s, 단계 %d개, %v)", spec.UserID, p.Name(), len(result.Steps), result.Duration.Round(time.Millisecond))
+	return result, nil
+}
+
+// ValidRelativePath 워크스페이스 안쪽을 가리키는 상대 경로인지 (저장소/링크 위치 검증)
+func ValidRelativePath(p string) bool {
+	if p == "" || strings.HasPrefix(p, "/") {
+		return false
+	}
+	clean := path.Clean(p)
+	return clean != "." && clean != ".." && !strings.HasPrefix(clean, "../")
+}
+
+// tail 출력의 마지막 부분
+func tail(output string) string {
+	output = strings.TrimSpace(output)
+	if len(output) > outputLimit {
+		output = output[len(output)-outputLimit:]
+	}
+	return output
+}
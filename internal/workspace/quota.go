@@ -0,0 +1,295 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/sandman/gpu-ssh-gateway/internal/command"
+	"github.com/sandman/gpu-ssh-gateway/internal/errs"
+	"github.com/sandman/gpu-ssh-gateway/internal/metrics"
+)
+
+const (
+	// QuotaXFS workspace_root가 prjquota로 마운트된 XFS일 때 사용자 디렉토리마다 프로젝트 쿼터
+	QuotaXFS = "xfs"
+	// QuotaLoopback 사용자마다 크기가 정해진 ext4 이미지 파일을 만들어 워크스페이스 경로에 loop 마운트
+	QuotaLoopback = "loopback"
+
+	// quotaCommandTimeout mkfs, e2fsck, resize2fs는 큰 이미지에서 기본 제한 시간보다 오래 걸릴 수 있음
+	quotaCommandTimeout = 10 * time.Minute
+	// migrateTimeout 기존 워크스페이스를 이미지로 복사하는 데 허용하는 시간
+	migrateTimeout = time.Hour
+
+	gb = int64(1) << 30
+)
+
+var quotaApplies = metrics.NewCounter("sandman_workspace_quota_apply_total", "워크스페이스 쿼터 적용 결과 (result: ok, failed)", "backend", "result")
+
+// QuotaTarget 쿼터를 걸 워크스페이스
+type QuotaTarget struct {
+	UserID     string
+	Dir        string // 호스트의 워크스페이스 경로 (없으면 만듦)
+	LimitBytes int64
+	UID        int // 새로 만든 디렉토리/파일시스템 루트의 소유자
+	GID        int
+}
+
+// Usage 워크스페이스 디스크 사용량 (쿼터가 걸린 파일시스템/프로젝트 기준)
+type Usage struct {
+	UsedBytes  int64 `json:"used_bytes"`
+	LimitBytes int64 `json:"limit_bytes"`
+}
+
+// Quota 워크스페이스 디스크 쿼터 백엔드
+type Quota interface {
+	Name() string
+	// Apply 워크스페이스에 한도를 걸거나 바꿈 (이미 같은 한도면 아무것도 하지 않음)
+	Apply(ctx context.Context, target QuotaTarget) error
+	// Release 워크스페이스를 지우기 전에 쿼터를 거둠 (loopback은 언마운트하고 이미지 삭제)
+	Release(ctx context.Context, userID, dir string) error
+	Usage(dir string) (*Usage, error)
+}
+
+// ApplyQuota 쿼터를 걸고 결과를 메트릭과 로그로 남김
+func ApplyQuota(q Quota, target QuotaTarget) error {
+	err := q.Apply(context.Background(), target)
+	if err != nil {
+		quotaApplies.Inc(q.Name(), "failed")
+		return fmt.Errorf("워크스페이스 쿼터 적용 실패 (%s): %w", q.Name(), err)
+	}
+	quotaApplies.Inc(q.Name(), "ok")
+	log.Printf("💽 워크스페이스 쿼터 적용: %s (%s, %s)", target.UserID, q.Name(), formatBytes(target.LimitBytes))
+	return nil
+}
+
+// formatBytes 로그와 오류 메시지용 크기 표시
+func formatBytes(n int64) string {
+	if n%gb == 0 {
+		return fmt.Sprintf("%dGB", n/gb)
+	}
+	return fmt.Sprintf("%.1fGB", float64(n)/float64(gb))
+}
+
+// ensureDir 워크스페이스 디렉토리가 없으면 만들고 소유자 지정 (root가 아니면 소유권은 건너뜀)
+func ensureDir(target QuotaTarget) error {
+	if _, err := os.Stat(target.Dir); !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(target.Dir, 0755); err != nil {
+		return err
+	}
+	return chownRoot(target)
+}
+
+func chownRoot(target QuotaTarget) error {
+	if os.Geteuid() != 0 {
+		return nil
+	}
+	return os.Lchown(target.Dir, target.UID, target.GID)
+}
+
+// ProjectIDs 사용자별 XFS 프로젝트 ID 기록 (두 사용자가 같은 ID로 한도를 나눠 쓰지 않도록 저장소에서 배정)
+type ProjectIDs interface {
+	// AssignProjectID 배정된 ID를 돌려주고, 없으면 base 이상에서 아무도 쓰지 않은 ID를 새로 배정
+	AssignProjectID(userID string, base uint32) (uint32, error)
+	// GetProjectID 배정된 ID (없으면 ErrNotFound)
+	GetProjectID(userID string) (uint32, error)
+}
+
+// XFSQuota XFS 프로젝트 쿼터 (workspace_root가 있는 파일시스템을 prjquota 옵션으로 마운트해야 함)
+// 프로젝트 ID는 처음 적용할 때 project_id_base 위에서 사용자마다 하나씩 배정해 저장하므로 재시작해도 같은 사용자는 같은 ID
+type XFSQuota struct {
+	MountPoint    string // xfs_quota에 넘길 파일시스템 마운트 지점
+	ProjectIDBase uint32
+	Projects      ProjectIDs
+}
+
+func (q *XFSQuota) Name() string { return QuotaXFS }
+
+func (q *XFSQuota) Apply(ctx context.Context, target QuotaTarget) error {
+	if err := ensureDir(target); err != nil {
+		return err
+	}
+	projectID, err := q.Projects.AssignProjectID(target.UserID, q.ProjectIDBase)
+	if err != nil {
+		return fmt.Errorf("XFS 프로젝트 ID 배정 실패: %w", err)
+	}
+	id := strconv.FormatUint(uint64(projectID), 10)
+	// project -s는 디렉토리 트리에 프로젝트 ID와 상속 플래그를 설정 (이후 만든 파일도 같은 프로젝트)
+	if err := q.run(ctx, fmt.Sprintf("project -s -p %s %s", target.Dir, id)); err != nil {
+		return err
+	}
+	return q.run(ctx, fmt.Sprintf("limit -p bhard=%dk %s", target.LimitBytes/1024, id))
+}
+
+// Release 배정된 ID가 없으면 건 한도도 없으므로 아무것도 하지 않음 (다른 사용자의 한도를 풀지 않도록 ID를 추측하지 않음)
+func (q *XFSQuota) Release(ctx context.Context, userID, dir string) error {
+	projectID, err := q.Projects.GetProjectID(userID)
+	if errors.Is(err, errs.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("XFS 프로젝트 ID 조회 실패: %w", err)
+	}
+	return q.run(ctx, fmt.Sprintf("limit -p bhard=0 %d", projectID))
+}
+
+// Usage 프로젝트 루트에 statfs하면 XFS가 프로젝트 한도와 사용량을 파일시스템 크기처럼 보고함
+func (q *XFSQuota) Usage(dir string) (*Usage, error) {
+	return statUsage(dir)
+}
+
+func (q *XFSQuota) run(ctx context.Context, cmd string) error {
+	_, err := command.Run(ctx, command.Options{Combined: true}, "xfs_quota", "-x", "-c", cmd, q.MountPoint)
+	return err
+}
+
+// LoopbackQuota 사용자마다 image_dir/<user>.img 희소 파일을 ext4로 포맷해 워크스페이스 경로에 loop 마운트
+// 이미 있는 워크스페이스는 처음 적용할 때 이미지로 옮기고, 한도를 늘리면 이미지를 키움 (줄이지는 않음)
+// 재부팅 등으로 마운트가 풀려 있으면 다음 적용 때 다시 마운트
+type LoopbackQuota struct {
+	ImageDir string
+}
+
+func (q *LoopbackQuota) Name() string { return QuotaLoopback }
+
+func (q *LoopbackQuota) image(userID string) string {
+	return filepath.Join(q.ImageDir, userID+".img")
+}
+
+func (q *LoopbackQuota) Apply(ctx context.Context, target QuotaTarget) error {
+	if err := ensureDir(target); err != nil {
+		return err
+	}
+	img := q.image(target.UserID)
+	info, err := os.Stat(img)
+	if os.IsNotExist(err) {
+		return q.create(ctx, img, target)
+	}
+	if err != nil {
+		return err
+	}
+
+	mounted := q.mounted(ctx, target.Dir)
+	if info.Size() >= target.LimitBytes {
+		if !mounted {
+			return q.mount(ctx, img, target.Dir)
+		}
+		return nil
+	}
+
+	// 키우기: 세션이 없을 때만 호출되므로 언마운트하고 파일시스템을 검사한 뒤 늘림
+	if mounted {
+		if err := q.run(ctx, "umount", target.Dir); err != nil {
+			return err
+		}
+	}
+	if err := os.Truncate(img, target.LimitBytes); err != nil {
+		return err
+	}
+	if err := q.run(ctx, "e2fsck", "-f", "-p", img); err != nil {
+		return err
+	}
+	if err := q.run(ctx, "resize2fs", img); err != nil {
+		return err
+	}
+	return q.mount(ctx, img, target.Dir)
+}
+
+// create 새 이미지를 만들고, 워크스페이스에 이미 있던 내용을 옮긴 뒤 그 자리에 마운트
+func (q *LoopbackQuota) create(ctx context.Context, img string, target QuotaTarget) error {
+	if err := os.MkdirAll(q.ImageDir, 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(img, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	f.Close()
+	fail := func(err error) error {
+		os.Remove(img)
+		return err
+	}
+	if err := os.Truncate(img, target.LimitBytes); err != nil {
+		return fail(err)
+	}
+	// 루트 예약 블록은 두지 않음 (사용자가 한도를 그대로 쓸 수 있게)
+	if err := q.run(ctx, "mkfs.ext4", "-q", "-F", "-m", "0", img); err != nil {
+		return fail(err)
+	}
+
+	entries, err := os.ReadDir(target.Dir)
+	if err != nil {
+		return fail(err)
+	}
+	if len(entries) > 0 {
+		if err := q.migrate(ctx, img, target.Dir, entries); err != nil {
+			return fail(err)
+		}
+	}
+	if err := q.mount(ctx, img, target.Dir); err != nil {
+		return fail(err)
+	}
+	return chownRoot(target)
+}
+
+// migrate 기존 워크스페이스 내용을 임시 마운트한 이미지로 복사하고 원본을 지움 (마운트에 가려 디스크만 차지하므로)
+func (q *LoopbackQuota) migrate(ctx context.Context, img, dir string, entries []os.DirEntry) error {
+	staging, err := os.MkdirTemp(q.ImageDir, ".migrate-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(staging)
+	if err := q.mount(ctx, img, staging); err != nil {
+		return err
+	}
+	_, err = command.Run(ctx, command.Options{Combined: true, Timeout: migrateTimeout}, "cp", "-a", dir+"/.", staging)
+	if umountErr := q.run(ctx, "umount", staging); err == nil {
+		err = umountErr
+	}
+	if err != nil {
+		return fmt.Errorf("기존 워크스페이스 복사 실패: %w", err)
+	}
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (q *LoopbackQuota) Release(ctx context.Context, userID, dir string) error {
+	if q.mounted(ctx, dir) {
+		if err := q.run(ctx, "umount", dir); err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(q.image(userID)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (q *LoopbackQuota) Usage(dir string) (*Usage, error) {
+	return statUsage(dir)
+}
+
+func (q *LoopbackQuota) mount(ctx context.Context, img, dir string) error {
+	return q.run(ctx, "mount", "-o", "loop,nodev,nosuid", img, dir)
+}
+
+func (q *LoopbackQuota) mounted(ctx context.Context, dir string) bool {
+	_, err := command.Run(ctx, command.Options{}, "mountpoint", "-q", dir)
+	return err == nil
+}
+
+func (q *LoopbackQuota) run(ctx context.Context, name string, args ...string) error {
+	_, err := command.Run(ctx, command.Options{Combined: true, Timeout: quotaCommandTimeout}, name, args...)
+	return err
+}
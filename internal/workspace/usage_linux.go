@@ -0,0 +1,18 @@
+//go:build linux
+
+package workspace
+
+import "syscall"
+
+// statUsage 워크스페이스가 있는 파일시스템(XFS 프로젝트 루트면 프로젝트 쿼터)의 사용량과 크기
+func statUsage(dir string) (*Usage, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return nil, err
+	}
+	bsize := int64(st.Bsize)
+	return &Usage{
+		UsedBytes:  int64(st.Blocks-st.Bfree) * bsize,
+		LimitBytes: int64(st.Blocks) * bsize,
+	}, nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package workspace
+
+import "fmt"
+
+// statUsage 워크스페이스 쿼터는 리눅스에서만 지원
+func statUsage(dir string) (*Usage, error) {
+	return nil, fmt.Errorf("워크스페이스 사용량은 리눅스에서만 조회할 수 있습니다")
+}
@@ -19,6 +19,79 @@ USER_HOME="/home/$USER_ID"
 # 사용자 디렉토리 소유권 설정
 chown -R $USER_ID:$USER_ID $USER_HOME
 
+# 시간대/로케일 적용 (SSH 로그인 세션은 컨테이너 환경 변수를 물려받지 않으므로 /etc/environment에 기록)
+sed -i '/^\(TZ\|LANG\|LC_ALL\)=/d' /etc/environment 2>/dev/null
+if [ -n "$TZ" ]; then
+    echo "🕒 시간대: $TZ"
+    echo "$TZ" > /etc/timezone
+    echo "TZ=$TZ" >> /etc/environment
+fi
+if [ -n "$LANG" ]; then
+    echo "🌐 로케일: $LANG"
+    case "$LANG" in
+        C|C.*|POSIX) ;;
+        *) locale-gen "$LANG" >/dev/null 2>&1 || echo "⚠️ 로케일 생성 실패: $LANG" ;;
+    esac
+    echo "LANG=$LANG" >> /etc/environment
+    echo "LC_ALL=$LANG" >> /etc/environment
+fi
+
+# 환영 메시지 (오케스트레이터가 세션 생성/연장 시 /workspace/.sandman/motd를 렌더링)
+cat > /etc/profile.d/sandman-welcome.sh <<'EOF'
+case $- in
+    *i*) [ -r /workspace/.sandman/motd ] && cat /workspace/.sandman/motd ;;
+esac
+EOF
+
+# 세션 상태 확인 도구 (오케스트레이터가 1분마다 /workspace/.sandman/status.json과 warnings를 갱신)
+cat > /usr/local/bin/sandman-status <<'EOF'
+#!/bin/sh
+# 사용법: sandman-status [--json]
+STATUS=/workspace/.sandman/status.json
+WARNINGS=/workspace/.sandman/warnings
+if [ ! -r "$STATUS" ]; then
+    echo "세션 상태 정보가 아직 없습니다." >&2
+    exit 1
+fi
+if [ "$1" = "--json" ]; then
+    cat "$STATUS"
+    exit 0
+fi
+field() { sed -n "s/^  \"$1\": \"\{0,1\}\([^\",]*\).*/\1/p" "$STATUS"; }
+echo "세션:     $(field session_id)"
+echo "프로파일: $(field profile)"
+echo "만료:     $(field expires_at_local) (남은 시간 $(field remaining_minutes)분)"
+echo "최대 TTL: $(field max_ttl_minutes)분"
+[ -s "$WARNINGS" ] && cat "$WARNINGS"
+exit 0
+EOF
+chmod 755 /usr/local/bin/sandman-status
+
+# 로그인 시 경고 출력
+cat > /etc/profile.d/sandman-warnings.sh <<'EOF'
+case $- in
+    *i*) [ -s /workspace/.sandman/warnings ] && cat /workspace/.sandman/warnings ;;
+esac
+EOF
+
+# 경고가 바뀌면 로그인한 사용자 터미널에 알림 (cron 없이 백그라운드 루프로)
+(
+    last=""
+    while true; do
+        sleep 60
+        [ -s /workspace/.sandman/warnings ] || { last=""; continue; }
+        current=$(cat /workspace/.sandman/warnings)
+        if [ "$current" != "$last" ]; then
+            echo "$current" | wall 2>/dev/null
+            last="$current"
+        fi
+    done
+) &
+
+# 워크스페이스 SSH 설정 (오케스트레이터가 세션 생성 시 /workspace/.ssh/config와 키를 만듦, 없으면 무시됨)
+mkdir -p /etc/ssh/ssh_config.d
+echo "Include /workspace/.ssh/config" > /etc/ssh/ssh_config.d/sandman-workspace.conf
+
 # sudo 권한 부여
 echo "$USER_ID ALL=(ALL) NOPASSWD:ALL" > "/etc/sudoers.d/$USER_ID"
 